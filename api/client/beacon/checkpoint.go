@@ -66,13 +66,91 @@ func fname(prefix string, vu *detect.VersionedUnmarshaler, slot primitives.Slot,
 // DownloadFinalizedData downloads the most recently finalized state, and the block most recently applied to that state.
 // This pair can be used to initialize a new beacon node via checkpoint sync.
 func DownloadFinalizedData(ctx context.Context, client *Client) (*OriginData, error) {
-	sb, err := client.GetState(ctx, IdFinalized)
+	od, err := downloadServedCheckpoint(ctx, client)
+	if err == nil {
+		return od, nil
+	}
+	logServedCheckpointError(client, err)
+	sb, vu, s, err := downloadState(ctx, client, IdFinalized)
+	if err != nil {
+		return nil, err
+	}
+	slot := s.LatestBlockHeader().Slot
+	bb, err := client.GetBlock(ctx, IdFromSlot(slot))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error requesting block by slot = %d", slot)
+	}
+	return newOriginData(ctx, vu, sb, s, bb)
+}
+
+// downloadServedCheckpoint downloads the finalized state and block cached by a Prysm beacon node running in
+// checkpoint serving mode. Unlike the debug state API, interrupted downloads of the cached state are resumed.
+// The block is requested by the root derived from the downloaded state, so that a checkpoint finalized between
+// the two downloads can not pair the state with the block of another checkpoint.
+func downloadServedCheckpoint(ctx context.Context, client *Client) (*OriginData, error) {
+	sb, err := client.GetCheckpointState(ctx)
 	if err != nil {
 		return nil, err
 	}
+	vu, s, err := unmarshalState(sb)
+	if err != nil {
+		return nil, err
+	}
+	br, err := latestBlockRoot(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	bb, err := client.GetCheckpointBlock(ctx, br)
+	if err != nil {
+		return nil, err
+	}
+	return newOriginData(ctx, vu, sb, s, bb)
+}
+
+// latestBlockRoot computes the root of the latest block applied to the state. The state root of the latest block
+// header is only filled in by the next slot transition, so it is set to the root of the state itself if missing.
+func latestBlockRoot(ctx context.Context, s state.BeaconState) ([32]byte, error) {
+	h := s.LatestBlockHeader()
+	if bytesutil.ToBytes32(h.StateRoot) == [32]byte{} {
+		sr, err := s.HashTreeRoot(ctx)
+		if err != nil {
+			return [32]byte{}, errors.Wrap(err, "error computing hash_tree_root of state")
+		}
+		h.StateRoot = sr[:]
+	}
+	br, err := h.HashTreeRoot()
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "error while computing block root using state data")
+	}
+	return br, nil
+}
+
+func logServedCheckpointError(client *Client, err error) {
+	l := log.WithError(err).WithField("url", client.NodeURL())
+	if errors.Is(err, base.ErrNotFound) {
+		l.Debug("Checkpoint serving is not available, falling back to the beacon API")
+		return
+	}
+	l.Warn("Could not download served checkpoint, falling back to the beacon API")
+}
+
+// downloadState downloads the state identified by stateId and unmarshals it using the config detected from its bytes.
+func downloadState(ctx context.Context, client *Client, stateId StateOrBlockId) ([]byte, *detect.VersionedUnmarshaler, state.BeaconState, error) {
+	sb, err := client.GetState(ctx, stateId)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	vu, s, err := unmarshalState(sb)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return sb, vu, s, nil
+}
+
+func unmarshalState(sb []byte) (*detect.VersionedUnmarshaler, state.BeaconState, error) {
 	vu, err := detect.FromState(sb)
 	if err != nil {
-		return nil, errors.Wrap(err, "error detecting chain config for finalized state")
+		return nil, nil, errors.Wrap(err, "error detecting chain config for finalized state")
 	}
 
 	log.WithFields(logrus.Fields{
@@ -82,14 +160,13 @@ func DownloadFinalizedData(ctx context.Context, client *Client) (*OriginData, er
 
 	s, err := vu.UnmarshalBeaconState(sb)
 	if err != nil {
-		return nil, errors.Wrap(err, "error unmarshaling finalized state to correct version")
+		return nil, nil, errors.Wrap(err, "error unmarshaling finalized state to correct version")
 	}
+	return vu, s, nil
+}
 
-	slot := s.LatestBlockHeader().Slot
-	bb, err := client.GetBlock(ctx, IdFromSlot(slot))
-	if err != nil {
-		return nil, errors.Wrapf(err, "error requesting block by slot = %d", slot)
-	}
+// newOriginData unmarshals the block and checks that it is the block most recently applied to the state.
+func newOriginData(ctx context.Context, vu *detect.VersionedUnmarshaler, sb []byte, s state.BeaconState, bb []byte) (*OriginData, error) {
 	b, err := vu.UnmarshalBeaconBlock(bb)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to unmarshal block to a supported type using the detected fork schedule")
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"path"
@@ -12,6 +13,7 @@ import (
 	"sort"
 	"strconv"
 	"text/template"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/pkg/errors"
@@ -26,15 +28,33 @@ import (
 )
 
 const (
-	getSignedBlockPath       = "/eth/v2/beacon/blocks"
-	getBlockRootPath         = "/eth/v1/beacon/blocks/{{.Id}}/root"
-	getForkForStatePath      = "/eth/v1/beacon/states/{{.Id}}/fork"
-	getWeakSubjectivityPath  = "/prysm/v1/beacon/weak_subjectivity"
-	getForkSchedulePath      = "/eth/v1/config/fork_schedule"
-	getConfigSpecPath        = "/eth/v1/config/spec"
-	getStatePath             = "/eth/v2/debug/beacon/states"
-	getNodeVersionPath       = "/eth/v1/node/version"
-	changeBLStoExecutionPath = "/eth/v1/beacon/pool/bls_to_execution_changes"
+	getSignedBlockPath         = "/eth/v2/beacon/blocks"
+	getBlockRootPath           = "/eth/v1/beacon/blocks/{{.Id}}/root"
+	getBlobSidecarsPath        = "/eth/v1/beacon/blob_sidecars/{{.Id}}"
+	getStateRootPath           = "/eth/v1/beacon/states/{{.Id}}/root"
+	getForkForStatePath        = "/eth/v1/beacon/states/{{.Id}}/fork"
+	getFinalityCheckpointsPath = "/eth/v1/beacon/states/{{.Id}}/finality_checkpoints"
+	getWeakSubjectivityPath    = "/prysm/v1/beacon/weak_subjectivity"
+	getCheckpointStatePath     = "/prysm/v1/beacon/checkpoint/state"
+	getCheckpointBlockPath     = "/prysm/v1/beacon/checkpoint/block"
+	getForkSchedulePath        = "/eth/v1/config/fork_schedule"
+	getConfigSpecPath          = "/eth/v1/config/spec"
+	getStatePath               = "/eth/v2/debug/beacon/states"
+	getNodeVersionPath         = "/eth/v1/node/version"
+	changeBLStoExecutionPath   = "/eth/v1/beacon/pool/bls_to_execution_changes"
+	getOptimisticReportPath    = "/prysm/v1/debug/optimistic"
+	revalidateOptimisticPath   = "/prysm/v1/debug/optimistic/revalidate"
+	getGenesisPath             = "/eth/v1/beacon/genesis"
+	getValidatorsPath          = "/eth/v1/beacon/states/{{.Id}}/validators"
+	getAttesterDutiesPath      = "/eth/v1/validator/duties/attester/%d"
+	getProposerDutiesPath      = "/eth/v1/validator/duties/proposer/%d"
+	getSyncCommitteeDutiesPath = "/eth/v1/validator/duties/sync/%d"
+	getAttestationRewardsPath  = "/eth/v1/beacon/rewards/attestations/%d"
+	getProposerLookaheadPath   = "/prysm/v1/validators/proposer_lookahead"
+	peerBansPath               = "/prysm/v1/node/peers/bans"
+	connectPeerPath            = "/prysm/v1/node/peers/connect"
+	disconnectPeerPath         = "/prysm/v1/node/peers/%s/disconnect"
+	peerScorePath              = "/prysm/v1/node/peers/%s/score"
 )
 
 // StateOrBlockId represents the block_id / state_id parameters that several of the Eth Beacon API methods accept.
@@ -136,6 +156,60 @@ func (c *Client) GetBlockRoot(ctx context.Context, blockId StateOrBlockId) ([32]
 	return bytesutil.ToBytes32(rs), nil
 }
 
+var getStateRootTpl = idTemplate(getStateRootPath)
+
+// GetStateRoot retrieves the hash_tree_root of the BeaconState for the given state id.
+// State identifier can be one of: "head" (canonical head in node's view), "genesis", "finalized",
+// <slot>, <hex encoded stateRoot with 0x prefix>. Variables of type StateOrBlockId are exported by this package
+// for the named identifiers.
+func (c *Client) GetStateRoot(ctx context.Context, stateId StateOrBlockId) ([32]byte, error) {
+	rootPath := getStateRootTpl(stateId)
+	b, err := c.Get(ctx, rootPath)
+	if err != nil {
+		return [32]byte{}, errors.Wrapf(err, "error requesting state root by id = %s", stateId)
+	}
+	jsonr := &struct{ Data struct{ Root string } }{}
+	err = json.Unmarshal(b, jsonr)
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "error decoding json data from get state root response")
+	}
+	rs, err := hexutil.Decode(jsonr.Data.Root)
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, fmt.Sprintf("error decoding hex-encoded value %s", jsonr.Data.Root))
+	}
+	return bytesutil.ToBytes32(rs), nil
+}
+
+var getBlobSidecarsTpl = idTemplate(getBlobSidecarsPath)
+
+// GetBlobSidecars retrieves the BlobSidecars of the block identified by blockId. When indices are given, only the
+// BlobSidecars with those indices are requested.
+// Block identifier can be one of: "head" (canonical head in node's view), "genesis", "finalized",
+// <slot>, <hex encoded blockRoot with 0x prefix>. Variables of type StateOrBlockId are exported by this package
+// for the named identifiers.
+func (c *Client) GetBlobSidecars(ctx context.Context, blockId StateOrBlockId, indices []uint64) ([]*ethpb.BlobSidecar, error) {
+	q := url.Values{}
+	for _, i := range indices {
+		q.Add("indices", strconv.FormatUint(i, 10))
+	}
+	body, err := c.Get(ctx, getBlobSidecarsTpl(blockId), client.WithQuery(q))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error requesting blob sidecars by block id = %s", blockId)
+	}
+	sr := &structs.SidecarsResponse{}
+	if err := json.Unmarshal(body, sr); err != nil {
+		return nil, errors.Wrap(err, "error decoding json response in GetBlobSidecars")
+	}
+	sidecars := make([]*ethpb.BlobSidecar, len(sr.Data))
+	for i := range sr.Data {
+		sidecars[i], err = sr.Data[i].ToConsensus()
+		if err != nil {
+			return nil, errors.Wrapf(err, "error converting blob sidecar %d of block id = %s", i, blockId)
+		}
+	}
+	return sidecars, nil
+}
+
 var getForkTpl = idTemplate(getForkForStatePath)
 
 // GetFork queries the Beacon Node API for the Fork from the state identified by stateId.
@@ -157,6 +231,27 @@ func (c *Client) GetFork(ctx context.Context, stateId StateOrBlockId) (*ethpb.Fo
 	return fr.ToConsensus()
 }
 
+var getFinalityCheckpointsTpl = idTemplate(getFinalityCheckpointsPath)
+
+// GetFinalizedCheckpoint queries the Beacon Node API for the finalized Checkpoint of the state identified by stateId.
+// State identifier can be one of: "head" (canonical head in node's view), "genesis", "finalized",
+// <slot>, <hex encoded stateRoot with 0x prefix>. Variables of type StateOrBlockId are exported by this package
+// for the named identifiers.
+func (c *Client) GetFinalizedCheckpoint(ctx context.Context, stateId StateOrBlockId) (*ethpb.Checkpoint, error) {
+	body, err := c.Get(ctx, getFinalityCheckpointsTpl(stateId))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error requesting finality checkpoints by state id = %s", stateId)
+	}
+	fc := &structs.GetFinalityCheckpointsResponse{}
+	if err := json.Unmarshal(body, fc); err != nil {
+		return nil, errors.Wrap(err, "error decoding json response in GetFinalizedCheckpoint")
+	}
+	if fc.Data == nil || fc.Data.Finalized == nil {
+		return nil, errors.New("finalized checkpoint missing from finality checkpoints response")
+	}
+	return fc.Data.Finalized.ToConsensus()
+}
+
 // GetForkSchedule retrieve all forks, past present and future, of which this node is aware.
 func (c *Client) GetForkSchedule(ctx context.Context) (forks.OrderedSchedule, error) {
 	body, err := c.Get(ctx, getForkSchedulePath)
@@ -246,6 +341,28 @@ func (c *Client) GetState(ctx context.Context, stateId StateOrBlockId) ([]byte,
 	return b, nil
 }
 
+// GetCheckpointState retrieves the ssz-encoded finalized state cached for checkpoint sync by a Prysm beacon node
+// running in checkpoint serving mode. Interrupted downloads are resumed.
+func (c *Client) GetCheckpointState(ctx context.Context) ([]byte, error) {
+	b, err := c.GetResumable(ctx, getCheckpointStatePath, client.WithSSZEncoding())
+	if err != nil {
+		return nil, errors.Wrap(err, "error requesting checkpoint state")
+	}
+	return b, nil
+}
+
+// GetCheckpointBlock retrieves the ssz-encoded block with the given root that a Prysm beacon node running in
+// checkpoint serving mode cached for checkpoint sync. The block root pins the block to a state downloaded before.
+// Interrupted downloads are resumed.
+func (c *Client) GetCheckpointBlock(ctx context.Context, blockRoot [32]byte) ([]byte, error) {
+	q := url.Values{"block_root": []string{hexutil.Encode(blockRoot[:])}}
+	b, err := c.GetResumable(ctx, getCheckpointBlockPath, client.WithSSZEncoding(), client.WithQuery(q))
+	if err != nil {
+		return nil, errors.Wrap(err, "error requesting checkpoint block")
+	}
+	return b, nil
+}
+
 // GetWeakSubjectivity calls a proposed API endpoint that is unique to prysm
 // This api method does the following:
 // - computes weak subjectivity epoch
@@ -334,6 +451,255 @@ func (c *Client) GetBLStoExecutionChanges(ctx context.Context) (*structs.BLSToEx
 	return poolResponse, nil
 }
 
+// GetOptimisticReport retrieves the optimistic sync report of a Prysm beacon node running with debug endpoints
+// enabled: the ranges of optimistic blocks in fork choice, the payloads found INVALID by the execution client and
+// the validator duties refused because the node was optimistic.
+func (c *Client) GetOptimisticReport(ctx context.Context) (*structs.GetOptimisticReportResponse, error) {
+	body, err := c.Get(ctx, getOptimisticReportPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "error requesting optimistic sync report")
+	}
+	report := &structs.GetOptimisticReportResponse{}
+	if err := json.Unmarshal(body, report); err != nil {
+		return nil, errors.Wrap(err, "failed to decode optimistic sync report")
+	}
+	return report, nil
+}
+
+// RevalidateOptimisticPayloads asks a Prysm beacon node running with debug endpoints enabled to re-submit the
+// execution payloads of its optimistic blocks in the [start, end] slot range to its execution client.
+func (c *Client) RevalidateOptimisticPayloads(ctx context.Context, start, end primitives.Slot) (*structs.RevalidateOptimisticPayloadsResponse, error) {
+	req := &structs.RevalidateOptimisticPayloadsRequest{
+		StartSlot: strconv.FormatUint(uint64(start), 10),
+		EndSlot:   strconv.FormatUint(uint64(end), 10),
+	}
+	res := &structs.RevalidateOptimisticPayloadsResponse{}
+	if err := c.postJSON(ctx, revalidateOptimisticPath, req, res); err != nil {
+		return nil, errors.Wrap(err, "error requesting payload revalidation")
+	}
+	return res, nil
+}
+
+// GetGenesis retrieves the genesis time, genesis validators root and genesis fork version of the network.
+func (c *Client) GetGenesis(ctx context.Context) (*structs.Genesis, error) {
+	body, err := c.Get(ctx, getGenesisPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "error requesting genesis")
+	}
+	res := &structs.GetGenesisResponse{}
+	if err := json.Unmarshal(body, res); err != nil {
+		return nil, errors.Wrap(err, "failed to decode genesis response")
+	}
+	if res.Data == nil {
+		return nil, errors.New("genesis response has no data")
+	}
+	return res.Data, nil
+}
+
+var getValidatorsTpl = idTemplate(getValidatorsPath)
+
+// GetValidators retrieves the validators of the given state with the given ids, which can be validator indices or
+// hex encoded public keys.
+func (c *Client) GetValidators(ctx context.Context, stateId StateOrBlockId, ids []string) ([]*structs.ValidatorContainer, error) {
+	res := &structs.GetValidatorsResponse{}
+	if err := c.postJSON(ctx, getValidatorsTpl(stateId), &structs.GetValidatorsRequest{Ids: ids}, res); err != nil {
+		return nil, errors.Wrapf(err, "error requesting validators of state id = %s", stateId)
+	}
+	return res.Data, nil
+}
+
+// GetAttesterDuties retrieves the attester duties of the given validators in the given epoch, which can be at most
+// the epoch after the current epoch.
+func (c *Client) GetAttesterDuties(ctx context.Context, epoch primitives.Epoch, indices []primitives.ValidatorIndex) (*structs.GetAttesterDutiesResponse, error) {
+	res := &structs.GetAttesterDutiesResponse{}
+	if err := c.postJSON(ctx, fmt.Sprintf(getAttesterDutiesPath, epoch), indicesToStrings(indices), res); err != nil {
+		return nil, errors.Wrapf(err, "error requesting attester duties of epoch %d", epoch)
+	}
+	return res, nil
+}
+
+// GetProposerDuties retrieves the block proposers of the given epoch, which can be at most the epoch after the
+// current epoch.
+func (c *Client) GetProposerDuties(ctx context.Context, epoch primitives.Epoch) (*structs.GetProposerDutiesResponse, error) {
+	body, err := c.Get(ctx, fmt.Sprintf(getProposerDutiesPath, epoch))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error requesting proposer duties of epoch %d", epoch)
+	}
+	res := &structs.GetProposerDutiesResponse{}
+	if err := json.Unmarshal(body, res); err != nil {
+		return nil, errors.Wrap(err, "failed to decode proposer duties")
+	}
+	return res, nil
+}
+
+// GetSyncCommitteeDuties retrieves the sync committee duties of the given validators in the sync committee period
+// of the given epoch, which can be at most the period after the current period.
+func (c *Client) GetSyncCommitteeDuties(ctx context.Context, epoch primitives.Epoch, indices []primitives.ValidatorIndex) (*structs.GetSyncCommitteeDutiesResponse, error) {
+	res := &structs.GetSyncCommitteeDutiesResponse{}
+	if err := c.postJSON(ctx, fmt.Sprintf(getSyncCommitteeDutiesPath, epoch), indicesToStrings(indices), res); err != nil {
+		return nil, errors.Wrapf(err, "error requesting sync committee duties of epoch %d", epoch)
+	}
+	return res, nil
+}
+
+// GetAttestationRewards retrieves the attestation rewards of the given validators in the given epoch, along with the
+// ideal rewards of a validator for each effective balance. The epoch must be at least two epochs before the current epoch.
+func (c *Client) GetAttestationRewards(ctx context.Context, epoch primitives.Epoch, indices []primitives.ValidatorIndex) (*structs.AttestationRewards, error) {
+	res := &structs.AttestationRewardsResponse{}
+	if err := c.postJSON(ctx, fmt.Sprintf(getAttestationRewardsPath, epoch), indicesToStrings(indices), res); err != nil {
+		return nil, errors.Wrapf(err, "error requesting attestation rewards of epoch %d", epoch)
+	}
+	return &res.Data, nil
+}
+
+// GetProposerLookahead retrieves the block proposers of the epochs in the [start, end] range from a Prysm beacon node.
+// The proposers of the epochs after the next epoch are speculative.
+func (c *Client) GetProposerLookahead(ctx context.Context, start, end primitives.Epoch) (*structs.GetProposerLookaheadResponse, error) {
+	q := url.Values{}
+	q.Set("start_epoch", strconv.FormatUint(uint64(start), 10))
+	q.Set("end_epoch", strconv.FormatUint(uint64(end), 10))
+	body, err := c.Get(ctx, getProposerLookaheadPath, client.WithQuery(q))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error requesting proposer lookahead of epochs %d to %d", start, end)
+	}
+	res := &structs.GetProposerLookaheadResponse{}
+	if err := json.Unmarshal(body, res); err != nil {
+		return nil, errors.Wrap(err, "failed to decode proposer lookahead")
+	}
+	return res, nil
+}
+
+func indicesToStrings(indices []primitives.ValidatorIndex) []string {
+	s := make([]string, len(indices))
+	for i, idx := range indices {
+		s[i] = strconv.FormatUint(uint64(idx), 10)
+	}
+	return s
+}
+
+// ListPeerBans retrieves the peer IDs and IP ranges banned by the operator of a Prysm beacon node.
+func (c *Client) ListPeerBans(ctx context.Context) ([]*structs.PeerBan, error) {
+	body, err := c.Get(ctx, peerBansPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "error requesting peer bans")
+	}
+	res := &structs.ListPeerBansResponse{}
+	if err := json.Unmarshal(body, res); err != nil {
+		return nil, errors.Wrap(err, "failed to decode peer bans")
+	}
+	return res.Data, nil
+}
+
+// BanPeer bans a peer ID, an IP address or an IP range in CIDR notation for the given duration, or permanently if
+// the duration is zero. The response lists the connected peers which were disconnected because of the ban.
+func (c *Client) BanPeer(ctx context.Context, target string, duration time.Duration, reason string) (*structs.BanPeerResponse, error) {
+	req := &structs.BanPeerRequest{
+		Target:   target,
+		Duration: strconv.FormatUint(uint64(duration/time.Second), 10),
+		Reason:   reason,
+	}
+	res := &structs.BanPeerResponse{}
+	if err := c.postJSON(ctx, peerBansPath, req, res); err != nil {
+		return nil, errors.Wrapf(err, "error banning %s", target)
+	}
+	return res, nil
+}
+
+// UnbanPeer lifts the ban of a peer ID, an IP address or an IP range.
+func (c *Client) UnbanPeer(ctx context.Context, target string) error {
+	p := path.Join(peerBansPath, target)
+	if err := c.sendJSON(ctx, http.MethodDelete, p, nil, nil); err != nil {
+		return errors.Wrapf(err, "error unbanning %s", target)
+	}
+	return nil
+}
+
+// ConnectPeer makes the beacon node dial a peer given by its multiaddr, including the peer ID, or by its ENR.
+func (c *Client) ConnectPeer(ctx context.Context, addr string) error {
+	if err := c.postJSON(ctx, connectPeerPath, &structs.AddrRequest{Addr: addr}, nil); err != nil {
+		return errors.Wrapf(err, "error connecting to %s", addr)
+	}
+	return nil
+}
+
+// DisconnectPeer makes the beacon node send a goodbye message with the given reason code to a connected peer,
+// and disconnect from it.
+func (c *Client) DisconnectPeer(ctx context.Context, peerId string, reasonCode uint64) error {
+	req := &structs.DisconnectPeerRequest{ReasonCode: strconv.FormatUint(reasonCode, 10)}
+	if err := c.postJSON(ctx, fmt.Sprintf(disconnectPeerPath, url.PathEscape(peerId)), req, nil); err != nil {
+		return errors.Wrapf(err, "error disconnecting from %s", peerId)
+	}
+	return nil
+}
+
+// GetPeerScore retrieves the score of a peer known to the beacon node.
+func (c *Client) GetPeerScore(ctx context.Context, peerId string) (*structs.PeerScore, error) {
+	body, err := c.Get(ctx, fmt.Sprintf(peerScorePath, url.PathEscape(peerId)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error requesting score of %s", peerId)
+	}
+	res := &structs.GetPeerScoreResponse{}
+	if err := json.Unmarshal(body, res); err != nil {
+		return nil, errors.Wrap(err, "failed to decode peer score")
+	}
+	return res.Data, nil
+}
+
+// SetPeerScore overrides the bad responses and processed blocks a peer score is computed from. Empty values in the
+// request are left unchanged.
+func (c *Client) SetPeerScore(ctx context.Context, peerId string, req *structs.SetPeerScoreRequest) (*structs.PeerScore, error) {
+	res := &structs.GetPeerScoreResponse{}
+	if err := c.postJSON(ctx, fmt.Sprintf(peerScorePath, url.PathEscape(peerId)), req, res); err != nil {
+		return nil, errors.Wrapf(err, "error setting score of %s", peerId)
+	}
+	return res.Data, nil
+}
+
+// postJSON sends the JSON encoding of req to the given path, and decodes the JSON response into res.
+func (c *Client) postJSON(ctx context.Context, p string, req, res interface{}) error {
+	return c.sendJSON(ctx, http.MethodPost, p, req, res)
+}
+
+// sendJSON sends a request with the given method and the JSON encoding of req as its body, if not nil, to the given
+// path. The JSON response is decoded into res, if not nil. The authentication token of the client, if any, is sent
+// as a bearer token.
+func (c *Client) sendJSON(ctx context.Context, method, p string, req, res interface{}) error {
+	u, err := url.Parse(p)
+	if err != nil {
+		return errors.Wrap(err, "invalid request path")
+	}
+	var body io.Reader = http.NoBody
+	if req != nil {
+		enc, err := json.Marshal(req)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal JSON")
+		}
+		body = bytes.NewBuffer(enc)
+	}
+	r, err := http.NewRequestWithContext(ctx, method, c.BaseURL().ResolveReference(u).String(), body)
+	if err != nil {
+		return errors.Wrapf(err, "invalid format, failed to create new %s request object", method)
+	}
+	r.Header.Set("Content-Type", "application/json")
+	if c.Token() != "" {
+		client.WithAuthorizationToken(c.Token())(r)
+	}
+	resp, err := c.Do(r)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return client.Non200Err(resp)
+	}
+	if res == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(res)
+}
+
 type forkScheduleResponse struct {
 	Data []structs.Fork
 }
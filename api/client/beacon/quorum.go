@@ -0,0 +1,298 @@
+package beacon
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	errCheckpointQuorum         = errors.New("checkpoint sync providers did not reach quorum on the finalized checkpoint")
+	errWeakSubjectivityMismatch = errors.New("finalized checkpoint is not consistent with the weak subjectivity checkpoint")
+	errStateRootQuorum          = errors.New("checkpoint sync providers did not reach quorum on the checkpoint state root")
+)
+
+// providerCheckpoint is the finalized checkpoint reported by a checkpoint sync provider, or the error
+// encountered while requesting it.
+type providerCheckpoint struct {
+	client *Client
+	epoch  primitives.Epoch
+	root   [32]byte
+	err    error
+}
+
+func (p *providerCheckpoint) String() string {
+	if p.err != nil {
+		return fmt.Sprintf("%s (error: %v)", p.client.NodeURL(), p.err)
+	}
+	return fmt.Sprintf("%s (%#x:%d)", p.client.NodeURL(), p.root, p.epoch)
+}
+
+func (p *providerCheckpoint) agrees(o *providerCheckpoint) bool {
+	return p.err == nil && o.err == nil && p.epoch == o.epoch && p.root == o.root
+}
+
+// DownloadFinalizedDataWithQuorum asks each of the given beacon nodes for their finalized checkpoint, and only
+// proceeds if at least quorum of them report the same checkpoint. A quorum of zero requires a majority of them to
+// agree. The checkpoint is cross-checked against the weak subjectivity checkpoint, when one is given, before the
+// state at the checkpoint and the checkpoint block are downloaded from one of the agreeing beacon nodes. The state
+// is only accepted if its root is the state root of the checkpoint block, or, when the checkpoint state was advanced
+// over empty slots, the state root reported by at least quorum of the agreeing beacon nodes. With a single beacon
+// node, the finalized state is downloaded as by DownloadFinalizedData, as many providers only serve the finalized state.
+func DownloadFinalizedDataWithQuorum(ctx context.Context, clients []*Client, quorum int, ws *ethpb.Checkpoint) (*OriginData, error) {
+	if len(clients) == 0 {
+		return nil, errors.New("no checkpoint sync providers given")
+	}
+	if quorum <= 0 {
+		quorum = len(clients)/2 + 1
+	}
+	if quorum > len(clients) {
+		return nil, errors.Errorf("checkpoint sync quorum %d is larger than the number of providers (%d)", quorum, len(clients))
+	}
+
+	if len(clients) == 1 {
+		return downloadFinalizedDataFromProvider(ctx, clients[0], ws)
+	}
+
+	agreed, err := finalizedCheckpointQuorum(ctx, clients, quorum)
+	if err != nil {
+		return nil, err
+	}
+	cp := agreed[0]
+	if ws != nil {
+		if ws.Epoch > cp.epoch {
+			return nil, errors.Wrapf(errWeakSubjectivityMismatch, "weak subjectivity checkpoint epoch %d is after finalized epoch %d", ws.Epoch, cp.epoch)
+		}
+		if ws.Epoch == cp.epoch && !bytes.Equal(ws.Root, cp.root[:]) {
+			return nil, errors.Wrapf(errWeakSubjectivityMismatch, "weak subjectivity checkpoint root %#x != finalized root %#x at epoch %d", ws.Root, cp.root, cp.epoch)
+		}
+	}
+
+	slot, err := slots.EpochStart(cp.epoch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error computing first slot of finalized epoch=%d", cp.epoch)
+	}
+	for _, p := range agreed {
+		od, err := downloadCheckpointData(ctx, p.client, slot, cp.root)
+		if err != nil {
+			log.WithError(err).WithField("url", p.client.NodeURL()).Warn("Could not download checkpoint state and block from provider")
+			continue
+		}
+		if err := verifyCheckpointState(ctx, od, agreed, quorum); err != nil {
+			log.WithError(err).WithField("url", p.client.NodeURL()).Warn("Checkpoint state downloaded from provider could not be verified")
+			continue
+		}
+		if err := checkWeakSubjectivity(od, ws); err != nil {
+			return nil, err
+		}
+		return od, nil
+	}
+	return nil, errors.Errorf("could not download checkpoint state and block for %#x:%d from any of the agreeing providers", cp.root, cp.epoch)
+}
+
+// downloadFinalizedDataFromProvider downloads the finalized state and block from a single provider, and checks them
+// against the weak subjectivity checkpoint when one is given.
+func downloadFinalizedDataFromProvider(ctx context.Context, c *Client, ws *ethpb.Checkpoint) (*OriginData, error) {
+	od, err := DownloadFinalizedData(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	if ws != nil {
+		epoch := slots.ToEpoch(od.st.Slot())
+		if ws.Epoch > epoch {
+			return nil, errors.Wrapf(errWeakSubjectivityMismatch, "weak subjectivity checkpoint epoch %d is after finalized epoch %d", ws.Epoch, epoch)
+		}
+		if ws.Epoch == epoch && !bytes.Equal(ws.Root, od.br[:]) {
+			return nil, errors.Wrapf(errWeakSubjectivityMismatch, "weak subjectivity checkpoint root %#x != finalized root %#x at epoch %d", ws.Root, od.br, epoch)
+		}
+	}
+	if err := checkWeakSubjectivity(od, ws); err != nil {
+		return nil, err
+	}
+	return od, nil
+}
+
+// finalizedCheckpointQuorum requests the finalized checkpoint from every provider, and returns the providers that
+// agree on the single checkpoint reported by at least quorum of them.
+func finalizedCheckpointQuorum(ctx context.Context, clients []*Client, quorum int) ([]*providerCheckpoint, error) {
+	results := make([]*providerCheckpoint, len(clients))
+	var wg sync.WaitGroup
+	for i, c := range clients {
+		wg.Add(1)
+		go func(i int, c *Client) {
+			defer wg.Done()
+			p := &providerCheckpoint{client: c}
+			cp, err := c.GetFinalizedCheckpoint(ctx, IdHead)
+			if err != nil {
+				p.err = err
+			} else {
+				p.epoch = cp.Epoch
+				p.root = bytesutil.ToBytes32(cp.Root)
+			}
+			results[i] = p
+		}(i, c)
+	}
+	wg.Wait()
+
+	var candidates [][]*providerCheckpoint
+	for i, p := range results {
+		if p.err != nil {
+			continue
+		}
+		seen := false
+		for _, q := range results[:i] {
+			if q.agrees(p) {
+				seen = true
+				break
+			}
+		}
+		if seen {
+			continue
+		}
+		group := []*providerCheckpoint{p}
+		for _, q := range results[i+1:] {
+			if q.agrees(p) {
+				group = append(group, q)
+			}
+		}
+		if len(group) >= quorum {
+			candidates = append(candidates, group)
+		}
+	}
+	if len(candidates) != 1 {
+		reports := make([]string, len(results))
+		for i, p := range results {
+			reports[i] = p.String()
+		}
+		reason := "no checkpoint was reported by enough providers"
+		if len(candidates) > 1 {
+			reason = "more than one checkpoint was reported by enough providers"
+		}
+		return nil, errors.Wrapf(errCheckpointQuorum, "%s, quorum=%d, providers: %s", reason, quorum, strings.Join(reports, ", "))
+	}
+
+	agreed := candidates[0]
+	for _, p := range results {
+		if !p.agrees(agreed[0]) {
+			log.WithFields(logrus.Fields{
+				"provider": p.String(),
+				"agreed":   fmt.Sprintf("%#x:%d", agreed[0].root, agreed[0].epoch),
+			}).Warn("Checkpoint sync provider diverged from the finalized checkpoint agreed by quorum")
+		}
+	}
+	log.WithFields(logrus.Fields{
+		"epoch":     agreed[0].epoch,
+		"root":      fmt.Sprintf("%#x", agreed[0].root),
+		"providers": len(agreed),
+		"quorum":    quorum,
+	}).Info("Checkpoint sync providers reached quorum on finalized checkpoint")
+	return agreed, nil
+}
+
+// downloadCheckpointData downloads the state at the first slot of the finalized epoch and the checkpoint block,
+// and checks that they match the agreed checkpoint root. The checkpoint served by Prysm beacon nodes in checkpoint
+// serving mode is preferred, as long as it matches the agreed checkpoint.
+func downloadCheckpointData(ctx context.Context, client *Client, slot primitives.Slot, root [32]byte) (*OriginData, error) {
+	od, err := downloadServedCheckpoint(ctx, client)
+	if err == nil && od.br == root && od.st.Slot() == slot {
+		return od, nil
+	}
+	if err != nil {
+		logServedCheckpointError(client, err)
+	}
+	sb, vu, s, err := downloadState(ctx, client, IdFromSlot(slot))
+	if err != nil {
+		return nil, err
+	}
+	if s.Slot() != slot {
+		return nil, errors.Wrapf(errCheckpointBlockMismatch, "requested state at slot %d, received state at slot %d", slot, s.Slot())
+	}
+	bb, err := client.GetBlock(ctx, IdFromRoot(root))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error requesting block by root = %#x", root)
+	}
+	od, err = newOriginData(ctx, vu, sb, s, bb)
+	if err != nil {
+		return nil, err
+	}
+	if od.br != root {
+		return nil, errors.Wrapf(errCheckpointBlockMismatch, "checkpoint root = %#x, block root = %#x", root, od.br)
+	}
+	return od, nil
+}
+
+// verifyCheckpointState checks that the downloaded state is the state at the agreed checkpoint. The checkpoint block
+// is bound to the agreed checkpoint root, so a state at the slot of the block must have the state root of the block.
+// A state advanced over empty slots is not bound to the block, so its root must be reported by a quorum of the
+// agreeing providers instead.
+func verifyCheckpointState(ctx context.Context, od *OriginData, agreed []*providerCheckpoint, quorum int) error {
+	blk := od.b.Block()
+	if od.st.Slot() == blk.Slot() {
+		if sr := blk.StateRoot(); od.sr != sr {
+			return errors.Wrapf(errCheckpointBlockMismatch, "state root = %#x, block state root = %#x", od.sr, sr)
+		}
+		return nil
+	}
+	roots := make([][32]byte, len(agreed))
+	errs := make([]error, len(agreed))
+	var wg sync.WaitGroup
+	for i, p := range agreed {
+		wg.Add(1)
+		go func(i int, c *Client) {
+			defer wg.Done()
+			roots[i], errs[i] = c.GetStateRoot(ctx, IdFromSlot(od.st.Slot()))
+		}(i, p.client)
+	}
+	wg.Wait()
+	reported := 0
+	for i, p := range agreed {
+		switch {
+		case errs[i] != nil:
+			log.WithError(errs[i]).WithField("url", p.client.NodeURL()).Warn("Could not request checkpoint state root from provider")
+		case roots[i] == od.sr:
+			reported++
+		default:
+			log.WithFields(logrus.Fields{
+				"url":       p.client.NodeURL(),
+				"stateRoot": fmt.Sprintf("%#x", roots[i]),
+				"expected":  fmt.Sprintf("%#x", od.sr),
+			}).Warn("Checkpoint sync provider reported a different checkpoint state root")
+		}
+	}
+	if reported < quorum {
+		return errors.Wrapf(errStateRootQuorum, "state root %#x at slot %d reported by %d providers, quorum=%d", od.sr, od.st.Slot(), reported, quorum)
+	}
+	return nil
+}
+
+// checkWeakSubjectivity checks that the downloaded state descends from the weak subjectivity checkpoint. This can
+// only be verified while the checkpoint block is within the block roots history of the state.
+func checkWeakSubjectivity(od *OriginData, ws *ethpb.Checkpoint) error {
+	if ws == nil || slots.ToEpoch(od.st.Slot()) == ws.Epoch {
+		return nil
+	}
+	wsSlot, err := slots.EpochStart(ws.Epoch)
+	if err != nil {
+		return errors.Wrapf(err, "error computing first slot of weak subjectivity epoch=%d", ws.Epoch)
+	}
+	root, err := helpers.BlockRootAtSlot(od.st, wsSlot)
+	if err != nil {
+		log.WithError(err).WithField("epoch", ws.Epoch).
+			Warn("Weak subjectivity checkpoint is too old to be verified against the checkpoint sync state")
+		return nil
+	}
+	if !bytes.Equal(root, ws.Root) {
+		return errors.Wrapf(errWeakSubjectivityMismatch, "weak subjectivity checkpoint root %#x != block root %#x at epoch %d", ws.Root, root, ws.Epoch)
+	}
+	return nil
+}
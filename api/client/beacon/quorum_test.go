@@ -0,0 +1,270 @@
+package beacon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/prysmaticlabs/prysm/v5/api/client"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	blocktest "github.com/prysmaticlabs/prysm/v5/consensus-types/blocks/testing"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+)
+
+type checkpointProvider struct {
+	epoch primitives.Epoch
+	root  [32]byte
+	sb    []byte
+	bb    []byte
+	// stateRoot is the state root reported at the first slot of the finalized epoch.
+	stateRoot [32]byte
+	// served makes the provider serve the checkpoint like a Prysm beacon node in checkpoint serving mode.
+	served    bool
+	requested []string
+}
+
+func (p *checkpointProvider) client(t *testing.T, host string) *Client {
+	slot, err := slots.EpochStart(p.epoch)
+	require.NoError(t, err)
+	trans := &testRT{rt: func(req *http.Request) (*http.Response, error) {
+		res := &http.Response{Request: req, StatusCode: http.StatusOK, Header: http.Header{}}
+		p.requested = append(p.requested, req.URL.Path)
+		switch {
+		case p.served && req.URL.Path == getCheckpointStatePath:
+			res.Body = io.NopCloser(bytes.NewBuffer(p.sb))
+			return res, nil
+		case p.served && req.URL.Path == getCheckpointBlockPath:
+			// The served block must be pinned to the root of the downloaded state.
+			if req.URL.Query().Get("block_root") != hexutil.Encode(p.root[:]) {
+				res.StatusCode = http.StatusNotFound
+				res.Body = io.NopCloser(bytes.NewBuffer(nil))
+				return res, nil
+			}
+			res.Body = io.NopCloser(bytes.NewBuffer(p.bb))
+			return res, nil
+		}
+		switch req.URL.Path {
+		case getFinalityCheckpointsTpl(IdHead):
+			body, err := json.Marshal(&structs.GetFinalityCheckpointsResponse{Data: &structs.FinalityCheckpoints{
+				Finalized: &structs.Checkpoint{Epoch: strconv.FormatUint(uint64(p.epoch), 10), Root: hexutil.Encode(p.root[:])},
+			}})
+			if err != nil {
+				return nil, err
+			}
+			res.Body = io.NopCloser(bytes.NewBuffer(body))
+		case getStateRootTpl(IdFromSlot(slot)):
+			body, err := json.Marshal(&structs.GetStateRootResponse{Data: &structs.StateRoot{Root: hexutil.Encode(p.stateRoot[:])}})
+			if err != nil {
+				return nil, err
+			}
+			res.Body = io.NopCloser(bytes.NewBuffer(body))
+		case renderGetStatePath(IdFromSlot(slot)):
+			res.Body = io.NopCloser(bytes.NewBuffer(p.sb))
+		case renderGetBlockPath(IdFromRoot(p.root)):
+			res.Body = io.NopCloser(bytes.NewBuffer(p.bb))
+		case renderGetStatePath(IdFinalized):
+			res.Body = io.NopCloser(bytes.NewBuffer(p.sb))
+		case renderGetBlockPath(IdFromSlot(slot)):
+			res.Body = io.NopCloser(bytes.NewBuffer(p.bb))
+		default:
+			res.StatusCode = http.StatusNotFound
+			res.Body = io.NopCloser(bytes.NewBufferString(""))
+		}
+		return res, nil
+	}}
+	c, err := NewClient(host, client.WithRoundTripper(trans))
+	require.NoError(t, err)
+	return c
+}
+
+// testCheckpointProvider sets up a provider serving a finalized checkpoint state and block at the given epoch.
+// The ancestor root is recorded in the block roots of the state at the first slot of the previous epoch. When
+// skipped is not zero, the checkpoint block is that many slots before the state, which is advanced over empty slots.
+func testCheckpointProvider(t *testing.T, epoch primitives.Epoch, ancestor [32]byte, skipped primitives.Slot) *checkpointProvider {
+	ctx := context.Background()
+	cfg := params.MainnetConfig().Copy()
+	slot, err := slots.EpochStart(epoch)
+	require.NoError(t, err)
+	st, err := util.NewBeaconState()
+	require.NoError(t, err)
+	fork, err := forkForEpoch(cfg, epoch)
+	require.NoError(t, err)
+	require.NoError(t, st.SetFork(fork))
+	require.NoError(t, st.SetSlot(slot))
+	require.NoError(t, st.UpdateBlockRootAtIndex(uint64((slot-cfg.SlotsPerEpoch)%cfg.SlotsPerHistoricalRoot), ancestor))
+
+	b, err := blocks.NewSignedBeaconBlock(util.NewBeaconBlock())
+	require.NoError(t, err)
+	b, err = blocktest.SetBlockParentRoot(b, ancestor)
+	require.NoError(t, err)
+	b, err = blocktest.SetBlockSlot(b, slot-skipped)
+	require.NoError(t, err)
+	header, err := b.Header()
+	require.NoError(t, err)
+	require.NoError(t, st.SetSlot(slot-skipped))
+	require.NoError(t, st.SetLatestBlockHeader(header.Header))
+	sr, err := st.HashTreeRoot(ctx)
+	require.NoError(t, err)
+	b, err = blocktest.SetBlockStateRoot(b, sr)
+	require.NoError(t, err)
+	if skipped != 0 {
+		header.Header.StateRoot = sr[:]
+		require.NoError(t, st.SetLatestBlockHeader(header.Header))
+		require.NoError(t, st.SetSlot(slot))
+	}
+
+	p := &checkpointProvider{epoch: epoch}
+	p.stateRoot, err = st.HashTreeRoot(ctx)
+	require.NoError(t, err)
+	p.bb, err = b.MarshalSSZ()
+	require.NoError(t, err)
+	p.root, err = b.Block().HashTreeRoot()
+	require.NoError(t, err)
+	p.sb, err = st.MarshalSSZ()
+	require.NoError(t, err)
+	return p
+}
+
+// forgeState replaces the state served by the provider with a state at the same checkpoint, whose genesis time was
+// changed, and reports its root.
+func forgeState(t *testing.T, p *checkpointProvider) *checkpointProvider {
+	forged := *p
+	st := &ethpb.BeaconState{}
+	require.NoError(t, st.UnmarshalSSZ(p.sb))
+	st.GenesisTime++
+	var err error
+	forged.sb, err = st.MarshalSSZ()
+	require.NoError(t, err)
+	forged.stateRoot, err = st.HashTreeRoot()
+	require.NoError(t, err)
+	return &forged
+}
+
+func TestDownloadFinalizedDataWithQuorum(t *testing.T) {
+	ctx := context.Background()
+	// avoid the altair zone because genesis tests are easier to set up
+	epoch := params.MainnetConfig().AltairForkEpoch - 1
+	ancestor := [32]byte{'a'}
+	honest := testCheckpointProvider(t, epoch, ancestor, 0)
+	diverged := testCheckpointProvider(t, epoch, [32]byte{'d'}, 0)
+	clients := []*Client{
+		honest.client(t, "http://honest-1:3500"),
+		diverged.client(t, "http://diverged:3500"),
+		honest.client(t, "http://honest-2:3500"),
+	}
+
+	t.Run("quorum reached", func(t *testing.T) {
+		od, err := DownloadFinalizedDataWithQuorum(ctx, clients, 2, nil)
+		require.NoError(t, err)
+		assert.Equal(t, honest.root, od.br)
+		assert.Equal(t, true, bytes.Equal(honest.sb, od.StateBytes()))
+		assert.Equal(t, true, bytes.Equal(honest.bb, od.BlockBytes()))
+	})
+	t.Run("majority by default", func(t *testing.T) {
+		od, err := DownloadFinalizedDataWithQuorum(ctx, clients, 0, nil)
+		require.NoError(t, err)
+		assert.Equal(t, honest.root, od.br)
+		_, err = DownloadFinalizedDataWithQuorum(ctx, []*Client{clients[0], clients[1]}, 0, nil)
+		require.ErrorIs(t, err, errCheckpointQuorum)
+		require.ErrorContains(t, fmt.Sprintf("http://diverged:3500 (%#x:%d)", diverged.root, epoch), err)
+	})
+	t.Run("forged state", func(t *testing.T) {
+		forged := forgeState(t, honest)
+		od, err := DownloadFinalizedDataWithQuorum(ctx, []*Client{forged.client(t, "http://forged:3500"), clients[0]}, 2, nil)
+		require.NoError(t, err)
+		assert.Equal(t, true, bytes.Equal(honest.sb, od.StateBytes()))
+		_, err = DownloadFinalizedDataWithQuorum(ctx, []*Client{forged.client(t, "http://forged-1:3500"), forged.client(t, "http://forged-2:3500")}, 2, nil)
+		require.ErrorContains(t, "could not download checkpoint state and block", err)
+	})
+	t.Run("advanced state", func(t *testing.T) {
+		advanced := testCheckpointProvider(t, epoch, ancestor, 3)
+		forged := forgeState(t, advanced)
+		od, err := DownloadFinalizedDataWithQuorum(ctx, []*Client{
+			forged.client(t, "http://forged:3500"),
+			advanced.client(t, "http://advanced-1:3500"),
+			advanced.client(t, "http://advanced-2:3500"),
+		}, 2, nil)
+		require.NoError(t, err)
+		assert.Equal(t, true, bytes.Equal(advanced.sb, od.StateBytes()))
+		// The root of a state advanced over empty slots must be reported by a quorum of the providers.
+		_, err = DownloadFinalizedDataWithQuorum(ctx, []*Client{
+			forged.client(t, "http://forged:3500"),
+			advanced.client(t, "http://advanced-1:3500"),
+		}, 2, nil)
+		require.ErrorContains(t, "could not download checkpoint state and block", err)
+	})
+	t.Run("unreachable provider", func(t *testing.T) {
+		unreachable, err := NewClient("http://unreachable:3500", client.WithRoundTripper(&testRT{}))
+		require.NoError(t, err)
+		_, err = DownloadFinalizedDataWithQuorum(ctx, []*Client{clients[0], unreachable}, 2, nil)
+		require.ErrorIs(t, err, errCheckpointQuorum)
+		require.ErrorContains(t, "http://unreachable:3500 (error:", err)
+	})
+	t.Run("quorum larger than providers", func(t *testing.T) {
+		_, err := DownloadFinalizedDataWithQuorum(ctx, clients, 4, nil)
+		require.ErrorContains(t, "larger than the number of providers", err)
+	})
+	t.Run("served checkpoint", func(t *testing.T) {
+		served := *honest
+		served.served = true
+		served.requested = nil
+		od, err := DownloadFinalizedDataWithQuorum(ctx, []*Client{served.client(t, "http://served:3500"), clients[0]}, 0, nil)
+		require.NoError(t, err)
+		assert.Equal(t, honest.root, od.br)
+		assert.DeepEqual(t, []string{getFinalityCheckpointsTpl(IdHead), getCheckpointStatePath, getCheckpointBlockPath}, served.requested)
+	})
+	t.Run("weak subjectivity checkpoint", func(t *testing.T) {
+		cases := []struct {
+			name string
+			ws   *ethpb.Checkpoint
+			err  bool
+		}{
+			{name: "same checkpoint", ws: &ethpb.Checkpoint{Epoch: epoch, Root: honest.root[:]}},
+			{name: "different root", ws: &ethpb.Checkpoint{Epoch: epoch, Root: ancestor[:]}, err: true},
+			{name: "later epoch", ws: &ethpb.Checkpoint{Epoch: epoch + 1, Root: honest.root[:]}, err: true},
+			{name: "ancestor", ws: &ethpb.Checkpoint{Epoch: epoch - 1, Root: ancestor[:]}},
+			{name: "not an ancestor", ws: &ethpb.Checkpoint{Epoch: epoch - 1, Root: honest.root[:]}, err: true},
+			{name: "too old to verify", ws: &ethpb.Checkpoint{Epoch: 1, Root: honest.root[:]}},
+		}
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				_, err := DownloadFinalizedDataWithQuorum(ctx, clients, 2, c.ws)
+				if c.err {
+					require.ErrorIs(t, err, errWeakSubjectivityMismatch)
+				} else {
+					require.NoError(t, err)
+				}
+				_, err = DownloadFinalizedDataWithQuorum(ctx, clients[:1], 0, c.ws)
+				if c.err {
+					require.ErrorIs(t, err, errWeakSubjectivityMismatch)
+				} else {
+					require.NoError(t, err)
+				}
+			})
+		}
+	})
+	t.Run("single provider", func(t *testing.T) {
+		// A single provider is not asked for its checkpoint, the finalized state is downloaded directly.
+		single := *honest
+		single.requested = nil
+		od, err := DownloadFinalizedDataWithQuorum(ctx, []*Client{single.client(t, "http://single:3500")}, 0, nil)
+		require.NoError(t, err)
+		assert.Equal(t, honest.root, od.br)
+		slot, err := slots.EpochStart(epoch)
+		require.NoError(t, err)
+		assert.DeepEqual(t, []string{getCheckpointStatePath, renderGetStatePath(IdFinalized), renderGetBlockPath(IdFromSlot(slot))}, single.requested)
+	})
+}
@@ -2,10 +2,12 @@ package client
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -103,3 +105,74 @@ func (c *Client) Get(ctx context.Context, path string, opts ...ReqOption) ([]byt
 	}
 	return b, nil
 }
+
+// MaxResumeAttempts is the number of times GetResumable resumes an interrupted download.
+const MaxResumeAttempts = 8
+
+// GetResumable is like Get, but resumes downloads that are interrupted while reading the response body.
+// Downloads can only be resumed if the server identifies the response with a strong ETag, and supports
+// range requests. The remainder of the body is then requested with a Range header, and the If-Range header
+// ensures the download restarts from scratch if the resource changed in the meantime.
+func (c *Client) GetResumable(ctx context.Context, path string, opts ...ReqOption) ([]byte, error) {
+	u := c.baseURL.ResolveReference(&url.URL{Path: path})
+	var (
+		body []byte
+		etag string
+		err  error
+	)
+	for attempt := 0; ; attempt++ {
+		body, etag, err = c.getRemainder(ctx, u.String(), body, etag, opts...)
+		if err == nil {
+			return body, nil
+		}
+		if errors.Is(err, ErrNotOK) || etag == "" || attempt >= MaxResumeAttempts || ctx.Err() != nil {
+			return nil, err
+		}
+	}
+}
+
+// getRemainder requests the part of the resource following the partial body, or the whole resource if the
+// partial body is empty, and appends it to the partial body. The returned ETag is empty if the download
+// can not be resumed.
+func (c *Client) getRemainder(ctx context.Context, u string, partial []byte, etag string, opts ...ReqOption) (body []byte, newEtag string, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, o := range opts {
+		o(req)
+	}
+	if len(partial) > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", len(partial)))
+		req.Header.Set("If-Range", etag)
+	}
+	r, err := c.hc.Do(req)
+	if err != nil {
+		return partial, etag, err
+	}
+	defer func() {
+		if closeErr := r.Body.Close(); closeErr != nil && err == nil {
+			err = errors.Wrap(closeErr, "error closing http response body")
+		}
+	}()
+	switch r.StatusCode {
+	case http.StatusOK:
+		partial = partial[:0]
+		etag = r.Header.Get("ETag")
+		if strings.HasPrefix(etag, "W/") || r.Header.Get("Accept-Ranges") != "bytes" {
+			etag = ""
+		}
+	case http.StatusPartialContent:
+		if !strings.HasPrefix(r.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", len(partial))) {
+			return nil, "", errors.Wrapf(ErrNotOK, "unexpected Content-Range %q when resuming download at byte %d", r.Header.Get("Content-Range"), len(partial))
+		}
+	default:
+		return nil, "", Non200Err(r)
+	}
+	b, err := io.ReadAll(io.LimitReader(r.Body, c.maxBodySize-int64(len(partial))))
+	body = append(partial, b...)
+	if err != nil {
+		return body, etag, errors.Wrap(err, "error reading http response body")
+	}
+	return body, etag, nil
+}
@@ -1,8 +1,16 @@
 package client
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/prysmaticlabs/prysm/v5/testing/require"
 )
@@ -46,3 +54,87 @@ func TestBaseURL(t *testing.T) {
 	require.Equal(t, "www.offchainlabs.com", cl.BaseURL().Hostname())
 	require.Equal(t, "3500", cl.BaseURL().Port())
 }
+
+type truncatingBody struct {
+	io.ReadCloser
+	remaining int
+}
+
+func (b *truncatingBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	if len(p) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= n
+	return n, err
+}
+
+// truncatingTransport interrupts the response body of the first requests after a few bytes.
+type truncatingTransport struct {
+	interruptions int
+	requests      []*http.Request
+}
+
+func (t *truncatingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests = append(t.requests, req)
+	res, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil || t.interruptions == 0 {
+		return res, err
+	}
+	t.interruptions--
+	res.Body = &truncatingBody{ReadCloser: res.Body, remaining: 10}
+	return res, nil
+}
+
+func TestGetResumable(t *testing.T) {
+	content := []byte(strings.Repeat("checkpoint state ", 10))
+	const etag = `"v1"`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", etag)
+		http.ServeContent(w, r, "state.ssz", time.Time{}, bytes.NewReader(content))
+	}))
+	defer srv.Close()
+
+	t.Run("resumed", func(t *testing.T) {
+		tr := &truncatingTransport{interruptions: 2}
+		cl, err := NewClient(srv.URL, WithRoundTripper(tr))
+		require.NoError(t, err)
+		b, err := cl.GetResumable(context.Background(), "/state")
+		require.NoError(t, err)
+		require.DeepEqual(t, content, b)
+		require.Equal(t, 3, len(tr.requests))
+		require.Equal(t, "bytes=20-", tr.requests[2].Header.Get("Range"))
+		require.Equal(t, etag, tr.requests[2].Header.Get("If-Range"))
+	})
+	t.Run("resource changed", func(t *testing.T) {
+		requests := 0
+		changing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("ETag", fmt.Sprintf(`"v%d"`, requests))
+			http.ServeContent(w, r, "state.ssz", time.Time{}, bytes.NewReader(content))
+		}))
+		defer changing.Close()
+		tr := &truncatingTransport{interruptions: 1}
+		cl, err := NewClient(changing.URL, WithRoundTripper(tr))
+		require.NoError(t, err)
+		b, err := cl.GetResumable(context.Background(), "/state")
+		require.NoError(t, err)
+		require.DeepEqual(t, content, b)
+		require.Equal(t, 2, len(tr.requests))
+		require.Equal(t, `"v1"`, tr.requests[1].Header.Get("If-Range"))
+	})
+	t.Run("not resumable without etag", func(t *testing.T) {
+		plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write(content)
+			require.NoError(t, err)
+		}))
+		defer plain.Close()
+		cl, err := NewClient(plain.URL, WithRoundTripper(&truncatingTransport{interruptions: 1}))
+		require.NoError(t, err)
+		_, err = cl.GetResumable(context.Background(), "/state")
+		require.ErrorContains(t, "error reading http response body", err)
+	})
+}
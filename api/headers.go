@@ -7,9 +7,13 @@ const (
 	ExecutionPayloadBlindedHeader = "Eth-Execution-Payload-Blinded"
 	ExecutionPayloadValueHeader   = "Eth-Execution-Payload-Value"
 	ConsensusBlockValueHeader     = "Eth-Consensus-Block-Value"
+	ExecutionOptimisticHeader     = "Prysm-Execution-Optimistic"
+	FinalizedHeader               = "Prysm-Finalized"
+	NextPageTokenHeader           = "Prysm-Next-Page-Token"
 	JsonMediaType                 = "application/json"
 	OctetStreamMediaType          = "application/octet-stream"
 	EventStreamMediaType          = "text/event-stream"
+	NdjsonMediaType               = "application/x-ndjson"
 	KeepAlive                     = "keep-alive"
 )
 
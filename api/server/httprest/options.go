@@ -1,10 +1,14 @@
 package httprest
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
 	"time"
 
 	"net/http"
 
+	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/v5/api/server/middleware"
 )
 
@@ -42,3 +46,33 @@ func WithTimeout(duration time.Duration) Option {
 		return nil
 	}
 }
+
+// WithTLS serves HTTPS with the given certificate and key. If a client CA file is given, clients can authenticate
+// with a certificate signed by one of its CAs. Client certificates are optional, so that clients can also
+// authenticate with bearer tokens.
+func WithTLS(certFile, keyFile, clientCAFile string) Option {
+	return func(g *Server) error {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return errors.Wrap(err, "could not load TLS certificate")
+		}
+		cfg := &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		}
+		if clientCAFile != "" {
+			pem, err := os.ReadFile(clientCAFile) // #nosec G304
+			if err != nil {
+				return errors.Wrap(err, "could not read client CA file")
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return errors.New("no certificates found in client CA file")
+			}
+			cfg.ClientCAs = pool
+			cfg.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+		g.cfg.tlsConfig = cfg
+		return nil
+	}
+}
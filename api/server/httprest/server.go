@@ -2,12 +2,14 @@ package httprest
 
 import (
 	"context"
+	"crypto/tls"
 	"net/http"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/v5/api/server/middleware"
 	"github.com/prysmaticlabs/prysm/v5/runtime"
+	"github.com/sirupsen/logrus"
 )
 
 var _ runtime.Service = (*Server)(nil)
@@ -18,6 +20,7 @@ type config struct {
 	middlewares []middleware.Middleware
 	router      http.Handler
 	timeout     time.Duration
+	tlsConfig   *tls.Config
 }
 
 // Server serves HTTP traffic.
@@ -54,6 +57,7 @@ func New(ctx context.Context, opts ...Option) (*Server, error) {
 		Addr:              g.cfg.httpAddr,
 		Handler:           handler,
 		ReadHeaderTimeout: defaultReadHeaderTimeout,
+		TLSConfig:         g.cfg.tlsConfig,
 	}
 
 	return g, nil
@@ -64,8 +68,18 @@ func (g *Server) Start() {
 	g.ctx, g.cancel = context.WithCancel(g.ctx)
 
 	go func() {
-		log.WithField("address", g.cfg.httpAddr).Info("Starting HTTP server")
-		if err := g.server.ListenAndServe(); err != http.ErrServerClosed {
+		log.WithFields(logrus.Fields{
+			"address": g.cfg.httpAddr,
+			"tls":     g.cfg.tlsConfig != nil,
+		}).Info("Starting HTTP server")
+		var err error
+		if g.cfg.tlsConfig != nil {
+			// The certificate is already in the TLS config.
+			err = g.server.ListenAndServeTLS("", "")
+		} else {
+			err = g.server.ListenAndServe()
+		}
+		if err != http.ErrServerClosed {
 			log.WithError(err).Error("Failed to start HTTP server")
 			g.startFailure = err
 			return
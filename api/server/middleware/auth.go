@@ -0,0 +1,203 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	leakybucket "github.com/prysmaticlabs/prysm/v5/container/leaky-bucket"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
+	"gopkg.in/yaml.v2"
+)
+
+// Scope is a permission over a group of API routes granted to an API client.
+type Scope string
+
+const (
+	// ScopeReadOnly grants access to the routes that read the chain and the node, such as states, blocks and node info.
+	ScopeReadOnly Scope = "read-only"
+	// ScopeValidator grants access to the validator duty routes and to the routes that publish blocks and operations.
+	ScopeValidator Scope = "validator"
+	// ScopeDebug grants access to the debug routes, which can be expensive to serve.
+	ScopeDebug Scope = "debug"
+	// ScopeAdmin grants access to the routes that change the node configuration, and to all other routes.
+	ScopeAdmin Scope = "admin"
+)
+
+// Reasons of authentication rejections, used as metric labels.
+const (
+	rejectUnauthenticated = "unauthenticated"
+	rejectForbidden       = "forbidden"
+	rejectRateLimited     = "rate_limited"
+)
+
+const unknownClient = "unknown"
+
+func (s Scope) valid() bool {
+	switch s {
+	case ScopeReadOnly, ScopeValidator, ScopeDebug, ScopeAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+// AuthConfig is the list of clients allowed to call the API.
+type AuthConfig struct {
+	Clients []*AuthClient `yaml:"clients"`
+}
+
+// AuthClient is an API client authenticated with a bearer token or with the common name of its TLS client certificate.
+// Any scope includes ScopeReadOnly, and ScopeAdmin includes all scopes. RateLimit is the number of requests per second
+// the client can make after it used its Burst, the client is not rate limited if RateLimit is 0.
+type AuthClient struct {
+	Name           string  `yaml:"name"`
+	Token          string  `yaml:"token,omitempty"`
+	CertCommonName string  `yaml:"cert_common_name,omitempty"`
+	Scopes         []Scope `yaml:"scopes"`
+	RateLimit      float64 `yaml:"rate_limit,omitempty"`
+	Burst          int64   `yaml:"burst,omitempty"`
+}
+
+// LoadAuthConfig reads the auth config from the YAML file at the given path.
+func LoadAuthConfig(path string) (*AuthConfig, error) {
+	b, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read auth config")
+	}
+	cfg := &AuthConfig{}
+	if err := yaml.UnmarshalStrict(b, cfg); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal auth config")
+	}
+	return cfg, nil
+}
+
+type authClient struct {
+	name    string
+	scopes  []Scope
+	limiter *leakybucket.Collector
+	rate    float64
+}
+
+func (c *authClient) allowed(scope Scope) bool {
+	for _, s := range c.scopes {
+		if s == ScopeAdmin || s == scope || scope == ScopeReadOnly {
+			return true
+		}
+	}
+	return false
+}
+
+// Authenticator authenticates API requests with bearer tokens or TLS client certificates, checks that the client was
+// granted the scope of the route and applies the rate limit of the client.
+type Authenticator struct {
+	byToken      map[[32]byte]*authClient
+	byCommonName map[string]*authClient
+}
+
+// NewAuthenticator returns an authenticator of the clients of the given config.
+func NewAuthenticator(cfg *AuthConfig) (*Authenticator, error) {
+	a := &Authenticator{
+		byToken:      make(map[[32]byte]*authClient),
+		byCommonName: make(map[string]*authClient),
+	}
+	names := make(map[string]bool)
+	for _, c := range cfg.Clients {
+		if c.Name == "" {
+			return nil, errors.New("client without a name")
+		}
+		if names[c.Name] {
+			return nil, fmt.Errorf("duplicate client %s", c.Name)
+		}
+		names[c.Name] = true
+		if c.Token == "" && c.CertCommonName == "" {
+			return nil, fmt.Errorf("client %s has neither a token nor a certificate common name", c.Name)
+		}
+		if len(c.Scopes) == 0 {
+			return nil, fmt.Errorf("client %s has no scopes", c.Name)
+		}
+		for _, s := range c.Scopes {
+			if !s.valid() {
+				return nil, fmt.Errorf("client %s has unknown scope %s", c.Name, s)
+			}
+		}
+		if c.RateLimit < 0 || c.Burst < 0 {
+			return nil, fmt.Errorf("client %s has a negative rate limit", c.Name)
+		}
+		ac := &authClient{name: c.Name, scopes: c.Scopes, rate: c.RateLimit}
+		if c.RateLimit > 0 {
+			burst := c.Burst
+			if burst == 0 {
+				burst = int64(math.Ceil(c.RateLimit))
+			}
+			ac.limiter = leakybucket.NewCollector(c.RateLimit, burst, time.Second, false)
+		}
+		if c.Token != "" {
+			h := sha256.Sum256([]byte(c.Token))
+			if _, ok := a.byToken[h]; ok {
+				return nil, fmt.Errorf("client %s has the token of another client", c.Name)
+			}
+			a.byToken[h] = ac
+		}
+		if c.CertCommonName != "" {
+			if _, ok := a.byCommonName[c.CertCommonName]; ok {
+				return nil, fmt.Errorf("client %s has the certificate common name of another client", c.Name)
+			}
+			a.byCommonName[c.CertCommonName] = ac
+		}
+	}
+	return a, nil
+}
+
+// authenticate returns the client of the request. A bearer token takes precedence over the client certificate,
+// which is only considered if it was verified during the TLS handshake.
+func (a *Authenticator) authenticate(r *http.Request) (*authClient, bool) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok {
+			return nil, false
+		}
+		// Tokens are looked up by hash so that the lookup time does not depend on the token.
+		c, ok := a.byToken[sha256.Sum256([]byte(strings.TrimSpace(token)))]
+		return c, ok
+	}
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+		c, ok := a.byCommonName[r.TLS.VerifiedChains[0][0].Subject.CommonName]
+		return c, ok
+	}
+	return nil, false
+}
+
+// Handler returns a middleware that only lets through requests of clients granted the given scope and within
+// their rate limit.
+func (a *Authenticator) Handler(scope Scope) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			c, ok := a.authenticate(r)
+			if !ok {
+				authRejections.WithLabelValues(unknownClient, rejectUnauthenticated).Inc()
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				httputil.HandleError(w, "Missing or invalid credentials", http.StatusUnauthorized)
+				return
+			}
+			if !c.allowed(scope) {
+				authRejections.WithLabelValues(c.name, rejectForbidden).Inc()
+				httputil.HandleError(w, fmt.Sprintf("Client %s is not allowed to access %s routes", c.name, scope), http.StatusForbidden)
+				return
+			}
+			if c.limiter != nil && c.limiter.Add(c.name, 1) == 0 {
+				authRejections.WithLabelValues(c.name, rejectRateLimited).Inc()
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(1/c.rate))))
+				httputil.HandleError(w, "Rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
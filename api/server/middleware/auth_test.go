@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func testAuthenticator(t *testing.T) *Authenticator {
+	a, err := NewAuthenticator(&AuthConfig{Clients: []*AuthClient{
+		{Name: "reader", Token: "reader-token", Scopes: []Scope{ScopeReadOnly}},
+		{Name: "validator", Token: "validator-token", Scopes: []Scope{ScopeValidator}, RateLimit: 1, Burst: 2},
+		{Name: "admin", CertCommonName: "admin.example.com", Scopes: []Scope{ScopeAdmin}},
+	}})
+	require.NoError(t, err)
+	return a
+}
+
+func TestAuthenticator_Handler(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	a := testAuthenticator(t)
+
+	tests := []struct {
+		name   string
+		scope  Scope
+		token  string
+		cn     string
+		status int
+	}{
+		{name: "no credentials", scope: ScopeReadOnly, status: http.StatusUnauthorized},
+		{name: "unknown token", scope: ScopeReadOnly, token: "foo", status: http.StatusUnauthorized},
+		{name: "read-only", scope: ScopeReadOnly, token: "reader-token", status: http.StatusOK},
+		{name: "read-only on validator route", scope: ScopeValidator, token: "reader-token", status: http.StatusForbidden},
+		{name: "validator on read-only route", scope: ScopeReadOnly, token: "validator-token", status: http.StatusOK},
+		{name: "validator on debug route", scope: ScopeDebug, token: "validator-token", status: http.StatusForbidden},
+		{name: "admin certificate on debug route", scope: ScopeDebug, cn: "admin.example.com", status: http.StatusOK},
+		{name: "unknown certificate", scope: ScopeReadOnly, cn: "foo.example.com", status: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/test", http.NoBody)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			if tt.cn != "" {
+				cert := &x509.Certificate{Subject: pkix.Name{CommonName: tt.cn}}
+				req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+			}
+			rr := httptest.NewRecorder()
+			a.Handler(tt.scope)(next).ServeHTTP(rr, req)
+			assert.Equal(t, tt.status, rr.Code)
+		})
+	}
+}
+
+func TestAuthenticator_Handler_RateLimit(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := testAuthenticator(t).Handler(ScopeValidator)(next)
+
+	codes := make([]int, 3)
+	for i := range codes {
+		req := httptest.NewRequest(http.MethodGet, "/test", http.NoBody)
+		req.Header.Set("Authorization", "Bearer validator-token")
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		codes[i] = rr.Code
+		if rr.Code == http.StatusTooManyRequests {
+			assert.Equal(t, "1", rr.Header().Get("Retry-After"))
+		}
+	}
+	assert.DeepEqual(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+}
+
+func TestNewAuthenticator_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		clients []*AuthClient
+		err     string
+	}{
+		{
+			name:    "no credentials",
+			clients: []*AuthClient{{Name: "a", Scopes: []Scope{ScopeReadOnly}}},
+			err:     "neither a token nor a certificate common name",
+		},
+		{
+			name:    "unknown scope",
+			clients: []*AuthClient{{Name: "a", Token: "t", Scopes: []Scope{"root"}}},
+			err:     "unknown scope root",
+		},
+		{
+			name: "duplicate token",
+			clients: []*AuthClient{
+				{Name: "a", Token: "t", Scopes: []Scope{ScopeReadOnly}},
+				{Name: "b", Token: "t", Scopes: []Scope{ScopeReadOnly}},
+			},
+			err: "client b has the token of another client",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAuthenticator(&AuthConfig{Clients: tt.clients})
+			assert.ErrorContains(t, tt.err, err)
+		})
+	}
+}
+
+func TestLoadAuthConfig(t *testing.T) {
+	f := filepath.Join(t.TempDir(), "auth.yaml")
+	content := `clients:
+  - name: partner
+    token: secret
+    scopes: [read-only, debug]
+    rate_limit: 10
+    burst: 20
+`
+	require.NoError(t, os.WriteFile(f, []byte(content), 0600))
+	cfg, err := LoadAuthConfig(f)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(cfg.Clients))
+	assert.DeepEqual(t, &AuthClient{Name: "partner", Token: "secret", Scopes: []Scope{ScopeReadOnly, ScopeDebug}, RateLimit: 10, Burst: 20}, cfg.Clients[0])
+}
@@ -0,0 +1,14 @@
+package middleware
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var authRejections = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "http_auth_rejections_total",
+		Help: "Number of HTTP API requests rejected by authentication, scope checks or rate limits.",
+	},
+	[]string{"client", "reason"},
+)
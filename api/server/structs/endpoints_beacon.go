@@ -107,6 +107,14 @@ type ValidatorContainer struct {
 	Validator *Validator `json:"validator"`
 }
 
+// StreamedValidator is a validator of the Prysm validator stream, with only the fields requested by the projection.
+type StreamedValidator struct {
+	Index     string     `json:"index"`
+	Balance   string     `json:"balance,omitempty"`
+	Status    string     `json:"status,omitempty"`
+	Validator *Validator `json:"validator,omitempty"`
+}
+
 type ValidatorBalance struct {
 	Index   string `json:"index"`
 	Balance string `json:"balance"`
@@ -194,6 +202,20 @@ type WeakSubjectivityData struct {
 	StateRoot    string      `json:"state_root"`
 }
 
+type GetMerkleProofResponse struct {
+	Version             string      `json:"version"`
+	ExecutionOptimistic bool        `json:"execution_optimistic"`
+	Finalized           bool        `json:"finalized"`
+	Data                *Multiproof `json:"data"`
+}
+
+type Multiproof struct {
+	Root    string   `json:"root"`
+	Indices []string `json:"indices"`
+	Leaves  []string `json:"leaves"`
+	Hashes  []string `json:"hashes"`
+}
+
 type GetDepositSnapshotResponse struct {
 	Data *DepositSnapshot `json:"data"`
 }
@@ -24,3 +24,19 @@ type PublishBlobsRequest struct {
 	BlobSidecars *BlobSidecars `json:"blob_sidecars"`
 	BlockRoot    string        `json:"block_root"`
 }
+
+type BlobArchiveCoverageResponse struct {
+	Data *BlobArchiveCoverage `json:"data"`
+}
+
+type BlobArchiveCoverage struct {
+	CheckedStartSlot string            `json:"checked_start_slot"`
+	CheckedEndSlot   string            `json:"checked_end_slot"`
+	Gaps             []*BlobArchiveGap `json:"gaps"`
+}
+
+type BlobArchiveGap struct {
+	Slot           string   `json:"slot"`
+	BlockRoot      string   `json:"block_root"`
+	MissingIndices []string `json:"missing_indices"`
+}
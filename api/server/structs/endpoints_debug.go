@@ -55,3 +55,61 @@ type ForkChoiceNodeExtraData struct {
 	ExecutionOptimistic      bool   `json:"execution_optimistic"`
 	TimeStamp                string `json:"timestamp"`
 }
+
+type GetOptimisticReportResponse struct {
+	IsOptimistic     bool               `json:"is_optimistic"`
+	HeadSlot         string             `json:"head_slot"`
+	OptimisticRanges []*OptimisticRange `json:"optimistic_ranges"`
+	InvalidPayloads  []*InvalidPayload  `json:"invalid_payloads"`
+	BlockedDuties    []*BlockedDuty     `json:"blocked_duties"`
+}
+
+type OptimisticRange struct {
+	StartSlot  string             `json:"start_slot"`
+	StartRoot  string             `json:"start_root"`
+	EndSlot    string             `json:"end_slot"`
+	EndRoot    string             `json:"end_root"`
+	BlockCount string             `json:"block_count"`
+	Canonical  bool               `json:"canonical"`
+	Blocks     []*OptimisticBlock `json:"blocks"`
+}
+
+type OptimisticBlock struct {
+	Slot               string `json:"slot"`
+	BlockRoot          string `json:"block_root"`
+	ParentRoot         string `json:"parent_root"`
+	ExecutionBlockHash string `json:"execution_block_hash"`
+	ElStatus           string `json:"el_status"`
+	ElStatusMethod     string `json:"el_status_method"`
+	ElStatusTimestamp  string `json:"el_status_timestamp"`
+}
+
+type InvalidPayload struct {
+	BlockRoot       string   `json:"block_root"`
+	LatestValidHash string   `json:"latest_valid_hash"`
+	InvalidRoots    []string `json:"invalid_roots"`
+	Method          string   `json:"method"`
+	Timestamp       string   `json:"timestamp"`
+}
+
+type BlockedDuty struct {
+	Duty          string `json:"duty"`
+	Count         string `json:"count"`
+	LastTimestamp string `json:"last_timestamp"`
+}
+
+type RevalidateOptimisticPayloadsRequest struct {
+	StartSlot string `json:"start_slot"`
+	EndSlot   string `json:"end_slot"`
+}
+
+type RevalidateOptimisticPayloadsResponse struct {
+	Data []*PayloadRevalidation `json:"data"`
+}
+
+type PayloadRevalidation struct {
+	Slot      string `json:"slot"`
+	BlockRoot string `json:"block_root"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
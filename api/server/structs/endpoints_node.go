@@ -71,3 +71,46 @@ type AddrRequest struct {
 type PeersResponse struct {
 	Peers []*Peer `json:"peers"`
 }
+
+type PeerBan struct {
+	Target string `json:"target"`
+	Reason string `json:"reason"`
+	Expiry string `json:"expiry"`
+}
+
+type ListPeerBansResponse struct {
+	Data []*PeerBan `json:"data"`
+}
+
+type BanPeerRequest struct {
+	Target   string `json:"target"`
+	Duration string `json:"duration"`
+	Reason   string `json:"reason"`
+}
+
+type BanPeerResponse struct {
+	Data              *PeerBan `json:"data"`
+	DisconnectedPeers []string `json:"disconnected_peers"`
+}
+
+type DisconnectPeerRequest struct {
+	ReasonCode string `json:"reason_code"`
+}
+
+type PeerScore struct {
+	PeerId          string `json:"peer_id"`
+	Score           string `json:"score"`
+	BadResponses    string `json:"bad_responses"`
+	ProcessedBlocks string `json:"processed_blocks"`
+	GossipScore     string `json:"gossip_score"`
+	IsBad           bool   `json:"is_bad"`
+}
+
+type GetPeerScoreResponse struct {
+	Data *PeerScore `json:"data"`
+}
+
+type SetPeerScoreRequest struct {
+	BadResponses    string `json:"bad_responses"`
+	ProcessedBlocks string `json:"processed_blocks"`
+}
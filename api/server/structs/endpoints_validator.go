@@ -150,3 +150,36 @@ type ActiveSetChanges struct {
 	EjectedPublicKeys   []string `json:"ejected_public_keys"`
 	EjectedIndices      []string `json:"ejected_indices"`
 }
+
+type GetProposerLookaheadResponse struct {
+	HeadRoot            string                    `json:"head_root"`
+	ExecutionOptimistic bool                      `json:"execution_optimistic"`
+	Data                []*ProposerLookaheadEpoch `json:"data"`
+}
+
+type ProposerLookaheadEpoch struct {
+	Epoch       string          `json:"epoch"`
+	Stability   string          `json:"stability"`
+	SeedFixed   bool            `json:"seed_fixed"`
+	Speculative bool            `json:"speculative"`
+	Duties      []*ProposerDuty `json:"duties"`
+}
+
+type LookupValidatorsResponse struct {
+	IndexedEpoch      string               `json:"indexed_epoch"`
+	HistoryStartEpoch string               `json:"history_start_epoch"`
+	Data              []*ValidatorIdentity `json:"data"`
+}
+
+type ValidatorIdentity struct {
+	Index                 string                         `json:"index"`
+	Pubkey                string                         `json:"pubkey"`
+	ActivationEpoch       string                         `json:"activation_epoch"`
+	ExitEpoch             string                         `json:"exit_epoch"`
+	WithdrawalCredentials []*WithdrawalCredentialsChange `json:"withdrawal_credentials"`
+}
+
+type WithdrawalCredentialsChange struct {
+	Epoch                 string `json:"epoch"`
+	WithdrawalCredentials string `json:"withdrawal_credentials"`
+}
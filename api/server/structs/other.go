@@ -123,8 +123,10 @@ type SignedValidatorRegistration struct {
 }
 
 type FeeRecipient struct {
-	ValidatorIndex string `json:"validator_index"`
-	FeeRecipient   string `json:"fee_recipient"`
+	ValidatorIndex string   `json:"validator_index"`
+	FeeRecipient   string   `json:"fee_recipient"`
+	Relays         []string `json:"relays,omitempty"`
+	MinBid         string   `json:"min_bid,omitempty"`
 }
 
 type SignedVoluntaryExit struct {
@@ -78,6 +78,7 @@ func (s *Service) notifyForkchoiceUpdate(ctx context.Context, arg *fcuConfig) (*
 		switch {
 		case errors.Is(err, execution.ErrAcceptedSyncingPayloadStatus):
 			forkchoiceUpdatedOptimisticNodeCount.Inc()
+			s.optimisticReport.recordStatus(bytesutil.ToBytes32(headPayload.BlockHash()), PayloadStatusSyncing, payloadMethodForkchoiceCall)
 			log.WithFields(logrus.Fields{
 				"headSlot":                  headBlk.Slot(),
 				"headPayloadBlockHash":      fmt.Sprintf("%#x", bytesutil.Trunc(headPayload.BlockHash())),
@@ -90,11 +91,18 @@ func (s *Service) notifyForkchoiceUpdate(ctx context.Context, arg *fcuConfig) (*
 			if len(lastValidHash) == 0 {
 				lastValidHash = defaultLatestValidHash
 			}
+			s.optimisticReport.recordStatus(bytesutil.ToBytes32(headPayload.BlockHash()), PayloadStatusInvalid, payloadMethodForkchoiceCall)
 			invalidRoots, err := s.cfg.ForkChoiceStore.SetOptimisticToInvalid(ctx, headRoot, headBlk.ParentRoot(), bytesutil.ToBytes32(lastValidHash))
 			if err != nil {
 				log.WithError(err).Error("Could not set head root to invalid")
 				return nil, nil
 			}
+			s.optimisticReport.recordInvalid(InvalidPayload{
+				BlockRoot:       headRoot,
+				LatestValidHash: bytesutil.ToBytes32(lastValidHash),
+				InvalidRoots:    invalidRoots,
+				Method:          payloadMethodForkchoiceCall,
+			})
 			if err := s.removeInvalidBlockAndState(ctx, invalidRoots); err != nil {
 				log.WithError(err).Error("Could not remove invalid block and state")
 				return nil, nil
@@ -146,6 +154,7 @@ func (s *Service) notifyForkchoiceUpdate(ctx context.Context, arg *fcuConfig) (*
 		}
 	}
 	forkchoiceUpdatedValidNodeCount.Inc()
+	s.optimisticReport.recordStatus(bytesutil.ToBytes32(headPayload.BlockHash()), PayloadStatusValid, payloadMethodForkchoiceCall)
 	if err := s.cfg.ForkChoiceStore.SetOptimisticToValid(ctx, arg.headRoot); err != nil {
 		log.WithError(err).Error("Could not set head root to valid")
 		return nil, nil
@@ -274,15 +283,18 @@ func (s *Service) notifyNewPayload(ctx context.Context, preStateVersion int,
 	switch {
 	case err == nil:
 		newPayloadValidNodeCount.Inc()
+		s.optimisticReport.recordStatus(bytesutil.ToBytes32(payload.BlockHash()), PayloadStatusValid, payloadMethodNewPayload)
 		return true, nil
 	case errors.Is(err, execution.ErrAcceptedSyncingPayloadStatus):
 		newPayloadOptimisticNodeCount.Inc()
+		s.optimisticReport.recordStatus(bytesutil.ToBytes32(payload.BlockHash()), PayloadStatusSyncing, payloadMethodNewPayload)
 		log.WithFields(logrus.Fields{
 			"slot":             blk.Block().Slot(),
 			"payloadBlockHash": fmt.Sprintf("%#x", bytesutil.Trunc(payload.BlockHash())),
 		}).Info("Called new payload with optimistic block")
 		return false, nil
 	case errors.Is(err, execution.ErrInvalidPayloadStatus):
+		s.optimisticReport.recordStatus(bytesutil.ToBytes32(payload.BlockHash()), PayloadStatusInvalid, payloadMethodNewPayload)
 		lvh := bytesutil.ToBytes32(lastValidHash)
 		return false, invalidBlock{
 			error:         ErrInvalidPayload,
@@ -300,6 +312,12 @@ func (s *Service) pruneInvalidBlock(ctx context.Context, root, parentRoot, lvh [
 	if err != nil {
 		return err
 	}
+	s.optimisticReport.recordInvalid(InvalidPayload{
+		BlockRoot:       root,
+		LatestValidHash: lvh,
+		InvalidRoots:    invalidRoots,
+		Method:          payloadMethodNewPayload,
+	})
 	if err := s.removeInvalidBlockAndState(ctx, invalidRoots); err != nil {
 		return err
 	}
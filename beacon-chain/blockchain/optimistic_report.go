@@ -0,0 +1,278 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+	doublylinkedtree "github.com/prysmaticlabs/prysm/v5/beacon-chain/forkchoice/doubly-linked-tree"
+	lruwrpr "github.com/prysmaticlabs/prysm/v5/cache/lru"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	"github.com/sirupsen/logrus"
+)
+
+// Execution client payload statuses recorded for optimistic sync reporting. The engine API client doesn't
+// distinguish SYNCING from ACCEPTED.
+const (
+	PayloadStatusValid          = "VALID"
+	PayloadStatusSyncing        = "SYNCING_OR_ACCEPTED"
+	PayloadStatusInvalid        = "INVALID"
+	PayloadStatusEngineError    = "ENGINE_ERROR"
+	payloadMethodNewPayload     = "engine_newPayload"
+	payloadMethodForkchoiceCall = "engine_forkchoiceUpdated"
+)
+
+// Validator duties that are refused while the node is optimistic, as recorded by OptimisticReporter.RecordBlockedDuty.
+const (
+	DutyAttestationData           = "attestation_data"
+	DutyAggregateAttestation      = "aggregate_attestation"
+	DutyBlockProposal             = "block_proposal"
+	DutySyncCommitteeMessage      = "sync_committee_message"
+	DutySyncCommitteeContribution = "sync_committee_contribution"
+)
+
+const (
+	// maxPayloadStatuses is the number of execution payload statuses kept for optimistic sync reporting.
+	maxPayloadStatuses = 4096
+	// maxInvalidPayloads is the number of INVALID execution payloads kept for optimistic sync reporting.
+	maxInvalidPayloads = 64
+	// MaxRevalidationSlots is the largest slot range whose optimistic payloads can be re-submitted at once.
+	MaxRevalidationSlots = 1024
+)
+
+var ErrRevalidationRange = errors.New("invalid payload revalidation range")
+
+// PayloadStatus is the last status returned by the execution client for an execution payload.
+type PayloadStatus struct {
+	Status string
+	Method string
+	Time   time.Time
+}
+
+// InvalidPayload is the execution payload of a block found INVALID by the execution client, with the latest
+// valid ancestor reported by the execution client and the blocks that were removed from fork choice as a result.
+type InvalidPayload struct {
+	BlockRoot       [32]byte
+	LatestValidHash [32]byte
+	InvalidRoots    [][32]byte
+	Method          string
+	Time            time.Time
+}
+
+// BlockedDuty counts the requests for a validator duty that were refused because the node was optimistic.
+type BlockedDuty struct {
+	Duty  string
+	Count uint64
+	Last  time.Time
+}
+
+// PayloadRevalidation is the result of re-submitting the execution payload of an optimistic block.
+type PayloadRevalidation struct {
+	Slot      primitives.Slot
+	BlockRoot [32]byte
+	Status    string
+	Err       error
+}
+
+// OptimisticReporter reports on the optimistically imported blocks and their effect on validators,
+// for debugging optimistic sync.
+type OptimisticReporter interface {
+	PayloadStatus(blockHash [32]byte) (PayloadStatus, bool)
+	InvalidPayloads() []InvalidPayload
+	BlockedDuties() []BlockedDuty
+	RecordBlockedDuty(duty string)
+	RevalidatePayloads(ctx context.Context, start, end primitives.Slot) ([]PayloadRevalidation, error)
+}
+
+// optimisticReport keeps the execution client responses and refused validator duties reported by the
+// OptimisticReporter. The zero value is ready to use.
+type optimisticReport struct {
+	sync.RWMutex
+	statuses *lru.Cache
+	invalid  []InvalidPayload
+	blocked  map[string]BlockedDuty
+}
+
+func (r *optimisticReport) recordStatus(blockHash [32]byte, status, method string) {
+	r.Lock()
+	defer r.Unlock()
+	if r.statuses == nil {
+		r.statuses = lruwrpr.New(maxPayloadStatuses)
+	}
+	r.statuses.Add(blockHash, PayloadStatus{Status: status, Method: method, Time: time.Now()})
+}
+
+func (r *optimisticReport) recordInvalid(p InvalidPayload) {
+	r.Lock()
+	defer r.Unlock()
+	p.Time = time.Now()
+	r.invalid = append(r.invalid, p)
+	if len(r.invalid) > maxInvalidPayloads {
+		r.invalid = r.invalid[len(r.invalid)-maxInvalidPayloads:]
+	}
+}
+
+// PayloadStatus returns the last status returned by the execution client for the payload with the given block hash.
+func (s *Service) PayloadStatus(blockHash [32]byte) (PayloadStatus, bool) {
+	s.optimisticReport.RLock()
+	defer s.optimisticReport.RUnlock()
+	if s.optimisticReport.statuses == nil {
+		return PayloadStatus{}, false
+	}
+	v, ok := s.optimisticReport.statuses.Peek(blockHash)
+	if !ok {
+		return PayloadStatus{}, false
+	}
+	st, ok := v.(PayloadStatus)
+	return st, ok
+}
+
+// InvalidPayloads returns the most recent execution payloads found INVALID by the execution client, oldest first.
+func (s *Service) InvalidPayloads() []InvalidPayload {
+	s.optimisticReport.RLock()
+	defer s.optimisticReport.RUnlock()
+	invalid := make([]InvalidPayload, len(s.optimisticReport.invalid))
+	copy(invalid, s.optimisticReport.invalid)
+	return invalid
+}
+
+// BlockedDuties returns the validator duties that were refused because the node was optimistic, sorted by name.
+func (s *Service) BlockedDuties() []BlockedDuty {
+	s.optimisticReport.RLock()
+	defer s.optimisticReport.RUnlock()
+	duties := make([]BlockedDuty, 0, len(s.optimisticReport.blocked))
+	for _, d := range s.optimisticReport.blocked {
+		duties = append(duties, d)
+	}
+	sort.Slice(duties, func(i, j int) bool {
+		return duties[i].Duty < duties[j].Duty
+	})
+	return duties
+}
+
+// RecordBlockedDuty records that a request for the given validator duty was refused because the node was optimistic.
+func (s *Service) RecordBlockedDuty(duty string) {
+	s.optimisticReport.Lock()
+	defer s.optimisticReport.Unlock()
+	if s.optimisticReport.blocked == nil {
+		s.optimisticReport.blocked = make(map[string]BlockedDuty)
+	}
+	d := s.optimisticReport.blocked[duty]
+	d.Duty = duty
+	d.Count++
+	d.Last = time.Now()
+	s.optimisticReport.blocked[duty] = d
+}
+
+// RevalidatePayloads re-submits the execution payloads of the optimistic blocks in fork choice with slots in
+// [start, end] to the execution client with engine_newPayload, in slot order, and updates fork choice with
+// the result. This forces the execution client to revalidate payloads it may have been stuck on.
+func (s *Service) RevalidatePayloads(ctx context.Context, start, end primitives.Slot) ([]PayloadRevalidation, error) {
+	if end < start || end-start >= MaxRevalidationSlots {
+		return nil, errors.Wrapf(ErrRevalidationRange, "start=%d, end=%d, max slots=%d", start, end, MaxRevalidationSlots)
+	}
+	dump, err := s.ForkChoiceDump(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not dump fork choice")
+	}
+	results := make([]PayloadRevalidation, 0)
+	for _, n := range dump.ForkChoiceNodes {
+		if n.ExecutionOptimistic && n.Slot >= start && n.Slot <= end {
+			results = append(results, PayloadRevalidation{Slot: n.Slot, BlockRoot: bytesutil.ToBytes32(n.BlockRoot)})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Slot < results[j].Slot
+	})
+	for i := range results {
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+		results[i].Status, results[i].Err = s.revalidatePayload(ctx, results[i].BlockRoot)
+	}
+	return results, nil
+}
+
+func (s *Service) revalidatePayload(ctx context.Context, root [32]byte) (string, error) {
+	// An earlier INVALID payload in the range may have removed the block from fork choice.
+	s.cfg.ForkChoiceStore.RLock()
+	optimistic, err := s.cfg.ForkChoiceStore.IsOptimistic(root)
+	s.cfg.ForkChoiceStore.RUnlock()
+	if errors.Is(err, doublylinkedtree.ErrNilNode) {
+		return PayloadStatusInvalid, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if !optimistic {
+		return PayloadStatusValid, nil
+	}
+	blk, err := s.getBlock(ctx, root)
+	if err != nil {
+		return "", err
+	}
+	preState, err := s.cfg.StateGen.StateByRoot(ctx, blk.Block().ParentRoot())
+	if err != nil {
+		return "", errors.Wrap(err, "could not get pre state")
+	}
+	preStateVersion, preStateHeader, err := getStateVersionAndPayload(preState)
+	if err != nil {
+		return "", err
+	}
+	valid, err := s.notifyNewPayload(ctx, preStateVersion, preStateHeader, blk)
+	if err != nil {
+		if !IsInvalidBlock(err) {
+			return PayloadStatusEngineError, err
+		}
+		s.cfg.ForkChoiceStore.Lock()
+		err = s.handleInvalidExecutionError(ctx, err, root, blk.Block().ParentRoot())
+		if IsInvalidBlock(err) {
+			s.updateHeadAfterPruning(ctx)
+		}
+		s.cfg.ForkChoiceStore.Unlock()
+		if IsInvalidBlock(err) {
+			return PayloadStatusInvalid, nil
+		}
+		return PayloadStatusInvalid, err
+	}
+	if !valid {
+		return PayloadStatusSyncing, nil
+	}
+	s.cfg.ForkChoiceStore.Lock()
+	err = s.cfg.ForkChoiceStore.SetOptimisticToValid(ctx, root)
+	s.cfg.ForkChoiceStore.Unlock()
+	if err != nil {
+		return PayloadStatusValid, errors.Wrap(err, "could not set optimistic block to valid")
+	}
+	log.WithFields(logrus.Fields{
+		"slot": blk.Block().Slot(),
+		"root": fmt.Sprintf("%#x", bytesutil.Trunc(root[:])),
+	}).Debug("Revalidated optimistic block")
+	return PayloadStatusValid, nil
+}
+
+// updateHeadAfterPruning recomputes the head after blocks with INVALID payloads were pruned, which may have
+// removed the current head, and notifies the engine of the new head. The caller must hold the fork choice lock.
+func (s *Service) updateHeadAfterPruning(ctx context.Context) {
+	r, err := s.cfg.ForkChoiceStore.Head(ctx)
+	if err != nil {
+		log.WithError(err).Error("Could not compute head after pruning invalid blocks")
+		return
+	}
+	if !s.isNewHead(r) {
+		return
+	}
+	st, b, err := s.getStateAndBlock(ctx, r)
+	if err != nil {
+		log.WithError(err).Error("Could not get head after pruning invalid blocks")
+		return
+	}
+	if err := s.forkchoiceUpdateWithExecution(ctx, &fcuConfig{headState: st, headBlock: b, headRoot: r}); err != nil {
+		log.WithError(err).Error("Could not update head after pruning invalid blocks")
+	}
+}
@@ -0,0 +1,165 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/execution"
+	mockExecution "github.com/prysmaticlabs/prysm/v5/beacon-chain/execution/testing"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/interfaces"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	v1 "github.com/prysmaticlabs/prysm/v5/proto/engine/v1"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+func TestOptimisticReport_RecordStatus(t *testing.T) {
+	s := &Service{}
+	r := &s.optimisticReport
+	for i := 0; i < maxPayloadStatuses+1; i++ {
+		r.recordStatus(bytesutil.ToBytes32(bytesutil.Uint64ToBytesBigEndian(uint64(i))), PayloadStatusSyncing, payloadMethodNewPayload)
+	}
+	require.Equal(t, maxPayloadStatuses, r.statuses.Len())
+	_, ok := s.PayloadStatus(bytesutil.ToBytes32(bytesutil.Uint64ToBytesBigEndian(0)))
+	require.Equal(t, false, ok)
+	_, ok = s.PayloadStatus(bytesutil.ToBytes32(bytesutil.Uint64ToBytesBigEndian(1)))
+	require.Equal(t, true, ok)
+	r.recordStatus([32]byte{'a'}, PayloadStatusSyncing, payloadMethodNewPayload)
+	r.recordStatus([32]byte{'a'}, PayloadStatusValid, payloadMethodForkchoiceCall)
+	require.Equal(t, maxPayloadStatuses, r.statuses.Len())
+	st, ok := s.PayloadStatus([32]byte{'a'})
+	require.Equal(t, true, ok)
+	require.Equal(t, PayloadStatusValid, st.Status)
+	require.Equal(t, payloadMethodForkchoiceCall, st.Method)
+}
+
+func TestOptimisticReport_InvalidPayloads(t *testing.T) {
+	s := &Service{}
+	for i := 0; i < maxInvalidPayloads+2; i++ {
+		s.optimisticReport.recordInvalid(InvalidPayload{BlockRoot: [32]byte{byte(i)}})
+	}
+	invalid := s.InvalidPayloads()
+	require.Equal(t, maxInvalidPayloads, len(invalid))
+	require.Equal(t, [32]byte{2}, invalid[0].BlockRoot)
+	require.Equal(t, [32]byte{maxInvalidPayloads + 1}, invalid[maxInvalidPayloads-1].BlockRoot)
+}
+
+func TestOptimisticReport_BlockedDuties(t *testing.T) {
+	s := &Service{}
+	require.Equal(t, 0, len(s.BlockedDuties()))
+	s.RecordBlockedDuty(DutySyncCommitteeMessage)
+	s.RecordBlockedDuty(DutyBlockProposal)
+	s.RecordBlockedDuty(DutySyncCommitteeMessage)
+	duties := s.BlockedDuties()
+	require.Equal(t, 2, len(duties))
+	require.Equal(t, DutyBlockProposal, duties[0].Duty)
+	require.Equal(t, uint64(1), duties[0].Count)
+	require.Equal(t, DutySyncCommitteeMessage, duties[1].Duty)
+	require.Equal(t, uint64(2), duties[1].Count)
+}
+
+func TestRevalidatePayloads(t *testing.T) {
+	service, tr := minimalTestService(t)
+	ctx, fcs := tr.ctx, tr.fcs
+
+	saveBlock := func(slot primitives.Slot, parentRoot [32]byte, parentHash, blockHash byte) (interfaces.ReadOnlySignedBeaconBlock, [32]byte) {
+		blk := util.NewBeaconBlockBellatrix()
+		blk.Block.Slot = slot
+		blk.Block.ParentRoot = parentRoot[:]
+		blk.Block.Body.ExecutionPayload = &v1.ExecutionPayload{
+			ParentHash:    bytesutil.PadTo([]byte{parentHash}, fieldparams.RootLength),
+			FeeRecipient:  make([]byte, fieldparams.FeeRecipientLength),
+			StateRoot:     make([]byte, fieldparams.RootLength),
+			ReceiptsRoot:  make([]byte, fieldparams.RootLength),
+			LogsBloom:     make([]byte, fieldparams.LogsBloomLength),
+			PrevRandao:    make([]byte, fieldparams.RootLength),
+			BaseFeePerGas: make([]byte, fieldparams.RootLength),
+			BlockHash:     bytesutil.PadTo([]byte{blockHash}, fieldparams.RootLength),
+		}
+		signed := util.SaveBlock(t, ctx, tr.db, blk)
+		root, err := signed.Block().HashTreeRoot()
+		require.NoError(t, err)
+		return signed, root
+	}
+	_, genesisRoot := saveBlock(0, [32]byte{}, 0, 'a')
+	signed, root := saveBlock(1, genesisRoot, 'a', 'b')
+
+	ojc := &ethpb.Checkpoint{Root: params.BeaconConfig().ZeroHash[:]}
+	ofc := &ethpb.Checkpoint{Root: params.BeaconConfig().ZeroHash[:]}
+	st, roblock, err := prepareForkchoiceState(ctx, 0, genesisRoot, [32]byte{}, [32]byte{'a'}, ojc, ofc)
+	require.NoError(t, err)
+	require.NoError(t, fcs.InsertNode(ctx, st, roblock))
+	require.NoError(t, fcs.SetOptimisticToValid(ctx, genesisRoot))
+	st, roblock, err = prepareForkchoiceState(ctx, 1, root, genesisRoot, [32]byte{'b'}, ojc, ofc)
+	require.NoError(t, err)
+	require.NoError(t, fcs.InsertNode(ctx, st, roblock))
+	headState, err := util.NewBeaconStateBellatrix()
+	require.NoError(t, err)
+	require.NoError(t, headState.SetSlot(1))
+	require.NoError(t, tr.db.SaveState(ctx, headState, root))
+	// The payloads are revalidated against the version and payload header of the pre-state.
+	genesisState, err := util.NewBeaconStateBellatrix()
+	require.NoError(t, err)
+	require.NoError(t, tr.db.SaveState(ctx, genesisState, genesisRoot))
+	require.NoError(t, tr.db.SaveStateSummary(ctx, &ethpb.StateSummary{Slot: 0, Root: genesisRoot[:]}))
+	require.NoError(t, tr.db.SaveStateSummary(ctx, &ethpb.StateSummary{Slot: 1, Root: root[:]}))
+
+	_, err = service.RevalidatePayloads(ctx, 2, 1)
+	require.ErrorIs(t, err, ErrRevalidationRange)
+	_, err = service.RevalidatePayloads(ctx, 0, MaxRevalidationSlots)
+	require.ErrorIs(t, err, ErrRevalidationRange)
+
+	service.cfg.ExecutionEngineCaller = &mockExecution.EngineClient{ErrNewPayload: execution.ErrAcceptedSyncingPayloadStatus}
+	results, err := service.RevalidatePayloads(ctx, 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(results))
+	require.Equal(t, primitives.Slot(1), results[0].Slot)
+	require.Equal(t, root, results[0].BlockRoot)
+	require.Equal(t, PayloadStatusSyncing, results[0].Status)
+	require.NoError(t, results[0].Err)
+	optimistic, err := service.IsOptimisticForRoot(ctx, root)
+	require.NoError(t, err)
+	require.Equal(t, true, optimistic)
+
+	service.cfg.ExecutionEngineCaller = &mockExecution.EngineClient{}
+	results, err = service.RevalidatePayloads(ctx, 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(results))
+	require.Equal(t, PayloadStatusValid, results[0].Status)
+	optimistic, err = service.IsOptimisticForRoot(ctx, root)
+	require.NoError(t, err)
+	require.Equal(t, false, optimistic)
+	status, ok := service.PayloadStatus([32]byte{'b'})
+	require.Equal(t, true, ok)
+	require.Equal(t, PayloadStatusValid, status.Status)
+
+	results, err = service.RevalidatePayloads(ctx, 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(results))
+
+	// An INVALID payload prunes the block, and the head moves away from it.
+	child, childRoot := saveBlock(2, root, 'b', 'c')
+	st, roblock, err = prepareForkchoiceState(ctx, 2, childRoot, root, [32]byte{'c'}, ojc, ofc)
+	require.NoError(t, err)
+	require.NoError(t, fcs.InsertNode(ctx, st, roblock))
+	childState := headState.Copy()
+	require.NoError(t, childState.SetSlot(2))
+	service.head = &head{root: childRoot, block: child, state: childState, slot: 2, optimistic: true}
+	service.cfg.ExecutionEngineCaller = &mockExecution.EngineClient{
+		ErrNewPayload:  execution.ErrInvalidPayloadStatus,
+		NewPayloadResp: bytesutil.PadTo([]byte{'b'}, fieldparams.RootLength),
+	}
+	results, err = service.RevalidatePayloads(ctx, 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(results))
+	require.Equal(t, childRoot, results[0].BlockRoot)
+	require.Equal(t, PayloadStatusInvalid, results[0].Status)
+	require.Equal(t, false, tr.db.HasBlock(ctx, childRoot))
+	headRoot, err := service.HeadRoot(ctx)
+	require.NoError(t, err)
+	require.DeepEqual(t, root[:], headRoot)
+	require.Equal(t, signed.Block().Slot(), service.HeadSlot())
+}
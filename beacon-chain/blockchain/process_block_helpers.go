@@ -16,6 +16,7 @@ import (
 	doublylinkedtree "github.com/prysmaticlabs/prysm/v5/beacon-chain/forkchoice/doubly-linked-tree"
 	forkchoicetypes "github.com/prysmaticlabs/prysm/v5/beacon-chain/forkchoice/types"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/config/features"
 	field_params "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
 	"github.com/prysmaticlabs/prysm/v5/config/params"
 	consensus_blocks "github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
@@ -464,9 +465,53 @@ func (s *Service) updateFinalized(ctx context.Context, cp *ethpb.Checkpoint) err
 			log.WithError(err).Error("could not migrate to cold")
 		}
 	}()
+	if features.Get().EnableValidatorIdentityIndex {
+		s.queueValidatorIdentityUpdate(fRoot)
+	}
 	return nil
 }
 
+// queueValidatorIdentityUpdate queues an update of the validator identity index to the given finalized root. A
+// queued update that hasn't started yet is replaced, since the index only needs the latest finalized state.
+func (s *Service) queueValidatorIdentityUpdate(fRoot [32]byte) {
+	select {
+	case <-s.identityIndexQueue:
+	default:
+	}
+	select {
+	case s.identityIndexQueue <- fRoot:
+	default:
+	}
+}
+
+// runValidatorIdentityIndexer updates the validator identity index to the queued finalized roots one at a time,
+// so that the updates are applied in finalization order.
+func (s *Service) runValidatorIdentityIndexer() {
+	for {
+		select {
+		case fRoot := <-s.identityIndexQueue:
+			if err := s.updateValidatorIdentityIndex(s.ctx, fRoot); err != nil {
+				log.WithError(err).Error("Could not update validator identity index")
+			}
+		case <-s.ctx.Done():
+			log.Debug("Context closed, exiting validator identity indexer")
+			return
+		}
+	}
+}
+
+// updateValidatorIdentityIndex saves the validators of the finalized state to the validator identity index.
+func (s *Service) updateValidatorIdentityIndex(ctx context.Context, fRoot [32]byte) error {
+	ctx, span := trace.StartSpan(ctx, "blockChain.updateValidatorIdentityIndex")
+	defer span.End()
+
+	st, err := s.cfg.StateGen.StateByRoot(ctx, fRoot)
+	if err != nil {
+		return errors.Wrap(err, "could not get finalized state")
+	}
+	return s.cfg.BeaconDB.SaveValidatorIdentities(ctx, st)
+}
+
 // This retrieves an ancestor root using DB. The look up is recursively looking up DB. Slower than `ancestorByForkChoiceStore`.
 func (s *Service) ancestorByDB(ctx context.Context, r [32]byte, slot primitives.Slot) (root [32]byte, err error) {
 	ctx, span := trace.StartSpan(ctx, "blockChain.ancestorByDB")
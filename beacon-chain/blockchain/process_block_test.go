@@ -2255,7 +2255,7 @@ func TestMissingIndices(t *testing.T) {
 	for _, c := range cases {
 		bm, bs := filesystem.NewEphemeralBlobStorageWithMocker(t)
 		t.Run(c.name, func(t *testing.T) {
-			require.NoError(t, bm.CreateFakeIndices(c.root, c.present...))
+			require.NoError(t, bm.CreateFakeIndices(c.root, 0, c.present...))
 			missing, err := missingIndices(bs, c.root, c.expected, 0)
 			if c.err != nil {
 				require.ErrorIs(t, err, c.err)
@@ -3106,3 +3106,36 @@ func TestSaveLightClientBootstrap(t *testing.T) {
 
 	reset()
 }
+
+func TestValidatorIdentityIndexer(t *testing.T) {
+	resetCfg := features.InitWithReset(&features.Flags{EnableValidatorIdentityIndex: true})
+	defer resetCfg()
+	service, tr := minimalTestService(t)
+	ctx := tr.ctx
+
+	st, _ := util.DeterministicGenesisState(t, 4)
+	require.NoError(t, st.SetSlot(params.BeaconConfig().SlotsPerEpoch))
+	blk := util.SaveBlock(t, ctx, tr.db, util.NewBeaconBlock())
+	root, err := blk.Block().HashTreeRoot()
+	require.NoError(t, err)
+	require.NoError(t, tr.db.SaveState(ctx, st, root))
+
+	// A queued update that hasn't started yet is replaced by the next one.
+	service.queueValidatorIdentityUpdate([32]byte{'a'})
+	service.queueValidatorIdentityUpdate(root)
+	require.Equal(t, 1, len(service.identityIndexQueue))
+
+	go service.runValidatorIdentityIndexer()
+	for i := 0; ; i++ {
+		epoch, err := tr.db.ValidatorIdentityIndexEpoch(ctx)
+		if err == nil {
+			require.Equal(t, primitives.Epoch(1), epoch)
+			break
+		}
+		require.Equal(t, true, i < 100, "validator identity index was not updated")
+		time.Sleep(10 * time.Millisecond)
+	}
+	id, err := tr.db.ValidatorIdentity(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), id.Index)
+}
@@ -65,6 +65,8 @@ type Service struct {
 	blobNotifiers        *blobNotifierMap
 	blockBeingSynced     *currentlySyncingBlock
 	blobStorage          *filesystem.BlobStorage
+	optimisticReport     optimisticReport
+	identityIndexQueue   chan [32]byte // latest finalized root the validator identity index isn't updated to yet
 }
 
 // config options for the service.
@@ -183,6 +185,7 @@ func NewService(ctx context.Context, opts ...Option) (*Service, error) {
 		blobNotifiers:        bn,
 		cfg:                  &config{},
 		blockBeingSynced:     &currentlySyncingBlock{roots: make(map[[32]byte]struct{})},
+		identityIndexQueue:   make(chan [32]byte, 1),
 	}
 	for _, opt := range opts {
 		if err := opt(srv); err != nil {
@@ -215,6 +218,9 @@ func (s *Service) Start() {
 	}
 	s.spawnProcessAttestationsRoutine()
 	go s.runLateBlockTasks()
+	if features.Get().EnableValidatorIdentityIndex {
+		go s.runValidatorIdentityIndexer()
+	}
 }
 
 // Stop the blockchain service's main event loop and associated goroutines.
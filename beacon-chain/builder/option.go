@@ -24,6 +24,7 @@ func FlagOptions(c *cli.Context) ([]Option, error) {
 	}
 	opts := []Option{
 		WithBuilderClient(client),
+		WithRelayAllowList(c.StringSlice(flags.BuilderRelayAllowList.Name)),
 	}
 	return opts, nil
 }
@@ -36,6 +37,14 @@ func WithBuilderClient(client builder.BuilderClient) Option {
 	}
 }
 
+// WithRelayAllowList sets the relays that validators may restrict their bids to.
+func WithRelayAllowList(urls []string) Option {
+	return func(s *Service) error {
+		s.relays.allow(urls)
+		return nil
+	}
+}
+
 // WithHeadFetcher gets the head info from chain service.
 func WithHeadFetcher(svc blockchain.HeadFetcher) Option {
 	return func(s *Service) error {
@@ -52,6 +61,14 @@ func WithDatabase(beaconDB db.HeadAccessDatabase) Option {
 	}
 }
 
+// WithTrackedValidatorsCache sets the cache holding the builder relays preferred by tracked validators.
+func WithTrackedValidatorsCache(c *cache.TrackedValidatorsCache) Option {
+	return func(s *Service) error {
+		s.cfg.trackedValidatorsCache = c
+		return nil
+	}
+}
+
 // WithRegistrationCache uses a cache for the validator registrations instead of a persistent db.
 func WithRegistrationCache() Option {
 	return func(s *Service) error {
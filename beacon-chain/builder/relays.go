@@ -0,0 +1,253 @@
+package builder
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api/client/builder"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	log "github.com/sirupsen/logrus"
+)
+
+// relayRegistrationTimeout bounds the registration of validators with their preferred relays.
+const relayRegistrationTimeout = 5 * time.Second
+
+// relays holds the clients of the relays preferred by tracked validators, and the relay that
+// provided the header of each pending proposal so that the blinded block is submitted to it.
+// Clients are only created for the relays of the operator allow-list, which bounds their number.
+type relays struct {
+	sync.Mutex
+	newClient func(string) (builder.BuilderClient, error)
+	allowed   map[string]bool
+	clients   map[string]builder.BuilderClient
+	bySlot    map[primitives.Slot]builder.BuilderClient
+}
+
+func newRelays() *relays {
+	return &relays{
+		newClient: func(url string) (builder.BuilderClient, error) {
+			return builder.NewClient(url)
+		},
+		allowed: make(map[string]bool),
+		clients: make(map[string]builder.BuilderClient),
+		bySlot:  make(map[primitives.Slot]builder.BuilderClient),
+	}
+}
+
+// allow sets the relays that clients may be created for.
+func (r *relays) allow(urls []string) {
+	r.Lock()
+	defer r.Unlock()
+	r.allowed = make(map[string]bool, len(urls))
+	for _, url := range urls {
+		r.allowed[url] = true
+	}
+}
+
+// isAllowed returns true if the relay at the given URL is in the allow-list.
+func (r *relays) isAllowed(url string) bool {
+	r.Lock()
+	defer r.Unlock()
+	return r.allowed[url]
+}
+
+// client returns the client of the relay at the given URL, creating it on first use. Only relays
+// of the allow-list are dialed.
+func (r *relays) client(url string) (builder.BuilderClient, error) {
+	r.Lock()
+	defer r.Unlock()
+	if c, ok := r.clients[url]; ok {
+		return c, nil
+	}
+	if !r.allowed[url] {
+		return nil, errors.Errorf("relay %s is not in the relay allow-list", url)
+	}
+	c, err := r.newClient(url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not create client for relay %s", url)
+	}
+	r.clients[url] = c
+	return c, nil
+}
+
+// setForSlot records the relay that provided the header for the slot, pruning the records of
+// slots older than an epoch.
+func (r *relays) setForSlot(slot primitives.Slot, c builder.BuilderClient) {
+	r.Lock()
+	defer r.Unlock()
+	for s := range r.bySlot {
+		if s+params.BeaconConfig().SlotsPerEpoch < slot {
+			delete(r.bySlot, s)
+		}
+	}
+	if c == nil {
+		delete(r.bySlot, slot)
+		return
+	}
+	r.bySlot[slot] = c
+}
+
+// forSlot returns the relay that provided the header for the slot, if any.
+func (r *relays) forSlot(slot primitives.Slot) (builder.BuilderClient, bool) {
+	r.Lock()
+	defer r.Unlock()
+	c, ok := r.bySlot[slot]
+	return c, ok
+}
+
+// RelayAllowed returns true if validators may restrict their bids to the relay at the given URL,
+// that is if the operator allowed it with --builder-relay-allowlist.
+func (s *Service) RelayAllowed(url string) bool {
+	return s.relays.isAllowed(url)
+}
+
+// AllowedRelays returns the relays of the validator that the block builder allows validators to
+// restrict their bids to. Other relays are dropped with a warning rather than failing the request,
+// so that the rest of the validator's proposer preparation, such as its fee recipient, still applies.
+// Every relay is dropped when no block builder is configured.
+func AllowedRelays(b BlockBuilder, idx primitives.ValidatorIndex, urls []string) []string {
+	if len(urls) == 0 {
+		return urls
+	}
+	allowed := make([]string, 0, len(urls))
+	for _, url := range urls {
+		if b == nil || !b.RelayAllowed(url) {
+			log.WithFields(log.Fields{
+				"validatorIndex": idx,
+				"relay":          url,
+			}).Warn("Ignoring relay preference of validator, relay is not in the relay allow-list")
+			continue
+		}
+		allowed = append(allowed, url)
+	}
+	return allowed
+}
+
+// preferredRelays returns the relays the validator restricted its bids to, or nil if it did not.
+func (s *Service) preferredRelays(idx primitives.ValidatorIndex) []string {
+	if s.cfg.trackedValidatorsCache == nil {
+		return nil
+	}
+	val, ok := s.cfg.trackedValidatorsCache.Validator(idx)
+	if !ok {
+		return nil
+	}
+	return val.Relays
+}
+
+// proposerRelays returns the relays the proposer with the given public key restricted its bids to, or nil if it did not.
+func (s *Service) proposerRelays(pubKey [48]byte) []string {
+	if s.cfg.headFetcher == nil {
+		return nil
+	}
+	idx, ok := s.cfg.headFetcher.HeadPublicKeyToValidatorIndex(pubKey)
+	if !ok {
+		return nil
+	}
+	return s.preferredRelays(idx)
+}
+
+// getHeaderFromRelays solicits a bid from every given relay concurrently and returns the highest
+// one along with the client of the relay that provided it.
+func (s *Service) getHeaderFromRelays(
+	ctx context.Context, urls []string, slot primitives.Slot, parentHash [32]byte, pubKey [48]byte,
+) (builder.SignedBid, builder.BuilderClient, error) {
+	type result struct {
+		url   string
+		c     builder.BuilderClient
+		bid   builder.SignedBid
+		value *big.Int
+	}
+	results := make(chan *result, len(urls))
+	var wg sync.WaitGroup
+	for _, url := range urls {
+		wg.Add(1)
+		go func(url string) {
+			defer wg.Done()
+			c, err := s.relays.client(url)
+			if err != nil {
+				log.WithError(err).Warn("Could not get header from relay")
+				return
+			}
+			bid, err := c.GetHeader(ctx, slot, parentHash, pubKey)
+			if err != nil {
+				log.WithError(err).WithField("relay", url).Warn("Could not get header from relay")
+				return
+			}
+			if bid == nil || bid.IsNil() {
+				return
+			}
+			m, err := bid.Message()
+			if err != nil {
+				log.WithError(err).WithField("relay", url).Warn("Could not get bid message from relay")
+				return
+			}
+			results <- &result{url: url, c: c, bid: bid, value: primitives.WeiToBigInt(m.Value())}
+		}(url)
+	}
+	wg.Wait()
+	close(results)
+
+	var best *result
+	for r := range results {
+		if best == nil || r.value.Cmp(best.value) > 0 {
+			best = r
+		}
+	}
+	if best == nil {
+		return nil, nil, errors.New("no bid from the proposer's relays")
+	}
+	log.WithFields(log.Fields{
+		"relay": best.url,
+		"slot":  slot,
+		"value": best.value,
+	}).Debug("Selected bid from the proposer's relays")
+	return best.bid, best.c, nil
+}
+
+// registerWithRelays forwards the registrations of the validators that restricted their bids to
+// specific relays to those relays, concurrently and within relayRegistrationTimeout so that a slow
+// relay does not hold up the registration. Failures are logged, as the registrations were already
+// sent to the configured builder.
+func (s *Service) registerWithRelays(ctx context.Context, regs map[primitives.ValidatorIndex]*ethpb.SignedValidatorRegistrationV1) {
+	byRelay := make(map[string][]*ethpb.SignedValidatorRegistrationV1)
+	for idx, r := range regs {
+		for _, url := range s.preferredRelays(idx) {
+			byRelay[url] = append(byRelay[url], r)
+		}
+	}
+	if len(byRelay) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, relayRegistrationTimeout)
+	defer cancel()
+	var wg sync.WaitGroup
+	for url, rs := range byRelay {
+		wg.Add(1)
+		go func(url string, rs []*ethpb.SignedValidatorRegistrationV1) {
+			defer wg.Done()
+			c, err := s.relays.client(url)
+			if err != nil {
+				log.WithError(err).Warn("Could not register validators with relay")
+				return
+			}
+			if err := c.RegisterValidator(ctx, rs); err != nil {
+				log.WithError(err).WithFields(log.Fields{
+					"relay":          url,
+					"validatorCount": len(rs),
+				}).Warn("Could not register validators with relay")
+				return
+			}
+			log.WithFields(log.Fields{
+				"relay":          url,
+				"validatorCount": len(rs),
+			}).Debug("Registered validators with relay")
+		}(url, rs)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,150 @@
+package builder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api/client/builder"
+	buildertesting "github.com/prysmaticlabs/prysm/v5/api/client/builder/testing"
+	blockchainTesting "github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/cache"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/interfaces"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	v1 "github.com/prysmaticlabs/prysm/v5/proto/engine/v1"
+	eth "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+type mockRelay struct {
+	value      uint64
+	err        error
+	registered int
+	submitted  int
+	// deadline records whether the last registration was bounded by a deadline.
+	deadline bool
+}
+
+func (*mockRelay) NodeURL() string {
+	return ""
+}
+
+func (r *mockRelay) GetHeader(_ context.Context, _ primitives.Slot, _ [32]byte, _ [48]byte) (builder.SignedBid, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return builder.WrappedSignedBuilderBidCapella(&eth.SignedBuilderBidCapella{Message: &eth.BuilderBidCapella{
+		Value: bytesutil.PadTo(bytesutil.Uint64ToBytesLittleEndian(r.value), 32),
+	}})
+}
+
+func (r *mockRelay) RegisterValidator(ctx context.Context, regs []*eth.SignedValidatorRegistrationV1) error {
+	_, r.deadline = ctx.Deadline()
+	r.registered += len(regs)
+	return r.err
+}
+
+func (r *mockRelay) SubmitBlindedBlock(_ context.Context, _ interfaces.ReadOnlySignedBeaconBlock) (interfaces.ExecutionData, *v1.BlobsBundle, error) {
+	r.submitted++
+	return nil, nil, nil
+}
+
+func (*mockRelay) Status(_ context.Context) error {
+	return nil
+}
+
+func setupRelays(t *testing.T, relayURLs []string) (*Service, *buildertesting.MockClient, map[string]*mockRelay) {
+	tracked := cache.NewTrackedValidatorsCache()
+	tracked.Set(cache.TrackedValidator{Index: 0, Relays: relayURLs})
+	c := buildertesting.NewClient()
+	s, err := NewService(context.Background(),
+		WithRegistrationCache(),
+		WithHeadFetcher(&blockchainTesting.ChainService{}),
+		WithBuilderClient(&c),
+		WithTrackedValidatorsCache(tracked),
+		WithRelayAllowList([]string{"https://low.example.com", "https://high.example.com", "https://broken.example.com", "https://unknown.example.com"}),
+	)
+	require.NoError(t, err)
+	mocks := map[string]*mockRelay{
+		"https://low.example.com":    {value: 1},
+		"https://high.example.com":   {value: 5},
+		"https://broken.example.com": {err: errors.New("unavailable")},
+	}
+	s.relays.newClient = func(url string) (builder.BuilderClient, error) {
+		r, ok := mocks[url]
+		if !ok {
+			return nil, errors.New("unknown relay")
+		}
+		return r, nil
+	}
+	return s, &c, mocks
+}
+
+func TestService_GetHeader_ProposerRelays(t *testing.T) {
+	ctx := context.Background()
+	s, _, mocks := setupRelays(t, []string{"https://low.example.com", "https://high.example.com", "https://broken.example.com"})
+
+	bid, err := s.GetHeader(ctx, 1, [32]byte{}, [48]byte{})
+	require.NoError(t, err)
+	m, err := bid.Message()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), primitives.WeiToBigInt(m.Value()).Uint64())
+
+	// The blinded block of the slot is submitted to the relay that provided the highest bid.
+	blk, err := blocks.NewSignedBeaconBlock(util.NewBlindedBeaconBlockCapella())
+	require.NoError(t, err)
+	blk.SetSlot(1)
+	_, _, err = s.SubmitBlindedBlock(ctx, blk)
+	require.NoError(t, err)
+	assert.Equal(t, 1, mocks["https://high.example.com"].submitted)
+	assert.Equal(t, 0, mocks["https://low.example.com"].submitted)
+
+	// Other slots use the configured builder.
+	blk.SetSlot(2)
+	_, _, err = s.SubmitBlindedBlock(ctx, blk)
+	require.NoError(t, err)
+	assert.Equal(t, 1, mocks["https://high.example.com"].submitted)
+}
+
+func TestService_GetHeader_ProposerRelaysFail(t *testing.T) {
+	s, _, _ := setupRelays(t, []string{"https://broken.example.com", "https://unknown.example.com"})
+	_, err := s.GetHeader(context.Background(), 1, [32]byte{}, [48]byte{})
+	require.ErrorContains(t, "no bid from the proposer's relays", err)
+}
+
+func TestService_RegisterValidator_ProposerRelays(t *testing.T) {
+	s, c, mocks := setupRelays(t, []string{"https://low.example.com", "https://broken.example.com"})
+	pubkey := bytesutil.ToBytes48([]byte("pubkey"))
+	var feeRecipient [20]byte
+	reg := &eth.SignedValidatorRegistrationV1{Message: &eth.ValidatorRegistrationV1{Pubkey: pubkey[:], FeeRecipient: feeRecipient[:]}}
+	require.NoError(t, s.RegisterValidator(context.Background(), []*eth.SignedValidatorRegistrationV1{reg}))
+	assert.Equal(t, true, c.RegisteredVals[pubkey])
+	assert.Equal(t, 1, mocks["https://low.example.com"].registered)
+	assert.Equal(t, 1, mocks["https://broken.example.com"].registered)
+	assert.Equal(t, 0, mocks["https://high.example.com"].registered)
+	// Registering with the relays is bounded even if the caller's context is not.
+	assert.Equal(t, true, mocks["https://low.example.com"].deadline)
+}
+
+func TestService_RelayAllowList(t *testing.T) {
+	s, _, _ := setupRelays(t, []string{"https://low.example.com", "http://169.254.169.254"})
+	assert.Equal(t, true, s.RelayAllowed("https://low.example.com"))
+	assert.Equal(t, false, s.RelayAllowed("http://169.254.169.254"))
+	assert.DeepEqual(t, []string{"https://low.example.com"}, AllowedRelays(s, 0, []string{"https://low.example.com", "http://169.254.169.254"}))
+	// Without a block builder, relay preferences are dropped.
+	assert.DeepEqual(t, []string{}, AllowedRelays(nil, 0, []string{"https://low.example.com"}))
+
+	// Relays outside of the allow-list are never dialed, and no client is kept for them.
+	_, err := s.relays.client("http://169.254.169.254")
+	require.ErrorContains(t, "not in the relay allow-list", err)
+	bid, err := s.GetHeader(context.Background(), 1, [32]byte{}, [48]byte{})
+	require.NoError(t, err)
+	m, err := bid.Message()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), primitives.WeiToBigInt(m.Value()).Uint64())
+	assert.Equal(t, 1, len(s.relays.clients))
+}
@@ -30,13 +30,15 @@ type BlockBuilder interface {
 	RegisterValidator(ctx context.Context, reg []*ethpb.SignedValidatorRegistrationV1) error
 	RegistrationByValidatorID(ctx context.Context, id primitives.ValidatorIndex) (*ethpb.ValidatorRegistrationV1, error)
 	Configured() bool
+	RelayAllowed(url string) bool
 }
 
 // config defines a config struct for dependencies into the service.
 type config struct {
-	builderClient builder.BuilderClient
-	beaconDB      db.HeadAccessDatabase
-	headFetcher   blockchain.HeadFetcher
+	builderClient          builder.BuilderClient
+	beaconDB               db.HeadAccessDatabase
+	headFetcher            blockchain.HeadFetcher
+	trackedValidatorsCache *cache.TrackedValidatorsCache
 }
 
 // Service defines a service that provides a client for interacting with the beacon chain and MEV relay network.
@@ -46,6 +48,7 @@ type Service struct {
 	ctx               context.Context
 	cancel            context.CancelFunc
 	registrationCache *cache.RegistrationCache
+	relays            *relays
 }
 
 // NewService instantiates a new service.
@@ -55,6 +58,7 @@ func NewService(ctx context.Context, opts ...Option) (*Service, error) {
 		ctx:    ctx,
 		cancel: cancel,
 		cfg:    &config{},
+		relays: newRelays(),
 	}
 	for _, opt := range opts {
 		if err := opt(s); err != nil {
@@ -87,7 +91,8 @@ func (s *Service) Stop() error {
 	return nil
 }
 
-// SubmitBlindedBlock submits a blinded block to the builder relay network.
+// SubmitBlindedBlock submits a blinded block to the builder relay network. The block is submitted
+// to the relay that provided its header when the proposer restricted its bids to specific relays.
 func (s *Service) SubmitBlindedBlock(ctx context.Context, b interfaces.ReadOnlySignedBeaconBlock) (interfaces.ExecutionData, *v1.BlobsBundle, error) {
 	ctx, span := trace.StartSpan(ctx, "builder.SubmitBlindedBlock")
 	defer span.End()
@@ -99,10 +104,16 @@ func (s *Service) SubmitBlindedBlock(ctx context.Context, b interfaces.ReadOnlyS
 		return nil, nil, ErrNoBuilder
 	}
 
+	if b != nil && !b.IsNil() {
+		if c, ok := s.relays.forSlot(b.Block().Slot()); ok {
+			return c.SubmitBlindedBlock(ctx, b)
+		}
+	}
 	return s.c.SubmitBlindedBlock(ctx, b)
 }
 
 // GetHeader retrieves the header for a given slot and parent hash from the builder relay network.
+// When the proposer restricted its bids to specific relays, the highest bid of those relays is returned.
 func (s *Service) GetHeader(ctx context.Context, slot primitives.Slot, parentHash [32]byte, pubKey [48]byte) (builder.SignedBid, error) {
 	ctx, span := trace.StartSpan(ctx, "builder.GetHeader")
 	defer span.End()
@@ -115,6 +126,14 @@ func (s *Service) GetHeader(ctx context.Context, slot primitives.Slot, parentHas
 		return nil, ErrNoBuilder
 	}
 
+	if urls := s.proposerRelays(pubKey); len(urls) != 0 {
+		h, c, err := s.getHeaderFromRelays(ctx, urls, slot, parentHash, pubKey)
+		tracing.AnnotateError(span, err)
+		s.relays.setForSlot(slot, c)
+		return h, err
+	}
+	s.relays.setForSlot(slot, nil)
+
 	h, err := s.c.GetHeader(ctx, slot, parentHash, pubKey)
 	tracing.AnnotateError(span, err)
 	return h, err
@@ -130,8 +149,8 @@ func (s *Service) Status() error {
 	return nil
 }
 
-// RegisterValidator registers a validator with the builder relay network.
-// It also saves the registration object to the DB.
+// RegisterValidator registers a validator with the builder relay network, and with the relays the
+// validator restricted its bids to. It also saves the registration object to the DB.
 func (s *Service) RegisterValidator(ctx context.Context, reg []*ethpb.SignedValidatorRegistrationV1) error {
 	ctx, span := trace.StartSpan(ctx, "builder.RegisterValidator")
 	defer span.End()
@@ -148,6 +167,7 @@ func (s *Service) RegisterValidator(ctx context.Context, reg []*ethpb.SignedVali
 	msgs := make([]*ethpb.ValidatorRegistrationV1, 0)
 
 	indexToRegistration := make(map[primitives.ValidatorIndex]*ethpb.ValidatorRegistrationV1)
+	indexToSigned := make(map[primitives.ValidatorIndex]*ethpb.SignedValidatorRegistrationV1)
 
 	valid := make([]*ethpb.SignedValidatorRegistrationV1, 0)
 	for i := 0; i < len(reg); i++ {
@@ -163,10 +183,12 @@ func (s *Service) RegisterValidator(ctx context.Context, reg []*ethpb.SignedVali
 		msgs = append(msgs, r.Message)
 		valid = append(valid, r)
 		indexToRegistration[nx] = r.Message
+		indexToSigned[nx] = r
 	}
 	if err := s.c.RegisterValidator(ctx, valid); err != nil {
 		return errors.Wrap(err, "could not register validator(s)")
 	}
+	s.registerWithRelays(ctx, indexToSigned)
 
 	if len(indexToRegistration) != len(msgs) {
 		return errors.New("ids and registrations must be the same length")
@@ -37,6 +37,7 @@ type MockBuilderService struct {
 	ErrGetHeader          error
 	ErrRegisterValidator  error
 	Cfg                   *Config
+	AllowedRelays         []string
 }
 
 // Configured for mocking.
@@ -44,6 +45,16 @@ func (s *MockBuilderService) Configured() bool {
 	return s.HasConfigured
 }
 
+// RelayAllowed for mocking.
+func (s *MockBuilderService) RelayAllowed(url string) bool {
+	for _, r := range s.AllowedRelays {
+		if r == url {
+			return true
+		}
+	}
+	return false
+}
+
 // SubmitBlindedBlock for mocking.
 func (s *MockBuilderService) SubmitBlindedBlock(_ context.Context, b interfaces.ReadOnlySignedBeaconBlock) (interfaces.ExecutionData, *v1.BlobsBundle, error) {
 	switch b.Version() {
@@ -10,6 +10,11 @@ type TrackedValidator struct {
 	Active       bool
 	FeeRecipient primitives.ExecutionAddress
 	Index        primitives.ValidatorIndex
+	// Relays restricts the builder relays bids are solicited from for the validator. All configured
+	// relays are used when empty.
+	Relays []string
+	// MinBid overrides the minimum builder bid accepted for the validator when non-zero.
+	MinBid primitives.Gwei
 }
 
 type TrackedValidatorsCache struct {
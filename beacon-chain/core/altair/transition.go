@@ -6,6 +6,7 @@ import (
 	"github.com/pkg/errors"
 	e "github.com/prysmaticlabs/prysm/v5/beacon-chain/core/epoch"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/epoch/precompute"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/transition/statetrace"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
 )
@@ -50,23 +51,27 @@ func ProcessEpoch(ctx context.Context, state state.BeaconState) error {
 	if err != nil {
 		return errors.Wrap(err, "could not process justification")
 	}
+	statetrace.Record(ctx, state, "process_justification_and_finalization")
 
 	// New in Altair.
 	state, vp, err = ProcessInactivityScores(ctx, state, vp)
 	if err != nil {
 		return errors.Wrap(err, "could not process inactivity updates")
 	}
+	statetrace.Record(ctx, state, "process_inactivity_updates")
 
 	// New in Altair.
 	state, err = ProcessRewardsAndPenaltiesPrecompute(state, bp, vp)
 	if err != nil {
 		return errors.Wrap(err, "could not process rewards and penalties")
 	}
+	statetrace.Record(ctx, state, "process_rewards_and_penalties")
 
 	state, err = e.ProcessRegistryUpdates(ctx, state)
 	if err != nil {
 		return errors.Wrap(err, "could not process registry updates")
 	}
+	statetrace.Record(ctx, state, "process_registry_updates")
 
 	// Modified in Altair and Bellatrix.
 	proportionalSlashingMultiplier, err := state.ProportionalSlashingMultiplier()
@@ -77,38 +82,46 @@ func ProcessEpoch(ctx context.Context, state state.BeaconState) error {
 	if err != nil {
 		return err
 	}
+	statetrace.Record(ctx, state, "process_slashings")
 	state, err = e.ProcessEth1DataReset(state)
 	if err != nil {
 		return err
 	}
+	statetrace.Record(ctx, state, "process_eth1_data_reset")
 	state, err = e.ProcessEffectiveBalanceUpdates(state)
 	if err != nil {
 		return err
 	}
+	statetrace.Record(ctx, state, "process_effective_balance_updates")
 	state, err = e.ProcessSlashingsReset(state)
 	if err != nil {
 		return err
 	}
+	statetrace.Record(ctx, state, "process_slashings_reset")
 	state, err = e.ProcessRandaoMixesReset(state)
 	if err != nil {
 		return err
 	}
+	statetrace.Record(ctx, state, "process_randao_mixes_reset")
 	state, err = e.ProcessHistoricalDataUpdate(state)
 	if err != nil {
 		return err
 	}
+	statetrace.Record(ctx, state, "process_historical_data_update")
 
 	// New in Altair.
 	state, err = ProcessParticipationFlagUpdates(state)
 	if err != nil {
 		return err
 	}
+	statetrace.Record(ctx, state, "process_participation_flag_updates")
 
 	// New in Altair.
 	_, err = ProcessSyncCommitteeUpdates(ctx, state)
 	if err != nil {
 		return err
 	}
+	statetrace.Record(ctx, state, "process_sync_committee_updates")
 
 	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/signing"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/transition/statetrace"
 	v "github.com/prysmaticlabs/prysm/v5/beacon-chain/core/validators"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/v5/config/params"
@@ -79,6 +80,7 @@ func ProcessVoluntaryExits(
 		} else if !errors.Is(err, v.ErrValidatorAlreadyExited) {
 			return nil, err
 		}
+		statetrace.RecordIndex(ctx, beaconState, "process_voluntary_exit", idx)
 	}
 	return beaconState, nil
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/blocks"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/signing"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/transition/statetrace"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
 	state_native "github.com/prysmaticlabs/prysm/v5/beacon-chain/state/state-native"
 	"github.com/prysmaticlabs/prysm/v5/config/params"
@@ -44,7 +45,7 @@ func ProcessDeposits(
 		return nil, errors.Wrap(err, "could not verify deposit signatures in batch")
 	}
 
-	for _, d := range deposits {
+	for idx, d := range deposits {
 		if d == nil || d.Data == nil {
 			return nil, errors.New("got a nil deposit in block")
 		}
@@ -52,6 +53,7 @@ func ProcessDeposits(
 		if err != nil {
 			return nil, errors.Wrapf(err, "could not process deposit from %#x", bytesutil.Trunc(d.Data.PublicKey))
 		}
+		statetrace.RecordIndex(ctx, beaconState, "process_deposit", idx)
 	}
 	return beaconState, nil
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/altair"
 	e "github.com/prysmaticlabs/prysm/v5/beacon-chain/core/epoch"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/epoch/precompute"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/transition/statetrace"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/v5/config/params"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/interfaces"
@@ -67,18 +68,22 @@ func ProcessEpoch(ctx context.Context, state state.BeaconState) error {
 	if err != nil {
 		return errors.Wrap(err, "could not process justification")
 	}
+	statetrace.Record(ctx, state, "process_justification_and_finalization")
 	state, vp, err = ProcessInactivityScores(ctx, state, vp)
 	if err != nil {
 		return errors.Wrap(err, "could not process inactivity updates")
 	}
+	statetrace.Record(ctx, state, "process_inactivity_updates")
 	state, err = ProcessRewardsAndPenaltiesPrecompute(state, bp, vp)
 	if err != nil {
 		return errors.Wrap(err, "could not process rewards and penalties")
 	}
+	statetrace.Record(ctx, state, "process_rewards_and_penalties")
 
 	if err := ProcessRegistryUpdates(ctx, state); err != nil {
 		return errors.Wrap(err, "could not process registry updates")
 	}
+	statetrace.Record(ctx, state, "process_registry_updates")
 
 	proportionalSlashingMultiplier, err := state.ProportionalSlashingMultiplier()
 	if err != nil {
@@ -88,43 +93,53 @@ func ProcessEpoch(ctx context.Context, state state.BeaconState) error {
 	if err != nil {
 		return err
 	}
+	statetrace.Record(ctx, state, "process_slashings")
 	state, err = ProcessEth1DataReset(state)
 	if err != nil {
 		return err
 	}
+	statetrace.Record(ctx, state, "process_eth1_data_reset")
 
 	if err = ProcessPendingDeposits(ctx, state, primitives.Gwei(bp.ActiveCurrentEpoch)); err != nil {
 		return err
 	}
+	statetrace.Record(ctx, state, "process_pending_deposits")
 	if err = ProcessPendingConsolidations(ctx, state); err != nil {
 		return err
 	}
+	statetrace.Record(ctx, state, "process_pending_consolidations")
 	if err = ProcessEffectiveBalanceUpdates(state); err != nil {
 		return err
 	}
+	statetrace.Record(ctx, state, "process_effective_balance_updates")
 
 	state, err = ProcessSlashingsReset(state)
 	if err != nil {
 		return err
 	}
+	statetrace.Record(ctx, state, "process_slashings_reset")
 	state, err = ProcessRandaoMixesReset(state)
 	if err != nil {
 		return err
 	}
+	statetrace.Record(ctx, state, "process_randao_mixes_reset")
 	state, err = ProcessHistoricalDataUpdate(state)
 	if err != nil {
 		return err
 	}
+	statetrace.Record(ctx, state, "process_historical_data_update")
 
 	state, err = ProcessParticipationFlagUpdates(state)
 	if err != nil {
 		return err
 	}
+	statetrace.Record(ctx, state, "process_participation_flag_updates")
 
 	_, err = ProcessSyncCommitteeUpdates(ctx, state)
 	if err != nil {
 		return err
 	}
+	statetrace.Record(ctx, state, "process_sync_committee_updates")
 
 	return nil
 }
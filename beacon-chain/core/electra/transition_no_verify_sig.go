@@ -6,6 +6,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/blocks"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/transition/statetrace"
 	v "github.com/prysmaticlabs/prysm/v5/beacon-chain/core/validators"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/interfaces"
@@ -77,6 +78,7 @@ func ProcessOperations(
 	if err != nil {
 		return nil, errors.Wrap(err, "could not process bls-to-execution changes")
 	}
+	statetrace.Record(ctx, st, "process_bls_to_execution_changes")
 	// new in electra
 	requests, err := bb.ExecutionRequests()
 	if err != nil {
@@ -86,12 +88,15 @@ func ProcessOperations(
 	if err != nil {
 		return nil, errors.Wrap(err, "could not process deposit requests")
 	}
+	statetrace.Record(ctx, st, "process_deposit_requests")
 	st, err = ProcessWithdrawalRequests(ctx, st, requests.Withdrawals)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not process withdrawal requests")
 	}
+	statetrace.Record(ctx, st, "process_withdrawal_requests")
 	if err := ProcessConsolidationRequests(ctx, st, requests.Consolidations); err != nil {
 		return nil, fmt.Errorf("could not process consolidation requests: %w", err)
 	}
+	statetrace.Record(ctx, st, "process_consolidation_requests")
 	return st, nil
 }
@@ -343,6 +343,39 @@ func BeaconProposerIndexAtSlot(ctx context.Context, state state.ReadOnlyBeaconSt
 	return ComputeProposerIndex(state, indices, seedWithSlotHash)
 }
 
+// PredictProposerIndices returns the proposer of each slot of the given epoch, computed from the validators of the
+// given state and from the given RANDAO mix instead of the mix at the seed lookahead of the epoch. Validators are
+// assumed to keep their current effective balances, activation and exit epochs, so that for epochs whose seed
+// RANDAO mix isn't known yet, the result is a prediction which can only hold if the given mix is the one that
+// ends up in the seed. The proposer caches are neither read nor updated.
+func PredictProposerIndices(st state.ReadOnlyBeaconState, epoch primitives.Epoch, randaoMix []byte) ([]primitives.ValidatorIndex, error) {
+	indices := make([]primitives.ValidatorIndex, 0, st.NumValidators())
+	if err := st.ReadFromEveryValidator(func(idx int, val state.ReadOnlyValidator) error {
+		if IsActiveValidatorUsingTrie(val, epoch) {
+			indices = append(indices, primitives.ValidatorIndex(idx))
+		}
+		return nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "could not get active indices")
+	}
+	domain := params.BeaconConfig().DomainBeaconProposer
+	seed := hash.Hash(append(append(domain[:], bytesutil.Bytes8(uint64(epoch))...), randaoMix...))
+	startSlot, err := slots.EpochStart(epoch)
+	if err != nil {
+		return nil, err
+	}
+	proposers := make([]primitives.ValidatorIndex, params.BeaconConfig().SlotsPerEpoch)
+	for i := range proposers {
+		slot := startSlot + primitives.Slot(i)
+		seedWithSlot := hash.Hash(append(seed[:], bytesutil.Bytes8(uint64(slot))...))
+		proposers[i], err = ComputeProposerIndex(st, indices, seedWithSlot)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not compute proposer at slot %d", slot)
+		}
+	}
+	return proposers, nil
+}
+
 // ComputeProposerIndex returns the index sampled by effective balance, which is used to calculate proposer.
 //
 // nolint:dupword
@@ -19,6 +19,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/runtime/version"
 	"github.com/prysmaticlabs/prysm/v5/testing/assert"
 	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
 )
 
 func TestIsActiveValidator_OK(t *testing.T) {
@@ -310,6 +311,49 @@ func TestComputeProposerIndex_Compatibility(t *testing.T) {
 	assert.DeepEqual(t, wantedProposerIndices, proposerIndices, "Wanted proposer indices from ComputeProposerIndexWithValidators does not match")
 }
 
+func TestPredictProposerIndices(t *testing.T) {
+	helpers.ClearCache()
+
+	validators := make([]*ethpb.Validator, params.BeaconConfig().MinGenesisActiveValidatorCount)
+	for i := 0; i < len(validators); i++ {
+		validators[i] = &ethpb.Validator{
+			ExitEpoch:        params.BeaconConfig().FarFutureEpoch,
+			EffectiveBalance: params.BeaconConfig().MaxEffectiveBalance,
+		}
+	}
+	// Validator 0 only becomes active at epoch 3.
+	validators[0].ActivationEpoch = 3
+	mixes := make([][]byte, params.BeaconConfig().EpochsPerHistoricalVector)
+	for i := range mixes {
+		mixes[i] = bytesutil.PadTo([]byte{byte(i)}, 32)
+	}
+	state, err := state_native.InitializeFromProtoPhase0(&ethpb.BeaconState{
+		Slot:        params.BeaconConfig().SlotsPerEpoch*3 + 1,
+		Validators:  validators,
+		RandaoMixes: mixes,
+	})
+	require.NoError(t, err)
+
+	// With the mix of the seed lookahead, the prediction for the current epoch matches the actual proposers.
+	mix, err := helpers.RandaoMix(state, 3+params.BeaconConfig().EpochsPerHistoricalVector-params.BeaconConfig().MinSeedLookahead-1)
+	require.NoError(t, err)
+	predicted, err := helpers.PredictProposerIndices(state, 3, mix)
+	require.NoError(t, err)
+	require.Equal(t, int(params.BeaconConfig().SlotsPerEpoch), len(predicted))
+	start, err := slots.EpochStart(3)
+	require.NoError(t, err)
+	for i, p := range predicted {
+		want, err := helpers.BeaconProposerIndexAtSlot(context.Background(), state, start+primitives.Slot(i))
+		require.NoError(t, err)
+		assert.Equal(t, want, p)
+	}
+
+	// A different mix gives a different prediction.
+	other, err := helpers.PredictProposerIndices(state, 3, bytesutil.PadTo([]byte{'a'}, 32))
+	require.NoError(t, err)
+	assert.DeepNotEqual(t, predicted, other)
+}
+
 func TestDelayedActivationExitEpoch_OK(t *testing.T) {
 	helpers.ClearCache()
 
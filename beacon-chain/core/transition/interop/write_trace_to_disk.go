@@ -0,0 +1,36 @@
+package interop
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/transition/statetrace"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/config/features"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/io/file"
+)
+
+// TraceStateTransition traces the state transition of the given pre-state to the block at the given slot, unless
+// the context already carries a tracer. The returned function writes the trace as JSON to temp directory. Debug!
+func TraceStateTransition(ctx context.Context, st state.BeaconState, slot primitives.Slot) (context.Context, func()) {
+	if !features.Get().WriteSSZStateTransitions || statetrace.FromContext(ctx) != nil {
+		return ctx, func() {}
+	}
+	t := statetrace.NewTracer(st)
+	return statetrace.NewContext(ctx, t), func() {
+		fp := path.Join(os.TempDir(), fmt.Sprintf("beacon_state_trace_%d.json", slot))
+		log.Warnf("Writing state transition trace to disk at %s", fp)
+		enc, err := json.Marshal(t.Trace())
+		if err != nil {
+			log.WithError(err).Error("Failed to json encode state transition trace")
+			return
+		}
+		if err := file.WriteFile(fp, enc); err != nil {
+			log.WithError(err).Error("Failed to write to disk")
+		}
+	}
+}
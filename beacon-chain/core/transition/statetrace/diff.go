@@ -0,0 +1,173 @@
+package statetrace
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Byte fields longer than maxWholeBytes, such as the epoch participation flags, are compared byte by byte.
+const maxWholeBytes = 64
+
+// Diff returns the fields that differ between the two states, which may be of different forks.
+func Diff(before, after state.ReadOnlyBeaconState) ([]*Change, error) {
+	b, ok := before.ToProtoUnsafe().(proto.Message)
+	if !ok {
+		return nil, errors.New("could not convert state to proto message")
+	}
+	a, ok := after.ToProtoUnsafe().(proto.Message)
+	if !ok {
+		return nil, errors.New("could not convert state to proto message")
+	}
+	changes := make([]*Change, 0)
+	diffMessages("", b.ProtoReflect(), a.ProtoReflect(), &changes)
+	return changes, nil
+}
+
+func diffMessages(prefix string, before, after protoreflect.Message, changes *[]*Change) {
+	bFields, aFields := before.Descriptor().Fields(), after.Descriptor().Fields()
+	for i := 0; i < aFields.Len(); i++ {
+		afd := aFields.Get(i)
+		path := prefix + string(afd.Name())
+		bfd := bFields.ByName(afd.Name())
+		if bfd == nil {
+			*changes = append(*changes, &Change{Field: path, After: jsonValue(afd, after.Get(afd))})
+			continue
+		}
+		diffField(path, bfd, afd, before.Get(bfd), after.Get(afd), changes)
+	}
+	for i := 0; i < bFields.Len(); i++ {
+		bfd := bFields.Get(i)
+		if aFields.ByName(bfd.Name()) == nil {
+			*changes = append(*changes, &Change{Field: prefix + string(bfd.Name()), Before: jsonValue(bfd, before.Get(bfd))})
+		}
+	}
+}
+
+func diffField(path string, bfd, afd protoreflect.FieldDescriptor, before, after protoreflect.Value, changes *[]*Change) {
+	switch {
+	case afd.IsList():
+		diffLists(path, bfd, afd, before.List(), after.List(), changes)
+	case afd.Kind() == protoreflect.MessageKind:
+		bm, am := before.Message(), after.Message()
+		if !bm.IsValid() || !am.IsValid() || bm.Descriptor().FullName() != am.Descriptor().FullName() {
+			// Messages of different forks, such as execution payload headers, are compared field by field.
+			if bm.IsValid() && am.IsValid() {
+				diffMessages(path+".", bm, am, changes)
+			} else if bm.IsValid() != am.IsValid() {
+				*changes = append(*changes, &Change{Field: path, Before: jsonValue(bfd, before), After: jsonValue(afd, after)})
+			}
+			return
+		}
+		if bm.Interface() == am.Interface() || proto.Equal(bm.Interface(), am.Interface()) {
+			return
+		}
+		diffMessages(path+".", bm, am, changes)
+	case afd.Kind() == protoreflect.BytesKind:
+		b, a := before.Bytes(), after.Bytes()
+		if len(b) > maxWholeBytes || len(a) > maxWholeBytes {
+			diffBytes(path, b, a, changes)
+			return
+		}
+		if !before.Equal(after) {
+			*changes = append(*changes, &Change{Field: path, Before: jsonValue(bfd, before), After: jsonValue(afd, after)})
+		}
+	default:
+		if !before.Equal(after) {
+			*changes = append(*changes, &Change{Field: path, Before: jsonValue(bfd, before), After: jsonValue(afd, after)})
+		}
+	}
+}
+
+func diffLists(path string, bfd, afd protoreflect.FieldDescriptor, before, after protoreflect.List, changes *[]*Change) {
+	n := max(before.Len(), after.Len())
+	for i := 0; i < n; i++ {
+		elemPath := fmt.Sprintf("%s[%d]", path, i)
+		if i >= before.Len() {
+			*changes = append(*changes, &Change{Field: elemPath, After: elemValue(afd, after.Get(i))})
+			continue
+		}
+		if i >= after.Len() {
+			*changes = append(*changes, &Change{Field: elemPath, Before: elemValue(bfd, before.Get(i))})
+			continue
+		}
+		b, a := before.Get(i), after.Get(i)
+		if afd.Kind() == protoreflect.MessageKind {
+			bm, am := b.Message(), a.Message()
+			// Unchanged elements are usually shared between the copies of a state.
+			if bm.Interface() == am.Interface() || proto.Equal(bm.Interface(), am.Interface()) {
+				continue
+			}
+			diffMessages(elemPath+".", bm, am, changes)
+			continue
+		}
+		if !b.Equal(a) {
+			*changes = append(*changes, &Change{Field: elemPath, Before: elemValue(bfd, b), After: elemValue(afd, a)})
+		}
+	}
+}
+
+func diffBytes(path string, before, after []byte, changes *[]*Change) {
+	n := max(len(before), len(after))
+	for i := 0; i < n; i++ {
+		if i < len(before) && i < len(after) && before[i] == after[i] {
+			continue
+		}
+		c := &Change{Field: fmt.Sprintf("%s[%d]", path, i)}
+		if i < len(before) {
+			c.Before = before[i]
+		}
+		if i < len(after) {
+			c.After = after[i]
+		}
+		*changes = append(*changes, c)
+	}
+}
+
+// jsonValue converts a field value to a value that encodes to JSON like the Beacon API: integers as decimal
+// strings and bytes as hex strings.
+func jsonValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+	if fd.IsList() {
+		l := v.List()
+		values := make([]interface{}, l.Len())
+		for i := range values {
+			values[i] = elemValue(fd, l.Get(i))
+		}
+		return values
+	}
+	return elemValue(fd, v)
+}
+
+func elemValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return v.Bool()
+	case protoreflect.Uint32Kind, protoreflect.Uint64Kind, protoreflect.Fixed32Kind, protoreflect.Fixed64Kind:
+		return strconv.FormatUint(v.Uint(), 10)
+	case protoreflect.Int32Kind, protoreflect.Int64Kind, protoreflect.Sint32Kind, protoreflect.Sint64Kind,
+		protoreflect.Sfixed32Kind, protoreflect.Sfixed64Kind:
+		return strconv.FormatInt(v.Int(), 10)
+	case protoreflect.BytesKind:
+		return hexutil.Encode(v.Bytes())
+	case protoreflect.StringKind:
+		return v.String()
+	case protoreflect.MessageKind:
+		m := v.Message()
+		if !m.IsValid() {
+			return nil
+		}
+		fields := m.Descriptor().Fields()
+		values := make(map[string]interface{}, fields.Len())
+		for i := 0; i < fields.Len(); i++ {
+			values[string(fields.Get(i).Name())] = jsonValue(fields.Get(i), m.Get(fields.Get(i)))
+		}
+		return values
+	default:
+		return v.Interface()
+	}
+}
@@ -0,0 +1,7 @@
+package statetrace
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "statetrace")
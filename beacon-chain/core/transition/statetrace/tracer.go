@@ -0,0 +1,99 @@
+// Package statetrace records the beacon state fields changed by each step of a state transition, such as an
+// attestation of a block or a step of epoch processing, with their values before and after the step. This is
+// meant for debugging consensus issues.
+package statetrace
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+)
+
+// Change is a state field changed by a step of the state transition. Fields of containers are separated by dots
+// and list elements are indexed, for instance `validators[12].effective_balance`. Before is nil for list elements
+// that were appended, and After is nil for fields that were removed by a fork upgrade.
+type Change struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Step is an operation of the state transition with the state fields it changed. Index is the position of the
+// operation in the block for block operations such as attestations or deposits.
+type Step struct {
+	Slot      string    `json:"slot"`
+	Operation string    `json:"operation"`
+	Index     *int      `json:"index,omitempty"`
+	Changes   []*Change `json:"changes"`
+}
+
+// Trace is the list of steps of a state transition that changed the state, in processing order.
+type Trace struct {
+	Steps []*Step `json:"steps"`
+}
+
+// Tracer attributes the changes made to the state since the previous step to each step of a state transition.
+// It keeps a copy of the state after each step, which is cheap as state fields are copied on write.
+type Tracer struct {
+	prev  state.BeaconState
+	trace Trace
+}
+
+// NewTracer returns a tracer of the state transition of the given pre-state.
+func NewTracer(st state.BeaconState) *Tracer {
+	return &Tracer{prev: st.Copy(), trace: Trace{Steps: make([]*Step, 0)}}
+}
+
+type tracerKey struct{}
+
+// NewContext returns a context carrying the given tracer. The state transition functions record their steps
+// in the tracer of their context.
+func NewContext(ctx context.Context, t *Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, t)
+}
+
+// FromContext returns the tracer carried by the context, if any.
+func FromContext(ctx context.Context) *Tracer {
+	t, _ := ctx.Value(tracerKey{}).(*Tracer)
+	return t
+}
+
+// Record records the changes made to the state since the previous step as the given operation, if the context
+// carries a tracer.
+func Record(ctx context.Context, st state.BeaconState, operation string) {
+	if t := FromContext(ctx); t != nil {
+		t.record(st, operation, nil)
+	}
+}
+
+// RecordIndex is like Record, for the operation at the given index in the block.
+func RecordIndex(ctx context.Context, st state.BeaconState, operation string, index int) {
+	if t := FromContext(ctx); t != nil {
+		t.record(st, operation, &index)
+	}
+}
+
+func (t *Tracer) record(st state.BeaconState, operation string, index *int) {
+	if st == nil || st.IsNil() {
+		return
+	}
+	changes, err := Diff(t.prev, st)
+	if err != nil {
+		log.WithError(err).WithField("operation", operation).Error("Could not trace state changes")
+	}
+	if len(changes) > 0 {
+		t.trace.Steps = append(t.trace.Steps, &Step{
+			Slot:      strconv.FormatUint(uint64(st.Slot()), 10),
+			Operation: operation,
+			Index:     index,
+			Changes:   changes,
+		})
+	}
+	t.prev = st.Copy()
+}
+
+// Trace returns the steps recorded so far.
+func (t *Tracer) Trace() *Trace {
+	return &t.trace
+}
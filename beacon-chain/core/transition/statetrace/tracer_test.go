@@ -0,0 +1,73 @@
+package statetrace_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/altair"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/transition"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/transition/statetrace"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+func TestDiff(t *testing.T) {
+	before, _ := util.DeterministicGenesisStateAltair(t, 8)
+	after := before.Copy()
+	require.NoError(t, after.UpdateBalancesAtIndex(3, 42))
+	require.NoError(t, after.SetSlot(5))
+
+	changes, err := statetrace.Diff(before, after)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(changes))
+	require.Equal(t, "slot", changes[0].Field)
+	require.Equal(t, "0", changes[0].Before)
+	require.Equal(t, "5", changes[0].After)
+	require.Equal(t, "balances[3]", changes[1].Field)
+	require.Equal(t, "42", changes[1].After)
+
+	changes, err = statetrace.Diff(before, before.Copy())
+	require.NoError(t, err)
+	require.Equal(t, 0, len(changes))
+}
+
+func TestTracer_ExecuteStateTransition(t *testing.T) {
+	st, privKeys := util.DeterministicGenesisStateAltair(t, 32)
+	sCom, err := altair.NextSyncCommittee(context.Background(), st)
+	require.NoError(t, err)
+	require.NoError(t, st.SetCurrentSyncCommittee(sCom))
+	blk, err := util.GenerateFullBlockAltair(st.Copy(), privKeys,
+		&util.BlockGenConfig{NumAttestations: 1}, 1)
+	require.NoError(t, err)
+	wsb, err := blocks.NewSignedBeaconBlock(blk)
+	require.NoError(t, err)
+
+	tracer := statetrace.NewTracer(st)
+	ctx := statetrace.NewContext(context.Background(), tracer)
+	_, err = transition.ExecuteStateTransition(ctx, st, wsb)
+	require.NoError(t, err)
+
+	ops := make(map[string]*statetrace.Step)
+	for _, s := range tracer.Trace().Steps {
+		ops[s.Operation] = s
+	}
+	for _, op := range []string{"process_slot", "process_slots", "process_block_header", "process_randao", "process_attestation"} {
+		_, ok := ops[op]
+		require.Equal(t, true, ok, "missing step %s", op)
+	}
+	att := ops["process_attestation"]
+	require.NotNil(t, att.Index)
+	require.Equal(t, 0, *att.Index)
+
+	_, err = json.Marshal(tracer.Trace())
+	require.NoError(t, err)
+}
+
+func TestRecord_NoTracer(t *testing.T) {
+	st, _ := util.DeterministicGenesisStateAltair(t, 8)
+	// Recording without a tracer in the context is a no-op.
+	statetrace.Record(context.Background(), st, "process_slot")
+	require.Equal(t, (*statetrace.Tracer)(nil), statetrace.FromContext(context.Background()))
+}
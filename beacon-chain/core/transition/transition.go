@@ -18,6 +18,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/epoch/precompute"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/execution"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/time"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/transition/statetrace"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/v5/config/features"
 	"github.com/prysmaticlabs/prysm/v5/config/params"
@@ -273,6 +274,7 @@ func ProcessSlotsCore(ctx context.Context, span trace.Span, state state.BeaconSt
 			tracing.AnnotateError(span, err)
 			return nil, errors.Wrap(err, "could not process slot")
 		}
+		statetrace.Record(ctx, state, "process_slot")
 
 		state, err = ProcessEpoch(ctx, state)
 		if err != nil {
@@ -284,12 +286,14 @@ func ProcessSlotsCore(ctx context.Context, span trace.Span, state state.BeaconSt
 			tracing.AnnotateError(span, err)
 			return nil, errors.Wrap(err, "failed to increment state slot")
 		}
+		statetrace.Record(ctx, state, "process_slots")
 
 		state, err = UpgradeState(ctx, state)
 		if err != nil {
 			tracing.AnnotateError(span, err)
 			return nil, errors.Wrap(err, "failed to upgrade state")
 		}
+		statetrace.Record(ctx, state, "upgrade_state")
 	}
 	return state, nil
 }
@@ -465,25 +469,30 @@ func ProcessEpochPrecompute(ctx context.Context, state state.BeaconState) (state
 	if err != nil {
 		return nil, errors.Wrap(err, "could not process justification")
 	}
+	statetrace.Record(ctx, state, "process_justification_and_finalization")
 
 	state, err = precompute.ProcessRewardsAndPenaltiesPrecompute(state, bp, vp, precompute.AttestationsDelta, precompute.ProposersDelta)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not process rewards and penalties")
 	}
+	statetrace.Record(ctx, state, "process_rewards_and_penalties")
 
 	state, err = e.ProcessRegistryUpdates(ctx, state)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not process registry updates")
 	}
+	statetrace.Record(ctx, state, "process_registry_updates")
 
 	err = precompute.ProcessSlashingsPrecompute(state, bp)
 	if err != nil {
 		return nil, err
 	}
+	statetrace.Record(ctx, state, "process_slashings")
 
 	state, err = e.ProcessFinalUpdates(state)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not process final updates")
 	}
+	statetrace.Record(ctx, state, "process_final_updates")
 	return state, nil
 }
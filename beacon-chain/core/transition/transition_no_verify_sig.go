@@ -10,6 +10,7 @@ import (
 	b "github.com/prysmaticlabs/prysm/v5/beacon-chain/core/blocks"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/electra"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/transition/interop"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/transition/statetrace"
 	v "github.com/prysmaticlabs/prysm/v5/beacon-chain/core/validators"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
@@ -60,12 +61,16 @@ func ExecuteStateTransitionNoVerifyAnySig(
 
 	interop.WriteBlockToDisk(signed, false /* Has the block failed */)
 	interop.WriteStateToDisk(st)
+	ctx, writeTrace := interop.TraceStateTransition(ctx, st, signed.Block().Slot())
+	defer writeTrace()
 
 	parentRoot := signed.Block().ParentRoot()
 	st, err = ProcessSlotsUsingNextSlotCache(ctx, st, parentRoot[:], signed.Block().Slot())
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "could not process slots")
 	}
+	// The slots may have been processed by the next slot cache, without recording their steps.
+	statetrace.Record(ctx, st, "process_slots")
 
 	// Execute per block transition.
 	set, st, err := ProcessBlockNoVerifyAnySig(ctx, st, signed)
@@ -317,6 +322,7 @@ func ProcessBlockForStateRoot(
 		tracing.AnnotateError(span, err)
 		return nil, errors.Wrap(err, "could not process block header")
 	}
+	statetrace.Record(ctx, state, "process_block_header")
 
 	enabled, err := b.IsExecutionEnabled(state, blk.Body())
 	if err != nil {
@@ -332,10 +338,12 @@ func ProcessBlockForStateRoot(
 			if err != nil {
 				return nil, errors.Wrap(err, "could not process withdrawals")
 			}
+			statetrace.Record(ctx, state, "process_withdrawals")
 		}
 		if err = b.ProcessPayload(state, blk.Body()); err != nil {
 			return nil, errors.Wrap(err, "could not process execution data")
 		}
+		statetrace.Record(ctx, state, "process_execution_payload")
 	}
 
 	randaoReveal := signed.Block().Body().RandaoReveal()
@@ -344,18 +352,21 @@ func ProcessBlockForStateRoot(
 		tracing.AnnotateError(span, err)
 		return nil, errors.Wrap(err, "could not verify and process randao")
 	}
+	statetrace.Record(ctx, state, "process_randao")
 
 	state, err = b.ProcessEth1DataInBlock(ctx, state, signed.Block().Body().Eth1Data())
 	if err != nil {
 		tracing.AnnotateError(span, err)
 		return nil, errors.Wrap(err, "could not process eth1 data")
 	}
+	statetrace.Record(ctx, state, "process_eth1_data")
 
 	state, err = ProcessOperationsNoVerifyAttsSigs(ctx, state, signed.Block())
 	if err != nil {
 		tracing.AnnotateError(span, err)
 		return nil, errors.Wrap(err, "could not process block operation")
 	}
+	statetrace.Record(ctx, state, "process_operations")
 
 	if signed.Block().Version() == version.Phase0 {
 		return state, nil
@@ -369,6 +380,7 @@ func ProcessBlockForStateRoot(
 	if err != nil {
 		return nil, errors.Wrap(err, "process_sync_aggregate failed")
 	}
+	statetrace.Record(ctx, state, "process_sync_aggregate")
 
 	return state, nil
 }
@@ -397,7 +409,12 @@ func altairOperations(
 	if err != nil {
 		return nil, errors.Wrap(err, "could not process voluntary exits")
 	}
-	return b.ProcessBLSToExecutionChanges(st, beaconBlock)
+	st, err = b.ProcessBLSToExecutionChanges(st, beaconBlock)
+	if err != nil {
+		return nil, err
+	}
+	statetrace.Record(ctx, st, "process_bls_to_execution_changes")
+	return st, nil
 }
 
 // This calls phase 0 block operations.
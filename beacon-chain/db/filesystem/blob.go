@@ -20,6 +20,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/io/file"
 	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
 	"github.com/prysmaticlabs/prysm/v5/runtime/logging"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 )
@@ -66,6 +67,15 @@ func WithSaveFsync(fsync bool) BlobStorageOption {
 	}
 }
 
+// WithBlobArchive is an option that disables pruning, so that blobs are kept forever. It also enables the coverage
+// index of the blob archive, which keeps track of the historical blobs that could not be found.
+func WithBlobArchive(archive bool) BlobStorageOption {
+	return func(b *BlobStorage) error {
+		b.archive = archive
+		return nil
+	}
+}
+
 // NewBlobStorage creates a new instance of the BlobStorage object. Note that the implementation of BlobStorage may
 // attempt to hold a file lock to guarantee exclusive control of the blob storage directory, so this should only be
 // initialized once per beacon node.
@@ -84,7 +94,24 @@ func NewBlobStorage(opts ...BlobStorageOption) (*BlobStorage, error) {
 		return nil, errors.Wrapf(err, "failed to create blob storage at %s", b.base)
 	}
 	b.fs = afero.NewBasePathFs(afero.NewOsFs(), b.base)
-	pruner, err := newBlobPruner(b.fs, b.retentionEpochs)
+	index, err := newBlobIndex(b.fs, b.fsync)
+	if err != nil {
+		return nil, err
+	}
+	b.index = index
+	if err := migrateFlatLayout(b.fs, b.index); err != nil {
+		log.WithError(err).Error("Some blobs could not be migrated to the per-epoch blob storage layout")
+	}
+	var popts []prunerOpt
+	if b.archive {
+		popts = append(popts, withPruningDisabled())
+		coverage, err := newBlobCoverage(b.fs)
+		if err != nil {
+			return nil, err
+		}
+		b.coverage = coverage
+	}
+	pruner, err := newBlobPruner(b.fs, b.index, b.retentionEpochs, popts...)
 	if err != nil {
 		return nil, err
 	}
@@ -97,19 +124,27 @@ type BlobStorage struct {
 	base            string
 	retentionEpochs primitives.Epoch
 	fsync           bool
+	archive         bool
 	fs              afero.Fs
+	index           *blobIndex
 	pruner          *blobPruner
+	coverage        *BlobCoverage
 }
 
-// WarmCache runs the prune routine with an expiration of slot of 0, so nothing will be pruned, but the pruner's cache
-// will be populated at node startup, avoiding a costly cold prune (~4s in syscalls) during syncing.
+// Coverage returns the coverage index of the blob archive, or nil if the BlobStorage is not in archive mode.
+func (bs *BlobStorage) Coverage() *BlobCoverage {
+	return bs.coverage
+}
+
+// WarmCache populates the pruner's cache from the blob index at node startup, so that the blob sidecars on disk
+// are known without listing the blob storage directories.
 func (bs *BlobStorage) WarmCache() {
 	if bs.pruner == nil {
 		return
 	}
 	go func() {
 		start := time.Now()
-		log.Info("Blob filesystem cache warm-up started. This may take a few minutes.")
+		log.Info("Blob filesystem cache warm-up started")
 		if err := bs.pruner.warmCache(); err != nil {
 			log.WithError(err).Error("Error encountered while warming up blob pruner cache")
 		}
@@ -143,7 +178,8 @@ func (bs *BlobStorage) Save(sidecar blocks.VerifiedROBlob) error {
 	}
 	if exists {
 		log.WithFields(logging.BlobFields(sidecar.ROBlob)).Debug("Ignoring a duplicate blob sidecar save attempt")
-		return nil
+		// The node could have stopped after writing the sidecar but before adding it to the index.
+		return bs.index.add(sidecar.BlockRoot(), sidecar.Slot(), sidecar.Index)
 	}
 	if bs.pruner != nil {
 		if err := bs.pruner.notify(sidecar.BlockRoot(), sidecar.Slot(), sidecar.Index); err != nil {
@@ -211,6 +247,11 @@ func (bs *BlobStorage) Save(sidecar blocks.VerifiedROBlob) error {
 		return errEmptyBlobWritten
 	}
 
+	// The sidecar is indexed before it is moved to its final name, so that a blob on disk is never missing from
+	// the index. An index entry without a blob file is harmless, since reads go to the file.
+	if err := bs.index.add(sidecar.BlockRoot(), sidecar.Slot(), sidecar.Index); err != nil {
+		return errors.Wrap(err, "failed to add blob sidecar to the blob index")
+	}
 	// Atomically rename the partial file to its final name.
 	err = bs.fs.Rename(partPath, sszPath)
 	if err != nil {
@@ -228,9 +269,14 @@ func (bs *BlobStorage) Save(sidecar blocks.VerifiedROBlob) error {
 // value is always a VerifiedROBlob.
 func (bs *BlobStorage) Get(root [32]byte, idx uint64) (blocks.VerifiedROBlob, error) {
 	startTime := time.Now()
-	expected := blobNamer{root: root, index: idx}
-	encoded, err := afero.ReadFile(bs.fs, expected.path())
 	var v blocks.VerifiedROBlob
+	slot, ok := bs.index.slot(root)
+	if !ok {
+		// Callers check for missing blobs with os.IsNotExist, which doesn't unwrap errors.
+		return v, &os.PathError{Op: "open", Path: rootString(root), Err: os.ErrNotExist}
+	}
+	expected := newBlobNamer(root, slot, idx)
+	encoded, err := afero.ReadFile(bs.fs, expected.path())
 	if err != nil {
 		return v, err
 	}
@@ -248,10 +294,50 @@ func (bs *BlobStorage) Get(root [32]byte, idx uint64) (blocks.VerifiedROBlob, er
 	return verification.BlobSidecarNoop(ro)
 }
 
-// Remove removes all blobs for a given root.
+// Remove removes all blobs for a given root. Roots missing from the index are looked up in the epoch directories,
+// so that blobs left behind by an earlier version or an interrupted write are removed too.
 func (bs *BlobStorage) Remove(root [32]byte) error {
-	rootDir := blobNamer{root: root}.dir()
-	return bs.fs.RemoveAll(rootDir)
+	var dirs []string
+	if slot, ok := bs.index.slot(root); ok {
+		if err := bs.index.remove(root); err != nil {
+			return err
+		}
+		dirs = []string{newBlobNamer(root, slot, 0).dir()}
+	} else {
+		var err error
+		if dirs, err = bs.rootDirs(root); err != nil {
+			return err
+		}
+	}
+	if bs.pruner != nil {
+		bs.pruner.cache.evict(root)
+	}
+	for _, dir := range dirs {
+		if err := bs.fs.RemoveAll(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rootDirs scans the epoch directories for the directories holding blobs of the given root.
+func (bs *BlobStorage) rootDirs(root [32]byte) ([]string, error) {
+	entries, err := listDir(bs.fs, ".")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list root blobs directory")
+	}
+	dirs := make([]string, 0)
+	for _, epochDir := range filter(entries, filterEpoch) {
+		dir := path.Join(epochDir, rootString(root))
+		exists, err := afero.DirExists(bs.fs, dir)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs, nil
 }
 
 // Indices generates a bitmap representing which BlobSidecar.Index values are present on disk for a given root.
@@ -261,7 +347,12 @@ func (bs *BlobStorage) Indices(root [32]byte, s primitives.Slot) ([]bool, error)
 	maxBlobsPerBlock := params.BeaconConfig().MaxBlobsPerBlock(s)
 	mask := make([]bool, maxBlobsPerBlock)
 
-	rootDir := blobNamer{root: root}.dir()
+	// The slot of the block is only needed to find its epoch directory when the root is not indexed.
+	dirSlot := s
+	if indexed, ok := bs.index.slot(root); ok {
+		dirSlot = indexed
+	}
+	rootDir := newBlobNamer(root, dirSlot, 0).dir()
 	entries, err := afero.ReadDir(bs.fs, rootDir)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -305,11 +396,22 @@ func (bs *BlobStorage) Clear() error {
 			return err
 		}
 	}
-	return nil
+	if bs.coverage != nil {
+		bs.coverage.reset()
+	}
+	return bs.index.reset()
 }
 
-// WithinRetentionPeriod checks if the requested epoch is within the blob retention period.
+// WithinRetentionPeriod checks if the requested epoch is within the blob retention period. In archive mode, epochs
+// older than the retention period are also within it once the blob archive has backfilled all of their slots.
 func (bs *BlobStorage) WithinRetentionPeriod(requested, current primitives.Epoch) bool {
+	if bs.archive && bs.coverage != nil {
+		low, high := bs.coverage.Checked()
+		start, err := slots.EpochStart(requested)
+		if high != 0 && err == nil && start >= low {
+			return true
+		}
+	}
 	if requested > math.MaxUint64-bs.retentionEpochs {
 		// If there is an overflow, then the retention period was set to an extremely large number.
 		return true
@@ -317,17 +419,23 @@ func (bs *BlobStorage) WithinRetentionPeriod(requested, current primitives.Epoch
 	return requested+bs.retentionEpochs >= current
 }
 
+// blobNamer computes the path of blob sidecars in the per-epoch layout: <epoch>/<block root>/<index>.ssz.
 type blobNamer struct {
+	epoch primitives.Epoch
 	root  [32]byte
 	index uint64
 }
 
+func newBlobNamer(root [32]byte, slot primitives.Slot, index uint64) blobNamer {
+	return blobNamer{epoch: slots.ToEpoch(slot), root: root, index: index}
+}
+
 func namerForSidecar(sc blocks.VerifiedROBlob) blobNamer {
-	return blobNamer{root: sc.BlockRoot(), index: sc.Index}
+	return newBlobNamer(sc.BlockRoot(), sc.Slot(), sc.Index)
 }
 
 func (p blobNamer) dir() string {
-	return rootString(p.root)
+	return path.Join(epochString(p.epoch), rootString(p.root))
 }
 
 func (p blobNamer) partPath(entropy string) string {
@@ -338,6 +446,10 @@ func (p blobNamer) path() string {
 	return path.Join(p.dir(), fmt.Sprintf("%d.%s", p.index, sszExt))
 }
 
+func epochString(epoch primitives.Epoch) string {
+	return strconv.FormatUint(uint64(epoch), 10)
+}
+
 func rootString(root [32]byte) string {
 	return fmt.Sprintf("%#x", root)
 }
@@ -88,6 +88,23 @@ func TestBlobStorage_SaveBlobData(t *testing.T) {
 		require.ErrorContains(t, "file does not exist", err)
 	})
 
+	t.Run("delete blobs missing from the index", func(t *testing.T) {
+		fs, bs := NewEphemeralBlobStorageWithFs(t)
+		expected := testSidecars[0]
+		require.NoError(t, bs.Save(expected))
+		// Simulate a blob that was written without an index entry.
+		require.NoError(t, bs.index.remove(expected.BlockRoot()))
+		dir := namerForSidecar(expected).dir()
+		exists, err := afero.DirExists(fs, dir)
+		require.NoError(t, err)
+		require.Equal(t, true, exists)
+
+		require.NoError(t, bs.Remove(expected.BlockRoot()))
+		exists, err = afero.DirExists(fs, dir)
+		require.NoError(t, err)
+		require.Equal(t, false, exists)
+	})
+
 	t.Run("clear", func(t *testing.T) {
 		blob := testSidecars[0]
 		b := NewEphemeralBlobStorage(t)
@@ -133,7 +150,7 @@ func TestBlobIndicesBounds(t *testing.T) {
 	root := [32]byte{}
 
 	okIdx := uint64(params.BeaconConfig().MaxBlobsPerBlock(0)) - 1
-	writeFakeSSZ(t, fs, root, okIdx)
+	writeFakeSSZ(t, fs, root, 100, okIdx)
 	indices, err := bs.Indices(root, 100)
 	require.NoError(t, err)
 	expected := make([]bool, params.BeaconConfig().MaxBlobsPerBlock(0))
@@ -143,13 +160,13 @@ func TestBlobIndicesBounds(t *testing.T) {
 	}
 
 	oobIdx := uint64(params.BeaconConfig().MaxBlobsPerBlock(0))
-	writeFakeSSZ(t, fs, root, oobIdx)
+	writeFakeSSZ(t, fs, root, 100, oobIdx)
 	_, err = bs.Indices(root, 100)
 	require.ErrorIs(t, err, errIndexOutOfBounds)
 }
 
-func writeFakeSSZ(t *testing.T, fs afero.Fs, root [32]byte, idx uint64) {
-	namer := blobNamer{root: root, index: idx}
+func writeFakeSSZ(t *testing.T, fs afero.Fs, root [32]byte, slot primitives.Slot, idx uint64) {
+	namer := newBlobNamer(root, slot, idx)
 	require.NoError(t, fs.MkdirAll(namer.dir(), 0700))
 	fh, err := fs.Create(namer.path())
 	require.NoError(t, err)
@@ -173,9 +190,9 @@ func TestBlobStoragePrune(t *testing.T) {
 
 		require.NoError(t, bs.pruner.prune(currentSlot-bs.pruner.windowSize))
 
-		remainingFolders, err := afero.ReadDir(fs, ".")
+		remainingFolders, err := listDir(fs, ".")
 		require.NoError(t, err)
-		require.Equal(t, 0, len(remainingFolders))
+		require.Equal(t, 0, len(filter(remainingFolders, filterEpoch)))
 	})
 	t.Run("Prune dangling blob", func(t *testing.T) {
 		_, sidecars := util.GenerateTestDenebBlockWithSidecar(t, [32]byte{}, 299, params.BeaconConfig().MaxBlobsPerBlock(0))
@@ -188,9 +205,9 @@ func TestBlobStoragePrune(t *testing.T) {
 
 		require.NoError(t, bs.pruner.prune(currentSlot-bs.pruner.windowSize))
 
-		remainingFolders, err := afero.ReadDir(fs, ".")
+		remainingFolders, err := listDir(fs, ".")
 		require.NoError(t, err)
-		require.Equal(t, 0, len(remainingFolders))
+		require.Equal(t, 0, len(filter(remainingFolders, filterEpoch)))
 	})
 	t.Run("PruneMany", func(t *testing.T) {
 		blockQty := 10
@@ -208,9 +225,9 @@ func TestBlobStoragePrune(t *testing.T) {
 
 		require.NoError(t, bs.pruner.prune(currentSlot-bs.pruner.windowSize))
 
-		remainingFolders, err := afero.ReadDir(fs, ".")
+		remainingFolders, err := listDir(fs, ".")
 		require.NoError(t, err)
-		require.Equal(t, 4, len(remainingFolders))
+		require.Equal(t, 4, len(filter(remainingFolders, filterEpoch)))
 	})
 }
 
@@ -0,0 +1,146 @@
+package filesystem
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/spf13/afero"
+)
+
+const coverageFileName = "archive-coverage.json"
+
+var errCoverageNotContiguous = errors.New("blob archive coverage can only be extended downwards from the lowest checked slot")
+
+// BlobGap describes a block whose blob sidecars could not be found by the blob archive.
+type BlobGap struct {
+	Slot    primitives.Slot `json:"slot"`
+	Root    [32]byte        `json:"root"`
+	Missing []uint64        `json:"missing"`
+}
+
+type blobCoverageRecord struct {
+	Low  primitives.Slot `json:"low"`
+	High primitives.Slot `json:"high"`
+	Gaps []BlobGap       `json:"gaps"`
+}
+
+// BlobCoverage is the index of the slots that the blob archive has checked for blob sidecars older than the
+// retention period. Slots in [low, high) have been checked, and every block in that range with blob sidecars that
+// could not be found is recorded as a BlobGap. The index is persisted next to the blobs.
+type BlobCoverage struct {
+	fs  afero.Fs
+	mu  sync.RWMutex
+	rec blobCoverageRecord
+}
+
+func newBlobCoverage(fs afero.Fs) (*BlobCoverage, error) {
+	c := &BlobCoverage{fs: fs}
+	b, err := afero.ReadFile(fs, coverageFileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read blob archive coverage")
+	}
+	if err := json.Unmarshal(b, &c.rec); err != nil {
+		return nil, errors.Wrap(err, "could not decode blob archive coverage")
+	}
+	return c, nil
+}
+
+// Checked returns the range of slots [low, high) that has been checked by the blob archive.
+// The range is empty when the archive hasn't started yet.
+func (c *BlobCoverage) Checked() (low, high primitives.Slot) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.rec.Low, c.rec.High
+}
+
+// Start sets the slot that the blob archive checks downwards from, unless the archive has started before.
+// It returns the lowest slot checked so far.
+func (c *BlobCoverage) Start(high primitives.Slot) (primitives.Slot, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rec.High != 0 {
+		return c.rec.Low, nil
+	}
+	c.rec.Low, c.rec.High = high, high
+	return high, c.persist()
+}
+
+// Extend records that the slots from low up to the lowest checked slot have been checked, along with
+// the gaps found in them.
+func (c *BlobCoverage) Extend(low primitives.Slot, gaps []BlobGap) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if low > c.rec.Low {
+		return errors.Wrapf(errCoverageNotContiguous, "low=%d, checked=[%d, %d)", low, c.rec.Low, c.rec.High)
+	}
+	for _, g := range gaps {
+		if g.Slot < low || g.Slot >= c.rec.Low {
+			return errors.Wrapf(errCoverageNotContiguous, "gap at slot %d outside of [%d, %d)", g.Slot, low, c.rec.Low)
+		}
+	}
+	c.rec.Low = low
+	c.rec.Gaps = append(c.rec.Gaps, gaps...)
+	sort.Slice(c.rec.Gaps, func(i, j int) bool {
+		return c.rec.Gaps[i].Slot < c.rec.Gaps[j].Slot
+	})
+	return c.persist()
+}
+
+// UpdateGap replaces the gap recorded for the block root of the given gap. The gap is removed from the index
+// when no blob sidecars are missing anymore.
+func (c *BlobCoverage) UpdateGap(g BlobGap) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range c.rec.Gaps {
+		if c.rec.Gaps[i].Root != g.Root {
+			continue
+		}
+		if len(g.Missing) == 0 {
+			c.rec.Gaps = append(c.rec.Gaps[:i], c.rec.Gaps[i+1:]...)
+		} else {
+			c.rec.Gaps[i].Missing = g.Missing
+		}
+		return c.persist()
+	}
+	return nil
+}
+
+// Gaps returns the gaps recorded for slots in [start, end).
+func (c *BlobCoverage) Gaps(start, end primitives.Slot) []BlobGap {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	gaps := make([]BlobGap, 0)
+	for _, g := range c.rec.Gaps {
+		if g.Slot >= start && g.Slot < end {
+			gaps = append(gaps, BlobGap{Slot: g.Slot, Root: g.Root, Missing: append([]uint64{}, g.Missing...)})
+		}
+	}
+	return gaps
+}
+
+// reset forgets the coverage after the blob storage has been cleared.
+func (c *BlobCoverage) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rec = blobCoverageRecord{}
+}
+
+func (c *BlobCoverage) persist() error {
+	b, err := json.Marshal(c.rec)
+	if err != nil {
+		return errors.Wrap(err, "could not encode blob archive coverage")
+	}
+	partPath := fmt.Sprintf("%s.%s", coverageFileName, partExt)
+	if err := afero.WriteFile(c.fs, partPath, b, 0600); err != nil {
+		return errors.Wrap(err, "could not write blob archive coverage")
+	}
+	return c.fs.Rename(partPath, coverageFileName)
+}
@@ -0,0 +1,68 @@
+package filesystem
+
+import (
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/spf13/afero"
+)
+
+func TestBlobCoverage(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	c, err := newBlobCoverage(fs)
+	require.NoError(t, err)
+	low, err := c.Start(100)
+	require.NoError(t, err)
+	require.Equal(t, primitives.Slot(100), low)
+
+	a := BlobGap{Slot: 90, Root: [32]byte{'a'}, Missing: []uint64{0, 1}}
+	b := BlobGap{Slot: 70, Root: [32]byte{'b'}, Missing: []uint64{2}}
+	require.NoError(t, c.Extend(80, []BlobGap{a}))
+	require.NoError(t, c.Extend(60, []BlobGap{b}))
+	require.ErrorIs(t, c.Extend(70, nil), errCoverageNotContiguous)
+	require.ErrorIs(t, c.Extend(50, []BlobGap{{Slot: 65}}), errCoverageNotContiguous)
+	require.DeepEqual(t, []BlobGap{b, a}, c.Gaps(0, 100))
+	require.DeepEqual(t, []BlobGap{a}, c.Gaps(80, 100))
+
+	// Coverage is persisted, and a restarted archive continues from the lowest checked slot.
+	c, err = newBlobCoverage(fs)
+	require.NoError(t, err)
+	low, err = c.Start(200)
+	require.NoError(t, err)
+	require.Equal(t, primitives.Slot(60), low)
+	low, high := c.Checked()
+	require.Equal(t, primitives.Slot(60), low)
+	require.Equal(t, primitives.Slot(100), high)
+
+	a.Missing = []uint64{1}
+	require.NoError(t, c.UpdateGap(a))
+	require.DeepEqual(t, []BlobGap{a}, c.Gaps(80, 100))
+	b.Missing = nil
+	require.NoError(t, c.UpdateGap(b))
+	require.DeepEqual(t, []BlobGap{a}, c.Gaps(0, 100))
+}
+
+func TestBlobArchiveRetention(t *testing.T) {
+	bs := NewEphemeralBlobArchive(t)
+	current := primitives.Epoch(1_000_000)
+	// Nothing has been backfilled yet, so only the retention period is available.
+	require.Equal(t, false, bs.WithinRetentionPeriod(0, current))
+	require.Equal(t, true, bs.WithinRetentionPeriod(current, current))
+
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	_, err := bs.Coverage().Start(primitives.Slot(100) * slotsPerEpoch)
+	require.NoError(t, err)
+	require.Equal(t, true, bs.WithinRetentionPeriod(100, current))
+	require.Equal(t, false, bs.WithinRetentionPeriod(99, current))
+	// An epoch is only available once all of its slots have been backfilled.
+	require.NoError(t, bs.Coverage().Extend(primitives.Slot(50)*slotsPerEpoch+1, nil))
+	require.Equal(t, true, bs.WithinRetentionPeriod(51, current))
+	require.Equal(t, false, bs.WithinRetentionPeriod(50, current))
+	require.NoError(t, bs.Coverage().Extend(primitives.Slot(50)*slotsPerEpoch, nil))
+	require.Equal(t, true, bs.WithinRetentionPeriod(50, current))
+	require.Equal(t, false, bs.WithinRetentionPeriod(0, current))
+
+	require.Equal(t, false, NewEphemeralBlobStorage(t).WithinRetentionPeriod(0, current))
+}
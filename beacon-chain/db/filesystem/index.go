@@ -0,0 +1,350 @@
+package filesystem
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+	"github.com/spf13/afero"
+)
+
+const (
+	indexFileName = "blob-index"
+	// indexRecordSize is the size of an index record: a 32 byte block root, followed by the little-endian
+	// encoding of the slot and blob index.
+	indexRecordSize = 48
+	// indexTombstone is the blob index of a record that removes all the blobs of a block root from the index.
+	indexTombstone = math.MaxUint64
+	// maxIndexedBlobs is the number of blob indices that fit in the mask of an index entry.
+	maxIndexedBlobs = 64
+)
+
+type indexEntry struct {
+	slot primitives.Slot
+	mask uint64
+}
+
+func (e indexEntry) indices() []uint64 {
+	idx := make([]uint64, 0)
+	for i := uint64(0); i < maxIndexedBlobs; i++ {
+		if e.mask&(1<<i) != 0 {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// blobIndex is the on-disk index of the blob sidecars in the per-epoch layout, mapping block roots to their slot,
+// which is needed to find the epoch directory of a block root. The index is an append-only log of fixed size
+// records, with one record for each saved sidecar, that is loaded in memory at startup and compacted when
+// epochs are pruned.
+type blobIndex struct {
+	sync.RWMutex
+	fs      afero.Fs
+	fsync   bool
+	log     afero.File
+	entries map[[32]byte]indexEntry
+}
+
+func newBlobIndex(fs afero.Fs, fsync bool) (*blobIndex, error) {
+	idx := &blobIndex{fs: fs, fsync: fsync, entries: make(map[[32]byte]indexEntry)}
+	if err := idx.load(); err != nil {
+		return nil, err
+	}
+	if err := idx.open(); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// load reads the index file. A missing, unreadable, empty or corrupt index is rebuilt from the epoch directories,
+// so that the blobs on disk are not treated as absent when only the index was lost.
+func (idx *blobIndex) load() error {
+	b, err := afero.ReadFile(idx.fs, indexFileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return idx.rebuild()
+	}
+	if err != nil {
+		log.WithError(err).Warn("Could not read blob index, rebuilding it from the blob directories")
+		return idx.rebuild()
+	}
+	valid := len(b) - len(b)%indexRecordSize
+	for i := 0; i < valid; i += indexRecordSize {
+		root, slot, bi := decodeIndexRecord(b[i : i+indexRecordSize])
+		if root == [32]byte{} || (bi >= maxIndexedBlobs && bi != indexTombstone) {
+			log.WithField("offset", i).Warn("Blob index is corrupt, rebuilding it from the blob directories")
+			idx.entries = make(map[[32]byte]indexEntry)
+			return idx.rebuild()
+		}
+		idx.apply(root, slot, bi)
+	}
+	if len(idx.entries) == 0 {
+		return idx.rebuild()
+	}
+	if valid == len(b) {
+		return nil
+	}
+	// A record was only partially written before the node stopped. Drop it so that new records stay aligned.
+	log.WithField("bytes", len(b)-valid).Warn("Truncating partially written blob index record")
+	f, err := idx.fs.OpenFile(indexFileName, os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrap(err, "could not open blob index")
+	}
+	if err := f.Truncate(int64(valid)); err != nil {
+		return errors.Wrap(err, "could not truncate blob index")
+	}
+	return f.Close()
+}
+
+// rebuild indexes the blob sidecars found in the epoch directories and replaces the index file with the result.
+// The slot of a block root is read from its first sidecar, root directories whose slot can't be read are skipped.
+func (idx *blobIndex) rebuild() error {
+	epochs, err := listDir(idx.fs, ".")
+	if err != nil {
+		return errors.Wrap(err, "unable to list root blobs directory")
+	}
+	for _, epochDir := range filter(epochs, filterEpoch) {
+		roots, err := listDir(idx.fs, epochDir)
+		if err != nil {
+			return errors.Wrapf(err, "failed to list epoch directory %s", epochDir)
+		}
+		for _, rootDir := range filter(roots, filterRoot) {
+			if err := idx.rebuildRootDir(path.Join(epochDir, rootDir)); err != nil {
+				log.WithError(err).WithField("directory", rootDir).Warn("Could not index blob directory")
+			}
+		}
+	}
+	if len(idx.entries) > 0 {
+		log.WithField("roots", len(idx.entries)).Info("Rebuilt blob index from the blob directories")
+	}
+	return idx.replace()
+}
+
+func (idx *blobIndex) rebuildRootDir(dir string) error {
+	root, err := rootFromDir(path.Base(dir))
+	if err != nil {
+		return err
+	}
+	entries, err := listDir(idx.fs, dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list blobs in directory %s", dir)
+	}
+	scFiles := filter(entries, filterSsz)
+	if len(scFiles) == 0 {
+		return nil
+	}
+	slot, err := slotFromFile(path.Join(dir, scFiles[0]), idx.fs)
+	if err != nil {
+		return errors.Wrapf(err, "slot could not be read from blob file %s", scFiles[0])
+	}
+	for _, fname := range scFiles {
+		bi, err := idxFromPath(fname)
+		if err != nil {
+			return errors.Wrapf(err, "index could not be determined for blob file %s", fname)
+		}
+		if bi >= maxIndexedBlobs {
+			return errIndexOutOfBounds
+		}
+		idx.apply(root, slot, bi)
+	}
+	return nil
+}
+
+func (idx *blobIndex) open() error {
+	f, err := idx.fs.OpenFile(indexFileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrap(err, "could not open blob index")
+	}
+	idx.log = f
+	return nil
+}
+
+func (idx *blobIndex) apply(root [32]byte, slot primitives.Slot, bi uint64) {
+	if bi == indexTombstone {
+		delete(idx.entries, root)
+		return
+	}
+	e := idx.entries[root]
+	e.slot = slot
+	e.mask |= 1 << bi
+	idx.entries[root] = e
+}
+
+// slot returns the slot of the block root, if the index contains blobs for it.
+func (idx *blobIndex) slot(root [32]byte) (primitives.Slot, bool) {
+	idx.RLock()
+	defer idx.RUnlock()
+	e, ok := idx.entries[root]
+	return e.slot, ok
+}
+
+// add records the blob sidecar with the given root, slot and index. Adding a sidecar that is already in the
+// index is a no-op.
+func (idx *blobIndex) add(root [32]byte, slot primitives.Slot, bi uint64) error {
+	if bi >= maxIndexedBlobs {
+		return errIndexOutOfBounds
+	}
+	idx.Lock()
+	defer idx.Unlock()
+	if e, ok := idx.entries[root]; ok && e.mask&(1<<bi) != 0 {
+		return nil
+	}
+	if err := idx.append(root, slot, bi); err != nil {
+		return err
+	}
+	idx.apply(root, slot, bi)
+	return nil
+}
+
+// remove removes all the blob sidecars of the given root from the index.
+func (idx *blobIndex) remove(root [32]byte) error {
+	idx.Lock()
+	defer idx.Unlock()
+	e, ok := idx.entries[root]
+	if !ok {
+		return nil
+	}
+	if err := idx.append(root, e.slot, indexTombstone); err != nil {
+		return err
+	}
+	idx.apply(root, e.slot, indexTombstone)
+	return nil
+}
+
+func (idx *blobIndex) append(root [32]byte, slot primitives.Slot, bi uint64) error {
+	if _, err := idx.log.Write(encodeIndexRecord(root, slot, bi)); err != nil {
+		return errors.Wrap(err, "could not append to blob index")
+	}
+	if idx.fsync {
+		return idx.log.Sync()
+	}
+	return nil
+}
+
+// each calls fn for each block root in the index, with its slot and the indices of its blob sidecars.
+func (idx *blobIndex) each(fn func(root [32]byte, slot primitives.Slot, indices []uint64) error) error {
+	idx.RLock()
+	defer idx.RUnlock()
+	for root, e := range idx.entries {
+		if err := fn(root, e.slot, e.indices()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneBefore removes the block roots of all epochs before the given epoch from the index, and compacts the
+// index file. It returns the entries that were removed.
+func (idx *blobIndex) pruneBefore(epoch primitives.Epoch) (map[[32]byte]indexEntry, error) {
+	idx.Lock()
+	defer idx.Unlock()
+	pruned := make(map[[32]byte]indexEntry)
+	for root, e := range idx.entries {
+		if slots.ToEpoch(e.slot) < epoch {
+			pruned[root] = e
+			delete(idx.entries, root)
+		}
+	}
+	if len(pruned) == 0 {
+		return pruned, nil
+	}
+	return pruned, idx.compact()
+}
+
+// compact rewrites the index file with a single record per blob sidecar in the index, dropping the records of
+// removed roots.
+func (idx *blobIndex) compact() error {
+	if err := idx.log.Close(); err != nil {
+		return errors.Wrap(err, "could not close blob index")
+	}
+	err := idx.replace()
+	if oerr := idx.open(); oerr != nil {
+		return oerr
+	}
+	return err
+}
+
+// replace atomically replaces the index file with a single record per blob sidecar in the index. The new file is
+// synced before the rename, so that a crash can't leave an empty or partially written index in place of the old
+// one, and the directory is synced around the rename when fsync is set.
+func (idx *blobIndex) replace() error {
+	b := make([]byte, 0, len(idx.entries)*indexRecordSize)
+	for root, e := range idx.entries {
+		for _, bi := range e.indices() {
+			b = append(b, encodeIndexRecord(root, e.slot, bi)...)
+		}
+	}
+	partPath := fmt.Sprintf("%s.%s", indexFileName, partExt)
+	f, err := idx.fs.OpenFile(partPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrap(err, "could not create compacted blob index")
+	}
+	if _, err := f.Write(b); err != nil {
+		closeIndexFile(f)
+		return errors.Wrap(err, "could not write compacted blob index")
+	}
+	if err := f.Sync(); err != nil {
+		closeIndexFile(f)
+		return errors.Wrap(err, "could not sync compacted blob index")
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, "could not close compacted blob index")
+	}
+	if err := idx.syncDir(); err != nil {
+		return err
+	}
+	if err := idx.fs.Rename(partPath, indexFileName); err != nil {
+		return errors.Wrap(err, "could not replace blob index")
+	}
+	return idx.syncDir()
+}
+
+// syncDir syncs the blob storage directory when fsync is set, so that the renames of the index file are durable.
+func (idx *blobIndex) syncDir() error {
+	if !idx.fsync {
+		return nil
+	}
+	d, err := idx.fs.Open(".")
+	if err != nil {
+		return errors.Wrap(err, "could not open blob storage directory")
+	}
+	if err := d.Sync(); err != nil {
+		closeIndexFile(d)
+		return errors.Wrap(err, "could not sync blob storage directory")
+	}
+	return d.Close()
+}
+
+func closeIndexFile(f afero.File) {
+	if err := f.Close(); err != nil {
+		log.WithError(err).Error("Could not close file")
+	}
+}
+
+// reset empties the index, after all the blobs have been deleted.
+func (idx *blobIndex) reset() error {
+	idx.Lock()
+	defer idx.Unlock()
+	idx.entries = make(map[[32]byte]indexEntry)
+	return idx.compact()
+}
+
+func encodeIndexRecord(root [32]byte, slot primitives.Slot, bi uint64) []byte {
+	b := make([]byte, indexRecordSize)
+	copy(b, root[:])
+	binary.LittleEndian.PutUint64(b[32:40], uint64(slot))
+	binary.LittleEndian.PutUint64(b[40:48], bi)
+	return b
+}
+
+func decodeIndexRecord(b []byte) ([32]byte, primitives.Slot, uint64) {
+	var root [32]byte
+	copy(root[:], b[:32])
+	return root, primitives.Slot(binary.LittleEndian.Uint64(b[32:40])), binary.LittleEndian.Uint64(b[40:48])
+}
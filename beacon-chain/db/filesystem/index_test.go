@@ -0,0 +1,136 @@
+package filesystem
+
+import (
+	"os"
+
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/verification"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+	"github.com/spf13/afero"
+)
+
+func TestBlobIndex(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	idx, err := newBlobIndex(fs, false)
+	require.NoError(t, err)
+	a, b, c := [32]byte{'a'}, [32]byte{'b'}, [32]byte{'c'}
+	require.NoError(t, idx.add(a, 10, 0))
+	require.NoError(t, idx.add(a, 10, 0))
+	require.NoError(t, idx.add(a, 10, 3))
+	require.NoError(t, idx.add(b, 40, 1))
+	require.NoError(t, idx.add(c, 70, 2))
+	require.NoError(t, idx.remove(b))
+	require.ErrorIs(t, idx.add(c, 70, maxIndexedBlobs), errIndexOutOfBounds)
+
+	// Duplicate sidecars are only recorded once.
+	info, err := fs.Stat(indexFileName)
+	require.NoError(t, err)
+	require.Equal(t, int64(5*indexRecordSize), info.Size())
+
+	// Partially written records are dropped when the index is loaded.
+	f, err := fs.OpenFile(indexFileName, os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{1, 2, 3})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	idx, err = newBlobIndex(fs, false)
+	require.NoError(t, err)
+	slot, ok := idx.slot(a)
+	require.Equal(t, true, ok)
+	require.Equal(t, primitives.Slot(10), slot)
+	require.DeepEqual(t, []uint64{0, 3}, idx.entries[a].indices())
+	_, ok = idx.slot(b)
+	require.Equal(t, false, ok)
+	info, err = fs.Stat(indexFileName)
+	require.NoError(t, err)
+	require.Equal(t, int64(5*indexRecordSize), info.Size())
+
+	t.Run("prune compacts the index", func(t *testing.T) {
+		pruned, err := idx.pruneBefore(1)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(pruned))
+		require.DeepEqual(t, []uint64{0, 3}, pruned[a].indices())
+		info, err := fs.Stat(indexFileName)
+		require.NoError(t, err)
+		require.Equal(t, int64(indexRecordSize), info.Size())
+		require.NoError(t, idx.add(a, 100, 0))
+
+		idx, err := newBlobIndex(fs, false)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(idx.entries))
+		slot, ok := idx.slot(c)
+		require.Equal(t, true, ok)
+		require.Equal(t, primitives.Slot(70), slot)
+	})
+}
+
+func TestBlobIndex_Rebuild(t *testing.T) {
+	slot := primitives.Slot(100)
+	_, sidecars := util.GenerateTestDenebBlockWithSidecar(t, [32]byte{}, slot, params.BeaconConfig().MaxBlobsPerBlock(slot))
+	testSidecars, err := verification.BlobSidecarSliceNoop(sidecars)
+	require.NoError(t, err)
+	root := testSidecars[0].BlockRoot()
+
+	cases := []struct {
+		name   string
+		damage func(t *testing.T, fs afero.Fs)
+	}{
+		{
+			name: "missing",
+			damage: func(t *testing.T, fs afero.Fs) {
+				require.NoError(t, fs.Remove(indexFileName))
+			},
+		},
+		{
+			name: "empty",
+			damage: func(t *testing.T, fs afero.Fs) {
+				require.NoError(t, afero.WriteFile(fs, indexFileName, nil, 0600))
+			},
+		},
+		{
+			name: "corrupt",
+			damage: func(t *testing.T, fs afero.Fs) {
+				b, err := afero.ReadFile(fs, indexFileName)
+				require.NoError(t, err)
+				for i := indexRecordSize; i < 2*indexRecordSize; i++ {
+					b[i] = 0
+				}
+				require.NoError(t, afero.WriteFile(fs, indexFileName, b, 0600))
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fs, bs := NewEphemeralBlobStorageWithFs(t)
+			for _, sc := range testSidecars[:2] {
+				require.NoError(t, bs.Save(sc))
+			}
+			c.damage(t, fs)
+
+			bs.index = newEphemeralBlobIndex(t, fs)
+			for _, sc := range testSidecars[:2] {
+				got, err := bs.Get(root, sc.Index)
+				require.NoError(t, err)
+				require.DeepSSZEqual(t, sc.BlobSidecar, got.BlobSidecar)
+			}
+			_, err := bs.Get(root, testSidecars[2].Index)
+			require.Equal(t, true, os.IsNotExist(err))
+			indexed, ok := bs.index.slot(root)
+			require.Equal(t, true, ok)
+			require.Equal(t, slot, indexed)
+
+			// The rebuilt index is written back, so that it is not rebuilt again on the next start.
+			info, err := fs.Stat(indexFileName)
+			require.NoError(t, err)
+			require.Equal(t, int64(2*indexRecordSize), info.Size())
+			require.NoError(t, bs.Remove(root))
+			_, err = bs.Get(root, testSidecars[0].Index)
+			require.Equal(t, true, os.IsNotExist(err))
+		})
+	}
+}
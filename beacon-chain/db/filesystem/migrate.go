@@ -0,0 +1,86 @@
+package filesystem
+
+import (
+	"path"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+var errMigrationFailures = errors.New("blobs could not be migrated for some roots")
+
+// migrateFlatLayout moves the blob sidecars stored in the flat layout, with one directory per block root at the top
+// of the blob storage, into the per-epoch layout, and adds them to the blob index. Directories that can't be
+// migrated are left in place.
+func migrateFlatLayout(fs afero.Fs, index *blobIndex) error {
+	entries, err := listDir(fs, ".")
+	if err != nil {
+		return errors.Wrap(err, "unable to list root blobs directory")
+	}
+	dirs := filter(entries, filterRoot)
+	if len(dirs) == 0 {
+		return nil
+	}
+	start := time.Now()
+	log.WithField("directories", len(dirs)).Info("Migrating blob storage to per-epoch directories")
+	totalMigrated, totalErr := 0, 0
+	for _, dir := range dirs {
+		migrated, err := migrateRootDir(fs, index, dir)
+		if err != nil {
+			totalErr += 1
+			log.WithError(err).WithField("directory", dir).Error("Unable to migrate blob directory")
+		}
+		totalMigrated += migrated
+	}
+	log.WithFields(logrus.Fields{
+		"duration":      time.Since(start).String(),
+		"filesMigrated": totalMigrated,
+	}).Info("Blob storage migration complete")
+	if totalErr > 0 {
+		return errors.Wrapf(errMigrationFailures, "migration failed for %d root directories", totalErr)
+	}
+	return nil
+}
+
+func migrateRootDir(fs afero.Fs, index *blobIndex, dir string) (int, error) {
+	root, err := rootFromDir(dir)
+	if err != nil {
+		return 0, err
+	}
+	entries, err := listDir(fs, dir)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to list blobs in directory %s", dir)
+	}
+	scFiles := filter(entries, filterSsz)
+	if len(scFiles) == 0 {
+		return 0, fs.RemoveAll(dir)
+	}
+	slot, err := slotFromFile(path.Join(dir, scFiles[0]), fs)
+	if err != nil {
+		return 0, errors.Wrapf(err, "slot could not be read from blob file %s", scFiles[0])
+	}
+	migrated := 0
+	for _, fname := range scFiles {
+		idx, err := idxFromPath(fname)
+		if err != nil {
+			return migrated, errors.Wrapf(err, "index could not be determined for blob file %s", fname)
+		}
+		n := newBlobNamer(root, slot, idx)
+		if err := fs.MkdirAll(n.dir(), directoryPermissions); err != nil {
+			return migrated, err
+		}
+		// The sidecar is indexed before it is moved, so that a migration interrupted in between is completed
+		// on the next start, rather than leaving a sidecar that is missing from the index.
+		if err := index.add(root, slot, idx); err != nil {
+			return migrated, err
+		}
+		if err := fs.Rename(path.Join(dir, fname), n.path()); err != nil {
+			return migrated, errors.Wrapf(err, "unable to move blob file %s", fname)
+		}
+		migrated += 1
+	}
+	// Only dangling .part files are left.
+	return migrated, fs.RemoveAll(dir)
+}
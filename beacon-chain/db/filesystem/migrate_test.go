@@ -0,0 +1,56 @@
+package filesystem
+
+import (
+	"path"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/verification"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+	"github.com/spf13/afero"
+)
+
+func TestMigrateFlatLayout(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, sidecars := util.GenerateTestDenebBlockWithSidecar(t, [32]byte{}, 100, 2)
+	scs, err := verification.BlobSidecarSliceNoop(sidecars)
+	require.NoError(t, err)
+	root := scs[0].BlockRoot()
+	legacy := rootString(root)
+	require.NoError(t, fs.MkdirAll(legacy, directoryPermissions))
+	for _, sc := range scs {
+		b, err := sc.MarshalSSZ()
+		require.NoError(t, err)
+		require.NoError(t, afero.WriteFile(fs, path.Join(legacy, path.Base(namerForSidecar(sc).path())), b, 0600))
+	}
+	require.NoError(t, afero.WriteFile(fs, path.Join(legacy, "abc-0.part"), []byte("derp"), 0600))
+	// Directories without blobs are removed.
+	require.NoError(t, fs.MkdirAll(rootString([32]byte{'e'}), directoryPermissions))
+	// Directories with corrupt blobs are left in place.
+	corrupt := rootString([32]byte{'c'})
+	require.NoError(t, fs.MkdirAll(corrupt, directoryPermissions))
+	require.NoError(t, afero.WriteFile(fs, path.Join(corrupt, "0.ssz"), []byte("derp"), 0600))
+
+	index, err := newBlobIndex(fs, false)
+	require.NoError(t, err)
+	require.ErrorIs(t, migrateFlatLayout(fs, index), errMigrationFailures)
+
+	entries, err := listDir(fs, ".")
+	require.NoError(t, err)
+	require.DeepEqual(t, []string{corrupt}, filter(entries, filterRoot))
+	bs := &BlobStorage{fs: fs, index: index}
+	for _, sc := range scs {
+		got, err := bs.Get(root, sc.Index)
+		require.NoError(t, err)
+		require.DeepSSZEqual(t, sc.BlobSidecar, got.BlobSidecar)
+	}
+	mask, err := bs.Indices(root, 100)
+	require.NoError(t, err)
+	require.Equal(t, true, mask[0] && mask[1])
+
+	// The migrated blobs are in the index after a restart.
+	index, err = newBlobIndex(fs, false)
+	require.NoError(t, err)
+	_, ok := index.slot(root)
+	require.Equal(t, true, ok)
+}
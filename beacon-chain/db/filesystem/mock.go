@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
 	"github.com/spf13/afero"
 )
 
@@ -11,23 +12,43 @@ import (
 // The instance of BlobStorage returned is backed by an in-memory virtual filesystem,
 // improving test performance and simplifying cleanup.
 func NewEphemeralBlobStorage(t testing.TB) *BlobStorage {
+	_, bs := NewEphemeralBlobStorageWithFs(t)
+	return bs
+}
+
+// NewEphemeralBlobArchive returns an in-memory BlobStorage in archive mode, for tests.
+func NewEphemeralBlobArchive(t testing.TB) *BlobStorage {
 	fs := afero.NewMemMapFs()
-	pruner, err := newBlobPruner(fs, params.BeaconConfig().MinEpochsForBlobsSidecarsRequest, withWarmedCache())
+	index := newEphemeralBlobIndex(t, fs)
+	pruner, err := newBlobPruner(fs, index, params.BeaconConfig().MinEpochsForBlobsSidecarsRequest, withWarmedCache(), withPruningDisabled())
+	if err != nil {
+		t.Fatal("test setup issue", err)
+	}
+	coverage, err := newBlobCoverage(fs)
 	if err != nil {
 		t.Fatal("test setup issue", err)
 	}
-	return &BlobStorage{fs: fs, pruner: pruner}
+	return &BlobStorage{fs: fs, index: index, pruner: pruner, archive: true, coverage: coverage}
 }
 
 // NewEphemeralBlobStorageWithFs can be used by tests that want access to the virtual filesystem
 // in order to interact with it outside the parameters of the BlobStorage api.
 func NewEphemeralBlobStorageWithFs(t testing.TB) (afero.Fs, *BlobStorage) {
 	fs := afero.NewMemMapFs()
-	pruner, err := newBlobPruner(fs, params.BeaconConfig().MinEpochsForBlobsSidecarsRequest, withWarmedCache())
+	index := newEphemeralBlobIndex(t, fs)
+	pruner, err := newBlobPruner(fs, index, params.BeaconConfig().MinEpochsForBlobsSidecarsRequest, withWarmedCache())
+	if err != nil {
+		t.Fatal("test setup issue", err)
+	}
+	return fs, &BlobStorage{fs: fs, index: index, pruner: pruner}
+}
+
+func newEphemeralBlobIndex(t testing.TB, fs afero.Fs) *blobIndex {
+	index, err := newBlobIndex(fs, false)
 	if err != nil {
 		t.Fatal("test setup issue", err)
 	}
-	return fs, &BlobStorage{fs: fs, pruner: pruner}
+	return index
 }
 
 type BlobMocker struct {
@@ -36,10 +57,10 @@ type BlobMocker struct {
 }
 
 // CreateFakeIndices creates empty blob sidecar files at the expected path for the given
-// root and indices to influence the result of Indices().
-func (bm *BlobMocker) CreateFakeIndices(root [32]byte, indices ...uint64) error {
+// root, slot and indices to influence the result of Indices().
+func (bm *BlobMocker) CreateFakeIndices(root [32]byte, slot primitives.Slot, indices ...uint64) error {
 	for i := range indices {
-		n := blobNamer{root: root, index: indices[i]}
+		n := newBlobNamer(root, slot, indices[i])
 		if err := bm.fs.MkdirAll(n.dir(), directoryPermissions); err != nil {
 			return err
 		}
@@ -56,9 +77,9 @@ func (bm *BlobMocker) CreateFakeIndices(root [32]byte, indices ...uint64) error
 
 // NewEphemeralBlobStorageWithMocker returns a *BlobMocker value in addition to the BlobStorage value.
 // BlockMocker encapsulates things blob path construction to avoid leaking implementation details.
-func NewEphemeralBlobStorageWithMocker(_ testing.TB) (*BlobMocker, *BlobStorage) {
+func NewEphemeralBlobStorageWithMocker(t testing.TB) (*BlobMocker, *BlobStorage) {
 	fs := afero.NewMemMapFs()
-	bs := &BlobStorage{fs: fs}
+	bs := &BlobStorage{fs: fs, index: newEphemeralBlobIndex(t, fs)}
 	return &BlobMocker{fs: fs, bs: bs}, bs
 }
 
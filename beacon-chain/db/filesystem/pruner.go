@@ -34,7 +34,9 @@ type blobPruner struct {
 	cache        *blobStorageCache
 	cacheReady   chan struct{}
 	warmed       bool
+	disabled     bool
 	fs           afero.Fs
+	index        *blobIndex
 }
 
 type prunerOpt func(*blobPruner) error
@@ -45,13 +47,21 @@ func withWarmedCache() prunerOpt {
 	}
 }
 
-func newBlobPruner(fs afero.Fs, retain primitives.Epoch, opts ...prunerOpt) (*blobPruner, error) {
+// withPruningDisabled keeps the pruner cache up to date without ever deleting blobs.
+func withPruningDisabled() prunerOpt {
+	return func(p *blobPruner) error {
+		p.disabled = true
+		return nil
+	}
+}
+
+func newBlobPruner(fs afero.Fs, index *blobIndex, retain primitives.Epoch, opts ...prunerOpt) (*blobPruner, error) {
 	r, err := slots.EpochStart(retain + retentionBuffer)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not set retentionSlots")
 	}
 	cw := make(chan struct{})
-	p := &blobPruner{fs: fs, windowSize: r, cache: newBlobStorageCache(), cacheReady: cw}
+	p := &blobPruner{fs: fs, index: index, windowSize: r, cache: newBlobStorageCache(), cacheReady: cw}
 	for _, o := range opts {
 		if err := o(p); err != nil {
 			return nil, err
@@ -66,6 +76,9 @@ func (p *blobPruner) notify(root [32]byte, latest primitives.Slot, idx uint64) e
 	if err := p.cache.ensure(root, latest, idx); err != nil {
 		return err
 	}
+	if p.disabled {
+		return nil
+	}
 	pruned := uint64(windowMin(latest, p.windowSize))
 	if p.prunedBefore.Swap(pruned) == pruned {
 		return nil
@@ -89,6 +102,7 @@ func windowMin(latest, offset primitives.Slot) primitives.Slot {
 	return latest - offset
 }
 
+// warmCache populates the cache from the blob index, which avoids listing the blob storage directories.
 func (p *blobPruner) warmCache() error {
 	p.Lock()
 	defer func() {
@@ -98,9 +112,24 @@ func (p *blobPruner) warmCache() error {
 		}
 		p.Unlock()
 	}()
-	if err := p.prune(0); err != nil {
+	start := time.Now()
+	failed := 0
+	err := p.index.each(func(root [32]byte, slot primitives.Slot, indices []uint64) error {
+		for _, idx := range indices {
+			if err := p.cache.ensure(root, slot, idx); err != nil {
+				failed += 1
+				log.WithError(err).WithField("root", rootString(root)).Error("Unable to add indexed blob to pruner cache")
+			}
+		}
+		return nil
+	})
+	if err != nil {
 		return err
 	}
+	log.WithField("duration", time.Since(start).String()).Debug("Warmed up pruner cache")
+	if failed > 0 {
+		return errors.Wrapf(errPruningFailures, "%d indexed blobs could not be cached", failed)
+	}
 	return nil
 }
 
@@ -116,114 +145,53 @@ func (p *blobPruner) waitForCache(ctx context.Context) (*blobStorageCache, error
 // Prune prunes blobs in the base directory based on the retention epoch.
 // It deletes blobs older than currentEpoch - (retentionEpochs+bufferEpochs).
 // This is so that we keep a slight buffer and blobs are deleted after n+2 epochs.
+// Since blobs are grouped by epoch, whole epoch directories are deleted.
 func (p *blobPruner) prune(pruneBefore primitives.Slot) error {
 	start := time.Now()
 	totalPruned, totalErr := 0, 0
-	// Customize logging/metrics behavior for the initial cache warmup when slot=0.
-	// We'll never see a prune request for slot 0, unless this is the initial call to warm up the cache.
-	if pruneBefore == 0 {
-		defer func() {
-			log.WithField("duration", time.Since(start).String()).Debug("Warmed up pruner cache")
-		}()
-	} else {
-		defer func() {
-			log.WithFields(logrus.Fields{
-				"upToEpoch":    slots.ToEpoch(pruneBefore),
-				"duration":     time.Since(start).String(),
-				"filesRemoved": totalPruned,
-			}).Debug("Pruned old blobs")
-			blobsPrunedCounter.Add(float64(totalPruned))
-		}()
-	}
-
-	entries, err := listDir(p.fs, ".")
-	if err != nil {
-		return errors.Wrap(err, "unable to list root blobs directory")
-	}
-	dirs := filter(entries, filterRoot)
-	for _, dir := range dirs {
-		pruned, err := p.tryPruneDir(dir, pruneBefore)
-		if err != nil {
-			totalErr += 1
-			log.WithError(err).WithField("directory", dir).Error("Unable to prune directory")
-		}
-		totalPruned += pruned
-	}
-
-	if totalErr > 0 {
-		return errors.Wrapf(errPruningFailures, "pruning failed for %d root directories", totalErr)
-	}
-	return nil
-}
-
-func shouldRetain(slot, pruneBefore primitives.Slot) bool {
-	return slot >= pruneBefore
-}
+	before := slots.ToEpoch(pruneBefore)
+	defer func() {
+		log.WithFields(logrus.Fields{
+			"upToEpoch":    before,
+			"duration":     time.Since(start).String(),
+			"filesRemoved": totalPruned,
+		}).Debug("Pruned old blobs")
+		blobsPrunedCounter.Add(float64(totalPruned))
+	}()
 
-func (p *blobPruner) tryPruneDir(dir string, pruneBefore primitives.Slot) (int, error) {
-	root, err := rootFromDir(dir)
+	// Roots are removed from the index before their directories are deleted, so that the index never refers to
+	// deleted blobs. Directories left behind by an interrupted prune are deleted by the next one.
+	pruned, err := p.index.pruneBefore(before)
 	if err != nil {
-		return 0, errors.Wrapf(err, "invalid directory, could not parse subdir as root %s", dir)
+		return errors.Wrap(err, "unable to prune blob index")
 	}
-	slot, slotCached := p.cache.slot(root)
-	// Return early if the slot is cached and doesn't need pruning.
-	if slotCached && shouldRetain(slot, pruneBefore) {
-		return 0, nil
+	for root, e := range pruned {
+		totalPruned += len(e.indices())
+		p.cache.evict(root)
 	}
 
-	// entries will include things that aren't ssz files, like dangling .part files. We need these to
-	// completely clean up the directory.
-	entries, err := listDir(p.fs, dir)
+	entries, err := listDir(p.fs, ".")
 	if err != nil {
-		return 0, errors.Wrapf(err, "failed to list blobs in directory %s", dir)
-	}
-	// scFiles filters the dir listing down to the ssz encoded BlobSidecar files. This allows us to peek
-	// at the first one in the list to figure out the slot.
-	scFiles := filter(entries, filterSsz)
-	if len(scFiles) == 0 {
-		log.WithField("dir", dir).Warn("Pruner ignoring directory with no blob files")
-		return 0, nil
+		return errors.Wrap(err, "unable to list root blobs directory")
 	}
-	if !slotCached {
-		slot, err = slotFromFile(path.Join(dir, scFiles[0]), p.fs)
+	for _, dir := range filter(entries, filterEpoch) {
+		epoch, err := epochFromDir(dir)
 		if err != nil {
-			return 0, errors.Wrapf(err, "slot could not be read from blob file %s", scFiles[0])
+			return err
 		}
-		for i := range scFiles {
-			idx, err := idxFromPath(scFiles[i])
-			if err != nil {
-				return 0, errors.Wrapf(err, "index could not be determined for blob file %s", scFiles[i])
-			}
-			if err := p.cache.ensure(root, slot, idx); err != nil {
-				return 0, errors.Wrapf(err, "could not update prune cache for blob file %s", scFiles[i])
-			}
+		if epoch >= before {
+			continue
 		}
-		if shouldRetain(slot, pruneBefore) {
-			return 0, nil
+		if err := p.fs.RemoveAll(dir); err != nil {
+			totalErr += 1
+			log.WithError(err).WithField("directory", dir).Error("Unable to prune epoch directory")
 		}
 	}
 
-	removed := 0
-	for _, fname := range entries {
-		fullName := path.Join(dir, fname)
-		if err := p.fs.Remove(fullName); err != nil {
-			return removed, errors.Wrapf(err, "unable to remove %s", fullName)
-		}
-		// Don't count other files that happen to be in the dir, like dangling .part files.
-		if filterSsz(fname) {
-			removed += 1
-		}
-		// Log a warning whenever we clean up a .part file
-		if filterPart(fullName) {
-			log.WithField("file", fullName).Warn("Deleting abandoned blob .part file")
-		}
-	}
-	if err := p.fs.Remove(dir); err != nil {
-		return removed, errors.Wrapf(err, "unable to remove blob directory %s", dir)
+	if totalErr > 0 {
+		return errors.Wrapf(errPruningFailures, "pruning failed for %d epoch directories", totalErr)
 	}
-
-	p.cache.evict(root)
-	return len(scFiles), nil
+	return nil
 }
 
 func idxFromPath(fname string) (uint64, error) {
@@ -239,6 +207,14 @@ func idxFromPath(fname string) (uint64, error) {
 	return strconv.ParseUint(parts[0], 10, 64)
 }
 
+func epochFromDir(dir string) (primitives.Epoch, error) {
+	epoch, err := strconv.ParseUint(filepath.Base(dir), 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid directory, could not parse subdir as epoch %s", dir)
+	}
+	return primitives.Epoch(epoch), nil
+}
+
 func rootFromDir(dir string) ([32]byte, error) {
 	subdir := filepath.Base(dir) // end of the path should be the blob directory, named by hex encoding of root
 	root, err := stringToRoot(subdir)
@@ -307,6 +283,11 @@ func filterRoot(s string) bool {
 	return strings.HasPrefix(s, "0x")
 }
 
+func filterEpoch(s string) bool {
+	_, err := strconv.ParseUint(s, 10, 64)
+	return err == nil
+}
+
 var dotSszExt = "." + sszExt
 var dotPartExt = "." + partExt
 
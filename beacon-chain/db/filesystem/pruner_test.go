@@ -20,37 +20,67 @@ import (
 	"github.com/spf13/afero"
 )
 
-func TestTryPruneDir_CachedNotExpired(t *testing.T) {
-	fs := afero.NewMemMapFs()
-	pr, err := newBlobPruner(fs, 0)
+func TestPrune_EpochDirectories(t *testing.T) {
+	fs, bs := NewEphemeralBlobStorageWithFs(t)
+	spe := params.BeaconConfig().SlotsPerEpoch
+	_, expired := util.GenerateTestDenebBlockWithSidecar(t, [32]byte{}, spe-1, 2)
+	_, retained := util.GenerateTestDenebBlockWithSidecar(t, [32]byte{}, spe, 2)
+	expiredScs, err := verification.BlobSidecarSliceNoop(expired)
+	require.NoError(t, err)
+	retainedScs, err := verification.BlobSidecarSliceNoop(retained)
+	require.NoError(t, err)
+	for i := range expiredScs {
+		require.NoError(t, bs.Save(expiredScs[i]))
+		require.NoError(t, bs.Save(retainedScs[i]))
+	}
+	// An epoch directory that isn't in the index, like one left behind by an interrupted prune.
+	require.NoError(t, fs.MkdirAll(path.Join(epochString(0), "0xdeadbeef"), directoryPermissions))
+
+	require.NoError(t, bs.pruner.prune(spe))
+
+	epochs, err := listDir(fs, ".")
+	require.NoError(t, err)
+	require.DeepEqual(t, []string{epochString(1)}, filter(epochs, filterEpoch))
+	_, ok := bs.pruner.cache.slot(expiredScs[0].BlockRoot())
+	require.Equal(t, false, ok)
+	_, ok = bs.index.slot(expiredScs[0].BlockRoot())
+	require.Equal(t, false, ok)
+	files, err := listDir(fs, namerForSidecar(retainedScs[0]).dir())
+	require.NoError(t, err)
+	require.Equal(t, 2, len(files))
+	_, err = bs.Get(retainedScs[1].BlockRoot(), 1)
+	require.NoError(t, err)
+}
+
+func TestWarmCache_FromIndex(t *testing.T) {
+	fs, bs := NewEphemeralBlobStorageWithFs(t)
+	_, sidecars := util.GenerateTestDenebBlockWithSidecar(t, [32]byte{}, 100, 3)
+	scs, err := verification.BlobSidecarSliceNoop(sidecars)
 	require.NoError(t, err)
-	slot := pr.windowSize
-	_, sidecars := util.GenerateTestDenebBlockWithSidecar(t, [32]byte{}, slot, params.BeaconConfig().MaxBlobsPerBlock(slot))
-	sc, err := verification.BlobSidecarNoop(sidecars[0])
+	require.NoError(t, bs.Save(scs[0]))
+	require.NoError(t, bs.Save(scs[2]))
+
+	// Simulate a restart by loading the index and warming a new pruner from the same filesystem.
+	index, err := newBlobIndex(fs, false)
 	require.NoError(t, err)
-	rootStr := rootString(sc.BlockRoot())
-	// This slot is right on the edge of what would need to be pruned, so by adding it to the cache and
-	// skipping any other test setup, we can be certain the hot cache path never touches the filesystem.
-	require.NoError(t, pr.cache.ensure(sc.BlockRoot(), sc.Slot(), 0))
-	pruned, err := pr.tryPruneDir(rootStr, pr.windowSize)
+	pr, err := newBlobPruner(fs, index, 0)
 	require.NoError(t, err)
-	require.Equal(t, 0, pruned)
+	require.NoError(t, pr.warmCache())
+	sum := pr.cache.Summary(scs[0].BlockRoot())
+	require.Equal(t, primitives.Slot(100), sum.slot)
+	require.Equal(t, true, sum.HasIndex(0))
+	require.Equal(t, false, sum.HasIndex(1))
+	require.Equal(t, true, sum.HasIndex(2))
 }
 
 func TestCacheWarmFail(t *testing.T) {
 	fs := afero.NewMemMapFs()
-	n := blobNamer{root: bytesutil.ToBytes32([]byte("derp")), index: 0}
-	bp := n.path()
-	mkdir := path.Dir(bp)
-	require.NoError(t, fs.MkdirAll(mkdir, directoryPermissions))
-
-	// Create an empty blob index in the fs by touching the file at a seemingly valid path.
-	fi, err := fs.Create(bp)
+	index, err := newBlobIndex(fs, false)
 	require.NoError(t, err)
-	require.NoError(t, fi.Close())
+	// The index can hold more blobs than a block may have, so the cache rejects this entry.
+	require.NoError(t, index.add(bytesutil.ToBytes32([]byte("derp")), 0, uint64(params.BeaconConfig().MaxBlobsPerBlock(0))))
 
-	// Cache warm should fail due to the unexpected EOF.
-	pr, err := newBlobPruner(fs, 0)
+	pr, err := newBlobPruner(fs, index, 0)
 	require.NoError(t, err)
 	require.ErrorIs(t, pr.warmCache(), errPruningFailures)
 
@@ -65,123 +95,6 @@ func TestCacheWarmFail(t *testing.T) {
 	require.NotNil(t, c)
 }
 
-func TestTryPruneDir_CachedExpired(t *testing.T) {
-	t.Run("empty directory", func(t *testing.T) {
-		fs := afero.NewMemMapFs()
-		pr, err := newBlobPruner(fs, 0)
-		require.NoError(t, err)
-		var slot primitives.Slot = 0
-		_, sidecars := util.GenerateTestDenebBlockWithSidecar(t, [32]byte{}, slot, 1)
-		sc, err := verification.BlobSidecarNoop(sidecars[0])
-		require.NoError(t, err)
-		rootStr := rootString(sc.BlockRoot())
-		require.NoError(t, fs.Mkdir(rootStr, directoryPermissions)) // make empty directory
-		require.NoError(t, pr.cache.ensure(sc.BlockRoot(), sc.Slot(), 0))
-		pruned, err := pr.tryPruneDir(rootStr, slot+1)
-		require.NoError(t, err)
-		require.Equal(t, 0, pruned)
-	})
-	t.Run("blobs to delete", func(t *testing.T) {
-		fs, bs := NewEphemeralBlobStorageWithFs(t)
-		var slot primitives.Slot = 0
-		_, sidecars := util.GenerateTestDenebBlockWithSidecar(t, [32]byte{}, slot, 2)
-		scs, err := verification.BlobSidecarSliceNoop(sidecars)
-		require.NoError(t, err)
-
-		require.NoError(t, bs.Save(scs[0]))
-		require.NoError(t, bs.Save(scs[1]))
-
-		// check that the root->slot is cached
-		root := scs[0].BlockRoot()
-		rootStr := rootString(root)
-		cs, cok := bs.pruner.cache.slot(scs[0].BlockRoot())
-		require.Equal(t, true, cok)
-		require.Equal(t, slot, cs)
-
-		// ensure that we see the saved files in the filesystem
-		files, err := listDir(fs, rootStr)
-		require.NoError(t, err)
-		require.Equal(t, 2, len(files))
-
-		pruned, err := bs.pruner.tryPruneDir(rootStr, slot+1)
-		require.NoError(t, err)
-		require.Equal(t, 2, pruned)
-		files, err = listDir(fs, rootStr)
-		require.ErrorIs(t, err, os.ErrNotExist)
-		require.Equal(t, 0, len(files))
-	})
-}
-
-func TestTryPruneDir_SlotFromFile(t *testing.T) {
-	t.Run("expired blobs deleted", func(t *testing.T) {
-		fs, bs := NewEphemeralBlobStorageWithFs(t)
-		var slot primitives.Slot = 0
-		_, sidecars := util.GenerateTestDenebBlockWithSidecar(t, [32]byte{}, slot, 2)
-		scs, err := verification.BlobSidecarSliceNoop(sidecars)
-		require.NoError(t, err)
-
-		require.NoError(t, bs.Save(scs[0]))
-		require.NoError(t, bs.Save(scs[1]))
-
-		// check that the root->slot is cached
-		root := scs[0].BlockRoot()
-		rootStr := rootString(root)
-		cs, ok := bs.pruner.cache.slot(root)
-		require.Equal(t, true, ok)
-		require.Equal(t, slot, cs)
-		// evict it from the cache so that we trigger the file read path
-		bs.pruner.cache.evict(root)
-		_, ok = bs.pruner.cache.slot(root)
-		require.Equal(t, false, ok)
-
-		// ensure that we see the saved files in the filesystem
-		files, err := listDir(fs, rootStr)
-		require.NoError(t, err)
-		require.Equal(t, 2, len(files))
-
-		pruned, err := bs.pruner.tryPruneDir(rootStr, slot+1)
-		require.NoError(t, err)
-		require.Equal(t, 2, pruned)
-		files, err = listDir(fs, rootStr)
-		require.ErrorIs(t, err, os.ErrNotExist)
-		require.Equal(t, 0, len(files))
-	})
-	t.Run("not expired, intact", func(t *testing.T) {
-		fs, bs := NewEphemeralBlobStorageWithFs(t)
-		// Set slot equal to the window size, so it should be retained.
-		slot := bs.pruner.windowSize
-		_, sidecars := util.GenerateTestDenebBlockWithSidecar(t, [32]byte{}, slot, 2)
-		scs, err := verification.BlobSidecarSliceNoop(sidecars)
-		require.NoError(t, err)
-
-		require.NoError(t, bs.Save(scs[0]))
-		require.NoError(t, bs.Save(scs[1]))
-
-		// Evict slot mapping from the cache so that we trigger the file read path.
-		root := scs[0].BlockRoot()
-		rootStr := rootString(root)
-		bs.pruner.cache.evict(root)
-		_, ok := bs.pruner.cache.slot(root)
-		require.Equal(t, false, ok)
-
-		// Ensure that we see the saved files in the filesystem.
-		files, err := listDir(fs, rootStr)
-		require.NoError(t, err)
-		require.Equal(t, 2, len(files))
-
-		// This should use the slotFromFile code (simulating restart).
-		// Setting pruneBefore == slot, so that the slot will be outside the window (at the boundary).
-		pruned, err := bs.pruner.tryPruneDir(rootStr, slot)
-		require.NoError(t, err)
-		require.Equal(t, 0, pruned)
-
-		// Ensure files are still present.
-		files, err = listDir(fs, rootStr)
-		require.NoError(t, err)
-		require.Equal(t, 2, len(files))
-	})
-}
-
 func TestSlotFromBlob(t *testing.T) {
 	cases := []struct {
 		slot primitives.Slot
@@ -11,6 +11,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/filters"
 	slashertypes "github.com/prysmaticlabs/prysm/v5/beacon-chain/slasher/types"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/interfaces"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
@@ -64,6 +65,12 @@ type ReadOnlyDatabase interface {
 	// origin checkpoint sync support
 	OriginCheckpointBlockRoot(ctx context.Context) ([32]byte, error)
 	BackfillStatus(context.Context) (*dbval.BackfillStatus, error)
+	// Historical validator identity index operations.
+	ValidatorIdentity(ctx context.Context, idx primitives.ValidatorIndex) (*dbval.ValidatorIdentity, error)
+	ValidatorIdentityByPublicKey(ctx context.Context, pubkey [fieldparams.BLSPubkeyLength]byte) (*dbval.ValidatorIdentity, error)
+	ValidatorIdentitiesByWithdrawalAddress(ctx context.Context, address common.Address) ([]*dbval.ValidatorIdentity, error)
+	ValidatorIdentityIndexEpoch(ctx context.Context) (primitives.Epoch, error)
+	ValidatorIdentityIndexStartEpoch(ctx context.Context) (primitives.Epoch, error)
 }
 
 // NoHeadAccessDatabase defines a struct without access to chain head data.
@@ -99,6 +106,8 @@ type NoHeadAccessDatabase interface {
 	// light client operations
 	SaveLightClientUpdate(ctx context.Context, period uint64, update interfaces.LightClientUpdate) error
 	SaveLightClientBootstrap(ctx context.Context, blockRoot []byte, bootstrap interfaces.LightClientBootstrap) error
+	// Historical validator identity index operations.
+	SaveValidatorIdentities(ctx context.Context, st state.ReadOnlyBeaconState) error
 
 	CleanUpDirtyStates(ctx context.Context, slotsPerArchivedPoint primitives.Slot) error
 }
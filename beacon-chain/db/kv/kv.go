@@ -115,6 +115,10 @@ var Buckets = [][]byte{
 	blockParentRootIndicesBucket,
 	finalizedBlockRootsIndexBucket,
 	blockRootValidatorHashesBucket,
+	validatorIdentityBucket,
+	validatorPubkeyIndicesBucket,
+	withdrawalAddressIndicesBucket,
+	validatorIdentityDigestBucket,
 	// Migrations
 	migrationsBucket,
 
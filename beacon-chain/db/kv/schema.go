@@ -33,14 +33,22 @@ var (
 	finalizedBlockRootsIndexBucket = []byte("finalized-block-roots-index")
 	blockRootValidatorHashesBucket = []byte("block-root-validator-hashes")
 
+	// Historical validator identity index buckets.
+	validatorIdentityBucket        = []byte("validator-identity")
+	validatorPubkeyIndicesBucket   = []byte("validator-pubkey-indices")
+	withdrawalAddressIndicesBucket = []byte("withdrawal-address-indices")
+	validatorIdentityDigestBucket  = []byte("validator-identity-digests")
+
 	// Specific item keys.
-	headBlockRootKey           = []byte("head-root")
-	genesisBlockRootKey        = []byte("genesis-root")
-	depositContractAddressKey  = []byte("deposit-contract")
-	justifiedCheckpointKey     = []byte("justified-checkpoint")
-	finalizedCheckpointKey     = []byte("finalized-checkpoint")
-	powchainDataKey            = []byte("powchain-data")
-	lastValidatedCheckpointKey = []byte("last-validated-checkpoint")
+	headBlockRootKey               = []byte("head-root")
+	genesisBlockRootKey            = []byte("genesis-root")
+	depositContractAddressKey      = []byte("deposit-contract")
+	justifiedCheckpointKey         = []byte("justified-checkpoint")
+	finalizedCheckpointKey         = []byte("finalized-checkpoint")
+	powchainDataKey                = []byte("powchain-data")
+	lastValidatedCheckpointKey     = []byte("last-validated-checkpoint")
+	validatorIdentityEpochKey      = []byte("validator-identity-epoch")
+	validatorIdentityStartEpochKey = []byte("validator-identity-start-epoch")
 
 	// Below keys are used to identify objects are to be fork compatible.
 	// Objects that are only compatible with specific forks should be prefixed with such keys.
@@ -0,0 +1,249 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	"github.com/prysmaticlabs/prysm/v5/proto/dbval"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+)
+
+// validatorIdentityDigestSize is the size of the digest of the indexed fields of a validator: the big-endian
+// activation and exit epochs, followed by the withdrawal credentials.
+const validatorIdentityDigestSize = 48
+
+// ErrNotFoundValidatorIdentity is a not found error specifically for the validator identity getters.
+var ErrNotFoundValidatorIdentity = errors.Wrap(ErrNotFound, "validator identity")
+
+// validatorIdentityBatchSize bounds the number of validators indexed in one transaction, so that indexing the whole
+// registry, when the index is first enabled, does not hold the single writer lock of the database for long.
+var validatorIdentityBatchSize = 10_000
+
+// SaveValidatorIdentities updates the historical validator identity index with the validators of the given finalized
+// state. New validators are indexed by index, public key and withdrawal address, the activation and exit epochs of
+// known validators are updated, and changed withdrawal credentials are appended to the credentials history of the
+// validator. Only validators whose indexed fields changed since the latest indexed state are rewritten. States that
+// are not newer than the latest indexed state are ignored. Validators are indexed in batches of bounded size, each in
+// its own transaction, and the indexed epoch is only updated once every batch is saved; callers must serialize the
+// updates, so that credential changes are not appended out of order.
+func (s *Store) SaveValidatorIdentities(ctx context.Context, st state.ReadOnlyBeaconState) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveValidatorIdentities")
+	defer span.End()
+
+	epoch := slots.ToEpoch(st.Slot())
+	var indexed bool
+	if err := s.db.View(func(tx *bolt.Tx) error {
+		enc := tx.Bucket(chainMetadataBucket).Get(validatorIdentityEpochKey)
+		indexed = enc != nil && primitives.Epoch(bytesutil.BytesToUint64BigEndian(enc)) >= epoch
+		return nil
+	}); err != nil {
+		return err
+	}
+	if indexed {
+		return nil
+	}
+	n := st.NumValidators()
+	for start := 0; start < n; start += validatorIdentityBatchSize {
+		end := min(start+validatorIdentityBatchSize, n)
+		if err := s.db.Update(func(tx *bolt.Tx) error {
+			return saveValidatorIdentities(ctx, tx, st, epoch, start, end)
+		}); err != nil {
+			return err
+		}
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		metadata := tx.Bucket(chainMetadataBucket)
+		if enc := metadata.Get(validatorIdentityEpochKey); enc != nil && primitives.Epoch(bytesutil.BytesToUint64BigEndian(enc)) >= epoch {
+			return nil
+		}
+		if metadata.Get(validatorIdentityStartEpochKey) == nil {
+			if err := metadata.Put(validatorIdentityStartEpochKey, bytesutil.Uint64ToBytesBigEndian(uint64(epoch))); err != nil {
+				return err
+			}
+		}
+		return metadata.Put(validatorIdentityEpochKey, bytesutil.Uint64ToBytesBigEndian(uint64(epoch)))
+	})
+}
+
+// saveValidatorIdentities indexes the validators of the state with indices in [start, end).
+func saveValidatorIdentities(ctx context.Context, tx *bolt.Tx, st state.ReadOnlyBeaconState, epoch primitives.Epoch, start, end int) error {
+	identities := tx.Bucket(validatorIdentityBucket)
+	digests := tx.Bucket(validatorIdentityDigestBucket)
+	pubkeys := tx.Bucket(validatorPubkeyIndicesBucket)
+	addresses := tx.Bucket(withdrawalAddressIndicesBucket)
+	for i := start; i < end; i++ {
+		if i%1000 == 0 && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		val, err := st.ValidatorAtIndexReadOnly(primitives.ValidatorIndex(i))
+		if err != nil {
+			return err
+		}
+		key := bytesutil.Uint64ToBytesBigEndian(uint64(i))
+		creds := val.GetWithdrawalCredentials()
+		digest := make([]byte, 0, validatorIdentityDigestSize)
+		digest = append(digest, bytesutil.Uint64ToBytesBigEndian(uint64(val.ActivationEpoch()))...)
+		digest = append(digest, bytesutil.Uint64ToBytesBigEndian(uint64(val.ExitEpoch()))...)
+		digest = append(digest, creds...)
+		if bytes.Equal(digests.Get(key), digest) {
+			continue
+		}
+
+		id := &dbval.ValidatorIdentity{}
+		if enc := identities.Get(key); enc != nil {
+			if err := proto.Unmarshal(enc, id); err != nil {
+				return errors.Wrapf(err, "could not unmarshal identity of validator %d", i)
+			}
+		} else {
+			pubkey := val.PublicKey()
+			id.Index = uint64(i)
+			id.PublicKey = pubkey[:]
+			if err := pubkeys.Put(id.PublicKey, key); err != nil {
+				return err
+			}
+		}
+		id.ActivationEpoch = uint64(val.ActivationEpoch())
+		id.ExitEpoch = uint64(val.ExitEpoch())
+		if n := len(id.WithdrawalCredentials); n == 0 || !bytes.Equal(id.WithdrawalCredentials[n-1].WithdrawalCredentials, creds) {
+			id.WithdrawalCredentials = append(id.WithdrawalCredentials, &dbval.WithdrawalCredentialsChange{
+				Epoch:                 uint64(epoch),
+				WithdrawalCredentials: bytesutil.SafeCopyBytes(creds),
+			})
+			if address, ok := withdrawalAddress(creds); ok {
+				if err := addresses.Put(append(address.Bytes(), key...), []byte{}); err != nil {
+					return err
+				}
+			}
+		}
+		enc, err := proto.Marshal(id)
+		if err != nil {
+			return err
+		}
+		if err := identities.Put(key, enc); err != nil {
+			return err
+		}
+		if err := digests.Put(key, digest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidatorIdentity returns the indexed identity of the validator with the given index.
+func (s *Store) ValidatorIdentity(ctx context.Context, idx primitives.ValidatorIndex) (*dbval.ValidatorIdentity, error) {
+	_, span := trace.StartSpan(ctx, "BeaconDB.ValidatorIdentity")
+	defer span.End()
+	var id *dbval.ValidatorIdentity
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		id, err = validatorIdentity(tx, bytesutil.Uint64ToBytesBigEndian(uint64(idx)))
+		return err
+	})
+	return id, err
+}
+
+// ValidatorIdentityByPublicKey returns the indexed identity of the validator with the given public key.
+func (s *Store) ValidatorIdentityByPublicKey(ctx context.Context, pubkey [fieldparams.BLSPubkeyLength]byte) (*dbval.ValidatorIdentity, error) {
+	_, span := trace.StartSpan(ctx, "BeaconDB.ValidatorIdentityByPublicKey")
+	defer span.End()
+	var id *dbval.ValidatorIdentity
+	err := s.db.View(func(tx *bolt.Tx) error {
+		key := tx.Bucket(validatorPubkeyIndicesBucket).Get(pubkey[:])
+		if key == nil {
+			return ErrNotFoundValidatorIdentity
+		}
+		var err error
+		id, err = validatorIdentity(tx, key)
+		return err
+	})
+	return id, err
+}
+
+// ValidatorIdentitiesByWithdrawalAddress returns the indexed identities of the validators which have or had
+// execution withdrawal credentials with the given address, ordered by validator index.
+func (s *Store) ValidatorIdentitiesByWithdrawalAddress(ctx context.Context, address common.Address) ([]*dbval.ValidatorIdentity, error) {
+	_, span := trace.StartSpan(ctx, "BeaconDB.ValidatorIdentitiesByWithdrawalAddress")
+	defer span.End()
+	ids := make([]*dbval.ValidatorIdentity, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(withdrawalAddressIndicesBucket).Cursor()
+		prefix := address.Bytes()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			id, err := validatorIdentity(tx, k[len(prefix):])
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		return nil
+	})
+	return ids, err
+}
+
+// ValidatorIdentityIndexEpoch returns the epoch of the latest finalized state saved in the validator identity index.
+func (s *Store) ValidatorIdentityIndexEpoch(ctx context.Context) (primitives.Epoch, error) {
+	_, span := trace.StartSpan(ctx, "BeaconDB.ValidatorIdentityIndexEpoch")
+	defer span.End()
+	var epoch primitives.Epoch
+	err := s.db.View(func(tx *bolt.Tx) error {
+		enc := tx.Bucket(chainMetadataBucket).Get(validatorIdentityEpochKey)
+		if enc == nil {
+			return errors.Wrap(ErrNotFound, "validator identity index epoch")
+		}
+		epoch = primitives.Epoch(bytesutil.BytesToUint64BigEndian(enc))
+		return nil
+	})
+	return epoch, err
+}
+
+// ValidatorIdentityIndexStartEpoch returns the epoch of the first finalized state saved in the validator identity index.
+// The index is not backfilled: withdrawal credentials changed before that epoch are not part of the history, and the
+// credentials a validator had at that epoch are recorded as set at that epoch.
+func (s *Store) ValidatorIdentityIndexStartEpoch(ctx context.Context) (primitives.Epoch, error) {
+	_, span := trace.StartSpan(ctx, "BeaconDB.ValidatorIdentityIndexStartEpoch")
+	defer span.End()
+	var epoch primitives.Epoch
+	err := s.db.View(func(tx *bolt.Tx) error {
+		enc := tx.Bucket(chainMetadataBucket).Get(validatorIdentityStartEpochKey)
+		if enc == nil {
+			return errors.Wrap(ErrNotFound, "validator identity index start epoch")
+		}
+		epoch = primitives.Epoch(bytesutil.BytesToUint64BigEndian(enc))
+		return nil
+	})
+	return epoch, err
+}
+
+func validatorIdentity(tx *bolt.Tx, key []byte) (*dbval.ValidatorIdentity, error) {
+	enc := tx.Bucket(validatorIdentityBucket).Get(key)
+	if enc == nil {
+		return nil, ErrNotFoundValidatorIdentity
+	}
+	id := &dbval.ValidatorIdentity{}
+	if err := proto.Unmarshal(enc, id); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+// withdrawalAddress returns the execution address of execution withdrawal credentials.
+func withdrawalAddress(creds []byte) (common.Address, bool) {
+	if len(creds) != 32 {
+		return common.Address{}, false
+	}
+	cfg := params.BeaconConfig()
+	if creds[0] != cfg.ETH1AddressWithdrawalPrefixByte && creds[0] != cfg.CompoundingWithdrawalPrefixByte {
+		return common.Address{}, false
+	}
+	return common.BytesToAddress(creds[12:]), true
+}
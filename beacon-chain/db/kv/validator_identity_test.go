@@ -0,0 +1,129 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestStore_ValidatorIdentities(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	_, err := db.ValidatorIdentityIndexEpoch(ctx)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	st, _ := util.DeterministicGenesisState(t, 4)
+	require.NoError(t, st.SetSlot(params.BeaconConfig().SlotsPerEpoch))
+	require.NoError(t, db.SaveValidatorIdentities(ctx, st))
+	epoch, err := db.ValidatorIdentityIndexEpoch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, primitives.Epoch(1), epoch)
+
+	blsCreds, err := st.ValidatorAtIndexReadOnly(2)
+	require.NoError(t, err)
+	id, err := db.ValidatorIdentityByPublicKey(ctx, blsCreds.PublicKey())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), id.Index)
+	require.Equal(t, 1, len(id.WithdrawalCredentials))
+	assert.Equal(t, uint64(1), id.WithdrawalCredentials[0].Epoch)
+
+	// Validators 1 and 2 change their credentials to the same withdrawal address, and validator 3 exits.
+	address := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	creds := make([]byte, 32)
+	creds[0] = params.BeaconConfig().ETH1AddressWithdrawalPrefixByte
+	copy(creds[12:], address.Bytes())
+	for _, idx := range []primitives.ValidatorIndex{1, 2} {
+		val, err := st.ValidatorAtIndex(idx)
+		require.NoError(t, err)
+		val.WithdrawalCredentials = creds
+		require.NoError(t, st.UpdateValidatorAtIndex(idx, val))
+	}
+	val, err := st.ValidatorAtIndex(3)
+	require.NoError(t, err)
+	val.ExitEpoch = 10
+	require.NoError(t, st.UpdateValidatorAtIndex(3, val))
+	require.NoError(t, st.SetSlot(3*params.BeaconConfig().SlotsPerEpoch))
+	require.NoError(t, db.SaveValidatorIdentities(ctx, st))
+
+	ids, err := db.ValidatorIdentitiesByWithdrawalAddress(ctx, address)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(ids))
+	assert.Equal(t, uint64(1), ids[0].Index)
+	assert.Equal(t, uint64(2), ids[1].Index)
+	require.Equal(t, 2, len(ids[1].WithdrawalCredentials))
+	assert.Equal(t, uint64(3), ids[1].WithdrawalCredentials[1].Epoch)
+	assert.DeepEqual(t, creds, ids[1].WithdrawalCredentials[1].WithdrawalCredentials)
+
+	id, err = db.ValidatorIdentity(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), id.ExitEpoch)
+	assert.Equal(t, 1, len(id.WithdrawalCredentials))
+
+	// Validators that didn't change since the latest indexed state are not read again.
+	require.NoError(t, db.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(validatorIdentityBucket).Put(bytesutil.Uint64ToBytesBigEndian(0), []byte("corrupted"))
+	}))
+	require.NoError(t, st.SetSlot(4*params.BeaconConfig().SlotsPerEpoch))
+	require.NoError(t, db.SaveValidatorIdentities(ctx, st))
+	epoch, err = db.ValidatorIdentityIndexEpoch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, primitives.Epoch(4), epoch)
+	startEpoch, err := db.ValidatorIdentityIndexStartEpoch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, primitives.Epoch(1), startEpoch)
+
+	// Older states are ignored.
+	require.NoError(t, st.SetSlot(2*params.BeaconConfig().SlotsPerEpoch))
+	val.ExitEpoch = 20
+	require.NoError(t, st.UpdateValidatorAtIndex(3, val))
+	require.NoError(t, db.SaveValidatorIdentities(ctx, st))
+	id, err = db.ValidatorIdentity(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), id.ExitEpoch)
+
+	_, err = db.ValidatorIdentity(ctx, 4)
+	require.ErrorIs(t, err, ErrNotFound)
+	ids, err = db.ValidatorIdentitiesByWithdrawalAddress(ctx, common.Address{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(ids))
+}
+
+func TestStore_ValidatorIdentities_Batches(t *testing.T) {
+	batchSize := validatorIdentityBatchSize
+	validatorIdentityBatchSize = 3
+	defer func() { validatorIdentityBatchSize = batchSize }()
+
+	db := setupDB(t)
+	ctx := context.Background()
+	st, _ := util.DeterministicGenesisState(t, 8)
+	require.NoError(t, st.SetSlot(params.BeaconConfig().SlotsPerEpoch))
+
+	// An interrupted update leaves the indexed epoch unchanged, so that the state is indexed again.
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	require.ErrorIs(t, db.SaveValidatorIdentities(cancelled, st), context.Canceled)
+	_, err := db.ValidatorIdentityIndexEpoch(ctx)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, db.SaveValidatorIdentities(ctx, st))
+	for i := 0; i < 8; i++ {
+		id, err := db.ValidatorIdentity(ctx, primitives.ValidatorIndex(i))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(i), id.Index)
+	}
+	epoch, err := db.ValidatorIdentityIndexEpoch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, primitives.Epoch(1), epoch)
+	startEpoch, err := db.ValidatorIdentityIndexStartEpoch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, primitives.Epoch(1), startEpoch)
+}
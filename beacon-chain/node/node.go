@@ -19,6 +19,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api/client/beacon"
 	"github.com/prysmaticlabs/prysm/v5/api/server/httprest"
 	"github.com/prysmaticlabs/prysm/v5/api/server/middleware"
 	"github.com/prysmaticlabs/prysm/v5/async/event"
@@ -113,12 +114,14 @@ type BeaconNode struct {
 	serviceFlagOpts         *serviceFlagOpts
 	GenesisInitializer      genesis.Initializer
 	CheckpointInitializer   checkpoint.Initializer
+	CheckpointCache         *checkpoint.Cache
 	forkChoicer             forkchoice.ForkChoicer
 	clockWaiter             startup.ClockWaiter
 	BackfillOpts            []backfill.ServiceOption
 	initialSyncComplete     chan struct{}
 	BlobStorage             *filesystem.BlobStorage
 	BlobStorageOptions      []filesystem.BlobStorageOption
+	BlobArchiveProvider     string
 	verifyInitWaiter        *verification.InitializerWaiter
 	syncChecker             *initialsync.SyncChecker
 }
@@ -362,6 +365,13 @@ func registerServices(cliCtx *cli.Context, beacon *BeaconNode, synchronizer *sta
 		return errors.Wrap(err, "could not register validator monitoring service")
 	}
 
+	if beacon.CheckpointCache != nil {
+		log.Debugln("Registering Checkpoint Serving Service")
+		if err := beacon.registerCheckpointServingService(); err != nil {
+			return errors.Wrap(err, "could not register checkpoint serving service")
+		}
+	}
+
 	if !cliCtx.Bool(cmd.DisableMonitoringFlag.Name) {
 		log.Debugln("Registering Prometheus Service")
 		if err := beacon.registerPrometheusService(cliCtx); err != nil {
@@ -674,7 +684,11 @@ func (b *BeaconNode) registerP2P(cliCtx *cli.Context) error {
 		TCPPort:              cliCtx.Uint(cmd.P2PTCPPort.Name),
 		UDPPort:              cliCtx.Uint(cmd.P2PUDPPort.Name),
 		MaxPeers:             cliCtx.Uint(cmd.P2PMaxPeers.Name),
+		MaxClientRatio:       cliCtx.Float64(cmd.P2PMaxClientRatio.Name),
+		MaxIPSubnetPeers:     cliCtx.Uint(cmd.P2PMaxIPSubnetPeers.Name),
 		QueueSize:            cliCtx.Uint(cmd.PubsubQueueSize.Name),
+		MeshDegree:           cliCtx.Uint(cmd.PubsubMeshDegree.Name),
+		IDontWantThreshold:   cliCtx.Uint(cmd.PubsubIDontWantThreshold.Name),
 		AllowListCIDR:        cliCtx.String(cmd.P2PAllowList.Name),
 		DenyListCIDR:         slice.SplitCommaSeparated(cliCtx.StringSlice(cmd.P2PDenyList.Name)),
 		EnableUPnP:           cliCtx.Bool(cmd.EnableUPnPFlag.Name),
@@ -929,6 +943,18 @@ func (b *BeaconNode) registerRPCService(router *http.ServeMux) error {
 	mockEth1DataVotes := b.cliCtx.Bool(flags.InteropMockEth1DataVotesFlag.Name)
 	maxMsgSize := b.cliCtx.Int(cmd.GrpcMaxCallRecvMsgSizeFlag.Name)
 	enableDebugRPCEndpoints := !b.cliCtx.Bool(flags.DisableDebugRPCEndpoints.Name)
+	var authenticator *middleware.Authenticator
+	if b.cliCtx.IsSet(flags.HTTPServerAuthConfig.Name) {
+		authCfg, err := middleware.LoadAuthConfig(b.cliCtx.String(flags.HTTPServerAuthConfig.Name))
+		if err != nil {
+			return err
+		}
+		authenticator, err = middleware.NewAuthenticator(authCfg)
+		if err != nil {
+			return errors.Wrap(err, "invalid HTTP auth config")
+		}
+		log.WithField("clients", len(authCfg.Clients)).Info("HTTP API authentication enabled")
+	}
 
 	p2pService := b.fetchP2P()
 	rpcService := rpc.NewService(b.ctx, &rpc.Config{
@@ -957,6 +983,7 @@ func (b *BeaconNode) registerRPCService(router *http.ServeMux) error {
 		GenesisTimeFetcher:        chainService,
 		GenesisFetcher:            chainService,
 		OptimisticModeFetcher:     chainService,
+		OptimisticReporter:        chainService,
 		AttestationCache:          b.attestationCache,
 		AttestationsPool:          b.attestationPool,
 		ExitPool:                  b.exitPool,
@@ -978,10 +1005,13 @@ func (b *BeaconNode) registerRPCService(router *http.ServeMux) error {
 		MaxMsgSize:                maxMsgSize,
 		BlockBuilder:              b.fetchBuilderService(),
 		Router:                    router,
+		Authenticator:             authenticator,
+		ResponseCacheSize:         int64(b.cliCtx.Uint64(flags.HTTPResponseCacheSize.Name)) * 1024 * 1024,
 		ClockWaiter:               b.clockWaiter,
 		BlobStorage:               b.BlobStorage,
 		TrackedValidatorsCache:    b.trackedValidatorsCache,
 		PayloadIDCache:            b.payloadIDCache,
+		CheckpointCache:           b.CheckpointCache,
 	})
 
 	return b.services.RegisterService(rpcService)
@@ -1034,6 +1064,19 @@ func (b *BeaconNode) registerHTTPService(router *http.ServeMux) error {
 	if b.cliCtx.IsSet(cmd.ApiTimeoutFlag.Name) {
 		opts = append(opts, httprest.WithTimeout(b.cliCtx.Duration(cmd.ApiTimeoutFlag.Name)))
 	}
+	tlsCert := b.cliCtx.String(flags.HTTPServerTLSCert.Name)
+	tlsKey := b.cliCtx.String(flags.HTTPServerTLSKey.Name)
+	clientCA := b.cliCtx.String(flags.HTTPServerTLSClientCA.Name)
+	switch {
+	case tlsCert != "" && tlsKey != "":
+		opts = append(opts, httprest.WithTLS(tlsCert, tlsKey, clientCA))
+	case tlsCert != "" || tlsKey != "":
+		return errors.New("both --http-tls-cert and --http-tls-key are required to serve HTTPS")
+	case clientCA != "":
+		return errors.New("--http-tls-client-ca requires --http-tls-cert and --http-tls-key")
+	case b.cliCtx.IsSet(flags.HTTPServerAuthConfig.Name):
+		log.Warn("HTTP API authentication is enabled without HTTPS, bearer tokens are sent in clear text")
+	}
 	g, err := httprest.New(b.ctx, opts...)
 	if err != nil {
 		return err
@@ -1069,6 +1112,22 @@ func (b *BeaconNode) registerValidatorMonitorService(initialSyncComplete chan st
 	return b.services.RegisterService(svc)
 }
 
+func (b *BeaconNode) registerCheckpointServingService() error {
+	var chainService *blockchain.Service
+	if err := b.services.FetchService(&chainService); err != nil {
+		return err
+	}
+	svc := checkpoint.NewService(b.ctx, &checkpoint.ServingConfig{
+		Cache:               b.CheckpointCache,
+		StateNotifier:       b,
+		FinalizationFetcher: chainService,
+		ReplayerBuilder:     stategen.NewCanonicalHistory(b.db, chainService, chainService),
+		BeaconDB:            b.db,
+		InitialSyncComplete: b.initialSyncComplete,
+	})
+	return b.services.RegisterService(svc)
+}
+
 func (b *BeaconNode) registerBuilderService(cliCtx *cli.Context) error {
 	var chainService *blockchain.Service
 	if err := b.services.FetchService(&chainService); err != nil {
@@ -1076,7 +1135,7 @@ func (b *BeaconNode) registerBuilderService(cliCtx *cli.Context) error {
 	}
 
 	opts := b.serviceFlagOpts.builderOpts
-	opts = append(opts, builder.WithHeadFetcher(chainService), builder.WithDatabase(b.db))
+	opts = append(opts, builder.WithHeadFetcher(chainService), builder.WithDatabase(b.db), builder.WithTrackedValidatorsCache(b.trackedValidatorsCache))
 
 	// make cache the default.
 	if !cliCtx.Bool(features.DisableRegistrationCache.Name) {
@@ -1095,8 +1154,34 @@ func (b *BeaconNode) RegisterBackfillService(cliCtx *cli.Context, bfs *backfill.
 	if err != nil {
 		return errors.Wrap(err, "error initializing backfill service")
 	}
+	if err := b.services.RegisterService(bf); err != nil {
+		return err
+	}
+	if b.BlobStorage.Coverage() == nil {
+		return nil
+	}
 
-	return b.services.RegisterService(bf)
+	cfg := &backfill.BlobArchiveConfig{
+		Store:          bfs,
+		DB:             b.db,
+		BlobStorage:    b.BlobStorage,
+		ClockWaiter:    b.clockWaiter,
+		P2P:            b.fetchP2P(),
+		PeerAssigner:   pa,
+		VerifierWaiter: b.verifyInitWaiter,
+		InitSyncWaiter: initSyncWaiter(cliCtx.Context, b.initialSyncComplete),
+	}
+	if b.BlobArchiveProvider != "" {
+		cfg.Provider, err = beacon.NewClient(b.BlobArchiveProvider)
+		if err != nil {
+			return errors.Wrap(err, "could not create blob archive provider client")
+		}
+	}
+	archive, err := backfill.NewBlobArchive(cliCtx.Context, cfg)
+	if err != nil {
+		return errors.Wrap(err, "error initializing blob archive")
+	}
+	return b.services.RegisterService(archive)
 }
 
 func hasNetworkFlag(cliCtx *cli.Context) bool {
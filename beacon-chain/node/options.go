@@ -50,3 +50,12 @@ func WithBlobStorageOptions(opt ...filesystem.BlobStorageOption) Option {
 		return nil
 	}
 }
+
+// WithBlobArchiveProvider sets the URL of a beacon API serving historical blob sidecars,
+// used by the blob archive when blob sidecars can't be downloaded from peers.
+func WithBlobArchiveProvider(url string) Option {
+	return func(bn *BeaconNode) error {
+		bn.BlobArchiveProvider = url
+		return nil
+	}
+}
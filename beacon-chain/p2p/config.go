@@ -9,6 +9,9 @@ import (
 // This is the default queue size used if we have specified an invalid one.
 const defaultPubsubQueueSize = 600
 
+// This is the minimum pubsub mesh degree, below which the mesh cannot keep outbound peers.
+const minMeshDegree = 4
+
 // Config for the p2p service. These parameters are set from application level flags
 // to initialize the p2p service.
 type Config struct {
@@ -28,7 +31,11 @@ type Config struct {
 	TCPPort              uint
 	UDPPort              uint
 	MaxPeers             uint
+	MaxClientRatio       float64
+	MaxIPSubnetPeers     uint
 	QueueSize            uint
+	MeshDegree           uint
+	IDontWantThreshold   uint
 	AllowListCIDR        string
 	DenyListCIDR         []string
 	StateNotifier        statefeed.Notifier
@@ -43,5 +50,9 @@ func validateConfig(cfg *Config) *Config {
 		log.Warnf("Invalid pubsub queue size of %d initialized, setting the quese size as %d instead", cfg.QueueSize, defaultPubsubQueueSize)
 		cfg.QueueSize = defaultPubsubQueueSize
 	}
+	if cfg.MeshDegree != 0 && cfg.MeshDegree < minMeshDegree {
+		log.Warnf("Invalid pubsub mesh degree of %d, the minimum is %d, using the default mesh degree instead", cfg.MeshDegree, minMeshDegree)
+		cfg.MeshDegree = 0
+	}
 	return cfg
 }
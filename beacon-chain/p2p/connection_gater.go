@@ -36,6 +36,9 @@ func (s *Service) InterceptAddrDial(pid peer.ID, m multiaddr.Multiaddr) (allow b
 	if s.peers.IsBad(pid) != nil {
 		return false
 	}
+	if s.peers.IsBannedAddr(m) {
+		return false
+	}
 	return filterConnections(s.addrFilter, m)
 }
 
@@ -53,6 +56,11 @@ func (s *Service) InterceptAccept(n network.ConnMultiaddrs) (allow bool) {
 			"reason": "exceeded dial limit"}).Trace("Not accepting inbound dial from ip address")
 		return false
 	}
+	if s.peers.IsBannedAddr(n.RemoteMultiaddr()) {
+		log.WithFields(logrus.Fields{"peer": n.RemoteMultiaddr(),
+			"reason": "banned"}).Trace("Not accepting inbound dial from ip address")
+		return false
+	}
 	if s.isPeerAtLimit(true /* inbound */) {
 		log.WithFields(logrus.Fields{"peer": n.RemoteMultiaddr(),
 			"reason": "at peer limit"}).Trace("Not accepting inbound dial")
@@ -573,6 +573,11 @@ func (s *Service) filterPeer(node *enode.Node) bool {
 	// If the peer has 2 multiaddrs, favor the QUIC address, which is in first position.
 	multiAddr := multiAddrs[0]
 
+	// Ignore nodes from IP subnets which already have as many peers as allowed.
+	if s.peers.IsIPSubnetFull(multiAddr) {
+		return false
+	}
+
 	// Add peer to peer handler.
 	s.peers.Add(nodeENR, peerData.ID, multiAddr, network.DirUnknown)
 
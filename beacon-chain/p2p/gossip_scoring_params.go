@@ -102,17 +102,18 @@ func (s *Service) topicScoreParams(topic string) (*pubsub.TopicScoreParams, erro
 	if err != nil {
 		return nil, err
 	}
+	meshDegree := s.meshDegree()
 	switch {
 	case strings.Contains(topic, GossipBlockMessage):
-		return defaultBlockTopicParams(), nil
+		return defaultBlockTopicParams(meshDegree), nil
 	case strings.Contains(topic, GossipAggregateAndProofMessage):
-		return defaultAggregateTopicParams(activeValidators), nil
+		return defaultAggregateTopicParams(activeValidators, meshDegree), nil
 	case strings.Contains(topic, GossipAttestationMessage):
-		return defaultAggregateSubnetTopicParams(activeValidators), nil
+		return defaultAggregateSubnetTopicParams(activeValidators, meshDegree), nil
 	case strings.Contains(topic, GossipSyncCommitteeMessage):
-		return defaultSyncSubnetTopicParams(activeValidators), nil
+		return defaultSyncSubnetTopicParams(activeValidators, meshDegree), nil
 	case strings.Contains(topic, GossipContributionAndProofMessage):
-		return defaultSyncContributionTopicParams(), nil
+		return defaultSyncContributionTopicParams(meshDegree), nil
 	case strings.Contains(topic, GossipExitMessage):
 		return defaultVoluntaryExitTopicParams(), nil
 	case strings.Contains(topic, GossipProposerSlashingMessage):
@@ -123,12 +124,21 @@ func (s *Service) topicScoreParams(topic string) (*pubsub.TopicScoreParams, erro
 		return defaultBlsToExecutionChangeTopicParams(), nil
 	case strings.Contains(topic, GossipBlobSidecarMessage):
 		// TODO(Deneb): Using the default block scoring. But this should be updated.
-		return defaultBlockTopicParams(), nil
+		return defaultBlockTopicParams(meshDegree), nil
 	default:
 		return nil, errors.Errorf("unrecognized topic provided for parameter registration: %s", topic)
 	}
 }
 
+// meshDegree returns the mesh degree of the gossipsub router, which topic scoring derives the first message
+// deliveries expected from each mesh peer from. The router keeps the same mesh degree for every topic.
+func (s *Service) meshDegree() uint64 {
+	if s.cfg.MeshDegree == 0 {
+		return gossipSubD
+	}
+	return uint64(s.cfg.MeshDegree)
+}
+
 func (s *Service) retrieveActiveValidators() (uint64, error) {
 	if s.activeValidatorCount != 0 {
 		return s.activeValidatorCount, nil
@@ -169,9 +179,13 @@ func (s *Service) retrieveActiveValidators() (uint64, error) {
 // Based on the lighthouse parameters.
 // https://gist.github.com/blacktemplar/5c1862cb3f0e32a1a7fb0b25e79e6e2c
 
-func defaultBlockTopicParams() *pubsub.TopicScoreParams {
+func defaultBlockTopicParams(meshDegree uint64) *pubsub.TopicScoreParams {
 	decayEpoch := time.Duration(5)
 	blocksPerEpoch := uint64(params.BeaconConfig().SlotsPerEpoch)
+	// The first deliveries cap is tuned for the default mesh degree. Each mesh peer delivers fewer blocks first in
+	// a larger mesh, so the cap is scaled by the mesh degree, and the weight along to keep the same maximum score.
+	firstMessageCap := 23 * gossipSubD / float64(meshDegree)
+	firstMessageWeight := 23 / firstMessageCap
 	meshWeight := -0.717
 	if !meshDeliveryIsScored {
 		// Set the mesh weight as zero as a temporary measure, so as to prevent
@@ -183,9 +197,9 @@ func defaultBlockTopicParams() *pubsub.TopicScoreParams {
 		TimeInMeshWeight:                maxInMeshScore / inMeshCap(),
 		TimeInMeshQuantum:               inMeshTime(),
 		TimeInMeshCap:                   inMeshCap(),
-		FirstMessageDeliveriesWeight:    1,
+		FirstMessageDeliveriesWeight:    firstMessageWeight,
 		FirstMessageDeliveriesDecay:     scoreDecay(twentyEpochs),
-		FirstMessageDeliveriesCap:       23,
+		FirstMessageDeliveriesCap:       firstMessageCap,
 		MeshMessageDeliveriesWeight:     meshWeight,
 		MeshMessageDeliveriesDecay:      scoreDecay(decayEpoch * oneEpochDuration()),
 		MeshMessageDeliveriesCap:        float64(blocksPerEpoch * uint64(decayEpoch)),
@@ -199,10 +213,10 @@ func defaultBlockTopicParams() *pubsub.TopicScoreParams {
 	}
 }
 
-func defaultAggregateTopicParams(activeValidators uint64) *pubsub.TopicScoreParams {
+func defaultAggregateTopicParams(activeValidators, meshDegree uint64) *pubsub.TopicScoreParams {
 	// Determine the expected message rate for the particular gossip topic.
 	aggPerSlot := aggregatorsPerSlot(activeValidators)
-	firstMessageCap, err := decayLimit(scoreDecay(1*oneEpochDuration()), float64(aggPerSlot*2/gossipSubD))
+	firstMessageCap, err := decayLimit(scoreDecay(1*oneEpochDuration()), float64(aggPerSlot*2/meshDegree))
 	if err != nil {
 		log.WithError(err).Warn("skipping initializing topic scoring")
 		return nil
@@ -241,10 +255,10 @@ func defaultAggregateTopicParams(activeValidators uint64) *pubsub.TopicScorePara
 	}
 }
 
-func defaultSyncContributionTopicParams() *pubsub.TopicScoreParams {
+func defaultSyncContributionTopicParams(meshDegree uint64) *pubsub.TopicScoreParams {
 	// Determine the expected message rate for the particular gossip topic.
 	aggPerSlot := params.BeaconConfig().SyncCommitteeSubnetCount * params.BeaconConfig().TargetAggregatorsPerSyncSubcommittee
-	firstMessageCap, err := decayLimit(scoreDecay(1*oneEpochDuration()), float64(aggPerSlot*2/gossipSubD))
+	firstMessageCap, err := decayLimit(scoreDecay(1*oneEpochDuration()), float64(aggPerSlot*2/meshDegree))
 	if err != nil {
 		log.WithError(err).Warn("skipping initializing topic scoring")
 		return nil
@@ -283,7 +297,7 @@ func defaultSyncContributionTopicParams() *pubsub.TopicScoreParams {
 	}
 }
 
-func defaultAggregateSubnetTopicParams(activeValidators uint64) *pubsub.TopicScoreParams {
+func defaultAggregateSubnetTopicParams(activeValidators, meshDegree uint64) *pubsub.TopicScoreParams {
 	subnetCount := params.BeaconConfig().AttestationSubnetCount
 	// Get weight for each specific subnet.
 	topicWeight := attestationTotalWeight / float64(subnetCount)
@@ -306,7 +320,7 @@ func defaultAggregateSubnetTopicParams(activeValidators uint64) *pubsub.TopicSco
 		firstDecay = 4
 		meshDecay = 16
 	}
-	rate := numPerSlot * 2 / gossipSubD
+	rate := numPerSlot * 2 / time.Duration(meshDegree)
 	if rate == 0 {
 		log.Warn("rate is 0, skipping initializing topic scoring")
 		return nil
@@ -352,7 +366,7 @@ func defaultAggregateSubnetTopicParams(activeValidators uint64) *pubsub.TopicSco
 	}
 }
 
-func defaultSyncSubnetTopicParams(activeValidators uint64) *pubsub.TopicScoreParams {
+func defaultSyncSubnetTopicParams(activeValidators, meshDegree uint64) *pubsub.TopicScoreParams {
 	subnetCount := params.BeaconConfig().SyncCommitteeSubnetCount
 	// Get weight for each specific subnet.
 	topicWeight := syncCommitteesTotalWeight / float64(subnetCount)
@@ -369,7 +383,7 @@ func defaultSyncSubnetTopicParams(activeValidators uint64) *pubsub.TopicScorePar
 	firstDecay := time.Duration(1)
 	meshDecay := time.Duration(4)
 
-	rate := subnetWeight * 2 / gossipSubD
+	rate := subnetWeight * 2 / meshDegree
 	if rate == 0 {
 		log.Warn("rate is 0, skipping initializing topic scoring")
 		return nil
@@ -2,6 +2,7 @@ package p2p
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
@@ -67,12 +68,56 @@ func TestLoggingParameters(_ *testing.T) {
 	logGossipParameters("testing", nil)
 	logGossipParameters("testing", &pubsub.TopicScoreParams{})
 	// Test out actual gossip parameters.
-	logGossipParameters("testing", defaultBlockTopicParams())
-	p := defaultAggregateSubnetTopicParams(10000)
+	logGossipParameters("testing", defaultBlockTopicParams(gossipSubD))
+	p := defaultAggregateSubnetTopicParams(10000, gossipSubD)
 	logGossipParameters("testing", p)
-	p = defaultAggregateTopicParams(10000)
+	p = defaultAggregateTopicParams(10000, gossipSubD)
 	logGossipParameters("testing", p)
 	logGossipParameters("testing", defaultAttesterSlashingTopicParams())
 	logGossipParameters("testing", defaultProposerSlashingTopicParams())
 	logGossipParameters("testing", defaultVoluntaryExitTopicParams())
 }
+
+func TestTopicScoreParams_MeshDegree(t *testing.T) {
+	digest := [4]byte{'A', 'B', 'C', 'D'}
+	blockTopic := fmt.Sprintf(BlockSubnetTopicFormat, digest)
+	blobTopic := fmt.Sprintf(BlobSubnetTopicFormat, digest, 0)
+	attTopic := fmt.Sprintf(AttestationSubnetTopicFormat, digest, 0)
+	aggTopic := fmt.Sprintf(AggregateAndProofSubnetTopicFormat, digest)
+
+	s := &Service{cfg: validateConfig(&Config{QueueSize: 600}), activeValidatorCount: 1_000_000}
+	assert.Equal(t, uint64(gossipSubD), s.meshDegree())
+	assert.Equal(t, gossipSubD, s.gossipSubParams().D)
+	defaultBlock, err := s.topicScoreParams(blockTopic)
+	require.NoError(t, err)
+	assert.Equal(t, float64(23), defaultBlock.FirstMessageDeliveriesCap)
+	assert.Equal(t, float64(1), defaultBlock.FirstMessageDeliveriesWeight)
+	defaultAtt, err := s.topicScoreParams(attTopic)
+	require.NoError(t, err)
+	defaultAgg, err := s.topicScoreParams(aggTopic)
+	require.NoError(t, err)
+
+	// Topic scoring follows the mesh degree of the router.
+	s = &Service{cfg: validateConfig(&Config{QueueSize: 600, MeshDegree: 4}), activeValidatorCount: 1_000_000}
+	assert.Equal(t, uint64(4), s.meshDegree())
+	assert.Equal(t, 4, s.gossipSubParams().D)
+
+	// A smaller mesh expects more first deliveries from each mesh peer, for the same maximum score.
+	block, err := s.topicScoreParams(blockTopic)
+	require.NoError(t, err)
+	assert.Equal(t, float64(46), block.FirstMessageDeliveriesCap)
+	assert.Equal(t, 0.5, block.FirstMessageDeliveriesWeight)
+	blob, err := s.topicScoreParams(blobTopic)
+	require.NoError(t, err)
+	assert.Equal(t, block.FirstMessageDeliveriesCap, blob.FirstMessageDeliveriesCap)
+	att, err := s.topicScoreParams(attTopic)
+	require.NoError(t, err)
+	assert.Equal(t, true, att.FirstMessageDeliveriesCap > defaultAtt.FirstMessageDeliveriesCap)
+	agg, err := s.topicScoreParams(aggTopic)
+	require.NoError(t, err)
+	assert.Equal(t, true, agg.FirstMessageDeliveriesCap > defaultAgg.FirstMessageDeliveriesCap)
+
+	// Mesh degrees below the minimum are reset to the default.
+	s = &Service{cfg: validateConfig(&Config{QueueSize: 600, MeshDegree: 2})}
+	assert.Equal(t, uint64(gossipSubD), s.meshDegree())
+}
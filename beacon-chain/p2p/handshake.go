@@ -27,6 +27,10 @@ func peerMultiaddrString(conn network.Conn) string {
 
 func (s *Service) connectToPeer(conn network.Conn) {
 	s.peers.SetConnectionState(conn.RemotePeer(), peers.Connected)
+	// The agent version is only known once the peer is identified, it is refreshed with the peer metrics otherwise.
+	if agent := agentVersion(conn.RemotePeer(), s.host.Peerstore()); agent != "" {
+		s.peers.SetAgentVersion(conn.RemotePeer(), agent)
+	}
 	// Go through the handshake process.
 	log.WithFields(logrus.Fields{
 		"direction":   conn.Stat().Direction.String(),
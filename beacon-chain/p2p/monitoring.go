@@ -1,24 +1,14 @@
 package p2p
 
 import (
-	"strings"
-
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/core/peerstore"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers"
 )
 
 var (
-	knownAgentVersions = []string{
-		"lighthouse",
-		"nimbus",
-		"prysm",
-		"teku",
-		"lodestar",
-		"js-libp2p",
-		"rust-libp2p",
-	}
 	p2pPeerCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "p2p_peer_count",
 		Help: "The number of peers in a given state.",
@@ -36,6 +26,23 @@ var (
 	},
 		[]string{"agent"},
 	)
+	connectedPeersByDirection = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "p2p_connected_peers_by_direction",
+		Help: "The number of connected peers by connection direction.",
+	},
+		[]string{"direction"})
+	peerDiversityIPSubnets = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "p2p_peer_diversity_ip_subnets",
+		Help: "The number of distinct /24 IPv4 or /48 IPv6 subnets of the connected peers.",
+	})
+	peerDiversityMaxIPSubnetPeers = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "p2p_peer_diversity_max_ip_subnet_peers",
+		Help: "The number of connected peers in the IP subnet with the most connected peers.",
+	})
+	peerDiversityMaxClientRatio = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "p2p_peer_diversity_max_client_ratio",
+		Help: "The proportion of the connected peers running the most common client.",
+	})
 	repeatPeerConnections = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "p2p_repeat_attempts",
 		Help: "The number of repeat attempts the connection handler is triggered for a peer.",
@@ -114,6 +121,21 @@ var (
 		Help: "The number of messages rejected of a particular topic",
 	},
 		[]string{"topic", "reason"})
+	pubsubRecvBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "p2p_pubsub_recv_bytes_total",
+		Help: "The number of message bytes received for a particular topic, including duplicates",
+	},
+		[]string{"topic"})
+	pubsubSentBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "p2p_pubsub_sent_bytes_total",
+		Help: "The number of message bytes sent for a particular topic",
+	},
+		[]string{"topic"})
+	pubsubDuplicateBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "p2p_pubsub_duplicate_bytes_total",
+		Help: "The number of duplicate message bytes received for a particular topic",
+	},
+		[]string{"topic"})
 	pubsubPeerThrottle = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "p2p_pubsub_throttle_total",
 		Help: "The number of times a peer has been throttled for a particular topic",
@@ -182,7 +204,9 @@ func (s *Service) updateMetrics() {
 			continue
 		}
 
-		foundName := agentFromPid(pid, store)
+		agent := agentVersion(pid, store)
+		s.peers.SetAgentVersion(pid, agent)
+		foundName := peers.ClientFromAgentVersion(agent)
 		numConnectedPeersByClient[foundName] += 1
 
 		// Get peer scoring data.
@@ -196,6 +220,30 @@ func (s *Service) updateMetrics() {
 		avgScore := average(scoringData)
 		avgScoreConnectedClients.WithLabelValues(agent).Set(avgScore)
 	}
+	updateDiversityMetrics(s.peers.Diversity())
+}
+
+func updateDiversityMetrics(stats *peers.DiversityStats) {
+	connectedPeersByDirection.WithLabelValues("inbound").Set(float64(stats.Inbound))
+	connectedPeersByDirection.WithLabelValues("outbound").Set(float64(stats.Outbound))
+	peerDiversityIPSubnets.Set(float64(len(stats.IPSubnets)))
+	maxSubnetPeers := 0
+	for _, count := range stats.IPSubnets {
+		maxSubnetPeers = max(maxSubnetPeers, count)
+	}
+	peerDiversityMaxIPSubnetPeers.Set(float64(maxSubnetPeers))
+	total, maxClientPeers := 0, 0
+	for client, count := range stats.Clients {
+		total += count
+		if client != peers.UnknownClient {
+			maxClientPeers = max(maxClientPeers, count)
+		}
+	}
+	if total == 0 {
+		peerDiversityMaxClientRatio.Set(0)
+		return
+	}
+	peerDiversityMaxClientRatio.Set(float64(maxClientPeers) / float64(total))
 }
 
 func average(xs []float64) float64 {
@@ -210,19 +258,15 @@ func average(xs []float64) float64 {
 }
 
 func agentFromPid(pid peer.ID, store peerstore.Peerstore) string {
-	// Get the agent data.
+	return peers.ClientFromAgentVersion(agentVersion(pid, store))
+}
+
+// agentVersion returns the libp2p agent version of a peer, empty if it has not been identified yet.
+func agentVersion(pid peer.ID, store peerstore.Peerstore) string {
 	rawAgent, err := store.Get(pid, "AgentVersion")
 	agent, ok := rawAgent.(string)
 	if err != nil || !ok {
-		return "unknown"
-	}
-	foundName := "unknown"
-	for _, knownAgent := range knownAgentVersions {
-		// If the agent string matches one of our known agents, we set
-		// the value to our own, sanitized string.
-		if strings.Contains(strings.ToLower(agent), knownAgent) {
-			foundName = knownAgent
-		}
+		return ""
 	}
-	return foundName
+	return agent
 }
@@ -46,3 +46,24 @@ func TestMiscParameters(t *testing.T) {
 	setPubSubParameters()
 	assert.Equal(t, rSubD, 8, "rSubD")
 }
+
+func TestGossipSubParams_Config(t *testing.T) {
+	params.SetupTestConfigCleanup(t)
+	s := &Service{cfg: validateConfig(&Config{QueueSize: 600})}
+	pms := s.gossipSubParams()
+	assert.Equal(t, gossipSubD, pms.D, "gossipSubD")
+	assert.Equal(t, pubsub.GossipSubIDontWantMessageThreshold, pms.IDontWantMessageThreshold, "IDontWantMessageThreshold")
+
+	s = &Service{cfg: validateConfig(&Config{QueueSize: 600, MeshDegree: 4, IDontWantThreshold: 16 * 1024})}
+	pms = s.gossipSubParams()
+	assert.Equal(t, 4, pms.D, "D")
+	assert.Equal(t, 3, pms.Dlo, "Dlo")
+	assert.Equal(t, 6, pms.Dhi, "Dhi")
+	assert.Equal(t, 16*1024, pms.IDontWantMessageThreshold, "IDontWantMessageThreshold")
+
+	// Mesh degrees below the minimum are reset to the default.
+	s = &Service{cfg: validateConfig(&Config{QueueSize: 600, MeshDegree: 2})}
+	pms = s.gossipSubParams()
+	assert.Equal(t, gossipSubD, pms.D, "D")
+	assert.Equal(t, gossipSubDlo, pms.Dlo, "Dlo")
+}
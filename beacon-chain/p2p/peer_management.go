@@ -0,0 +1,54 @@
+package p2p
+
+import (
+	"context"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/types"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+	"github.com/sirupsen/logrus"
+)
+
+// ConnectToPeer dials the given peer, unless it is considered bad.
+func (s *Service) ConnectToPeer(ctx context.Context, info peer.AddrInfo) error {
+	if len(info.Addrs) == 0 {
+		return errors.New("peer has no address")
+	}
+	if s.peers.IsBannedAddr(info.Addrs[0]) {
+		return errors.Wrap(peers.ErrPeerBanned, "refused to connect to banned address")
+	}
+	return s.connectWithPeer(ctx, info)
+}
+
+// DisconnectWithGoodbye sends a goodbye message with the given reason to the peer, and disconnects from it.
+// Failing to send the goodbye message does not prevent the disconnection.
+func (s *Service) DisconnectWithGoodbye(ctx context.Context, pid peer.ID, code types.RPCGoodbyeCode) error {
+	if s.host.Network().Connectedness(pid) != network.Connected {
+		return nil
+	}
+	if err := s.sendGoodbye(ctx, pid, code); err != nil {
+		log.WithFields(logrus.Fields{
+			"error": err,
+			"peer":  pid,
+		}).Debug("Could not send goodbye message to peer")
+	}
+	return s.Disconnect(pid)
+}
+
+func (s *Service) sendGoodbye(ctx context.Context, pid peer.ID, code types.RPCGoodbyeCode) error {
+	ctx, cancel := context.WithTimeout(ctx, maxDialTimeout)
+	defer cancel()
+
+	topic, err := TopicFromMessage(GoodbyeMessageName, slots.ToEpoch(slots.CurrentSlot(uint64(s.genesisTime.Unix()))))
+	if err != nil {
+		return err
+	}
+	stream, err := s.Send(ctx, &code, topic, pid)
+	if err != nil {
+		return err
+	}
+	return stream.Close()
+}
@@ -0,0 +1,147 @@
+package peers
+
+import (
+	"net"
+	"sort"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
+	"github.com/pkg/errors"
+)
+
+// ErrPeerBanned is returned for peers banned by the operator.
+var ErrPeerBanned = errors.New("peer is banned")
+
+// Ban is an operator ban of a single peer ID, or of all peers with an address in an IP range.
+type Ban struct {
+	// Target is the banned peer ID, or the banned IP range in CIDR notation.
+	Target string
+	// PeerID is the banned peer, if the ban targets a peer ID.
+	PeerID peer.ID
+	// Network is the banned IP range, if the ban targets IP addresses.
+	Network *net.IPNet
+	// Reason is a free text description of the ban.
+	Reason string
+	// Expiry is the time the ban is lifted at. Bans with a zero expiry are permanent.
+	Expiry time.Time
+}
+
+// NewBan creates a ban of the given target, which is either a peer ID, an IP address or an IP range in CIDR
+// notation, for the given duration. A zero duration bans the target permanently.
+func NewBan(target string, duration time.Duration, reason string) (*Ban, error) {
+	if duration < 0 {
+		return nil, errors.New("ban duration must not be negative")
+	}
+	ban := &Ban{Reason: reason}
+	if duration > 0 {
+		ban.Expiry = time.Now().Add(duration)
+	}
+	if ip := net.ParseIP(target); ip != nil {
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, 8*net.IPv4len
+		}
+		ban.Network = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+		ban.Target = ban.Network.String()
+		return ban, nil
+	}
+	if _, ipnet, err := net.ParseCIDR(target); err == nil {
+		ban.Network = ipnet
+		ban.Target = ipnet.String()
+		return ban, nil
+	}
+	pid, err := peer.Decode(target)
+	if err != nil {
+		return nil, errors.Errorf("%s is neither a peer ID, an IP address nor a CIDR", target)
+	}
+	ban.PeerID = pid
+	ban.Target = pid.String()
+	return ban, nil
+}
+
+// expired returns true if the ban is lifted at the given time.
+func (b *Ban) expired(now time.Time) bool {
+	return !b.Expiry.IsZero() && !now.Before(b.Expiry)
+}
+
+// Ban bans a peer ID or an IP range. A ban of the same target replaces the previous one.
+func (p *Status) Ban(ban *Ban) {
+	p.store.Lock()
+	defer p.store.Unlock()
+	p.bans[ban.Target] = ban
+}
+
+// Unban lifts the ban of a peer ID, IP address or IP range, and returns false if the target was not banned.
+func (p *Status) Unban(target string) (bool, error) {
+	ban, err := NewBan(target, 0, "")
+	if err != nil {
+		return false, err
+	}
+	p.store.Lock()
+	defer p.store.Unlock()
+	if _, ok := p.bans[ban.Target]; !ok {
+		return false, nil
+	}
+	delete(p.bans, ban.Target)
+	return true, nil
+}
+
+// Bans returns the bans in effect, ordered by target.
+func (p *Status) Bans() []*Ban {
+	p.store.Lock()
+	defer p.store.Unlock()
+	now := time.Now()
+	bans := make([]*Ban, 0, len(p.bans))
+	for target, ban := range p.bans {
+		if ban.expired(now) {
+			delete(p.bans, target)
+			continue
+		}
+		bans = append(bans, ban)
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].Target < bans[j].Target
+	})
+	return bans
+}
+
+// IsBannedAddr returns true if the IP address of the given multiaddr is in a banned IP range.
+func (p *Status) IsBannedAddr(addr ma.Multiaddr) bool {
+	p.store.RLock()
+	defer p.store.RUnlock()
+	return p.isBannedAddr(addr)
+}
+
+// isBannedAddr is the lock-free version of IsBannedAddr.
+func (p *Status) isBannedAddr(addr ma.Multiaddr) bool {
+	if addr == nil || len(p.bans) == 0 {
+		return false
+	}
+	ip, err := manet.ToIP(addr)
+	if err != nil {
+		return false
+	}
+	now := time.Now()
+	for _, ban := range p.bans {
+		if ban.Network != nil && !ban.expired(now) && ban.Network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBanned returns an error if the peer, or its last known address, is banned.
+func (p *Status) isBanned(pid peer.ID) error {
+	if len(p.bans) == 0 {
+		return nil
+	}
+	if ban, ok := p.bans[pid.String()]; ok && !ban.expired(time.Now()) {
+		return ErrPeerBanned
+	}
+	if peerData, ok := p.store.PeerData(pid); ok && p.isBannedAddr(peerData.Address) {
+		return errors.Wrap(ErrPeerBanned, "address is in a banned IP range")
+	}
+	return nil
+}
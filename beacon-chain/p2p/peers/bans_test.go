@@ -0,0 +1,102 @@
+package peers_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	libp2ptest "github.com/libp2p/go-libp2p/p2p/host/peerstore/test"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers/scorers"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func TestNewBan(t *testing.T) {
+	pid := libp2ptest.GeneratePeerIDs(1)[0]
+	tests := []struct {
+		target string
+		want   string
+		err    string
+	}{
+		{target: "10.0.0.1", want: "10.0.0.1/32"},
+		{target: "10.0.0.1/16", want: "10.0.0.0/16"},
+		{target: "2001:db8::1", want: "2001:db8::1/128"},
+		{target: pid.String(), want: pid.String()},
+		{target: "foo", err: "neither a peer ID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			ban, err := peers.NewBan(tt.target, 0, "")
+			if tt.err != "" {
+				assert.ErrorContains(t, tt.err, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ban.Target)
+			assert.Equal(t, true, ban.Expiry.IsZero())
+		})
+	}
+	_, err := peers.NewBan("10.0.0.1", -time.Second, "")
+	assert.ErrorContains(t, "must not be negative", err)
+}
+
+func TestStatus_Bans(t *testing.T) {
+	persistPath := filepath.Join(t.TempDir(), "peers.json")
+	newStatus := func() *peers.Status {
+		return peers.NewStatus(context.Background(), &peers.StatusConfig{
+			PeerLimit:    30,
+			ScorerParams: &scorers.Config{},
+			PersistPath:  persistPath,
+		})
+	}
+	p := newStatus()
+	ids := libp2ptest.GeneratePeerIDs(3)
+	for i, addr := range []string{"/ip4/10.0.0.1/tcp/13000", "/ip4/10.1.0.1/tcp/13000", "/ip4/10.2.0.1/tcp/13000"} {
+		maddr, err := ma.NewMultiaddr(addr)
+		require.NoError(t, err)
+		p.Add(nil, ids[i], maddr, network.DirOutbound)
+	}
+
+	ban, err := peers.NewBan(ids[0].String(), 0, "spam")
+	require.NoError(t, err)
+	p.Ban(ban)
+	ban, err = peers.NewBan("10.1.0.0/16", time.Hour, "")
+	require.NoError(t, err)
+	p.Ban(ban)
+	ban, err = peers.NewBan("10.2.0.1", time.Nanosecond, "")
+	require.NoError(t, err)
+	p.Ban(ban)
+	time.Sleep(time.Millisecond)
+
+	assert.Equal(t, true, errors.Is(p.IsBad(ids[0]), peers.ErrPeerBanned))
+	assert.Equal(t, true, errors.Is(p.IsBad(ids[1]), peers.ErrPeerBanned))
+	assert.NoError(t, p.IsBad(ids[2]), "Expired bans are lifted")
+	banned, err := ma.NewMultiaddr("/ip4/10.1.2.3/udp/9000")
+	require.NoError(t, err)
+	assert.Equal(t, true, p.IsBannedAddr(banned))
+	bans := p.Bans()
+	require.Equal(t, 2, len(bans))
+	assert.Equal(t, "10.1.0.0/16", bans[0].Target)
+
+	// Bans survive a restart.
+	require.NoError(t, p.Persist())
+	p = newStatus()
+	bans = p.Bans()
+	require.Equal(t, 2, len(bans))
+	assert.Equal(t, "spam", bans[1].Reason)
+	assert.Equal(t, true, bans[1].Expiry.IsZero())
+	assert.Equal(t, true, p.IsBannedAddr(banned))
+
+	found, err := p.Unban("10.1.0.0/16")
+	require.NoError(t, err)
+	assert.Equal(t, true, found)
+	found, err = p.Unban("10.1.0.0/16")
+	require.NoError(t, err)
+	assert.Equal(t, false, found)
+	assert.Equal(t, false, p.IsBannedAddr(banned))
+}
@@ -0,0 +1,270 @@
+package peers
+
+import (
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers/peerdata"
+)
+
+const (
+	// UnknownClient is the client of peers whose agent version is unknown or not recognized.
+	UnknownClient = "unknown"
+
+	// ipv4SubnetBits and ipv6SubnetBits are the prefix lengths of the IP subnets peers are grouped by, which
+	// approximate the address blocks a hosting provider assigns. Peers are not grouped by autonomous system, so
+	// a provider announcing several address blocks is only capped per block.
+	ipv4SubnetBits = 24
+	ipv6SubnetBits = 48
+)
+
+// knownClients are the client names recognized in peer agent versions.
+var knownClients = []string{
+	"lighthouse",
+	"nimbus",
+	"prysm",
+	"teku",
+	"lodestar",
+	"js-libp2p",
+	"rust-libp2p",
+}
+
+// DiversityConfig holds the caps on the diversity of connected peers, enforced when pruning and dialing peers.
+type DiversityConfig struct {
+	// MaxClientRatio is the maximum proportion of the peer limit which may run the same client. Peers of an
+	// unknown client are not capped. A zero value disables the cap.
+	MaxClientRatio float64
+	// MaxIPSubnetPeers is the maximum number of connected peers from the same /24 IPv4 or /48 IPv6 subnet.
+	// Only these prefix buckets are enforced, there is no ASN-level cap. Private and loopback addresses are not
+	// capped. A zero value disables the cap.
+	MaxIPSubnetPeers int
+}
+
+// DiversityFeatures are the features of a peer its diversity is assessed by.
+type DiversityFeatures struct {
+	// Client is the client name derived from the agent version of the peer.
+	Client string
+	// IPSubnet is the /24 IPv4 or /48 IPv6 subnet of the peer address, empty if the address is unknown.
+	IPSubnet string
+	// Direction is the direction of the connection to the peer.
+	Direction network.Direction
+}
+
+// DiversityStats aggregates the diversity features of the connected peers.
+type DiversityStats struct {
+	Clients   map[string]int
+	IPSubnets map[string]int
+	Inbound   int
+	Outbound  int
+}
+
+// ClientFromAgentVersion returns the name of the client in the given libp2p agent version, or UnknownClient.
+func ClientFromAgentVersion(agent string) string {
+	agent = strings.ToLower(agent)
+	client := UnknownClient
+	for _, knownClient := range knownClients {
+		if strings.Contains(agent, knownClient) {
+			client = knownClient
+		}
+	}
+	return client
+}
+
+// SetAgentVersion sets the libp2p agent version of a peer.
+func (p *Status) SetAgentVersion(pid peer.ID, agent string) {
+	p.store.Lock()
+	defer p.store.Unlock()
+	peerData := p.store.PeerDataGetOrCreate(pid)
+	peerData.AgentVersion = agent
+}
+
+// AgentVersion returns the libp2p agent version of a peer, empty if it has not been identified yet.
+func (p *Status) AgentVersion(pid peer.ID) (string, error) {
+	p.store.RLock()
+	defer p.store.RUnlock()
+	if peerData, ok := p.store.PeerData(pid); ok {
+		return peerData.AgentVersion, nil
+	}
+	return "", peerdata.ErrPeerUnknown
+}
+
+// DiversityFeatures returns the diversity features of a peer.
+func (p *Status) DiversityFeatures(pid peer.ID) (*DiversityFeatures, error) {
+	p.store.RLock()
+	defer p.store.RUnlock()
+	peerData, ok := p.store.PeerData(pid)
+	if !ok {
+		return nil, peerdata.ErrPeerUnknown
+	}
+	return diversityFeatures(peerData), nil
+}
+
+// Diversity returns the diversity features of the connected peers, aggregated.
+func (p *Status) Diversity() *DiversityStats {
+	p.store.RLock()
+	defer p.store.RUnlock()
+	stats := &DiversityStats{
+		Clients:   map[string]int{},
+		IPSubnets: map[string]int{},
+	}
+	for _, peerData := range p.store.Peers() {
+		if peerData.ConnState != Connected {
+			continue
+		}
+		features := diversityFeatures(peerData)
+		stats.Clients[features.Client]++
+		if features.IPSubnet != "" {
+			stats.IPSubnets[features.IPSubnet]++
+		}
+		switch features.Direction {
+		case network.DirInbound:
+			stats.Inbound++
+		case network.DirOutbound:
+			stats.Outbound++
+		}
+	}
+	return stats
+}
+
+// IsIPSubnetFull returns true if the IP subnet of the given address already has as many active peers as the
+// configured cap, in which case no more peers from that subnet are dialed.
+func (p *Status) IsIPSubnetFull(addr ma.Multiaddr) bool {
+	if p.diversity.MaxIPSubnetPeers <= 0 {
+		return false
+	}
+	subnet := cappedIPSubnet(addr)
+	if subnet == "" {
+		return false
+	}
+	p.store.RLock()
+	defer p.store.RUnlock()
+	count := 0
+	for _, peerData := range p.store.Peers() {
+		if peerData.ConnState != Connected && peerData.ConnState != Connecting {
+			continue
+		}
+		if cappedIPSubnet(peerData.Address) == subnet {
+			count++
+		}
+	}
+	return count >= p.diversity.MaxIPSubnetPeers
+}
+
+// diversityCapsEnabled returns true if any diversity cap is configured.
+func (p *Status) diversityCapsEnabled() bool {
+	return p.diversity.MaxClientRatio > 0 || p.diversity.MaxIPSubnetPeers > 0
+}
+
+// peersBeyondDiversityCaps returns the connected peers to prune for the connected peers to be within the
+// diversity caps. Within a client or IP subnet beyond its cap, inbound peers are pruned before outbound ones,
+// and lower scored peers before higher scored ones. Trusted peers count towards the caps but are never pruned.
+// This method assumes the store lock is acquired before executing the method.
+func (p *Status) peersBeyondDiversityCaps() map[peer.ID]bool {
+	beyondCaps := make(map[peer.ID]bool)
+	if !p.diversityCapsEnabled() {
+		return beyondCaps
+	}
+	byClient := make(map[string][]peer.ID)
+	bySubnet := make(map[string][]peer.ID)
+	for pid, peerData := range p.store.Peers() {
+		if peerData.ConnState != Connected {
+			continue
+		}
+		if client := ClientFromAgentVersion(peerData.AgentVersion); client != UnknownClient {
+			byClient[client] = append(byClient[client], pid)
+		}
+		if subnet := cappedIPSubnet(peerData.Address); subnet != "" {
+			bySubnet[subnet] = append(bySubnet[subnet], pid)
+		}
+	}
+	if p.diversity.MaxClientRatio > 0 {
+		maxClientPeers := max(int(p.diversity.MaxClientRatio*float64(p.ConnectedPeerLimit())), 1)
+		for _, pids := range byClient {
+			p.markBeyondCap(pids, maxClientPeers, beyondCaps)
+		}
+	}
+	if p.diversity.MaxIPSubnetPeers > 0 {
+		for _, pids := range bySubnet {
+			p.markBeyondCap(pids, p.diversity.MaxIPSubnetPeers, beyondCaps)
+		}
+	}
+	return beyondCaps
+}
+
+// markBeyondCap marks the peers of a group to prune for the group to be within the given cap. Peers already marked
+// do not count towards the cap.
+func (p *Status) markBeyondCap(pids []peer.ID, limit int, beyondCaps map[peer.ID]bool) {
+	candidates := make([]peer.ID, 0, len(pids))
+	for _, pid := range pids {
+		if !beyondCaps[pid] {
+			candidates = append(candidates, pid)
+		}
+	}
+	excess := len(candidates) - limit
+	if excess <= 0 {
+		return
+	}
+	inbound := func(pid peer.ID) bool {
+		peerData, ok := p.store.PeerData(pid)
+		return ok && peerData.Direction == network.DirInbound
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if inbound(candidates[i]) != inbound(candidates[j]) {
+			return inbound(candidates[i])
+		}
+		return p.scorers.ScoreNoLock(candidates[i]) < p.scorers.ScoreNoLock(candidates[j])
+	})
+	for _, pid := range candidates {
+		if excess == 0 {
+			return
+		}
+		if p.store.IsTrustedPeer(pid) {
+			continue
+		}
+		beyondCaps[pid] = true
+		excess--
+	}
+}
+
+func diversityFeatures(peerData *peerdata.PeerData) *DiversityFeatures {
+	return &DiversityFeatures{
+		Client:    ClientFromAgentVersion(peerData.AgentVersion),
+		IPSubnet:  ipSubnet(peerData.Address),
+		Direction: peerData.Direction,
+	}
+}
+
+// ipSubnet returns the /24 IPv4 or /48 IPv6 subnet of the given address, or an empty string if it has no IP.
+func ipSubnet(addr ma.Multiaddr) string {
+	if addr == nil {
+		return ""
+	}
+	ip, err := manet.ToIP(addr)
+	if err != nil {
+		return ""
+	}
+	bits, size := ipv6SubnetBits, 8*net.IPv6len
+	if ip4 := ip.To4(); ip4 != nil {
+		ip, bits, size = ip4, ipv4SubnetBits, 8*net.IPv4len
+	}
+	mask := net.CIDRMask(bits, size)
+	return (&net.IPNet{IP: ip.Mask(mask), Mask: mask}).String()
+}
+
+// cappedIPSubnet is like ipSubnet, but returns an empty string for private and loopback addresses, which are
+// not subject to the IP subnet cap.
+func cappedIPSubnet(addr ma.Multiaddr) string {
+	if addr == nil {
+		return ""
+	}
+	ip, err := manet.ToIP(addr)
+	if err != nil || ip.IsPrivate() || ip.IsLoopback() {
+		return ""
+	}
+	return ipSubnet(addr)
+}
@@ -0,0 +1,150 @@
+package peers_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	libp2ptest "github.com/libp2p/go-libp2p/p2p/host/peerstore/test"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers/scorers"
+	"github.com/prysmaticlabs/prysm/v5/config/features"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func TestClientFromAgentVersion(t *testing.T) {
+	assert.Equal(t, "prysm", peers.ClientFromAgentVersion("Prysm/v5.2.0/abcdef"))
+	assert.Equal(t, "lighthouse", peers.ClientFromAgentVersion("Lighthouse/v6.0.0-aa/x86_64-linux"))
+	assert.Equal(t, peers.UnknownClient, peers.ClientFromAgentVersion(""))
+	assert.Equal(t, peers.UnknownClient, peers.ClientFromAgentVersion("grandine/1.0"))
+}
+
+func TestStatus_DiversityFeatures(t *testing.T) {
+	p := peers.NewStatus(context.Background(), &peers.StatusConfig{
+		PeerLimit:    30,
+		ScorerParams: &scorers.Config{},
+	})
+	ids := libp2ptest.GeneratePeerIDs(3)
+	for i, addr := range []string{"/ip4/1.2.3.4/tcp/13000", "/ip4/1.2.3.200/tcp/13000", "/ip6/2001:db8:1:2::1/udp/9000/quic-v1"} {
+		maddr, err := ma.NewMultiaddr(addr)
+		require.NoError(t, err)
+		direction := network.DirInbound
+		if i == 2 {
+			direction = network.DirOutbound
+		}
+		p.Add(nil, ids[i], maddr, direction)
+		p.SetConnectionState(ids[i], peers.Connected)
+	}
+	p.SetAgentVersion(ids[0], "Prysm/v5.2.0")
+	p.SetAgentVersion(ids[1], "teku/v24.10.0")
+
+	features, err := p.DiversityFeatures(ids[0])
+	require.NoError(t, err)
+	assert.Equal(t, "prysm", features.Client)
+	assert.Equal(t, "1.2.3.0/24", features.IPSubnet)
+	assert.Equal(t, network.DirInbound, features.Direction)
+	features, err = p.DiversityFeatures(ids[2])
+	require.NoError(t, err)
+	assert.Equal(t, peers.UnknownClient, features.Client)
+	assert.Equal(t, "2001:db8:1::/48", features.IPSubnet)
+
+	stats := p.Diversity()
+	assert.DeepEqual(t, map[string]int{"prysm": 1, "teku": 1, peers.UnknownClient: 1}, stats.Clients)
+	assert.DeepEqual(t, map[string]int{"1.2.3.0/24": 2, "2001:db8:1::/48": 1}, stats.IPSubnets)
+	assert.Equal(t, 2, stats.Inbound)
+	assert.Equal(t, 1, stats.Outbound)
+}
+
+func TestStatus_PeersToPrune_DiversityCaps(t *testing.T) {
+	for _, enablePeerScorer := range []bool{true, false} {
+		t.Run(fmt.Sprintf("peer scorer %t", enablePeerScorer), func(t *testing.T) {
+			resetCfg := features.InitWithReset(&features.Flags{EnablePeerScorer: enablePeerScorer})
+			defer resetCfg()
+
+			// The peer limit is well above the number of connected peers, only the diversity caps trigger pruning.
+			p := peers.NewStatus(context.Background(), &peers.StatusConfig{
+				PeerLimit:    25,
+				ScorerParams: &scorers.Config{},
+				Diversity: &peers.DiversityConfig{
+					MaxClientRatio:   0.2,
+					MaxIPSubnetPeers: 2,
+				},
+			})
+			add := func(addr, agent string, direction network.Direction) peer.ID {
+				pid := libp2ptest.GeneratePeerIDs(1)[0]
+				maddr, err := ma.NewMultiaddr(addr)
+				require.NoError(t, err)
+				p.Add(nil, pid, maddr, direction)
+				p.SetConnectionState(pid, peers.Connected)
+				p.SetAgentVersion(pid, agent)
+				return pid
+			}
+			// Four peers share a public subnet: the inbound ones are pruned before the outbound ones.
+			subnetInbound1 := add("/ip4/8.8.8.1/tcp/13000", "teku", network.DirInbound)
+			subnetInbound2 := add("/ip4/8.8.8.2/tcp/13000", "teku", network.DirInbound)
+			add("/ip4/8.8.8.3/tcp/13000", "teku", network.DirOutbound)
+			add("/ip4/8.8.8.4/tcp/13000", "teku", network.DirOutbound)
+			// Private subnets are not capped.
+			for i := 1; i <= 3; i++ {
+				add(fmt.Sprintf("/ip4/192.168.0.%d/tcp/13000", i), "nimbus", network.DirInbound)
+			}
+			// Six prysm peers exceed a fifth of the peer limit: one of them, an inbound one, is pruned.
+			prysmInbound := add("/ip4/9.9.1.1/tcp/13000", "prysm", network.DirInbound)
+			for i := 2; i <= 6; i++ {
+				add(fmt.Sprintf("/ip4/9.9.%d.1/tcp/13000", i), "prysm", network.DirOutbound)
+			}
+			// Unknown clients are not capped.
+			for i := 1; i <= 6; i++ {
+				add(fmt.Sprintf("/ip4/7.7.%d.1/tcp/13000", i), "", network.DirOutbound)
+			}
+
+			pruned := p.PeersToPrune()
+			want := []peer.ID{subnetInbound1, subnetInbound2, prysmInbound}
+			sort.Slice(pruned, func(i, j int) bool { return pruned[i] < pruned[j] })
+			sort.Slice(want, func(i, j int) bool { return want[i] < want[j] })
+			assert.DeepEqual(t, want, pruned)
+			// Pruned peers are not re-dialed right away, as they would be pruned again.
+			for _, pid := range pruned {
+				assert.Equal(t, false, p.IsReadyToDial(pid))
+			}
+
+			full, err := ma.NewMultiaddr("/ip4/8.8.8.100/tcp/13000")
+			require.NoError(t, err)
+			assert.Equal(t, true, p.IsIPSubnetFull(full))
+			notFull, err := ma.NewMultiaddr("/ip4/8.8.9.100/tcp/13000")
+			require.NoError(t, err)
+			assert.Equal(t, false, p.IsIPSubnetFull(notFull))
+		})
+	}
+}
+
+func TestStatus_PeersToPrune_DiversityCapsBelowLimit(t *testing.T) {
+	for _, enablePeerScorer := range []bool{true, false} {
+		t.Run(fmt.Sprintf("peer scorer %t", enablePeerScorer), func(t *testing.T) {
+			resetCfg := features.InitWithReset(&features.Flags{EnablePeerScorer: enablePeerScorer})
+			defer resetCfg()
+
+			p := peers.NewStatus(context.Background(), &peers.StatusConfig{
+				PeerLimit:    10,
+				ScorerParams: &scorers.Config{},
+				Diversity: &peers.DiversityConfig{
+					MaxIPSubnetPeers: 2,
+				},
+			})
+			// Nine inbound peers are above the inbound limit, but within the peer limit and the diversity caps.
+			for i := 1; i <= 9; i++ {
+				pid := libp2ptest.GeneratePeerIDs(1)[0]
+				maddr, err := ma.NewMultiaddr(fmt.Sprintf("/ip4/8.8.%d.1/tcp/13000", i))
+				require.NoError(t, err)
+				p.Add(nil, pid, maddr, network.DirInbound)
+				p.SetConnectionState(pid, peers.Connected)
+			}
+			assert.Equal(t, 0, len(p.PeersToPrune()))
+		})
+	}
+}
@@ -52,6 +52,7 @@ type PeerData struct {
 	ConnState     ConnectionState
 	Enr           *enr.Record
 	NextValidTime time.Time
+	AgentVersion  string
 	// Chain related data.
 	MetaData                  metadata.Metadata
 	ChainState                *ethpb.Status
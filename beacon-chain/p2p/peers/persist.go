@@ -0,0 +1,240 @@
+package peers
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers/peerdata"
+	"github.com/prysmaticlabs/prysm/v5/io/file"
+)
+
+// persistedGossipScoreHalfLife is the time after which a persisted gossip score and behaviour penalty are halved
+// when they are restored.
+const persistedGossipScoreHalfLife = time.Hour
+
+// persistedPeers is the on-disk snapshot of the peer status store.
+type persistedPeers struct {
+	SavedAt time.Time        `json:"saved_at"`
+	Peers   []*persistedPeer `json:"peers"`
+	Bans    []*persistedBan  `json:"bans,omitempty"`
+}
+
+// persistedPeer holds the data of a single peer which outlives a restart of the node. The address is only set
+// for peers we successfully dialed, as the address of an inbound peer is usually not dialable.
+type persistedPeer struct {
+	ID               peer.ID `json:"id"`
+	Address          string  `json:"address,omitempty"`
+	ENR              []byte  `json:"enr,omitempty"`
+	BadResponses     int     `json:"bad_responses,omitempty"`
+	ProcessedBlocks  uint64  `json:"processed_blocks,omitempty"`
+	GossipScore      float64 `json:"gossip_score,omitempty"`
+	BehaviourPenalty float64 `json:"behaviour_penalty,omitempty"`
+}
+
+// persistedBan is an operator ban which outlives a restart of the node.
+type persistedBan struct {
+	Target string    `json:"target"`
+	Reason string    `json:"reason,omitempty"`
+	Expiry time.Time `json:"expiry"`
+}
+
+// Persist writes the scores, known-good multiaddrs and ENRs of the peers in the store, and the operator bans, to the
+// persistence file. Ban state is otherwise carried by the scores themselves: a peer whose restored scores are still
+// past a scorer threshold remains bad after a restart. This is a no-op if persistence is disabled.
+func (p *Status) Persist() error {
+	if p.persistPath == "" {
+		return nil
+	}
+	snapshot := &persistedPeers{SavedAt: time.Now()}
+	p.store.RLock()
+	for pid, peerData := range p.store.Peers() {
+		pp := &persistedPeer{
+			ID:               pid,
+			BadResponses:     peerData.BadResponses,
+			ProcessedBlocks:  peerData.ProcessedBlocks,
+			GossipScore:      peerData.GossipScore,
+			BehaviourPenalty: peerData.BehaviourPenalty,
+		}
+		if peerData.Address != nil && peerData.Direction == network.DirOutbound {
+			pp.Address = peerData.Address.String()
+		}
+		if peerData.Enr != nil {
+			// Unsigned records cannot be encoded, they are not persisted.
+			if enc, err := rlp.EncodeToBytes(peerData.Enr); err == nil {
+				pp.ENR = enc
+			}
+		}
+		snapshot.Peers = append(snapshot.Peers, pp)
+	}
+	for _, ban := range p.bans {
+		if !ban.expired(snapshot.SavedAt) {
+			snapshot.Bans = append(snapshot.Bans, &persistedBan{Target: ban.Target, Reason: ban.Reason, Expiry: ban.Expiry})
+		}
+	}
+	p.store.RUnlock()
+
+	enc, err := json.Marshal(snapshot)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal peers")
+	}
+	// Write to a temporary file first, so that a crash while writing never leaves a truncated file behind.
+	tmpPath := p.persistPath + ".tmp"
+	if err := file.WriteFile(tmpPath, enc); err != nil {
+		return errors.Wrap(err, "could not write peers file")
+	}
+	return os.Rename(tmpPath, p.persistPath)
+}
+
+// loadPersisted restores the peers of the persistence file into the store, with their scores decayed by the time
+// elapsed since they were saved. Every peer which is still bad is restored, along with the best scored of the other
+// peers up to the peer limit. A missing file is not an error.
+func (p *Status) loadPersisted(now time.Time) error {
+	enc, err := os.ReadFile(p.persistPath) // #nosec G304
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrap(err, "could not read peers file")
+	}
+	snapshot := &persistedPeers{}
+	if err := json.Unmarshal(enc, snapshot); err != nil {
+		return errors.Wrap(err, "could not unmarshal peers file")
+	}
+	elapsed := now.Sub(snapshot.SavedAt)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	badResponsesDecay := decaySteps(elapsed, p.scorers.BadResponsesScorer().Params().DecayInterval)
+	blockProviderParams := p.scorers.BlockProviderScorer().Params()
+	processedBlocksDecay := decaySteps(elapsed, blockProviderParams.DecayInterval) * blockProviderParams.Decay
+	gossipDecay := math.Pow(0.5, float64(elapsed)/float64(persistedGossipScoreHalfLife))
+
+	p.store.Lock()
+	defer p.store.Unlock()
+	for _, pb := range snapshot.Bans {
+		ban, err := NewBan(pb.Target, 0, pb.Reason)
+		if err != nil {
+			log.WithError(err).Debug("Could not parse persisted ban")
+			continue
+		}
+		ban.Expiry = pb.Expiry
+		if !ban.expired(now) {
+			p.bans[ban.Target] = ban
+		}
+	}
+	restored := make([]peer.ID, 0, len(snapshot.Peers))
+	for _, pp := range snapshot.Peers {
+		if _, ok := p.store.PeerData(pp.ID); ok {
+			continue
+		}
+		peerData := &peerdata.PeerData{
+			ConnState:        Disconnected,
+			BadResponses:     max(pp.BadResponses-int(badResponsesDecay), 0),
+			GossipScore:      pp.GossipScore * gossipDecay,
+			BehaviourPenalty: pp.BehaviourPenalty * gossipDecay,
+		}
+		if pp.ProcessedBlocks > processedBlocksDecay {
+			peerData.ProcessedBlocks = pp.ProcessedBlocks - processedBlocksDecay
+		}
+		if pp.Address != "" {
+			address, err := ma.NewMultiaddr(pp.Address)
+			if err != nil {
+				log.WithError(err).WithField("peer", pp.ID).Debug("Could not parse persisted peer address")
+				continue
+			}
+			peerData.Address = address
+			peerData.Direction = network.DirOutbound
+		}
+		if len(pp.ENR) > 0 {
+			record := &enr.Record{}
+			if err := rlp.DecodeBytes(pp.ENR, record); err != nil {
+				log.WithError(err).WithField("peer", pp.ID).Debug("Could not decode persisted peer ENR")
+				continue
+			}
+			peerData.Enr = record
+		}
+		p.store.SetPeerData(pp.ID, peerData)
+		restored = append(restored, pp.ID)
+	}
+
+	// Peers which are still bad are all kept, so that they stay banned. Of the others, only the best scored ones
+	// up to the peer limit are kept.
+	good := make([]peer.ID, 0, len(restored))
+	scores := make(map[peer.ID]float64, len(restored))
+	kept := 0
+	for _, pid := range restored {
+		if p.isBad(pid) != nil {
+			p.addIpToTracker(pid)
+			kept++
+			continue
+		}
+		good = append(good, pid)
+		scores[pid] = p.scorers.ScoreNoLock(pid)
+	}
+	sort.Slice(good, func(i, j int) bool {
+		return scores[good[i]] > scores[good[j]]
+	})
+	for i, pid := range good {
+		if i >= p.store.Config().MaxPeers {
+			p.store.DeletePeerData(pid)
+			continue
+		}
+		p.addIpToTracker(pid)
+		kept++
+	}
+	log.WithField("count", kept).Info("Restored persisted peers")
+	return nil
+}
+
+// DialCandidates returns the address info of up to limit disconnected peers with a known-good address which are
+// not considered bad, ordered by descending score. These are dialed at startup, before discovery finds new peers.
+func (p *Status) DialCandidates(limit int) []peer.AddrInfo {
+	p.store.RLock()
+	defer p.store.RUnlock()
+
+	type candidate struct {
+		info  peer.AddrInfo
+		score float64
+	}
+	candidates := make([]*candidate, 0)
+	for pid, peerData := range p.store.Peers() {
+		if peerData.ConnState != Disconnected || peerData.Address == nil || peerData.Direction != network.DirOutbound {
+			continue
+		}
+		if peerData.NextValidTime.After(time.Now()) || p.isBad(pid) != nil {
+			continue
+		}
+		candidates = append(candidates, &candidate{
+			info:  peer.AddrInfo{ID: pid, Addrs: []ma.Multiaddr{peerData.Address}},
+			score: p.scorers.ScoreNoLock(pid),
+		})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	infos := make([]peer.AddrInfo, len(candidates))
+	for i, c := range candidates {
+		infos[i] = c.info
+	}
+	return infos
+}
+
+// decaySteps returns the number of decay intervals within the elapsed time.
+func decaySteps(elapsed, interval time.Duration) uint64 {
+	if interval <= 0 {
+		return 0
+	}
+	return uint64(elapsed / interval)
+}
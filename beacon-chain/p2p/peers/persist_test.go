@@ -0,0 +1,171 @@
+package peers_test
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gethCrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	libp2ptest "github.com/libp2p/go-libp2p/p2p/host/peerstore/test"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers/scorers"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func TestStatus_PersistAndRestore(t *testing.T) {
+	persistPath := filepath.Join(t.TempDir(), "peers.json")
+	newStatus := func() *peers.Status {
+		return peers.NewStatus(context.Background(), &peers.StatusConfig{
+			PeerLimit: 30,
+			ScorerParams: &scorers.Config{
+				BadResponsesScorerConfig: &scorers.BadResponsesScorerConfig{
+					Threshold:     5,
+					DecayInterval: time.Hour,
+				},
+				BlockProviderScorerConfig: &scorers.BlockProviderScorerConfig{
+					DecayInterval: time.Hour,
+					Decay:         64,
+				},
+			},
+			PersistPath: persistPath,
+		})
+	}
+
+	// Nothing is restored without a persisted file.
+	p := newStatus()
+	assert.Equal(t, 0, len(p.All()))
+	addPersistedPeer := func(addr ma.Multiaddr, direction network.Direction) peer.ID {
+		key, _, err := crypto.GenerateSecp256k1Key(rand.Reader)
+		require.NoError(t, err)
+		pid, err := peer.IDFromPrivateKey(key)
+		require.NoError(t, err)
+		p.Add(nil, pid, addr, direction)
+		return pid
+	}
+
+	goodAddr, err := ma.NewMultiaddr("/ip4/1.1.1.1/tcp/13000")
+	require.NoError(t, err)
+	good := addPersistedPeer(goodAddr, network.DirOutbound)
+	key, err := gethCrypto.GenerateKey()
+	require.NoError(t, err)
+	record := &enr.Record{}
+	record.SetSeq(7)
+	require.NoError(t, enode.SignV4(record, key))
+	p.UpdateENR(record, good)
+	p.Scorers().BadResponsesScorer().Increment(good)
+	p.Scorers().BlockProviderScorer().IncrementProcessedBlocks(good, 640)
+
+	inboundAddr, err := ma.NewMultiaddr("/ip4/2.2.2.2/tcp/40000")
+	require.NoError(t, err)
+	inbound := addPersistedPeer(inboundAddr, network.DirInbound)
+
+	badAddr, err := ma.NewMultiaddr("/ip4/3.3.3.3/tcp/13000")
+	require.NoError(t, err)
+	bad := addPersistedPeer(badAddr, network.DirOutbound)
+	for i := 0; i < 6; i++ {
+		p.Scorers().BadResponsesScorer().Increment(bad)
+	}
+	p.Scorers().GossipScorer().SetGossipData(bad, -10, 4, nil)
+	require.NoError(t, p.Persist())
+
+	// Pretend the snapshot was saved an hour ago.
+	enc, err := os.ReadFile(persistPath)
+	require.NoError(t, err)
+	snapshot := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(enc, &snapshot))
+	snapshot["saved_at"] = time.Now().Add(-time.Hour).Format(time.RFC3339Nano)
+	enc, err = json.Marshal(snapshot)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(persistPath, enc, 0600))
+
+	p = newStatus()
+	assert.Equal(t, 3, len(p.All()))
+	count, err := p.Scorers().BadResponsesScorer().Count(good)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+	assert.Equal(t, uint64(576), p.Scorers().BlockProviderScorer().ProcessedBlocks(good))
+	restoredRecord, err := p.ENR(good)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), restoredRecord.Seq())
+	addr, err := p.Address(good)
+	require.NoError(t, err)
+	assert.Equal(t, goodAddr.String(), addr.String())
+
+	// The address of an inbound peer is not dialable, so it is not kept.
+	addr, err = p.Address(inbound)
+	require.NoError(t, err)
+	assert.Equal(t, nil, addr)
+
+	// The bad peer is still banned after decaying.
+	assert.NotNil(t, p.IsBad(bad))
+	gossipScore, penalty, _, err := p.Scorers().GossipScorer().GossipData(bad)
+	require.NoError(t, err)
+	assert.Equal(t, float64(-5), math.Round(gossipScore))
+	assert.Equal(t, float64(2), math.Round(penalty))
+
+	candidates := p.DialCandidates(10)
+	require.Equal(t, 1, len(candidates))
+	assert.Equal(t, good, candidates[0].ID)
+	assert.Equal(t, goodAddr.String(), candidates[0].Addrs[0].String())
+	assert.Equal(t, 0, len(p.DialCandidates(0)))
+}
+
+func TestStatus_RestoreBeyondPeerLimit(t *testing.T) {
+	persistPath := filepath.Join(t.TempDir(), "peers.json")
+	newStatus := func(peerLimit int) *peers.Status {
+		return peers.NewStatus(context.Background(), &peers.StatusConfig{
+			PeerLimit: peerLimit,
+			ScorerParams: &scorers.Config{
+				BadResponsesScorerConfig: &scorers.BadResponsesScorerConfig{
+					Threshold:     5,
+					DecayInterval: time.Hour,
+				},
+			},
+			PersistPath: persistPath,
+		})
+	}
+
+	// More good peers than the peer store holds, with increasing scores, and a few bad ones.
+	p := newStatus(300)
+	good := libp2ptest.GeneratePeerIDs(160)
+	for i, pid := range good {
+		p.Add(nil, pid, nil, network.DirOutbound)
+		p.Scorers().GossipScorer().SetGossipData(pid, float64(i+1), 0, nil)
+	}
+	bad := libp2ptest.GeneratePeerIDs(10)
+	for _, pid := range bad {
+		p.Add(nil, pid, nil, network.DirOutbound)
+		for i := 0; i < 6; i++ {
+			p.Scorers().BadResponsesScorer().Increment(pid)
+		}
+	}
+	require.NoError(t, p.Persist())
+
+	// The store of a node with a peer limit of 1 holds 151 peers: every bad peer is restored, along with the
+	// best scored good peers.
+	p = newStatus(1)
+	assert.Equal(t, 161, len(p.All()))
+	for _, pid := range bad {
+		assert.NotNil(t, p.IsBad(pid))
+	}
+	for i, pid := range good {
+		_, err := p.ConnectionState(pid)
+		if i < 9 {
+			assert.ErrorContains(t, "peer unknown", err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+}
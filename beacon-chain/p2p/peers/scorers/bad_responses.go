@@ -115,6 +115,14 @@ func (s *BadResponsesScorer) Increment(pid peer.ID) {
 	peerData.BadResponses++
 }
 
+// Set overrides the number of bad responses we have received from the given remote peer.
+func (s *BadResponsesScorer) Set(pid peer.ID, count int) {
+	s.store.Lock()
+	defer s.store.Unlock()
+
+	s.store.PeerDataGetOrCreate(pid).BadResponses = max(count, 0)
+}
+
 // IsBadPeer states if the peer is to be considered bad.
 // If the peer is unknown this will return `false`, which makes using this function easier than returning an error.
 func (s *BadResponsesScorer) IsBadPeer(pid peer.ID) error {
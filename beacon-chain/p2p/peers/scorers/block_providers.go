@@ -140,6 +140,15 @@ func (s *BlockProviderScorer) IncrementProcessedBlocks(pid peer.ID, cnt uint64)
 	}
 }
 
+// SetProcessedBlocks overrides the number of processed blocks of a given peer, up to the processed blocks cap.
+func (s *BlockProviderScorer) SetProcessedBlocks(pid peer.ID, cnt uint64) {
+	s.store.Lock()
+	defer s.store.Unlock()
+	defer s.touchNoLock(pid)
+
+	s.store.PeerDataGetOrCreate(pid).ProcessedBlocks = min(cnt, s.config.ProcessedBlocksCap)
+}
+
 // Touch updates last access time for a given peer. This allows to detect peers that are
 // stale and boost their scores to increase chances in block fetching participation.
 func (s *BlockProviderScorer) Touch(pid peer.ID, t ...time.Time) {
@@ -19,7 +19,8 @@
 //
 // Peer information is persistent for the run of the service. This allows for collection of useful
 // long-term statistics such as number of bad responses obtained from the peer, giving the basis for
-// decisions to not talk to known-bad peers (by de-scoring them).
+// decisions to not talk to known-bad peers (by de-scoring them). Scores and known-good addresses can
+// also be persisted to disk, so that they survive a restart of the node.
 package peers
 
 import (
@@ -42,7 +43,6 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/config/params"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
 	"github.com/prysmaticlabs/prysm/v5/crypto/rand"
-	pmath "github.com/prysmaticlabs/prysm/v5/math"
 	pb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
 	"github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1/metadata"
 	prysmTime "github.com/prysmaticlabs/prysm/v5/time"
@@ -76,6 +76,8 @@ const (
 	MinBackOffDuration = 100
 	// MaxBackOffDuration maximum amount (in milliseconds) to wait before peer is re-dialed.
 	MaxBackOffDuration = 5000
+	// DiversityBackOffDuration is the amount of time to wait before a peer pruned for the diversity caps is re-dialed.
+	DiversityBackOffDuration = 10 * time.Minute
 )
 
 type InternetProtocol string
@@ -87,11 +89,14 @@ const (
 
 // Status is the structure holding the peer status information.
 type Status struct {
-	ctx       context.Context
-	scorers   *scorers.Service
-	store     *peerdata.Store
-	ipTracker map[string]uint64
-	rand      *rand.Rand
+	ctx         context.Context
+	scorers     *scorers.Service
+	store       *peerdata.Store
+	ipTracker   map[string]uint64
+	rand        *rand.Rand
+	persistPath string
+	bans        map[string]*Ban
+	diversity   DiversityConfig
 }
 
 // StatusConfig represents peer status service params.
@@ -100,6 +105,11 @@ type StatusConfig struct {
 	PeerLimit int
 	// ScorerParams holds peer scorer configuration params.
 	ScorerParams *scorers.Config
+	// PersistPath is the file peer scores and addresses are persisted to, and restored from on startup.
+	// Persistence is disabled if it is empty.
+	PersistPath string
+	// Diversity holds the caps on the diversity of connected peers. No caps are enforced if it is nil.
+	Diversity *DiversityConfig
 }
 
 // NewStatus creates a new status entity.
@@ -107,15 +117,26 @@ func NewStatus(ctx context.Context, config *StatusConfig) *Status {
 	store := peerdata.NewStore(ctx, &peerdata.StoreConfig{
 		MaxPeers: maxLimitBuffer + config.PeerLimit,
 	})
-	return &Status{
-		ctx:       ctx,
-		store:     store,
-		scorers:   scorers.NewService(ctx, store, config.ScorerParams),
-		ipTracker: map[string]uint64{},
+	p := &Status{
+		ctx:         ctx,
+		store:       store,
+		scorers:     scorers.NewService(ctx, store, config.ScorerParams),
+		ipTracker:   map[string]uint64{},
+		persistPath: config.PersistPath,
+		bans:        map[string]*Ban{},
 		// Random generator used to calculate dial backoff period.
 		// It is ok to use deterministic generator, no need for true entropy.
 		rand: rand.NewDeterministicGenerator(),
 	}
+	if config.Diversity != nil {
+		p.diversity = *config.Diversity
+	}
+	if p.persistPath != "" {
+		if err := p.loadPersisted(time.Now()); err != nil {
+			log.WithError(err).Warn("Could not restore persisted peers")
+		}
+	}
+	return p
 }
 
 func (p *Status) UpdateENR(record *enr.Record, pid peer.ID) {
@@ -354,6 +375,11 @@ func (p *Status) IsBad(pid peer.ID) error {
 
 // isBad is the lock-free version of IsBad.
 func (p *Status) isBad(pid peer.ID) error {
+	// Operator bans take precedence over trust.
+	if err := p.isBanned(pid); err != nil {
+		return err
+	}
+
 	// Do not disconnect from trusted peers.
 	if p.store.IsTrustedPeer(pid) {
 		return nil
@@ -811,6 +837,9 @@ func (p *Status) BestNonFinalized(minPeers int, ourHeadEpoch primitives.Epoch) (
 // the pruning relies on simple heuristics such as
 // bad response count. In the future scoring will be used
 // to determine the most suitable peers to take out.
+// Peers beyond the diversity caps, inbound or outbound,
+// are always pruned first, and are not re-dialed for
+// DiversityBackOffDuration.
 func (p *Status) PeersToPrune() []peer.ID {
 	if !features.Get().EnablePeerScorer {
 		return p.deprecatedPeersToPrune()
@@ -820,47 +849,62 @@ func (p *Status) PeersToPrune() []peer.ID {
 	activePeers := p.Active()
 	numInboundPeers := uint64(len(p.InboundConnected()))
 	// Exit early if we are still below our max
-	// limit.
-	if uint64(len(activePeers)) <= connLimit {
+	// limit, and no diversity cap is configured.
+	if uint64(len(activePeers)) <= connLimit && !p.diversityCapsEnabled() {
 		return []peer.ID{}
 	}
 	p.store.Lock()
 	defer p.store.Unlock()
 
 	type peerResp struct {
-		pid   peer.ID
-		score float64
+		pid        peer.ID
+		score      float64
+		beyondCaps bool
+	}
+	beyondCaps := p.peersBeyondDiversityCaps()
+	// Exit early if we are still below our max limit, and
+	// no peer is beyond the diversity caps.
+	aboveLimit := uint64(len(activePeers)) > connLimit
+	if !aboveLimit && len(beyondCaps) == 0 {
+		return []peer.ID{}
 	}
 	peersToPrune := make([]*peerResp, 0)
-	// Select connected and inbound peers to prune.
+	// Select connected and inbound peers, and peers beyond
+	// the diversity caps, to prune.
 	for pid, peerData := range p.store.Peers() {
-		if peerData.ConnState == Connected &&
-			peerData.Direction == network.DirInbound && !p.store.IsTrustedPeer(pid) {
+		if peerData.ConnState == Connected && (beyondCaps[pid] ||
+			peerData.Direction == network.DirInbound && !p.store.IsTrustedPeer(pid)) {
 			peersToPrune = append(peersToPrune, &peerResp{
-				pid:   pid,
-				score: p.scorers.ScoreNoLock(pid),
+				pid:        pid,
+				score:      p.scorers.ScoreNoLock(pid),
+				beyondCaps: beyondCaps[pid],
 			})
 		}
 	}
 
 	// Sort in ascending order to favour pruning peers with a
-	// lower score.
+	// lower score, after the peers beyond the diversity caps.
 	sort.Slice(peersToPrune, func(i, j int) bool {
+		if peersToPrune[i].beyondCaps != peersToPrune[j].beyondCaps {
+			return peersToPrune[i].beyondCaps
+		}
 		return peersToPrune[i].score < peersToPrune[j].score
 	})
 
 	// Determine amount of peers to prune using our
 	// max connection limit.
-	amountToPrune, err := pmath.Sub64(uint64(len(activePeers)), connLimit)
-	if err != nil {
-		// This should never happen.
-		log.WithError(err).Error("Failed to determine amount of peers to prune")
-		return []peer.ID{}
+	amountToPrune := uint64(0)
+	if aboveLimit {
+		amountToPrune = uint64(len(activePeers)) - connLimit
+	}
+	// Peers beyond the diversity caps are pruned regardless.
+	if uint64(len(beyondCaps)) > amountToPrune {
+		amountToPrune = uint64(len(beyondCaps))
 	}
 
 	// Also check for inbound peers above our limit.
 	excessInbound := uint64(0)
-	if numInboundPeers > inBoundLimit {
+	if aboveLimit && numInboundPeers > inBoundLimit {
 		excessInbound = numInboundPeers - inBoundLimit
 	}
 	// Prune the largest amount between excess peers and
@@ -874,6 +918,9 @@ func (p *Status) PeersToPrune() []peer.ID {
 	ids := make([]peer.ID, 0, len(peersToPrune))
 	for _, pr := range peersToPrune {
 		ids = append(ids, pr.pid)
+		if pr.beyondCaps {
+			p.backOffDiversityPruned(pr.pid)
+		}
 	}
 	return ids
 }
@@ -886,46 +933,62 @@ func (p *Status) deprecatedPeersToPrune() []peer.ID {
 	activePeers := p.Active()
 	numInboundPeers := len(p.InboundConnected())
 	// Exit early if we are still below our max
-	// limit.
-	if uint64(len(activePeers)) <= connLimit {
+	// limit, and no diversity cap is configured.
+	if uint64(len(activePeers)) <= connLimit && !p.diversityCapsEnabled() {
 		return []peer.ID{}
 	}
 	p.store.Lock()
 	defer p.store.Unlock()
 
 	type peerResp struct {
-		pid     peer.ID
-		badResp int
+		pid        peer.ID
+		badResp    int
+		beyondCaps bool
+	}
+	beyondCaps := p.peersBeyondDiversityCaps()
+	// Exit early if we are still below our max limit, and
+	// no peer is beyond the diversity caps.
+	aboveLimit := uint64(len(activePeers)) > connLimit
+	if !aboveLimit && len(beyondCaps) == 0 {
+		return []peer.ID{}
 	}
 	peersToPrune := make([]*peerResp, 0)
-	// Select connected and inbound peers to prune.
+	// Select connected and inbound peers, and peers beyond
+	// the diversity caps, to prune.
 	for pid, peerData := range p.store.Peers() {
-		if peerData.ConnState == Connected &&
-			peerData.Direction == network.DirInbound && !p.store.IsTrustedPeer(pid) {
+		if peerData.ConnState == Connected && (beyondCaps[pid] ||
+			peerData.Direction == network.DirInbound && !p.store.IsTrustedPeer(pid)) {
 			peersToPrune = append(peersToPrune, &peerResp{
-				pid:     pid,
-				badResp: peerData.BadResponses,
+				pid:        pid,
+				badResp:    peerData.BadResponses,
+				beyondCaps: beyondCaps[pid],
 			})
 		}
 	}
 
 	// Sort in descending order to favour pruning peers with a
-	// higher bad response count.
+	// higher bad response count, after the peers beyond the
+	// diversity caps.
 	sort.Slice(peersToPrune, func(i, j int) bool {
+		if peersToPrune[i].beyondCaps != peersToPrune[j].beyondCaps {
+			return peersToPrune[i].beyondCaps
+		}
 		return peersToPrune[i].badResp > peersToPrune[j].badResp
 	})
 
 	// Determine amount of peers to prune using our
 	// max connection limit.
-	amountToPrune, err := pmath.Sub64(uint64(len(activePeers)), connLimit)
-	if err != nil {
-		// This should never happen
-		log.WithError(err).Error("Failed to determine amount of peers to prune")
-		return []peer.ID{}
+	amountToPrune := uint64(0)
+	if aboveLimit {
+		amountToPrune = uint64(len(activePeers)) - connLimit
+	}
+	// Peers beyond the diversity caps are pruned regardless.
+	if uint64(len(beyondCaps)) > amountToPrune {
+		amountToPrune = uint64(len(beyondCaps))
 	}
 	// Also check for inbound peers above our limit.
 	excessInbound := uint64(0)
-	if numInboundPeers > inBoundLimit {
+	if aboveLimit && numInboundPeers > inBoundLimit {
 		excessInbound = uint64(numInboundPeers - inBoundLimit)
 	}
 	// Prune the largest amount between excess peers and
@@ -939,10 +1002,24 @@ func (p *Status) deprecatedPeersToPrune() []peer.ID {
 	ids := make([]peer.ID, 0, len(peersToPrune))
 	for _, pr := range peersToPrune {
 		ids = append(ids, pr.pid)
+		if pr.beyondCaps {
+			p.backOffDiversityPruned(pr.pid)
+		}
 	}
 	return ids
 }
 
+// backOffDiversityPruned keeps a peer pruned for the diversity caps from being
+// re-dialed for DiversityBackOffDuration, so that it is not pruned and dialed
+// again in a loop. This method assumes the store lock is acquired before
+// executing the method.
+func (p *Status) backOffDiversityPruned(pid peer.ID) {
+	peerData := p.store.PeerDataGetOrCreate(pid)
+	if next := time.Now().Add(DiversityBackOffDuration); next.After(peerData.NextValidTime) {
+		peerData.NextValidTime = next
+	}
+}
+
 // HighestEpoch returns the highest epoch reported epoch amongst peers.
 func (p *Status) HighestEpoch() primitives.Epoch {
 	p.store.RLock()
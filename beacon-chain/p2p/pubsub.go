@@ -145,7 +145,7 @@ func (s *Service) pubsubOptions() []pubsub.Option {
 		pubsub.WithValidateQueueSize(int(s.cfg.QueueSize)),
 		pubsub.WithPeerScore(peerScoringParams()),
 		pubsub.WithPeerScoreInspect(s.peerInspector, time.Minute),
-		pubsub.WithGossipSubParams(pubsubGossipParam()),
+		pubsub.WithGossipSubParams(s.gossipSubParams()),
 		pubsub.WithRawTracer(gossipTracer{host: s.host}),
 	}
 
@@ -189,6 +189,29 @@ func pubsubGossipParam() pubsub.GossipSubParams {
 	return gParams
 }
 
+// gossipSubParams applies the configured mesh degree and IDONTWANT threshold to our gossipsub parameters. Peers
+// negotiating gossipsub v1.2 are sent IDONTWANT messages for the messages we receive above the threshold, so that
+// they do not forward us duplicates of large blocks and blobs. The gossipsub router keeps a single mesh degree for
+// every topic, with the low and high watermarks scaled along, and topic scoring is derived from it.
+//
+// The mesh degree can't be tuned per topic class (blocks, blobs, attestations): go-libp2p-pubsub only takes the
+// router wide GossipSubParams, and its options require the concrete GossipSubRouter, so a router applying another
+// degree when joining a topic would need a fork of the library. Per topic class degrees are left out until the
+// library supports per topic mesh parameters.
+func (s *Service) gossipSubParams() pubsub.GossipSubParams {
+	gParams := pubsubGossipParam()
+	if s.cfg.MeshDegree != 0 {
+		d := int(s.cfg.MeshDegree)
+		gParams.D = d
+		gParams.Dlo = d * gossipSubDlo / gossipSubD
+		gParams.Dhi = d * gossipSubDhi / gossipSubD
+	}
+	if s.cfg.IDontWantThreshold != 0 {
+		gParams.IDontWantMessageThreshold = int(s.cfg.IDontWantThreshold)
+	}
+	return gParams
+}
+
 // We have to unfortunately set this globally in order
 // to configure our message id time-cache rather than instantiating
 // it with a router instance.
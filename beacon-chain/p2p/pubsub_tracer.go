@@ -58,6 +58,7 @@ func (g gossipTracer) Prune(p peer.ID, topic string) {
 // ValidateMessage .
 func (g gossipTracer) ValidateMessage(msg *pubsub.Message) {
 	pubsubMessageValidate.WithLabelValues(*msg.Topic).Inc()
+	pubsubRecvBytes.WithLabelValues(*msg.Topic).Add(float64(len(msg.Data)))
 }
 
 // DeliverMessage .
@@ -73,6 +74,8 @@ func (g gossipTracer) RejectMessage(msg *pubsub.Message, reason string) {
 // DuplicateMessage .
 func (g gossipTracer) DuplicateMessage(msg *pubsub.Message) {
 	pubsubMessageDuplicate.WithLabelValues(*msg.Topic).Inc()
+	pubsubRecvBytes.WithLabelValues(*msg.Topic).Add(float64(len(msg.Data)))
+	pubsubDuplicateBytes.WithLabelValues(*msg.Topic).Add(float64(len(msg.Data)))
 }
 
 // UndeliverableMessage .
@@ -108,13 +111,22 @@ func (g gossipTracer) setMetricFromRPC(act action, subCtr prometheus.Counter, pu
 		ctrlCtr.WithLabelValues("prune").Add(float64(len(rpc.Control.Prune)))
 		ctrlCtr.WithLabelValues("ihave").Add(float64(len(rpc.Control.Ihave)))
 		ctrlCtr.WithLabelValues("iwant").Add(float64(len(rpc.Control.Iwant)))
+		idontwant := 0
+		for _, ctrl := range rpc.Control.Idontwant {
+			idontwant += len(ctrl.MessageIDs)
+		}
+		ctrlCtr.WithLabelValues("idontwant").Add(float64(idontwant))
 	}
 	for _, msg := range rpc.Publish {
 		// For incoming messages from pubsub, we do not record metrics for them as these values
-		// could be junk.
+		// could be junk. The bytes of the messages on our topics are counted once they are
+		// validated or found to be duplicates.
 		if act == recv {
 			continue
 		}
 		pubCtr.WithLabelValues(*msg.Topic).Inc()
+		if act == send {
+			pubsubSentBytes.WithLabelValues(*msg.Topic).Add(float64(len(msg.Data)))
+		}
 	}
 }
@@ -6,6 +6,7 @@ package p2p
 import (
 	"context"
 	"crypto/ecdsa"
+	"path"
 	"sync"
 	"time"
 
@@ -53,6 +54,9 @@ var refreshRate = slots.DivideSlotBy(2)
 // maxBadResponses is the maximum number of bad responses from a peer before we stop talking to it.
 const maxBadResponses = 5
 
+// peerPersistInterval is how often peer scores and addresses are persisted to disk.
+const peerPersistInterval = 5 * time.Minute
+
 // maxDialTimeout is the timeout for a single peer dial.
 var maxDialTimeout = params.BeaconConfig().RespTimeoutDuration()
 
@@ -159,8 +163,17 @@ func NewService(ctx context.Context, cfg *Config) (*Service, error) {
 
 	s.pubsub = gs
 
+	var peersPath string
+	if s.cfg.DataDir != "" {
+		peersPath = path.Join(s.cfg.DataDir, peerStatusPath)
+	}
 	s.peers = peers.NewStatus(ctx, &peers.StatusConfig{
-		PeerLimit: int(s.cfg.MaxPeers),
+		PeerLimit:   int(s.cfg.MaxPeers),
+		PersistPath: peersPath,
+		Diversity: &peers.DiversityConfig{
+			MaxClientRatio:   s.cfg.MaxClientRatio,
+			MaxIPSubnetPeers: int(s.cfg.MaxIPSubnetPeers),
+		},
 		ScorerParams: &scorers.Config{
 			BadResponsesScorerConfig: &scorers.BadResponsesScorerConfig{
 				Threshold:     maxBadResponses,
@@ -212,6 +225,8 @@ func (s *Service) Start() {
 			s.startupErr = err
 			return
 		}
+		// Dial good peers known from a previous run while discovery is still looking for new ones.
+		s.connectWithPersistedPeers()
 
 		s.dv5Listener = listener
 		go s.listenForNewNodes()
@@ -238,6 +253,7 @@ func (s *Service) Start() {
 		ensurePeerConnections(s.ctx, s.host, s.peers, relayNodes...)
 	})
 	async.RunEvery(s.ctx, 30*time.Minute, s.Peers().Prune)
+	async.RunEvery(s.ctx, peerPersistInterval, s.persistPeers)
 	async.RunEvery(s.ctx, time.Duration(params.BeaconConfig().RespTimeout)*time.Second, s.updateMetrics)
 	async.RunEvery(s.ctx, refreshRate, s.RefreshPersistentSubnets)
 	async.RunEvery(s.ctx, 1*time.Minute, func() {
@@ -287,6 +303,7 @@ func (s *Service) Stop() error {
 	if s.dv5Listener != nil {
 		s.dv5Listener.Close()
 	}
+	s.persistPeers()
 	return nil
 }
 
@@ -465,6 +482,24 @@ func (s *Service) connectWithAllPeers(multiAddrs []multiaddr.Multiaddr) {
 	}
 }
 
+// connectWithPersistedPeers dials the best peers restored from the persisted peer status store.
+func (s *Service) connectWithPersistedPeers() {
+	for _, info := range s.peers.DialCandidates(int(s.cfg.MaxPeers)) {
+		// make each dial non-blocking
+		go func(info peer.AddrInfo) {
+			if err := s.connectWithPeer(s.ctx, info); err != nil {
+				log.WithError(err).Tracef("Could not connect with persisted peer %s", info.String())
+			}
+		}(info)
+	}
+}
+
+func (s *Service) persistPeers() {
+	if err := s.peers.Persist(); err != nil {
+		log.WithError(err).Error("Could not persist peers")
+	}
+}
+
 func (s *Service) connectWithPeer(ctx context.Context, info peer.AddrInfo) error {
 	ctx, span := trace.StartSpan(ctx, "p2p.connectWithPeer")
 	defer span.End()
@@ -12,6 +12,7 @@ import (
 	"github.com/multiformats/go-multiaddr"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/encoder"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/types"
 	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
 	"github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1/metadata"
 	"google.golang.org/protobuf/proto"
@@ -128,6 +129,16 @@ func (*FakeP2P) Disconnect(_ peer.ID) error {
 	return nil
 }
 
+// ConnectToPeer -- fake.
+func (*FakeP2P) ConnectToPeer(_ context.Context, _ peer.AddrInfo) error {
+	return nil
+}
+
+// DisconnectWithGoodbye -- fake.
+func (*FakeP2P) DisconnectWithGoodbye(_ context.Context, _ peer.ID, _ types.RPCGoodbyeCode) error {
+	return nil
+}
+
 // Broadcast -- fake.
 func (*FakeP2P) Broadcast(_ context.Context, _ proto.Message) error {
 	return nil
@@ -8,6 +8,7 @@ import (
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/multiformats/go-multiaddr"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/types"
 )
 
 // MockPeerManager is mock of the PeerManager interface.
@@ -17,6 +18,8 @@ type MockPeerManager struct {
 	BHost             host.Host
 	DiscoveryAddr     []multiaddr.Multiaddr
 	FailDiscoveryAddr bool
+	ConnectedPeers    []peer.AddrInfo
+	Goodbyes          map[peer.ID]types.RPCGoodbyeCode
 }
 
 // Disconnect .
@@ -57,3 +60,18 @@ func (*MockPeerManager) FindPeersWithSubnet(_ context.Context, _ string, _ uint6
 
 // AddPingMethod .
 func (*MockPeerManager) AddPingMethod(_ func(ctx context.Context, id peer.ID) error) {}
+
+// ConnectToPeer .
+func (m *MockPeerManager) ConnectToPeer(_ context.Context, info peer.AddrInfo) error {
+	m.ConnectedPeers = append(m.ConnectedPeers, info)
+	return nil
+}
+
+// DisconnectWithGoodbye .
+func (m *MockPeerManager) DisconnectWithGoodbye(_ context.Context, pid peer.ID, code types.RPCGoodbyeCode) error {
+	if m.Goodbyes == nil {
+		m.Goodbyes = make(map[peer.ID]types.RPCGoodbyeCode)
+	}
+	m.Goodbyes[pid] = code
+	return nil
+}
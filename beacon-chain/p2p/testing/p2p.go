@@ -27,6 +27,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/encoder"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers/scorers"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/types"
 	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
 	"github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1/metadata"
 	"github.com/prysmaticlabs/prysm/v5/testing/require"
@@ -268,6 +269,16 @@ func (p *TestP2P) Disconnect(pid peer.ID) error {
 	return p.BHost.Network().ClosePeer(pid)
 }
 
+// ConnectToPeer dials a peer.
+func (p *TestP2P) ConnectToPeer(ctx context.Context, info peer.AddrInfo) error {
+	return p.BHost.Connect(ctx, info)
+}
+
+// DisconnectWithGoodbye disconnects from a peer, without sending a goodbye message.
+func (p *TestP2P) DisconnectWithGoodbye(_ context.Context, pid peer.ID, _ types.RPCGoodbyeCode) error {
+	return p.Disconnect(pid)
+}
+
 // PeerID returns the Peer ID of the local peer.
 func (p *TestP2P) PeerID() peer.ID {
 	return p.BHost.ID()
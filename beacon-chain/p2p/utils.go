@@ -32,6 +32,7 @@ import (
 
 const keyPath = "network-keys"
 const metaDataPath = "metaData"
+const peerStatusPath = "peers.json"
 
 const dialTimeout = 1 * time.Second
 
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/cache"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/altair"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/epoch/precompute"
@@ -538,6 +539,9 @@ func (s *Service) GetAttestationData(
 		return nil, &RpcError{Reason: Internal, Err: err}
 	}
 	if optimistic {
+		if s.OptimisticReporter != nil {
+			s.OptimisticReporter.RecordBlockedDuty(blockchain.DutyAttestationData)
+		}
 		return nil, &RpcError{Reason: Unavailable, Err: errOptimisticMode}
 	}
 
@@ -20,6 +20,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/eth/validator"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/lookup"
 	beaconprysm "github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/prysm/beacon"
+	debugprysm "github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/prysm/debug"
 	nodeprysm "github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/prysm/node"
 	validatorv1alpha1 "github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/prysm/v1alpha1/validator"
 	validatorprysm "github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/prysm/validator"
@@ -32,6 +33,12 @@ type endpoint struct {
 	middleware []middleware.Middleware
 	handler    http.HandlerFunc
 	methods    []string
+	// scope is the scope a client needs to call the endpoint when API authentication is enabled. Endpoints without
+	// a scope get the scope of their route group.
+	scope middleware.Scope
+	// cache identifies the finalized state the response of the endpoint is computed from, for endpoints whose
+	// responses are cached when the response cache is enabled.
+	cache cacheBy
 }
 
 // responseWriter is the wrapper to http Response writer.
@@ -88,20 +95,47 @@ func (s *Service) endpoints(
 	ch *stategen.CanonicalHistory,
 ) []endpoint {
 	endpoints := make([]endpoint, 0)
-	endpoints = append(endpoints, s.rewardsEndpoints(blocker, stater, rewardFetcher)...)
-	endpoints = append(endpoints, s.builderEndpoints(stater)...)
-	endpoints = append(endpoints, s.blobEndpoints(blocker)...)
-	endpoints = append(endpoints, s.validatorEndpoints(validatorServer, stater, coreService, rewardFetcher)...)
-	endpoints = append(endpoints, s.nodeEndpoints()...)
-	endpoints = append(endpoints, s.beaconEndpoints(ch, stater, blocker, validatorServer, coreService)...)
-	endpoints = append(endpoints, s.configEndpoints()...)
-	endpoints = append(endpoints, s.lightClientEndpoints(blocker, stater)...)
-	endpoints = append(endpoints, s.eventsEndpoints()...)
-	endpoints = append(endpoints, s.prysmBeaconEndpoints(ch, stater, coreService)...)
-	endpoints = append(endpoints, s.prysmNodeEndpoints()...)
-	endpoints = append(endpoints, s.prysmValidatorEndpoints(stater, coreService)...)
+	endpoints = append(endpoints, withScope(middleware.ScopeReadOnly, s.rewardsEndpoints(blocker, stater, rewardFetcher))...)
+	endpoints = append(endpoints, withScope(middleware.ScopeReadOnly, s.builderEndpoints(stater))...)
+	endpoints = append(endpoints, withScope(middleware.ScopeReadOnly, s.blobEndpoints(blocker))...)
+	endpoints = append(endpoints, withScope(middleware.ScopeValidator, s.validatorEndpoints(validatorServer, stater, coreService, rewardFetcher))...)
+	endpoints = append(endpoints, withScope(middleware.ScopeReadOnly, s.nodeEndpoints())...)
+	endpoints = append(endpoints, withScope(middleware.ScopeReadOnly, s.beaconEndpoints(ch, stater, blocker, validatorServer, coreService))...)
+	endpoints = append(endpoints, withScope(middleware.ScopeReadOnly, s.configEndpoints())...)
+	endpoints = append(endpoints, withScope(middleware.ScopeReadOnly, s.lightClientEndpoints(blocker, stater))...)
+	endpoints = append(endpoints, withScope(middleware.ScopeReadOnly, s.eventsEndpoints())...)
+	endpoints = append(endpoints, withScope(middleware.ScopeReadOnly, s.prysmBeaconEndpoints(ch, stater, blocker, coreService))...)
+	endpoints = append(endpoints, withScope(middleware.ScopeReadOnly, s.prysmNodeEndpoints())...)
+	endpoints = append(endpoints, withScope(middleware.ScopeReadOnly, s.prysmValidatorEndpoints(stater, coreService))...)
 	if enableDebug {
-		endpoints = append(endpoints, s.debugEndpoints(stater)...)
+		endpoints = append(endpoints, withScope(middleware.ScopeDebug, s.debugEndpoints(stater))...)
+		endpoints = append(endpoints, withScope(middleware.ScopeDebug, s.prysmDebugEndpoints())...)
+	}
+	if s.responseCache != nil {
+		for i := range endpoints {
+			if endpoints[i].cache != noCache {
+				// The first middleware is wrapped by the others, so cached responses are only served to requests
+				// that pass the Accept and Content-Type checks.
+				cache := s.responseCache.handler(endpoints[i].template, endpoints[i].cache)
+				endpoints[i].middleware = append([]middleware.Middleware{cache}, endpoints[i].middleware...)
+			}
+		}
+	}
+	if s.cfg.Authenticator != nil {
+		for i := range endpoints {
+			// The last middleware wraps the others, so requests are authenticated first.
+			endpoints[i].middleware = append(endpoints[i].middleware, s.cfg.Authenticator.Handler(endpoints[i].scope))
+		}
+	}
+	return endpoints
+}
+
+// withScope sets the scope of the endpoints that do not have one.
+func withScope(scope middleware.Scope, endpoints []endpoint) []endpoint {
+	for i := range endpoints {
+		if endpoints[i].scope == "" {
+			endpoints[i].scope = scope
+		}
 	}
 	return endpoints
 }
@@ -127,6 +161,7 @@ func (s *Service) rewardsEndpoints(blocker lookup.Blocker, stater lookup.Stater,
 			},
 			handler: server.BlockRewards,
 			methods: []string{http.MethodGet},
+			cache:   cacheByBlockId,
 		},
 		{
 			template: "/eth/v1/beacon/rewards/attestations/{epoch}",
@@ -137,6 +172,7 @@ func (s *Service) rewardsEndpoints(blocker lookup.Blocker, stater lookup.Stater,
 			},
 			handler: server.AttestationRewards,
 			methods: []string{http.MethodPost},
+			cache:   cacheByEpoch,
 		},
 		{
 			template: "/eth/v1/beacon/rewards/sync_committee/{block_id}",
@@ -147,6 +183,7 @@ func (s *Service) rewardsEndpoints(blocker lookup.Blocker, stater lookup.Stater,
 			},
 			handler: server.SyncCommitteeRewards,
 			methods: []string{http.MethodPost},
+			cache:   cacheByBlockId,
 		},
 	}
 }
@@ -544,6 +581,7 @@ func (s *Service) beaconEndpoints(
 			},
 			handler: server.GetCommittees,
 			methods: []string{http.MethodGet},
+			cache:   cacheByStateId,
 		},
 		{
 			template: "/eth/v1/beacon/states/{state_id}/fork",
@@ -571,6 +609,7 @@ func (s *Service) beaconEndpoints(
 			},
 			handler: server.GetSyncCommittees,
 			methods: []string{http.MethodGet},
+			cache:   cacheByStateId,
 		},
 		{
 			template: "/eth/v1/beacon/states/{state_id}/randao",
@@ -590,6 +629,7 @@ func (s *Service) beaconEndpoints(
 			},
 			handler: server.PublishBlock,
 			methods: []string{http.MethodPost},
+			scope:   middleware.ScopeValidator,
 		},
 		{
 			template: "/eth/v1/beacon/blinded_blocks",
@@ -600,6 +640,7 @@ func (s *Service) beaconEndpoints(
 			},
 			handler: server.PublishBlindedBlock,
 			methods: []string{http.MethodPost},
+			scope:   middleware.ScopeValidator,
 		},
 		{
 			template: "/eth/v2/beacon/blocks",
@@ -610,6 +651,7 @@ func (s *Service) beaconEndpoints(
 			},
 			handler: server.PublishBlockV2,
 			methods: []string{http.MethodPost},
+			scope:   middleware.ScopeValidator,
 		},
 		{
 			template: "/eth/v2/beacon/blinded_blocks",
@@ -620,6 +662,7 @@ func (s *Service) beaconEndpoints(
 			},
 			handler: server.PublishBlindedBlockV2,
 			methods: []string{http.MethodPost},
+			scope:   middleware.ScopeValidator,
 		},
 		{
 			template: "/eth/v2/beacon/blocks/{block_id}",
@@ -693,6 +736,7 @@ func (s *Service) beaconEndpoints(
 			},
 			handler: server.SubmitAttestations,
 			methods: []string{http.MethodPost},
+			scope:   middleware.ScopeValidator,
 		},
 		{
 			template: "/eth/v2/beacon/pool/attestations",
@@ -703,6 +747,7 @@ func (s *Service) beaconEndpoints(
 			},
 			handler: server.SubmitAttestationsV2,
 			methods: []string{http.MethodPost},
+			scope:   middleware.ScopeValidator,
 		},
 		{
 			template: "/eth/v1/beacon/pool/voluntary_exits",
@@ -722,6 +767,7 @@ func (s *Service) beaconEndpoints(
 			},
 			handler: server.SubmitVoluntaryExit,
 			methods: []string{http.MethodPost},
+			scope:   middleware.ScopeValidator,
 		},
 		{
 			template: "/eth/v1/beacon/pool/sync_committees",
@@ -732,6 +778,7 @@ func (s *Service) beaconEndpoints(
 			},
 			handler: server.SubmitSyncCommitteeSignatures,
 			methods: []string{http.MethodPost},
+			scope:   middleware.ScopeValidator,
 		},
 		{
 			template: "/eth/v1/beacon/pool/bls_to_execution_changes",
@@ -751,6 +798,7 @@ func (s *Service) beaconEndpoints(
 			},
 			handler: server.SubmitBLSToExecutionChanges,
 			methods: []string{http.MethodPost},
+			scope:   middleware.ScopeValidator,
 		},
 		{
 			template: "/eth/v1/beacon/pool/attester_slashings",
@@ -779,6 +827,7 @@ func (s *Service) beaconEndpoints(
 			},
 			handler: server.SubmitAttesterSlashings,
 			methods: []string{http.MethodPost},
+			scope:   middleware.ScopeValidator,
 		},
 		{
 			template: "/eth/v2/beacon/pool/attester_slashings",
@@ -789,6 +838,7 @@ func (s *Service) beaconEndpoints(
 			},
 			handler: server.SubmitAttesterSlashingsV2,
 			methods: []string{http.MethodPost},
+			scope:   middleware.ScopeValidator,
 		},
 		{
 			template: "/eth/v1/beacon/pool/proposer_slashings",
@@ -808,6 +858,7 @@ func (s *Service) beaconEndpoints(
 			},
 			handler: server.SubmitProposerSlashing,
 			methods: []string{http.MethodPost},
+			scope:   middleware.ScopeValidator,
 		},
 		{
 			template: "/eth/v1/beacon/headers",
@@ -854,6 +905,7 @@ func (s *Service) beaconEndpoints(
 			},
 			handler: server.GetValidators,
 			methods: []string{http.MethodGet, http.MethodPost},
+			cache:   cacheByStateId,
 		},
 		{
 			template: "/eth/v1/beacon/states/{state_id}/validators/{validator_id}",
@@ -863,6 +915,7 @@ func (s *Service) beaconEndpoints(
 			},
 			handler: server.GetValidator,
 			methods: []string{http.MethodGet},
+			cache:   cacheByStateId,
 		},
 		{
 			template: "/eth/v1/beacon/states/{state_id}/validator_balances",
@@ -873,6 +926,7 @@ func (s *Service) beaconEndpoints(
 			},
 			handler: server.GetValidatorBalances,
 			methods: []string{http.MethodGet, http.MethodPost},
+			cache:   cacheByStateId,
 		},
 		{
 			template: "/eth/v1/beacon/deposit_snapshot",
@@ -991,6 +1045,7 @@ func (s *Service) debugEndpoints(stater lookup.Stater) []endpoint {
 			},
 			handler: server.GetBeaconStateV2,
 			methods: []string{http.MethodGet},
+			cache:   cacheByStateId,
 		},
 		{
 			template: "/eth/v2/debug/beacon/heads",
@@ -1040,6 +1095,7 @@ func (s *Service) eventsEndpoints() []endpoint {
 func (s *Service) prysmBeaconEndpoints(
 	ch *stategen.CanonicalHistory,
 	stater lookup.Stater,
+	blocker lookup.Blocker,
 	coreService *core.Service,
 ) []endpoint {
 	server := &beaconprysm.Server{
@@ -1050,11 +1106,14 @@ func (s *Service) prysmBeaconEndpoints(
 		CanonicalHistory:      ch,
 		BeaconDB:              s.cfg.BeaconDB,
 		Stater:                stater,
+		Blocker:               blocker,
 		ChainInfoFetcher:      s.cfg.ChainInfoFetcher,
 		FinalizationFetcher:   s.cfg.FinalizationFetcher,
 		CoreService:           coreService,
 		Broadcaster:           s.cfg.Broadcaster,
 		BlobReceiver:          s.cfg.BlobReceiver,
+		CheckpointCache:       s.cfg.CheckpointCache,
+		BlobStorage:           s.cfg.BlobStorage,
 	}
 
 	const namespace = "prysm.beacon"
@@ -1086,6 +1145,15 @@ func (s *Service) prysmBeaconEndpoints(
 			handler: server.GetValidatorCount,
 			methods: []string{http.MethodGet},
 		},
+		{
+			template: "/prysm/v1/beacon/states/{state_id}/validators",
+			name:     namespace + ".StreamValidators",
+			middleware: []middleware.Middleware{
+				middleware.AcceptHeaderHandler([]string{api.NdjsonMediaType, api.JsonMediaType, api.OctetStreamMediaType}),
+			},
+			handler: server.StreamValidators,
+			methods: []string{http.MethodGet},
+		},
 		{
 			template: "/prysm/v1/beacon/individual_votes",
 			name:     namespace + ".GetIndividualVotes",
@@ -1115,6 +1183,51 @@ func (s *Service) prysmBeaconEndpoints(
 			handler: server.PublishBlobs,
 			methods: []string{http.MethodPost},
 		},
+		{
+			template: "/prysm/v1/beacon/states/{state_id}/proof",
+			name:     namespace + ".GetStateProof",
+			middleware: []middleware.Middleware{
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType, api.OctetStreamMediaType}),
+			},
+			handler: server.GetStateProof,
+			methods: []string{http.MethodGet},
+		},
+		{
+			template: "/prysm/v1/beacon/blocks/{block_id}/proof",
+			name:     namespace + ".GetBlockProof",
+			middleware: []middleware.Middleware{
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType, api.OctetStreamMediaType}),
+			},
+			handler: server.GetBlockProof,
+			methods: []string{http.MethodGet},
+		},
+		{
+			template: "/prysm/v1/beacon/checkpoint/state",
+			name:     namespace + ".GetCheckpointState",
+			middleware: []middleware.Middleware{
+				middleware.AcceptHeaderHandler([]string{api.OctetStreamMediaType}),
+			},
+			handler: server.GetCheckpointState,
+			methods: []string{http.MethodGet},
+		},
+		{
+			template: "/prysm/v1/beacon/checkpoint/block",
+			name:     namespace + ".GetCheckpointBlock",
+			middleware: []middleware.Middleware{
+				middleware.AcceptHeaderHandler([]string{api.OctetStreamMediaType}),
+			},
+			handler: server.GetCheckpointBlock,
+			methods: []string{http.MethodGet},
+		},
+		{
+			template: "/prysm/v1/beacon/blobs/coverage",
+			name:     namespace + ".GetBlobArchiveCoverage",
+			middleware: []middleware.Middleware{
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.GetBlobArchiveCoverage,
+			methods: []string{http.MethodGet},
+		},
 	}
 }
 
@@ -1160,6 +1273,7 @@ func (s *Service) prysmNodeEndpoints() []endpoint {
 			},
 			handler: server.AddTrustedPeer,
 			methods: []string{http.MethodPost},
+			scope:   middleware.ScopeAdmin,
 		},
 		{
 			template: "/prysm/v1/node/trusted_peers",
@@ -1170,6 +1284,7 @@ func (s *Service) prysmNodeEndpoints() []endpoint {
 			},
 			handler: server.AddTrustedPeer,
 			methods: []string{http.MethodPost},
+			scope:   middleware.ScopeAdmin,
 		},
 		{
 			template: "/prysm/node/trusted_peers/{peer_id}",
@@ -1179,6 +1294,7 @@ func (s *Service) prysmNodeEndpoints() []endpoint {
 			},
 			handler: server.RemoveTrustedPeer,
 			methods: []string{http.MethodDelete},
+			scope:   middleware.ScopeAdmin,
 		},
 		{
 			template: "/prysm/v1/node/trusted_peers/{peer_id}",
@@ -1188,15 +1304,91 @@ func (s *Service) prysmNodeEndpoints() []endpoint {
 			},
 			handler: server.RemoveTrustedPeer,
 			methods: []string{http.MethodDelete},
+			scope:   middleware.ScopeAdmin,
+		},
+		{
+			template: "/prysm/v1/node/peers/bans",
+			name:     namespace + ".ListPeerBans",
+			middleware: []middleware.Middleware{
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.ListPeerBans,
+			methods: []string{http.MethodGet},
+		},
+		{
+			template: "/prysm/v1/node/peers/bans",
+			name:     namespace + ".BanPeer",
+			middleware: []middleware.Middleware{
+				middleware.ContentTypeHandler([]string{api.JsonMediaType}),
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.BanPeer,
+			methods: []string{http.MethodPost},
+			scope:   middleware.ScopeAdmin,
+		},
+		{
+			template: "/prysm/v1/node/peers/bans/{target...}",
+			name:     namespace + ".UnbanPeer",
+			middleware: []middleware.Middleware{
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.UnbanPeer,
+			methods: []string{http.MethodDelete},
+			scope:   middleware.ScopeAdmin,
+		},
+		{
+			template: "/prysm/v1/node/peers/connect",
+			name:     namespace + ".ConnectPeer",
+			middleware: []middleware.Middleware{
+				middleware.ContentTypeHandler([]string{api.JsonMediaType}),
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.ConnectPeer,
+			methods: []string{http.MethodPost},
+			scope:   middleware.ScopeAdmin,
+		},
+		{
+			template: "/prysm/v1/node/peers/{peer_id}/disconnect",
+			name:     namespace + ".DisconnectPeer",
+			middleware: []middleware.Middleware{
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.DisconnectPeer,
+			methods: []string{http.MethodPost},
+			scope:   middleware.ScopeAdmin,
+		},
+		{
+			template: "/prysm/v1/node/peers/{peer_id}/score",
+			name:     namespace + ".GetPeerScore",
+			middleware: []middleware.Middleware{
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.GetPeerScore,
+			methods: []string{http.MethodGet},
+		},
+		{
+			template: "/prysm/v1/node/peers/{peer_id}/score",
+			name:     namespace + ".SetPeerScore",
+			middleware: []middleware.Middleware{
+				middleware.ContentTypeHandler([]string{api.JsonMediaType}),
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.SetPeerScore,
+			methods: []string{http.MethodPost},
+			scope:   middleware.ScopeAdmin,
 		},
 	}
 }
 
 func (s *Service) prysmValidatorEndpoints(stater lookup.Stater, coreService *core.Service) []endpoint {
 	server := &validatorprysm.Server{
-		ChainInfoFetcher: s.cfg.ChainInfoFetcher,
-		Stater:           stater,
-		CoreService:      coreService,
+		BeaconDB:              s.cfg.BeaconDB,
+		ChainInfoFetcher:      s.cfg.ChainInfoFetcher,
+		HeadFetcher:           s.cfg.HeadFetcher,
+		TimeFetcher:           s.cfg.GenesisTimeFetcher,
+		OptimisticModeFetcher: s.cfg.OptimisticModeFetcher,
+		Stater:                stater,
+		CoreService:           coreService,
 	}
 
 	const namespace = "prysm.validator"
@@ -1239,5 +1431,56 @@ func (s *Service) prysmValidatorEndpoints(stater lookup.Stater, coreService *cor
 			handler: server.GetActiveSetChanges,
 			methods: []string{http.MethodGet},
 		},
+		{
+			template: "/prysm/v1/validators/proposer_lookahead",
+			name:     namespace + ".GetProposerLookahead",
+			middleware: []middleware.Middleware{
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.GetProposerLookahead,
+			methods: []string{http.MethodGet},
+		},
+		{
+			template: "/prysm/v1/validators/lookup",
+			name:     namespace + ".LookupValidators",
+			middleware: []middleware.Middleware{
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.LookupValidators,
+			methods: []string{http.MethodGet},
+		},
+	}
+}
+
+func (s *Service) prysmDebugEndpoints() []endpoint {
+	server := &debugprysm.Server{
+		ForkchoiceFetcher:     s.cfg.ForkchoiceFetcher,
+		OptimisticModeFetcher: s.cfg.OptimisticModeFetcher,
+		HeadFetcher:           s.cfg.HeadFetcher,
+		OptimisticReporter:    s.cfg.OptimisticReporter,
+	}
+
+	const namespace = "prysm.debug"
+	return []endpoint{
+		{
+			template: "/prysm/v1/debug/optimistic",
+			name:     namespace + ".GetOptimisticReport",
+			middleware: []middleware.Middleware{
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.GetOptimisticReport,
+			methods: []string{http.MethodGet},
+		},
+		{
+			template: "/prysm/v1/debug/optimistic/revalidate",
+			name:     namespace + ".RevalidateOptimisticPayloads",
+			middleware: []middleware.Middleware{
+				middleware.ContentTypeHandler([]string{api.JsonMediaType}),
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.RevalidateOptimisticPayloads,
+			methods: []string{http.MethodPost},
+			scope:   middleware.ScopeAdmin,
+		},
 	}
 }
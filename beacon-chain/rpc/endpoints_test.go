@@ -2,10 +2,13 @@ package rpc
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"slices"
 	"testing"
 
+	"github.com/prysmaticlabs/prysm/v5/api/server/middleware"
 	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
 	"golang.org/x/exp/maps"
 )
 
@@ -76,6 +79,8 @@ func Test_endpoints(t *testing.T) {
 		"/eth/v2/debug/beacon/states/{state_id}": {http.MethodGet},
 		"/eth/v2/debug/beacon/heads":             {http.MethodGet},
 		"/eth/v1/debug/fork_choice":              {http.MethodGet},
+		"/prysm/v1/debug/optimistic":             {http.MethodGet},
+		"/prysm/v1/debug/optimistic/revalidate":  {http.MethodPost},
 	}
 
 	eventsRoutes := map[string][]string{
@@ -119,15 +124,26 @@ func Test_endpoints(t *testing.T) {
 		"/prysm/v1/beacon/weak_subjectivity":                 {http.MethodGet},
 		"/eth/v1/beacon/states/{state_id}/validator_count":   {http.MethodGet},
 		"/prysm/v1/beacon/states/{state_id}/validator_count": {http.MethodGet},
+		"/prysm/v1/beacon/states/{state_id}/validators":      {http.MethodGet},
 		"/prysm/v1/beacon/chain_head":                        {http.MethodGet},
 		"/prysm/v1/beacon/blobs":                             {http.MethodPost},
+		"/prysm/v1/beacon/states/{state_id}/proof":           {http.MethodGet},
+		"/prysm/v1/beacon/blocks/{block_id}/proof":           {http.MethodGet},
+		"/prysm/v1/beacon/checkpoint/state":                  {http.MethodGet},
+		"/prysm/v1/beacon/checkpoint/block":                  {http.MethodGet},
+		"/prysm/v1/beacon/blobs/coverage":                    {http.MethodGet},
 	}
 
 	prysmNodeRoutes := map[string][]string{
-		"/prysm/node/trusted_peers":              {http.MethodGet, http.MethodPost},
-		"/prysm/v1/node/trusted_peers":           {http.MethodGet, http.MethodPost},
-		"/prysm/node/trusted_peers/{peer_id}":    {http.MethodDelete},
-		"/prysm/v1/node/trusted_peers/{peer_id}": {http.MethodDelete},
+		"/prysm/node/trusted_peers":                 {http.MethodGet, http.MethodPost},
+		"/prysm/v1/node/trusted_peers":              {http.MethodGet, http.MethodPost},
+		"/prysm/node/trusted_peers/{peer_id}":       {http.MethodDelete},
+		"/prysm/v1/node/trusted_peers/{peer_id}":    {http.MethodDelete},
+		"/prysm/v1/node/peers/bans":                 {http.MethodGet, http.MethodPost},
+		"/prysm/v1/node/peers/bans/{target...}":     {http.MethodDelete},
+		"/prysm/v1/node/peers/connect":              {http.MethodPost},
+		"/prysm/v1/node/peers/{peer_id}/disconnect": {http.MethodPost},
+		"/prysm/v1/node/peers/{peer_id}/score":      {http.MethodGet, http.MethodPost},
 	}
 
 	prysmValidatorRoutes := map[string][]string{
@@ -135,6 +151,8 @@ func Test_endpoints(t *testing.T) {
 		"/prysm/v1/validators/performance":        {http.MethodPost},
 		"/prysm/v1/validators/participation":      {http.MethodGet},
 		"/prysm/v1/validators/active_set_changes": {http.MethodGet},
+		"/prysm/v1/validators/proposer_lookahead": {http.MethodGet},
+		"/prysm/v1/validators/lookup":             {http.MethodGet},
 	}
 
 	s := &Service{cfg: &Config{}}
@@ -154,3 +172,40 @@ func Test_endpoints(t *testing.T) {
 		return slices.Equal(expectedMethods, actualMethods)
 	}))
 }
+
+func Test_endpoints_scopes(t *testing.T) {
+	s := &Service{cfg: &Config{}}
+	scopes := make(map[string]middleware.Scope)
+	for _, e := range s.endpoints(true, nil, nil, nil, nil, nil, nil) {
+		require.NotEqual(t, middleware.Scope(""), e.scope, "endpoint %s has no scope", e.name)
+		for _, m := range e.methods {
+			scopes[m+" "+e.template] = e.scope
+		}
+	}
+
+	assert.Equal(t, middleware.ScopeReadOnly, scopes["GET /eth/v1/beacon/genesis"])
+	assert.Equal(t, middleware.ScopeReadOnly, scopes["GET /eth/v1/beacon/pool/attestations"])
+	assert.Equal(t, middleware.ScopeValidator, scopes["POST /eth/v1/beacon/pool/attestations"])
+	assert.Equal(t, middleware.ScopeValidator, scopes["POST /eth/v2/beacon/blocks"])
+	assert.Equal(t, middleware.ScopeValidator, scopes["GET /eth/v1/validator/attestation_data"])
+	assert.Equal(t, middleware.ScopeDebug, scopes["GET /eth/v2/debug/beacon/states/{state_id}"])
+	assert.Equal(t, middleware.ScopeReadOnly, scopes["GET /prysm/v1/node/trusted_peers"])
+	assert.Equal(t, middleware.ScopeAdmin, scopes["POST /prysm/v1/node/trusted_peers"])
+	assert.Equal(t, middleware.ScopeAdmin, scopes["DELETE /prysm/v1/node/trusted_peers/{peer_id}"])
+	assert.Equal(t, middleware.ScopeDebug, scopes["GET /prysm/v1/debug/optimistic"])
+	assert.Equal(t, middleware.ScopeAdmin, scopes["POST /prysm/v1/debug/optimistic/revalidate"])
+}
+
+func Test_endpoints_authenticated(t *testing.T) {
+	a, err := middleware.NewAuthenticator(&middleware.AuthConfig{Clients: []*middleware.AuthClient{
+		{Name: "reader", Token: "reader-token", Scopes: []middleware.Scope{middleware.ScopeReadOnly}},
+	}})
+	require.NoError(t, err)
+	s := &Service{cfg: &Config{Authenticator: a}}
+	for _, e := range s.endpoints(true, nil, nil, nil, nil, nil, nil) {
+		req := httptest.NewRequest(e.methods[0], e.template, http.NoBody)
+		rr := httptest.NewRecorder()
+		e.handlerWithMiddleware()(rr, req)
+		require.Equal(t, http.StatusUnauthorized, rr.Code, "endpoint %s is not authenticated", e.name)
+	}
+}
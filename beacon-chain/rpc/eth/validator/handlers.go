@@ -785,10 +785,19 @@ func (s *Server) PrepareBeaconProposer(w http.ResponseWriter, r *http.Request) {
 				log.WithField("validatorIndex", validatorIndex).Warn("fee recipient is the burn address")
 			}
 		}
+		var minBid uint64
+		if r.MinBid != "" {
+			minBid, valid = shared.ValidateUint(w, "min_bid", r.MinBid)
+			if !valid {
+				return
+			}
+		}
 		val := cache.TrackedValidator{
 			Active:       true, // TODO: either check or add the field in the request
 			Index:        primitives.ValidatorIndex(validatorIndex),
 			FeeRecipient: feeRecipient,
+			Relays:       builder.AllowedRelays(s.BlockBuilder, primitives.ValidatorIndex(validatorIndex), r.Relays),
+			MinBid:       primitives.Gwei(minBid),
 		}
 		s.TrackedValidatorsCache.Set(val)
 		validatorIndices = append(validatorIndices, primitives.ValidatorIndex(validatorIndex))
@@ -2576,6 +2576,8 @@ func TestPrepareBeaconProposer(t *testing.T) {
 		request []*structs.FeeRecipient
 		code    int
 		wantErr string
+		// Relays expected to be tracked for the validator, if they differ from the requested ones.
+		wantRelays []string
 	}{
 		{
 			name: "Happy Path",
@@ -2587,6 +2589,18 @@ func TestPrepareBeaconProposer(t *testing.T) {
 			code:    http.StatusOK,
 			wantErr: "",
 		},
+		{
+			name: "Relays and min bid",
+			request: []*structs.FeeRecipient{{
+				FeeRecipient:   "0xb698D697092822185bF0311052215d5B5e1F3934",
+				ValidatorIndex: "1",
+				Relays:         []string{"https://relay.example.com"},
+				MinBid:         "100",
+			},
+			},
+			code:    http.StatusOK,
+			wantErr: "",
+		},
 		{
 			name: "invalid fee recipient length",
 			request: []*structs.FeeRecipient{{
@@ -2597,6 +2611,28 @@ func TestPrepareBeaconProposer(t *testing.T) {
 			code:    http.StatusBadRequest,
 			wantErr: "Invalid fee_recipient",
 		},
+		{
+			name: "relay not allowed is dropped",
+			request: []*structs.FeeRecipient{{
+				FeeRecipient:   "0xb698D697092822185bF0311052215d5B5e1F3934",
+				ValidatorIndex: "1",
+				Relays:         []string{"http://169.254.169.254", "https://relay.example.com"},
+			},
+			},
+			code:       http.StatusOK,
+			wantRelays: []string{"https://relay.example.com"},
+		},
+		{
+			name: "invalid min bid",
+			request: []*structs.FeeRecipient{{
+				FeeRecipient:   "0xb698D697092822185bF0311052215d5B5e1F3934",
+				ValidatorIndex: "1",
+				MinBid:         "foo",
+			},
+			},
+			code:    http.StatusBadRequest,
+			wantErr: "min_bid is invalid",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -2613,6 +2649,7 @@ func TestPrepareBeaconProposer(t *testing.T) {
 				BeaconDB:               db,
 				TrackedValidatorsCache: cache.NewTrackedValidatorsCache(),
 				PayloadIDCache:         cache.NewPayloadIDCache(),
+				BlockBuilder:           &builderTest.MockBuilderService{AllowedRelays: []string{"https://relay.example.com"}},
 			}
 			server.PrepareBeaconProposer(writer, request)
 			require.Equal(t, tt.code, writer.Code)
@@ -2627,6 +2664,14 @@ func TestPrepareBeaconProposer(t *testing.T) {
 				val, tracked := server.TrackedValidatorsCache.Validator(primitives.ValidatorIndex(index))
 				require.Equal(t, true, tracked)
 				require.Equal(t, primitives.ExecutionAddress(feebytes), val.FeeRecipient)
+				wantRelays := tt.request[0].Relays
+				if tt.wantRelays != nil {
+					wantRelays = tt.wantRelays
+				}
+				require.DeepEqual(t, wantRelays, val.Relays)
+				if tt.request[0].MinBid != "" {
+					require.Equal(t, tt.request[0].MinBid, fmt.Sprintf("%d", val.MinBid))
+				}
 			}
 		})
 	}
@@ -28,4 +28,11 @@ var (
 		},
 		[]string{"endpoint", "code", "method"},
 	)
+	responseCacheRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "http_response_cache_requests_total",
+			Help: "Number of HTTP requests to cached endpoints by result: hit, miss, shared with a concurrent identical request, or bypass for non-finalized states",
+		},
+		[]string{"endpoint", "result"},
+	)
 )
@@ -0,0 +1,65 @@
+package beacon
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/eth/shared"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
+)
+
+// GetBlobArchiveCoverage is a HTTP handler that serves the GET /prysm/v1/beacon/blobs/coverage endpoint.
+// It returns the range of slots checked by the blob archive, and the blocks in the requested range of slots
+// whose blob sidecars could not be found. Both query parameters are optional and default to the checked range.
+func (s *Server) GetBlobArchiveCoverage(w http.ResponseWriter, r *http.Request) {
+	_, span := trace.StartSpan(r.Context(), "beacon.GetBlobArchiveCoverage")
+	defer span.End()
+
+	if s.BlobStorage == nil || s.BlobStorage.Coverage() == nil {
+		httputil.HandleError(w, "Blob archive is not enabled", http.StatusNotFound)
+		return
+	}
+	coverage := s.BlobStorage.Coverage()
+	low, high := coverage.Checked()
+	rawStart, start, ok := shared.UintFromQuery(w, r, "start_slot", false)
+	if !ok {
+		return
+	}
+	if rawStart == "" {
+		start = uint64(low)
+	}
+	rawEnd, end, ok := shared.UintFromQuery(w, r, "end_slot", false)
+	if !ok {
+		return
+	}
+	if rawEnd == "" {
+		end = uint64(high)
+	}
+	if start > end {
+		httputil.HandleError(w, "start_slot must not be greater than end_slot", http.StatusBadRequest)
+		return
+	}
+
+	gaps := coverage.Gaps(primitives.Slot(start), primitives.Slot(end))
+	resp := &structs.BlobArchiveCoverage{
+		CheckedStartSlot: strconv.FormatUint(uint64(low), 10),
+		CheckedEndSlot:   strconv.FormatUint(uint64(high), 10),
+		Gaps:             make([]*structs.BlobArchiveGap, len(gaps)),
+	}
+	for i, g := range gaps {
+		missing := make([]string, len(g.Missing))
+		for j := range g.Missing {
+			missing[j] = strconv.FormatUint(g.Missing[j], 10)
+		}
+		resp.Gaps[i] = &structs.BlobArchiveGap{
+			Slot:           strconv.FormatUint(uint64(g.Slot), 10),
+			BlockRoot:      fmt.Sprintf("%#x", g.Root),
+			MissingIndices: missing,
+		}
+	}
+	httputil.WriteJson(w, &structs.BlobArchiveCoverageResponse{Data: resp})
+}
@@ -0,0 +1,60 @@
+package beacon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/filesystem"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func TestGetBlobArchiveCoverage(t *testing.T) {
+	t.Run("archive disabled", func(t *testing.T) {
+		s := &Server{BlobStorage: filesystem.NewEphemeralBlobStorage(t)}
+		writer := httptest.NewRecorder()
+		s.GetBlobArchiveCoverage(writer, httptest.NewRequest(http.MethodGet, "http://example.com/prysm/v1/beacon/blobs/coverage", nil))
+		assert.Equal(t, http.StatusNotFound, writer.Code)
+	})
+
+	bs := filesystem.NewEphemeralBlobArchive(t)
+	_, err := bs.Coverage().Start(100)
+	require.NoError(t, err)
+	require.NoError(t, bs.Coverage().Extend(50, []filesystem.BlobGap{
+		{Slot: 60, Root: [32]byte{'a'}, Missing: []uint64{0, 2}},
+		{Slot: 80, Root: [32]byte{'b'}, Missing: []uint64{1}},
+	}))
+	s := &Server{BlobStorage: bs}
+
+	t.Run("checked range", func(t *testing.T) {
+		writer := httptest.NewRecorder()
+		s.GetBlobArchiveCoverage(writer, httptest.NewRequest(http.MethodGet, "http://example.com/prysm/v1/beacon/blobs/coverage", nil))
+		require.Equal(t, http.StatusOK, writer.Code)
+		resp := &structs.BlobArchiveCoverageResponse{}
+		require.NoError(t, json.Unmarshal(writer.Body.Bytes(), resp))
+		assert.Equal(t, "50", resp.Data.CheckedStartSlot)
+		assert.Equal(t, "100", resp.Data.CheckedEndSlot)
+		require.Equal(t, 2, len(resp.Data.Gaps))
+		assert.Equal(t, "60", resp.Data.Gaps[0].Slot)
+		assert.Equal(t, fmt.Sprintf("%#x", [32]byte{'a'}), resp.Data.Gaps[0].BlockRoot)
+		assert.DeepEqual(t, []string{"0", "2"}, resp.Data.Gaps[0].MissingIndices)
+	})
+	t.Run("requested range", func(t *testing.T) {
+		writer := httptest.NewRecorder()
+		s.GetBlobArchiveCoverage(writer, httptest.NewRequest(http.MethodGet, "http://example.com/prysm/v1/beacon/blobs/coverage?start_slot=70&end_slot=90", nil))
+		require.Equal(t, http.StatusOK, writer.Code)
+		resp := &structs.BlobArchiveCoverageResponse{}
+		require.NoError(t, json.Unmarshal(writer.Body.Bytes(), resp))
+		require.Equal(t, 1, len(resp.Data.Gaps))
+		assert.Equal(t, "80", resp.Data.Gaps[0].Slot)
+	})
+	t.Run("invalid range", func(t *testing.T) {
+		writer := httptest.NewRecorder()
+		s.GetBlobArchiveCoverage(writer, httptest.NewRequest(http.MethodGet, "http://example.com/prysm/v1/beacon/blobs/coverage?start_slot=90&end_slot=70", nil))
+		assert.Equal(t, http.StatusBadRequest, writer.Code)
+	})
+}
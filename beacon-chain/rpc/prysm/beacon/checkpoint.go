@@ -0,0 +1,93 @@
+package beacon
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/eth/shared"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/sync/checkpoint"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
+)
+
+// GetCheckpointState is a HTTP handler that serves the GET /prysm/v1/beacon/checkpoint/state endpoint.
+// It streams the ssz-encoded finalized state cached for checkpoint sync, and supports range requests
+// so that interrupted downloads can be resumed. The ETag of the response is the state root.
+func (s *Server) GetCheckpointState(w http.ResponseWriter, r *http.Request) {
+	_, span := trace.StartSpan(r.Context(), "beacon.GetCheckpointState")
+	defer span.End()
+
+	if s.CheckpointCache == nil {
+		httputil.HandleError(w, "Checkpoint serving is not enabled", http.StatusNotFound)
+		return
+	}
+	f, cp, err := s.CheckpointCache.OpenState()
+	serveCheckpointFile(w, r, f, cp, err, "checkpoint_state.ssz", (*checkpoint.CachedCheckpoint).StateETag)
+}
+
+// GetCheckpointBlock is a HTTP handler that serves the GET /prysm/v1/beacon/checkpoint/block endpoint.
+// It streams the ssz-encoded block of the finalized state cached for checkpoint sync, and supports range
+// requests so that interrupted downloads can be resumed. The ETag of the response is the block root.
+// The optional block_root query parameter pins the response to the block of a state downloaded before, even if
+// a newer checkpoint was cached in the meantime.
+func (s *Server) GetCheckpointBlock(w http.ResponseWriter, r *http.Request) {
+	_, span := trace.StartSpan(r.Context(), "beacon.GetCheckpointBlock")
+	defer span.End()
+
+	if s.CheckpointCache == nil {
+		httputil.HandleError(w, "Checkpoint serving is not enabled", http.StatusNotFound)
+		return
+	}
+	var (
+		f   *os.File
+		cp  *checkpoint.CachedCheckpoint
+		err error
+	)
+	if rawRoot := r.URL.Query().Get("block_root"); rawRoot != "" {
+		root, valid := shared.ValidateHex(w, "Block root", rawRoot, fieldparams.RootLength)
+		if !valid {
+			return
+		}
+		f, cp, err = s.CheckpointCache.OpenBlockByRoot(bytesutil.ToBytes32(root))
+		if errors.Is(err, checkpoint.ErrNotCached) {
+			httputil.HandleError(w, "No finalized checkpoint with block root "+rawRoot+" is cached", http.StatusNotFound)
+			return
+		}
+	} else {
+		f, cp, err = s.CheckpointCache.OpenBlock()
+	}
+	serveCheckpointFile(w, r, f, cp, err, "checkpoint_block.ssz", (*checkpoint.CachedCheckpoint).BlockETag)
+}
+
+func serveCheckpointFile(
+	w http.ResponseWriter,
+	r *http.Request,
+	f *os.File,
+	cp *checkpoint.CachedCheckpoint,
+	err error,
+	fileName string,
+	etag func(*checkpoint.CachedCheckpoint) string,
+) {
+	if errors.Is(err, checkpoint.ErrNotCached) {
+		httputil.HandleError(w, "No finalized checkpoint is cached yet", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		httputil.HandleError(w, "Could not open cached checkpoint: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.WithError(err).Error("Could not close cached checkpoint file")
+		}
+	}()
+	w.Header().Set("ETag", etag(cp))
+	w.Header().Set(api.VersionHeader, cp.Version)
+	w.Header().Set("Content-Type", api.OctetStreamMediaType)
+	w.Header().Set("Content-Disposition", "attachment; filename="+fileName)
+	http.ServeContent(w, r, fileName, cp.Saved, f)
+}
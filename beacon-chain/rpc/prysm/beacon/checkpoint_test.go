@@ -0,0 +1,91 @@
+package beacon
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/api"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/sync/checkpoint"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func TestGetCheckpointState(t *testing.T) {
+	t.Run("serving disabled", func(t *testing.T) {
+		s := &Server{}
+		writer := httptest.NewRecorder()
+		s.GetCheckpointState(writer, httptest.NewRequest(http.MethodGet, "http://example.com/prysm/v1/beacon/checkpoint/state", nil))
+		assert.Equal(t, http.StatusNotFound, writer.Code)
+	})
+
+	cache, err := checkpoint.NewCache(t.TempDir())
+	require.NoError(t, err)
+	s := &Server{CheckpointCache: cache}
+	t.Run("nothing cached", func(t *testing.T) {
+		writer := httptest.NewRecorder()
+		s.GetCheckpointBlock(writer, httptest.NewRequest(http.MethodGet, "http://example.com/prysm/v1/beacon/checkpoint/block", nil))
+		assert.Equal(t, http.StatusNotFound, writer.Code)
+	})
+
+	cp := &checkpoint.CachedCheckpoint{Epoch: 3, Version: "deneb", BlockRoot: [32]byte{'b'}, StateRoot: [32]byte{'s'}}
+	require.NoError(t, cache.Save(cp, []byte("finalized state"), []byte("finalized block")))
+	t.Run("full", func(t *testing.T) {
+		writer := httptest.NewRecorder()
+		s.GetCheckpointState(writer, httptest.NewRequest(http.MethodGet, "http://example.com/prysm/v1/beacon/checkpoint/state", nil))
+		require.Equal(t, http.StatusOK, writer.Code)
+		assert.Equal(t, "finalized state", writer.Body.String())
+		assert.Equal(t, cp.StateETag(), writer.Header().Get("ETag"))
+		assert.Equal(t, "deneb", writer.Header().Get(api.VersionHeader))
+		assert.Equal(t, "bytes", writer.Header().Get("Accept-Ranges"))
+	})
+	t.Run("range", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "http://example.com/prysm/v1/beacon/checkpoint/block", nil)
+		request.Header.Set("Range", "bytes=10-")
+		request.Header.Set("If-Range", cp.BlockETag())
+		writer := httptest.NewRecorder()
+		s.GetCheckpointBlock(writer, request)
+		require.Equal(t, http.StatusPartialContent, writer.Code)
+		assert.Equal(t, "block", writer.Body.String())
+		assert.Equal(t, "bytes 10-14/15", writer.Header().Get("Content-Range"))
+	})
+	t.Run("range of replaced checkpoint", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "http://example.com/prysm/v1/beacon/checkpoint/state", nil)
+		request.Header.Set("Range", "bytes=10-")
+		request.Header.Set("If-Range", `"0x00"`)
+		writer := httptest.NewRecorder()
+		s.GetCheckpointState(writer, request)
+		require.Equal(t, http.StatusOK, writer.Code)
+		assert.Equal(t, "finalized state", writer.Body.String())
+	})
+	t.Run("block pinned to root", func(t *testing.T) {
+		next := &checkpoint.CachedCheckpoint{Epoch: 4, Version: "deneb", BlockRoot: [32]byte{'n'}, StateRoot: [32]byte{'t'}}
+		c, err := checkpoint.NewCache(t.TempDir())
+		require.NoError(t, err)
+		require.NoError(t, c.Save(cp, []byte("finalized state"), []byte("finalized block")))
+		require.NoError(t, c.Save(next, []byte("next state"), []byte("next block")))
+		s := &Server{CheckpointCache: c}
+
+		writer := httptest.NewRecorder()
+		s.GetCheckpointBlock(writer, httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://example.com/prysm/v1/beacon/checkpoint/block?block_root=%#x", cp.BlockRoot), nil))
+		require.Equal(t, http.StatusOK, writer.Code)
+		assert.Equal(t, "finalized block", writer.Body.String())
+		assert.Equal(t, cp.BlockETag(), writer.Header().Get("ETag"))
+
+		writer = httptest.NewRecorder()
+		s.GetCheckpointBlock(writer, httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://example.com/prysm/v1/beacon/checkpoint/block?block_root=%#x", [32]byte{'x'}), nil))
+		assert.Equal(t, http.StatusNotFound, writer.Code)
+
+		writer = httptest.NewRecorder()
+		s.GetCheckpointBlock(writer, httptest.NewRequest(http.MethodGet, "http://example.com/prysm/v1/beacon/checkpoint/block?block_root=0x01", nil))
+		assert.Equal(t, http.StatusBadRequest, writer.Code)
+	})
+	t.Run("not modified", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "http://example.com/prysm/v1/beacon/checkpoint/state", nil)
+		request.Header.Set("If-None-Match", cp.StateETag())
+		writer := httptest.NewRecorder()
+		s.GetCheckpointState(writer, request)
+		assert.Equal(t, http.StatusNotModified, writer.Code)
+	})
+}
@@ -0,0 +1,203 @@
+package beacon
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/eth/helpers"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/eth/shared"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/encoding/ssz/multiproof"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
+	"github.com/prysmaticlabs/prysm/v5/runtime/version"
+)
+
+// GetStateProof is a HTTP handler that serves the GET /prysm/v1/beacon/states/{state_id}/proof endpoint.
+// It returns a Merkle multiproof of the nodes of the state at the generalized indices given by the
+// gindex query parameter, which can be repeated or comma separated, up to 64 distinct indices.
+//
+// Example usage, proving the withdrawal credentials of validator 3 and the finalized checkpoint root of
+// a Capella state:
+//
+//	GET /prysm/v1/beacon/states/head/proof?gindex=756463999909913,105
+func (s *Server) GetStateProof(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "beacon.GetStateProof")
+	defer span.End()
+
+	stateID := r.PathValue("state_id")
+	if stateID == "" {
+		httputil.HandleError(w, "state_id is required in URL params", http.StatusBadRequest)
+		return
+	}
+	gindices, ok := gindicesFromQuery(w, r)
+	if !ok {
+		return
+	}
+	st, err := s.Stater.State(ctx, []byte(stateID))
+	if err != nil {
+		shared.WriteStateFetchError(w, err)
+		return
+	}
+	proof, err := st.Multiproof(ctx, gindices)
+	if err != nil {
+		writeProofError(w, err)
+		return
+	}
+	if httputil.RespondWithSsz(r) {
+		writeProofSsz(w, proof, st.Version(), "state_proof.ssz")
+		return
+	}
+
+	root, err := st.HashTreeRoot(ctx)
+	if err != nil {
+		httputil.HandleError(w, "Could not compute state root: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	isOptimistic, err := helpers.IsOptimistic(ctx, []byte(stateID), s.OptimisticModeFetcher, s.Stater, s.ChainInfoFetcher, s.BeaconDB)
+	if err != nil {
+		httputil.HandleError(w, "Could not check optimistic status: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	blockRoot, err := st.LatestBlockHeader().HashTreeRoot()
+	if err != nil {
+		httputil.HandleError(w, "Could not compute root of latest block header: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	httputil.WriteJson(w, &structs.GetMerkleProofResponse{
+		Version:             version.String(st.Version()),
+		ExecutionOptimistic: isOptimistic,
+		Finalized:           s.FinalizationFetcher.IsFinalized(ctx, blockRoot),
+		Data:                multiproofJson(root, proof),
+	})
+}
+
+// GetBlockProof is a HTTP handler that serves the GET /prysm/v1/beacon/blocks/{block_id}/proof endpoint.
+// It returns a Merkle multiproof of the nodes of the block at the generalized indices given by the
+// gindex query parameter, which can be repeated or comma separated, up to 64 distinct indices. The
+// proof can descend into the fields of the block body.
+//
+// Example usage, proving the slot and the execution payload of a Deneb block:
+//
+//	GET /prysm/v1/beacon/blocks/head/proof?gindex=8&gindex=201
+func (s *Server) GetBlockProof(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "beacon.GetBlockProof")
+	defer span.End()
+
+	blockID := r.PathValue("block_id")
+	if blockID == "" {
+		httputil.HandleError(w, "block_id is required in URL params", http.StatusBadRequest)
+		return
+	}
+	gindices, ok := gindicesFromQuery(w, r)
+	if !ok {
+		return
+	}
+	blk, err := s.Blocker.Block(ctx, []byte(blockID))
+	if !shared.WriteBlockFetchError(w, blk, err) {
+		return
+	}
+	proof, err := blocks.BlockMultiproof(ctx, blk.Block(), gindices)
+	if err != nil {
+		writeProofError(w, err)
+		return
+	}
+	if httputil.RespondWithSsz(r) {
+		writeProofSsz(w, proof, blk.Version(), "block_proof.ssz")
+		return
+	}
+
+	root, err := blk.Block().HashTreeRoot()
+	if err != nil {
+		httputil.HandleError(w, "Could not compute block root: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	isOptimistic, err := s.OptimisticModeFetcher.IsOptimisticForRoot(ctx, root)
+	if err != nil {
+		httputil.HandleError(w, "Could not check optimistic status: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	httputil.WriteJson(w, &structs.GetMerkleProofResponse{
+		Version:             version.String(blk.Version()),
+		ExecutionOptimistic: isOptimistic,
+		Finalized:           s.FinalizationFetcher.IsFinalized(ctx, root),
+		Data:                multiproofJson(root, proof),
+	})
+}
+
+// maxProofGindices bounds the number of generalized indices of a proof request, so that a single
+// request cannot make the node compute a proof over an arbitrary number of leaves.
+const maxProofGindices = 64
+
+// gindicesFromQuery parses the generalized indices of the gindex query parameter. At most
+// maxProofGindices distinct indices are accepted.
+func gindicesFromQuery(w http.ResponseWriter, r *http.Request) ([]uint64, bool) {
+	var gindices []uint64
+	seen := make(map[uint64]bool)
+	for _, v := range r.URL.Query()["gindex"] {
+		for _, g := range strings.Split(v, ",") {
+			if len(gindices) == maxProofGindices {
+				httputil.HandleError(w, fmt.Sprintf("At most %d gindex values are allowed", maxProofGindices), http.StatusBadRequest)
+				return nil, false
+			}
+			gindex, ok := shared.ValidateUint(w, "gindex", strings.TrimSpace(g))
+			if !ok {
+				return nil, false
+			}
+			if seen[gindex] {
+				httputil.HandleError(w, fmt.Sprintf("Duplicate gindex %d", gindex), http.StatusBadRequest)
+				return nil, false
+			}
+			seen[gindex] = true
+			gindices = append(gindices, gindex)
+		}
+	}
+	if len(gindices) == 0 {
+		httputil.HandleError(w, "gindex is required in query params", http.StatusBadRequest)
+		return nil, false
+	}
+	return gindices, true
+}
+
+func writeProofError(w http.ResponseWriter, err error) {
+	if errors.Is(err, multiproof.ErrInvalidIndices) || errors.Is(err, multiproof.ErrLeaf) {
+		httputil.HandleError(w, "Invalid generalized indices: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	httputil.HandleError(w, "Could not compute proof: "+err.Error(), http.StatusInternalServerError)
+}
+
+func writeProofSsz(w http.ResponseWriter, proof *multiproof.Multiproof, v int, fileName string) {
+	sszResp, err := proof.MarshalSSZ()
+	if err != nil {
+		httputil.HandleError(w, "Could not marshal proof into SSZ: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(api.VersionHeader, version.String(v))
+	httputil.WriteSsz(w, sszResp, fileName)
+}
+
+func multiproofJson(root [32]byte, proof *multiproof.Multiproof) *structs.Multiproof {
+	m := &structs.Multiproof{
+		Root:    hexutil.Encode(root[:]),
+		Indices: make([]string, len(proof.Indices)),
+		Leaves:  make([]string, len(proof.Leaves)),
+		Hashes:  make([]string, len(proof.Hashes)),
+	}
+	for i, g := range proof.Indices {
+		m.Indices[i] = strconv.FormatUint(g, 10)
+	}
+	for i, l := range proof.Leaves {
+		m.Leaves[i] = hexutil.Encode(l[:])
+	}
+	for i, h := range proof.Hashes {
+		m.Hashes[i] = hexutil.Encode(h[:])
+	}
+	return m
+}
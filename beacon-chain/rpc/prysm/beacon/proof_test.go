@@ -0,0 +1,122 @@
+package beacon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/prysmaticlabs/prysm/v5/api"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	chainMock "github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/testutil"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/encoding/ssz/multiproof"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+func TestGetStateProof(t *testing.T) {
+	ctx := context.Background()
+	st, _ := util.DeterministicGenesisStateCapella(t, 8)
+	root, err := st.HashTreeRoot(ctx)
+	require.NoError(t, err)
+	s := &Server{
+		Stater:                &testutil.MockStater{BeaconState: st},
+		OptimisticModeFetcher: &chainMock.ChainService{},
+		FinalizationFetcher:   &chainMock.ChainService{},
+	}
+	// The withdrawal credentials of validator 3, and the finalized checkpoint root.
+	withdrawalCredentials := strconv.FormatUint(multiproof.Concat(32+11, 2, 1<<40+3, 8+1), 10)
+
+	t.Run("json", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "http://example.com/prysm/v1/beacon/states/head/proof?gindex="+withdrawalCredentials+",105", nil)
+		request.SetPathValue("state_id", "head")
+		writer := httptest.NewRecorder()
+
+		s.GetStateProof(writer, request)
+		require.Equal(t, http.StatusOK, writer.Code)
+		resp := &structs.GetMerkleProofResponse{}
+		require.NoError(t, json.Unmarshal(writer.Body.Bytes(), resp))
+		assert.Equal(t, "capella", resp.Version)
+		assert.Equal(t, hexutil.Encode(root[:]), resp.Data.Root)
+		assert.DeepEqual(t, []string{"105", withdrawalCredentials}, resp.Data.Indices)
+		val, err := st.ValidatorAtIndexReadOnly(3)
+		require.NoError(t, err)
+		creds := val.GetWithdrawalCredentials()
+		assert.Equal(t, hexutil.Encode(creds), resp.Data.Leaves[1])
+	})
+	t.Run("ssz", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "http://example.com/prysm/v1/beacon/states/head/proof?gindex=105&gindex="+withdrawalCredentials, nil)
+		request.SetPathValue("state_id", "head")
+		request.Header.Set("Accept", api.OctetStreamMediaType)
+		writer := httptest.NewRecorder()
+
+		s.GetStateProof(writer, request)
+		require.Equal(t, http.StatusOK, writer.Code)
+		assert.Equal(t, "capella", writer.Header().Get(api.VersionHeader))
+		p := &multiproof.Multiproof{}
+		require.NoError(t, p.UnmarshalSSZ(writer.Body.Bytes()))
+		ok, err := p.Verify(root)
+		require.NoError(t, err)
+		assert.Equal(t, true, ok)
+	})
+	t.Run("invalid gindex", func(t *testing.T) {
+		tooMany := make([]string, maxProofGindices+1)
+		for i := range tooMany {
+			tooMany[i] = strconv.Itoa(64 + i)
+		}
+		for _, q := range []string{
+			"",
+			"?gindex=foo",
+			"?gindex=2,4",
+			"?gindex=" + strconv.FormatUint(multiproof.Concat(32+9, 2), 10),
+			"?gindex=105&gindex=105",
+			"?gindex=" + strings.Join(tooMany, ","),
+		} {
+			request := httptest.NewRequest(http.MethodGet, "http://example.com/prysm/v1/beacon/states/head/proof"+q, nil)
+			request.SetPathValue("state_id", "head")
+			writer := httptest.NewRecorder()
+
+			s.GetStateProof(writer, request)
+			require.Equal(t, http.StatusBadRequest, writer.Code)
+			e := &httputil.DefaultJsonError{}
+			require.NoError(t, json.Unmarshal(writer.Body.Bytes(), e))
+			assert.Equal(t, http.StatusBadRequest, e.Code)
+		}
+	})
+}
+
+func TestGetBlockProof(t *testing.T) {
+	b := util.NewBeaconBlockDeneb()
+	b.Block.Slot = 5
+	blk, err := blocks.NewSignedBeaconBlock(b)
+	require.NoError(t, err)
+	root, err := blk.Block().HashTreeRoot()
+	require.NoError(t, err)
+	s := &Server{
+		Blocker:               &testutil.MockBlocker{BlockToReturn: blk},
+		OptimisticModeFetcher: &chainMock.ChainService{},
+		FinalizationFetcher:   &chainMock.ChainService{FinalizedRoots: map[[32]byte]bool{root: true}},
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "http://example.com/prysm/v1/beacon/blocks/head/proof?gindex=8&gindex=201", nil)
+	request.SetPathValue("block_id", "head")
+	writer := httptest.NewRecorder()
+
+	s.GetBlockProof(writer, request)
+	require.Equal(t, http.StatusOK, writer.Code)
+	resp := &structs.GetMerkleProofResponse{}
+	require.NoError(t, json.Unmarshal(writer.Body.Bytes(), resp))
+	assert.Equal(t, "deneb", resp.Version)
+	assert.Equal(t, true, resp.Finalized)
+	assert.Equal(t, hexutil.Encode(root[:]), resp.Data.Root)
+	assert.DeepEqual(t, []string{"8", "201"}, resp.Data.Indices)
+	assert.Equal(t, "0x0500000000000000000000000000000000000000000000000000000000000000", resp.Data.Leaves[0])
+}
@@ -3,11 +3,13 @@ package beacon
 import (
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain"
 	beacondb "github.com/prysmaticlabs/prysm/v5/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/filesystem"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/core"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/lookup"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state/stategen"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/sync"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/sync/checkpoint"
 )
 
 type Server struct {
@@ -18,9 +20,12 @@ type Server struct {
 	CanonicalHistory      *stategen.CanonicalHistory
 	BeaconDB              beacondb.ReadOnlyDatabase
 	Stater                lookup.Stater
+	Blocker               lookup.Blocker
 	ChainInfoFetcher      blockchain.ChainInfoFetcher
 	FinalizationFetcher   blockchain.FinalizationFetcher
 	CoreService           *core.Service
 	Broadcaster           p2p.Broadcaster
 	BlobReceiver          blockchain.BlobReceiver
+	CheckpointCache       *checkpoint.Cache
+	BlobStorage           *filesystem.BlobStorage
 }
@@ -0,0 +1,319 @@
+package beacon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api"
+	"github.com/prysmaticlabs/prysm/v5/api/pagination"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	rpchelpers "github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/eth/helpers"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/eth/shared"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/validator"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+)
+
+// Fields of the validator stream projection.
+const (
+	fieldBalance   = "balance"
+	fieldStatus    = "status"
+	fieldValidator = "validator"
+)
+
+// validatorsFlushInterval is the number of validators written between two flushes of the stream.
+const validatorsFlushInterval = 1000
+
+// validatorsFilter selects the validators of the stream.
+type validatorsFilter struct {
+	statuses            map[validator.Status]bool
+	credentialsPrefix   []byte
+	minEffectiveBalance uint64
+	maxEffectiveBalance uint64
+}
+
+func (f *validatorsFilter) matches(val state.ReadOnlyValidator, status, subStatus validator.Status) bool {
+	if len(f.statuses) > 0 && !f.statuses[status] && !f.statuses[subStatus] {
+		return false
+	}
+	if len(f.credentialsPrefix) > 0 && !bytes.HasPrefix(val.GetWithdrawalCredentials(), f.credentialsPrefix) {
+		return false
+	}
+	eb := val.EffectiveBalance()
+	return eb >= f.minEffectiveBalance && eb <= f.maxEffectiveBalance
+}
+
+// validatorsProjection is the set of fields of the streamed validators. The index is always included.
+type validatorsProjection struct {
+	balance   bool
+	status    bool
+	validator bool
+}
+
+// StreamValidators is a HTTP handler that serves the GET /prysm/v1/beacon/states/{state_id}/validators endpoint.
+// It streams the validators of the state as newline-delimited JSON objects, or with an `Accept: application/octet-stream`
+// header as a sequence of fixed-size SSZ records, so that clients can process the registry without holding it in memory.
+//
+// Query parameters:
+//   - status: validator statuses or sub-statuses to include, as in /eth/v1/beacon/states/{state_id}/validators.
+//   - withdrawal_credentials_prefix: hex prefix of the withdrawal credentials, such as 0x01.
+//   - min_effective_balance, max_effective_balance: inclusive effective balance range in Gwei.
+//   - fields: fields to include besides the index, any of balance, status and validator. All fields are included by default.
+//   - page_size, page_token: a page covers page_size validator indices from the index page_token * page_size, and the
+//     filters apply within the page, so that pages can hold fewer than page_size validators. The token of the next page
+//     is returned in the Prysm-Next-Page-Token header, which is empty for the last page. The whole registry is
+//     streamed when page_size is not set.
+//
+// An SSZ record is the little-endian uint64 index, followed by the requested fields in the order balance (uint64),
+// status (uint8, the numeric value of the sub-status) and validator (the SSZ encoding of the validator container).
+// The execution optimistic and finalized flags are returned in the Prysm-Execution-Optimistic and Prysm-Finalized headers.
+func (s *Server) StreamValidators(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "beacon.StreamValidators")
+	defer span.End()
+
+	stateId := r.PathValue("state_id")
+	if stateId == "" {
+		httputil.HandleError(w, "state_id is required in URL params", http.StatusBadRequest)
+		return
+	}
+	filter, ok := validatorsFilterFromQuery(w, r)
+	if !ok {
+		return
+	}
+	projection, ok := validatorsProjectionFromQuery(w, r)
+	if !ok {
+		return
+	}
+	rawPageSize, pageSize, ok := shared.UintFromQuery(w, r, "page_size", false)
+	if !ok {
+		return
+	}
+	pageToken := r.URL.Query().Get("page_token")
+
+	st, err := s.Stater.State(ctx, []byte(stateId))
+	if err != nil {
+		shared.WriteStateFetchError(w, err)
+		return
+	}
+	isOptimistic, err := rpchelpers.IsOptimistic(ctx, []byte(stateId), s.OptimisticModeFetcher, s.Stater, s.ChainInfoFetcher, s.BeaconDB)
+	if err != nil {
+		httputil.HandleError(w, "Could not check optimistic status: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	blockRoot, err := st.LatestBlockHeader().HashTreeRoot()
+	if err != nil {
+		httputil.HandleError(w, "Could not calculate root of latest block header: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	isFinalized := s.FinalizationFetcher.IsFinalized(ctx, blockRoot)
+
+	start, end, nextPageToken := 0, st.NumValidators(), ""
+	if rawPageSize != "" || pageToken != "" {
+		start, end, nextPageToken, err = pagination.StartAndEndPage(pageToken, int(pageSize), st.NumValidators())
+		if err != nil {
+			httputil.HandleError(w, "Could not paginate validators: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	ssz := httputil.RespondWithSsz(r)
+	if ssz {
+		w.Header().Set("Content-Type", api.OctetStreamMediaType)
+	} else {
+		w.Header().Set("Content-Type", api.NdjsonMediaType)
+	}
+	w.Header().Set(api.ExecutionOptimisticHeader, strconv.FormatBool(isOptimistic))
+	w.Header().Set(api.FinalizedHeader, strconv.FormatBool(isFinalized))
+	w.Header().Set(api.NextPageTokenHeader, nextPageToken)
+	w.WriteHeader(http.StatusOK)
+
+	// Errors can no longer be reported with the status code once the stream started, so they end the stream.
+	if err := streamValidators(w, st, start, end, filter, projection, ssz); err != nil {
+		log.WithError(err).Debug("Could not stream validators")
+	}
+}
+
+func streamValidators(
+	w http.ResponseWriter,
+	st state.BeaconState,
+	start, end int,
+	filter *validatorsFilter,
+	projection *validatorsProjection,
+	ssz bool,
+) error {
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	epoch := slots.ToEpoch(st.Slot())
+	written := 0
+	for i := start; i < end; i++ {
+		idx := primitives.ValidatorIndex(i)
+		val, err := st.ValidatorAtIndexReadOnly(idx)
+		if err != nil {
+			return errors.Wrapf(err, "could not get validator %d", i)
+		}
+		status, err := rpchelpers.ValidatorStatus(val, epoch)
+		if err != nil {
+			return errors.Wrapf(err, "could not get status of validator %d", i)
+		}
+		subStatus, err := rpchelpers.ValidatorSubStatus(val, epoch)
+		if err != nil {
+			return errors.Wrapf(err, "could not get status of validator %d", i)
+		}
+		if !filter.matches(val, status, subStatus) {
+			continue
+		}
+		var balance uint64
+		if projection.balance {
+			balance, err = st.BalanceAtIndex(idx)
+			if err != nil {
+				return errors.Wrapf(err, "could not get balance of validator %d", i)
+			}
+		}
+		if ssz {
+			err = writeValidatorSSZ(w, st, idx, balance, subStatus, projection)
+		} else {
+			err = enc.Encode(streamedValidator(val, idx, balance, subStatus, projection))
+		}
+		if err != nil {
+			return errors.Wrapf(err, "could not write validator %d", i)
+		}
+		written++
+		if flusher != nil && written%validatorsFlushInterval == 0 {
+			flusher.Flush()
+		}
+	}
+	if flusher != nil {
+		flusher.Flush()
+	}
+	return nil
+}
+
+func streamedValidator(
+	val state.ReadOnlyValidator,
+	idx primitives.ValidatorIndex,
+	balance uint64,
+	status validator.Status,
+	projection *validatorsProjection,
+) *structs.StreamedValidator {
+	v := &structs.StreamedValidator{Index: strconv.FormatUint(uint64(idx), 10)}
+	if projection.balance {
+		v.Balance = strconv.FormatUint(balance, 10)
+	}
+	if projection.status {
+		v.Status = status.String()
+	}
+	if projection.validator {
+		pubkey := val.PublicKey()
+		v.Validator = &structs.Validator{
+			Pubkey:                     hexutil.Encode(pubkey[:]),
+			WithdrawalCredentials:      hexutil.Encode(val.GetWithdrawalCredentials()),
+			EffectiveBalance:           strconv.FormatUint(val.EffectiveBalance(), 10),
+			Slashed:                    val.Slashed(),
+			ActivationEligibilityEpoch: strconv.FormatUint(uint64(val.ActivationEligibilityEpoch()), 10),
+			ActivationEpoch:            strconv.FormatUint(uint64(val.ActivationEpoch()), 10),
+			ExitEpoch:                  strconv.FormatUint(uint64(val.ExitEpoch()), 10),
+			WithdrawableEpoch:          strconv.FormatUint(uint64(val.WithdrawableEpoch()), 10),
+		}
+	}
+	return v
+}
+
+func writeValidatorSSZ(
+	w http.ResponseWriter,
+	st state.BeaconState,
+	idx primitives.ValidatorIndex,
+	balance uint64,
+	status validator.Status,
+	projection *validatorsProjection,
+) error {
+	record := binary.LittleEndian.AppendUint64(make([]byte, 0, 8), uint64(idx))
+	if projection.balance {
+		record = binary.LittleEndian.AppendUint64(record, balance)
+	}
+	if projection.status {
+		record = append(record, byte(status))
+	}
+	if projection.validator {
+		val, err := st.ValidatorAtIndex(idx)
+		if err != nil {
+			return err
+		}
+		record, err = val.MarshalSSZTo(record)
+		if err != nil {
+			return err
+		}
+	}
+	_, err := w.Write(record)
+	return err
+}
+
+func validatorsFilterFromQuery(w http.ResponseWriter, r *http.Request) (*validatorsFilter, bool) {
+	f := &validatorsFilter{maxEffectiveBalance: ^uint64(0)}
+	query := r.URL.Query()
+	if statuses := query["status"]; len(statuses) > 0 {
+		f.statuses = make(map[validator.Status]bool, len(statuses))
+		for _, ss := range statuses {
+			ok, vs := validator.StatusFromString(strings.ToLower(ss))
+			if !ok {
+				httputil.HandleError(w, "Invalid status "+ss, http.StatusBadRequest)
+				return nil, false
+			}
+			f.statuses[vs] = true
+		}
+	}
+	if prefix := query.Get("withdrawal_credentials_prefix"); prefix != "" {
+		b, err := hexutil.Decode(prefix)
+		if err != nil || len(b) > 32 {
+			httputil.HandleError(w, "Invalid withdrawal_credentials_prefix "+prefix, http.StatusBadRequest)
+			return nil, false
+		}
+		f.credentialsPrefix = b
+	}
+	raw, v, ok := shared.UintFromQuery(w, r, "min_effective_balance", false)
+	if !ok {
+		return nil, false
+	}
+	if raw != "" {
+		f.minEffectiveBalance = v
+	}
+	raw, v, ok = shared.UintFromQuery(w, r, "max_effective_balance", false)
+	if !ok {
+		return nil, false
+	}
+	if raw != "" {
+		f.maxEffectiveBalance = v
+	}
+	return f, true
+}
+
+func validatorsProjectionFromQuery(w http.ResponseWriter, r *http.Request) (*validatorsProjection, bool) {
+	fields := r.URL.Query()["fields"]
+	if len(fields) == 0 {
+		return &validatorsProjection{balance: true, status: true, validator: true}, true
+	}
+	p := &validatorsProjection{}
+	for _, f := range fields {
+		switch strings.ToLower(f) {
+		case fieldBalance:
+			p.balance = true
+		case fieldStatus:
+			p.status = true
+		case fieldValidator:
+			p.validator = true
+		case "index":
+		default:
+			httputil.HandleError(w, "Invalid field "+f, http.StatusBadRequest)
+			return nil, false
+		}
+	}
+	return p, true
+}
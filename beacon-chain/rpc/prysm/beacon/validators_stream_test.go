@@ -0,0 +1,165 @@
+package beacon
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/api"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	chainMock "github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/testutil"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/validator"
+	eth "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+func streamValidatorsTestServer(t *testing.T) (*Server, state.BeaconState) {
+	st, _ := util.DeterministicGenesisState(t, 8)
+	// Validators 4 to 7 have execution withdrawal credentials and a lower effective balance.
+	for i := 4; i < 8; i++ {
+		val, err := st.ValidatorAtIndex(primitives.ValidatorIndex(i))
+		require.NoError(t, err)
+		val.WithdrawalCredentials[0] = params.BeaconConfig().ETH1AddressWithdrawalPrefixByte
+		val.EffectiveBalance = params.BeaconConfig().MaxEffectiveBalance / 2
+		require.NoError(t, st.UpdateValidatorAtIndex(primitives.ValidatorIndex(i), val))
+	}
+	// Validator 7 is pending.
+	val, err := st.ValidatorAtIndex(7)
+	require.NoError(t, err)
+	val.ActivationEligibilityEpoch = params.BeaconConfig().FarFutureEpoch
+	val.ActivationEpoch = params.BeaconConfig().FarFutureEpoch
+	require.NoError(t, st.UpdateValidatorAtIndex(7, val))
+
+	chainService := &chainMock.ChainService{FinalizedRoots: map[[32]byte]bool{}}
+	return &Server{
+		OptimisticModeFetcher: chainService,
+		FinalizationFetcher:   chainService,
+		Stater:                &testutil.MockStater{BeaconState: st},
+	}, st
+}
+
+func streamValidatorsRequest(s *Server, query, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/prysm/v1/beacon/states/head/validators?"+query, nil)
+	req.SetPathValue("state_id", "head")
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rr := httptest.NewRecorder()
+	s.StreamValidators(rr, req)
+	return rr
+}
+
+func decodeStreamedValidators(t *testing.T, rr *httptest.ResponseRecorder) []*structs.StreamedValidator {
+	var vals []*structs.StreamedValidator
+	scanner := bufio.NewScanner(rr.Body)
+	for scanner.Scan() {
+		v := &structs.StreamedValidator{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), v))
+		vals = append(vals, v)
+	}
+	require.NoError(t, scanner.Err())
+	return vals
+}
+
+func TestStreamValidators(t *testing.T) {
+	s, _ := streamValidatorsTestServer(t)
+
+	t.Run("all", func(t *testing.T) {
+		rr := streamValidatorsRequest(s, "", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, api.NdjsonMediaType, rr.Header().Get("Content-Type"))
+		assert.Equal(t, "false", rr.Header().Get(api.ExecutionOptimisticHeader))
+		assert.Equal(t, "false", rr.Header().Get(api.FinalizedHeader))
+		assert.Equal(t, "", rr.Header().Get(api.NextPageTokenHeader))
+		vals := decodeStreamedValidators(t, rr)
+		require.Equal(t, 8, len(vals))
+		assert.Equal(t, "7", vals[7].Index)
+		assert.Equal(t, "32000000000", vals[7].Balance)
+		assert.Equal(t, "pending_initialized", vals[7].Status)
+		require.NotNil(t, vals[7].Validator)
+		assert.Equal(t, "16000000000", vals[7].Validator.EffectiveBalance)
+	})
+	t.Run("projection", func(t *testing.T) {
+		rr := streamValidatorsRequest(s, "fields=balance", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		vals := decodeStreamedValidators(t, rr)
+		require.Equal(t, 8, len(vals))
+		assert.DeepEqual(t, &structs.StreamedValidator{Index: "0", Balance: "32000000000"}, vals[0])
+	})
+	t.Run("filters", func(t *testing.T) {
+		rr := streamValidatorsRequest(s, "withdrawal_credentials_prefix=0x01&status=active&fields=status", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		vals := decodeStreamedValidators(t, rr)
+		require.Equal(t, 3, len(vals))
+		assert.DeepEqual(t, &structs.StreamedValidator{Index: "4", Status: "active_ongoing"}, vals[0])
+
+		rr = streamValidatorsRequest(s, "min_effective_balance=20000000000", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, 4, len(decodeStreamedValidators(t, rr)))
+		rr = streamValidatorsRequest(s, "max_effective_balance=20000000000", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, 4, len(decodeStreamedValidators(t, rr)))
+	})
+	t.Run("pagination", func(t *testing.T) {
+		var indices []string
+		token := "0"
+		for token != "" {
+			rr := streamValidatorsRequest(s, "page_size=3&fields=index&page_token="+token, "")
+			require.Equal(t, http.StatusOK, rr.Code)
+			for _, v := range decodeStreamedValidators(t, rr) {
+				indices = append(indices, v.Index)
+			}
+			token = rr.Header().Get(api.NextPageTokenHeader)
+		}
+		assert.DeepEqual(t, []string{"0", "1", "2", "3", "4", "5", "6", "7"}, indices)
+	})
+	t.Run("ssz", func(t *testing.T) {
+		rr := streamValidatorsRequest(s, "fields=balance&fields=status&status=pending", api.OctetStreamMediaType)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, api.OctetStreamMediaType, rr.Header().Get("Content-Type"))
+		b := rr.Body.Bytes()
+		require.Equal(t, 17, len(b))
+		assert.Equal(t, uint64(7), binary.LittleEndian.Uint64(b[:8]))
+		assert.Equal(t, params.BeaconConfig().MaxEffectiveBalance, binary.LittleEndian.Uint64(b[8:16]))
+		assert.Equal(t, byte(validator.PendingInitialized), b[16])
+	})
+	t.Run("ssz validator", func(t *testing.T) {
+		rr := streamValidatorsRequest(s, "fields=validator", api.OctetStreamMediaType)
+		require.Equal(t, http.StatusOK, rr.Code)
+		b := rr.Body.Bytes()
+		require.Equal(t, 8*(8+121), len(b))
+		val := &eth.Validator{}
+		require.NoError(t, val.UnmarshalSSZ(b[8+129:2*129]))
+		assert.Equal(t, params.BeaconConfig().MaxEffectiveBalance, val.EffectiveBalance)
+	})
+}
+
+func TestStreamValidators_InvalidRequest(t *testing.T) {
+	s, _ := streamValidatorsTestServer(t)
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "invalid status", query: "status=foo"},
+		{name: "invalid field", query: "fields=pubkey"},
+		{name: "invalid prefix", query: "withdrawal_credentials_prefix=foo"},
+		{name: "invalid balance", query: "min_effective_balance=foo"},
+		{name: "invalid page token", query: "page_size=2&page_token=100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := streamValidatorsRequest(s, tt.query, "")
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/forkchoice"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
+)
+
+const elStatusUnknown = "UNKNOWN"
+
+// GetOptimisticReport reports on the optimistically imported blocks in fork choice, grouped in ranges of
+// consecutive optimistic blocks, with the last execution client status of each block's payload. It also lists
+// the payloads recently found INVALID by the execution client, and the validator duties refused because the
+// node was optimistic.
+func (s *Server) GetOptimisticReport(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "debug.GetOptimisticReport")
+	defer span.End()
+
+	isOptimistic, err := s.OptimisticModeFetcher.IsOptimistic(ctx)
+	if err != nil {
+		httputil.HandleError(w, "Could not check optimistic status: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	dump, err := s.ForkchoiceFetcher.ForkChoiceDump(ctx)
+	if err != nil {
+		httputil.HandleError(w, "Could not get forkchoice dump: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	invalid := s.OptimisticReporter.InvalidPayloads()
+	invalidPayloads := make([]*structs.InvalidPayload, len(invalid))
+	for i, p := range invalid {
+		roots := make([]string, len(p.InvalidRoots))
+		for j, root := range p.InvalidRoots {
+			roots[j] = hexutil.Encode(root[:])
+		}
+		invalidPayloads[i] = &structs.InvalidPayload{
+			BlockRoot:       hexutil.Encode(p.BlockRoot[:]),
+			LatestValidHash: hexutil.Encode(p.LatestValidHash[:]),
+			InvalidRoots:    roots,
+			Method:          p.Method,
+			Timestamp:       fmt.Sprintf("%d", p.Time.Unix()),
+		}
+	}
+	blocked := s.OptimisticReporter.BlockedDuties()
+	blockedDuties := make([]*structs.BlockedDuty, len(blocked))
+	for i, d := range blocked {
+		blockedDuties[i] = &structs.BlockedDuty{
+			Duty:          d.Duty,
+			Count:         fmt.Sprintf("%d", d.Count),
+			LastTimestamp: fmt.Sprintf("%d", d.Last.Unix()),
+		}
+	}
+
+	httputil.WriteJson(w, &structs.GetOptimisticReportResponse{
+		IsOptimistic:     isOptimistic,
+		HeadSlot:         fmt.Sprintf("%d", s.HeadFetcher.HeadSlot()),
+		OptimisticRanges: s.optimisticRanges(dump),
+		InvalidPayloads:  invalidPayloads,
+		BlockedDuties:    blockedDuties,
+	})
+}
+
+// optimisticRanges groups the optimistic nodes of the fork choice dump into ranges, each made of an optimistic
+// node whose parent is fully validated, and all of its optimistic descendants. A range is canonical when the
+// head descends from its first block.
+func (s *Server) optimisticRanges(dump *forkchoice.Dump) []*structs.OptimisticRange {
+	nodes := make(map[[32]byte]*forkchoice.Node, len(dump.ForkChoiceNodes))
+	children := make(map[[32]byte][]*forkchoice.Node)
+	for _, n := range dump.ForkChoiceNodes {
+		nodes[bytesutil.ToBytes32(n.BlockRoot)] = n
+		children[bytesutil.ToBytes32(n.ParentRoot)] = append(children[bytesutil.ToBytes32(n.ParentRoot)], n)
+	}
+	canonical := make(map[[32]byte]bool)
+	for root := bytesutil.ToBytes32(dump.HeadRoot); ; {
+		n, ok := nodes[root]
+		if !ok {
+			break
+		}
+		canonical[root] = true
+		root = bytesutil.ToBytes32(n.ParentRoot)
+	}
+
+	starts := make([]*forkchoice.Node, 0)
+	for _, n := range dump.ForkChoiceNodes {
+		if !n.ExecutionOptimistic {
+			continue
+		}
+		if parent, ok := nodes[bytesutil.ToBytes32(n.ParentRoot)]; ok && parent.ExecutionOptimistic {
+			continue
+		}
+		starts = append(starts, n)
+	}
+	sort.SliceStable(starts, func(i, j int) bool {
+		return starts[i].Slot < starts[j].Slot
+	})
+
+	ranges := make([]*structs.OptimisticRange, 0, len(starts))
+	for _, n := range starts {
+		blocks := []*forkchoice.Node{n}
+		for i := 0; i < len(blocks); i++ {
+			for _, c := range children[bytesutil.ToBytes32(blocks[i].BlockRoot)] {
+				if c.ExecutionOptimistic {
+					blocks = append(blocks, c)
+				}
+			}
+		}
+		sort.SliceStable(blocks, func(i, j int) bool {
+			return blocks[i].Slot < blocks[j].Slot
+		})
+		last := blocks[len(blocks)-1]
+		rng := &structs.OptimisticRange{
+			StartSlot:  fmt.Sprintf("%d", n.Slot),
+			StartRoot:  hexutil.Encode(n.BlockRoot),
+			EndSlot:    fmt.Sprintf("%d", last.Slot),
+			EndRoot:    hexutil.Encode(last.BlockRoot),
+			BlockCount: fmt.Sprintf("%d", len(blocks)),
+			Canonical:  canonical[bytesutil.ToBytes32(n.BlockRoot)],
+			Blocks:     make([]*structs.OptimisticBlock, len(blocks)),
+		}
+		for i, b := range blocks {
+			rng.Blocks[i] = &structs.OptimisticBlock{
+				Slot:               fmt.Sprintf("%d", b.Slot),
+				BlockRoot:          hexutil.Encode(b.BlockRoot),
+				ParentRoot:         hexutil.Encode(b.ParentRoot),
+				ExecutionBlockHash: hexutil.Encode(b.ExecutionBlockHash),
+				ElStatus:           elStatusUnknown,
+			}
+			if st, ok := s.OptimisticReporter.PayloadStatus(bytesutil.ToBytes32(b.ExecutionBlockHash)); ok {
+				rng.Blocks[i].ElStatus = st.Status
+				rng.Blocks[i].ElStatusMethod = st.Method
+				rng.Blocks[i].ElStatusTimestamp = fmt.Sprintf("%d", st.Time.Unix())
+			}
+		}
+		ranges = append(ranges, rng)
+	}
+	return ranges
+}
+
+// RevalidateOptimisticPayloads re-submits the execution payloads of the optimistic blocks in the requested slot
+// range to the execution client, and returns the resulting status of each block.
+func (s *Server) RevalidateOptimisticPayloads(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "debug.RevalidateOptimisticPayloads")
+	defer span.End()
+
+	var req structs.RevalidateOptimisticPayloadsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.HandleError(w, "Could not decode JSON request body", http.StatusBadRequest)
+		return
+	}
+	start, err := strconv.ParseUint(req.StartSlot, 10, 64)
+	if err != nil {
+		httputil.HandleError(w, "Could not parse start slot: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	end, err := strconv.ParseUint(req.EndSlot, 10, 64)
+	if err != nil {
+		httputil.HandleError(w, "Could not parse end slot: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	results, err := s.OptimisticReporter.RevalidatePayloads(ctx, primitives.Slot(start), primitives.Slot(end))
+	if errors.Is(err, blockchain.ErrRevalidationRange) {
+		httputil.HandleError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		httputil.HandleError(w, "Could not revalidate payloads: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data := make([]*structs.PayloadRevalidation, len(results))
+	for i, res := range results {
+		data[i] = &structs.PayloadRevalidation{
+			Slot:      fmt.Sprintf("%d", res.Slot),
+			BlockRoot: hexutil.Encode(res.BlockRoot[:]),
+			Status:    res.Status,
+		}
+		if res.Err != nil {
+			data[i].Error = res.Err.Error()
+		}
+	}
+	httputil.WriteJson(w, &structs.RevalidateOptimisticPayloadsResponse{Data: data})
+}
@@ -0,0 +1,197 @@
+package debug
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain"
+	blockchainmock "github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/forkchoice"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+type mockForkchoice struct {
+	*blockchainmock.ChainService
+	dump *forkchoice.Dump
+}
+
+func (m *mockForkchoice) ForkChoiceDump(_ context.Context) (*forkchoice.Dump, error) {
+	return m.dump, nil
+}
+
+type mockReporter struct {
+	statuses   map[[32]byte]blockchain.PayloadStatus
+	invalid    []blockchain.InvalidPayload
+	blocked    []blockchain.BlockedDuty
+	results    []blockchain.PayloadRevalidation
+	err        error
+	start, end primitives.Slot
+}
+
+func (m *mockReporter) PayloadStatus(blockHash [32]byte) (blockchain.PayloadStatus, bool) {
+	st, ok := m.statuses[blockHash]
+	return st, ok
+}
+
+func (m *mockReporter) InvalidPayloads() []blockchain.InvalidPayload {
+	return m.invalid
+}
+
+func (m *mockReporter) BlockedDuties() []blockchain.BlockedDuty {
+	return m.blocked
+}
+
+func (m *mockReporter) RecordBlockedDuty(string) {}
+
+func (m *mockReporter) RevalidatePayloads(_ context.Context, start, end primitives.Slot) ([]blockchain.PayloadRevalidation, error) {
+	m.start, m.end = start, end
+	return m.results, m.err
+}
+
+func fcNode(slot primitives.Slot, root, parent byte, optimistic bool) *forkchoice.Node {
+	return &forkchoice.Node{
+		Slot:                slot,
+		BlockRoot:           bytes.Repeat([]byte{root}, 32),
+		ParentRoot:          bytes.Repeat([]byte{parent}, 32),
+		ExecutionBlockHash:  bytes.Repeat([]byte{root + 0x80}, 32),
+		ExecutionOptimistic: optimistic,
+	}
+}
+
+func TestGetOptimisticReport(t *testing.T) {
+	// 1 <- 2 <- 3 <- 4 is the canonical chain, with 3 and 4 optimistic. 5 is an optimistic fork of 2.
+	dump := &forkchoice.Dump{
+		HeadRoot: bytes.Repeat([]byte{4}, 32),
+		ForkChoiceNodes: []*forkchoice.Node{
+			fcNode(1, 1, 0, false),
+			fcNode(2, 2, 1, false),
+			fcNode(4, 4, 3, true),
+			fcNode(3, 3, 2, true),
+			fcNode(3, 5, 2, true),
+		},
+	}
+	now := time.Now()
+	reporter := &mockReporter{
+		statuses: map[[32]byte]blockchain.PayloadStatus{
+			[32]byte(bytes.Repeat([]byte{0x83}, 32)): {Status: blockchain.PayloadStatusSyncing, Method: "engine_newPayload", Time: now},
+		},
+		invalid: []blockchain.InvalidPayload{{
+			BlockRoot:       [32]byte{6},
+			LatestValidHash: [32]byte{7},
+			InvalidRoots:    [][32]byte{{6}, {8}},
+			Method:          "engine_newPayload",
+			Time:            now,
+		}},
+		blocked: []blockchain.BlockedDuty{{Duty: blockchain.DutyBlockProposal, Count: 3, Last: now}},
+	}
+	chainService := &blockchainmock.ChainService{Optimistic: true}
+	s := &Server{
+		ForkchoiceFetcher:     &mockForkchoice{ChainService: chainService, dump: dump},
+		OptimisticModeFetcher: chainService,
+		HeadFetcher:           chainService,
+		OptimisticReporter:    reporter,
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "http://example.com/prysm/v1/debug/optimistic", nil)
+	writer := httptest.NewRecorder()
+	writer.Body = &bytes.Buffer{}
+
+	s.GetOptimisticReport(writer, request)
+	require.Equal(t, http.StatusOK, writer.Code)
+	resp := &structs.GetOptimisticReportResponse{}
+	require.NoError(t, json.Unmarshal(writer.Body.Bytes(), resp))
+	assert.Equal(t, true, resp.IsOptimistic)
+	require.Equal(t, 2, len(resp.OptimisticRanges))
+
+	canonical := resp.OptimisticRanges[0]
+	if !canonical.Canonical {
+		canonical = resp.OptimisticRanges[1]
+	}
+	assert.Equal(t, true, canonical.Canonical)
+	assert.Equal(t, "3", canonical.StartSlot)
+	assert.Equal(t, hexutil.Encode(bytes.Repeat([]byte{3}, 32)), canonical.StartRoot)
+	assert.Equal(t, "4", canonical.EndSlot)
+	assert.Equal(t, hexutil.Encode(bytes.Repeat([]byte{4}, 32)), canonical.EndRoot)
+	assert.Equal(t, "2", canonical.BlockCount)
+	require.Equal(t, 2, len(canonical.Blocks))
+	assert.Equal(t, blockchain.PayloadStatusSyncing, canonical.Blocks[0].ElStatus)
+	assert.Equal(t, "engine_newPayload", canonical.Blocks[0].ElStatusMethod)
+	assert.Equal(t, elStatusUnknown, canonical.Blocks[1].ElStatus)
+
+	fork := resp.OptimisticRanges[0]
+	if fork.Canonical {
+		fork = resp.OptimisticRanges[1]
+	}
+	assert.Equal(t, false, fork.Canonical)
+	assert.Equal(t, "1", fork.BlockCount)
+	assert.Equal(t, hexutil.Encode(bytes.Repeat([]byte{5}, 32)), fork.StartRoot)
+
+	require.Equal(t, 1, len(resp.InvalidPayloads))
+	lvh := [32]byte{7}
+	assert.Equal(t, hexutil.Encode(lvh[:]), resp.InvalidPayloads[0].LatestValidHash)
+	assert.Equal(t, 2, len(resp.InvalidPayloads[0].InvalidRoots))
+	require.Equal(t, 1, len(resp.BlockedDuties))
+	assert.Equal(t, blockchain.DutyBlockProposal, resp.BlockedDuties[0].Duty)
+	assert.Equal(t, "3", resp.BlockedDuties[0].Count)
+}
+
+func TestRevalidateOptimisticPayloads(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		reporter := &mockReporter{results: []blockchain.PayloadRevalidation{
+			{Slot: 3, BlockRoot: [32]byte{3}, Status: blockchain.PayloadStatusValid},
+			{Slot: 4, BlockRoot: [32]byte{4}, Status: blockchain.PayloadStatusEngineError, Err: errors.New("timeout")},
+		}}
+		s := &Server{OptimisticReporter: reporter}
+
+		request := httptest.NewRequest(http.MethodPost, "http://example.com/prysm/v1/debug/optimistic/revalidate", strings.NewReader(`{"start_slot":"3","end_slot":"10"}`))
+		writer := httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+
+		s.RevalidateOptimisticPayloads(writer, request)
+		require.Equal(t, http.StatusOK, writer.Code)
+		assert.Equal(t, primitives.Slot(3), reporter.start)
+		assert.Equal(t, primitives.Slot(10), reporter.end)
+		resp := &structs.RevalidateOptimisticPayloadsResponse{}
+		require.NoError(t, json.Unmarshal(writer.Body.Bytes(), resp))
+		require.Equal(t, 2, len(resp.Data))
+		assert.Equal(t, blockchain.PayloadStatusValid, resp.Data[0].Status)
+		assert.Equal(t, "", resp.Data[0].Error)
+		assert.Equal(t, "4", resp.Data[1].Slot)
+		assert.Equal(t, "timeout", resp.Data[1].Error)
+	})
+	t.Run("invalid range", func(t *testing.T) {
+		s := &Server{OptimisticReporter: &mockReporter{err: errors.Wrap(blockchain.ErrRevalidationRange, "start=10, end=3")}}
+
+		request := httptest.NewRequest(http.MethodPost, "http://example.com/prysm/v1/debug/optimistic/revalidate", strings.NewReader(`{"start_slot":"10","end_slot":"3"}`))
+		writer := httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+
+		s.RevalidateOptimisticPayloads(writer, request)
+		require.Equal(t, http.StatusBadRequest, writer.Code)
+		e := &httputil.DefaultJsonError{}
+		require.NoError(t, json.Unmarshal(writer.Body.Bytes(), e))
+		assert.StringContains(t, "invalid payload revalidation range", e.Message)
+	})
+	t.Run("bad slot", func(t *testing.T) {
+		s := &Server{OptimisticReporter: &mockReporter{}}
+
+		request := httptest.NewRequest(http.MethodPost, "http://example.com/prysm/v1/debug/optimistic/revalidate", strings.NewReader(`{"start_slot":"foo","end_slot":"3"}`))
+		writer := httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+
+		s.RevalidateOptimisticPayloads(writer, request)
+		require.Equal(t, http.StatusBadRequest, writer.Code)
+	})
+}
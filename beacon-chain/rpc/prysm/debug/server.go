@@ -0,0 +1,13 @@
+// Package debug defines Prysm-specific debugging endpoints of the beacon node.
+package debug
+
+import (
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain"
+)
+
+type Server struct {
+	ForkchoiceFetcher     blockchain.ForkchoiceFetcher
+	OptimisticModeFetcher blockchain.OptimisticModeFetcher
+	HeadFetcher           blockchain.HeadFetcher
+	OptimisticReporter    blockchain.OptimisticReporter
+}
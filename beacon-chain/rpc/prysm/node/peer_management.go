@@ -0,0 +1,302 @@
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers/peerdata"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/types"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
+	"github.com/sirupsen/logrus"
+)
+
+// ListPeerBans lists the peer IDs and IP ranges banned by the operator.
+func (s *Server) ListPeerBans(w http.ResponseWriter, r *http.Request) {
+	_, span := trace.StartSpan(r.Context(), "node.ListPeerBans")
+	defer span.End()
+
+	bans := s.PeersFetcher.Peers().Bans()
+	resp := &structs.ListPeerBansResponse{Data: make([]*structs.PeerBan, len(bans))}
+	for i, ban := range bans {
+		resp.Data[i] = peerBanFromBan(ban)
+	}
+	httputil.WriteJson(w, resp)
+}
+
+// BanPeer bans a peer ID, an IP address or an IP range in CIDR notation for the given duration in seconds, or
+// permanently if the duration is zero or omitted. Connected peers matching the ban are sent a goodbye message and
+// disconnected, and banned peers are refused by the connection gater.
+func (s *Server) BanPeer(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "node.BanPeer")
+	defer span.End()
+
+	var req structs.BanPeerRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	switch {
+	case errors.Is(err, io.EOF):
+		httputil.HandleError(w, "No data submitted", http.StatusBadRequest)
+		return
+	case err != nil:
+		httputil.HandleError(w, "Could not decode request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	var duration uint64
+	if req.Duration != "" {
+		duration, err = strconv.ParseUint(req.Duration, 10, 63)
+		if err != nil {
+			httputil.HandleError(w, "Could not parse duration: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if duration > uint64(math.MaxInt64/int64(time.Second)) {
+			httputil.HandleError(w, fmt.Sprintf("Duration must be at most %d seconds", math.MaxInt64/int64(time.Second)), http.StatusBadRequest)
+			return
+		}
+	}
+	ban, err := peers.NewBan(req.Target, time.Duration(duration)*time.Second, req.Reason)
+	if err != nil {
+		httputil.HandleError(w, "Invalid ban target: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	peerStatus := s.PeersFetcher.Peers()
+	peerStatus.Ban(ban)
+	disconnected := make([]string, 0)
+	for _, pid := range peerStatus.Connected() {
+		if err := peerStatus.IsBad(pid); !errors.Is(err, peers.ErrPeerBanned) {
+			continue
+		}
+		if err := s.PeerManager.DisconnectWithGoodbye(ctx, pid, types.GoodbyeCodeBanned); err != nil {
+			log.WithError(err).WithField("peer", pid).Error("Could not disconnect from banned peer")
+			continue
+		}
+		disconnected = append(disconnected, pid.String())
+	}
+	persistPeers(peerStatus)
+	log.WithFields(logrus.Fields{
+		"target": ban.Target,
+		"reason": ban.Reason,
+	}).Info("Banned peer")
+	httputil.WriteJson(w, &structs.BanPeerResponse{Data: peerBanFromBan(ban), DisconnectedPeers: disconnected})
+}
+
+// UnbanPeer lifts the ban of a peer ID, an IP address or an IP range.
+func (s *Server) UnbanPeer(w http.ResponseWriter, r *http.Request) {
+	_, span := trace.StartSpan(r.Context(), "node.UnbanPeer")
+	defer span.End()
+
+	target := r.PathValue("target")
+	peerStatus := s.PeersFetcher.Peers()
+	found, err := peerStatus.Unban(target)
+	if err != nil {
+		httputil.HandleError(w, "Invalid ban target: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !found {
+		httputil.HandleError(w, "Ban not found", http.StatusNotFound)
+		return
+	}
+	persistPeers(peerStatus)
+	w.WriteHeader(http.StatusOK)
+}
+
+// ConnectPeer dials a peer given by its multiaddr, which must include the peer ID, or by its ENR.
+func (s *Server) ConnectPeer(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "node.ConnectPeer")
+	defer span.End()
+
+	var req structs.AddrRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	switch {
+	case errors.Is(err, io.EOF):
+		httputil.HandleError(w, "No data submitted", http.StatusBadRequest)
+		return
+	case err != nil:
+		httputil.HandleError(w, "Could not decode request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	addrs, err := p2p.PeersFromStringAddrs([]string{req.Addr})
+	if err != nil {
+		httputil.HandleError(w, "Could not parse peer address: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	infos, err := peer.AddrInfosFromP2pAddrs(addrs...)
+	if err != nil || len(infos) != 1 {
+		httputil.HandleError(w, "Could not derive peer info from address, the peer ID is required", http.StatusBadRequest)
+		return
+	}
+	if err := s.PeerManager.ConnectToPeer(ctx, infos[0]); err != nil {
+		httputil.HandleError(w, "Could not connect to peer: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// DisconnectPeer sends a goodbye message to a connected peer and disconnects from it. The goodbye reason code is
+// optional and defaults to a generic error.
+func (s *Server) DisconnectPeer(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "node.DisconnectPeer")
+	defer span.End()
+
+	pid, ok := peerIdFromPath(w, r)
+	if !ok {
+		return
+	}
+	var req structs.DisconnectPeerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		httputil.HandleError(w, "Could not decode request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	code := types.GoodbyeCodeGenericError
+	if req.ReasonCode != "" {
+		c, err := strconv.ParseUint(req.ReasonCode, 10, 64)
+		if err != nil {
+			httputil.HandleError(w, "Could not parse reason code: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		code = types.RPCGoodbyeCode(c)
+	}
+	state, err := s.PeersFetcher.Peers().ConnectionState(pid)
+	if err != nil || state != peers.Connected {
+		httputil.HandleError(w, "Peer is not connected", http.StatusNotFound)
+		return
+	}
+	if err := s.PeerManager.DisconnectWithGoodbye(ctx, pid, code); err != nil {
+		httputil.HandleError(w, "Could not disconnect from peer: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// GetPeerScore returns the score of a known peer, along with the bad responses and processed blocks it is
+// computed from.
+func (s *Server) GetPeerScore(w http.ResponseWriter, r *http.Request) {
+	_, span := trace.StartSpan(r.Context(), "node.GetPeerScore")
+	defer span.End()
+
+	pid, ok := peerIdFromPath(w, r)
+	if !ok {
+		return
+	}
+	score, ok := s.peerScore(w, pid)
+	if !ok {
+		return
+	}
+	httputil.WriteJson(w, &structs.GetPeerScoreResponse{Data: score})
+}
+
+// SetPeerScore overrides the bad responses and the processed blocks of a known peer, from which its score is
+// computed. Omitted values are left unchanged. Scores decay as usual after being set.
+func (s *Server) SetPeerScore(w http.ResponseWriter, r *http.Request) {
+	_, span := trace.StartSpan(r.Context(), "node.SetPeerScore")
+	defer span.End()
+
+	pid, ok := peerIdFromPath(w, r)
+	if !ok {
+		return
+	}
+	var req structs.SetPeerScoreRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	switch {
+	case errors.Is(err, io.EOF):
+		httputil.HandleError(w, "No data submitted", http.StatusBadRequest)
+		return
+	case err != nil:
+		httputil.HandleError(w, "Could not decode request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	var badResponses, processedBlocks uint64
+	if req.BadResponses != "" {
+		if badResponses, err = strconv.ParseUint(req.BadResponses, 10, 31); err != nil {
+			httputil.HandleError(w, "Could not parse bad responses: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if req.ProcessedBlocks != "" {
+		if processedBlocks, err = strconv.ParseUint(req.ProcessedBlocks, 10, 64); err != nil {
+			httputil.HandleError(w, "Could not parse processed blocks: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	peerStatus := s.PeersFetcher.Peers()
+	if _, err := peerStatus.ConnectionState(pid); errors.Is(err, peerdata.ErrPeerUnknown) {
+		httputil.HandleError(w, "Peer not found", http.StatusNotFound)
+		return
+	}
+	if req.BadResponses != "" {
+		peerStatus.Scorers().BadResponsesScorer().Set(pid, int(badResponses))
+	}
+	if req.ProcessedBlocks != "" {
+		peerStatus.Scorers().BlockProviderScorer().SetProcessedBlocks(pid, processedBlocks)
+	}
+	score, ok := s.peerScore(w, pid)
+	if !ok {
+		return
+	}
+	httputil.WriteJson(w, &structs.GetPeerScoreResponse{Data: score})
+}
+
+func (s *Server) peerScore(w http.ResponseWriter, pid peer.ID) (*structs.PeerScore, bool) {
+	peerStatus := s.PeersFetcher.Peers()
+	scorers := peerStatus.Scorers()
+	badResponses, err := scorers.BadResponsesScorer().Count(pid)
+	if err != nil {
+		if errors.Is(err, peerdata.ErrPeerUnknown) {
+			httputil.HandleError(w, "Peer not found", http.StatusNotFound)
+			return nil, false
+		}
+		httputil.HandleError(w, "Could not get bad responses: "+err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	gossipScore, _, _, err := scorers.GossipScorer().GossipData(pid)
+	if err != nil {
+		httputil.HandleError(w, "Could not get gossip score: "+err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	return &structs.PeerScore{
+		PeerId:          pid.String(),
+		Score:           strconv.FormatFloat(scorers.Score(pid), 'f', -1, 64),
+		BadResponses:    strconv.Itoa(badResponses),
+		ProcessedBlocks: strconv.FormatUint(scorers.BlockProviderScorer().ProcessedBlocks(pid), 10),
+		GossipScore:     strconv.FormatFloat(gossipScore, 'f', -1, 64),
+		IsBad:           peerStatus.IsBad(pid) != nil,
+	}, true
+}
+
+func peerIdFromPath(w http.ResponseWriter, r *http.Request) (peer.ID, bool) {
+	pid, err := peer.Decode(r.PathValue("peer_id"))
+	if err != nil {
+		httputil.HandleError(w, "Could not decode peer ID: "+err.Error(), http.StatusBadRequest)
+		return "", false
+	}
+	return pid, true
+}
+
+func peerBanFromBan(ban *peers.Ban) *structs.PeerBan {
+	expiry := "0"
+	if !ban.Expiry.IsZero() {
+		expiry = strconv.FormatInt(ban.Expiry.Unix(), 10)
+	}
+	return &structs.PeerBan{
+		Target: ban.Target,
+		Reason: ban.Reason,
+		Expiry: expiry,
+	}
+}
+
+// persistPeers persists the peer status store right away, so that ban changes survive a crash.
+func persistPeers(peerStatus *peers.Status) {
+	if err := peerStatus.Persist(); err != nil {
+		log.WithError(err).Error("Could not persist peers")
+	}
+}
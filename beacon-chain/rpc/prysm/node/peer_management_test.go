@@ -0,0 +1,159 @@
+package node
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	corenet "github.com/libp2p/go-libp2p/core/network"
+	libp2ptest "github.com/libp2p/go-libp2p/p2p/host/peerstore/test"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers"
+	mockp2p "github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/testing"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/types"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func TestPeerBans(t *testing.T) {
+	ids := libp2ptest.GeneratePeerIDs(3)
+	peerFetcher := &mockp2p.MockPeersProvider{}
+	peerFetcher.ClearPeers()
+	peerStatus := peerFetcher.Peers()
+	for i, id := range ids {
+		addr, err := ma.NewMultiaddr("/ip4/10.0.0." + strconv.Itoa(i+1) + "/tcp/13000")
+		require.NoError(t, err)
+		peerStatus.Add(nil, id, addr, corenet.DirOutbound)
+		peerStatus.SetConnectionState(id, peers.Connected)
+	}
+	peerManager := &mockp2p.MockPeerManager{}
+	s := &Server{PeersFetcher: peerFetcher, PeerManager: peerManager}
+
+	ban := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/prysm/v1/node/peers/bans", bytes.NewBufferString(body))
+		rr := httptest.NewRecorder()
+		s.BanPeer(rr, req)
+		return rr
+	}
+
+	rr := ban(`{"target":"` + ids[0].String() + `","duration":"3600","reason":"spam"}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	resp := &structs.BanPeerResponse{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), resp))
+	assert.Equal(t, ids[0].String(), resp.Data.Target)
+	assert.DeepEqual(t, []string{ids[0].String()}, resp.DisconnectedPeers)
+	assert.Equal(t, types.GoodbyeCodeBanned, peerManager.Goodbyes[ids[0]])
+	peerStatus.SetConnectionState(ids[0], peers.Disconnected)
+
+	// Peers 1 and 2 are in the banned range, which is normalized.
+	rr = ban(`{"target":"10.0.0.2/31"}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	resp = &structs.BanPeerResponse{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), resp))
+	assert.Equal(t, "10.0.0.2/31", resp.Data.Target)
+	assert.Equal(t, "0", resp.Data.Expiry)
+	assert.Equal(t, 2, len(resp.DisconnectedPeers))
+
+	for _, body := range []string{``, `{"target":"foo"}`, `{"target":"10.0.0.1","duration":"-1"}`, `{"target":"10.0.0.1","duration":"9223372037"}`} {
+		assert.Equal(t, http.StatusBadRequest, ban(body).Code, body)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/prysm/v1/node/peers/bans", nil)
+	rr = httptest.NewRecorder()
+	s.ListPeerBans(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	list := &structs.ListPeerBansResponse{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), list))
+	require.Equal(t, 2, len(list.Data))
+	assert.Equal(t, "spam", list.Data[1].Reason)
+
+	unban := func(target string) int {
+		req := httptest.NewRequest(http.MethodDelete, "/prysm/v1/node/peers/bans/"+target, nil)
+		req.SetPathValue("target", target)
+		rr := httptest.NewRecorder()
+		s.UnbanPeer(rr, req)
+		return rr.Code
+	}
+	assert.Equal(t, http.StatusOK, unban("10.0.0.2/31"))
+	assert.Equal(t, http.StatusNotFound, unban("10.0.0.2/31"))
+	assert.Equal(t, http.StatusBadRequest, unban("foo"))
+	assert.Equal(t, 1, len(peerStatus.Bans()))
+	assert.NoError(t, peerStatus.IsBad(ids[1]))
+}
+
+func TestConnectAndDisconnectPeer(t *testing.T) {
+	peerFetcher := &mockp2p.MockPeersProvider{}
+	peerFetcher.ClearPeers()
+	peerManager := &mockp2p.MockPeerManager{}
+	s := &Server{PeersFetcher: peerFetcher, PeerManager: peerManager}
+
+	addr := "/ip4/127.0.0.1/tcp/13000/p2p/16Uiu2HAm7yD5fhhw1Kihg5pffaGbvKV3k7sqxRGHMZzkb7u9UUxQ"
+	req := httptest.NewRequest(http.MethodPost, "/prysm/v1/node/peers/connect", bytes.NewBufferString(`{"addr":"`+addr+`"}`))
+	rr := httptest.NewRecorder()
+	s.ConnectPeer(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, 1, len(peerManager.ConnectedPeers))
+	pid := peerManager.ConnectedPeers[0].ID
+	assert.Equal(t, "16Uiu2HAm7yD5fhhw1Kihg5pffaGbvKV3k7sqxRGHMZzkb7u9UUxQ", pid.String())
+
+	req = httptest.NewRequest(http.MethodPost, "/prysm/v1/node/peers/connect", bytes.NewBufferString(`{"addr":"/ip4/127.0.0.1/tcp/13000"}`))
+	rr = httptest.NewRecorder()
+	s.ConnectPeer(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	disconnect := func(body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/prysm/v1/node/peers/"+pid.String()+"/disconnect", bytes.NewBufferString(body))
+		req.SetPathValue("peer_id", pid.String())
+		rr := httptest.NewRecorder()
+		s.DisconnectPeer(rr, req)
+		return rr.Code
+	}
+	assert.Equal(t, http.StatusNotFound, disconnect(""))
+	maddr, err := ma.NewMultiaddr("/ip4/127.0.0.1/tcp/13000")
+	require.NoError(t, err)
+	peerFetcher.Peers().Add(nil, pid, maddr, corenet.DirOutbound)
+	peerFetcher.Peers().SetConnectionState(pid, peers.Connected)
+	assert.Equal(t, http.StatusBadRequest, disconnect(`{"reason_code":"foo"}`))
+	assert.Equal(t, http.StatusOK, disconnect(`{"reason_code":"1"}`))
+	assert.Equal(t, types.GoodbyeCodeClientShutdown, peerManager.Goodbyes[pid])
+}
+
+func TestPeerScore(t *testing.T) {
+	ids := libp2ptest.GeneratePeerIDs(2)
+	peerFetcher := &mockp2p.MockPeersProvider{}
+	peerFetcher.ClearPeers()
+	peerFetcher.Peers().Add(nil, ids[0], nil, corenet.DirOutbound)
+	s := &Server{PeersFetcher: peerFetcher}
+
+	setScore := func(id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/prysm/v1/node/peers/"+id+"/score", bytes.NewBufferString(body))
+		req.SetPathValue("peer_id", id)
+		rr := httptest.NewRecorder()
+		s.SetPeerScore(rr, req)
+		return rr
+	}
+	rr := setScore(ids[0].String(), `{"bad_responses":"10","processed_blocks":"64"}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	resp := &structs.GetPeerScoreResponse{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), resp))
+	assert.Equal(t, "10", resp.Data.BadResponses)
+	assert.Equal(t, "64", resp.Data.ProcessedBlocks)
+	assert.Equal(t, true, resp.Data.IsBad)
+
+	req := httptest.NewRequest(http.MethodGet, "/prysm/v1/node/peers/"+ids[0].String()+"/score", nil)
+	req.SetPathValue("peer_id", ids[0].String())
+	rr = httptest.NewRecorder()
+	s.GetPeerScore(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	resp = &structs.GetPeerScoreResponse{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), resp))
+	assert.Equal(t, "10", resp.Data.BadResponses)
+
+	assert.Equal(t, http.StatusNotFound, setScore(ids[1].String(), `{"bad_responses":"0"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, setScore(ids[0].String(), `{"bad_responses":"-1"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, setScore("foo", `{}`).Code)
+}
@@ -3,6 +3,7 @@ package validator
 import (
 	"context"
 
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/cache"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/core"
@@ -99,7 +100,7 @@ func (vs *Server) processAggregateSelection(ctx context.Context, req *ethpb.Aggr
 
 	// An optimistic validator MUST NOT participate in attestation
 	// (i.e., sign across the DOMAIN_BEACON_ATTESTER, DOMAIN_SELECTION_PROOF or DOMAIN_AGGREGATE_AND_PROOF domains).
-	if err := vs.optimisticStatus(ctx); err != nil {
+	if err := vs.optimisticStatus(ctx, blockchain.DutyAggregateAttestation); err != nil {
 		return 0, 0, err
 	}
 
@@ -67,7 +67,7 @@ func (vs *Server) GetBeaconBlock(ctx context.Context, req *ethpb.BlockRequest) (
 	}
 	// An optimistic validator MUST NOT produce a block (i.e., sign across the DOMAIN_BEACON_PROPOSER domain).
 	if slots.ToEpoch(req.Slot) >= params.BeaconConfig().BellatrixForkEpoch {
-		if err := vs.optimisticStatus(ctx); err != nil {
+		if err := vs.optimisticStatus(ctx, blockchain.DutyBlockProposal); err != nil {
 			return nil, status.Errorf(codes.Unavailable, "Validator is not ready to propose: %v", err)
 		}
 	}
@@ -252,7 +252,7 @@ func (vs *Server) BuildBlockParallel(ctx context.Context, sBlk interfaces.Signed
 			}
 		}
 
-		winningBid, bundle, err = setExecutionData(ctx, sBlk, local, builderBid, builderBoostFactor)
+		winningBid, bundle, err = setExecutionData(ctx, sBlk, local, builderBid, builderBoostFactor, vs.minBuilderBid(sBlk.Block().ProposerIndex()))
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "Could not set execution data: %v", err)
 		}
@@ -431,6 +431,8 @@ func (vs *Server) PrepareBeaconProposer(
 			Active:       true, // TODO: either check or add the field in the request
 			Index:        r.ValidatorIndex,
 			FeeRecipient: feeRecipient,
+			Relays:       builder.AllowedRelays(vs.BlockBuilder, r.ValidatorIndex, r.Relays),
+			MinBid:       r.MinBid,
 		}
 		vs.TrackedValidatorsCache.Set(val)
 		validatorIndices = append(validatorIndices, r.ValidatorIndex)
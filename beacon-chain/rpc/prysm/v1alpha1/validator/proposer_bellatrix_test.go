@@ -104,7 +104,7 @@ func TestServer_setExecutionData(t *testing.T) {
 		builderBid, err := vs.getBuilderPayloadAndBlobs(ctx, b.Slot(), b.ProposerIndex(), gasLimit)
 		require.NoError(t, err)
 		require.IsNil(t, builderBid)
-		_, bundle, err := setExecutionData(context.Background(), blk, res, builderBid, defaultBuilderBoostFactor)
+		_, bundle, err := setExecutionData(context.Background(), blk, res, builderBid, defaultBuilderBoostFactor, vs.minBuilderBid(blk.Block().ProposerIndex()))
 		require.NoError(t, err)
 		require.IsNil(t, bundle)
 		e, err := blk.Block().Body().Execution()
@@ -178,7 +178,7 @@ func TestServer_setExecutionData(t *testing.T) {
 			require.NoError(t, err)
 		}
 		require.DeepEqual(t, [][]uint8{}, builderKzgCommitments)
-		_, bundle, err := setExecutionData(context.Background(), blk, res, builderBid, defaultBuilderBoostFactor)
+		_, bundle, err := setExecutionData(context.Background(), blk, res, builderBid, defaultBuilderBoostFactor, vs.minBuilderBid(blk.Block().ProposerIndex()))
 		require.NoError(t, err)
 		require.IsNil(t, bundle)
 		e, err := blk.Block().Body().Execution()
@@ -255,7 +255,7 @@ func TestServer_setExecutionData(t *testing.T) {
 			require.NoError(t, err)
 		}
 		require.DeepEqual(t, [][]uint8{}, builderKzgCommitments)
-		_, bundle, err := setExecutionData(context.Background(), blk, res, builderBid, defaultBuilderBoostFactor)
+		_, bundle, err := setExecutionData(context.Background(), blk, res, builderBid, defaultBuilderBoostFactor, vs.minBuilderBid(blk.Block().ProposerIndex()))
 		require.NoError(t, err)
 		require.IsNil(t, bundle)
 		e, err := blk.Block().Body().Execution()
@@ -331,7 +331,7 @@ func TestServer_setExecutionData(t *testing.T) {
 			require.NoError(t, err)
 		}
 		require.DeepEqual(t, [][]uint8{}, builderKzgCommitments)
-		_, bundle, err := setExecutionData(context.Background(), blk, res, builderBid, math.MaxUint64)
+		_, bundle, err := setExecutionData(context.Background(), blk, res, builderBid, math.MaxUint64, vs.minBuilderBid(blk.Block().ProposerIndex()))
 		require.NoError(t, err)
 		require.IsNil(t, bundle)
 		e, err := blk.Block().Body().Execution()
@@ -407,7 +407,7 @@ func TestServer_setExecutionData(t *testing.T) {
 			require.NoError(t, err)
 		}
 		require.DeepEqual(t, [][]uint8{}, builderKzgCommitments)
-		_, bundle, err := setExecutionData(context.Background(), blk, res, builderBid, 0)
+		_, bundle, err := setExecutionData(context.Background(), blk, res, builderBid, 0, vs.minBuilderBid(blk.Block().ProposerIndex()))
 		require.NoError(t, err)
 		require.IsNil(t, bundle)
 		e, err := blk.Block().Body().Execution()
@@ -433,7 +433,7 @@ func TestServer_setExecutionData(t *testing.T) {
 			require.NoError(t, err)
 		}
 		require.DeepEqual(t, [][]uint8{}, builderKzgCommitments)
-		_, bundle, err := setExecutionData(context.Background(), blk, res, builderBid, defaultBuilderBoostFactor)
+		_, bundle, err := setExecutionData(context.Background(), blk, res, builderBid, defaultBuilderBoostFactor, vs.minBuilderBid(blk.Block().ProposerIndex()))
 		require.NoError(t, err)
 		require.IsNil(t, bundle)
 		e, err := blk.Block().Body().Execution()
@@ -465,7 +465,7 @@ func TestServer_setExecutionData(t *testing.T) {
 			require.NoError(t, err)
 		}
 		require.DeepEqual(t, [][]uint8{}, builderKzgCommitments)
-		_, bundle, err := setExecutionData(context.Background(), blk, res, builderBid, defaultBuilderBoostFactor)
+		_, bundle, err := setExecutionData(context.Background(), blk, res, builderBid, defaultBuilderBoostFactor, vs.minBuilderBid(blk.Block().ProposerIndex()))
 		require.NoError(t, err)
 		require.IsNil(t, bundle)
 		e, err := blk.Block().Body().Execution()
@@ -500,7 +500,7 @@ func TestServer_setExecutionData(t *testing.T) {
 		_, err = builderBid.Header()
 		require.NoError(t, err)
 		require.DeepEqual(t, [][]uint8{}, builderKzgCommitments)
-		_, bundle, err := setExecutionData(context.Background(), blk, res, builderBid, defaultBuilderBoostFactor)
+		_, bundle, err := setExecutionData(context.Background(), blk, res, builderBid, defaultBuilderBoostFactor, vs.minBuilderBid(blk.Block().ProposerIndex()))
 		require.NoError(t, err)
 		require.IsNil(t, bundle)
 		e, err := blk.Block().Body().Execution()
@@ -526,7 +526,7 @@ func TestServer_setExecutionData(t *testing.T) {
 		builderBid, err := vs.getBuilderPayloadAndBlobs(ctx, b.Slot(), b.ProposerIndex(), gasLimit)
 		require.ErrorIs(t, consensus_types.ErrNilObjectWrapped, err) // Builder returns fault. Use local block
 		require.IsNil(t, builderBid)
-		_, bundle, err := setExecutionData(context.Background(), blk, res, nil, defaultBuilderBoostFactor)
+		_, bundle, err := setExecutionData(context.Background(), blk, res, nil, defaultBuilderBoostFactor, vs.minBuilderBid(blk.Block().ProposerIndex()))
 		require.NoError(t, err)
 		require.IsNil(t, bundle)
 		e, err := blk.Block().Body().Execution()
@@ -655,7 +655,7 @@ func TestServer_setExecutionData(t *testing.T) {
 
 		res, err := vs.getLocalPayload(ctx, blk.Block(), denebTransitionState)
 		require.NoError(t, err)
-		_, bundle, err := setExecutionData(context.Background(), blk, res, builderBid, defaultBuilderBoostFactor)
+		_, bundle, err := setExecutionData(context.Background(), blk, res, builderBid, defaultBuilderBoostFactor, vs.minBuilderBid(blk.Block().ProposerIndex()))
 		require.NoError(t, err)
 		require.IsNil(t, bundle)
 
@@ -50,6 +50,19 @@ func (vs *Server) validatorRegistered(ctx context.Context, id primitives.Validat
 	return true, nil
 }
 
+// minBuilderBid returns the minimum builder bid accepted for the proposer with index `idx`, which is
+// the proposer's own minimum if it set one, or the configured minimum otherwise. A proposer can
+// only raise the configured minimum, not lower it.
+func (vs *Server) minBuilderBid(idx primitives.ValidatorIndex) primitives.Gwei {
+	minBid := primitives.Gwei(params.BeaconConfig().MinBuilderBid)
+	if vs.TrackedValidatorsCache != nil {
+		if val, ok := vs.TrackedValidatorsCache.Validator(idx); ok && val.MinBid > minBid {
+			return val.MinBid
+		}
+	}
+	return minBid
+}
+
 // circuitBreakBuilder returns true if the builder is not allowed to be used due to circuit breaker conditions.
 func (vs *Server) circuitBreakBuilder(s primitives.Slot) (bool, error) {
 	if vs.ForkchoiceFetcher == nil {
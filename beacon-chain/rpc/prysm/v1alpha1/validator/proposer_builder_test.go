@@ -8,6 +8,7 @@ import (
 	blockchainTest "github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain/testing"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/builder"
 	testing2 "github.com/prysmaticlabs/prysm/v5/beacon-chain/builder/testing"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/cache"
 	dbTest "github.com/prysmaticlabs/prysm/v5/beacon-chain/db/testing"
 	doublylinkedtree "github.com/prysmaticlabs/prysm/v5/beacon-chain/forkchoice/doubly-linked-tree"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
@@ -106,6 +107,26 @@ func TestServer_validatorRegistered(t *testing.T) {
 
 }
 
+func TestServer_minBuilderBid(t *testing.T) {
+	params.SetupTestConfigCleanup(t)
+	cfg := params.BeaconConfig().Copy()
+	cfg.MinBuilderBid = 5
+	params.OverrideBeaconConfig(cfg)
+
+	proposerServer := &Server{}
+	require.Equal(t, primitives.Gwei(5), proposerServer.minBuilderBid(1))
+
+	proposerServer.TrackedValidatorsCache = cache.NewTrackedValidatorsCache()
+	proposerServer.TrackedValidatorsCache.Set(cache.TrackedValidator{Index: 1, MinBid: 8})
+	proposerServer.TrackedValidatorsCache.Set(cache.TrackedValidator{Index: 2})
+	// A proposer's own minimum cannot lower the configured one.
+	proposerServer.TrackedValidatorsCache.Set(cache.TrackedValidator{Index: 4, MinBid: 2})
+	require.Equal(t, primitives.Gwei(8), proposerServer.minBuilderBid(1))
+	require.Equal(t, primitives.Gwei(5), proposerServer.minBuilderBid(2))
+	require.Equal(t, primitives.Gwei(5), proposerServer.minBuilderBid(3))
+	require.Equal(t, primitives.Gwei(5), proposerServer.minBuilderBid(4))
+}
+
 func TestServer_canUseBuilder(t *testing.T) {
 	proposerServer := &Server{
 		BlockBuilder: &testing2.MockBuilderService{
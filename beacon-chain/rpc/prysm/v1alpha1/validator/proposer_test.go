@@ -2837,6 +2837,8 @@ func TestProposer_PrepareBeaconProposer(t *testing.T) {
 		name    string
 		args    args
 		wantErr string
+		// Relays expected to be tracked for the validator, if they differ from the requested ones.
+		wantRelays []string
 	}{
 		{
 			name: "Happy Path",
@@ -2852,6 +2854,22 @@ func TestProposer_PrepareBeaconProposer(t *testing.T) {
 			},
 			wantErr: "",
 		},
+		{
+			name: "Relays and min bid",
+			args: args{
+				request: &ethpb.PrepareBeaconProposerRequest{
+					Recipients: []*ethpb.PrepareBeaconProposerRequest_FeeRecipientContainer{
+						{
+							FeeRecipient:   make([]byte, fieldparams.FeeRecipientLength),
+							ValidatorIndex: 1,
+							Relays:         []string{"https://relay.example.com"},
+							MinBid:         100,
+						},
+					},
+				},
+			},
+			wantErr: "",
+		},
 		{
 			name: "invalid fee recipient length",
 			args: args{
@@ -2866,6 +2884,21 @@ func TestProposer_PrepareBeaconProposer(t *testing.T) {
 			},
 			wantErr: "Invalid fee recipient address",
 		},
+		{
+			name: "relay not allowed is dropped",
+			args: args{
+				request: &ethpb.PrepareBeaconProposerRequest{
+					Recipients: []*ethpb.PrepareBeaconProposerRequest_FeeRecipientContainer{
+						{
+							FeeRecipient:   make([]byte, fieldparams.FeeRecipientLength),
+							ValidatorIndex: 1,
+							Relays:         []string{"http://169.254.169.254", "https://relay.example.com"},
+						},
+					},
+				},
+			},
+			wantRelays: []string{"https://relay.example.com"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -2874,6 +2907,7 @@ func TestProposer_PrepareBeaconProposer(t *testing.T) {
 			proposerServer := &Server{
 				BeaconDB:               db,
 				TrackedValidatorsCache: cache.NewTrackedValidatorsCache(),
+				BlockBuilder:           &builderTest.MockBuilderService{AllowedRelays: []string{"https://relay.example.com"}},
 			}
 			require.Equal(t, false, proposerServer.TrackedValidatorsCache.Validating())
 			_, err := proposerServer.PrepareBeaconProposer(ctx, tt.args.request)
@@ -2887,7 +2921,12 @@ func TestProposer_PrepareBeaconProposer(t *testing.T) {
 			val, tracked := proposerServer.TrackedValidatorsCache.Validator(1)
 			require.Equal(t, true, tracked)
 			require.Equal(t, primitives.ExecutionAddress(tt.args.request.Recipients[0].FeeRecipient), val.FeeRecipient)
-
+			wantRelays := tt.args.request.Recipients[0].Relays
+			if tt.wantRelays != nil {
+				wantRelays = tt.wantRelays
+			}
+			require.DeepEqual(t, wantRelays, val.Relays)
+			require.Equal(t, tt.args.request.Recipients[0].MinBid, val.MinBid)
 		})
 	}
 }
@@ -257,7 +257,7 @@ func (vs *Server) activationStatus(
 //
 // Spec:
 // https://github.com/ethereum/consensus-specs/blob/dev/sync/optimistic.md
-func (vs *Server) optimisticStatus(ctx context.Context) error {
+func (vs *Server) optimisticStatus(ctx context.Context, duty string) error {
 	if slots.ToEpoch(vs.TimeFetcher.CurrentSlot()) < params.BeaconConfig().BellatrixForkEpoch {
 		return nil
 	}
@@ -268,6 +268,9 @@ func (vs *Server) optimisticStatus(ctx context.Context) error {
 	if !optimistic {
 		return nil
 	}
+	if vs.OptimisticReporter != nil {
+		vs.OptimisticReporter.RecordBlockedDuty(duty)
+	}
 
 	return status.Errorf(codes.Unavailable, errOptimisticMode.Error())
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/d4l3k/messagediff"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain"
 	mockChain "github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain/testing"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/cache/depositsnapshot"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/helpers"
@@ -580,7 +581,7 @@ func TestActivationStatus_OK(t *testing.T) {
 func TestOptimisticStatus(t *testing.T) {
 	params.SetupTestConfigCleanup(t)
 	server := &Server{OptimisticModeFetcher: &mockChain.ChainService{}, TimeFetcher: &mockChain.ChainService{}}
-	err := server.optimisticStatus(context.Background())
+	err := server.optimisticStatus(context.Background(), blockchain.DutyBlockProposal)
 	require.NoError(t, err)
 
 	cfg := params.BeaconConfig().Copy()
@@ -588,14 +589,14 @@ func TestOptimisticStatus(t *testing.T) {
 	params.OverrideBeaconConfig(cfg)
 
 	server = &Server{OptimisticModeFetcher: &mockChain.ChainService{Optimistic: true}, TimeFetcher: &mockChain.ChainService{}}
-	err = server.optimisticStatus(context.Background())
+	err = server.optimisticStatus(context.Background(), blockchain.DutyBlockProposal)
 	s, ok := status.FromError(err)
 	require.Equal(t, true, ok)
 	require.DeepEqual(t, codes.Unavailable, s.Code())
 	require.ErrorContains(t, errOptimisticMode.Error(), err)
 
 	server = &Server{OptimisticModeFetcher: &mockChain.ChainService{Optimistic: false}, TimeFetcher: &mockChain.ChainService{}}
-	err = server.optimisticStatus(context.Background())
+	err = server.optimisticStatus(context.Background(), blockchain.DutyBlockProposal)
 	require.NoError(t, err)
 }
 
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/core"
 	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
 	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
@@ -18,7 +19,7 @@ func (vs *Server) GetSyncMessageBlockRoot(
 ) (*ethpb.SyncMessageBlockRootResponse, error) {
 	// An optimistic validator MUST NOT participate in sync committees
 	// (i.e., sign across the DOMAIN_SYNC_COMMITTEE, DOMAIN_SYNC_COMMITTEE_SELECTION_PROOF or DOMAIN_CONTRIBUTION_AND_PROOF domains).
-	if err := vs.optimisticStatus(ctx); err != nil {
+	if err := vs.optimisticStatus(ctx, blockchain.DutySyncCommitteeMessage); err != nil {
 		return nil, err
 	}
 
@@ -64,7 +65,7 @@ func (vs *Server) GetSyncCommitteeContribution(
 ) (*ethpb.SyncCommitteeContribution, error) {
 	// An optimistic validator MUST NOT participate in sync committees
 	// (i.e., sign across the DOMAIN_SYNC_COMMITTEE, DOMAIN_SYNC_COMMITTEE_SELECTION_PROOF or DOMAIN_CONTRIBUTION_AND_PROOF domains).
-	if err := vs.optimisticStatus(ctx); err != nil {
+	if err := vs.optimisticStatus(ctx, blockchain.DutySyncCommitteeContribution); err != nil {
 		return nil, err
 	}
 
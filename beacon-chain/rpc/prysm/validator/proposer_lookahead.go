@@ -0,0 +1,159 @@
+package validator
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/transition"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/eth/shared"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+)
+
+// maxProposerLookaheadEpochs is the largest number of epochs for which proposer duties can be requested at once.
+const maxProposerLookaheadEpochs = 32
+
+// Stability of the proposer duties of an epoch, from the point of view of the head state.
+const (
+	// The proposers of the current epoch only change if the chain reorgs.
+	lookaheadStable = "stable"
+	// The seed of the next epoch is fixed, but the effective balance updates and the activations and exits of
+	// the epoch transition can change its proposers.
+	lookaheadSeedFixed = "seed_fixed"
+	// The seed of later epochs depends on RANDAO reveals that have not been made yet. Their proposers are computed
+	// with the current RANDAO mix, and assuming no further balance, activation or exit changes.
+	lookaheadSpeculative = "speculative"
+)
+
+// GetProposerLookahead computes the proposer duties of a range of epochs starting at the current epoch or later.
+// Unlike the proposer duties of the Beacon API, which are limited to the current and next epoch, epochs further
+// ahead are supported by computing their duties speculatively. Each epoch reports how stable its duties are.
+func (s *Server) GetProposerLookahead(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "validator.GetProposerLookahead")
+	defer span.End()
+
+	_, start, ok := shared.UintFromQuery(w, r, "start_epoch", true)
+	if !ok {
+		return
+	}
+	rawEnd, end, ok := shared.UintFromQuery(w, r, "end_epoch", false)
+	if !ok {
+		return
+	}
+	if rawEnd == "" {
+		end = start
+	}
+	startEpoch, endEpoch := primitives.Epoch(start), primitives.Epoch(end)
+	currentEpoch := slots.ToEpoch(s.TimeFetcher.CurrentSlot())
+	if startEpoch < currentEpoch {
+		httputil.HandleError(w, fmt.Sprintf("Start epoch %d can not be before current epoch %d", startEpoch, currentEpoch), http.StatusBadRequest)
+		return
+	}
+	if endEpoch < startEpoch {
+		httputil.HandleError(w, fmt.Sprintf("End epoch %d can not be before start epoch %d", endEpoch, startEpoch), http.StatusBadRequest)
+		return
+	}
+	if endEpoch-startEpoch >= maxProposerLookaheadEpochs {
+		httputil.HandleError(w, fmt.Sprintf("Can not request more than %d epochs", maxProposerLookaheadEpochs), http.StatusBadRequest)
+		return
+	}
+
+	st, err := s.HeadFetcher.HeadState(ctx)
+	if err != nil {
+		httputil.HandleError(w, "Could not get head state: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	headRoot, err := s.HeadFetcher.HeadRoot(ctx)
+	if err != nil {
+		httputil.HandleError(w, "Could not get head root: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// Advance the state with empty slots to the current epoch, so that its epoch transitions are applied.
+	currentEpochStart, err := slots.EpochStart(currentEpoch)
+	if err != nil {
+		httputil.HandleError(w, fmt.Sprintf("Could not get start slot of epoch %d: %v", currentEpoch, err), http.StatusInternalServerError)
+		return
+	}
+	if st.Slot() < currentEpochStart {
+		st, err = transition.ProcessSlotsUsingNextSlotCache(ctx, st, headRoot, currentEpochStart)
+		if err != nil {
+			httputil.HandleError(w, fmt.Sprintf("Could not process slots up to %d: %v", currentEpochStart, err), http.StatusInternalServerError)
+			return
+		}
+	}
+	stateEpoch := slots.ToEpoch(st.Slot())
+	currentMix, err := helpers.RandaoMix(st, stateEpoch)
+	if err != nil {
+		httputil.HandleError(w, "Could not get RANDAO mix: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := make([]*structs.ProposerLookaheadEpoch, 0, endEpoch-startEpoch+1)
+	for epoch := startEpoch; epoch <= endEpoch; epoch++ {
+		e := &structs.ProposerLookaheadEpoch{Epoch: strconv.FormatUint(uint64(epoch), 10)}
+		mix := currentMix
+		switch {
+		case epoch == stateEpoch:
+			e.Stability, e.SeedFixed = lookaheadStable, true
+		case epoch == stateEpoch+1:
+			e.Stability, e.SeedFixed = lookaheadSeedFixed, true
+		default:
+			e.Stability, e.Speculative = lookaheadSpeculative, true
+		}
+		if e.SeedFixed {
+			mix, err = helpers.RandaoMix(st, epoch+params.BeaconConfig().EpochsPerHistoricalVector-params.BeaconConfig().MinSeedLookahead-1)
+			if err != nil {
+				httputil.HandleError(w, "Could not get RANDAO mix: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		proposers, err := helpers.PredictProposerIndices(st, epoch, mix)
+		if err != nil {
+			httputil.HandleError(w, fmt.Sprintf("Could not compute proposers of epoch %d: %v", epoch, err), http.StatusInternalServerError)
+			return
+		}
+		epochStart, err := slots.EpochStart(epoch)
+		if err != nil {
+			httputil.HandleError(w, fmt.Sprintf("Could not get start slot of epoch %d: %v", epoch, err), http.StatusInternalServerError)
+			return
+		}
+		e.Duties = make([]*structs.ProposerDuty, 0, len(proposers))
+		for i, idx := range proposers {
+			slot := epochStart + primitives.Slot(i)
+			// There is no proposer for the genesis slot.
+			if slot == 0 {
+				continue
+			}
+			val, err := st.ValidatorAtIndexReadOnly(idx)
+			if err != nil {
+				httputil.HandleError(w, fmt.Sprintf("Could not get validator at index %d: %v", idx, err), http.StatusInternalServerError)
+				return
+			}
+			pubkey := val.PublicKey()
+			e.Duties = append(e.Duties, &structs.ProposerDuty{
+				Pubkey:         hexutil.Encode(pubkey[:]),
+				ValidatorIndex: strconv.FormatUint(uint64(idx), 10),
+				Slot:           strconv.FormatUint(uint64(slot), 10),
+			})
+		}
+		data = append(data, e)
+	}
+
+	isOptimistic, err := s.OptimisticModeFetcher.IsOptimistic(ctx)
+	if err != nil {
+		httputil.HandleError(w, "Could not check optimistic status: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	httputil.WriteJson(w, &structs.GetProposerLookaheadResponse{
+		HeadRoot:            hexutil.Encode(headRoot),
+		ExecutionOptimistic: isOptimistic,
+		Data:                data,
+	})
+}
@@ -0,0 +1,111 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	mock "github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/transition"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+func TestGetProposerLookahead(t *testing.T) {
+	helpers.ClearCache()
+	ctx := context.Background()
+	st, _ := util.DeterministicGenesisState(t, 64)
+	slot := primitives.Slot(0)
+	chainService := &mock.ChainService{State: st, Root: make([]byte, 32), Slot: &slot}
+	s := &Server{
+		HeadFetcher:           chainService,
+		TimeFetcher:           chainService,
+		OptimisticModeFetcher: chainService,
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "http://example.com/prysm/v1/validators/proposer_lookahead?start_epoch=0&end_epoch=2", nil)
+	writer := httptest.NewRecorder()
+	writer.Body = &bytes.Buffer{}
+
+	s.GetProposerLookahead(writer, request)
+	require.Equal(t, http.StatusOK, writer.Code)
+	resp := &structs.GetProposerLookaheadResponse{}
+	require.NoError(t, json.Unmarshal(writer.Body.Bytes(), resp))
+	require.Equal(t, 3, len(resp.Data))
+
+	assert.Equal(t, "0", resp.Data[0].Epoch)
+	assert.Equal(t, lookaheadStable, resp.Data[0].Stability)
+	assert.Equal(t, true, resp.Data[0].SeedFixed)
+	assert.Equal(t, false, resp.Data[0].Speculative)
+	assert.Equal(t, lookaheadSeedFixed, resp.Data[1].Stability)
+	assert.Equal(t, true, resp.Data[1].SeedFixed)
+	assert.Equal(t, lookaheadSpeculative, resp.Data[2].Stability)
+	assert.Equal(t, false, resp.Data[2].SeedFixed)
+	assert.Equal(t, true, resp.Data[2].Speculative)
+
+	// There is no proposer for the genesis slot.
+	require.Equal(t, int(params.BeaconConfig().SlotsPerEpoch)-1, len(resp.Data[0].Duties))
+	require.Equal(t, int(params.BeaconConfig().SlotsPerEpoch), len(resp.Data[1].Duties))
+	require.Equal(t, int(params.BeaconConfig().SlotsPerEpoch), len(resp.Data[2].Duties))
+
+	// The duties of the epochs with a fixed seed are the ones computed once the epoch is reached.
+	for _, d := range resp.Data[0].Duties {
+		ds, err := strconv.ParseUint(d.Slot, 10, 64)
+		require.NoError(t, err)
+		idx, err := helpers.BeaconProposerIndexAtSlot(ctx, st, primitives.Slot(ds))
+		require.NoError(t, err)
+		assert.Equal(t, strconv.FormatUint(uint64(idx), 10), d.ValidatorIndex)
+	}
+	next, err := transition.ProcessSlots(ctx, st.Copy(), params.BeaconConfig().SlotsPerEpoch)
+	require.NoError(t, err)
+	for _, d := range resp.Data[1].Duties {
+		ds, err := strconv.ParseUint(d.Slot, 10, 64)
+		require.NoError(t, err)
+		idx, err := helpers.BeaconProposerIndexAtSlot(ctx, next, primitives.Slot(ds))
+		require.NoError(t, err)
+		assert.Equal(t, strconv.FormatUint(uint64(idx), 10), d.ValidatorIndex)
+	}
+}
+
+func TestGetProposerLookahead_InvalidRange(t *testing.T) {
+	slot := params.BeaconConfig().SlotsPerEpoch * 10
+	chainService := &mock.ChainService{Slot: &slot}
+	s := &Server{
+		HeadFetcher:           chainService,
+		TimeFetcher:           chainService,
+		OptimisticModeFetcher: chainService,
+	}
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "missing start epoch", query: "end_epoch=12"},
+		{name: "past start epoch", query: "start_epoch=9"},
+		{name: "end before start", query: "start_epoch=12&end_epoch=11"},
+		{name: "too many epochs", query: "start_epoch=10&end_epoch=42"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "http://example.com/prysm/v1/validators/proposer_lookahead?"+tt.query, nil)
+			writer := httptest.NewRecorder()
+			writer.Body = &bytes.Buffer{}
+
+			s.GetProposerLookahead(writer, request)
+			require.Equal(t, http.StatusBadRequest, writer.Code)
+			e := &httputil.DefaultJsonError{}
+			require.NoError(t, json.Unmarshal(writer.Body.Bytes(), e))
+			assert.Equal(t, http.StatusBadRequest, e.Code)
+		})
+	}
+}
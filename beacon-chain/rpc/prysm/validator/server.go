@@ -8,10 +8,13 @@ import (
 )
 
 type Server struct {
-	BeaconDB            db.ReadOnlyDatabase
-	Stater              lookup.Stater
-	CanonicalFetcher    blockchain.CanonicalFetcher
-	FinalizationFetcher blockchain.FinalizationFetcher
-	ChainInfoFetcher    blockchain.ChainInfoFetcher
-	CoreService         *core.Service
+	BeaconDB              db.ReadOnlyDatabase
+	Stater                lookup.Stater
+	CanonicalFetcher      blockchain.CanonicalFetcher
+	FinalizationFetcher   blockchain.FinalizationFetcher
+	ChainInfoFetcher      blockchain.ChainInfoFetcher
+	HeadFetcher           blockchain.HeadFetcher
+	TimeFetcher           blockchain.TimeFetcher
+	OptimisticModeFetcher blockchain.OptimisticModeFetcher
+	CoreService           *core.Service
 }
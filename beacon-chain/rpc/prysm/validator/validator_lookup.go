@@ -0,0 +1,132 @@
+package validator
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/eth/shared"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
+	"github.com/prysmaticlabs/prysm/v5/proto/dbval"
+)
+
+// LookupValidators looks up validators in the historical validator identity index of the database, without loading
+// a state. Exactly one of the `withdrawal_address`, `pubkey` and `index` query parameters is required. A withdrawal
+// address matches every validator which has or had execution withdrawal credentials with that address.
+// The index is only maintained with the --enable-validator-identity-index flag, and is updated on finalization. It is
+// not backfilled: the response reports the first indexed epoch as `history_start_epoch`, and withdrawal credentials
+// changed before that epoch are missing from the history.
+func (s *Server) LookupValidators(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "validator.LookupValidators")
+	defer span.End()
+
+	query := r.URL.Query()
+	rawAddress, rawPubkey, rawIndex := query.Get("withdrawal_address"), query.Get("pubkey"), query.Get("index")
+	set := 0
+	for _, v := range []string{rawAddress, rawPubkey, rawIndex} {
+		if v != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		httputil.HandleError(w, "Exactly one of withdrawal_address, pubkey and index is required", http.StatusBadRequest)
+		return
+	}
+
+	indexedEpoch, err := s.BeaconDB.ValidatorIdentityIndexEpoch(ctx)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			httputil.HandleError(w, "Validator identity index is empty, it is maintained with --enable-validator-identity-index and updated on finalization", http.StatusServiceUnavailable)
+			return
+		}
+		httputil.HandleError(w, "Could not get validator identity index epoch: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	startEpoch, err := s.BeaconDB.ValidatorIdentityIndexStartEpoch(ctx)
+	if err != nil {
+		httputil.HandleError(w, "Could not get validator identity index start epoch: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var ids []*dbval.ValidatorIdentity
+	switch {
+	case rawAddress != "":
+		address, valid := shared.ValidateHex(w, "withdrawal_address", rawAddress, common.AddressLength)
+		if !valid {
+			return
+		}
+		ids, err = s.BeaconDB.ValidatorIdentitiesByWithdrawalAddress(ctx, common.BytesToAddress(address))
+		if err != nil {
+			httputil.HandleError(w, "Could not look up validators: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	case rawPubkey != "":
+		pubkey, valid := shared.ValidateHex(w, "pubkey", rawPubkey, fieldparams.BLSPubkeyLength)
+		if !valid {
+			return
+		}
+		id, err := s.BeaconDB.ValidatorIdentityByPublicKey(ctx, bytesutil.ToBytes48(pubkey))
+		if !writeLookupError(w, err) {
+			return
+		}
+		ids = append(ids, id)
+	default:
+		_, index, valid := shared.UintFromQuery(w, r, "index", true)
+		if !valid {
+			return
+		}
+		id, err := s.BeaconDB.ValidatorIdentity(ctx, primitives.ValidatorIndex(index))
+		if !writeLookupError(w, err) {
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	resp := &structs.LookupValidatorsResponse{
+		IndexedEpoch:      strconv.FormatUint(uint64(indexedEpoch), 10),
+		HistoryStartEpoch: strconv.FormatUint(uint64(startEpoch), 10),
+		Data:              make([]*structs.ValidatorIdentity, len(ids)),
+	}
+	for i, id := range ids {
+		resp.Data[i] = validatorIdentityFromDB(id)
+	}
+	httputil.WriteJson(w, resp)
+}
+
+// writeLookupError writes the HTTP error of a validator identity lookup, and returns false if there was an error.
+func writeLookupError(w http.ResponseWriter, err error) bool {
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, db.ErrNotFound) {
+		httputil.HandleError(w, "Validator not found in the validator identity index", http.StatusNotFound)
+		return false
+	}
+	httputil.HandleError(w, "Could not look up validator: "+err.Error(), http.StatusInternalServerError)
+	return false
+}
+
+func validatorIdentityFromDB(id *dbval.ValidatorIdentity) *structs.ValidatorIdentity {
+	creds := make([]*structs.WithdrawalCredentialsChange, len(id.WithdrawalCredentials))
+	for i, c := range id.WithdrawalCredentials {
+		creds[i] = &structs.WithdrawalCredentialsChange{
+			Epoch:                 strconv.FormatUint(c.Epoch, 10),
+			WithdrawalCredentials: hexutil.Encode(c.WithdrawalCredentials),
+		}
+	}
+	return &structs.ValidatorIdentity{
+		Index:                 strconv.FormatUint(id.Index, 10),
+		Pubkey:                hexutil.Encode(id.PublicKey),
+		ActivationEpoch:       strconv.FormatUint(id.ActivationEpoch, 10),
+		ExitEpoch:             strconv.FormatUint(id.ExitEpoch, 10),
+		WithdrawalCredentials: creds,
+	}
+}
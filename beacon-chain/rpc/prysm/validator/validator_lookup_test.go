@@ -0,0 +1,77 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	dbTest "github.com/prysmaticlabs/prysm/v5/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+func TestLookupValidators(t *testing.T) {
+	beaconDB := dbTest.SetupDB(t)
+	s := &Server{BeaconDB: beaconDB}
+	lookup := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/prysm/v1/validators/lookup?"+query, nil)
+		rr := httptest.NewRecorder()
+		s.LookupValidators(rr, req)
+		return rr
+	}
+
+	rr := lookup("index=1")
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+
+	st, _ := util.DeterministicGenesisState(t, 4)
+	creds := make([]byte, 32)
+	creds[0] = params.BeaconConfig().ETH1AddressWithdrawalPrefixByte
+	creds[31] = 0xaa
+	val, err := st.ValidatorAtIndex(2)
+	require.NoError(t, err)
+	val.WithdrawalCredentials = creds
+	require.NoError(t, st.UpdateValidatorAtIndex(2, val))
+	require.NoError(t, st.SetSlot(params.BeaconConfig().SlotsPerEpoch))
+	require.NoError(t, beaconDB.SaveValidatorIdentities(context.Background(), st))
+
+	rr = lookup("withdrawal_address=" + hexutil.Encode(creds[12:]))
+	require.Equal(t, http.StatusOK, rr.Code)
+	resp := &structs.LookupValidatorsResponse{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), resp))
+	assert.Equal(t, "1", resp.IndexedEpoch)
+	assert.Equal(t, "1", resp.HistoryStartEpoch)
+	require.Equal(t, 1, len(resp.Data))
+	assert.Equal(t, "2", resp.Data[0].Index)
+	assert.Equal(t, hexutil.Encode(val.PublicKey), resp.Data[0].Pubkey)
+	require.Equal(t, 1, len(resp.Data[0].WithdrawalCredentials))
+	assert.Equal(t, hexutil.Encode(creds), resp.Data[0].WithdrawalCredentials[0].WithdrawalCredentials)
+
+	rr = lookup("pubkey=" + hexutil.Encode(val.PublicKey))
+	require.Equal(t, http.StatusOK, rr.Code)
+	resp = &structs.LookupValidatorsResponse{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), resp))
+	require.Equal(t, 1, len(resp.Data))
+	assert.Equal(t, "2", resp.Data[0].Index)
+
+	rr = lookup("index=3")
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	tests := []struct {
+		query string
+		code  int
+	}{
+		{query: "", code: http.StatusBadRequest},
+		{query: "index=1&pubkey=0x01", code: http.StatusBadRequest},
+		{query: "withdrawal_address=0x01", code: http.StatusBadRequest},
+		{query: "index=10", code: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.code, lookup(tt.query).Code, tt.query)
+	}
+}
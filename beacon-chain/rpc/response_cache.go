@@ -0,0 +1,269 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dgraph-io/ristretto"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api"
+	"github.com/prysmaticlabs/prysm/v5/api/server/middleware"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/lookup"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+	"golang.org/x/sync/singleflight"
+)
+
+// cacheBy identifies the finalized state an endpoint computes its response from.
+type cacheBy int
+
+const (
+	// noCache endpoints are not cached.
+	noCache cacheBy = iota
+	// cacheByStateId endpoints are cached when their `state_id` is the genesis state, the finalized state, or the
+	// slot of a finalized state. State roots are not resolved and bypass the cache.
+	cacheByStateId
+	// cacheByBlockId endpoints are cached when their `block_id` is a finalized block.
+	cacheByBlockId
+	// cacheByEpoch endpoints compute their response from the state at the end of the epoch after their `epoch`,
+	// and are cached when that state is finalized.
+	cacheByEpoch
+)
+
+// cachedResponse is a response recorded by a responseRecorder.
+type cachedResponse struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+func (c *cachedResponse) write(w http.ResponseWriter) {
+	for k, v := range c.header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(c.status)
+	if _, err := w.Write(c.body); err != nil {
+		log.WithError(err).Debug("Could not write cached response")
+	}
+}
+
+// responseRecorder records the response of a handler.
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+// responseStatus holds the metadata fields of a JSON response that depend on the chain at the time of the response.
+type responseStatus struct {
+	ExecutionOptimistic *bool `json:"execution_optimistic"`
+	Finalized           *bool `json:"finalized"`
+}
+
+// responseCache caches the responses of expensive endpoints computed from finalized states, which never change.
+// Responses are keyed by route, finalized state, Accept header and query, and the cache is bounded by the size of
+// the responses. Concurrent identical requests are served by a single call to the handler, unless it fails.
+type responseCache struct {
+	cache        *ristretto.Cache
+	group        singleflight.Group
+	stater       lookup.Stater
+	blocker      lookup.Blocker
+	finalization blockchain.FinalizationFetcher
+	optimistic   blockchain.OptimisticModeFetcher
+}
+
+func newResponseCache(
+	maxBytes int64,
+	stater lookup.Stater,
+	blocker lookup.Blocker,
+	finalization blockchain.FinalizationFetcher,
+	optimistic blockchain.OptimisticModeFetcher,
+) (*responseCache, error) {
+	c, err := ristretto.NewCache(&ristretto.Config{
+		NumCounters: 100_000, // number of keys to track frequency of.
+		MaxCost:     maxBytes,
+		BufferItems: 64, // number of keys per Get buffer.
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create response cache")
+	}
+	return &responseCache{
+		cache:        c,
+		stater:       stater,
+		blocker:      blocker,
+		finalization: finalization,
+		optimistic:   optimistic,
+	}, nil
+}
+
+// handler returns a middleware caching the responses of the endpoint with the given route. The middleware must be
+// wrapped by the Accept and Content-Type checks of the endpoint, so that cached responses are only served to
+// requests that pass them.
+func (c *responseCache) handler(route string, by cacheBy) middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			finalizedId, ok := c.finalizedId(r, by)
+			if !ok {
+				responseCacheRequests.WithLabelValues(route, "bypass").Inc()
+				next.ServeHTTP(w, r)
+				return
+			}
+			key, err := requestKey(r, route, finalizedId)
+			if err != nil {
+				httputil.HandleError(w, "Could not read request body: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			if v, ok := c.cache.Get(key); ok {
+				responseCacheRequests.WithLabelValues(route, "hit").Inc()
+				v.(*cachedResponse).write(w)
+				return
+			}
+			// The handler runs with a context detached from the cancellation of the request which happens to run
+			// it, so that the other requests waiting for the response don't fail if that client disconnects.
+			ran := false
+			v, _, _ := c.group.Do(key, func() (interface{}, error) {
+				ran = true
+				dr := r.WithContext(context.WithoutCancel(r.Context()))
+				rec := &responseRecorder{header: make(http.Header)}
+				next.ServeHTTP(rec, dr)
+				resp := &cachedResponse{status: rec.status, header: rec.header, body: rec.body.Bytes()}
+				if c.cacheable(dr, resp) {
+					c.cache.Set(key, resp, int64(len(resp.body)))
+				}
+				return resp, nil
+			})
+			switch {
+			case ran:
+				responseCacheRequests.WithLabelValues(route, "miss").Inc()
+			case v.(*cachedResponse).status != http.StatusOK:
+				// An error may be specific to the request that ran the handler, so it is not shared.
+				responseCacheRequests.WithLabelValues(route, "bypass").Inc()
+				next.ServeHTTP(w, r)
+				return
+			default:
+				responseCacheRequests.WithLabelValues(route, "shared").Inc()
+			}
+			v.(*cachedResponse).write(w)
+		})
+	}
+}
+
+// cacheable reports whether the response can be served to later requests. The `execution_optimistic` and
+// `finalized` fields of a response are frozen when it is cached, so only successful responses that were finalized
+// and not optimistic are cached. SSZ responses don't carry these fields, so none are cached while the node is
+// optimistic.
+func (c *responseCache) cacheable(r *http.Request, resp *cachedResponse) bool {
+	if resp.status != http.StatusOK {
+		return false
+	}
+	if resp.header.Get(api.ExecutionOptimisticHeader) == "true" || resp.header.Get(api.FinalizedHeader) == "false" {
+		return false
+	}
+	optimistic, err := c.optimistic.IsOptimistic(r.Context())
+	if err != nil || optimistic {
+		return false
+	}
+	if !strings.HasPrefix(resp.header.Get("Content-Type"), api.JsonMediaType) {
+		return true
+	}
+	status := &responseStatus{}
+	if err := json.Unmarshal(resp.body, status); err != nil {
+		return false
+	}
+	if status.ExecutionOptimistic != nil && *status.ExecutionOptimistic {
+		return false
+	}
+	return status.Finalized == nil || *status.Finalized
+}
+
+// finalizedId returns an identifier of the finalized state the response to the request is computed from, or
+// false if the state is not finalized or cannot be identified cheaply.
+func (c *responseCache) finalizedId(r *http.Request, by cacheBy) (string, bool) {
+	finalizedEpoch := c.finalization.FinalizedCheckpt().Epoch
+	finalizedSlot, err := slots.EpochStart(finalizedEpoch)
+	if err != nil {
+		return "", false
+	}
+	switch by {
+	case cacheByStateId:
+		id := strings.ToLower(r.PathValue("state_id"))
+		switch id {
+		case "genesis":
+			return "slot 0", true
+		case "finalized":
+			// The finalized state is the state at the start slot of the finalized epoch.
+			return fmt.Sprintf("slot %d", finalizedSlot), true
+		}
+		slot, err := strconv.ParseUint(id, 10, 64)
+		if err != nil || primitives.Slot(slot) > finalizedSlot {
+			return "", false
+		}
+		return fmt.Sprintf("slot %d", slot), true
+	case cacheByBlockId:
+		id := r.PathValue("block_id")
+		if strings.ToLower(id) == "head" {
+			return "", false
+		}
+		blk, err := c.blocker.Block(r.Context(), []byte(id))
+		if err != nil || blk == nil || blk.IsNil() {
+			return "", false
+		}
+		root, err := blk.Block().HashTreeRoot()
+		if err != nil || !c.finalization.IsFinalized(r.Context(), root) {
+			return "", false
+		}
+		stateRoot := blk.Block().StateRoot()
+		return fmt.Sprintf("state %#x", stateRoot), true
+	case cacheByEpoch:
+		epoch, err := strconv.ParseUint(r.PathValue("epoch"), 10, 64)
+		if err != nil || primitives.Epoch(epoch)+2 > finalizedEpoch {
+			return "", false
+		}
+		return fmt.Sprintf("epoch %d", epoch), true
+	default:
+		return "", false
+	}
+}
+
+// requestKey returns the cache key of the request. The body of the request is read and restored so that
+// it can be read again by the handler.
+func requestKey(r *http.Request, route, finalizedId string) (string, error) {
+	accept := strings.Join(r.Header.Values("Accept"), ",")
+	var body []byte
+	if r.Body != nil && r.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(r.Body)
+		if err != nil {
+			return "", err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	// Encoding the query sorts it by key.
+	return fmt.Sprintf("%s %s %s %q %s %#x", r.Method, route, finalizedId, accept, r.URL.Query().Encode(), sha256.Sum256(body)), nil
+}
@@ -0,0 +1,280 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/v5/api"
+	mock "github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/testutil"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	eth "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+func testResponseHandler(t *testing.T, calls *atomic.Int32, release <-chan struct{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if release != nil {
+			<-release
+		}
+		w.Header().Set("Content-Type", api.JsonMediaType)
+		if r.URL.Query().Get("fail") != "" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		resp := fmt.Sprintf(
+			`{"execution_optimistic":%t,"finalized":%t,"data":"response %s"}`,
+			r.URL.Query().Get("optimistic") != "", r.URL.Query().Get("unfinalized") == "", r.URL.RawQuery,
+		)
+		_, err := w.Write([]byte(resp))
+		require.NoError(t, err)
+	}
+}
+
+func testResponseCacheMux(t *testing.T, c *responseCache, template string, by cacheBy, calls *atomic.Int32, release <-chan struct{}) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle(template, c.handler(template, by)(testResponseHandler(t, calls, release)))
+	return mux
+}
+
+func serve(mux *http.ServeMux, method, url, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, url, bytes.NewBufferString(body))
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestResponseCache_StateId(t *testing.T) {
+	chain := &mock.ChainService{FinalizedCheckPoint: &eth.Checkpoint{Epoch: 2}}
+	c, err := newResponseCache(1<<20, nil, nil, chain, chain)
+	require.NoError(t, err)
+	calls := &atomic.Int32{}
+	mux := testResponseCacheMux(t, c, "/eth/v1/beacon/states/{state_id}/committees", cacheByStateId, calls, nil)
+
+	tests := []struct {
+		url    string
+		calls  int32
+		cached bool
+	}{
+		{url: "/eth/v1/beacon/states/head/committees", calls: 1},
+		{url: "/eth/v1/beacon/states/head/committees", calls: 2},
+		{url: "/eth/v1/beacon/states/100/committees", calls: 3},
+		{url: "/eth/v1/beacon/states/64/committees", calls: 4},
+		{url: "/eth/v1/beacon/states/64/committees", calls: 4, cached: true},
+		// The finalized state is the state at slot 64.
+		{url: "/eth/v1/beacon/states/finalized/committees", calls: 4, cached: true},
+		{url: "/eth/v1/beacon/states/64/committees?epoch=1", calls: 5},
+		{url: "/eth/v1/beacon/states/64/committees?epoch=1", calls: 5, cached: true},
+		{url: "/eth/v1/beacon/states/genesis/committees?fail=1", calls: 6},
+		{url: "/eth/v1/beacon/states/genesis/committees?fail=1", calls: 7},
+		// Responses that were optimistic or not finalized when computed are not cached.
+		{url: "/eth/v1/beacon/states/64/committees?optimistic=1", calls: 8},
+		{url: "/eth/v1/beacon/states/64/committees?optimistic=1", calls: 9},
+		{url: "/eth/v1/beacon/states/64/committees?unfinalized=1", calls: 10},
+		{url: "/eth/v1/beacon/states/64/committees?unfinalized=1", calls: 11},
+	}
+	for _, tt := range tests {
+		rr := serve(mux, http.MethodGet, tt.url, "")
+		c.cache.Wait()
+		assert.Equal(t, tt.calls, calls.Load(), tt.url)
+		if tt.cached {
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, api.JsonMediaType, rr.Header().Get("Content-Type"))
+			assert.Equal(t, true, strings.Contains(rr.Body.String(), `"data":"response`))
+		}
+	}
+}
+
+func TestResponseCache_BlockIdAndBody(t *testing.T) {
+	b := util.NewBeaconBlock()
+	b.Block.Slot = 10
+	blk, err := blocks.NewSignedBeaconBlock(b)
+	require.NoError(t, err)
+	root, err := blk.Block().HashTreeRoot()
+	require.NoError(t, err)
+	chain := &mock.ChainService{FinalizedCheckPoint: &eth.Checkpoint{Epoch: 2}, FinalizedRoots: map[[32]byte]bool{}}
+	c, err := newResponseCache(1<<20, nil, &testutil.MockBlocker{BlockToReturn: blk}, chain, chain)
+	require.NoError(t, err)
+	calls := &atomic.Int32{}
+	mux := testResponseCacheMux(t, c, "/eth/v1/beacon/rewards/sync_committee/{block_id}", cacheByBlockId, calls, nil)
+
+	serve(mux, http.MethodPost, "/eth/v1/beacon/rewards/sync_committee/10", `["1"]`)
+	serve(mux, http.MethodPost, "/eth/v1/beacon/rewards/sync_committee/10", `["1"]`)
+	c.cache.Wait()
+	assert.Equal(t, int32(2), calls.Load())
+
+	chain.FinalizedRoots[root] = true
+	serve(mux, http.MethodPost, "/eth/v1/beacon/rewards/sync_committee/10", `["1"]`)
+	c.cache.Wait()
+	serve(mux, http.MethodPost, "/eth/v1/beacon/rewards/sync_committee/10", `["1"]`)
+	assert.Equal(t, int32(3), calls.Load())
+	serve(mux, http.MethodPost, "/eth/v1/beacon/rewards/sync_committee/10", `["2"]`)
+	assert.Equal(t, int32(4), calls.Load())
+	// An SSZ request is cached separately from a JSON request.
+	req := httptest.NewRequest(http.MethodPost, "/eth/v1/beacon/rewards/sync_committee/10", bytes.NewBufferString(`["1"]`))
+	req.Header.Set("Accept", api.OctetStreamMediaType)
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, int32(5), calls.Load())
+	// Nothing is cached while the node is optimistic.
+	chain.Optimistic = true
+	serve(mux, http.MethodPost, "/eth/v1/beacon/rewards/sync_committee/10", `["3"]`)
+	c.cache.Wait()
+	serve(mux, http.MethodPost, "/eth/v1/beacon/rewards/sync_committee/10", `["3"]`)
+	assert.Equal(t, int32(7), calls.Load())
+}
+
+func TestResponseCache_Epoch(t *testing.T) {
+	chain := &mock.ChainService{FinalizedCheckPoint: &eth.Checkpoint{Epoch: primitives.Epoch(10)}}
+	c, err := newResponseCache(1<<20, nil, nil, chain, chain)
+	require.NoError(t, err)
+	calls := &atomic.Int32{}
+	mux := testResponseCacheMux(t, c, "/eth/v1/beacon/rewards/attestations/{epoch}", cacheByEpoch, calls, nil)
+
+	for i := 0; i < 2; i++ {
+		serve(mux, http.MethodPost, "/eth/v1/beacon/rewards/attestations/8", "")
+		c.cache.Wait()
+		serve(mux, http.MethodPost, "/eth/v1/beacon/rewards/attestations/9", "")
+	}
+	// Rewards of epoch 8 are computed from the finalized state at the end of epoch 9, those of epoch 9 are not.
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestResponseCache_SingleFlight(t *testing.T) {
+	chain := &mock.ChainService{FinalizedCheckPoint: &eth.Checkpoint{Epoch: 2}}
+	c, err := newResponseCache(1<<20, nil, nil, chain, chain)
+	require.NoError(t, err)
+	calls := &atomic.Int32{}
+	release := make(chan struct{})
+	mux := testResponseCacheMux(t, c, "/eth/v1/beacon/states/{state_id}/committees", cacheByStateId, calls, release)
+
+	const n = 10
+	var wg sync.WaitGroup
+	codes := make([]int, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			codes[i] = serve(mux, http.MethodGet, "/eth/v1/beacon/states/finalized/committees", "").Code
+		}(i)
+	}
+	// Give all requests time to reach the cache before releasing the handler.
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	for _, code := range codes {
+		assert.Equal(t, http.StatusOK, code)
+	}
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestResponseCache_SingleFlightCanceled(t *testing.T) {
+	chain := &mock.ChainService{FinalizedCheckPoint: &eth.Checkpoint{Epoch: 2}}
+	c, err := newResponseCache(1<<20, nil, nil, chain, chain)
+	require.NoError(t, err)
+	calls := &atomic.Int32{}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	// The handler fails if the request it runs for is canceled, or with the `fail` query parameter for the
+	// request running it first.
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			close(started)
+			<-release
+		}
+		if r.Context().Err() != nil || (calls.Load() == 1 && r.URL.Query().Get("fail") != "") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", api.JsonMediaType)
+		_, err := w.Write([]byte(`{"finalized":true,"data":"response"}`))
+		require.NoError(t, err)
+	})
+	const template = "/eth/v1/beacon/states/{state_id}/committees"
+	mux := http.NewServeMux()
+	mux.Handle(template, c.handler(template, cacheByStateId)(handler))
+
+	for _, query := range []string{"", "?fail=1"} {
+		t.Run(query, func(t *testing.T) {
+			calls.Store(0)
+			started = make(chan struct{})
+			release = make(chan struct{})
+			c.cache.Clear()
+
+			// The client of the request running the handler disconnects while another request waits for it.
+			ctx, cancel := context.WithCancel(context.Background())
+			first := httptest.NewRequest(http.MethodGet, "/eth/v1/beacon/states/finalized/committees"+query, nil).WithContext(ctx)
+			done := make(chan struct{})
+			go func() {
+				mux.ServeHTTP(httptest.NewRecorder(), first)
+				close(done)
+			}()
+			<-started
+			var code int
+			waited := make(chan struct{})
+			go func() {
+				code = serve(mux, http.MethodGet, "/eth/v1/beacon/states/finalized/committees"+query, "").Code
+				close(waited)
+			}()
+			time.Sleep(100 * time.Millisecond)
+			cancel()
+			close(release)
+			<-done
+			<-waited
+			assert.Equal(t, http.StatusOK, code)
+		})
+	}
+}
+
+func TestResponseCache_InsideHeaderChecks(t *testing.T) {
+	chain := &mock.ChainService{FinalizedCheckPoint: &eth.Checkpoint{Epoch: 2}}
+	c, err := newResponseCache(1<<20, nil, nil, chain, chain)
+	require.NoError(t, err)
+	s := &Service{cfg: &Config{}, responseCache: c}
+	var e *endpoint
+	endpoints := s.endpoints(false, nil, nil, nil, nil, nil, nil)
+	for i := range endpoints {
+		if endpoints[i].template == "/eth/v1/beacon/states/{state_id}/validators" {
+			e = &endpoints[i]
+		}
+	}
+	require.NotNil(t, e)
+	calls := &atomic.Int32{}
+	e.handler = testResponseHandler(t, calls, nil)
+	mux := http.NewServeMux()
+	mux.HandleFunc(e.template, e.handlerWithMiddleware())
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/eth/v1/beacon/states/genesis/validators", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", api.JsonMediaType)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		c.cache.Wait()
+		assert.Equal(t, http.StatusOK, rr.Code)
+	}
+	assert.Equal(t, int32(1), calls.Load())
+	// A cached response is not served to requests failing the Content-Type or Accept checks.
+	req := httptest.NewRequest(http.MethodPost, "/eth/v1/beacon/states/genesis/validators", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "text/plain")
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+	req = httptest.NewRequest(http.MethodPost, "/eth/v1/beacon/states/genesis/validators", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", api.JsonMediaType)
+	req.Header.Set("Accept", api.OctetStreamMediaType)
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotAcceptable, rr.Code)
+	assert.Equal(t, int32(1), calls.Load())
+}
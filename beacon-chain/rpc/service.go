@@ -14,6 +14,7 @@ import (
 	grpcopentracing "github.com/grpc-ecosystem/go-grpc-middleware/tracing/opentracing"
 	grpcprometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"github.com/pkg/errors"
+	httpmiddleware "github.com/prysmaticlabs/prysm/v5/api/server/middleware"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/builder"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/cache"
@@ -40,6 +41,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/startup"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state/stategen"
 	chainSync "github.com/prysmaticlabs/prysm/v5/beacon-chain/sync"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/sync/checkpoint"
 	"github.com/prysmaticlabs/prysm/v5/config/features"
 	"github.com/prysmaticlabs/prysm/v5/config/params"
 	"github.com/prysmaticlabs/prysm/v5/io/logs"
@@ -67,6 +69,7 @@ type Service struct {
 	connectedRPCClients  map[net.Addr]bool
 	clientConnectionLock sync.Mutex
 	validatorServer      *validatorv1alpha1.Server
+	responseCache        *responseCache
 }
 
 // Config options for the beacon node RPC server.
@@ -115,10 +118,14 @@ type Config struct {
 	MaxMsgSize                int
 	ExecutionEngineCaller     execution.EngineCaller
 	OptimisticModeFetcher     blockchain.OptimisticModeFetcher
+	OptimisticReporter        blockchain.OptimisticReporter
 	BlockBuilder              builder.BlockBuilder
 	Router                    *http.ServeMux
+	Authenticator             *httpmiddleware.Authenticator
+	ResponseCacheSize         int64
 	ClockWaiter               startup.ClockWaiter
 	BlobStorage               *filesystem.BlobStorage
+	CheckpointCache           *checkpoint.Cache
 	TrackedValidatorsCache    *cache.TrackedValidatorsCache
 	PayloadIDCache            *cache.PayloadIDCache
 }
@@ -210,6 +217,7 @@ func NewService(ctx context.Context, cfg *Config) *Service {
 		FinalizedFetcher:      s.cfg.FinalizationFetcher,
 		ReplayerBuilder:       ch,
 		OptimisticModeFetcher: s.cfg.OptimisticModeFetcher,
+		OptimisticReporter:    s.cfg.OptimisticReporter,
 	}
 	validatorServer := &validatorv1alpha1.Server{
 		Ctx:                    s.ctx,
@@ -227,6 +235,7 @@ func NewService(ctx context.Context, cfg *Config) *Service {
 		ChainStartFetcher:      s.cfg.ChainStartFetcher,
 		Eth1InfoFetcher:        s.cfg.ExecutionChainService,
 		OptimisticModeFetcher:  s.cfg.OptimisticModeFetcher,
+		OptimisticReporter:     s.cfg.OptimisticReporter,
 		SyncChecker:            s.cfg.SyncService,
 		StateNotifier:          s.cfg.StateNotifier,
 		BlockNotifier:          s.cfg.BlockNotifier,
@@ -291,6 +300,14 @@ func NewService(ctx context.Context, cfg *Config) *Service {
 		CoreService:                 coreService,
 	}
 
+	if s.cfg.ResponseCacheSize > 0 {
+		rc, err := newResponseCache(s.cfg.ResponseCacheSize, stater, blocker, s.cfg.FinalizationFetcher, s.cfg.OptimisticModeFetcher)
+		if err != nil {
+			log.WithError(err).Error("Could not create HTTP response cache")
+		} else {
+			s.responseCache = rc
+		}
+	}
 	endpoints := s.endpoints(s.cfg.EnableDebugRPCEndpoints, blocker, stater, rewardFetcher, validatorServer, coreService, ch)
 	for _, e := range endpoints {
 		for i := range e.methods {
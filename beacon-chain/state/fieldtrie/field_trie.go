@@ -267,6 +267,12 @@ func (f *FieldTrie) Empty() bool {
 	return f == nil || len(f.fieldLayers) == 0 || f.isTransferred
 }
 
+// FieldLayers returns the layers of the trie, the leaves first. The layers are shared with the trie,
+// so they must not be modified and the trie must be read locked while they are in use.
+func (f *FieldTrie) FieldLayers() [][]*[32]byte {
+	return f.fieldLayers
+}
+
 // InsertFieldLayer manually inserts a field layer. This method
 // bypasses the normal method of field computation, it is only
 // meant to be used in tests.
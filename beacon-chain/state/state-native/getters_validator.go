@@ -385,6 +385,10 @@ func (b *BeaconState) BalancesLength() int {
 	b.lock.RLock()
 	defer b.lock.RUnlock()
 
+	return b.balancesLen()
+}
+
+func (b *BeaconState) balancesLen() int {
 	if features.Get().EnableExperimentalState {
 		if b.balancesMultiValue == nil {
 			return 0
@@ -0,0 +1,335 @@
+package state_native
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state/state-native/types"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state/stateutil"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/container/slice"
+	"github.com/prysmaticlabs/prysm/v5/crypto/hash/htr"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	"github.com/prysmaticlabs/prysm/v5/encoding/ssz"
+	"github.com/prysmaticlabs/prysm/v5/encoding/ssz/multiproof"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/runtime/version"
+)
+
+// Multiproof crafts a Merkle multiproof of the nodes at the given generalized indices of the
+// state's SSZ tree. The proof can descend into the validator registry, balances, inactivity
+// scores, block and state roots, randao mixes, slashings, historical roots and summaries, as well
+// as the fork, latest block header, eth1 data and checkpoint containers. Other fields can only be
+// proven as a whole.
+//
+// The proof is crafted under the read lock, from the Merkle layers of the state and the layers of
+// the field tries it keeps for hashing. Only the subtrees of the fields the generalized indices
+// descend into are built.
+func (b *BeaconState) Multiproof(ctx context.Context, gindices []uint64) (*multiproof.Multiproof, error) {
+	ctx, span := trace.StartSpan(ctx, "beaconState.Multiproof")
+	defer span.End()
+
+	b.lock.RLock()
+	for !b.multiproofReady(gindices) {
+		b.lock.RUnlock()
+		if err := b.prepareMultiproof(ctx, gindices); err != nil {
+			return nil, err
+		}
+		b.lock.RLock()
+	}
+	defer b.lock.RUnlock()
+
+	// The layers of the field tries are read while the tree is traversed, and field tries can be
+	// shared with other states, so they are read locked until the proof is crafted.
+	layers := make(map[types.FieldIndex][][]*[32]byte)
+	for _, field := range b.multiproofTrieFields(gindices) {
+		fTrie := b.stateFieldLeaves[field]
+		fTrie.RLock()
+		defer fTrie.RUnlock()
+		if !fTrie.Empty() {
+			layers[field] = fTrie.FieldLayers()
+		}
+	}
+
+	roots := make([][32]byte, len(b.merkleLayers[0]))
+	for i, r := range b.merkleLayers[0] {
+		roots[i] = bytesutil.ToBytes32(r)
+	}
+	tree, err := multiproof.Chunks(roots, uint8(len(b.merkleLayers)-1), func(position uint64) (multiproof.Node, error) {
+		return b.fieldTree(position, layers)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not build state tree")
+	}
+	return multiproof.Prove(tree, gindices)
+}
+
+// multiproofReady returns true if the Merkle layers of the state and the field tries of the fields
+// the generalized indices descend into are up to date.
+//
+// WARNING: Caller must acquire the mutex before using.
+func (b *BeaconState) multiproofReady(gindices []uint64) bool {
+	if len(b.merkleLayers) == 0 || len(b.dirtyFields) != 0 {
+		return false
+	}
+	for _, field := range b.multiproofTrieFields(gindices) {
+		if b.rebuildTrie[field] {
+			return false
+		}
+	}
+	return true
+}
+
+// prepareMultiproof brings the Merkle layers of the state up to date, and builds the field tries of
+// the fields the generalized indices descend into if they are missing.
+func (b *BeaconState) prepareMultiproof(ctx context.Context, gindices []uint64) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if err := b.initializeMerkleLayers(ctx); err != nil {
+		return err
+	}
+	if err := b.recomputeDirtyFields(ctx); err != nil {
+		return err
+	}
+	for _, field := range b.multiproofTrieFields(gindices) {
+		fTrie := b.stateFieldLeaves[field]
+		fTrie.RLock()
+		empty := fTrie.Empty()
+		fTrie.RUnlock()
+		if b.rebuildTrie[field] || empty {
+			if _, err := b.rootSelector(ctx, field); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// multiproofTrieFields returns the fields backed by a field trie that the generalized indices
+// descend into.
+//
+// WARNING: Caller must acquire the mutex before using.
+func (b *BeaconState) multiproofTrieFields(gindices []uint64) []types.FieldIndex {
+	if len(b.merkleLayers) == 0 {
+		return nil
+	}
+	depth := uint8(len(b.merkleLayers) - 1)
+	var fields []types.FieldIndex
+	for _, g := range gindices {
+		d := multiproof.Depth(g)
+		if d <= depth {
+			continue
+		}
+		position := g>>(d-depth) - uint64(1)<<depth
+		for _, field := range []types.FieldIndex{types.Validators, types.Balances, types.BlockRoots, types.StateRoots, types.RandaoMixes} {
+			if uint64(field.RealPosition()) == position && b.stateFieldLeaves[field] != nil {
+				fields = append(fields, field)
+			}
+		}
+	}
+	return slice.Unique(fields)
+}
+
+// fieldTree returns the subtree of the field at the given position of the state tree. The subtrees
+// of the fields backed by a field trie are read from the given trie layers when available.
+//
+// WARNING: Caller must acquire the mutex before using.
+func (b *BeaconState) fieldTree(position uint64, layers map[types.FieldIndex][][]*[32]byte) (multiproof.Node, error) {
+	if node, ok, err := b.fieldTrieTree(position, layers); ok || err != nil {
+		return node, err
+	}
+	switch int(position) {
+	case types.Fork.RealPosition():
+		return forkTree(b.fork), nil
+	case types.LatestBlockHeader.RealPosition():
+		h := b.latestBlockHeader
+		if h == nil {
+			h = &ethpb.BeaconBlockHeader{}
+		}
+		return multiproof.Container([]multiproof.Node{
+			multiproof.Uint64Leaf(uint64(h.Slot)),
+			multiproof.Uint64Leaf(uint64(h.ProposerIndex)),
+			multiproof.Leaf(bytesutil.ToBytes32(h.ParentRoot)),
+			multiproof.Leaf(bytesutil.ToBytes32(h.StateRoot)),
+			multiproof.Leaf(bytesutil.ToBytes32(h.BodyRoot)),
+		}), nil
+	case types.BlockRoots.RealPosition():
+		return rootsVectorTree(b.blockRootsVal().Slice(), fieldparams.BlockRootsLength)
+	case types.StateRoots.RealPosition():
+		return rootsVectorTree(b.stateRootsVal().Slice(), fieldparams.StateRootsLength)
+	case types.HistoricalRoots.RealPosition():
+		data, err := rootsVectorTree(b.historicalRoots.Slice(), fieldparams.HistoricalRootsLength)
+		if err != nil {
+			return nil, err
+		}
+		return multiproof.List(data, uint64(len(b.historicalRoots))), nil
+	case types.Eth1Data.RealPosition():
+		d := b.eth1Data
+		if d == nil {
+			d = &ethpb.Eth1Data{}
+		}
+		return multiproof.Container([]multiproof.Node{
+			multiproof.Leaf(bytesutil.ToBytes32(d.DepositRoot)),
+			multiproof.Uint64Leaf(d.DepositCount),
+			multiproof.Leaf(bytesutil.ToBytes32(d.BlockHash)),
+		}), nil
+	case types.Validators.RealPosition():
+		return validatorsTree(b.validatorsVal())
+	case types.Balances.RealPosition():
+		return uint64ListTree(b.balancesVal(), stateutil.ValidatorLimitForBalancesChunks())
+	case types.RandaoMixes.RealPosition():
+		return rootsVectorTree(b.randaoMixesVal().Slice(), fieldparams.RandaoMixesLength)
+	case types.Slashings.RealPosition():
+		chunks, err := stateutil.PackUint64IntoChunks(b.slashings)
+		if err != nil {
+			return nil, err
+		}
+		return multiproof.Chunks(chunks, ssz.Depth(uint64(len(chunks))), nil)
+	case types.PreviousJustifiedCheckpoint.RealPosition():
+		return checkpointTree(b.previousJustifiedCheckpoint), nil
+	case types.CurrentJustifiedCheckpoint.RealPosition():
+		return checkpointTree(b.currentJustifiedCheckpoint), nil
+	case types.FinalizedCheckpoint.RealPosition():
+		return checkpointTree(b.finalizedCheckpoint), nil
+	case types.InactivityScores.RealPosition():
+		if b.version >= version.Altair {
+			return uint64ListTree(b.inactivityScoresVal(), stateutil.ValidatorLimitForBalancesChunks())
+		}
+	case types.HistoricalSummaries.RealPosition():
+		if b.version >= version.Capella {
+			return historicalSummariesTree(b.historicalSummaries)
+		}
+	}
+	return multiproof.Leaf(bytesutil.ToBytes32(b.merkleLayers[0][position])), nil
+}
+
+// fieldTrieTree returns the subtree of a field backed by a field trie from the layers of the trie,
+// or false if they are not available.
+//
+// WARNING: Caller must acquire the mutex before using.
+func (b *BeaconState) fieldTrieTree(position uint64, layers map[types.FieldIndex][][]*[32]byte) (multiproof.Node, bool, error) {
+	fieldLayers := func(field types.FieldIndex, limit uint64) [][]*[32]byte {
+		l := layers[field]
+		if int(position) != field.RealPosition() || len(l) != int(ssz.Depth(limit))+1 {
+			return nil
+		}
+		return l
+	}
+	if l := fieldLayers(types.Validators, fieldparams.ValidatorRegistryLimit); l != nil {
+		data, err := multiproof.Layers(l, func(i uint64) (multiproof.Node, error) {
+			val, err := b.validatorAtIndex(primitives.ValidatorIndex(i))
+			if err != nil {
+				return nil, err
+			}
+			fieldRoots, err := stateutil.ValidatorFieldRoots(val)
+			if err != nil {
+				return nil, err
+			}
+			return multiproof.Roots(fieldRoots), nil
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		return multiproof.List(data, uint64(b.validatorsLen())), true, nil
+	}
+	if l := fieldLayers(types.Balances, stateutil.ValidatorLimitForBalancesChunks()); l != nil {
+		data, err := multiproof.Layers(l, nil)
+		if err != nil {
+			return nil, false, err
+		}
+		return multiproof.List(data, uint64(b.balancesLen())), true, nil
+	}
+	if l := fieldLayers(types.BlockRoots, fieldparams.BlockRootsLength); l != nil {
+		node, err := multiproof.Layers(l, nil)
+		return node, err == nil, err
+	}
+	if l := fieldLayers(types.StateRoots, fieldparams.StateRootsLength); l != nil {
+		node, err := multiproof.Layers(l, nil)
+		return node, err == nil, err
+	}
+	if l := fieldLayers(types.RandaoMixes, fieldparams.RandaoMixesLength); l != nil {
+		node, err := multiproof.Layers(l, nil)
+		return node, err == nil, err
+	}
+	return nil, false, nil
+}
+
+func forkTree(f *ethpb.Fork) multiproof.Node {
+	if f == nil {
+		f = &ethpb.Fork{}
+	}
+	return multiproof.Container([]multiproof.Node{
+		multiproof.Leaf(bytesutil.ToBytes32(f.PreviousVersion)),
+		multiproof.Leaf(bytesutil.ToBytes32(f.CurrentVersion)),
+		multiproof.Uint64Leaf(uint64(f.Epoch)),
+	})
+}
+
+func checkpointTree(c *ethpb.Checkpoint) multiproof.Node {
+	if c == nil {
+		c = &ethpb.Checkpoint{}
+	}
+	return multiproof.Container([]multiproof.Node{
+		multiproof.Uint64Leaf(uint64(c.Epoch)),
+		multiproof.Leaf(bytesutil.ToBytes32(c.Root)),
+	})
+}
+
+// rootsVectorTree returns the tree of a vector of roots, or the data tree of a list of roots with the given limit.
+func rootsVectorTree(roots [][]byte, limit uint64) (multiproof.Node, error) {
+	chunks := make([][32]byte, len(roots))
+	for i, r := range roots {
+		chunks[i] = bytesutil.ToBytes32(r)
+	}
+	return multiproof.Chunks(chunks, ssz.Depth(limit), nil)
+}
+
+// uint64ListTree returns the tree of a list of packed uint64 values with the given chunk limit.
+func uint64ListTree(vals []uint64, chunkLimit uint64) (multiproof.Node, error) {
+	chunks, err := stateutil.PackUint64IntoChunks(vals)
+	if err != nil {
+		return nil, err
+	}
+	data, err := multiproof.Chunks(chunks, ssz.Depth(chunkLimit), nil)
+	if err != nil {
+		return nil, err
+	}
+	return multiproof.List(data, uint64(len(vals))), nil
+}
+
+func validatorsTree(vals []*ethpb.Validator) (multiproof.Node, error) {
+	roots, err := stateutil.OptimizedValidatorRoots(vals)
+	if err != nil {
+		return nil, err
+	}
+	data, err := multiproof.Chunks(roots, ssz.Depth(fieldparams.ValidatorRegistryLimit), func(i uint64) (multiproof.Node, error) {
+		fieldRoots, err := stateutil.ValidatorFieldRoots(vals[i])
+		if err != nil {
+			return nil, err
+		}
+		return multiproof.Roots(fieldRoots), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return multiproof.List(data, uint64(len(vals))), nil
+}
+
+func historicalSummariesTree(summaries []*ethpb.HistoricalSummary) (multiproof.Node, error) {
+	fields := make([][2][32]byte, len(summaries))
+	roots := make([][32]byte, len(summaries))
+	for i, s := range summaries {
+		fields[i] = [2][32]byte{bytesutil.ToBytes32(s.BlockSummaryRoot), bytesutil.ToBytes32(s.StateSummaryRoot)}
+		roots[i] = htr.VectorizedSha256(fields[i][:])[0]
+	}
+	data, err := multiproof.Chunks(roots, ssz.Depth(fieldparams.HistoricalRootsLength), func(i uint64) (multiproof.Node, error) {
+		return multiproof.Roots(fields[i][:]), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return multiproof.List(data, uint64(len(summaries))), nil
+}
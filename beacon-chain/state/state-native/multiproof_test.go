@@ -0,0 +1,129 @@
+package state_native_test
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state/state-native/types"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	"github.com/prysmaticlabs/prysm/v5/encoding/ssz/multiproof"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+func TestBeaconState_Multiproof(t *testing.T) {
+	ctx := context.Background()
+	st, _ := util.DeterministicGenesisStateCapella(t, 64)
+	require.NoError(t, st.UpdateBalancesAtIndex(5, 123))
+	require.NoError(t, st.UpdateBlockRootAtIndex(2, [32]byte{'b'}))
+	require.NoError(t, st.AppendHistoricalSummaries(&ethpb.HistoricalSummary{BlockSummaryRoot: make([]byte, 32), StateSummaryRoot: bytesutil.PadTo([]byte{'s'}, 32)}))
+	require.NoError(t, st.AppendHistoricalSummaries(&ethpb.HistoricalSummary{BlockSummaryRoot: make([]byte, 32), StateSummaryRoot: bytesutil.PadTo([]byte{'t'}, 32)}))
+	require.NoError(t, st.SetFinalizedCheckpoint(&ethpb.Checkpoint{Epoch: 3, Root: bytesutil.PadTo([]byte{'f'}, 32)}))
+	root, err := st.HashTreeRoot(ctx)
+	require.NoError(t, err)
+
+	field := func(f types.FieldIndex) uint64 {
+		return 32 + uint64(f.RealPosition())
+	}
+	withdrawalCredentials := multiproof.Concat(field(types.Validators), 2, fieldparams.ValidatorRegistryLimit+3, 8+1)
+	balances := multiproof.Concat(field(types.Balances), 2, (fieldparams.ValidatorRegistryLimit/4)+1)
+	validatorCount := multiproof.Concat(field(types.Validators), 3)
+	blockRoot := multiproof.Concat(field(types.BlockRoots), fieldparams.BlockRootsLength+2)
+	stateSummaryRoot := multiproof.Concat(field(types.HistoricalSummaries), 2, fieldparams.HistoricalRootsLength+1, 3)
+	finalizedRoot := multiproof.Concat(field(types.FinalizedCheckpoint), 3)
+	gindices := []uint64{withdrawalCredentials, balances, validatorCount, blockRoot, stateSummaryRoot, finalizedRoot}
+
+	p, err := st.Multiproof(ctx, gindices)
+	require.NoError(t, err)
+	ok, err := p.Verify(root)
+	require.NoError(t, err)
+	require.Equal(t, true, ok)
+
+	leaves := make(map[uint64][]byte)
+	for i, g := range p.Indices {
+		leaves[g] = p.Leaves[i][:]
+	}
+	val, err := st.ValidatorAtIndexReadOnly(3)
+	require.NoError(t, err)
+	assert.Equal(t, val.GetWithdrawalCredentials()[0], leaves[withdrawalCredentials][0])
+	assert.Equal(t, uint64(123), binary.LittleEndian.Uint64(leaves[balances][8:16]))
+	assert.Equal(t, uint64(64), binary.LittleEndian.Uint64(leaves[validatorCount][:8]))
+	assert.DeepEqual(t, bytesutil.PadTo([]byte{'b'}, 32), leaves[blockRoot])
+	assert.Equal(t, byte('t'), leaves[stateSummaryRoot][0])
+	assert.Equal(t, byte('f'), leaves[finalizedRoot][0])
+
+	t.Run("opaque field", func(t *testing.T) {
+		_, err := st.Multiproof(ctx, []uint64{multiproof.Concat(field(types.Eth1DataVotes), 2)})
+		require.ErrorIs(t, err, multiproof.ErrLeaf)
+	})
+	t.Run("phase0", func(t *testing.T) {
+		st, _ := util.DeterministicGenesisState(t, 16)
+		require.NoError(t, st.AppendHistoricalRoots([32]byte{'h'}))
+		root, err := st.HashTreeRoot(ctx)
+		require.NoError(t, err)
+		historicalRoot := multiproof.Concat(field(types.HistoricalRoots), 2, fieldparams.HistoricalRootsLength)
+		p, err := st.Multiproof(ctx, []uint64{
+			multiproof.Concat(field(types.Validators), 2, fieldparams.ValidatorRegistryLimit+15, 8+2),
+			historicalRoot,
+			field(types.Slashings),
+			multiproof.Concat(field(types.Fork), 5),
+		})
+		require.NoError(t, err)
+		ok, err := p.Verify(root)
+		require.NoError(t, err)
+		require.Equal(t, true, ok)
+		assert.DeepEqual(t, [][32]byte{{'h'}}, p.Leaves[2:3])
+	})
+}
+
+func TestBeaconState_Multiproof_FieldTries(t *testing.T) {
+	ctx := context.Background()
+	st, _ := util.DeterministicGenesisStateCapella(t, 64)
+	field := func(f types.FieldIndex) uint64 {
+		return 32 + uint64(f.RealPosition())
+	}
+	effectiveBalance := multiproof.Concat(field(types.Validators), 2, fieldparams.ValidatorRegistryLimit+7, 8+2)
+	randaoMix := multiproof.Concat(field(types.RandaoMixes), fieldparams.RandaoMixesLength+1)
+	gindices := []uint64{effectiveBalance, randaoMix}
+
+	// The proofs of a state and of its copy stay valid as the field tries they share are updated.
+	p, err := st.Multiproof(ctx, gindices)
+	require.NoError(t, err)
+	root, err := st.HashTreeRoot(ctx)
+	require.NoError(t, err)
+	ok, err := p.Verify(root)
+	require.NoError(t, err)
+	require.Equal(t, true, ok)
+
+	cp := st.Copy()
+	val, err := st.ValidatorAtIndex(7)
+	require.NoError(t, err)
+	val.EffectiveBalance = 1
+	require.NoError(t, st.UpdateValidatorAtIndex(7, val))
+	require.NoError(t, st.UpdateRandaoMixesAtIndex(1, [32]byte{'r'}))
+
+	for _, s := range []interface {
+		HashTreeRoot(context.Context) ([32]byte, error)
+		Multiproof(context.Context, []uint64) (*multiproof.Multiproof, error)
+	}{st, cp} {
+		p, err := s.Multiproof(ctx, gindices)
+		require.NoError(t, err)
+		root, err := s.HashTreeRoot(ctx)
+		require.NoError(t, err)
+		ok, err := p.Verify(root)
+		require.NoError(t, err)
+		require.Equal(t, true, ok)
+	}
+	p, err = st.Multiproof(ctx, gindices)
+	require.NoError(t, err)
+	leaves := make(map[uint64][]byte)
+	for i, g := range p.Indices {
+		leaves[g] = p.Leaves[i][:]
+	}
+	assert.Equal(t, uint64(1), binary.LittleEndian.Uint64(leaves[effectiveBalance][:8]))
+	assert.Equal(t, byte('r'), leaves[randaoMix][0])
+}
@@ -0,0 +1,403 @@
+package backfill
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api/client/beacon"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/filesystem"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/filters"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p"
+	p2ptypes "github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/types"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/startup"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/sync"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/verification"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/interfaces"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/runtime"
+	"github.com/prysmaticlabs/prysm/v5/runtime/version"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// archiveBatchSlots is the number of slots the blob archive checks at once.
+	archiveBatchSlots = 32
+	// archivePeers is the number of peers asked for the blob sidecars of a block before falling back to the provider.
+	archivePeers = 3
+	// archiveRetryInterval is how long the blob archive waits for backfill, or after an error, before trying again.
+	archiveRetryInterval = time.Minute
+	// archiveGapRetryInterval is how often blob sidecars that could not be found are requested again.
+	archiveGapRetryInterval = time.Hour
+)
+
+var errArchiveBlobMismatch = errors.New("blob sidecar does not match the stored block")
+
+// BlobArchiveDB is the subset of the database used by the blob archive to find the blocks that have blob sidecars.
+type BlobArchiveDB interface {
+	Block(ctx context.Context, blockRoot [32]byte) (interfaces.ReadOnlySignedBeaconBlock, error)
+	Blocks(ctx context.Context, f *filters.QueryFilter) ([]interfaces.ReadOnlySignedBeaconBlock, [][32]byte, error)
+	IsFinalizedBlock(ctx context.Context, blockRoot [32]byte) bool
+}
+
+// BlobArchiveConfig contains the dependencies of the BlobArchive.
+type BlobArchiveConfig struct {
+	Store          *Store
+	DB             BlobArchiveDB
+	BlobStorage    *filesystem.BlobStorage
+	ClockWaiter    startup.ClockWaiter
+	P2P            p2p.P2P
+	PeerAssigner   PeerAssigner
+	VerifierWaiter InitializerWaiter
+	InitSyncWaiter func() error
+	// Provider is an optional beacon API that serves historical blob sidecars, like another archive node.
+	Provider *beacon.Client
+}
+
+// BlobArchive downloads the blob sidecars of finalized blocks older than the blob retention period, for beacon nodes
+// that keep blobs forever. Blob sidecars are requested from peers, then from the configured beacon API provider,
+// and verified against the KZG commitments of the stored blocks. The blocks whose blob sidecars can't be found are
+// recorded as gaps in the filesystem.BlobCoverage index, and requested again periodically.
+type BlobArchive struct {
+	cfg      *BlobArchiveConfig
+	ctx      context.Context
+	cancel   context.CancelFunc
+	clock    *startup.Clock
+	ctxMap   sync.ContextByteVersions
+	nbv      verification.NewBlobVerifier
+	coverage *filesystem.BlobCoverage
+}
+
+var _ runtime.Service = (*BlobArchive)(nil)
+
+// NewBlobArchive creates a BlobArchive. The BlobStorage must be in archive mode.
+func NewBlobArchive(ctx context.Context, cfg *BlobArchiveConfig) (*BlobArchive, error) {
+	coverage := cfg.BlobStorage.Coverage()
+	if coverage == nil {
+		return nil, errors.New("blob archive requires blob storage in archive mode")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return &BlobArchive{cfg: cfg, ctx: ctx, cancel: cancel, coverage: coverage}, nil
+}
+
+// Start the blob archive.
+func (a *BlobArchive) Start() {
+	go a.run()
+}
+
+// Stop the blob archive.
+func (a *BlobArchive) Stop() error {
+	a.cancel()
+	return nil
+}
+
+// Status of the blob archive.
+func (*BlobArchive) Status() error {
+	return nil
+}
+
+func (a *BlobArchive) run() {
+	if params.BeaconConfig().DenebForkEpoch == math.MaxUint64 {
+		log.Info("Blob archive not started; the deneb fork is not scheduled")
+		return
+	}
+	if err := a.init(a.ctx); err != nil {
+		log.WithError(err).Error("Could not start blob archive")
+		return
+	}
+	floor, err := slots.EpochStart(params.BeaconConfig().DenebForkEpoch)
+	if err != nil {
+		log.WithError(err).Error("Could not compute the first slot of the deneb fork")
+		return
+	}
+	high, err := sync.BlobRPCMinValidSlot(a.clock.CurrentSlot())
+	if err != nil {
+		log.WithError(err).Error("Could not compute the first slot of the blob retention period")
+		return
+	}
+	low, err := a.coverage.Start(high)
+	if err != nil {
+		log.WithError(err).Error("Could not initialize blob archive coverage")
+		return
+	}
+	log.WithFields(logrus.Fields{"lowestCheckedSlot": low, "denebSlot": floor}).Info("Blob archive started")
+	a.retryGaps(a.ctx)
+	a.updateMetrics()
+
+	for low > floor {
+		if a.ctx.Err() != nil {
+			return
+		}
+		start := floor
+		if low > floor+archiveBatchSlots {
+			start = low - archiveBatchSlots
+		}
+		// Blocks below the backfill low slot aren't in the database yet, so they can't be checked.
+		if !a.cfg.Store.AvailableBlock(start) {
+			a.wait(archiveRetryInterval)
+			continue
+		}
+		gaps, err := a.archiveRange(a.ctx, start, low)
+		if err == nil {
+			err = a.coverage.Extend(start, gaps)
+		}
+		if err != nil {
+			log.WithError(err).WithFields(logrus.Fields{"startSlot": start, "endSlot": low}).
+				Error("Could not archive blob sidecars")
+			a.wait(archiveRetryInterval)
+			continue
+		}
+		low = start
+		a.updateMetrics()
+	}
+	log.WithField("gaps", len(a.coverage.Gaps(floor, high))).Info("Blob archive reached the deneb fork")
+
+	ticker := time.NewTicker(archiveGapRetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			a.retryGaps(a.ctx)
+			a.updateMetrics()
+		case <-a.ctx.Done():
+			return
+		}
+	}
+}
+
+func (a *BlobArchive) init(ctx context.Context) error {
+	clock, err := a.cfg.ClockWaiter.WaitForClock(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not wait for genesis data")
+	}
+	a.clock = clock
+	ini, err := a.cfg.VerifierWaiter.WaitForInitializer(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not initialize blob verifier")
+	}
+	a.nbv = newBlobVerifierFromInitializer(ini)
+	a.ctxMap, err = sync.ContextByteVersionsForValRoot(clock.GenesisValidatorsRoot())
+	if err != nil {
+		return errors.Wrap(err, "could not initialize context version map")
+	}
+	if a.cfg.InitSyncWaiter != nil {
+		if err := a.cfg.InitSyncWaiter(); err != nil {
+			return errors.Wrap(err, "could not wait for initial sync")
+		}
+	}
+	return nil
+}
+
+func (a *BlobArchive) updateMetrics() {
+	low, high := a.coverage.Checked()
+	blobArchiveLowestSlot.Set(float64(low))
+	blobArchiveGaps.Set(float64(len(a.coverage.Gaps(low, high))))
+}
+
+func (a *BlobArchive) wait(d time.Duration) {
+	select {
+	case <-time.After(d):
+	case <-a.ctx.Done():
+	}
+}
+
+// archiveRange downloads the missing blob sidecars of the finalized blocks in [start, end), and returns the gaps
+// for the blocks whose blob sidecars could not all be found.
+func (a *BlobArchive) archiveRange(ctx context.Context, start, end primitives.Slot) ([]filesystem.BlobGap, error) {
+	blks, roots, err := a.cfg.DB.Blocks(ctx, filters.NewFilter().SetStartSlot(start).SetEndSlot(end-1))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read blocks from the database")
+	}
+	gaps := make([]filesystem.BlobGap, 0)
+	for i := range blks {
+		if blks[i].Version() < version.Deneb || !a.cfg.DB.IsFinalizedBlock(ctx, roots[i]) {
+			continue
+		}
+		blk, err := blocks.NewROBlockWithRoot(blks[i], roots[i])
+		if err != nil {
+			return nil, err
+		}
+		missing, err := a.archiveBlock(ctx, blk, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(missing) > 0 {
+			gaps = append(gaps, filesystem.BlobGap{Slot: blk.Block().Slot(), Root: blk.Root(), Missing: missing})
+		}
+	}
+	return gaps, nil
+}
+
+// retryGaps requests the blob sidecars recorded as gaps again, and removes the gaps that were filled from the index.
+func (a *BlobArchive) retryGaps(ctx context.Context) {
+	low, high := a.coverage.Checked()
+	gaps := a.coverage.Gaps(low, high)
+	filled := 0
+	for _, g := range gaps {
+		if ctx.Err() != nil {
+			return
+		}
+		b, err := a.cfg.DB.Block(ctx, g.Root)
+		if err != nil {
+			log.WithError(err).WithField("root", g.Root).Debug("Could not read block of blob archive gap")
+			continue
+		}
+		blk, err := blocks.NewROBlockWithRoot(b, g.Root)
+		if err != nil {
+			continue
+		}
+		missing, err := a.archiveBlock(ctx, blk, g.Missing)
+		if err != nil {
+			log.WithError(err).WithField("root", g.Root).Debug("Could not archive blob sidecars of gap")
+			continue
+		}
+		if len(missing) == 0 {
+			filled++
+		}
+		g.Missing = missing
+		if err := a.coverage.UpdateGap(g); err != nil {
+			log.WithError(err).Error("Could not update blob archive coverage")
+			return
+		}
+	}
+	if len(gaps) > 0 {
+		log.WithFields(logrus.Fields{"gaps": len(gaps), "filled": filled}).Info("Retried blob archive gaps")
+	}
+}
+
+// archiveBlock downloads and saves the blob sidecars of the block that are not stored yet, and returns the indices
+// of the blob sidecars that could not be found. When missing is nil, the stored blob sidecars are checked first.
+func (a *BlobArchive) archiveBlock(ctx context.Context, blk blocks.ROBlock, missing []uint64) ([]uint64, error) {
+	commitments, err := blk.Block().Body().BlobKzgCommitments()
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read commitments of block %#x", blk.Root())
+	}
+	if len(commitments) == 0 {
+		return nil, nil
+	}
+	if missing == nil {
+		stored, err := a.cfg.BlobStorage.Indices(blk.Root(), blk.Block().Slot())
+		if err != nil {
+			return nil, err
+		}
+		for i := range commitments {
+			if !stored[i] {
+				missing = append(missing, uint64(i))
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+	missing = a.fromPeers(ctx, blk, missing)
+	if len(missing) > 0 && a.cfg.Provider != nil {
+		missing = a.fromProvider(ctx, blk, missing)
+	}
+	return missing, nil
+}
+
+func (a *BlobArchive) fromPeers(ctx context.Context, blk blocks.ROBlock, missing []uint64) []uint64 {
+	pids, err := a.cfg.PeerAssigner.Assign(nil, archivePeers)
+	if err != nil {
+		return missing
+	}
+	for _, pid := range pids {
+		req := make(p2ptypes.BlobSidecarsByRootReq, len(missing))
+		for i := range missing {
+			req[i] = &ethpb.BlobIdentifier{BlockRoot: blk.RootSlice(), Index: missing[i]}
+		}
+		blobs, err := sync.SendBlobSidecarByRoot(ctx, a.clock, a.cfg.P2P, pid, a.ctxMap, &req, blk.Block().Slot())
+		if err != nil {
+			log.WithError(err).WithField("peer", pid).Debug("Could not request archived blob sidecars from peer")
+			continue
+		}
+		missing = a.saveVerified(blk, blobs, missing)
+		if len(missing) == 0 {
+			return missing
+		}
+	}
+	return missing
+}
+
+func (a *BlobArchive) fromProvider(ctx context.Context, blk blocks.ROBlock, missing []uint64) []uint64 {
+	sidecars, err := a.cfg.Provider.GetBlobSidecars(ctx, beacon.IdFromRoot(blk.Root()), missing)
+	if err != nil {
+		log.WithError(err).WithField("root", blk.Root()).Debug("Could not request archived blob sidecars from provider")
+		return missing
+	}
+	blobs := make([]blocks.ROBlob, 0, len(sidecars))
+	for _, sc := range sidecars {
+		rb, err := blocks.NewROBlob(sc)
+		if err != nil {
+			log.WithError(err).Debug("Ignoring invalid blob sidecar from provider")
+			continue
+		}
+		blobs = append(blobs, rb)
+	}
+	return a.saveVerified(blk, blobs, missing)
+}
+
+// saveVerified saves the given blob sidecars that pass verification against the block, and returns the indices
+// that are still missing.
+func (a *BlobArchive) saveVerified(blk blocks.ROBlock, blobs []blocks.ROBlob, missing []uint64) []uint64 {
+	saved := make(map[uint64]bool)
+	for _, rb := range blobs {
+		if err := a.verifyAndSave(blk, rb); err != nil {
+			log.WithError(err).WithFields(logrus.Fields{"root": blk.Root(), "index": rb.Index}).
+				Debug("Ignoring archived blob sidecar that failed verification")
+			continue
+		}
+		saved[rb.Index] = true
+	}
+	remaining := make([]uint64, 0, len(missing))
+	for _, idx := range missing {
+		if !saved[idx] {
+			remaining = append(remaining, idx)
+		}
+	}
+	return remaining
+}
+
+func (a *BlobArchive) verifyAndSave(blk blocks.ROBlock, rb blocks.ROBlob) error {
+	if rb.BlockRoot() != blk.Root() {
+		return errors.Wrapf(errArchiveBlobMismatch, "block root %#x != %#x", rb.BlockRoot(), blk.Root())
+	}
+	commitments, err := blk.Block().Body().BlobKzgCommitments()
+	if err != nil {
+		return err
+	}
+	if rb.Index >= uint64(len(commitments)) || !bytes.Equal(commitments[rb.Index], rb.KzgCommitment) {
+		return errors.Wrapf(errArchiveBlobMismatch, "commitment %#x at index %d", rb.KzgCommitment, rb.Index)
+	}
+	sig := blk.Signature()
+	if !bytes.Equal(sig[:], rb.SignedBlockHeader.Signature) {
+		return verification.ErrInvalidProposerSignature
+	}
+	v := a.nbv(rb, verification.BackfillBlobSidecarRequirements)
+	if err := v.BlobIndexInBounds(); err != nil {
+		return err
+	}
+	// The signature of the stored block was verified when it was imported, and matches the sidecar.
+	v.SatisfyRequirement(verification.RequireValidProposerSignature)
+	if err := v.SidecarInclusionProven(); err != nil {
+		return err
+	}
+	if err := v.SidecarKzgProofVerified(); err != nil {
+		return err
+	}
+	vb, err := v.VerifiedROBlob()
+	if err != nil {
+		return err
+	}
+	if err := a.cfg.BlobStorage.Save(vb); err != nil {
+		return err
+	}
+	blobArchiveBlobsSaved.Inc()
+	return nil
+}
@@ -0,0 +1,135 @@
+package backfill
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/prysmaticlabs/prysm/v5/api/client"
+	"github.com/prysmaticlabs/prysm/v5/api/client/beacon"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/filesystem"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/filters"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/verification"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/interfaces"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+type mockArchiveDB struct {
+	blocks []blocks.ROBlock
+}
+
+func (m *mockArchiveDB) Block(_ context.Context, root [32]byte) (interfaces.ReadOnlySignedBeaconBlock, error) {
+	for _, b := range m.blocks {
+		if b.Root() == root {
+			return b.ReadOnlySignedBeaconBlock, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockArchiveDB) Blocks(_ context.Context, f *filters.QueryFilter) ([]interfaces.ReadOnlySignedBeaconBlock, [][32]byte, error) {
+	start, end := f.Filters()[filters.StartSlot].(primitives.Slot), f.Filters()[filters.EndSlot].(primitives.Slot)
+	blks := make([]interfaces.ReadOnlySignedBeaconBlock, 0)
+	roots := make([][32]byte, 0)
+	for _, b := range m.blocks {
+		if b.Block().Slot() < start || b.Block().Slot() > end {
+			continue
+		}
+		blks = append(blks, b.ReadOnlySignedBeaconBlock)
+		roots = append(roots, b.Root())
+	}
+	return blks, roots, nil
+}
+
+func (*mockArchiveDB) IsFinalizedBlock(context.Context, [32]byte) bool {
+	return true
+}
+
+type archiveProviderRT func(*http.Request) (*http.Response, error)
+
+func (rt archiveProviderRT) RoundTrip(req *http.Request) (*http.Response, error) {
+	return rt(req)
+}
+
+func sidecarsResponse(t *testing.T, blobs []blocks.ROBlob) []byte {
+	data := make([]*structs.Sidecar, len(blobs))
+	for i, b := range blobs {
+		proof := make([]string, len(b.CommitmentInclusionProof))
+		for j := range b.CommitmentInclusionProof {
+			proof[j] = hexutil.Encode(b.CommitmentInclusionProof[j])
+		}
+		data[i] = &structs.Sidecar{
+			Index:                    strconv.FormatUint(b.Index, 10),
+			Blob:                     hexutil.Encode(b.Blob),
+			SignedBeaconBlockHeader:  structs.SignedBeaconBlockHeaderFromConsensus(b.SignedBlockHeader),
+			KzgCommitment:            hexutil.Encode(b.KzgCommitment),
+			KzgProof:                 hexutil.Encode(b.KzgProof),
+			CommitmentInclusionProof: proof,
+		}
+	}
+	body, err := json.Marshal(&structs.SidecarsResponse{Data: data})
+	require.NoError(t, err)
+	return body
+}
+
+func TestBlobArchiveRange(t *testing.T) {
+	served, servedBlobs := util.GenerateTestDenebBlockWithSidecar(t, [32]byte{}, 10, 2)
+	missing, _ := util.GenerateTestDenebBlockWithSidecar(t, [32]byte{}, 11, 3)
+	empty, _ := util.GenerateTestDenebBlockWithSidecar(t, [32]byte{}, 12, 0)
+
+	rt := archiveProviderRT(func(req *http.Request) (*http.Response, error) {
+		res := &http.Response{Request: req, StatusCode: http.StatusNotFound, Body: io.NopCloser(bytes.NewBufferString(""))}
+		if req.URL.Path == fmt.Sprintf("/eth/v1/beacon/blob_sidecars/%#x", served.Root()) {
+			res.StatusCode = http.StatusOK
+			res.Body = io.NopCloser(bytes.NewBuffer(sidecarsResponse(t, servedBlobs)))
+		}
+		return res, nil
+	})
+	provider, err := beacon.NewClient("http://provider:3500", client.WithRoundTripper(rt))
+	require.NoError(t, err)
+
+	bs := filesystem.NewEphemeralBlobArchive(t)
+	a, err := NewBlobArchive(context.Background(), &BlobArchiveConfig{
+		DB:           &mockArchiveDB{blocks: []blocks.ROBlock{served, missing, empty}},
+		BlobStorage:  bs,
+		PeerAssigner: &mockAssigner{err: peers.ErrInsufficientSuitable},
+		Provider:     provider,
+	})
+	require.NoError(t, err)
+	a.nbv = func(rb blocks.ROBlob, _ []verification.Requirement) verification.BlobVerifier {
+		return &verification.MockBlobVerifier{CbVerifiedROBlob: func() (blocks.VerifiedROBlob, error) {
+			return blocks.NewVerifiedROBlob(rb), nil
+		}}
+	}
+
+	_, err = bs.Coverage().Start(20)
+	require.NoError(t, err)
+	gaps, err := a.archiveRange(context.Background(), 10, 20)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(gaps))
+	require.Equal(t, missing.Root(), gaps[0].Root)
+	require.DeepEqual(t, []uint64{0, 1, 2}, gaps[0].Missing)
+	stored, err := bs.Indices(served.Root(), served.Block().Slot())
+	require.NoError(t, err)
+	require.Equal(t, true, stored[0] && stored[1])
+
+	require.NoError(t, bs.Coverage().Extend(10, gaps))
+	// Retrying the gap fails again, and keeps it in the index.
+	a.retryGaps(context.Background())
+	require.Equal(t, 1, len(bs.Coverage().Gaps(10, 20)))
+
+	t.Run("sidecar of a different block", func(t *testing.T) {
+		require.ErrorIs(t, a.verifyAndSave(missing, servedBlobs[0]), errArchiveBlobMismatch)
+	})
+}
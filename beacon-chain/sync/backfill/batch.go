@@ -75,6 +75,8 @@ type batch struct {
 	blockPid       peer.ID
 	blobPid        peer.ID
 	bs             *blobSync
+	dlBytes        int           // approximate bytes of blocks and blobs downloaded for the batch
+	dlTime         time.Duration // time spent downloading blocks and blobs for the batch
 }
 
 func (b batch) logFields() logrus.Fields {
@@ -166,6 +168,7 @@ func (b batch) withState(s batchState) batch {
 		case batchInit, batchNil:
 			b.firstScheduled = b.scheduled
 		}
+		b.dlBytes, b.dlTime = 0, 0
 	}
 	if s == batchImportComplete {
 		backfillBatchTimeRoundtrip.Observe(float64(time.Since(b.firstScheduled).Milliseconds()))
@@ -177,7 +180,7 @@ func (b batch) withState(s batchState) batch {
 }
 
 func (b batch) withPeer(p peer.ID) batch {
-	b.blockPid = p
+	b.busy = p
 	backfillBatchTimeWaiting.Observe(float64(time.Since(b.scheduled).Milliseconds()))
 	return b
 }
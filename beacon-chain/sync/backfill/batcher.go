@@ -125,6 +125,11 @@ func (c *batchSequencer) moveMinimum(min primitives.Slot) error {
 	return nil
 }
 
+// setBatchSize changes the size of batches that are created from now on. Batches that were already created keep their bounds.
+func (c *batchSequencer) setBatchSize(size primitives.Slot) {
+	c.batcher.size = size
+}
+
 // countWithState provides a view into how many batches are in a particular state
 // to be used for logging or metrics purposes.
 func (c *batchSequencer) countWithState(s batchState) int {
@@ -57,6 +57,48 @@ var (
 			Help: "Number of BeaconBlock values downloaded from peers for backfill.",
 		},
 	)
+	backfillBatchSize = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "backfill_batch_size",
+			Help: "Number of slots per backfill batch, adapted to the throughput and error rate of peers.",
+		},
+	)
+	backfillBytesPerSecond = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "backfill_bytes_per_second",
+			Help: "Approximate BeaconBlock and BlobSidecar bytes downloaded per second by backfill over the last minute.",
+		},
+	)
+	backfillSlotsPerSecond = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "backfill_slots_per_second",
+			Help: "Slots imported per second by backfill over the last minute.",
+		},
+	)
+	backfillETASeconds = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "backfill_eta_seconds",
+			Help: "Estimated number of seconds until backfill is complete, at the current import rate.",
+		},
+	)
+	blobArchiveLowestSlot = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "backfill_blob_archive_lowest_slot",
+			Help: "Lowest slot checked by the blob archive for blob sidecars older than the retention period.",
+		},
+	)
+	blobArchiveGaps = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "backfill_blob_archive_gaps",
+			Help: "Number of blocks whose blob sidecars could not be found by the blob archive.",
+		},
+	)
+	blobArchiveBlobsSaved = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "backfill_blob_archive_blobs_saved",
+			Help: "Number of historical BlobSidecar values downloaded and saved by the blob archive.",
+		},
+	)
 	backfillBatchTimeRoundtrip = promauto.NewHistogram(
 		prometheus.HistogramOpts{
 			Name:    "backfill_batch_time_roundtrip",
@@ -10,10 +10,13 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/filesystem"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers/scorers"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/startup"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/sync"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/verification"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/crypto/rand"
 )
 
 type batchWorkerPool interface {
@@ -37,6 +40,8 @@ func defaultNewWorker(p p2p.P2P) newWorker {
 type p2pBatchWorkerPool struct {
 	maxBatches  int
 	newWorker   newWorker
+	scorer      *scorers.BlockProviderScorer
+	rand        *rand.Rand
 	toWorkers   chan batch
 	fromWorkers chan batch
 	toRouter    chan batch
@@ -53,6 +58,8 @@ func newP2PBatchWorkerPool(p p2p.P2P, maxBatches int) *p2pBatchWorkerPool {
 	nw := defaultNewWorker(p)
 	return &p2pBatchWorkerPool{
 		newWorker:   nw,
+		scorer:      p.Peers().Scorers().BlockProviderScorer(),
+		rand:        rand.NewGenerator(),
 		toRouter:    make(chan batch, maxBatches),
 		fromRouter:  make(chan batch, maxBatches),
 		toWorkers:   make(chan batch),
@@ -132,7 +139,12 @@ func (p *p2pBatchWorkerPool) batchRouter(pa PeerAssigner) {
 			continue
 		}
 		// Try to assign as many outstanding batches as possible to peers and feed the assigned batches to workers.
-		assigned, err := pa.Assign(busy, len(todo))
+		// More peers than batches are requested, so that peers can be picked based on their block provider score.
+		n := len(todo)
+		if n < params.BeaconConfig().MaxPeersToSync {
+			n = params.BeaconConfig().MaxPeersToSync
+		}
+		assigned, err := pa.Assign(busy, n)
 		if err != nil {
 			if errors.Is(err, peers.ErrInsufficientSuitable) {
 				// Transient error resulting from insufficient number of connected peers. Leave batches in
@@ -142,14 +154,16 @@ func (p *p2pBatchWorkerPool) batchRouter(pa PeerAssigner) {
 			p.shutdown(err)
 			return
 		}
-		for _, pid := range assigned {
+		assigned = p.scorer.WeightSorted(p.rand, assigned, nil)
+		for len(todo) > 0 && len(assigned) > 0 {
 			if err := todo[0].waitUntilReady(p.ctx); err != nil {
 				log.WithError(p.ctx.Err()).Info("p2pBatchWorkerPool context canceled, shutting down")
 				p.shutdown(p.ctx.Err())
 				return
 			}
+			var pid peer.ID
+			pid, assigned = pickPeer(todo[0], assigned)
 			busy[pid] = true
-			todo[0].busy = pid
 			p.toWorkers <- todo[0].withPeer(pid)
 			if todo[0].begin < earliest {
 				earliest = todo[0].begin
@@ -160,6 +174,22 @@ func (p *p2pBatchWorkerPool) batchRouter(pa PeerAssigner) {
 	}
 }
 
+// pickPeer removes the peer that should download the given batch from the list of candidates, which are ordered by
+// preference. Blob sidecars are requested from a different peer than the one that served the blocks of the batch,
+// unless it is the only candidate, to spread the load of a batch across peers.
+func pickPeer(b batch, candidates []peer.ID) (peer.ID, []peer.ID) {
+	i := 0
+	if b.state == batchBlobSync {
+		for i < len(candidates)-1 && candidates[i] == b.blockPid {
+			i++
+		}
+	}
+	pid := candidates[i]
+	rest := make([]peer.ID, 0, len(candidates)-1)
+	rest = append(rest, candidates[:i]...)
+	return pid, append(rest, candidates[i+1:]...)
+}
+
 func (p *p2pBatchWorkerPool) shutdown(err error) {
 	p.cancel()
 	p.shutdownErr <- err
@@ -89,3 +89,26 @@ func (m *mockPool) complete() (batch, error) {
 }
 
 var _ batchWorkerPool = &mockPool{}
+
+func TestPickPeer(t *testing.T) {
+	a, b, c := peer.ID("a"), peer.ID("b"), peer.ID("c")
+	cases := []struct {
+		name       string
+		batch      batch
+		candidates []peer.ID
+		pid        peer.ID
+		rest       []peer.ID
+	}{
+		{name: "blocks from the preferred peer", batch: batch{state: batchSequenced}, candidates: []peer.ID{a, b, c}, pid: a, rest: []peer.ID{b, c}},
+		{name: "blobs from a different peer", batch: batch{state: batchBlobSync, blockPid: a}, candidates: []peer.ID{a, b, c}, pid: b, rest: []peer.ID{a, c}},
+		{name: "blobs from the preferred peer", batch: batch{state: batchBlobSync, blockPid: c}, candidates: []peer.ID{a, b, c}, pid: a, rest: []peer.ID{b, c}},
+		{name: "blobs from the block peer as a last resort", batch: batch{state: batchBlobSync, blockPid: a}, candidates: []peer.ID{a}, pid: a, rest: []peer.ID{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pid, rest := pickPeer(tc.batch, tc.candidates)
+			require.Equal(t, tc.pid, pid)
+			require.DeepEqual(t, tc.rest, rest)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package backfill
 
 import (
 	"context"
+	"time"
 
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/pkg/errors"
@@ -11,12 +12,14 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/startup"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/sync"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/verification"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
 	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
 	"github.com/prysmaticlabs/prysm/v5/proto/dbval"
 	"github.com/prysmaticlabs/prysm/v5/runtime"
 	"github.com/prysmaticlabs/prysm/v5/time/slots"
+	"github.com/sirupsen/logrus"
 )
 
 type Service struct {
@@ -31,6 +34,9 @@ type Service struct {
 	nWorkers        int
 	batchSeq        *batchSequencer
 	batchSize       uint64
+	maxBatchSize    uint64
+	sizer           *batchSizer
+	progress        *progress
 	pool            batchWorkerPool
 	verifier        *verifier
 	ctxMap          sync.ContextByteVersions
@@ -93,6 +99,15 @@ func WithBatchSize(n uint64) ServiceOption {
 	}
 }
 
+// WithMaxBatchSize sets the upper bound for the batch size, which is adapted to the throughput and error rate observed
+// from peers. Setting it to the same value as WithBatchSize disables batch size adaptation.
+func WithMaxBatchSize(n uint64) ServiceOption {
+	return func(s *Service) error {
+		s.maxBatchSize = n
+		return nil
+	}
+}
+
 // WithInitSyncWaiter sets a function on the service which will block until init-sync
 // completes for the first time, or returns an error if context is canceled.
 func WithInitSyncWaiter(w func() error) ServiceOption {
@@ -154,6 +169,10 @@ func NewService(ctx context.Context, su *Store, bStore *filesystem.BlobStorage,
 			return nil, err
 		}
 	}
+	// Larger requests would be rejected by peers after deneb.
+	if maxRequest := params.BeaconConfig().MaxRequestBlocksDeneb; s.maxBatchSize > maxRequest {
+		s.maxBatchSize = maxRequest
+	}
 	s.pool = newP2PBatchWorkerPool(p, s.nWorkers)
 
 	return s, nil
@@ -188,6 +207,13 @@ func (s *Service) updateComplete() bool {
 		return true
 	}
 	s.batchSeq.update(b)
+	s.progress.downloaded(time.Now(), b)
+	if size := s.sizer.observe(b); size != s.batchSeq.batcher.size {
+		log.WithField("batchSize", size).WithField("previousBatchSize", s.batchSeq.batcher.size).
+			Debug("Adjusting backfill batch size")
+		s.batchSeq.setBatchSize(size)
+		backfillBatchSize.Set(float64(size))
+	}
 	return false
 }
 
@@ -215,18 +241,41 @@ func (s *Service) importBatches(ctx context.Context) {
 			break
 		}
 		s.batchSeq.update(ib.withState(batchImportComplete))
+		s.progress.imported(time.Now(), ib)
 		imported += 1
 		// Calling update with state=batchImportComplete will advance the batch list.
 	}
 
 	nt := s.batchSeq.numTodo()
-	log.WithField("imported", imported).WithField("importable", len(importable)).
-		WithField("batchesRemaining", nt).
-		Info("Backfill batches processed")
+	now := time.Now()
+	bps := s.progress.bytes.perSecond(now)
+	fields := logrus.Fields{
+		"imported":         imported,
+		"importable":       len(importable),
+		"batchesRemaining": nt,
+		"batchSize":        s.batchSeq.batcher.size,
+		"bytesPerSecond":   int(bps),
+	}
+	backfillBytesPerSecond.Set(bps)
+	backfillSlotsPerSecond.Set(s.progress.slots.perSecond(now))
+	if eta, ok := s.progress.eta(now, s.remainingSlots(current)); ok {
+		fields["eta"] = eta.Round(time.Second).String()
+		backfillETASeconds.Set(eta.Seconds())
+	}
+	log.WithFields(fields).Info("Backfill batches processed")
 
 	backfillRemainingBatches.Set(float64(nt))
 }
 
+// remainingSlots returns the number of slots between the lowest backfilled block and the minimum backfill slot.
+func (s *Service) remainingSlots(current primitives.Slot) primitives.Slot {
+	low, min := primitives.Slot(s.store.status().LowSlot), s.ms(current)
+	if low <= min {
+		return 0
+	}
+	return low - min
+}
+
 func (s *Service) scheduleTodos() {
 	batches, err := s.batchSeq.sequence()
 	if err != nil {
@@ -298,6 +347,9 @@ func (s *Service) Start() {
 	}
 	s.pool.spawn(ctx, s.nWorkers, clock, s.pa, s.verifier, s.ctxMap, s.newBlobVerifier, s.blobStore)
 	s.batchSeq = newBatchSequencer(s.nWorkers, s.ms(s.clock.CurrentSlot()), primitives.Slot(status.LowSlot), primitives.Slot(s.batchSize))
+	s.sizer = newBatchSizer(primitives.Slot(s.batchSize), primitives.Slot(s.maxBatchSize))
+	s.progress = newProgress(time.Now())
+	backfillBatchSize.Set(float64(s.batchSize))
 	if err = s.initBatches(); err != nil {
 		log.WithError(err).Error("Non-recoverable error in backfill service")
 		return
@@ -0,0 +1,148 @@
+package backfill
+
+import (
+	"time"
+
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+)
+
+const (
+	// minAdaptiveBatchSize is the smallest batch size the batchSizer will shrink batches to.
+	minAdaptiveBatchSize = 4
+	// targetBatchDownload is the download time per batch that the batchSizer aims for. Batches downloading faster than
+	// half of this are grown, batches downloading slower than this are shrunk.
+	targetBatchDownload = 4 * time.Second
+	// errorRateDecay is the weight of the previous error rate when a new batch result is observed.
+	errorRateDecay = 0.8
+	// maxErrorRate is the error rate above which batches are shrunk, so that a failing request costs less.
+	maxErrorRate = 0.25
+	// rateWindow is the length of the sliding window used to compute download and import rates.
+	rateWindow = time.Minute
+)
+
+// batchSizer adapts the batch size to the throughput and error rate observed from peers. Batch sizes grow additively
+// while peers serve batches quickly and reliably, and shrink multiplicatively when requests fail.
+type batchSizer struct {
+	size    primitives.Slot
+	min     primitives.Slot
+	max     primitives.Slot
+	errRate float64
+}
+
+func newBatchSizer(size, max primitives.Slot) *batchSizer {
+	if max < size {
+		max = size
+	}
+	min := primitives.Slot(minAdaptiveBatchSize)
+	if size < min {
+		min = size
+	}
+	return &batchSizer{size: size, min: min, max: max}
+}
+
+// observe updates the batch size given a batch returned by the worker pool.
+func (s *batchSizer) observe(b batch) primitives.Slot {
+	switch b.state {
+	case batchErrRetryable:
+		s.errRate = s.errRate*errorRateDecay + (1 - errorRateDecay)
+		if s.errRate > maxErrorRate {
+			s.resize(s.size / 2)
+		}
+	case batchImportable:
+		s.errRate = s.errRate * errorRateDecay
+		// Only full size batches tell us whether peers can keep up with the current size.
+		if b.end-b.begin < s.size {
+			break
+		}
+		if b.dlTime > targetBatchDownload {
+			s.resize(s.size - s.size/4)
+		} else if b.dlTime < targetBatchDownload/2 && s.errRate <= maxErrorRate {
+			s.resize(s.size + s.size/4 + 1)
+		}
+	}
+	return s.size
+}
+
+func (s *batchSizer) resize(size primitives.Slot) {
+	if size < s.min {
+		size = s.min
+	}
+	if size > s.max {
+		size = s.max
+	}
+	s.size = size
+}
+
+type rateSample struct {
+	t time.Time
+	n uint64
+}
+
+// rateTracker computes the rate of a quantity, like downloaded bytes or imported slots, over a sliding window.
+type rateTracker struct {
+	since   time.Time
+	window  time.Duration
+	samples []rateSample
+}
+
+func newRateTracker(since time.Time, window time.Duration) *rateTracker {
+	return &rateTracker{since: since, window: window}
+}
+
+func (r *rateTracker) add(t time.Time, n uint64) {
+	r.samples = append(r.samples, rateSample{t: t, n: n})
+}
+
+// perSecond returns the rate over the window preceding now, or over the time since the tracker was created
+// when that is shorter than the window.
+func (r *rateTracker) perSecond(now time.Time) float64 {
+	start := now.Add(-r.window)
+	if r.since.After(start) {
+		start = r.since
+	}
+	// Samples are added in order, so everything before the first sample in the window can be dropped.
+	i := 0
+	for i < len(r.samples) && r.samples[i].t.Before(start) {
+		i++
+	}
+	r.samples = r.samples[i:]
+	elapsed := now.Sub(start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	total := uint64(0)
+	for _, s := range r.samples {
+		total += s.n
+	}
+	return float64(total) / elapsed
+}
+
+// progress tracks the download and import rates of backfill to estimate the time remaining.
+type progress struct {
+	bytes *rateTracker
+	slots *rateTracker
+}
+
+func newProgress(now time.Time) *progress {
+	return &progress{bytes: newRateTracker(now, rateWindow), slots: newRateTracker(now, rateWindow)}
+}
+
+func (p *progress) downloaded(now time.Time, b batch) {
+	if b.dlBytes > 0 {
+		p.bytes.add(now, uint64(b.dlBytes))
+	}
+}
+
+func (p *progress) imported(now time.Time, b batch) {
+	p.slots.add(now, uint64(b.end-b.begin))
+}
+
+// eta estimates the time needed to backfill the given number of slots at the current import rate.
+// The second return value is false when nothing has been imported recently, making an estimate impossible.
+func (p *progress) eta(now time.Time, remaining primitives.Slot) (time.Duration, bool) {
+	rate := p.slots.perSecond(now)
+	if rate == 0 {
+		return 0, false
+	}
+	return time.Duration(float64(remaining) / rate * float64(time.Second)), true
+}
@@ -0,0 +1,70 @@
+package backfill
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func TestBatchSizer(t *testing.T) {
+	fast := batch{begin: 0, end: 32, state: batchImportable, dlTime: time.Second}
+	slow := batch{begin: 0, end: 32, state: batchImportable, dlTime: 2 * targetBatchDownload}
+	failed := batch{begin: 0, end: 32, state: batchErrRetryable}
+
+	t.Run("fixed size", func(t *testing.T) {
+		s := newBatchSizer(32, 0)
+		require.Equal(t, primitives.Slot(32), s.observe(fast))
+		require.Equal(t, primitives.Slot(32), s.observe(failed))
+	})
+	t.Run("grows while peers keep up", func(t *testing.T) {
+		s := newBatchSizer(32, 64)
+		require.Equal(t, primitives.Slot(41), s.observe(fast))
+		// Batches created before the last resize don't tell if peers keep up with the new size.
+		require.Equal(t, primitives.Slot(41), s.observe(fast))
+		grown := batch{begin: 0, end: 41, state: batchImportable, dlTime: time.Second}
+		require.Equal(t, primitives.Slot(52), s.observe(grown))
+		grown.end = 52
+		require.Equal(t, primitives.Slot(64), s.observe(grown))
+		grown.end = 64
+		require.Equal(t, primitives.Slot(64), s.observe(grown))
+	})
+	t.Run("shrinks when downloads are slow", func(t *testing.T) {
+		s := newBatchSizer(32, 64)
+		require.Equal(t, primitives.Slot(24), s.observe(slow))
+	})
+	t.Run("shrinks when the error rate is high", func(t *testing.T) {
+		s := newBatchSizer(32, 64)
+		// A single error doesn't push the error rate over the threshold.
+		require.Equal(t, primitives.Slot(32), s.observe(failed))
+		require.Equal(t, primitives.Slot(16), s.observe(failed))
+		require.Equal(t, primitives.Slot(8), s.observe(failed))
+		require.Equal(t, primitives.Slot(minAdaptiveBatchSize), s.observe(failed))
+		require.Equal(t, primitives.Slot(minAdaptiveBatchSize), s.observe(failed))
+		// Fast batches don't grow the batch size until the error rate recovers.
+		small := batch{begin: 0, end: minAdaptiveBatchSize, state: batchImportable, dlTime: time.Second}
+		require.Equal(t, primitives.Slot(minAdaptiveBatchSize), s.observe(small))
+	})
+}
+
+func TestProgress(t *testing.T) {
+	start := time.Now()
+	p := newProgress(start)
+	_, ok := p.eta(start, 100)
+	require.Equal(t, false, ok)
+
+	p.downloaded(start.Add(5*time.Second), batch{dlBytes: 1000})
+	p.imported(start.Add(5*time.Second), batch{begin: 0, end: 50})
+	now := start.Add(10 * time.Second)
+	require.Equal(t, float64(100), p.bytes.perSecond(now))
+	eta, ok := p.eta(now, 100)
+	require.Equal(t, true, ok)
+	require.Equal(t, 20*time.Second, eta)
+
+	// Samples outside of the window no longer count towards the rate.
+	later := start.Add(5*time.Second + rateWindow + time.Second)
+	require.Equal(t, float64(0), p.bytes.perSecond(later))
+	_, ok = p.eta(later, 100)
+	require.Equal(t, false, ok)
+}
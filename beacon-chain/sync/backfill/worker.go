@@ -54,6 +54,7 @@ func (w *p2pWorker) handleBlocks(ctx context.Context, b batch) batch {
 	results, err := sync.SendBeaconBlocksByRangeRequest(ctx, w.c, w.p2p, b.blockPid, b.blockRequest(), blockValidationMetrics)
 	dlt := time.Now()
 	backfillBatchTimeDownloadingBlocks.Observe(float64(dlt.Sub(start).Milliseconds()))
+	b.dlTime += dlt.Sub(start)
 	if err != nil {
 		log.WithError(err).WithFields(b.logFields()).Debug("Batch requesting failed")
 		return b.withRetryableError(err)
@@ -72,6 +73,8 @@ func (w *p2pWorker) handleBlocks(ctx context.Context, b batch) batch {
 		bdl += vb[i].SizeSSZ()
 	}
 	backfillBlocksApproximateBytes.Add(float64(bdl))
+	b.dlBytes += bdl
+	w.p2p.Peers().Scorers().BlockProviderScorer().IncrementProcessedBlocks(b.blockPid, uint64(len(vb)))
 	log.WithFields(b.logFields()).WithField("dlbytes", bdl).Debug("Backfill batch block bytes downloaded")
 	bs, err := newBlobSync(cs, vb, &blobSyncConfig{retentionStart: blobRetentionStart, nbv: w.nbv, store: w.bfs})
 	if err != nil {
@@ -86,16 +89,18 @@ func (w *p2pWorker) handleBlobs(ctx context.Context, b batch) batch {
 	// we don't need to use the response for anything other than metrics, because blobResponseValidation
 	// adds each of them to a batch AvailabilityStore once it is checked.
 	blobs, err := sync.SendBlobsByRangeRequest(ctx, w.c, w.p2p, b.blobPid, w.cm, b.blobRequest(), b.blobResponseValidator(), blobValidationMetrics)
+	dlt := time.Now()
+	b.dlTime += dlt.Sub(start)
 	if err != nil {
 		b.bs = nil
 		return b.withRetryableError(err)
 	}
-	dlt := time.Now()
 	backfillBatchTimeDownloadingBlobs.Observe(float64(dlt.Sub(start).Milliseconds()))
 	if len(blobs) > 0 {
 		// All blobs are the same size, so we can compute 1 and use it for all in the batch.
 		sz := blobs[0].SizeSSZ() * len(blobs)
 		backfillBlobsApproximateBytes.Add(float64(sz))
+		b.dlBytes += sz
 		log.WithFields(b.logFields()).WithField("dlbytes", sz).Debug("Backfill batch blob bytes downloaded")
 	}
 	return b.postBlobSync()
@@ -8,22 +8,31 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/api/client/beacon"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db"
 	"github.com/prysmaticlabs/prysm/v5/config/params"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
 )
 
 // APIInitializer manages initializing the beacon node using checkpoint sync, retrieving the checkpoint state and root
-// from the remote beacon node api.
+// from the remote beacon node api. When several beacon nodes are given, a quorum of them must agree on the
+// finalized checkpoint before it is downloaded.
 type APIInitializer struct {
-	c *beacon.Client
+	clients []*beacon.Client
+	quorum  int
+	ws      *ethpb.Checkpoint
 }
 
-// NewAPIInitializer creates an APIInitializer, handling the set up of a beacon node api client
-// using the provided host string.
-func NewAPIInitializer(beaconNodeHost string) (*APIInitializer, error) {
-	c, err := beacon.NewClient(beaconNodeHost, client.WithMaxBodySize(client.MaxBodySizeState))
-	if err != nil {
-		return nil, errors.Wrapf(err, "unable to parse beacon node url or hostname - %s", beaconNodeHost)
+// NewAPIInitializer creates an APIInitializer, handling the set up of a beacon node api client for each of
+// the provided host strings. A quorum of zero requires a majority of the beacon nodes to agree on the finalized checkpoint,
+// which is also checked against the weak subjectivity checkpoint when it is not nil.
+func NewAPIInitializer(beaconNodeHosts []string, quorum int, ws *ethpb.Checkpoint) (*APIInitializer, error) {
+	clients := make([]*beacon.Client, len(beaconNodeHosts))
+	for i, host := range beaconNodeHosts {
+		c, err := beacon.NewClient(host, client.WithMaxBodySize(client.MaxBodySizeState))
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to parse beacon node url or hostname - %s", host)
+		}
+		clients[i] = c
 	}
-	return &APIInitializer{c: c}, nil
+	return &APIInitializer{clients: clients, quorum: quorum, ws: ws}, nil
 }
 
 // Initialize downloads origin state and block for checkpoint sync and initializes database records to
@@ -37,7 +46,7 @@ func (dl *APIInitializer) Initialize(ctx context.Context, d db.Database) error {
 	if err != nil && !errors.Is(err, db.ErrNotFound) {
 		return errors.Wrap(err, "error while checking database for origin root")
 	}
-	od, err := beacon.DownloadFinalizedData(ctx, dl.c)
+	od, err := beacon.DownloadFinalizedDataWithQuorum(ctx, dl.clients, dl.quorum, dl.ws)
 	if err != nil {
 		return errors.Wrap(err, "Error retrieving checkpoint origin state and block")
 	}
@@ -0,0 +1,214 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/io/file"
+)
+
+// ErrNotCached is returned when the Cache does not hold a finalized checkpoint yet.
+var ErrNotCached = errors.New("no finalized checkpoint cached for serving")
+
+const (
+	manifestName   = "checkpoint.json"
+	partialExt     = ".part"
+	sszExt         = ".ssz"
+	statePrefix    = "state_"
+	blockPrefix    = "block_"
+	cacheFilesGlob = "*" + sszExt
+)
+
+// CachedCheckpoint describes the finalized state and block held by the Cache.
+type CachedCheckpoint struct {
+	Epoch     primitives.Epoch `json:"epoch"`
+	Version   string           `json:"version"`
+	BlockRoot [32]byte         `json:"block_root"`
+	StateRoot [32]byte         `json:"state_root"`
+	Saved     time.Time        `json:"saved"`
+}
+
+// StateETag is the entity tag of the cached state, which changes with every finalized checkpoint.
+func (c *CachedCheckpoint) StateETag() string {
+	return fmt.Sprintf(`"%#x"`, c.StateRoot)
+}
+
+// BlockETag is the entity tag of the cached block, which changes with every finalized checkpoint.
+func (c *CachedCheckpoint) BlockETag() string {
+	return fmt.Sprintf(`"%#x"`, c.BlockRoot)
+}
+
+func (c *CachedCheckpoint) statePath(dir string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s%d-%#x%s", statePrefix, c.Epoch, c.StateRoot, sszExt))
+}
+
+func (c *CachedCheckpoint) blockPath(dir string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s%d-%#x%s", blockPrefix, c.Epoch, c.BlockRoot, sszExt))
+}
+
+// Cache keeps the ssz-encoded bytes of the latest finalized state and block on disk, so that they can be
+// served to checkpoint syncing nodes without marshaling the state for every request. Every finalized
+// checkpoint is written to new files, so that readers of the previous files are not disturbed. The files of
+// the checkpoint that was replaced last are kept, so that a block can still be served for a state downloaded
+// just before a newer checkpoint was cached.
+type Cache struct {
+	dir      string
+	lock     sync.RWMutex
+	current  *CachedCheckpoint
+	previous *CachedCheckpoint
+}
+
+// NewCache creates a Cache in the given directory, picking up the checkpoint cached by a previous run.
+func NewCache(dir string) (*Cache, error) {
+	if err := file.MkdirAll(dir); err != nil {
+		return nil, errors.Wrapf(err, "could not create checkpoint cache directory %s", dir)
+	}
+	c := &Cache{dir: dir}
+	mb, err := os.ReadFile(filepath.Join(dir, manifestName)) // #nosec G304
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read checkpoint cache manifest")
+	}
+	cp := &CachedCheckpoint{}
+	if err := json.Unmarshal(mb, cp); err != nil {
+		log.WithError(err).Warn("Ignoring invalid checkpoint cache manifest")
+		return c, nil
+	}
+	if err := existsAndIsFile(cp.statePath(dir)); err != nil {
+		log.WithError(err).Warn("Ignoring incomplete checkpoint cache")
+		return c, nil
+	}
+	if err := existsAndIsFile(cp.blockPath(dir)); err != nil {
+		log.WithError(err).Warn("Ignoring incomplete checkpoint cache")
+		return c, nil
+	}
+	c.current = cp
+	return c, nil
+}
+
+// Current returns the cached checkpoint, or nil if there is none.
+func (c *Cache) Current() *CachedCheckpoint {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.current == nil {
+		return nil
+	}
+	cp := *c.current
+	return &cp
+}
+
+// Save writes the ssz-encoded state and block of a finalized checkpoint to disk, and replaces the previously
+// cached checkpoint once both are complete.
+func (c *Cache) Save(cp *CachedCheckpoint, stateBytes, blockBytes []byte) error {
+	cp.Saved = time.Now()
+	if err := writeAtomic(cp.statePath(c.dir), stateBytes); err != nil {
+		return errors.Wrap(err, "could not write checkpoint state")
+	}
+	if err := writeAtomic(cp.blockPath(c.dir), blockBytes); err != nil {
+		return errors.Wrap(err, "could not write checkpoint block")
+	}
+	mb, err := json.Marshal(cp)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal checkpoint cache manifest")
+	}
+	if err := writeAtomic(filepath.Join(c.dir, manifestName), mb); err != nil {
+		return errors.Wrap(err, "could not write checkpoint cache manifest")
+	}
+
+	c.lock.Lock()
+	prev := c.current
+	c.previous, c.current = prev, cp
+	c.lock.Unlock()
+	c.prune(cp, prev)
+	return nil
+}
+
+// OpenState opens the file holding the cached state. The file can still be read after a newer
+// checkpoint replaces it. The caller is responsible for closing it.
+func (c *Cache) OpenState() (*os.File, *CachedCheckpoint, error) {
+	return c.open((*CachedCheckpoint).statePath)
+}
+
+// OpenBlock opens the file holding the cached block. The file can still be read after a newer
+// checkpoint replaces it. The caller is responsible for closing it.
+func (c *Cache) OpenBlock() (*os.File, *CachedCheckpoint, error) {
+	return c.open((*CachedCheckpoint).blockPath)
+}
+
+// OpenBlockByRoot opens the file holding the cached block with the given root, which is the block of either the
+// current checkpoint or the checkpoint it replaced. This pins the block to a state that was downloaded before.
+// ErrNotCached is returned if neither checkpoint has that block root. The caller is responsible for closing the file.
+func (c *Cache) OpenBlockByRoot(root [32]byte) (*os.File, *CachedCheckpoint, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	for _, cp := range []*CachedCheckpoint{c.current, c.previous} {
+		if cp != nil && cp.BlockRoot == root {
+			return openCheckpointFile(cp, cp.blockPath(c.dir))
+		}
+	}
+	return nil, nil, ErrNotCached
+}
+
+func (c *Cache) open(path func(*CachedCheckpoint, string) string) (*os.File, *CachedCheckpoint, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.current == nil {
+		return nil, nil, ErrNotCached
+	}
+	return openCheckpointFile(c.current, path(c.current, c.dir))
+}
+
+func openCheckpointFile(cp *CachedCheckpoint, path string) (*os.File, *CachedCheckpoint, error) {
+	f, err := os.Open(path) // #nosec G304
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not open cached checkpoint file")
+	}
+	cpy := *cp
+	return f, &cpy, nil
+}
+
+// prune removes the files of all checkpoints other than the given ones.
+func (c *Cache) prune(keep ...*CachedCheckpoint) {
+	files, err := filepath.Glob(filepath.Join(c.dir, cacheFilesGlob))
+	if err != nil {
+		log.WithError(err).Warn("Could not list checkpoint cache files")
+		return
+	}
+	kept := make(map[string]bool)
+	for _, cp := range keep {
+		if cp != nil {
+			kept[cp.statePath(c.dir)] = true
+			kept[cp.blockPath(c.dir)] = true
+		}
+	}
+	for _, f := range files {
+		if kept[f] {
+			continue
+		}
+		base := filepath.Base(f)
+		if !strings.HasPrefix(base, statePrefix) && !strings.HasPrefix(base, blockPrefix) {
+			continue
+		}
+		if err := os.Remove(f); err != nil {
+			log.WithError(err).WithField("path", f).Warn("Could not remove stale checkpoint cache file")
+		}
+	}
+}
+
+// writeAtomic writes data to a partial file before renaming it, so that the file at path is always complete.
+func writeAtomic(path string, data []byte) error {
+	partPath := path + partialExt
+	if err := file.WriteFile(partPath, data); err != nil {
+		return err
+	}
+	return os.Rename(partPath, path)
+}
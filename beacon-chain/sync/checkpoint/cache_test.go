@@ -0,0 +1,78 @@
+package checkpoint
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func TestCache(t *testing.T) {
+	dir := t.TempDir()
+	c, err := NewCache(dir)
+	require.NoError(t, err)
+	assert.Equal(t, (*CachedCheckpoint)(nil), c.Current())
+	_, _, err = c.OpenState()
+	require.ErrorIs(t, err, ErrNotCached)
+
+	first := &CachedCheckpoint{Epoch: 10, Version: "deneb", BlockRoot: [32]byte{'b', 1}, StateRoot: [32]byte{'s', 1}}
+	require.NoError(t, c.Save(first, []byte("state 1"), []byte("block 1")))
+	f, cp, err := c.OpenState()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, f.Close())
+	}()
+	assert.Equal(t, first.StateETag(), cp.StateETag())
+
+	second := &CachedCheckpoint{Epoch: 11, Version: "deneb", BlockRoot: [32]byte{'b', 2}, StateRoot: [32]byte{'s', 2}}
+	require.NoError(t, c.Save(second, []byte("state 2"), []byte("block 2")))
+	// The file of the previous checkpoint can still be read after it has been replaced.
+	b, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, "state 1", string(b))
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	assert.Equal(t, 5, len(files))
+
+	// The block of the replaced checkpoint can still be opened by its root, to match a state downloaded before.
+	pf, cp, err := c.OpenBlockByRoot(first.BlockRoot)
+	require.NoError(t, err)
+	b, err = io.ReadAll(pf)
+	require.NoError(t, err)
+	require.NoError(t, pf.Close())
+	assert.Equal(t, "block 1", string(b))
+	assert.Equal(t, first.BlockETag(), cp.BlockETag())
+
+	// Only the current and the replaced checkpoint are kept.
+	third := &CachedCheckpoint{Epoch: 12, Version: "deneb", BlockRoot: [32]byte{'b', 3}, StateRoot: [32]byte{'s', 3}}
+	require.NoError(t, c.Save(third, []byte("state 3"), []byte("block 3")))
+	_, _, err = c.OpenBlockByRoot(first.BlockRoot)
+	require.ErrorIs(t, err, ErrNotCached)
+	files, err = filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	assert.Equal(t, 5, len(files))
+
+	// A new cache picks up the checkpoint saved by the previous one.
+	c, err = NewCache(dir)
+	require.NoError(t, err)
+	require.NotNil(t, c.Current())
+	assert.Equal(t, third.Epoch, c.Current().Epoch)
+	bf, cp, err := c.OpenBlock()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, bf.Close())
+	}()
+	b, err = io.ReadAll(bf)
+	require.NoError(t, err)
+	assert.Equal(t, "block 3", string(b))
+	assert.Equal(t, `"0x6203000000000000000000000000000000000000000000000000000000000000"`, cp.BlockETag())
+
+	// An incomplete cache is ignored.
+	require.NoError(t, os.Remove(third.statePath(dir)))
+	c, err = NewCache(dir)
+	require.NoError(t, err)
+	assert.Equal(t, (*CachedCheckpoint)(nil), c.Current())
+}
@@ -0,0 +1,169 @@
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/feed"
+	statefeed "github.com/prysmaticlabs/prysm/v5/beacon-chain/core/feed/state"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	ethpbv1 "github.com/prysmaticlabs/prysm/v5/proto/eth/v1"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/runtime/version"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+	"github.com/sirupsen/logrus"
+)
+
+// ServingConfig contains the dependencies of the Service keeping the checkpoint serving Cache up to date.
+type ServingConfig struct {
+	Cache               *Cache
+	StateNotifier       statefeed.Notifier
+	FinalizationFetcher blockchain.FinalizationFetcher
+	ReplayerBuilder     stategen.ReplayerBuilder
+	BeaconDB            db.ReadOnlyDatabase
+	InitialSyncComplete chan struct{}
+}
+
+// Service writes the state and block of every finalized checkpoint to the Cache, once initial sync is complete.
+type Service struct {
+	cfg    *ServingConfig
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewService creates a Service to keep the given Cache up to date.
+func NewService(ctx context.Context, cfg *ServingConfig) *Service {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Service{cfg: cfg, ctx: ctx, cancel: cancel}
+}
+
+// Start the service.
+func (s *Service) Start() {
+	go s.run()
+}
+
+// Stop the service.
+func (s *Service) Stop() error {
+	s.cancel()
+	return nil
+}
+
+// Status of the service.
+func (s *Service) Status() error {
+	return nil
+}
+
+func (s *Service) run() {
+	select {
+	case <-s.cfg.InitialSyncComplete:
+	case <-s.ctx.Done():
+		return
+	}
+
+	stateChannel := make(chan *feed.Event, 1)
+	stateSub := s.cfg.StateNotifier.StateFeed().Subscribe(stateChannel)
+	defer stateSub.Unsubscribe()
+
+	// Saving a checkpoint can take longer than an epoch on a slow disk, so only the latest
+	// finalized checkpoint is queued to avoid blocking the state feed.
+	pending := make(chan *ethpb.Checkpoint, 1)
+	go s.saveRoutine(pending)
+	queue := func(cp *ethpb.Checkpoint) {
+		select {
+		case <-pending:
+		default:
+		}
+		pending <- cp
+	}
+	queue(s.cfg.FinalizationFetcher.FinalizedCheckpt())
+
+	for {
+		select {
+		case e := <-stateChannel:
+			if e.Type != statefeed.FinalizedCheckpoint {
+				continue
+			}
+			data, ok := e.Data.(*ethpbv1.EventFinalizedCheckpoint)
+			if !ok {
+				log.Error("Event feed data is not of type *ethpbv1.EventFinalizedCheckpoint")
+				continue
+			}
+			queue(&ethpb.Checkpoint{Epoch: data.Epoch, Root: data.Block})
+		case err := <-stateSub.Err():
+			log.WithError(err).Error("Could not subscribe to state feed")
+			return
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Service) saveRoutine(pending chan *ethpb.Checkpoint) {
+	for {
+		select {
+		case cp := <-pending:
+			if err := s.save(s.ctx, cp); err != nil {
+				log.WithError(err).WithField("epoch", cp.Epoch).Error("Could not cache finalized checkpoint for serving")
+			}
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// save writes the state at the start of the epoch of the finalized checkpoint, along with the checkpoint block,
+// to the Cache. This is the same state served for the "finalized" state id by the beacon API.
+func (s *Service) save(ctx context.Context, cp *ethpb.Checkpoint) error {
+	root := bytesutil.ToBytes32(cp.Root)
+	if cur := s.cfg.Cache.Current(); cur != nil && cur.Epoch >= cp.Epoch {
+		return nil
+	}
+	slot, err := slots.EpochStart(cp.Epoch)
+	if err != nil {
+		return err
+	}
+	st, err := s.cfg.ReplayerBuilder.ReplayerForSlot(slot).ReplayToSlot(ctx, slot)
+	if err != nil {
+		return errors.Wrapf(err, "could not replay finalized state at slot %d", slot)
+	}
+	blk, err := s.cfg.BeaconDB.Block(ctx, root)
+	if err != nil {
+		return errors.Wrapf(err, "could not get finalized block %#x", root)
+	}
+	if err := blocks.BeaconBlockIsNil(blk); err != nil {
+		return err
+	}
+	sb, err := st.MarshalSSZ()
+	if err != nil {
+		return errors.Wrap(err, "could not marshal finalized state")
+	}
+	bb, err := blk.MarshalSSZ()
+	if err != nil {
+		return errors.Wrap(err, "could not marshal finalized block")
+	}
+	sr, err := st.HashTreeRoot(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not compute finalized state root")
+	}
+	cached := &CachedCheckpoint{
+		Epoch:     cp.Epoch,
+		Version:   version.String(st.Version()),
+		BlockRoot: root,
+		StateRoot: sr,
+	}
+	if err := s.cfg.Cache.Save(cached, sb, bb); err != nil {
+		return err
+	}
+	log.WithFields(logrus.Fields{
+		"epoch":     cp.Epoch,
+		"blockRoot": fmt.Sprintf("%#x", root),
+		"stateRoot": fmt.Sprintf("%#x", sr),
+		"stateSize": len(sb),
+	}).Debug("Cached finalized checkpoint for serving")
+	return nil
+}
@@ -28,6 +28,12 @@ var (
 		Usage: "A MEV builder relay string http endpoint, this will be used to interact MEV builder network using API defined in: https://ethereum.github.io/builder-specs/#/Builder",
 		Value: "",
 	}
+	// BuilderRelayAllowList defines the relays that validators may restrict their builder bids to.
+	BuilderRelayAllowList = &cli.StringSliceFlag{
+		Name: "builder-relay-allowlist",
+		Usage: "Relay URLs that validators may restrict their builder bids to with the relays of their proposer settings. " +
+			"Prepare beacon proposer requests with any other relay are rejected.",
+	}
 	MaxBuilderConsecutiveMissedSlots = &cli.IntFlag{
 		Name:  "max-builder-consecutive-missed-slots",
 		Usage: "Number of consecutive skip slot to fallback from using relay/builder to local execution engine for block construction",
@@ -153,6 +159,36 @@ var (
 		Value:   strings.Join(DefaultHTTPCorsDomains, ", "),
 		Aliases: []string{"grpc-gateway-corsdomain"},
 	}
+	// HTTPServerAuthConfig enables authentication of the HTTP API with the clients of a YAML file.
+	HTTPServerAuthConfig = &cli.StringFlag{
+		Name: "http-auth-config",
+		Usage: "Path to a YAML file listing the clients allowed to call the HTTP API, identified by a bearer token or " +
+			"by the common name of their TLS client certificate, with their scopes (read-only, validator, debug, admin) " +
+			"and rate limits. The HTTP API is open to everyone who can reach it when not set.",
+	}
+	// HTTPServerTLSCert defines a flag for the HTTP server's TLS certificate.
+	HTTPServerTLSCert = &cli.StringFlag{
+		Name:  "http-tls-cert",
+		Usage: "Certificate for serving the HTTP API over HTTPS. Pass this and the http-tls-key flag in order to use HTTPS.",
+	}
+	// HTTPServerTLSKey defines a flag for the HTTP server's TLS key.
+	HTTPServerTLSKey = &cli.StringFlag{
+		Name:  "http-tls-key",
+		Usage: "Key for serving the HTTP API over HTTPS. Pass this and the http-tls-cert flag in order to use HTTPS.",
+	}
+	// HTTPResponseCacheSize sets the size of the cache of HTTP API responses computed from finalized states.
+	HTTPResponseCacheSize = &cli.Uint64Flag{
+		Name: "http-response-cache-size",
+		Usage: "Maximum size in megabytes of the cache of the HTTP API responses computed from finalized states, such as " +
+			"validators, committees and rewards, which otherwise regenerate historical states on every request. " +
+			"The cache is disabled when set to 0.",
+	}
+	// HTTPServerTLSClientCA defines a flag for the CAs of the TLS client certificates accepted by the HTTP server.
+	HTTPServerTLSClientCA = &cli.StringFlag{
+		Name: "http-tls-client-ca",
+		Usage: "PEM file of the CA certificates of the TLS client certificates accepted by the HTTP server, " +
+			"so that clients of the http-auth-config file can authenticate with a client certificate. Requires HTTPS.",
+	}
 
 	// MinSyncPeers specifies the required number of successful peer handshakes in order
 	// to start syncing with external peers.
@@ -52,6 +52,11 @@ var appFlags = []cli.Flag{
 	flags.HTTPServerHost,
 	flags.HTTPServerPort,
 	flags.HTTPServerCorsDomain,
+	flags.HTTPServerAuthConfig,
+	flags.HTTPServerTLSCert,
+	flags.HTTPServerTLSKey,
+	flags.HTTPServerTLSClientCA,
+	flags.HTTPResponseCacheSize,
 	flags.MinSyncPeers,
 	flags.ContractDeploymentBlock,
 	flags.SetGCPercent,
@@ -75,6 +80,7 @@ var appFlags = []cli.Flag{
 	flags.TerminalBlockHashOverride,
 	flags.TerminalBlockHashActivationEpochOverride,
 	flags.MevRelayEndpoint,
+	flags.BuilderRelayAllowList,
 	flags.MaxBuilderEpochMissedSlots,
 	flags.MaxBuilderConsecutiveMissedSlots,
 	flags.EngineEndpointTimeoutSeconds,
@@ -96,12 +102,16 @@ var appFlags = []cli.Flag{
 	cmd.P2PHost,
 	cmd.P2PHostDNS,
 	cmd.P2PMaxPeers,
+	cmd.P2PMaxClientRatio,
+	cmd.P2PMaxIPSubnetPeers,
 	cmd.P2PPrivKey,
 	cmd.P2PStaticID,
 	cmd.P2PMetadata,
 	cmd.P2PAllowList,
 	cmd.P2PDenyList,
 	cmd.PubsubQueueSize,
+	cmd.PubsubMeshDegree,
+	cmd.PubsubIDontWantThreshold,
 	cmd.DataDirFlag,
 	cmd.VerbosityFlag,
 	cmd.EnableTracingFlag,
@@ -136,14 +146,19 @@ var appFlags = []cli.Flag{
 	checkpoint.BlockPath,
 	checkpoint.StatePath,
 	checkpoint.RemoteURL,
+	checkpoint.Quorum,
+	checkpoint.Serve,
 	genesis.StatePath,
 	genesis.BeaconAPIURL,
 	flags.SlasherDirFlag,
 	flags.JwtId,
 	storage.BlobStoragePathFlag,
 	storage.BlobRetentionEpochFlag,
+	storage.BlobArchiveFlag,
+	storage.BlobArchiveProviderFlag,
 	bflags.EnableExperimentalBackfill,
 	bflags.BackfillBatchSize,
+	bflags.BackfillMaxBatchSize,
 	bflags.BackfillWorkerCount,
 	bflags.BackfillOldestSlot,
 }
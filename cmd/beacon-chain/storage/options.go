@@ -24,6 +24,16 @@ var (
 		Value:   uint64(params.BeaconConfig().MinEpochsForBlobsSidecarsRequest),
 		Aliases: []string{"extend-blob-retention-epoch"},
 	}
+	// BlobArchiveFlag enables archive mode, keeping all blobs and backfilling blobs older than the retention period.
+	BlobArchiveFlag = &cli.BoolFlag{
+		Name:  "blob-archive",
+		Usage: "Keep blob sidecars forever instead of pruning them after the retention period, and download historical blob sidecars back to the Deneb fork.",
+	}
+	// BlobArchiveProviderFlag sets a beacon API to download historical blob sidecars from when peers don't have them.
+	BlobArchiveProviderFlag = &cli.StringFlag{
+		Name:  "blob-archive-provider",
+		Usage: "URL of a beacon API serving historical blob sidecars (e.g. another archive node), used by --blob-archive when peers don't serve them.",
+	}
 )
 
 // BeaconNodeOptions sets configuration values on the node.BeaconNode value at node startup.
@@ -37,7 +47,14 @@ func BeaconNodeOptions(c *cli.Context) ([]node.Option, error) {
 	}
 	opts := []node.Option{node.WithBlobStorageOptions(
 		filesystem.WithBlobRetentionEpochs(e), filesystem.WithBasePath(blobStoragePath(c)),
+		filesystem.WithBlobArchive(c.Bool(BlobArchiveFlag.Name)),
 	)}
+	if c.IsSet(BlobArchiveProviderFlag.Name) {
+		if !c.Bool(BlobArchiveFlag.Name) {
+			return nil, errors.Errorf("--%s requires --%s", BlobArchiveProviderFlag.Name, BlobArchiveFlag.Name)
+		}
+		opts = append(opts, node.WithBlobArchiveProvider(c.String(BlobArchiveProviderFlag.Name)))
+	}
 	return opts, nil
 }
 
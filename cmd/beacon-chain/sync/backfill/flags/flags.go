@@ -5,8 +5,9 @@ import (
 )
 
 var (
-	backfillBatchSizeName   = "backfill-batch-size"
-	backfillWorkerCountName = "backfill-worker-count"
+	backfillBatchSizeName    = "backfill-batch-size"
+	backfillMaxBatchSizeName = "backfill-max-batch-size"
+	backfillWorkerCountName  = "backfill-worker-count"
 
 	// EnableExperimentalBackfill enables backfill for checkpoint synced nodes.
 	// This flag will be removed once backfill is enabled by default.
@@ -24,6 +25,15 @@ var (
 			"hold batches in memory during processing. This has a multiplicative effect with " + backfillWorkerCountName + ".",
 		Value: 32,
 	}
+	// BackfillMaxBatchSize bounds the batch size that backfill grows to when peers keep up with requests.
+	BackfillMaxBatchSize = &cli.Uint64Flag{
+		Name: backfillMaxBatchSizeName,
+		Usage: "Upper bound for the number of blocks per backfill batch. " +
+			"Backfill starts with " + backfillBatchSizeName + " blocks per batch, then grows or shrinks batches based on " +
+			"the observed download speed and error rate of peers. Set this to the value of " + backfillBatchSizeName +
+			" to use a fixed batch size. Values above MAX_REQUEST_BLOCKS_DENEB are capped.",
+		Value: 128,
+	}
 	// BackfillWorkerCount allows users to tune the number of concurrent backfill batches to download, to maximize
 	// network utilization at the cost of higher memory.
 	BackfillWorkerCount = &cli.IntFlag{
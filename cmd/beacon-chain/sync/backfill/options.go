@@ -14,6 +14,7 @@ func BeaconNodeOptions(c *cli.Context) ([]node.Option, error) {
 	opt := func(node *node.BeaconNode) (err error) {
 		bno := []backfill.ServiceOption{
 			backfill.WithBatchSize(c.Uint64(flags.BackfillBatchSize.Name)),
+			backfill.WithMaxBatchSize(c.Uint64(flags.BackfillMaxBatchSize.Name)),
 			backfill.WithWorkerCount(c.Int(flags.BackfillWorkerCount.Name)),
 			backfill.WithEnableBackfill(c.Bool(flags.EnableExperimentalBackfill.Name)),
 		}
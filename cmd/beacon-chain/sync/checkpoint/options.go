@@ -2,13 +2,21 @@ package checkpoint
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/node"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/sync/checkpoint"
+	"github.com/prysmaticlabs/prysm/v5/cmd"
+	"github.com/prysmaticlabs/prysm/v5/cmd/beacon-chain/flags"
 	"github.com/urfave/cli/v2"
 )
 
+// cacheDirName is the directory within the data directory holding the checkpoint serving cache.
+const cacheDirName = "checkpoint"
+
 var (
 	// StatePath defines a flag to start the beacon chain from a give genesis state file.
 	StatePath = &cli.PathFlag{
@@ -25,22 +33,63 @@ var (
 	RemoteURL = &cli.StringFlag{
 		Name: "checkpoint-sync-url",
 		Usage: "URL of a synced beacon node to trust in obtaining checkpoint sync data. " +
+			"Several comma separated URLs can be given, in which case --checkpoint-sync-quorum of them must agree on the " +
+			"finalized checkpoint before it is downloaded. " +
 			"As an additional safety measure, it is strongly recommended to only use this option in conjunction with " +
 			"--weak-subjectivity-checkpoint flag",
 	}
+	// Serve enables caching the finalized state and block on disk for serving checkpoint sync.
+	Serve = &cli.BoolFlag{
+		Name: "checkpoint-serving",
+		Usage: "Caches the ssz-encoded latest finalized state and block on disk once synced, and serves them " +
+			"with support for resumable downloads at /prysm/v1/beacon/checkpoint/state and /prysm/v1/beacon/checkpoint/block.",
+	}
+	// Quorum is the number of checkpoint sync providers that must agree on the finalized checkpoint.
+	Quorum = &cli.IntFlag{
+		Name: "checkpoint-sync-quorum",
+		Usage: "Number of the beacon nodes given to --checkpoint-sync-url that must report the same finalized checkpoint " +
+			"for checkpoint sync to proceed. Defaults to a majority of them.",
+	}
 )
 
 // BeaconNodeOptions is responsible for determining if the checkpoint sync options have been used, and if so,
 // reading the block and state ssz-serialized values from the filesystem locations specified and preparing a
 // checkpoint.Initializer, which uses the provided io.ReadClosers to initialize the beacon node database.
+// It also sets up the checkpoint serving cache when --checkpoint-serving is used.
 func BeaconNodeOptions(c *cli.Context) ([]node.Option, error) {
+	opts, err := initializerOptions(c)
+	if err != nil {
+		return nil, err
+	}
+	if c.Bool(Serve.Name) {
+		dir := filepath.Join(c.String(cmd.DataDirFlag.Name), cacheDirName)
+		opts = append(opts, func(node *node.BeaconNode) (err error) {
+			node.CheckpointCache, err = checkpoint.NewCache(dir)
+			if err != nil {
+				return errors.Wrap(err, "error preparing checkpoint serving cache")
+			}
+			return nil
+		})
+	}
+	return opts, nil
+}
+
+func initializerOptions(c *cli.Context) ([]node.Option, error) {
 	blockPath := c.Path(BlockPath.Name)
 	statePath := c.Path(StatePath.Name)
-	remoteURL := c.String(RemoteURL.Name)
-	if remoteURL != "" {
+	remoteURLs := RemoteURLs(c)
+	if len(remoteURLs) > 0 {
+		ws, err := helpers.ParseWeakSubjectivityInputString(c.String(flags.WeakSubjectivityCheckpoint.Name))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid --%s value", flags.WeakSubjectivityCheckpoint.Name)
+		}
+		quorum := c.Int(Quorum.Name)
+		if quorum < 0 || quorum > len(remoteURLs) {
+			return nil, fmt.Errorf("--%s must not be negative or exceed the number of checkpoint sync urls (%d)", Quorum.Name, len(remoteURLs))
+		}
 		opt := func(node *node.BeaconNode) error {
 			var err error
-			node.CheckpointInitializer, err = checkpoint.NewAPIInitializer(remoteURL)
+			node.CheckpointInitializer, err = checkpoint.NewAPIInitializer(remoteURLs, quorum, ws)
 			if err != nil {
 				return errors.Wrap(err, "error while constructing beacon node api client for checkpoint sync")
 			}
@@ -68,3 +117,14 @@ func BeaconNodeOptions(c *cli.Context) ([]node.Option, error) {
 	}
 	return []node.Option{opt}, nil
 }
+
+// RemoteURLs returns the checkpoint sync urls given to the comma separated --checkpoint-sync-url flag.
+func RemoteURLs(c *cli.Context) []string {
+	var urls []string
+	for _, u := range strings.Split(c.String(RemoteURL.Name), ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
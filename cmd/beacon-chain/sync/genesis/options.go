@@ -30,9 +30,9 @@ var (
 func BeaconNodeOptions(c *cli.Context) ([]node.Option, error) {
 	statePath := c.Path(StatePath.Name)
 	remoteURL := c.String(BeaconAPIURL.Name)
-	if remoteURL == "" && c.String(checkpoint.RemoteURL.Name) != "" {
-		log.Infof("using checkpoint sync url %s for value in --%s flag", c.String(checkpoint.RemoteURL.Name), BeaconAPIURL.Name)
-		remoteURL = c.String(checkpoint.RemoteURL.Name)
+	if cpURLs := checkpoint.RemoteURLs(c); remoteURL == "" && len(cpURLs) > 0 {
+		log.Infof("using checkpoint sync url %s for value in --%s flag", cpURLs[0], BeaconAPIURL.Name)
+		remoteURL = cpURLs[0]
 	}
 	if remoteURL != "" {
 		opt := func(node *node.BeaconNode) error {
@@ -106,6 +106,11 @@ var appHelpFlagGroups = []flagGroup{
 			flags.HTTPServerHost,
 			flags.HTTPServerPort,
 			flags.HTTPServerCorsDomain,
+			flags.HTTPServerAuthConfig,
+			flags.HTTPServerTLSCert,
+			flags.HTTPServerTLSKey,
+			flags.HTTPServerTLSClientCA,
+			flags.HTTPResponseCacheSize,
 			flags.ExecutionEngineEndpoint,
 			flags.ExecutionEngineHeaders,
 			flags.ExecutionJWTSecretFlag,
@@ -125,6 +130,7 @@ var appHelpFlagGroups = []flagGroup{
 			flags.MinPeersPerSubnet,
 			flags.MaxConcurrentDials,
 			flags.MevRelayEndpoint,
+			flags.BuilderRelayAllowList,
 			flags.MaxBuilderEpochMissedSlots,
 			flags.MaxBuilderConsecutiveMissedSlots,
 			flags.EngineEndpointTimeoutSeconds,
@@ -136,13 +142,18 @@ var appHelpFlagGroups = []flagGroup{
 			checkpoint.BlockPath,
 			checkpoint.StatePath,
 			checkpoint.RemoteURL,
+			checkpoint.Quorum,
+			checkpoint.Serve,
 			genesis.StatePath,
 			genesis.BeaconAPIURL,
 			storage.BlobStoragePathFlag,
 			storage.BlobRetentionEpochFlag,
+			storage.BlobArchiveFlag,
+			storage.BlobArchiveProviderFlag,
 			backfill.EnableExperimentalBackfill,
 			backfill.BackfillWorkerCount,
 			backfill.BackfillBatchSize,
+			backfill.BackfillMaxBatchSize,
 			backfill.BackfillOldestSlot,
 		},
 	},
@@ -162,12 +173,16 @@ var appHelpFlagGroups = []flagGroup{
 			cmd.P2PHost,
 			cmd.P2PHostDNS,
 			cmd.P2PMaxPeers,
+			cmd.P2PMaxClientRatio,
+			cmd.P2PMaxIPSubnetPeers,
 			cmd.P2PPrivKey,
 			cmd.P2PStaticID,
 			cmd.P2PMetadata,
 			cmd.P2PAllowList,
 			cmd.P2PDenyList,
 			cmd.PubsubQueueSize,
+			cmd.PubsubMeshDegree,
+			cmd.PubsubIDontWantThreshold,
 			cmd.StaticPeers,
 			cmd.EnableUPnPFlag,
 			flags.MinSyncPeers,
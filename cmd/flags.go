@@ -170,6 +170,21 @@ var (
 		Usage: "The max number of p2p peers to maintain.",
 		Value: 70,
 	}
+	// P2PMaxClientRatio defines a flag to cap the proportion of peers running the same client.
+	P2PMaxClientRatio = &cli.Float64Flag{
+		Name: "p2p-max-client-ratio",
+		Usage: "The max proportion of the peer limit which may run the same client, between 0 and 1. Peers beyond " +
+			"the cap are pruned first. The default of 0 disables the cap.",
+		Value: 0,
+	}
+	// P2PMaxIPSubnetPeers defines a flag to cap the number of peers from the same IP subnet.
+	P2PMaxIPSubnetPeers = &cli.UintFlag{
+		Name: "p2p-max-ip-subnet-peers",
+		Usage: "The max number of peers from the same public /24 IPv4 or /48 IPv6 subnet. Peers beyond the cap are " +
+			"pruned first, and no more peers from a full subnet are dialed. Only these address prefixes are capped, " +
+			"peers are not grouped by autonomous system (ASN). The default of 0 disables the cap.",
+		Value: 0,
+	}
 	// P2PAllowList defines a CIDR subnet to exclusively allow connections.
 	P2PAllowList = &cli.StringFlag{
 		Name: "p2p-allowlist",
@@ -191,6 +206,20 @@ var (
 		Usage: "The size of the pubsub validation and outbound queue for the node.",
 		Value: 1000,
 	}
+	// PubsubMeshDegree defines a flag to set the target number of peers in the gossipsub mesh of a topic.
+	PubsubMeshDegree = &cli.UintFlag{
+		Name: "pubsub-mesh-degree",
+		Usage: "The target number of peers in the gossipsub mesh of every topic, at least 4. The low and high " +
+			"watermarks are scaled along. Lowering it reduces duplicate messages at the cost of propagation latency.",
+		Value: 8,
+	}
+	// PubsubIDontWantThreshold defines a flag to set the message size above which gossipsub IDONTWANT messages are sent.
+	PubsubIDontWantThreshold = &cli.UintFlag{
+		Name: "pubsub-idontwant-threshold",
+		Usage: "The size in bytes above which received gossip messages are announced to mesh peers supporting " +
+			"gossipsub v1.2 with IDONTWANT, so that they do not send duplicates of them.",
+		Value: 1024,
+	}
 	// ForceClearDB removes any previously stored data at the data directory.
 	ForceClearDB = &cli.BoolFlag{
 		Name:  "force-clear-db",
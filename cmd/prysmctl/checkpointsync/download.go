@@ -7,13 +7,15 @@ import (
 
 	"github.com/prysmaticlabs/prysm/v5/api/client"
 	"github.com/prysmaticlabs/prysm/v5/api/client/beacon"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/helpers"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 )
 
 var downloadFlags = struct {
-	BeaconNodeHost string
-	Timeout        time.Duration
+	Quorum           int
+	WeakSubjectivity string
+	Timeout          time.Duration
 }{}
 
 var downloadCmd = &cli.Command{
@@ -27,11 +29,20 @@ var downloadCmd = &cli.Command{
 		return nil
 	},
 	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "beacon-node-host",
+			Usage: "host:port for beacon node connection, can be repeated to download from several untrusted beacon nodes",
+			Value: cli.NewStringSlice("localhost:3500"),
+		},
+		&cli.IntFlag{
+			Name:        "quorum",
+			Usage:       "number of beacon nodes that must report the same finalized checkpoint. default: a majority of them",
+			Destination: &downloadFlags.Quorum,
+		},
 		&cli.StringFlag{
-			Name:        "beacon-node-host",
-			Usage:       "host:port for beacon node connection",
-			Destination: &downloadFlags.BeaconNodeHost,
-			Value:       "localhost:3500",
+			Name:        "weak-subjectivity-checkpoint",
+			Usage:       "block_root:epoch_number checkpoint the finalized checkpoint must be consistent with",
+			Destination: &downloadFlags.WeakSubjectivity,
 		},
 		&cli.DurationFlag{
 			Name:        "http-timeout",
@@ -42,12 +53,21 @@ var downloadCmd = &cli.Command{
 	},
 }
 
-func cliActionDownload(_ *cli.Context) error {
+func cliActionDownload(cliCtx *cli.Context) error {
 	ctx := context.Background()
 	f := downloadFlags
 
 	opts := []client.ClientOpt{client.WithTimeout(f.Timeout), client.WithMaxBodySize(client.MaxBodySizeState)}
-	client, err := beacon.NewClient(downloadFlags.BeaconNodeHost, opts...)
+	hosts := cliCtx.StringSlice("beacon-node-host")
+	clients := make([]*beacon.Client, len(hosts))
+	for i, host := range hosts {
+		c, err := beacon.NewClient(host, opts...)
+		if err != nil {
+			return err
+		}
+		clients[i] = c
+	}
+	ws, err := helpers.ParseWeakSubjectivityInputString(f.WeakSubjectivity)
 	if err != nil {
 		return err
 	}
@@ -57,7 +77,7 @@ func cliActionDownload(_ *cli.Context) error {
 		return err
 	}
 
-	od, err := beacon.DownloadFinalizedData(ctx, client)
+	od, err := beacon.DownloadFinalizedDataWithQuorum(ctx, clients, f.Quorum, ws)
 	if err != nil {
 		return err
 	}
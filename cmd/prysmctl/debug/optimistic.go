@@ -0,0 +1,163 @@
+package debug
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/v5/api/client"
+	"github.com/prysmaticlabs/prysm/v5/api/client/beacon"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+var optimisticFlags = struct {
+	BeaconNodeHost string
+	Timeout        time.Duration
+	StartSlot      uint64
+	EndSlot        uint64
+}{}
+
+var beaconNodeFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:        "beacon-node-host",
+		Usage:       "host:port for beacon node to query, the beacon node must not run with --disable-debug-rpc-endpoints",
+		Destination: &optimisticFlags.BeaconNodeHost,
+		Value:       "http://localhost:3500",
+	},
+	&cli.DurationFlag{
+		Name:        "http-timeout",
+		Usage:       "timeout for http requests made to beacon-node-url (uses duration format, ex: 2m31s). default: 2m",
+		Destination: &optimisticFlags.Timeout,
+		Value:       time.Minute * 2,
+	},
+}
+
+var optimisticCmd = &cli.Command{
+	Name:  "optimistic",
+	Usage: "Report the optimistically imported blocks of a beacon node, the payloads found INVALID by its execution client and the validator duties blocked by optimistic sync.",
+	Action: func(cliCtx *cli.Context) error {
+		if err := cliActionOptimisticReport(cliCtx); err != nil {
+			log.WithError(err).Fatal("Could not get optimistic sync report")
+		}
+		return nil
+	},
+	Flags: beaconNodeFlags,
+	Subcommands: []*cli.Command{
+		{
+			Name:  "revalidate",
+			Usage: "Re-submit the execution payloads of the optimistic blocks in a slot range to the execution client of a beacon node, to force their revalidation.",
+			Action: func(cliCtx *cli.Context) error {
+				if err := cliActionRevalidate(cliCtx); err != nil {
+					log.WithError(err).Fatal("Could not revalidate optimistic payloads")
+				}
+				return nil
+			},
+			Flags: append([]cli.Flag{
+				&cli.Uint64Flag{
+					Name:        "start-slot",
+					Usage:       "first slot of the range of optimistic blocks to revalidate",
+					Destination: &optimisticFlags.StartSlot,
+					Required:    true,
+				},
+				&cli.Uint64Flag{
+					Name:        "end-slot",
+					Usage:       "last slot of the range of optimistic blocks to revalidate",
+					Destination: &optimisticFlags.EndSlot,
+					Required:    true,
+				},
+			}, beaconNodeFlags...),
+		},
+	},
+}
+
+func newClient() (*beacon.Client, error) {
+	return beacon.NewClient(optimisticFlags.BeaconNodeHost, client.WithTimeout(optimisticFlags.Timeout))
+}
+
+func cliActionOptimisticReport(_ *cli.Context) error {
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	report, err := c.GetOptimisticReport(context.Background())
+	if err != nil {
+		return err
+	}
+	printOptimisticReport(os.Stdout, report)
+	return nil
+}
+
+func printOptimisticReport(w io.Writer, report *structs.GetOptimisticReportResponse) {
+	fmt.Fprintf(w, "Head slot: %s, optimistic: %t\n", report.HeadSlot, report.IsOptimistic)
+
+	fmt.Fprintf(w, "\nOptimistic ranges: %d\n", len(report.OptimisticRanges))
+	for _, r := range report.OptimisticRanges {
+		fork := "canonical"
+		if !r.Canonical {
+			fork = "non-canonical"
+		}
+		fmt.Fprintf(w, "  slots %s-%s (%s blocks, %s), %s..%s\n", r.StartSlot, r.EndSlot, r.BlockCount, fork, r.StartRoot, r.EndRoot)
+		for _, b := range r.Blocks {
+			fmt.Fprintf(w, "    slot %s root %s payload %s: %s\n", b.Slot, b.BlockRoot, b.ExecutionBlockHash, elStatus(b))
+		}
+	}
+
+	fmt.Fprintf(w, "\nInvalid payloads: %d\n", len(report.InvalidPayloads))
+	for _, p := range report.InvalidPayloads {
+		fmt.Fprintf(w, "  root %s (%s at %s): latest valid hash %s, %d blocks invalidated\n",
+			p.BlockRoot, p.Method, formatTimestamp(p.Timestamp), p.LatestValidHash, len(p.InvalidRoots))
+		for _, root := range p.InvalidRoots {
+			fmt.Fprintf(w, "    %s\n", root)
+		}
+	}
+
+	fmt.Fprintf(w, "\nDuties blocked by optimistic sync: %d\n", len(report.BlockedDuties))
+	for _, d := range report.BlockedDuties {
+		fmt.Fprintf(w, "  %s: %s times, last at %s\n", d.Duty, d.Count, formatTimestamp(d.LastTimestamp))
+	}
+}
+
+func elStatus(b *structs.OptimisticBlock) string {
+	if b.ElStatusMethod == "" {
+		return b.ElStatus
+	}
+	return fmt.Sprintf("%s (%s at %s)", b.ElStatus, b.ElStatusMethod, formatTimestamp(b.ElStatusTimestamp))
+}
+
+func formatTimestamp(ts string) string {
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ts
+	}
+	return time.Unix(sec, 0).UTC().Format(time.RFC3339)
+}
+
+func cliActionRevalidate(_ *cli.Context) error {
+	f := optimisticFlags
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	res, err := c.RevalidateOptimisticPayloads(context.Background(), primitives.Slot(f.StartSlot), primitives.Slot(f.EndSlot))
+	if err != nil {
+		return err
+	}
+	if len(res.Data) == 0 {
+		fmt.Printf("No optimistic blocks in slots %d-%d\n", f.StartSlot, f.EndSlot)
+		return nil
+	}
+	for _, r := range res.Data {
+		if r.Error != "" {
+			fmt.Printf("slot %s root %s: %s, error: %s\n", r.Slot, r.BlockRoot, r.Status, r.Error)
+			continue
+		}
+		fmt.Printf("slot %s root %s: %s\n", r.Slot, r.BlockRoot, r.Status)
+	}
+	return nil
+}
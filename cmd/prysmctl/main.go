@@ -5,7 +5,9 @@ import (
 
 	"github.com/prysmaticlabs/prysm/v5/cmd/prysmctl/checkpointsync"
 	"github.com/prysmaticlabs/prysm/v5/cmd/prysmctl/db"
+	"github.com/prysmaticlabs/prysm/v5/cmd/prysmctl/debug"
 	"github.com/prysmaticlabs/prysm/v5/cmd/prysmctl/p2p"
+	"github.com/prysmaticlabs/prysm/v5/cmd/prysmctl/state"
 	"github.com/prysmaticlabs/prysm/v5/cmd/prysmctl/testnet"
 	"github.com/prysmaticlabs/prysm/v5/cmd/prysmctl/validator"
 	"github.com/prysmaticlabs/prysm/v5/cmd/prysmctl/weaksubjectivity"
@@ -28,7 +30,9 @@ func main() {
 func init() {
 	prysmctlCommands = append(prysmctlCommands, checkpointsync.Commands...)
 	prysmctlCommands = append(prysmctlCommands, db.Commands...)
+	prysmctlCommands = append(prysmctlCommands, debug.Commands...)
 	prysmctlCommands = append(prysmctlCommands, p2p.Commands...)
+	prysmctlCommands = append(prysmctlCommands, state.Commands...)
 	prysmctlCommands = append(prysmctlCommands, testnet.Commands...)
 	prysmctlCommands = append(prysmctlCommands, weaksubjectivity.Commands...)
 	prysmctlCommands = append(prysmctlCommands, validator.Commands...)
@@ -21,6 +21,8 @@ import (
 	"github.com/prysmaticlabs/go-bitfield"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/encoder"
+	p2ptypes "github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/types"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/wrapper"
 	ecdsaprysm "github.com/prysmaticlabs/prysm/v5/crypto/ecdsa"
 	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
@@ -45,6 +47,24 @@ type client struct {
 }
 
 func newClient(beaconEndpoints []string, tcpPort, quicPort uint) (*client, error) {
+	c, err := newHostClient(tcpPort, quicPort)
+	if err != nil {
+		return nil, err
+	}
+	if len(beaconEndpoints) == 0 {
+		return nil, errors.New("no specified beacon API endpoints")
+	}
+	conn, err := grpc.Dial(beaconEndpoints[0], grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	c.beaconClient = pb.NewBeaconChainClient(conn)
+	c.nodeClient = pb.NewNodeClient(conn)
+	return c, nil
+}
+
+// newHostClient sets up a client with a libp2p host only, without beacon node API clients.
+func newHostClient(tcpPort, quicPort uint) (*client, error) {
 	ipAdd := ipAddr()
 	priv, err := privKey()
 	if err != nil {
@@ -71,20 +91,9 @@ func newClient(beaconEndpoints []string, tcpPort, quicPort uint) (*client, error
 	if err != nil {
 		return nil, errors.Wrap(err, "could not start libp2p")
 	}
-	if len(beaconEndpoints) == 0 {
-		return nil, errors.New("no specified beacon API endpoints")
-	}
-	conn, err := grpc.Dial(beaconEndpoints[0], grpc.WithInsecure())
-	if err != nil {
-		return nil, err
-	}
-	beaconClient := pb.NewBeaconChainClient(conn)
-	nodeClient := pb.NewNodeClient(conn)
 	return &client{
-		host:         h,
-		meta:         meta,
-		beaconClient: beaconClient,
-		nodeClient:   nodeClient,
+		host: h,
+		meta: meta,
 	}, nil
 }
 
@@ -126,7 +135,7 @@ func (c *client) Send(
 		return nil, errors.Wrap(err, "could not open new stream")
 	}
 	// do not encode anything if we are sending a metadata request
-	if baseTopic != p2p.RPCMetaDataTopicV1 && baseTopic != p2p.RPCMetaDataTopicV2 {
+	if baseTopic != p2p.RPCMetaDataTopicV1 && baseTopic != p2p.RPCMetaDataTopicV2 && baseTopic != rpcMetaDataTopicV3 {
 		castedMsg, ok := message.(ssz.Marshaler)
 		if !ok {
 			return nil, errors.Errorf("%T does not support the ssz marshaller interface", message)
@@ -149,6 +158,38 @@ func (c *client) Send(
 	return stream, nil
 }
 
+// readStatusCode reads the response code of a response chunk, and the message of an error response. As in the
+// sync service, the response code must arrive within the TTFB timeout and the rest of the chunk within the
+// response timeout. Both deadlines are capped by the deadline of the context.
+func (c *client) readStatusCode(ctx context.Context, stream corenet.Stream) (uint8, string, error) {
+	setStreamReadDeadline(ctx, stream, params.BeaconConfig().TtfbTimeoutDuration())
+	b := make([]byte, 1)
+	if _, err := stream.Read(b); err != nil {
+		return 0, "", err
+	}
+	setStreamReadDeadline(ctx, stream, params.BeaconConfig().RespTimeoutDuration())
+	if b[0] == responseCodeSuccess {
+		return b[0], "", nil
+	}
+	msg := &p2ptypes.ErrorMessage{}
+	if err := c.Encoding().DecodeWithMaxLength(stream, msg); err != nil {
+		return 0, "", err
+	}
+	return b[0], string(*msg), nil
+}
+
+// setStreamReadDeadline sets the deadline of the next reads from a stream to the given timeout from now, or to
+// the deadline of the context if it is earlier.
+func setStreamReadDeadline(ctx context.Context, stream corenet.Stream, timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := stream.SetReadDeadline(deadline); err != nil {
+		log.WithError(err).Debug("Could not set stream read deadline")
+	}
+}
+
 func (c *client) retrievePeerAddressesViaRPC(ctx context.Context, beaconEndpoints []string) ([]string, error) {
 	if len(beaconEndpoints) == 0 {
 		return nil, errors.New("no beacon RPC endpoints specified")
@@ -0,0 +1,798 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	corenet "github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/signing"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p"
+	p2ptypes "github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/types"
+	"github.com/prysmaticlabs/prysm/v5/cmd"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/interfaces"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	pb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/runtime/version"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+	"github.com/urfave/cli/v2"
+)
+
+// rpcMetaDataTopicV3 is the metadata protocol introduced with PeerDAS, whose response includes the
+// custody subnet count of the peer. Beacon nodes do not serve it before PeerDAS is scheduled.
+const rpcMetaDataTopicV3 = "/eth2/beacon_chain/req" + p2p.MetadataMessageName + "/3"
+
+const (
+	conformancePass = "pass"
+	conformanceFail = "fail"
+	conformanceSkip = "skip"
+
+	// conformanceRangeSize is the number of slots requested by the range checks, up to the head of the peer.
+	conformanceRangeSize = 32
+)
+
+// errCheckSkipped is returned by conformance checks which do not apply to the peer.
+var errCheckSkipped = errors.New("skipped")
+
+var conformanceFlags = struct {
+	Peer           string
+	ClientPortTCP  uint
+	ClientPortQUIC uint
+	APIEndpoints   string
+	RateLimitBurst uint64
+	JSON           bool
+}{}
+
+var conformanceCmd = &cli.Command{
+	Name:  "conformance",
+	Usage: "Run a battery of req/resp checks against a beacon node via a p2p connection and report which pass",
+	Action: func(cliCtx *cli.Context) error {
+		if err := cliActionConformance(cliCtx); err != nil {
+			log.WithError(err).Fatal("Could not run conformance checks")
+		}
+		return nil
+	},
+	Flags: []cli.Flag{
+		cmd.ChainConfigFileFlag,
+		&cli.StringFlag{
+			Name:        "peer-multiaddr",
+			Usage:       "multiaddr, including the peer ID, of the beacon node to check",
+			Destination: &conformanceFlags.Peer,
+			Required:    true,
+		},
+		&cli.UintFlag{
+			Name:        "client-port-tcp",
+			Aliases:     []string{"client-port"},
+			Usage:       "TCP port to use for the client as a libp2p host",
+			Destination: &conformanceFlags.ClientPortTCP,
+			Value:       13001,
+		},
+		&cli.UintFlag{
+			Name:        "client-port-quic",
+			Usage:       "QUIC port to use for the client as a libp2p host",
+			Destination: &conformanceFlags.ClientPortQUIC,
+			Value:       13001,
+		},
+		&cli.StringFlag{
+			Name:        "prysm-api-endpoints",
+			Usage:       "comma-separated, gRPC API endpoint(s) for Prysm beacon node(s), the first one provides the status sent to the peer",
+			Destination: &conformanceFlags.APIEndpoints,
+			Value:       "localhost:4000",
+		},
+		&cli.Uint64Flag{
+			Name:        "rate-limit-burst",
+			Usage:       "number of blocks by range requests sent in a burst to check how the peer rate limits them",
+			Destination: &conformanceFlags.RateLimitBurst,
+			Value:       64,
+		},
+		&cli.BoolFlag{
+			Name:        "json",
+			Usage:       "print the report as JSON",
+			Destination: &conformanceFlags.JSON,
+		},
+	},
+}
+
+// conformanceResult is the outcome of a single conformance check.
+type conformanceResult struct {
+	Check    string `json:"check"`
+	Result   string `json:"result"`
+	Detail   string `json:"detail,omitempty"`
+	Duration string `json:"duration"`
+}
+
+type conformanceCheck struct {
+	name string
+	run  func(ctx context.Context) (string, error)
+}
+
+// conformanceRun holds the state shared by the conformance checks against a peer. Later checks build on
+// the responses to earlier ones, such as the head of the peer or the blocks it served.
+type conformanceRun struct {
+	c     *client
+	chain *mockChain
+	pid   peer.ID
+	// forkVersions maps the context bytes of response chunks to the fork versions they stand for.
+	forkVersions map[[4]byte][4]byte
+
+	peerStatus *pb.Status
+	blocks     []interfaces.ReadOnlySignedBeaconBlock
+	blobs      []*pb.BlobSidecar
+}
+
+func cliActionConformance(cliCtx *cli.Context) error {
+	if cliCtx.IsSet(cmd.ChainConfigFileFlag.Name) {
+		chainConfigFileName := cliCtx.String(cmd.ChainConfigFileFlag.Name)
+		if err := params.LoadChainConfigFile(chainConfigFileName, nil); err != nil {
+			return err
+		}
+	}
+	p2ptypes.InitializeDataMaps()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	allAPIEndpoints := make([]string, 0)
+	if conformanceFlags.APIEndpoints != "" {
+		allAPIEndpoints = strings.Split(conformanceFlags.APIEndpoints, ",")
+	}
+	c, err := newClient(allAPIEndpoints, conformanceFlags.ClientPortTCP, conformanceFlags.ClientPortQUIC)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	chain, err := c.initializeMockChainService(ctx)
+	if err != nil {
+		return err
+	}
+	c.registerHandshakeHandlers()
+
+	info, err := peer.AddrInfoFromString(conformanceFlags.Peer)
+	if err != nil {
+		return errors.Wrap(err, "could not parse peer multiaddr")
+	}
+	if err := c.host.Connect(ctx, *info); err != nil {
+		return errors.Wrap(err, "could not connect to peer")
+	}
+	forkVersions := make(map[[4]byte][4]byte)
+	for fv := range params.ConfigForkVersions(params.BeaconConfig()) {
+		digest, err := signing.ComputeForkDigest(fv[:], chain.genesisValsRoot[:])
+		if err != nil {
+			return errors.Wrapf(err, "could not compute fork digest for fork version %#x", fv)
+		}
+		forkVersions[digest] = fv
+	}
+	r := &conformanceRun{
+		c:            c,
+		chain:        chain,
+		pid:          info.ID,
+		forkVersions: forkVersions,
+	}
+
+	results := r.run(ctx)
+	failed := 0
+	for _, res := range results {
+		if res.Result == conformanceFail {
+			failed++
+		}
+	}
+	if conformanceFlags.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CHECK\tRESULT\tDURATION\tDETAIL")
+		for _, res := range results {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", res.Check, strings.ToUpper(res.Result), res.Duration, res.Detail)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("%d of %d conformance checks failed against peer %s", failed, len(results), info.ID)
+	}
+	log.WithField("peer", info.ID).Infof("All %d conformance checks passed or were skipped", len(results))
+	return nil
+}
+
+// run runs the conformance checks in order. The rate limit check comes last, as the peer may disconnect the
+// client for exceeding its rate limits, and the client reconnects after the goodbye check for it.
+func (r *conformanceRun) run(ctx context.Context) []*conformanceResult {
+	checks := []conformanceCheck{
+		{name: "status", run: r.checkStatus},
+		{name: "ping", run: r.checkPing},
+		{name: "metadata_v2", run: r.checkMetadataV2},
+		{name: "metadata_v3", run: r.checkMetadataV3},
+		{name: "blocks_by_range", run: r.checkBlocksByRange},
+		{name: "blocks_by_range_step", run: r.checkBlocksByRangeStep},
+		{name: "blocks_by_range_count_zero", run: r.checkBlocksByRangeCountZero},
+		{name: "blocks_by_range_beyond_head", run: r.checkBlocksByRangeBeyondHead},
+		{name: "blocks_by_root", run: r.checkBlocksByRoot},
+		{name: "blob_sidecars_by_range", run: r.checkBlobSidecarsByRange},
+		{name: "blob_sidecars_by_root", run: r.checkBlobSidecarsByRoot},
+		{name: "goodbye", run: r.checkGoodbye},
+		{name: "rate_limit", run: r.checkRateLimit},
+	}
+	results := make([]*conformanceResult, 0, len(checks))
+	for _, check := range checks {
+		start := time.Now()
+		detail, err := check.run(ctx)
+		res := &conformanceResult{
+			Check:    check.name,
+			Result:   conformancePass,
+			Detail:   detail,
+			Duration: time.Since(start).Round(time.Millisecond).String(),
+		}
+		switch {
+		case errors.Is(err, errCheckSkipped):
+			res.Result = conformanceSkip
+			res.Detail = strings.TrimSuffix(err.Error(), ": "+errCheckSkipped.Error())
+		case err != nil:
+			res.Result = conformanceFail
+			res.Detail = err.Error()
+		}
+		log.WithField("check", res.Check).WithField("result", res.Result).Debug("Ran conformance check")
+		results = append(results, res)
+	}
+	return results
+}
+
+func (r *conformanceRun) checkStatus(ctx context.Context) (string, error) {
+	status, err := r.c.localStatus(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "could not build local status")
+	}
+	stream, err := r.c.Send(ctx, status, p2p.RPCStatusTopicV1, r.pid)
+	if err != nil {
+		return "", err
+	}
+	defer closeStream(stream)
+	if err := r.readSingleChunk(ctx, stream); err != nil {
+		return "", err
+	}
+	resp := &pb.Status{}
+	if err := r.c.Encoding().DecodeWithMaxLength(stream, resp); err != nil {
+		return "", errors.Wrap(err, "could not decode status")
+	}
+	if bytesutil.ToBytes4(resp.ForkDigest) != bytesutil.ToBytes4(status.ForkDigest) {
+		return "", errors.Errorf("peer is on fork digest %#x, expected %#x", resp.ForkDigest, status.ForkDigest)
+	}
+	if resp.FinalizedEpoch > slots.ToEpoch(resp.HeadSlot) {
+		return "", errors.Errorf("finalized epoch %d is after the epoch of head slot %d", resp.FinalizedEpoch, resp.HeadSlot)
+	}
+	if resp.HeadSlot > r.chain.CurrentSlot()+1 {
+		return "", errors.Errorf("head slot %d is ahead of the current slot %d", resp.HeadSlot, r.chain.CurrentSlot())
+	}
+	r.peerStatus = resp
+	return fmt.Sprintf("head slot %d, finalized epoch %d", resp.HeadSlot, resp.FinalizedEpoch), nil
+}
+
+func (r *conformanceRun) checkPing(ctx context.Context) (string, error) {
+	seq := primitives.SSZUint64(r.c.MetadataSeq())
+	stream, err := r.c.Send(ctx, &seq, p2p.RPCPingTopicV1, r.pid)
+	if err != nil {
+		return "", err
+	}
+	defer closeStream(stream)
+	if err := r.readSingleChunk(ctx, stream); err != nil {
+		return "", err
+	}
+	var resp primitives.SSZUint64
+	if err := r.c.Encoding().DecodeWithMaxLength(stream, &resp); err != nil {
+		return "", errors.Wrap(err, "could not decode ping")
+	}
+	return fmt.Sprintf("metadata sequence number %d", resp), nil
+}
+
+func (r *conformanceRun) checkMetadataV2(ctx context.Context) (string, error) {
+	stream, err := r.c.Send(ctx, new(interface{}), p2p.RPCMetaDataTopicV2, r.pid)
+	if err != nil {
+		return "", err
+	}
+	defer closeStream(stream)
+	if err := r.readSingleChunk(ctx, stream); err != nil {
+		return "", err
+	}
+	resp := &pb.MetaDataV1{}
+	if err := r.c.Encoding().DecodeWithMaxLength(stream, resp); err != nil {
+		return "", errors.Wrap(err, "could not decode metadata")
+	}
+	return fmt.Sprintf("sequence number %d, attnets %#x, syncnets %#x", resp.SeqNumber, resp.Attnets, resp.Syncnets), nil
+}
+
+func (r *conformanceRun) checkMetadataV3(ctx context.Context) (string, error) {
+	if !r.supportsProtocol(rpcMetaDataTopicV3) {
+		return "", errors.Wrap(errCheckSkipped, "peer does not support the metadata v3 protocol")
+	}
+	stream, err := r.c.Send(ctx, new(interface{}), rpcMetaDataTopicV3, r.pid)
+	if err != nil {
+		return "", err
+	}
+	defer closeStream(stream)
+	if err := r.readSingleChunk(ctx, stream); err != nil {
+		return "", err
+	}
+	resp := &pb.MetaDataV2{}
+	if err := r.c.Encoding().DecodeWithMaxLength(stream, resp); err != nil {
+		return "", errors.Wrap(err, "could not decode metadata")
+	}
+	return fmt.Sprintf("sequence number %d, custody subnet count %d", resp.SeqNumber, resp.CustodySubnetCount), nil
+}
+
+func (r *conformanceRun) checkBlocksByRange(ctx context.Context) (string, error) {
+	if r.peerStatus == nil {
+		return "", errors.Wrap(errCheckSkipped, "no peer head, the status check failed")
+	}
+	req := &pb.BeaconBlocksByRangeRequest{StartSlot: r.rangeStart(), Count: conformanceRangeSize, Step: 1}
+	blks, code, errMsg, err := r.requestBlocksByRange(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	if code != responseCodeSuccess {
+		return "", errors.Errorf("peer responded with error code %d: %s", code, errMsg)
+	}
+	if len(blks) == 0 && r.peerStatus.HeadSlot > 0 {
+		return "", errors.Errorf("no blocks in slots [%d, %d] up to the head of the peer", req.StartSlot, r.peerStatus.HeadSlot)
+	}
+	r.blocks = blks
+	return fmt.Sprintf("%d blocks from slot %d", len(blks), req.StartSlot), nil
+}
+
+// checkBlocksByRangeStep checks a request with a step greater than 1, which is deprecated: peers may respond
+// with blocks at step increments, with a single block, or with an error response.
+func (r *conformanceRun) checkBlocksByRangeStep(ctx context.Context) (string, error) {
+	if r.peerStatus == nil {
+		return "", errors.Wrap(errCheckSkipped, "no peer head, the status check failed")
+	}
+	req := &pb.BeaconBlocksByRangeRequest{StartSlot: r.rangeStart(), Count: conformanceRangeSize / 2, Step: 2}
+	blks, code, errMsg, err := r.requestBlocksByRange(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	if code != responseCodeSuccess {
+		return fmt.Sprintf("rejected with error code %d: %s", code, errMsg), nil
+	}
+	return fmt.Sprintf("%d blocks from slot %d with step %d", len(blks), req.StartSlot, req.Step), nil
+}
+
+func (r *conformanceRun) checkBlocksByRangeCountZero(ctx context.Context) (string, error) {
+	if r.peerStatus == nil {
+		return "", errors.Wrap(errCheckSkipped, "no peer head, the status check failed")
+	}
+	return r.expectNoBlocks(ctx, &pb.BeaconBlocksByRangeRequest{StartSlot: r.rangeStart(), Count: 0, Step: 1})
+}
+
+func (r *conformanceRun) checkBlocksByRangeBeyondHead(ctx context.Context) (string, error) {
+	// Start well after the current slot, for blocks produced while the checks run not to be in range.
+	start := r.chain.CurrentSlot() + params.BeaconConfig().SlotsPerEpoch
+	return r.expectNoBlocks(ctx, &pb.BeaconBlocksByRangeRequest{StartSlot: start, Count: conformanceRangeSize, Step: 1})
+}
+
+// checkBlocksByRoot requests the blocks served by range by their roots, along with an unknown root.
+func (r *conformanceRun) checkBlocksByRoot(ctx context.Context) (string, error) {
+	if len(r.blocks) == 0 {
+		return "", errors.Wrap(errCheckSkipped, "no blocks were served by range to request by root")
+	}
+	requested := make(map[[32]byte]bool, len(r.blocks))
+	req := make(p2ptypes.BeaconBlockByRootsReq, 0, len(r.blocks)+1)
+	for _, blk := range r.blocks {
+		root, err := blk.Block().HashTreeRoot()
+		if err != nil {
+			return "", err
+		}
+		requested[root] = true
+		req = append(req, root)
+	}
+	unknownRoot := bytesutil.ToBytes32([]byte("prysmctl p2p conformance"))
+	req = append(req, unknownRoot)
+
+	topic, err := p2p.TopicFromMessage(p2p.BeaconBlocksByRootsMessageName, slots.ToEpoch(r.chain.CurrentSlot()))
+	if err != nil {
+		return "", err
+	}
+	stream, err := r.c.Send(ctx, &req, topic, r.pid)
+	if err != nil {
+		return "", err
+	}
+	defer closeStream(stream)
+	served := 0
+	code, errMsg, err := r.readChunks(ctx, stream, func(forkVersion [4]byte) error {
+		blk, err := r.decodeBlock(stream, forkVersion)
+		if err != nil {
+			return err
+		}
+		root, err := blk.Block().HashTreeRoot()
+		if err != nil {
+			return err
+		}
+		if !requested[root] {
+			return errors.Errorf("block %#x at slot %d was not requested or was served twice", root, blk.Block().Slot())
+		}
+		delete(requested, root)
+		served++
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if code != responseCodeSuccess {
+		return "", errors.Errorf("peer responded with error code %d: %s", code, errMsg)
+	}
+	if len(requested) > 0 {
+		return "", errors.Errorf("%d of %d blocks known to the peer were not served", len(requested), len(r.blocks))
+	}
+	return fmt.Sprintf("%d blocks, unknown root omitted", served), nil
+}
+
+func (r *conformanceRun) checkBlobSidecarsByRange(ctx context.Context) (string, error) {
+	if r.peerStatus == nil {
+		return "", errors.Wrap(errCheckSkipped, "no peer head, the status check failed")
+	}
+	denebStart, err := slots.EpochStart(params.BeaconConfig().DenebForkEpoch)
+	if err != nil || r.peerStatus.HeadSlot < denebStart {
+		return "", errors.Wrap(errCheckSkipped, "the head of the peer is before deneb")
+	}
+	req := &pb.BlobSidecarsByRangeRequest{StartSlot: max(r.rangeStart(), denebStart), Count: conformanceRangeSize}
+	topic, err := p2p.TopicFromMessage(p2p.BlobSidecarsByRangeName, slots.ToEpoch(r.chain.CurrentSlot()))
+	if err != nil {
+		return "", err
+	}
+	stream, err := r.c.Send(ctx, req, topic, r.pid)
+	if err != nil {
+		return "", err
+	}
+	defer closeStream(stream)
+	sidecars, code, errMsg, err := r.readBlobSidecarsByRange(ctx, stream, req)
+	if err != nil {
+		return "", err
+	}
+	if code != responseCodeSuccess {
+		return "", errors.Errorf("peer responded with error code %d: %s", code, errMsg)
+	}
+	r.blobs = sidecars
+	return fmt.Sprintf("%d blob sidecars from slot %d", len(r.blobs), req.StartSlot), nil
+}
+
+// readBlobSidecarsByRange reads the response to a blob sidecars by range request, and validates that the blob
+// sidecars served are within the requested range, in ascending slot and index order, and on a fork with blobs.
+func (r *conformanceRun) readBlobSidecarsByRange(
+	ctx context.Context, stream corenet.Stream, req *pb.BlobSidecarsByRangeRequest,
+) ([]*pb.BlobSidecar, byte, string, error) {
+	var sidecars []*pb.BlobSidecar
+	code, errMsg, err := r.readChunks(ctx, stream, func(forkVersion [4]byte) error {
+		sidecar, err := r.decodeBlobSidecar(stream, forkVersion)
+		if err != nil {
+			return err
+		}
+		slot := sidecar.SignedBlockHeader.Header.Slot
+		if slot < req.StartSlot || slot >= req.StartSlot.Add(req.Count) {
+			return errors.Errorf("blob sidecar at slot %d is out of the requested range", slot)
+		}
+		if len(sidecars) > 0 {
+			prev := sidecars[len(sidecars)-1]
+			prevSlot := prev.SignedBlockHeader.Header.Slot
+			if slot < prevSlot || (slot == prevSlot && sidecar.Index <= prev.Index) {
+				return errors.Errorf("blob sidecar %d at slot %d is not ordered after blob sidecar %d at slot %d",
+					sidecar.Index, slot, prev.Index, prevSlot)
+			}
+		}
+		sidecars = append(sidecars, sidecar)
+		return nil
+	})
+	return sidecars, code, errMsg, err
+}
+
+// checkBlobSidecarsByRoot requests the blob sidecars served by range by their identifiers, along with an
+// unknown identifier.
+func (r *conformanceRun) checkBlobSidecarsByRoot(ctx context.Context) (string, error) {
+	if len(r.blobs) == 0 {
+		return "", errors.Wrap(errCheckSkipped, "no blob sidecars were served by range to request by root")
+	}
+	requested := make(map[[32]byte]map[uint64]bool)
+	req := make(p2ptypes.BlobSidecarsByRootReq, 0, len(r.blobs)+1)
+	for _, sidecar := range r.blobs {
+		root, err := sidecar.SignedBlockHeader.Header.HashTreeRoot()
+		if err != nil {
+			return "", err
+		}
+		if requested[root] == nil {
+			requested[root] = make(map[uint64]bool)
+		}
+		requested[root][sidecar.Index] = true
+		req = append(req, &pb.BlobIdentifier{BlockRoot: root[:], Index: sidecar.Index})
+	}
+	unknownRoot := bytesutil.ToBytes32([]byte("prysmctl p2p conformance"))
+	req = append(req, &pb.BlobIdentifier{BlockRoot: unknownRoot[:], Index: 0})
+
+	topic, err := p2p.TopicFromMessage(p2p.BlobSidecarsByRootName, slots.ToEpoch(r.chain.CurrentSlot()))
+	if err != nil {
+		return "", err
+	}
+	stream, err := r.c.Send(ctx, &req, topic, r.pid)
+	if err != nil {
+		return "", err
+	}
+	defer closeStream(stream)
+	served := 0
+	code, errMsg, err := r.readChunks(ctx, stream, func(forkVersion [4]byte) error {
+		sidecar, err := r.decodeBlobSidecar(stream, forkVersion)
+		if err != nil {
+			return err
+		}
+		root, err := sidecar.SignedBlockHeader.Header.HashTreeRoot()
+		if err != nil {
+			return err
+		}
+		if !requested[root][sidecar.Index] {
+			return errors.Errorf("blob sidecar %d of block %#x was not requested or was served twice", sidecar.Index, root)
+		}
+		delete(requested[root], sidecar.Index)
+		served++
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if code != responseCodeSuccess {
+		return "", errors.Errorf("peer responded with error code %d: %s", code, errMsg)
+	}
+	if served != len(r.blobs) {
+		return "", errors.Errorf("%d of %d blob sidecars known to the peer were not served", len(r.blobs)-served, len(r.blobs))
+	}
+	return fmt.Sprintf("%d blob sidecars, unknown identifier omitted", served), nil
+}
+
+// checkRateLimit sends a burst of blocks by range requests. Each request must either be served or rejected
+// with an error response. The peer may disconnect once it rejected requests, but not before.
+func (r *conformanceRun) checkRateLimit(ctx context.Context) (string, error) {
+	if r.peerStatus == nil {
+		return "", errors.Wrap(errCheckSkipped, "no peer head, the status check failed")
+	}
+	if r.c.host.Network().Connectedness(r.pid) != corenet.Connected {
+		if err := r.c.host.Connect(ctx, r.c.host.Peerstore().PeerInfo(r.pid)); err != nil {
+			return "", errors.Wrap(err, "could not reconnect to peer")
+		}
+	}
+	topic, err := p2p.TopicFromMessage(p2p.BeaconBlocksByRangeMessageName, slots.ToEpoch(r.chain.CurrentSlot()))
+	if err != nil {
+		return "", err
+	}
+	req := &pb.BeaconBlocksByRangeRequest{StartSlot: r.rangeStart(), Count: conformanceRangeSize, Step: 1}
+	served, rejected := 0, 0
+	var rejection string
+	for i := uint64(0); i < conformanceFlags.RateLimitBurst; i++ {
+		if rejected > 0 && r.c.host.Network().Connectedness(r.pid) != corenet.Connected {
+			return fmt.Sprintf("%d requests served, %d rejected with %s, then disconnected", served, rejected, rejection), nil
+		}
+		stream, err := r.c.Send(ctx, req, topic, r.pid)
+		if err != nil {
+			return "", errors.Wrapf(err, "request %d of the burst", i+1)
+		}
+		code, errMsg, err := r.c.readStatusCode(ctx, stream)
+		if err := stream.Reset(); err != nil {
+			log.WithError(err).Debug("Could not reset stream")
+		}
+		switch {
+		case errors.Is(err, io.EOF):
+			served++
+		case err != nil:
+			return "", errors.Wrapf(err, "request %d of the burst", i+1)
+		case code == responseCodeSuccess:
+			served++
+		default:
+			rejected++
+			rejection = fmt.Sprintf("error code %d: %s", code, errMsg)
+		}
+	}
+	if rejected == 0 {
+		return fmt.Sprintf("all %d requests served", served), nil
+	}
+	return fmt.Sprintf("%d requests served, %d rejected with %s", served, rejected, rejection), nil
+}
+
+func (r *conformanceRun) checkGoodbye(ctx context.Context) (string, error) {
+	code := p2ptypes.GoodbyeCodeClientShutdown
+	stream, err := r.c.Send(ctx, &code, p2p.RPCGoodByeTopicV1, r.pid)
+	if err != nil {
+		return "", err
+	}
+	closeStream(stream)
+	// The sender of a goodbye disconnects, the peer may also disconnect on receiving it.
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if r.c.host.Network().Connectedness(r.pid) != corenet.Connected {
+			return "peer disconnected", nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if err := r.c.host.Network().ClosePeer(r.pid); err != nil {
+		return "", errors.Wrap(err, "could not disconnect from peer")
+	}
+	return "peer accepted the goodbye and kept the connection open", nil
+}
+
+// rangeStart is the start slot of range requests, for them to end at the head of the peer.
+func (r *conformanceRun) rangeStart() primitives.Slot {
+	if r.peerStatus.HeadSlot < conformanceRangeSize {
+		return 0
+	}
+	return r.peerStatus.HeadSlot - conformanceRangeSize + 1
+}
+
+// requestBlocksByRange requests blocks by range, and validates that the blocks served are within the requested
+// range, in ascending slot order, at step increments, and on the fork their context bytes stand for.
+func (r *conformanceRun) requestBlocksByRange(
+	ctx context.Context, req *pb.BeaconBlocksByRangeRequest,
+) ([]interfaces.ReadOnlySignedBeaconBlock, byte, string, error) {
+	topic, err := p2p.TopicFromMessage(p2p.BeaconBlocksByRangeMessageName, slots.ToEpoch(r.chain.CurrentSlot()))
+	if err != nil {
+		return nil, 0, "", err
+	}
+	stream, err := r.c.Send(ctx, req, topic, r.pid)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	defer closeStream(stream)
+	return r.readBlocksByRange(ctx, stream, req)
+}
+
+// readBlocksByRange reads the response to a blocks by range request, and validates the blocks served as
+// described in requestBlocksByRange.
+func (r *conformanceRun) readBlocksByRange(
+	ctx context.Context, stream corenet.Stream, req *pb.BeaconBlocksByRangeRequest,
+) ([]interfaces.ReadOnlySignedBeaconBlock, byte, string, error) {
+	var blks []interfaces.ReadOnlySignedBeaconBlock
+	code, errMsg, err := r.readChunks(ctx, stream, func(forkVersion [4]byte) error {
+		blk, err := r.decodeBlock(stream, forkVersion)
+		if err != nil {
+			return err
+		}
+		slot := blk.Block().Slot()
+		if uint64(len(blks)) >= req.Count {
+			return errors.Errorf("more than the %d requested blocks were served", req.Count)
+		}
+		if slot < req.StartSlot || slot >= req.StartSlot.Add(req.Count*req.Step) {
+			return errors.Errorf("block at slot %d is out of the requested range", slot)
+		}
+		if len(blks) > 0 {
+			prevSlot := blks[len(blks)-1].Block().Slot()
+			if slot <= prevSlot || slot.SubSlot(prevSlot).Mod(req.Step) != 0 {
+				return errors.Errorf("block at slot %d does not follow block at slot %d with step %d", slot, prevSlot, req.Step)
+			}
+		}
+		blks = append(blks, blk)
+		return nil
+	})
+	return blks, code, errMsg, err
+}
+
+// expectNoBlocks requests blocks by range which the peer must not serve, either responding with no
+// blocks or with an error response.
+func (r *conformanceRun) expectNoBlocks(ctx context.Context, req *pb.BeaconBlocksByRangeRequest) (string, error) {
+	blks, code, errMsg, err := r.requestBlocksByRange(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	if len(blks) > 0 {
+		return "", errors.Errorf("%d blocks were served", len(blks))
+	}
+	if code != responseCodeSuccess {
+		return fmt.Sprintf("rejected with error code %d: %s", code, errMsg), nil
+	}
+	return "no blocks served", nil
+}
+
+// readSingleChunk reads the response code of a single chunk response, which must be successful.
+func (r *conformanceRun) readSingleChunk(ctx context.Context, stream corenet.Stream) error {
+	code, errMsg, err := r.c.readStatusCode(ctx, stream)
+	if err != nil {
+		return errors.Wrap(err, "could not read response code")
+	}
+	if code != responseCodeSuccess {
+		return errors.Errorf("peer responded with error code %d: %s", code, errMsg)
+	}
+	return nil
+}
+
+// readChunks reads the chunks of a response until the stream ends. The payload of each successful chunk is
+// decoded by the given function, from the fork version its context bytes stand for. Reading stops at the
+// first error response, whose code and message are returned. Each chunk must start within the TTFB timeout
+// and be read within the response timeout, so that a silent peer fails the check.
+func (r *conformanceRun) readChunks(ctx context.Context, stream corenet.Stream, decode func(forkVersion [4]byte) error) (byte, string, error) {
+	for {
+		code, errMsg, err := r.c.readStatusCode(ctx, stream)
+		if errors.Is(err, io.EOF) {
+			return responseCodeSuccess, "", nil
+		}
+		if err != nil {
+			return 0, "", errors.Wrap(err, "could not read response code")
+		}
+		if code != responseCodeSuccess {
+			return code, errMsg, nil
+		}
+		contextBytes := [4]byte{}
+		if _, err := io.ReadFull(stream, contextBytes[:]); err != nil {
+			return 0, "", errors.Wrap(err, "could not read context bytes")
+		}
+		forkVersion, ok := r.forkVersions[contextBytes]
+		if !ok {
+			return 0, "", errors.Errorf("unknown context bytes %#x", contextBytes)
+		}
+		if err := decode(forkVersion); err != nil {
+			return 0, "", err
+		}
+	}
+}
+
+func (r *conformanceRun) decodeBlock(stream corenet.Stream, forkVersion [4]byte) (interfaces.ReadOnlySignedBeaconBlock, error) {
+	newBlock, ok := p2ptypes.BlockMap[forkVersion]
+	if !ok {
+		return nil, errors.Errorf("no block type for fork version %#x", forkVersion)
+	}
+	blk, err := newBlock()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.c.Encoding().DecodeWithMaxLength(stream, blk); err != nil {
+		return nil, errors.Wrap(err, "could not decode block")
+	}
+	if err := blocks.BeaconBlockIsNil(blk); err != nil {
+		return nil, err
+	}
+	if v := slots.ToForkVersion(blk.Block().Slot()); v != blk.Version() {
+		return nil, errors.Errorf("block at slot %d is a %s block, expected a %s block",
+			blk.Block().Slot(), version.String(blk.Version()), version.String(v))
+	}
+	return blk, nil
+}
+
+func (r *conformanceRun) decodeBlobSidecar(stream corenet.Stream, forkVersion [4]byte) (*pb.BlobSidecar, error) {
+	if v := params.ConfigForkVersions(params.BeaconConfig())[forkVersion]; v < version.Deneb {
+		return nil, errors.Errorf("blob sidecar with the context bytes of a %s fork", version.String(v))
+	}
+	sidecar := &pb.BlobSidecar{}
+	if err := r.c.Encoding().DecodeWithMaxLength(stream, sidecar); err != nil {
+		return nil, errors.Wrap(err, "could not decode blob sidecar")
+	}
+	if sidecar.SignedBlockHeader == nil || sidecar.SignedBlockHeader.Header == nil {
+		return nil, errors.New("blob sidecar has no block header")
+	}
+	slot := sidecar.SignedBlockHeader.Header.Slot
+	if sidecar.Index >= uint64(params.BeaconConfig().MaxBlobsPerBlock(slot)) {
+		return nil, errors.Errorf("blob sidecar index %d at slot %d is out of bounds", sidecar.Index, slot)
+	}
+	return sidecar, nil
+}
+
+// supportsProtocol returns true if the peer advertises the given protocol, waiting for the peer to be
+// identified if it was not yet.
+func (r *conformanceRun) supportsProtocol(baseTopic string) bool {
+	topic := protocol.ID(baseTopic + r.c.Encoding().ProtocolSuffix())
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		protocols, err := r.c.host.Peerstore().GetProtocols(r.pid)
+		if err == nil && len(protocols) > 0 {
+			supported, err := r.c.host.Peerstore().SupportsProtocols(r.pid, topic)
+			return err == nil && len(supported) > 0
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,346 @@
+package p2p
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	corenet "github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	ssz "github.com/prysmaticlabs/fastssz"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/encoder"
+	p2ptest "github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/testing"
+	p2ptypes "github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/types"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	pb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+var (
+	phase0ContextBytes = [4]byte{0x01, 0x02, 0x03, 0x04}
+	denebContextBytes  = [4]byte{0x05, 0x06, 0x07, 0x08}
+)
+
+func testConformanceRun() *conformanceRun {
+	p2ptypes.InitializeDataMaps()
+	cfg := params.BeaconConfig()
+	return &conformanceRun{
+		c: &client{},
+		forkVersions: map[[4]byte][4]byte{
+			phase0ContextBytes: bytesutil.ToBytes4(cfg.GenesisForkVersion),
+			denebContextBytes:  bytesutil.ToBytes4(cfg.DenebForkVersion),
+		},
+	}
+}
+
+// responseStream opens a stream to a peer which responds with the given bytes and closes the stream. A nil
+// response stands for a silent peer, which keeps the stream open without responding.
+func responseStream(t *testing.T, resp []byte) corenet.Stream {
+	p1 := p2ptest.NewTestP2P(t)
+	p2 := p2ptest.NewTestP2P(t)
+	p1.Connect(p2)
+	pcl := protocol.ID("/testing")
+	p2.BHost.SetStreamHandler(pcl, func(stream corenet.Stream) {
+		if resp == nil {
+			return
+		}
+		_, err := stream.Write(resp)
+		assert.NoError(t, err)
+		assert.NoError(t, stream.Close())
+	})
+	stream, err := p1.BHost.NewStream(context.Background(), p2.PeerID(), pcl)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = stream.Reset()
+	})
+	return stream
+}
+
+// successChunk encodes a successful response chunk with the given context bytes and payload.
+func successChunk(t *testing.T, contextBytes [4]byte, msg ssz.Marshaler) []byte {
+	buf := bytes.NewBuffer([]byte{responseCodeSuccess})
+	buf.Write(contextBytes[:])
+	if msg != nil {
+		_, err := (&encoder.SszNetworkEncoder{}).EncodeWithMaxLength(buf, msg)
+		require.NoError(t, err)
+	}
+	return buf.Bytes()
+}
+
+func errorChunk(t *testing.T, code byte, errMsg string) []byte {
+	buf := bytes.NewBuffer([]byte{code})
+	msg := p2ptypes.ErrorMessage(errMsg)
+	_, err := (&encoder.SszNetworkEncoder{}).EncodeWithMaxLength(buf, &msg)
+	require.NoError(t, err)
+	return buf.Bytes()
+}
+
+func TestConformance_ReadChunks(t *testing.T) {
+	cfg := params.BeaconConfig()
+	tests := []struct {
+		name         string
+		resp         []byte
+		timeout      time.Duration
+		forkVersions [][4]byte
+		code         byte
+		errMsg       string
+		wantErr      string
+	}{
+		{
+			name: "empty response",
+			resp: []byte{},
+		},
+		{
+			name: "context bytes mapped to fork versions",
+			resp: bytes.Join([][]byte{
+				successChunk(t, phase0ContextBytes, nil),
+				successChunk(t, denebContextBytes, nil),
+				successChunk(t, phase0ContextBytes, nil),
+			}, nil),
+			forkVersions: [][4]byte{
+				bytesutil.ToBytes4(cfg.GenesisForkVersion),
+				bytesutil.ToBytes4(cfg.DenebForkVersion),
+				bytesutil.ToBytes4(cfg.GenesisForkVersion),
+			},
+		},
+		{
+			name:         "unknown context bytes",
+			resp:         append(successChunk(t, phase0ContextBytes, nil), successChunk(t, [4]byte{0xff}, nil)...),
+			forkVersions: [][4]byte{bytesutil.ToBytes4(cfg.GenesisForkVersion)},
+			wantErr:      "unknown context bytes 0xff000000",
+		},
+		{
+			name:    "truncated context bytes",
+			resp:    []byte{responseCodeSuccess, 0x01, 0x02},
+			wantErr: "could not read context bytes",
+		},
+		{
+			name:         "error response after a chunk",
+			resp:         append(successChunk(t, denebContextBytes, nil), errorChunk(t, 0x03, "rate limited")...),
+			forkVersions: [][4]byte{bytesutil.ToBytes4(cfg.DenebForkVersion)},
+			code:         0x03,
+			errMsg:       "rate limited",
+		},
+		{
+			name:    "silent peer",
+			timeout: 500 * time.Millisecond,
+			wantErr: "could not read response code",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testConformanceRun()
+			stream := responseStream(t, tt.resp)
+			ctx := context.Background()
+			if tt.timeout != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			var forkVersions [][4]byte
+			start := time.Now()
+			code, errMsg, err := r.readChunks(ctx, stream, func(forkVersion [4]byte) error {
+				forkVersions = append(forkVersions, forkVersion)
+				return nil
+			})
+			if tt.wantErr != "" {
+				require.ErrorContains(t, tt.wantErr, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.code, code)
+				assert.Equal(t, tt.errMsg, errMsg)
+			}
+			assert.DeepEqual(t, tt.forkVersions, forkVersions)
+			if tt.timeout != 0 {
+				assert.Equal(t, true, time.Since(start) < tt.timeout+time.Second, "read was not bounded by the deadline")
+			}
+		})
+	}
+}
+
+func TestConformance_ReadSingleChunk_SilentPeer(t *testing.T) {
+	r := testConformanceRun()
+	stream := responseStream(t, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	require.ErrorContains(t, "could not read response code", r.readSingleChunk(ctx, stream))
+}
+
+func TestConformance_ReadBlocksByRange(t *testing.T) {
+	blockChunks := func(slots ...primitives.Slot) []byte {
+		var resp []byte
+		for _, slot := range slots {
+			blk := util.NewBeaconBlock()
+			blk.Block.Slot = slot
+			resp = append(resp, successChunk(t, phase0ContextBytes, blk)...)
+		}
+		return resp
+	}
+	tests := []struct {
+		name    string
+		req     *pb.BeaconBlocksByRangeRequest
+		resp    []byte
+		slots   []primitives.Slot
+		code    byte
+		wantErr string
+	}{
+		{
+			name:  "blocks in range",
+			req:   &pb.BeaconBlocksByRangeRequest{StartSlot: 10, Count: 4, Step: 1},
+			resp:  blockChunks(10, 11, 13),
+			slots: []primitives.Slot{10, 11, 13},
+		},
+		{
+			name:  "blocks at step increments",
+			req:   &pb.BeaconBlocksByRangeRequest{StartSlot: 10, Count: 4, Step: 2},
+			resp:  blockChunks(10, 12, 16),
+			slots: []primitives.Slot{10, 12, 16},
+		},
+		{
+			name:    "block before the range",
+			req:     &pb.BeaconBlocksByRangeRequest{StartSlot: 10, Count: 4, Step: 1},
+			resp:    blockChunks(9),
+			wantErr: "block at slot 9 is out of the requested range",
+		},
+		{
+			name:    "block after the range",
+			req:     &pb.BeaconBlocksByRangeRequest{StartSlot: 10, Count: 4, Step: 2},
+			resp:    blockChunks(10, 18),
+			wantErr: "block at slot 18 is out of the requested range",
+		},
+		{
+			name:    "block off the step",
+			req:     &pb.BeaconBlocksByRangeRequest{StartSlot: 10, Count: 4, Step: 2},
+			resp:    blockChunks(10, 11),
+			wantErr: "block at slot 11 does not follow block at slot 10 with step 2",
+		},
+		{
+			name:    "blocks out of order",
+			req:     &pb.BeaconBlocksByRangeRequest{StartSlot: 10, Count: 4, Step: 1},
+			resp:    blockChunks(11, 10),
+			wantErr: "block at slot 10 does not follow block at slot 11 with step 1",
+		},
+		{
+			name:    "duplicate block",
+			req:     &pb.BeaconBlocksByRangeRequest{StartSlot: 10, Count: 4, Step: 1},
+			resp:    blockChunks(10, 10),
+			wantErr: "block at slot 10 does not follow block at slot 10 with step 1",
+		},
+		{
+			name:    "more blocks than requested",
+			req:     &pb.BeaconBlocksByRangeRequest{StartSlot: 10, Count: 2, Step: 1},
+			resp:    blockChunks(10, 11, 12),
+			wantErr: "more than the 2 requested blocks were served",
+		},
+		{
+			name:    "block with the context bytes of another fork",
+			req:     &pb.BeaconBlocksByRangeRequest{StartSlot: 10, Count: 4, Step: 1},
+			resp:    successChunk(t, denebContextBytes, util.NewBeaconBlock()),
+			wantErr: "could not decode block",
+		},
+		{
+			name:  "error response",
+			req:   &pb.BeaconBlocksByRangeRequest{StartSlot: 10, Count: 4, Step: 1},
+			resp:  append(blockChunks(10), errorChunk(t, 0x02, "server error")...),
+			slots: []primitives.Slot{10},
+			code:  0x02,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testConformanceRun()
+			blks, code, _, err := r.readBlocksByRange(context.Background(), responseStream(t, tt.resp), tt.req)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, tt.wantErr, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.code, code)
+			slots := make([]primitives.Slot, 0, len(blks))
+			for _, blk := range blks {
+				slots = append(slots, blk.Block().Slot())
+			}
+			assert.DeepEqual(t, tt.slots, slots)
+		})
+	}
+}
+
+func TestConformance_ReadBlobSidecarsByRange(t *testing.T) {
+	type blobID struct {
+		slot  primitives.Slot
+		index uint64
+	}
+	sidecarChunks := func(contextBytes [4]byte, ids ...blobID) []byte {
+		var resp []byte
+		for _, id := range ids {
+			sidecar := util.HydrateBlobSidecar(&pb.BlobSidecar{
+				Index: id.index,
+				SignedBlockHeader: util.HydrateSignedBeaconHeader(&pb.SignedBeaconBlockHeader{
+					Header: &pb.BeaconBlockHeader{Slot: id.slot},
+				}),
+			})
+			resp = append(resp, successChunk(t, contextBytes, sidecar)...)
+		}
+		return resp
+	}
+	req := &pb.BlobSidecarsByRangeRequest{StartSlot: 20, Count: 4}
+	tests := []struct {
+		name    string
+		resp    []byte
+		ids     []blobID
+		wantErr string
+	}{
+		{
+			name: "ordered by slot and index",
+			resp: sidecarChunks(denebContextBytes, blobID{20, 0}, blobID{20, 1}, blobID{22, 0}),
+			ids:  []blobID{{20, 0}, {20, 1}, {22, 0}},
+		},
+		{
+			name:    "indices out of order",
+			resp:    sidecarChunks(denebContextBytes, blobID{20, 1}, blobID{20, 0}),
+			wantErr: "blob sidecar 0 at slot 20 is not ordered after blob sidecar 1 at slot 20",
+		},
+		{
+			name:    "slots out of order",
+			resp:    sidecarChunks(denebContextBytes, blobID{21, 0}, blobID{20, 1}),
+			wantErr: "blob sidecar 1 at slot 20 is not ordered after blob sidecar 0 at slot 21",
+		},
+		{
+			name:    "duplicate blob sidecar",
+			resp:    sidecarChunks(denebContextBytes, blobID{20, 0}, blobID{20, 0}),
+			wantErr: "blob sidecar 0 at slot 20 is not ordered after blob sidecar 0 at slot 20",
+		},
+		{
+			name:    "blob sidecar after the range",
+			resp:    sidecarChunks(denebContextBytes, blobID{24, 0}),
+			wantErr: "blob sidecar at slot 24 is out of the requested range",
+		},
+		{
+			name:    "blob sidecar with the context bytes of a fork without blobs",
+			resp:    sidecarChunks(phase0ContextBytes, blobID{20, 0}),
+			wantErr: "blob sidecar with the context bytes of a phase0 fork",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testConformanceRun()
+			sidecars, code, _, err := r.readBlobSidecarsByRange(context.Background(), responseStream(t, tt.resp), req)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, tt.wantErr, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, responseCodeSuccess, code)
+			ids := make([]blobID, 0, len(sidecars))
+			for _, sidecar := range sidecars {
+				ids = append(ids, blobID{sidecar.SignedBlockHeader.Header.Slot, sidecar.Index})
+			}
+			assert.DeepEqual(t, tt.ids, ids)
+		})
+	}
+}
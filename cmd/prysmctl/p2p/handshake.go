@@ -42,40 +42,49 @@ func (c *client) goodbyeHandler(_ context.Context, _ interface{}, _ libp2pcore.S
 // This handler will disconnect any peer that does not match our fork version.
 func (c *client) statusRPCHandler(ctx context.Context, _ interface{}, stream libp2pcore.Stream) error {
 	defer closeStream(stream)
-	chainHead, err := c.beaconClient.GetChainHead(ctx, &emptypb.Empty{})
+	status, err := c.localStatus(ctx)
 	if err != nil {
 		return err
 	}
+	log.WithField("forkDigest", status.ForkDigest).Info("Responding to status RPC handler")
+
+	if _, err := stream.Write([]byte{responseCodeSuccess}); err != nil {
+		log.WithError(err).Debug("Could not write to stream")
+		return err
+	}
+	_, err = c.Encoding().EncodeWithMaxLength(stream, status)
+	return err
+}
+
+// localStatus builds the status of the client from the chain head of the beacon node backing it.
+func (c *client) localStatus(ctx context.Context) (*pb.Status, error) {
+	chainHead, err := c.beaconClient.GetChainHead(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, err
+	}
 	resp, err := c.nodeClient.GetGenesis(ctx, &emptypb.Empty{})
 	if err != nil {
-		return err
+		return nil, err
 	}
 	digest, err := forks.CreateForkDigest(resp.GenesisTime.AsTime(), resp.GenesisValidatorsRoot)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	kindOfFork, err := forks.Fork(slots.ToEpoch(chainHead.HeadSlot))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	log.WithFields(logrus.Fields{
 		"genesisTime":  resp.GenesisTime.AsTime(),
 		"forkDigest":   digest,
 		"currentFork":  kindOfFork.CurrentVersion,
 		"previousFork": kindOfFork.PreviousVersion,
-	}).Info("Responding to status RPC handler")
-	status := &pb.Status{
+	}).Debug("Built status from beacon node chain head")
+	return &pb.Status{
 		ForkDigest:     digest[:],
 		FinalizedRoot:  chainHead.FinalizedBlockRoot,
 		FinalizedEpoch: chainHead.FinalizedEpoch,
 		HeadRoot:       chainHead.HeadBlockRoot,
 		HeadSlot:       chainHead.HeadSlot,
-	}
-
-	if _, err := stream.Write([]byte{responseCodeSuccess}); err != nil {
-		log.WithError(err).Debug("Could not write to stream")
-		return err
-	}
-	_, err = c.Encoding().EncodeWithMaxLength(stream, status)
-	return err
+	}, nil
 }
@@ -0,0 +1,249 @@
+package p2p
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	apiclient "github.com/prysmaticlabs/prysm/v5/api/client"
+	"github.com/prysmaticlabs/prysm/v5/api/client/beacon"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/types"
+	"github.com/urfave/cli/v2"
+)
+
+var peersAdminFlags = struct {
+	BeaconNodeHost  string
+	Timeout         time.Duration
+	APIToken        string
+	Duration        time.Duration
+	Reason          string
+	ReasonCode      uint64
+	BadResponses    string
+	ProcessedBlocks string
+}{}
+
+var peersAdminBeaconNodeFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:        "beacon-node-host",
+		Usage:       "host:port for beacon node to manage the peers of",
+		Destination: &peersAdminFlags.BeaconNodeHost,
+		Value:       "http://localhost:3500",
+	},
+	&cli.DurationFlag{
+		Name:        "http-timeout",
+		Usage:       "timeout for http requests made to beacon-node-url (uses duration format, ex: 2m31s). default: 2m",
+		Destination: &peersAdminFlags.Timeout,
+		Value:       time.Minute * 2,
+	},
+	&cli.StringFlag{
+		Name:        "api-token",
+		Usage:       "bearer token sent to the beacon node, required if it runs with HTTP API authentication",
+		Destination: &peersAdminFlags.APIToken,
+	},
+}
+
+var peersCmd = &cli.Command{
+	Name:  "peers",
+	Usage: "commands for managing the peers of a beacon node through its HTTP API",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "bans",
+			Usage:  "List the peer IDs and IP ranges banned on a beacon node.",
+			Flags:  peersAdminBeaconNodeFlags,
+			Action: peersAdminAction("Could not list peer bans", cliActionListBans),
+		},
+		{
+			Name:      "ban",
+			Usage:     "Ban a peer ID, an IP address or an IP range in CIDR notation, and disconnect the matching peers.",
+			ArgsUsage: "<peer ID|IP|CIDR>",
+			Flags: append([]cli.Flag{
+				&cli.DurationFlag{
+					Name:        "duration",
+					Usage:       "how long the ban lasts (uses duration format, ex: 24h), bans are permanent by default",
+					Destination: &peersAdminFlags.Duration,
+				},
+				&cli.StringFlag{
+					Name:        "reason",
+					Usage:       "free text reason of the ban",
+					Destination: &peersAdminFlags.Reason,
+				},
+			}, peersAdminBeaconNodeFlags...),
+			Action: peersAdminAction("Could not ban peer", cliActionBan),
+		},
+		{
+			Name:      "unban",
+			Usage:     "Lift the ban of a peer ID, an IP address or an IP range.",
+			ArgsUsage: "<peer ID|IP|CIDR>",
+			Flags:     peersAdminBeaconNodeFlags,
+			Action:    peersAdminAction("Could not unban peer", cliActionUnban),
+		},
+		{
+			Name:      "connect",
+			Usage:     "Make a beacon node dial a peer given by its multiaddr, including the peer ID, or by its ENR.",
+			ArgsUsage: "<multiaddr|ENR>",
+			Flags:     peersAdminBeaconNodeFlags,
+			Action:    peersAdminAction("Could not connect to peer", cliActionConnect),
+		},
+		{
+			Name:      "disconnect",
+			Usage:     "Make a beacon node send a goodbye message to a connected peer and disconnect from it.",
+			ArgsUsage: "<peer ID>",
+			Flags: append([]cli.Flag{
+				&cli.Uint64Flag{
+					Name:        "reason-code",
+					Usage:       "goodbye reason code sent to the peer",
+					Destination: &peersAdminFlags.ReasonCode,
+					Value:       uint64(types.GoodbyeCodeGenericError),
+				},
+			}, peersAdminBeaconNodeFlags...),
+			Action: peersAdminAction("Could not disconnect from peer", cliActionDisconnect),
+		},
+		{
+			Name:      "score",
+			Usage:     "Show the score of a peer known to a beacon node, or override the bad responses and processed blocks it is computed from.",
+			ArgsUsage: "<peer ID>",
+			Flags: append([]cli.Flag{
+				&cli.StringFlag{
+					Name:        "set-bad-responses",
+					Usage:       "number of bad responses to set for the peer",
+					Destination: &peersAdminFlags.BadResponses,
+				},
+				&cli.StringFlag{
+					Name:        "set-processed-blocks",
+					Usage:       "number of processed blocks to set for the peer",
+					Destination: &peersAdminFlags.ProcessedBlocks,
+				},
+			}, peersAdminBeaconNodeFlags...),
+			Action: peersAdminAction("Could not manage peer score", cliActionScore),
+		},
+	},
+}
+
+func peersAdminAction(msg string, action func(*cli.Context, *beacon.Client) error) cli.ActionFunc {
+	return func(cliCtx *cli.Context) error {
+		opts := []apiclient.ClientOpt{apiclient.WithTimeout(peersAdminFlags.Timeout)}
+		if peersAdminFlags.APIToken != "" {
+			opts = append(opts, apiclient.WithAuthenticationToken(peersAdminFlags.APIToken))
+		}
+		c, err := beacon.NewClient(peersAdminFlags.BeaconNodeHost, opts...)
+		if err == nil {
+			err = action(cliCtx, c)
+		}
+		if err != nil {
+			log.WithError(err).Fatal(msg)
+		}
+		return nil
+	}
+}
+
+func singleArg(cliCtx *cli.Context, name string) (string, error) {
+	if cliCtx.NArg() != 1 {
+		return "", fmt.Errorf("expected a single %s argument, got %d arguments", name, cliCtx.NArg())
+	}
+	return cliCtx.Args().First(), nil
+}
+
+func cliActionListBans(_ *cli.Context, c *beacon.Client) error {
+	bans, err := c.ListPeerBans(context.Background())
+	if err != nil {
+		return err
+	}
+	if len(bans) == 0 {
+		fmt.Println("No bans")
+		return nil
+	}
+	for _, ban := range bans {
+		fmt.Println(formatBan(ban))
+	}
+	return nil
+}
+
+func cliActionBan(cliCtx *cli.Context, c *beacon.Client) error {
+	target, err := singleArg(cliCtx, "ban target")
+	if err != nil {
+		return err
+	}
+	res, err := c.BanPeer(context.Background(), target, peersAdminFlags.Duration, peersAdminFlags.Reason)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Banned %s\n", formatBan(res.Data))
+	if len(res.DisconnectedPeers) > 0 {
+		fmt.Printf("Disconnected peers: %s\n", strings.Join(res.DisconnectedPeers, ", "))
+	}
+	return nil
+}
+
+func cliActionUnban(cliCtx *cli.Context, c *beacon.Client) error {
+	target, err := singleArg(cliCtx, "ban target")
+	if err != nil {
+		return err
+	}
+	if err := c.UnbanPeer(context.Background(), target); err != nil {
+		return err
+	}
+	fmt.Printf("Unbanned %s\n", target)
+	return nil
+}
+
+func cliActionConnect(cliCtx *cli.Context, c *beacon.Client) error {
+	addr, err := singleArg(cliCtx, "peer address")
+	if err != nil {
+		return err
+	}
+	if err := c.ConnectPeer(context.Background(), addr); err != nil {
+		return err
+	}
+	fmt.Printf("Connected to %s\n", addr)
+	return nil
+}
+
+func cliActionDisconnect(cliCtx *cli.Context, c *beacon.Client) error {
+	pid, err := singleArg(cliCtx, "peer ID")
+	if err != nil {
+		return err
+	}
+	if err := c.DisconnectPeer(context.Background(), pid, peersAdminFlags.ReasonCode); err != nil {
+		return err
+	}
+	fmt.Printf("Disconnected from %s\n", pid)
+	return nil
+}
+
+func cliActionScore(cliCtx *cli.Context, c *beacon.Client) error {
+	pid, err := singleArg(cliCtx, "peer ID")
+	if err != nil {
+		return err
+	}
+	var score *structs.PeerScore
+	if peersAdminFlags.BadResponses != "" || peersAdminFlags.ProcessedBlocks != "" {
+		score, err = c.SetPeerScore(context.Background(), pid, &structs.SetPeerScoreRequest{
+			BadResponses:    peersAdminFlags.BadResponses,
+			ProcessedBlocks: peersAdminFlags.ProcessedBlocks,
+		})
+	} else {
+		score, err = c.GetPeerScore(context.Background(), pid)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Peer %s: score %s, bad responses %s, processed blocks %s, gossip score %s, bad: %t\n",
+		score.PeerId, score.Score, score.BadResponses, score.ProcessedBlocks, score.GossipScore, score.IsBad)
+	return nil
+}
+
+func formatBan(ban *structs.PeerBan) string {
+	s := ban.Target
+	if expiry, err := strconv.ParseInt(ban.Expiry, 10, 64); err == nil && expiry > 0 {
+		s += " until " + time.Unix(expiry, 0).UTC().Format(time.RFC3339)
+	} else {
+		s += " permanently"
+	}
+	if ban.Reason != "" {
+		s += ": " + ban.Reason
+	}
+	return s
+}
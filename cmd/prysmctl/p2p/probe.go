@@ -0,0 +1,136 @@
+package p2p
+
+import (
+	"context"
+	"time"
+
+	libp2pcore "github.com/libp2p/go-libp2p/core"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/wrapper"
+	pb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1/metadata"
+)
+
+// probeTimeout bounds the time spent dialing a peer and running the handshakes with it, including the reads of
+// the responses.
+const probeTimeout = 20 * time.Second
+
+// Prober dials peers and runs the status and metadata handshakes with them. Unlike the other
+// p2p commands, it is not backed by a beacon node: the status it sends is provided by the caller,
+// and the status requests of peers are answered with their own status.
+type Prober struct {
+	c *client
+}
+
+// ProbeResult holds what a peer responded to the handshakes.
+type ProbeResult struct {
+	AgentVersion string
+	Status       *pb.Status
+	Metadata     metadata.Metadata
+}
+
+// NewProber starts a libp2p host listening on the given ports to probe peers from.
+func NewProber(tcpPort, quicPort uint) (*Prober, error) {
+	c, err := newHostClient(tcpPort, quicPort)
+	if err != nil {
+		return nil, err
+	}
+	c.registerRPCHandler(p2p.RPCPingTopicV1, c.pingHandler)
+	c.registerRPCHandler(p2p.RPCGoodByeTopicV1, c.goodbyeHandler)
+	c.registerRPCHandler(p2p.RPCStatusTopicV1, c.echoStatusHandler)
+	return &Prober{c: c}, nil
+}
+
+// Close shuts down the libp2p host of the prober.
+func (p *Prober) Close() {
+	p.c.Close()
+}
+
+// Probe connects to a peer, sends it the given status, requests its metadata, and disconnects
+// from it.
+func (p *Prober) Probe(ctx context.Context, info peer.AddrInfo, status *pb.Status) (*ProbeResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	if err := p.c.host.Connect(ctx, info); err != nil {
+		return nil, errors.Wrap(err, "could not connect to peer")
+	}
+	defer func() {
+		if err := p.c.host.Network().ClosePeer(info.ID); err != nil {
+			log.WithError(err).Debug("Could not disconnect from peer")
+		}
+	}()
+
+	res := &ProbeResult{}
+	var err error
+	res.Status, err = p.requestStatus(ctx, info.ID, status)
+	if err != nil {
+		return nil, errors.Wrap(err, "status handshake failed")
+	}
+	res.Metadata, err = p.requestMetadata(ctx, info.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "metadata request failed")
+	}
+	if agent, err := p.c.host.Peerstore().Get(info.ID, "AgentVersion"); err == nil {
+		res.AgentVersion, _ = agent.(string)
+	}
+	return res, nil
+}
+
+func (p *Prober) requestStatus(ctx context.Context, pid peer.ID, status *pb.Status) (*pb.Status, error) {
+	stream, err := p.c.Send(ctx, status, p2p.RPCStatusTopicV1, pid)
+	if err != nil {
+		return nil, err
+	}
+	defer closeStream(stream)
+	code, errMsg, err := p.c.readStatusCode(ctx, stream)
+	if err != nil {
+		return nil, err
+	}
+	if code != responseCodeSuccess {
+		return nil, errors.Errorf("peer responded with error code %d: %s", code, errMsg)
+	}
+	resp := &pb.Status{}
+	if err := p.c.Encoding().DecodeWithMaxLength(stream, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// requestMetadata requests the altair metadata of a peer, which includes its sync committee subnets.
+func (p *Prober) requestMetadata(ctx context.Context, pid peer.ID) (metadata.Metadata, error) {
+	stream, err := p.c.Send(ctx, new(interface{}), p2p.RPCMetaDataTopicV2, pid)
+	if err != nil {
+		return nil, err
+	}
+	defer closeStream(stream)
+	code, errMsg, err := p.c.readStatusCode(ctx, stream)
+	if err != nil {
+		return nil, err
+	}
+	if code != responseCodeSuccess {
+		return nil, errors.Errorf("peer responded with error code %d: %s", code, errMsg)
+	}
+	resp := &pb.MetaDataV1{}
+	if err := p.c.Encoding().DecodeWithMaxLength(stream, resp); err != nil {
+		return nil, err
+	}
+	return wrapper.WrappedMetadataV1(resp), nil
+}
+
+// echoStatusHandler responds to a status request with the status of the requesting peer, for the peer
+// to consider the prober on the same chain as itself.
+func (c *client) echoStatusHandler(_ context.Context, msg interface{}, stream libp2pcore.Stream) error {
+	defer closeStream(stream)
+	status, ok := msg.(*pb.Status)
+	if !ok {
+		return errors.Errorf("message is of type %T, expected %T", msg, &pb.Status{})
+	}
+	if _, err := stream.Write([]byte{responseCodeSuccess}); err != nil {
+		return err
+	}
+	_, err := c.Encoding().EncodeWithMaxLength(stream, status)
+	return err
+}
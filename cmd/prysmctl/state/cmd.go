@@ -0,0 +1,13 @@
+package state
+
+import "github.com/urfave/cli/v2"
+
+var Commands = []*cli.Command{
+	{
+		Name:  "state",
+		Usage: "commands for debugging beacon state transitions",
+		Subcommands: []*cli.Command{
+			traceCmd,
+		},
+	},
+}
@@ -0,0 +1,111 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/transition"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/transition/statetrace"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/encoding/ssz/detect"
+	"github.com/prysmaticlabs/prysm/v5/io/file"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+var traceFlags = struct {
+	PreState string
+	Block    string
+	Output   string
+}{}
+
+var traceCmd = &cli.Command{
+	Name:  "trace",
+	Usage: "Apply a block to a pre-state and output the state fields changed by each step of the state transition as JSON.",
+	Action: func(cliCtx *cli.Context) error {
+		if err := cliActionTrace(cliCtx); err != nil {
+			log.WithError(err).Fatal("Could not trace state transition")
+		}
+		return nil
+	},
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:        "pre-state",
+			Usage:       "path to the ssz-encoded state the block is applied to",
+			Destination: &traceFlags.PreState,
+			Required:    true,
+		},
+		&cli.StringFlag{
+			Name:        "block",
+			Usage:       "path to the ssz-encoded signed block",
+			Destination: &traceFlags.Block,
+			Required:    true,
+		},
+		&cli.StringFlag{
+			Name:        "output",
+			Usage:       "path of the JSON trace file, the trace is printed if not set",
+			Destination: &traceFlags.Output,
+		},
+	},
+}
+
+func cliActionTrace(_ *cli.Context) error {
+	f := traceFlags
+	sb, err := os.ReadFile(f.PreState) // #nosec G304
+	if err != nil {
+		return errors.Wrap(err, "could not read pre-state")
+	}
+	vu, err := detect.FromState(sb)
+	if err != nil {
+		return errors.Wrap(err, "could not detect version of pre-state")
+	}
+	undo, err := params.SetActiveWithUndo(vu.Config)
+	if err != nil {
+		return errors.Wrap(err, "could not set beacon config of the pre-state network")
+	}
+	defer func() {
+		if err := undo(); err != nil {
+			log.WithError(err).Error("Could not restore beacon config")
+		}
+	}()
+	st, err := vu.UnmarshalBeaconState(sb)
+	if err != nil {
+		return errors.Wrap(err, "could not unmarshal pre-state")
+	}
+	bb, err := os.ReadFile(f.Block) // #nosec G304
+	if err != nil {
+		return errors.Wrap(err, "could not read block")
+	}
+	// The block may be the first block of a fork, so its version is detected from its own slot rather than the
+	// pre-state's, with the beacon config of the pre-state network.
+	bvu, err := detect.FromBlock(bb)
+	if err != nil {
+		return errors.Wrap(err, "could not detect version of block")
+	}
+	blk, err := bvu.UnmarshalBeaconBlock(bb)
+	if err != nil {
+		return errors.Wrap(err, "could not unmarshal block")
+	}
+
+	tracer := statetrace.NewTracer(st)
+	ctx := statetrace.NewContext(context.Background(), tracer)
+	_, transitionErr := transition.ExecuteStateTransition(ctx, st, blk)
+	// The trace is written even if the transition failed, as it shows the steps up to the failure.
+	enc, err := json.MarshalIndent(tracer.Trace(), "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not marshal trace")
+	}
+	if f.Output == "" {
+		if _, err := os.Stdout.Write(append(enc, '\n')); err != nil {
+			return err
+		}
+	} else {
+		if err := file.WriteFile(f.Output, enc); err != nil {
+			return errors.Wrap(err, "could not write trace")
+		}
+		log.WithField("path", f.Output).Info("Wrote state transition trace")
+	}
+	return errors.Wrap(transitionErr, "state transition failed")
+}
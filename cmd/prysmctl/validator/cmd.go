@@ -3,6 +3,7 @@ package validator
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/logrusorgru/aurora"
 	"github.com/prysmaticlabs/prysm/v5/cmd"
@@ -70,6 +71,76 @@ var (
 		Aliases: []string{"t"},
 		Usage:   "keymanager API bearer token, note: currently required but may be removed in the future, this is the same token as the web ui token.",
 	}
+
+	ExecutionHostFlag = &cli.StringFlag{
+		Name:  "execution-node-host",
+		Usage: "execution node JSON-RPC endpoint used to estimate the request fee and prepare the transaction",
+		Value: "http://127.0.0.1:8545",
+	}
+
+	SourcePubkeyFlag = &cli.StringFlag{
+		Name:  "source-pubkey",
+		Usage: "public key of the validator consolidated into the target validator",
+	}
+
+	TargetPubkeyFlag = &cli.StringFlag{
+		Name:  "target-pubkey",
+		Usage: "public key of the validator receiving the balance of the source validator, use the source public key to switch the source to compounding withdrawal credentials",
+	}
+
+	ValidatorPubkeyFlag = &cli.StringFlag{
+		Name:  "pubkey",
+		Usage: "public key of the validator to withdraw from",
+	}
+
+	AmountGweiFlag = &cli.Uint64Flag{
+		Name:  "amount-gwei",
+		Usage: "amount in Gwei to withdraw from the balance above the minimum activation balance",
+	}
+
+	FullExitFlag = &cli.BoolFlag{
+		Name:  "full-exit",
+		Usage: "requests the exit of the validator and the withdrawal of its entire balance instead of a partial withdrawal",
+	}
+
+	FromAddressFlag = &cli.StringFlag{
+		Name: "from",
+		Usage: "address sending the request transaction, defaults to the withdrawal address of the validator. " +
+			"The request only takes effect if the sender is the withdrawal address in the validator's withdrawal credentials",
+	}
+
+	PubkeysFlag = &cli.StringFlag{
+		Name:  "pubkeys",
+		Usage: "comma separated list of the public keys of the validators to plan maintenance for",
+	}
+
+	EpochsFlag = &cli.Uint64Flag{
+		Name:  "epochs",
+		Usage: "number of epochs to plan maintenance windows for, starting at the current epoch, at most 32",
+		Value: 8,
+	}
+
+	MinWindowFlag = &cli.DurationFlag{
+		Name:  "min-window",
+		Usage: "minimum duration of the suggested maintenance windows (uses duration format, ex: 10m)",
+		Value: 5 * time.Minute,
+	}
+
+	WindowsFlag = &cli.IntFlag{
+		Name:  "windows",
+		Usage: "number of maintenance windows to suggest, longest first",
+		Value: 3,
+	}
+
+	WaitFlag = &cli.BoolFlag{
+		Name:  "wait",
+		Usage: "blocks until the next suggested maintenance window opens",
+	}
+
+	PrivateKeyFileFlag = &cli.StringFlag{
+		Name:  "private-key-file",
+		Usage: "path to a file containing the hex encoded private key of the withdrawal address, used to sign the request transaction. The transaction is printed unsigned for an offline signer if not provided",
+	}
 )
 
 var Commands = []*cli.Command{
@@ -144,6 +215,75 @@ var Commands = []*cli.Command{
 					return nil
 				},
 			},
+			{
+				Name:  "consolidate",
+				Usage: "Prepare a transaction requesting the consolidation of a validator into another one, or the switch of a validator to compounding withdrawal credentials.",
+				Flags: []cli.Flag{
+					BeaconHostFlag,
+					ExecutionHostFlag,
+					SourcePubkeyFlag,
+					TargetPubkeyFlag,
+					FromAddressFlag,
+					PrivateKeyFileFlag,
+					cmd.ConfigFileFlag,
+				},
+				Before: func(cliCtx *cli.Context) error {
+					return cmd.LoadFlagsFromConfig(cliCtx, cliCtx.Command.Flags)
+				},
+				Action: func(cliCtx *cli.Context) error {
+					if err := consolidate(cliCtx); err != nil {
+						log.WithError(err).Fatal("Could not prepare consolidation request")
+					}
+					return nil
+				},
+			},
+			{
+				Name:    "partial-withdraw",
+				Aliases: []string{"pw"},
+				Usage:   "Prepare a transaction requesting a partial withdrawal, or the full exit, of a validator from its withdrawal address.",
+				Flags: []cli.Flag{
+					BeaconHostFlag,
+					ExecutionHostFlag,
+					ValidatorPubkeyFlag,
+					AmountGweiFlag,
+					FullExitFlag,
+					FromAddressFlag,
+					PrivateKeyFileFlag,
+					cmd.ConfigFileFlag,
+				},
+				Before: func(cliCtx *cli.Context) error {
+					return cmd.LoadFlagsFromConfig(cliCtx, cliCtx.Command.Flags)
+				},
+				Action: func(cliCtx *cli.Context) error {
+					if err := partialWithdraw(cliCtx); err != nil {
+						log.WithError(err).Fatal("Could not prepare withdrawal request")
+					}
+					return nil
+				},
+			},
+			{
+				Name:    "maintenance-window",
+				Aliases: []string{"mw"},
+				Usage:   "Suggest maintenance windows without block proposals or sync committee duties for a set of validators, with the attestation rewards forgone by a downtime.",
+				Flags: []cli.Flag{
+					BeaconHostFlag,
+					PubkeysFlag,
+					EpochsFlag,
+					MinWindowFlag,
+					WindowsFlag,
+					WaitFlag,
+					cmd.ConfigFileFlag,
+				},
+				Before: func(cliCtx *cli.Context) error {
+					return cmd.LoadFlagsFromConfig(cliCtx, cliCtx.Command.Flags)
+				},
+				Action: func(cliCtx *cli.Context) error {
+					if err := maintenanceWindows(cliCtx); err != nil {
+						log.WithError(err).Fatal("Could not plan maintenance windows")
+					}
+					return nil
+				},
+			},
 			{
 				Name:    "exit",
 				Aliases: []string{"e", "voluntary-exit"},
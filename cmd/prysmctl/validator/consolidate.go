@@ -0,0 +1,76 @@
+package validator
+
+import (
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	enginev1 "github.com/prysmaticlabs/prysm/v5/proto/engine/v1"
+	"github.com/prysmaticlabs/prysm/v5/validator/requests"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+func consolidate(c *cli.Context) error {
+	ctx, span := trace.StartSpan(c.Context, "withdrawal.consolidate")
+	defer span.End()
+	source, err := pubkeyFromFlag(c, SourcePubkeyFlag.Name)
+	if err != nil {
+		return err
+	}
+	target, err := pubkeyFromFlag(c, TargetPubkeyFlag.Name)
+	if err != nil {
+		return err
+	}
+
+	st, undo, err := headState(ctx, c.String(BeaconHostFlag.Name))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := undo(); err != nil {
+			log.WithError(err).Error("Could not restore beacon config")
+		}
+	}()
+	withdrawalAddress, err := requests.WithdrawalAddress(st, source)
+	if err != nil {
+		return err
+	}
+	from, key, err := requestSender(c, withdrawalAddress)
+	if err != nil {
+		return err
+	}
+	req := &enginev1.ConsolidationRequest{
+		SourceAddress: from.Bytes(),
+		SourcePubkey:  source,
+		TargetPubkey:  target,
+	}
+	outcome, err := requests.CheckConsolidation(ctx, st, req)
+	if err != nil {
+		return errors.Wrap(err, "consolidation request would be ignored by the beacon chain")
+	}
+	if outcome.SwitchToCompounding {
+		log.WithFields(log.Fields{
+			"validatorIndex":    outcome.SourceIndex,
+			"queuedBalanceGwei": outcome.QueuedBalance,
+		}).Info("Validator would switch to compounding withdrawal credentials")
+	} else {
+		log.WithFields(log.Fields{
+			"sourceIndex":       outcome.SourceIndex,
+			"targetIndex":       outcome.TargetIndex,
+			"exitEpoch":         outcome.ExitEpoch,
+			"withdrawableEpoch": outcome.WithdrawableEpoch,
+			"movedBalanceGwei":  outcome.MovedBalance,
+		}).Info("Source validator would exit and be consolidated into the target validator")
+	}
+
+	ec, err := ethclient.DialContext(ctx, c.String(ExecutionHostFlag.Name))
+	if err != nil {
+		return errors.Wrap(err, "could not connect to execution node")
+	}
+	defer ec.Close()
+	tx, err := buildRequestTransaction(ctx, ec, from, consolidationRequestContract, consolidationRequestCalldata(source, target))
+	if err != nil {
+		return err
+	}
+	return outputRequestTransaction(tx, key)
+}
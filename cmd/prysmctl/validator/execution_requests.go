@@ -0,0 +1,293 @@
+package validator
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api/client"
+	"github.com/prysmaticlabs/prysm/v5/api/client/beacon"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/encoding/ssz/detect"
+	"github.com/prysmaticlabs/prysm/v5/runtime/version"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// withdrawalRequestContract is the EIP-7002 system contract receiving withdrawal requests.
+	withdrawalRequestContract = common.HexToAddress("0x00000961Ef480Eb55e80D19ad83579A64c007002")
+	// consolidationRequestContract is the EIP-7251 system contract receiving consolidation requests.
+	consolidationRequestContract = common.HexToAddress("0x0000BBdDc7CE488642fb579F8B00f3a590007251")
+	// excessInhibitor is the excess stored by the system contracts until the fork activating them.
+	excessInhibitor = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+)
+
+// Both system contracts price requests the same way, see EIP-7002 and EIP-7251.
+const (
+	minRequestFee            = 1
+	requestFeeUpdateFraction = 17
+)
+
+// executionClient is the subset of the execution JSON-RPC API needed to build request transactions.
+type executionClient interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+}
+
+// requestTransaction is a transaction to a request system contract, in the format accepted by
+// eth_signTransaction so that it can be handed to an offline signer as is. Raw and Hash are only
+// set once the transaction is signed.
+type requestTransaction struct {
+	From                 common.Address `json:"from"`
+	To                   common.Address `json:"to"`
+	Value                *hexutil.Big   `json:"value"`
+	Input                hexutil.Bytes  `json:"input"`
+	Nonce                hexutil.Uint64 `json:"nonce"`
+	Gas                  hexutil.Uint64 `json:"gas"`
+	MaxFeePerGas         *hexutil.Big   `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big   `json:"maxPriorityFeePerGas"`
+	ChainID              *hexutil.Big   `json:"chainId"`
+	Raw                  hexutil.Bytes  `json:"raw,omitempty"`
+	Hash                 *common.Hash   `json:"hash,omitempty"`
+}
+
+// withdrawalRequestCalldata returns the input of a call to the withdrawal request contract:
+// the validator public key followed by the amount in Gwei as a big endian uint64.
+func withdrawalRequestCalldata(pubkey []byte, amount uint64) []byte {
+	data := make([]byte, fieldparams.BLSPubkeyLength+8)
+	copy(data, pubkey)
+	binary.BigEndian.PutUint64(data[fieldparams.BLSPubkeyLength:], amount)
+	return data
+}
+
+// consolidationRequestCalldata returns the input of a call to the consolidation request contract:
+// the source public key followed by the target public key.
+func consolidationRequestCalldata(source, target []byte) []byte {
+	data := make([]byte, 2*fieldparams.BLSPubkeyLength)
+	copy(data, source)
+	copy(data[fieldparams.BLSPubkeyLength:], target)
+	return data
+}
+
+// requestFee computes the fee charged by a request contract for the given excess of requests.
+//
+// Spec definition (EIP-7002):
+//
+//	def fake_exponential(factor: int, numerator: int, denominator: int) -> int:
+//	    i = 1
+//	    output = 0
+//	    numerator_accum = factor * denominator
+//	    while numerator_accum > 0:
+//	        output += numerator_accum
+//	        numerator_accum = (numerator_accum * numerator) // (denominator * i)
+//	        i += 1
+//	    return output // denominator
+func requestFee(excess *big.Int) *big.Int {
+	denominator := big.NewInt(requestFeeUpdateFraction)
+	output := new(big.Int)
+	accum := new(big.Int).Mul(big.NewInt(minRequestFee), denominator)
+	divisor := new(big.Int)
+	for i := int64(1); accum.Sign() > 0; i++ {
+		output.Add(output, accum)
+		accum.Mul(accum, excess)
+		accum.Div(accum, divisor.Mul(denominator, big.NewInt(i)))
+	}
+	return output.Div(output, denominator)
+}
+
+// estimateRequestFee reads the current excess of requests from the storage of a request contract
+// and returns the fee a request sent now has to pay.
+func estimateRequestFee(ctx context.Context, ec executionClient, contract common.Address) (*big.Int, error) {
+	slot, err := ec.StorageAt(ctx, contract, common.Hash{}, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read excess requests of contract %s", contract)
+	}
+	excess := new(big.Int).SetBytes(slot)
+	if excess.Cmp(excessInhibitor) == 0 {
+		return nil, fmt.Errorf("request contract %s is not active yet on the execution chain", contract)
+	}
+	return requestFee(excess), nil
+}
+
+// buildRequestTransaction prepares a transaction sending a request to a system contract from the
+// given address, paying the current request fee.
+func buildRequestTransaction(
+	ctx context.Context,
+	ec executionClient,
+	from, contract common.Address,
+	input []byte,
+) (*requestTransaction, error) {
+	fee, err := estimateRequestFee(ctx, ec, contract)
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := ec.ChainID(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get chain ID")
+	}
+	nonce, err := ec.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get nonce of %s", from)
+	}
+	tip, err := ec.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get gas tip")
+	}
+	head, err := ec.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get latest execution block")
+	}
+	if head.BaseFee == nil {
+		return nil, errors.New("latest execution block has no base fee")
+	}
+	gas, err := ec.EstimateGas(ctx, ethereum.CallMsg{
+		From:  from,
+		To:    &contract,
+		Value: fee,
+		Data:  input,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not estimate gas of request transaction")
+	}
+	// Leave room for the base fee to double before the transaction is included.
+	maxFee := new(big.Int).Add(new(big.Int).Mul(head.BaseFee, big.NewInt(2)), tip)
+	return &requestTransaction{
+		From:                 from,
+		To:                   contract,
+		Value:                (*hexutil.Big)(fee),
+		Input:                input,
+		Nonce:                hexutil.Uint64(nonce),
+		Gas:                  hexutil.Uint64(gas),
+		MaxFeePerGas:         (*hexutil.Big)(maxFee),
+		MaxPriorityFeePerGas: (*hexutil.Big)(tip),
+		ChainID:              (*hexutil.Big)(chainID),
+	}, nil
+}
+
+// sign signs the transaction with the key of its sender and records the encoded result.
+func (tx *requestTransaction) sign(key *ecdsa.PrivateKey) error {
+	if addr := crypto.PubkeyToAddress(key.PublicKey); addr != tx.From {
+		return fmt.Errorf("private key belongs to %s, not to the sender %s", addr, tx.From)
+	}
+	chainID := tx.ChainID.ToInt()
+	signed, err := types.SignNewTx(key, types.LatestSignerForChainID(chainID), &types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     uint64(tx.Nonce),
+		GasTipCap: tx.MaxPriorityFeePerGas.ToInt(),
+		GasFeeCap: tx.MaxFeePerGas.ToInt(),
+		Gas:       uint64(tx.Gas),
+		To:        &tx.To,
+		Value:     tx.Value.ToInt(),
+		Data:      tx.Input,
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not sign request transaction")
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return errors.Wrap(err, "could not encode signed request transaction")
+	}
+	hash := signed.Hash()
+	tx.Raw = raw
+	tx.Hash = &hash
+	return nil
+}
+
+// requestSender returns the address sending the request and, if a private key file was provided,
+// the key to sign the transaction with. Without either, the request is sent from the withdrawal
+// address of the validator. The system contracts accept requests from any address and record the
+// sender as the source address of the request, and the consensus layer only processes requests
+// whose source address matches the withdrawal credentials of the validator, so requests sent from
+// another address are accepted on chain but have no effect.
+func requestSender(c *cli.Context, withdrawalAddress common.Address) (common.Address, *ecdsa.PrivateKey, error) {
+	if path := c.String(PrivateKeyFileFlag.Name); path != "" {
+		b, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return common.Address{}, nil, errors.Wrap(err, "could not read private key file")
+		}
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(string(b)), "0x"))
+		if err != nil {
+			return common.Address{}, nil, errors.Wrap(err, "could not parse private key")
+		}
+		return crypto.PubkeyToAddress(key.PublicKey), key, nil
+	}
+	if from := c.String(FromAddressFlag.Name); from != "" {
+		if !common.IsHexAddress(from) {
+			return common.Address{}, nil, fmt.Errorf("--%s is not a valid address: %s", FromAddressFlag.Name, from)
+		}
+		return common.HexToAddress(from), nil, nil
+	}
+	return withdrawalAddress, nil, nil
+}
+
+// headState downloads the head state from the beacon node. The returned function restores the
+// beacon config active before the config of the beacon node network was set.
+func headState(ctx context.Context, host string) (state.BeaconState, func() error, error) {
+	c, err := beacon.NewClient(host, client.WithMaxBodySize(client.MaxBodySizeState))
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := c.GetState(ctx, beacon.IdHead)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not get head state from beacon node")
+	}
+	vu, err := detect.FromState(b)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not detect version of head state")
+	}
+	if vu.Fork < version.Electra {
+		return nil, nil, fmt.Errorf("execution layer requests are only processed from Electra, beacon node is on %s", version.String(vu.Fork))
+	}
+	undo, err := params.SetActiveWithUndo(vu.Config)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not set beacon config of the beacon node network")
+	}
+	st, err := vu.UnmarshalBeaconState(b)
+	if err != nil {
+		if undoErr := undo(); undoErr != nil {
+			log.WithError(undoErr).Error("Could not restore beacon config")
+		}
+		return nil, nil, errors.Wrap(err, "could not unmarshal head state")
+	}
+	return st, undo, nil
+}
+
+// outputRequestTransaction prints the transaction, signed if a key was provided.
+func outputRequestTransaction(tx *requestTransaction, key *ecdsa.PrivateKey) error {
+	if key != nil {
+		if err := tx.sign(key); err != nil {
+			return err
+		}
+	}
+	b, err := json.MarshalIndent(tx, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not marshal request transaction")
+	}
+	fmt.Println(string(b))
+	if key == nil {
+		log.Info("Sign the transaction above, for instance with an offline signer, and broadcast it to the execution network")
+	} else {
+		log.WithField("hash", tx.Hash.Hex()).Info("Broadcast the raw transaction above to the execution network, for instance with eth_sendRawTransaction")
+	}
+	log.Warn("The request fee rises with the number of pending requests. The transaction reverts if the fee paid is below the fee at inclusion")
+	return nil
+}
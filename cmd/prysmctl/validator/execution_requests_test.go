@@ -0,0 +1,124 @@
+package validator
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+type mockExecutionClient struct {
+	excess  *big.Int
+	chainID *big.Int
+	nonce   uint64
+	tip     *big.Int
+	baseFee *big.Int
+	gas     uint64
+	call    ethereum.CallMsg
+}
+
+func (m *mockExecutionClient) ChainID(context.Context) (*big.Int, error) {
+	return m.chainID, nil
+}
+
+func (m *mockExecutionClient) StorageAt(_ context.Context, _ common.Address, _ common.Hash, _ *big.Int) ([]byte, error) {
+	return common.BigToHash(m.excess).Bytes(), nil
+}
+
+func (m *mockExecutionClient) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	return m.nonce, nil
+}
+
+func (m *mockExecutionClient) SuggestGasTipCap(context.Context) (*big.Int, error) {
+	return m.tip, nil
+}
+
+func (m *mockExecutionClient) HeaderByNumber(context.Context, *big.Int) (*types.Header, error) {
+	return &types.Header{BaseFee: m.baseFee}, nil
+}
+
+func (m *mockExecutionClient) EstimateGas(_ context.Context, msg ethereum.CallMsg) (uint64, error) {
+	m.call = msg
+	return m.gas, nil
+}
+
+func TestRequestCalldata(t *testing.T) {
+	source := bytesutil.PadTo([]byte{0x01}, 48)
+	target := bytesutil.PadTo([]byte{0x02}, 48)
+
+	data := withdrawalRequestCalldata(source, 0x0102030405060708)
+	require.Equal(t, 56, len(data))
+	assert.DeepEqual(t, source, data[:48])
+	assert.DeepEqual(t, []byte{1, 2, 3, 4, 5, 6, 7, 8}, data[48:])
+
+	data = consolidationRequestCalldata(source, target)
+	require.Equal(t, 96, len(data))
+	assert.DeepEqual(t, source, data[:48])
+	assert.DeepEqual(t, target, data[48:])
+}
+
+func TestRequestFee(t *testing.T) {
+	tests := []struct {
+		excess int64
+		want   int64
+	}{
+		{excess: 0, want: 1},
+		{excess: 1, want: 1},
+		{excess: 17, want: 2},
+		{excess: 100, want: 357},
+		{excess: 500, want: 5933467376577},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, requestFee(big.NewInt(tt.excess)).Int64())
+	}
+}
+
+func TestEstimateRequestFee_NotActive(t *testing.T) {
+	ec := &mockExecutionClient{excess: excessInhibitor}
+	_, err := estimateRequestFee(context.Background(), ec, withdrawalRequestContract)
+	require.ErrorContains(t, "is not active yet", err)
+}
+
+func TestBuildRequestTransaction(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	ec := &mockExecutionClient{
+		excess:  big.NewInt(100),
+		chainID: big.NewInt(17000),
+		nonce:   7,
+		tip:     big.NewInt(2),
+		baseFee: big.NewInt(10),
+		gas:     150000,
+	}
+	input := consolidationRequestCalldata(bytesutil.PadTo([]byte{0x01}, 48), bytesutil.PadTo([]byte{0x02}, 48))
+	tx, err := buildRequestTransaction(context.Background(), ec, from, consolidationRequestContract, input)
+	require.NoError(t, err)
+	assert.Equal(t, int64(357), tx.Value.ToInt().Int64())
+	assert.Equal(t, int64(22), tx.MaxFeePerGas.ToInt().Int64())
+	assert.Equal(t, uint64(7), uint64(tx.Nonce))
+	assert.Equal(t, uint64(150000), uint64(tx.Gas))
+	assert.Equal(t, consolidationRequestContract, *ec.call.To)
+	assert.Equal(t, int64(357), ec.call.Value.Int64())
+
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	require.ErrorContains(t, "not to the sender", tx.sign(other))
+
+	require.NoError(t, tx.sign(key))
+	signed := &types.Transaction{}
+	require.NoError(t, signed.UnmarshalBinary(tx.Raw))
+	sender, err := types.Sender(types.LatestSignerForChainID(big.NewInt(17000)), signed)
+	require.NoError(t, err)
+	assert.Equal(t, from, sender)
+	assert.Equal(t, consolidationRequestContract, *signed.To())
+	assert.DeepEqual(t, input, signed.Data())
+	assert.Equal(t, *tx.Hash, signed.Hash())
+}
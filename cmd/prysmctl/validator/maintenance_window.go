@@ -0,0 +1,426 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api/client/beacon"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+// maxMaintenanceEpochs is the number of epochs covered by the proposer lookahead of the beacon node.
+const maxMaintenanceEpochs = 32
+
+// maintenanceSchedule holds the duties of a set of validators over the slots [start, end), from which the
+// maintenance windows are computed.
+type maintenanceSchedule struct {
+	slotsPerEpoch  primitives.Slot
+	secondsPerSlot uint64
+	genesis        time.Time
+	start, end     primitives.Slot
+	validators     uint64
+	// proposals maps the slots with a block proposal to whether the proposal is speculative.
+	proposals map[primitives.Slot]bool
+	// syncEpochs are the epochs in which one of the validators is in the sync committee.
+	syncEpochs map[primitives.Epoch]bool
+	// attestations holds the attestation slots of the validators, for the epochs with known attester duties.
+	// The attestations of the other epochs are estimated.
+	attestations map[primitives.Epoch][]primitives.Slot
+	// speculativeFrom is the first epoch whose proposers are speculative.
+	speculativeFrom primitives.Epoch
+	// attestationCostGwei is the estimated reward forgone, plus the penalty, for a missed attestation.
+	attestationCostGwei uint64
+}
+
+// maintenanceWindow is a range of slots [start, end) without block proposals or sync committee duties.
+type maintenanceWindow struct {
+	start, end         primitives.Slot
+	missedAttestations uint64
+	forgoneGwei        uint64
+	// speculative is set if the window depends on speculative proposer duties.
+	speculative bool
+	// openEnded is set if the window extends past the end of the schedule.
+	openEnded bool
+}
+
+func (s *maintenanceSchedule) epoch(slot primitives.Slot) primitives.Epoch {
+	return primitives.Epoch(slot / s.slotsPerEpoch)
+}
+
+func (s *maintenanceSchedule) slotTime(slot primitives.Slot) time.Time {
+	return s.genesis.Add(time.Duration(uint64(slot)*s.secondsPerSlot) * time.Second)
+}
+
+func (s *maintenanceSchedule) busy(slot primitives.Slot) bool {
+	_, ok := s.proposals[slot]
+	return ok || s.syncEpochs[s.epoch(slot)]
+}
+
+// windows returns the maintenance windows of the schedule, longest first.
+func (s *maintenanceSchedule) windows() []maintenanceWindow {
+	ws := make([]maintenanceWindow, 0)
+	open := false
+	var w maintenanceWindow
+	for slot := s.start; slot < s.end; slot++ {
+		if s.busy(slot) {
+			if open {
+				ws = append(ws, s.finishWindow(w))
+				open = false
+			}
+			continue
+		}
+		if !open {
+			w = maintenanceWindow{start: slot}
+			open = true
+		}
+		w.end = slot + 1
+	}
+	if open {
+		w.openEnded = true
+		ws = append(ws, s.finishWindow(w))
+	}
+	sort.SliceStable(ws, func(i, j int) bool {
+		return ws[i].end-ws[i].start > ws[j].end-ws[j].start
+	})
+	return ws
+}
+
+// finishWindow estimates the attestations missed by a downtime spanning the window.
+func (s *maintenanceSchedule) finishWindow(w maintenanceWindow) maintenanceWindow {
+	for e := s.epoch(w.start); e <= s.epoch(w.end-1); e++ {
+		lo, hi := primitives.Slot(e)*s.slotsPerEpoch, primitives.Slot(e+1)*s.slotsPerEpoch
+		if lo < w.start {
+			lo = w.start
+		}
+		if hi > w.end {
+			hi = w.end
+		}
+		if duties, ok := s.attestations[e]; ok {
+			for _, slot := range duties {
+				if slot >= lo && slot < hi {
+					w.missedAttestations++
+				}
+			}
+			continue
+		}
+		// Without attester duties, each validator is assumed to attest in a random slot of the epoch.
+		spe := uint64(s.slotsPerEpoch)
+		w.missedAttestations += (s.validators*uint64(hi-lo) + spe - 1) / spe
+	}
+	w.forgoneGwei = w.missedAttestations * s.attestationCostGwei
+	w.speculative = s.epoch(w.end-1) >= s.speculativeFrom || (!w.openEnded && s.proposals[w.end])
+	return w
+}
+
+func maintenanceWindows(c *cli.Context) error {
+	ctx, span := trace.StartSpan(c.Context, "validator.maintenanceWindows")
+	defer span.End()
+	if !c.IsSet(PubkeysFlag.Name) {
+		return errNoFlag(PubkeysFlag.Name)
+	}
+	epochs := c.Uint64(EpochsFlag.Name)
+	if epochs == 0 || epochs > maxMaintenanceEpochs {
+		return fmt.Errorf("--%s must be between 1 and %d", EpochsFlag.Name, maxMaintenanceEpochs)
+	}
+	pubkeys := strings.Split(c.String(PubkeysFlag.Name), ",")
+	for i, pk := range pubkeys {
+		pubkeys[i] = strings.TrimSpace(pk)
+		b, err := hexutil.Decode(pubkeys[i])
+		if err != nil {
+			return errors.Wrapf(err, "could not decode public key %s", pubkeys[i])
+		}
+		if len(b) != fieldparams.BLSPubkeyLength {
+			return fmt.Errorf("%s is not a %d byte public key", pubkeys[i], fieldparams.BLSPubkeyLength)
+		}
+	}
+
+	client, err := beacon.NewClient(c.String(BeaconHostFlag.Name))
+	if err != nil {
+		return err
+	}
+	s, err := buildMaintenanceSchedule(ctx, client, pubkeys, primitives.Epoch(epochs), time.Now())
+	if err != nil {
+		return err
+	}
+	minSlots := primitives.Slot((uint64(c.Duration(MinWindowFlag.Name).Seconds()) + s.secondsPerSlot - 1) / s.secondsPerSlot)
+	ws := make([]maintenanceWindow, 0)
+	for _, w := range s.windows() {
+		if w.end-w.start >= minSlots {
+			ws = append(ws, w)
+		}
+	}
+	printMaintenanceWindows(os.Stdout, s, ws, c.Int(WindowsFlag.Name))
+	if !c.Bool(WaitFlag.Name) || len(ws) == 0 {
+		return nil
+	}
+	return waitForWindow(ctx, s, ws)
+}
+
+// buildMaintenanceSchedule queries the duties of the given validators for the given number of epochs, starting
+// at the current epoch.
+func buildMaintenanceSchedule(ctx context.Context, client *beacon.Client, pubkeys []string, epochs primitives.Epoch, now time.Time) (*maintenanceSchedule, error) {
+	spec, err := client.GetConfigSpec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s := &maintenanceSchedule{
+		proposals:    make(map[primitives.Slot]bool),
+		syncEpochs:   make(map[primitives.Epoch]bool),
+		attestations: make(map[primitives.Epoch][]primitives.Slot),
+	}
+	spe, err := specUint(spec, "SLOTS_PER_EPOCH")
+	if err != nil {
+		return nil, err
+	}
+	s.slotsPerEpoch = primitives.Slot(spe)
+	if s.secondsPerSlot, err = specUint(spec, "SECONDS_PER_SLOT"); err != nil {
+		return nil, err
+	}
+	epochsPerPeriod, err := specUint(spec, "EPOCHS_PER_SYNC_COMMITTEE_PERIOD")
+	if err != nil {
+		return nil, err
+	}
+	genesis, err := client.GetGenesis(ctx)
+	if err != nil {
+		return nil, err
+	}
+	genesisTime, err := strconv.ParseInt(genesis.GenesisTime, 10, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse genesis time %s", genesis.GenesisTime)
+	}
+	s.genesis = time.Unix(genesisTime, 0)
+	if now.Before(s.genesis) {
+		return nil, errors.New("the network has not started yet")
+	}
+	s.start = primitives.Slot(uint64(now.Sub(s.genesis).Seconds()) / s.secondsPerSlot)
+	current := s.epoch(s.start)
+	last := current + epochs - 1
+	s.end = primitives.Slot(last+1) * s.slotsPerEpoch
+
+	vals, err := client.GetValidators(ctx, beacon.IdHead, pubkeys)
+	if err != nil {
+		return nil, err
+	}
+	if len(vals) != len(pubkeys) {
+		return nil, fmt.Errorf("found %d of the %d validators", len(vals), len(pubkeys))
+	}
+	indices := make([]primitives.ValidatorIndex, len(vals))
+	mine := make(map[string]bool)
+	for i, v := range vals {
+		idx, err := strconv.ParseUint(v.Index, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not parse validator index %s", v.Index)
+		}
+		indices[i] = primitives.ValidatorIndex(idx)
+		mine[v.Index] = true
+	}
+	s.validators = uint64(len(indices))
+
+	// The proposer and attester duties of the current and next epoch are final, unless the chain reorgs.
+	s.speculativeFrom = current + 2
+	for e := current; e <= last && e < s.speculativeFrom; e++ {
+		proposers, err := client.GetProposerDuties(ctx, e)
+		if err != nil {
+			return nil, err
+		}
+		s.addProposals(proposers.Data, mine, false)
+		attesters, err := client.GetAttesterDuties(ctx, e, indices)
+		if err != nil {
+			return nil, err
+		}
+		slots := make([]primitives.Slot, 0, len(attesters.Data))
+		for _, d := range attesters.Data {
+			slot, err := strconv.ParseUint(d.Slot, 10, 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "could not parse attester duty slot %s", d.Slot)
+			}
+			slots = append(slots, primitives.Slot(slot))
+		}
+		s.attestations[e] = slots
+	}
+	if last >= s.speculativeFrom {
+		lookahead, err := client.GetProposerLookahead(ctx, s.speculativeFrom, last)
+		if err != nil {
+			// The proposer lookahead is only served by Prysm beacon nodes.
+			log.WithError(err).Warn("Could not get proposer lookahead, only planning for the current and next epoch")
+			s.end = primitives.Slot(s.speculativeFrom) * s.slotsPerEpoch
+		} else {
+			for _, e := range lookahead.Data {
+				s.addProposals(e.Duties, mine, e.Speculative)
+			}
+		}
+	}
+
+	// Sync committee duties can be queried up to the next sync committee period.
+	period := primitives.Epoch(epochsPerPeriod)
+	for e := current; e < s.epoch(s.end) && e/period <= current/period+1; e = (e/period + 1) * period {
+		duties, err := client.GetSyncCommitteeDuties(ctx, e, indices)
+		if err != nil {
+			return nil, err
+		}
+		if len(duties.Data) == 0 {
+			continue
+		}
+		periodEnd := (e/period + 1) * period
+		for se := e; se < periodEnd && se < s.epoch(s.end); se++ {
+			s.syncEpochs[se] = true
+		}
+	}
+
+	if current >= 2 {
+		s.attestationCostGwei, err = attestationCost(ctx, client, current-2, indices, vals)
+		if err != nil {
+			log.WithError(err).Warn("Could not estimate attestation rewards")
+		}
+	}
+	return s, nil
+}
+
+func (s *maintenanceSchedule) addProposals(duties []*structs.ProposerDuty, mine map[string]bool, speculative bool) {
+	for _, d := range duties {
+		if !mine[d.ValidatorIndex] {
+			continue
+		}
+		slot, err := strconv.ParseUint(d.Slot, 10, 64)
+		if err != nil {
+			log.WithError(err).WithField("slot", d.Slot).Warn("Could not parse proposer duty slot")
+			continue
+		}
+		s.proposals[primitives.Slot(slot)] = speculative
+	}
+}
+
+// attestationCost returns the average cost of a missed attestation for the given validators: the ideal head, target
+// and source rewards of their effective balance, which are forgone, plus the target and source penalties.
+func attestationCost(ctx context.Context, client *beacon.Client, epoch primitives.Epoch, indices []primitives.ValidatorIndex, vals []*structs.ValidatorContainer) (uint64, error) {
+	rewards, err := client.GetAttestationRewards(ctx, epoch, indices)
+	if err != nil {
+		return 0, err
+	}
+	ideal := make(map[string]uint64)
+	for _, r := range rewards.IdealRewards {
+		head, err := strconv.ParseInt(r.Head, 10, 64)
+		if err != nil {
+			return 0, errors.Wrap(err, "could not parse ideal head reward")
+		}
+		target, err := strconv.ParseInt(r.Target, 10, 64)
+		if err != nil {
+			return 0, errors.Wrap(err, "could not parse ideal target reward")
+		}
+		source, err := strconv.ParseInt(r.Source, 10, 64)
+		if err != nil {
+			return 0, errors.Wrap(err, "could not parse ideal source reward")
+		}
+		ideal[r.EffectiveBalance] = uint64(head + 2*target + 2*source)
+	}
+	total := uint64(0)
+	for _, v := range vals {
+		if v.Validator == nil {
+			continue
+		}
+		total += ideal[v.Validator.EffectiveBalance]
+	}
+	return total / uint64(len(vals)), nil
+}
+
+func specUint(spec *structs.GetSpecResponse, name string) (uint64, error) {
+	data, ok := spec.Data.(map[string]interface{})
+	if !ok {
+		return 0, errors.New("unexpected config spec format")
+	}
+	v, ok := data[name].(string)
+	if !ok {
+		return 0, fmt.Errorf("%s is missing from the config spec", name)
+	}
+	u, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "could not parse %s", name)
+	}
+	return u, nil
+}
+
+func printMaintenanceWindows(w io.Writer, s *maintenanceSchedule, ws []maintenanceWindow, limit int) {
+	fmt.Fprintf(w, "Duties of %d validators from slot %d to slot %d:\n", s.validators, s.start, s.end-1)
+	proposals := make([]primitives.Slot, 0, len(s.proposals))
+	for slot := range s.proposals {
+		proposals = append(proposals, slot)
+	}
+	sort.Slice(proposals, func(i, j int) bool {
+		return proposals[i] < proposals[j]
+	})
+	for _, slot := range proposals {
+		kind := "block proposal"
+		if s.proposals[slot] {
+			kind = "block proposal (speculative)"
+		}
+		fmt.Fprintf(w, "  slot %d at %s: %s\n", slot, s.slotTime(slot).Format(time.RFC3339), kind)
+	}
+	syncEpochs := make([]primitives.Epoch, 0, len(s.syncEpochs))
+	for e := range s.syncEpochs {
+		syncEpochs = append(syncEpochs, e)
+	}
+	sort.Slice(syncEpochs, func(i, j int) bool {
+		return syncEpochs[i] < syncEpochs[j]
+	})
+	if len(syncEpochs) > 0 {
+		fmt.Fprintf(w, "  epochs %d to %d: sync committee\n", syncEpochs[0], syncEpochs[len(syncEpochs)-1])
+	}
+
+	if len(ws) == 0 {
+		fmt.Fprintln(w, "\nNo maintenance window found")
+		return
+	}
+	if limit > 0 && len(ws) > limit {
+		ws = ws[:limit]
+	}
+	fmt.Fprintln(w, "\nSuggested maintenance windows:")
+	for _, mw := range ws {
+		duration := time.Duration(uint64(mw.end-mw.start)*s.secondsPerSlot) * time.Second
+		notes := ""
+		if mw.openEnded {
+			notes += ", may extend past the planned epochs"
+		}
+		if mw.speculative {
+			notes += ", speculative"
+		}
+		fmt.Fprintf(w, "  %s to %s (%s, slots %d-%d): ~%d missed attestations, ~%d Gwei forgone%s\n",
+			s.slotTime(mw.start).Format(time.RFC3339), s.slotTime(mw.end).Format(time.RFC3339), duration,
+			mw.start, mw.end-1, mw.missedAttestations, mw.forgoneGwei, notes)
+	}
+}
+
+// waitForWindow blocks until the earliest of the given windows opens.
+func waitForWindow(ctx context.Context, s *maintenanceSchedule, ws []maintenanceWindow) error {
+	next := ws[0]
+	for _, w := range ws[1:] {
+		if w.start < next.start {
+			next = w
+		}
+	}
+	opens := s.slotTime(next.start)
+	log.WithFields(log.Fields{
+		"slot":     next.start,
+		"opensAt":  opens.Format(time.RFC3339),
+		"closesAt": s.slotTime(next.end).Format(time.RFC3339),
+	}).Info("Waiting for the next maintenance window")
+	select {
+	case <-time.After(time.Until(opens)):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	log.WithField("closesAt", s.slotTime(next.end).Format(time.RFC3339)).Info("Maintenance window is open")
+	return nil
+}
@@ -0,0 +1,124 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/v5/api/client/beacon"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func TestMaintenanceSchedule_Windows(t *testing.T) {
+	s := &maintenanceSchedule{
+		slotsPerEpoch:       4,
+		start:               1,
+		end:                 16,
+		validators:          1,
+		proposals:           map[primitives.Slot]bool{6: false, 13: true},
+		syncEpochs:          map[primitives.Epoch]bool{},
+		attestations:        map[primitives.Epoch][]primitives.Slot{0: {2}, 1: {5}},
+		speculativeFrom:     2,
+		attestationCostGwei: 10,
+	}
+	ws := s.windows()
+	require.Equal(t, 3, len(ws))
+	assert.DeepEqual(t, maintenanceWindow{start: 7, end: 13, missedAttestations: 2, forgoneGwei: 20, speculative: true}, ws[0])
+	assert.DeepEqual(t, maintenanceWindow{start: 1, end: 6, missedAttestations: 2, forgoneGwei: 20}, ws[1])
+	assert.DeepEqual(t, maintenanceWindow{start: 14, end: 16, missedAttestations: 1, forgoneGwei: 10, speculative: true, openEnded: true}, ws[2])
+
+	s.syncEpochs[1] = true
+	ws = s.windows()
+	require.Equal(t, 3, len(ws))
+	assert.DeepEqual(t, maintenanceWindow{start: 8, end: 13, missedAttestations: 2, forgoneGwei: 20, speculative: true}, ws[0])
+	assert.DeepEqual(t, maintenanceWindow{start: 1, end: 4, missedAttestations: 1, forgoneGwei: 10}, ws[1])
+}
+
+func TestBuildMaintenanceSchedule(t *testing.T) {
+	pubkey := "0xa99a76ed7796f7be22d5b7e85deeb7c5677e88e511e0b337618f8c4eb61349b4bf2d153f649f7b53359fe8b94a38e44c"
+	respond := func(w http.ResponseWriter, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(v))
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/eth/v1/config/spec", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, &structs.GetSpecResponse{Data: map[string]string{
+			"SLOTS_PER_EPOCH":                  "4",
+			"SECONDS_PER_SLOT":                 "12",
+			"EPOCHS_PER_SYNC_COMMITTEE_PERIOD": "8",
+		}})
+	})
+	mux.HandleFunc("/eth/v1/beacon/genesis", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, &structs.GetGenesisResponse{Data: &structs.Genesis{GenesisTime: "1000"}})
+	})
+	mux.HandleFunc("/eth/v1/beacon/states/head/validators", func(w http.ResponseWriter, r *http.Request) {
+		req := &structs.GetValidatorsRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		require.DeepEqual(t, []string{pubkey}, req.Ids)
+		respond(w, &structs.GetValidatorsResponse{Data: []*structs.ValidatorContainer{
+			{Index: "5", Validator: &structs.Validator{Pubkey: pubkey, EffectiveBalance: "32000000000"}},
+		}})
+	})
+	mux.HandleFunc("/eth/v1/validator/duties/proposer/2", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, &structs.GetProposerDutiesResponse{Data: []*structs.ProposerDuty{
+			{ValidatorIndex: "5", Slot: "10"},
+			{ValidatorIndex: "7", Slot: "11"},
+		}})
+	})
+	mux.HandleFunc("/eth/v1/validator/duties/proposer/3", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, &structs.GetProposerDutiesResponse{Data: []*structs.ProposerDuty{{ValidatorIndex: "7", Slot: "12"}}})
+	})
+	mux.HandleFunc("/eth/v1/validator/duties/attester/2", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, &structs.GetAttesterDutiesResponse{Data: []*structs.AttesterDuty{{ValidatorIndex: "5", Slot: "9"}}})
+	})
+	mux.HandleFunc("/eth/v1/validator/duties/attester/3", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, &structs.GetAttesterDutiesResponse{Data: []*structs.AttesterDuty{{ValidatorIndex: "5", Slot: "13"}}})
+	})
+	mux.HandleFunc("/prysm/v1/validators/proposer_lookahead", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "4", r.URL.Query().Get("start_epoch"))
+		require.Equal(t, "4", r.URL.Query().Get("end_epoch"))
+		respond(w, &structs.GetProposerLookaheadResponse{Data: []*structs.ProposerLookaheadEpoch{{
+			Epoch:       "4",
+			Stability:   "speculative",
+			Speculative: true,
+			Duties:      []*structs.ProposerDuty{{ValidatorIndex: "5", Slot: "17"}},
+		}}})
+	})
+	mux.HandleFunc("/eth/v1/validator/duties/sync/2", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, &structs.GetSyncCommitteeDutiesResponse{Data: []*structs.SyncCommitteeDuty{}})
+	})
+	mux.HandleFunc("/eth/v1/beacon/rewards/attestations/0", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, &structs.AttestationRewardsResponse{Data: structs.AttestationRewards{
+			IdealRewards: []structs.IdealAttestationReward{
+				{EffectiveBalance: "31000000000", Head: "1", Target: "1", Source: "1"},
+				{EffectiveBalance: "32000000000", Head: "10", Target: "20", Source: "10"},
+			},
+		}})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := beacon.NewClient(srv.URL)
+	require.NoError(t, err)
+	// Slot 9, in epoch 2.
+	now := time.Unix(1000+9*12+5, 0)
+	s, err := buildMaintenanceSchedule(context.Background(), client, []string{pubkey}, 3, now)
+	require.NoError(t, err)
+	assert.Equal(t, primitives.Slot(9), s.start)
+	assert.Equal(t, primitives.Slot(20), s.end)
+	assert.DeepEqual(t, map[primitives.Slot]bool{10: false, 17: true}, s.proposals)
+	assert.Equal(t, 0, len(s.syncEpochs))
+	assert.Equal(t, uint64(70), s.attestationCostGwei)
+
+	ws := s.windows()
+	require.Equal(t, 3, len(ws))
+	assert.DeepEqual(t, maintenanceWindow{start: 11, end: 17, missedAttestations: 2, forgoneGwei: 140, speculative: true}, ws[0])
+	assert.DeepEqual(t, maintenanceWindow{start: 18, end: 20, missedAttestations: 1, forgoneGwei: 70, speculative: true, openEnded: true}, ws[1])
+	assert.DeepEqual(t, maintenanceWindow{start: 9, end: 10, missedAttestations: 1, forgoneGwei: 70}, ws[2])
+}
@@ -0,0 +1,103 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pkg/errors"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	enginev1 "github.com/prysmaticlabs/prysm/v5/proto/engine/v1"
+	"github.com/prysmaticlabs/prysm/v5/validator/requests"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+func partialWithdraw(c *cli.Context) error {
+	ctx, span := trace.StartSpan(c.Context, "withdrawal.partialWithdraw")
+	defer span.End()
+	pubkey, err := pubkeyFromFlag(c, ValidatorPubkeyFlag.Name)
+	if err != nil {
+		return err
+	}
+	amount := c.Uint64(AmountGweiFlag.Name)
+	fullExit := c.Bool(FullExitFlag.Name)
+	if fullExit && amount != 0 {
+		return fmt.Errorf("--%s and --%s are mutually exclusive", FullExitFlag.Name, AmountGweiFlag.Name)
+	}
+	if !fullExit && amount == 0 {
+		return fmt.Errorf("either --%s or --%s must be provided", AmountGweiFlag.Name, FullExitFlag.Name)
+	}
+
+	st, undo, err := headState(ctx, c.String(BeaconHostFlag.Name))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := undo(); err != nil {
+			log.WithError(err).Error("Could not restore beacon config")
+		}
+	}()
+	withdrawalAddress, err := requests.WithdrawalAddress(st, pubkey)
+	if err != nil {
+		return err
+	}
+	from, key, err := requestSender(c, withdrawalAddress)
+	if err != nil {
+		return err
+	}
+	req := &enginev1.WithdrawalRequest{
+		SourceAddress:   from.Bytes(),
+		ValidatorPubkey: pubkey,
+		Amount:          amount,
+	}
+	outcome, err := requests.CheckWithdrawal(ctx, st, req)
+	if err != nil {
+		return errors.Wrap(err, "withdrawal request would be ignored by the beacon chain")
+	}
+	if fullExit {
+		log.WithFields(log.Fields{
+			"validatorIndex":    outcome.Index,
+			"exitEpoch":         outcome.ExitEpoch,
+			"withdrawableEpoch": outcome.WithdrawableEpoch,
+		}).Info("Validator would exit")
+	} else {
+		fields := log.Fields{
+			"validatorIndex":    outcome.Index,
+			"amountGwei":        outcome.Amount,
+			"withdrawableEpoch": outcome.WithdrawableEpoch,
+		}
+		if outcome.Amount < amount {
+			log.WithFields(fields).Warnf("Only %d of the requested %d Gwei are above the minimum activation balance and would be withdrawn", outcome.Amount, amount)
+		} else {
+			log.WithFields(fields).Info("Partial withdrawal would be queued")
+		}
+	}
+
+	ec, err := ethclient.DialContext(ctx, c.String(ExecutionHostFlag.Name))
+	if err != nil {
+		return errors.Wrap(err, "could not connect to execution node")
+	}
+	defer ec.Close()
+	tx, err := buildRequestTransaction(ctx, ec, from, withdrawalRequestContract, withdrawalRequestCalldata(pubkey, amount))
+	if err != nil {
+		return err
+	}
+	return outputRequestTransaction(tx, key)
+}
+
+// pubkeyFromFlag decodes the validator public key provided with the given flag.
+func pubkeyFromFlag(c *cli.Context, name string) ([]byte, error) {
+	if !c.IsSet(name) {
+		return nil, errNoFlag(name)
+	}
+	pubkey, err := hexutil.Decode(c.String(name))
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not decode --%s", name)
+	}
+	if len(pubkey) != fieldparams.BLSPubkeyLength {
+		return nil, fmt.Errorf("--%s must be a %d byte public key", name, fieldparams.BLSPubkeyLength)
+	}
+	return pubkey, nil
+}
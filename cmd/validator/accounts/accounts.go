@@ -145,6 +145,75 @@ var Commands = &cli.Command{
 				return nil
 			},
 		},
+		{
+			Name: "handover",
+			Description: "Hands the key leases of this validator client over to another validator client, " +
+				"exporting the slashing protection history of the handed over keys. The validator client " +
+				"handing over must be stopped.",
+			Flags: cmd.WrapFlags([]cli.Flag{
+				cmd.DataDirFlag,
+				flags.KeyLeaseDirFlag,
+				flags.KeyLeaseURLFlag,
+				flags.KeyLeaseTokenFileFlag,
+				flags.KeyLeaseOwnerFlag,
+				flags.KeyLeaseDurationFlag,
+				flags.HandoverToFlag,
+				flags.HandoverPublicKeysFlag,
+				flags.SlashingProtectionExportDirFlag,
+				features.EnableMinimalSlashingProtection,
+				features.Mainnet,
+				features.SepoliaTestnet,
+				features.HoleskyTestnet,
+				cmd.AcceptTosFlag,
+			}),
+			Before: func(cliCtx *cli.Context) error {
+				if err := cmd.LoadFlagsFromConfig(cliCtx, cliCtx.Command.Flags); err != nil {
+					return err
+				}
+				if err := tos.VerifyTosAcceptedOrPrompt(cliCtx); err != nil {
+					return err
+				}
+				return features.ConfigureValidator(cliCtx)
+			},
+			Action: func(cliCtx *cli.Context) error {
+				if err := accountsHandover(cliCtx); err != nil {
+					log.WithError(err).Fatal("Could not hand over accounts")
+				}
+				return nil
+			},
+		},
+		{
+			Name: "release-leases",
+			Description: "Releases the key leases of this validator client, so that other validator clients can " +
+				"acquire the keys without waiting for the leases to expire. The validator client must be stopped, " +
+				"and the slashing protection history of the keys must be imported wherever they are used next.",
+			Flags: cmd.WrapFlags([]cli.Flag{
+				flags.KeyLeaseDirFlag,
+				flags.KeyLeaseURLFlag,
+				flags.KeyLeaseTokenFileFlag,
+				flags.KeyLeaseOwnerFlag,
+				flags.ReleasePublicKeysFlag,
+				features.Mainnet,
+				features.SepoliaTestnet,
+				features.HoleskyTestnet,
+				cmd.AcceptTosFlag,
+			}),
+			Before: func(cliCtx *cli.Context) error {
+				if err := cmd.LoadFlagsFromConfig(cliCtx, cliCtx.Command.Flags); err != nil {
+					return err
+				}
+				if err := tos.VerifyTosAcceptedOrPrompt(cliCtx); err != nil {
+					return err
+				}
+				return features.ConfigureValidator(cliCtx)
+			},
+			Action: func(cliCtx *cli.Context) error {
+				if err := accountsReleaseLeases(cliCtx); err != nil {
+					log.WithError(err).Fatal("Could not release key leases")
+				}
+				return nil
+			},
+		},
 		{
 			Name:        "voluntary-exit",
 			Description: "Performs a voluntary exit on selected accounts",
@@ -0,0 +1,209 @@
+package accounts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/cmd"
+	"github.com/prysmaticlabs/prysm/v5/cmd/validator/flags"
+	"github.com/prysmaticlabs/prysm/v5/config/features"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/io/file"
+	"github.com/prysmaticlabs/prysm/v5/validator/db/filesystem"
+	"github.com/prysmaticlabs/prysm/v5/validator/db/iface"
+	"github.com/prysmaticlabs/prysm/v5/validator/db/kv"
+	"github.com/prysmaticlabs/prysm/v5/validator/keymanager/lease"
+	"github.com/prysmaticlabs/prysm/v5/validator/node"
+	slashingprotection "github.com/prysmaticlabs/prysm/v5/validator/slashing-protection-history"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+const handoverExportFileName = "handover_slashing_protection.json"
+
+// accountsHandover hands the key leases of this validator client over to another one, together
+// with the slashing protection history of the keys. The slashing protection history is exported
+// before the leases are transferred, so the validator client handing over must be stopped: its
+// database cannot be opened otherwise, and it must not sign anything after the export.
+func accountsHandover(c *cli.Context) error {
+	store, err := node.KeyLeaseStore(c)
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		return fmt.Errorf("either --%s or --%s must be set", flags.KeyLeaseDirFlag.Name, flags.KeyLeaseURLFlag.Name)
+	}
+	from, err := node.KeyLeaseOwner(c)
+	if err != nil {
+		return err
+	}
+	to := c.String(flags.HandoverToFlag.Name)
+	if to == "" {
+		return fmt.Errorf("--%s must be set", flags.HandoverToFlag.Name)
+	}
+	if to == from {
+		return fmt.Errorf("cannot hand keys over from %s to itself", from)
+	}
+	outputDir := c.String(flags.SlashingProtectionExportDirFlag.Name)
+	if outputDir == "" {
+		return fmt.Errorf("--%s must be set", flags.SlashingProtectionExportDirFlag.Name)
+	}
+
+	pubkeys, err := leasedPublicKeys(c, store, from, flags.HandoverPublicKeysFlag.Name)
+	if err != nil {
+		return err
+	}
+	if len(pubkeys) == 0 {
+		return fmt.Errorf("%s does not hold any key lease to hand over", from)
+	}
+
+	validatorDB, err := openValidatorDB(c)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := validatorDB.Close(); err != nil {
+			log.WithError(err).Error("Could not close validator DB")
+		}
+	}()
+	filteredKeys := make([][]byte, len(pubkeys))
+	for i, pk := range pubkeys {
+		filteredKeys[i] = pk[:]
+	}
+	eipJSON, err := slashingprotection.ExportStandardProtectionJSON(c.Context, validatorDB, filteredKeys...)
+	if err != nil {
+		return errors.Wrap(err, "could not export slashing protection history")
+	}
+	encoded, err := json.MarshalIndent(eipJSON, "", "\t")
+	if err != nil {
+		return errors.Wrap(err, "could not JSON marshal slashing protection history")
+	}
+	if err := file.MkdirAll(outputDir); err != nil {
+		return errors.Wrapf(err, "could not create output directory %s", outputDir)
+	}
+	outputPath := filepath.Join(outputDir, handoverExportFileName)
+	if err := file.WriteFile(outputPath, encoded); err != nil {
+		return errors.Wrapf(err, "could not write file to path %s", outputPath)
+	}
+
+	leases, err := store.Transfer(c.Context, pubkeys, from, to, c.Duration(flags.KeyLeaseDurationFlag.Name))
+	if err != nil {
+		// Nothing was handed over, so the export must not be imported anywhere.
+		if rmErr := os.Remove(outputPath); rmErr != nil {
+			log.WithError(rmErr).Errorf("Could not remove slashing protection export %s", outputPath)
+		}
+		return errors.Wrap(err, "could not transfer key leases")
+	}
+	var usableAt time.Time
+	for _, l := range leases {
+		if l.NotBefore.After(usableAt) {
+			usableAt = l.NotBefore
+		}
+	}
+	log.WithFields(logrus.Fields{
+		"from":               from,
+		"to":                 to,
+		"keys":               len(leases),
+		"usableAt":           usableAt,
+		"slashingProtection": outputPath,
+	}).Info("Handed key leases over. Import the slashing protection history into the receiving validator client before the leases become usable")
+	return nil
+}
+
+// accountsReleaseLeases releases the key leases of this validator client, so that other validator
+// clients can acquire the keys right away instead of waiting for the leases to expire. The validator
+// client must be stopped, and the slashing protection history of the keys must be imported wherever
+// they are used next.
+func accountsReleaseLeases(c *cli.Context) error {
+	store, err := node.KeyLeaseStore(c)
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		return fmt.Errorf("either --%s or --%s must be set", flags.KeyLeaseDirFlag.Name, flags.KeyLeaseURLFlag.Name)
+	}
+	owner, err := node.KeyLeaseOwner(c)
+	if err != nil {
+		return err
+	}
+	pubkeys, err := leasedPublicKeys(c, store, owner, flags.ReleasePublicKeysFlag.Name)
+	if err != nil {
+		return err
+	}
+	if len(pubkeys) == 0 {
+		return fmt.Errorf("%s does not hold any key lease to release", owner)
+	}
+	for _, pk := range pubkeys {
+		if err := store.Release(c.Context, pk, owner); err != nil {
+			return errors.Wrapf(err, "could not release lease of key %#x", pk)
+		}
+	}
+	log.WithFields(logrus.Fields{
+		"owner": owner,
+		"keys":  len(pubkeys),
+	}).Info("Released key leases")
+	return nil
+}
+
+// leasedPublicKeys returns the keys selected with the given public keys flag, or every key whose
+// lease was last held by the owner. Leases which expired since the owner was stopped are included,
+// as long as no other owner acquired them in the meantime.
+func leasedPublicKeys(c *cli.Context, store lease.Store, owner, pubkeysFlag string) ([][fieldparams.BLSPubkeyLength]byte, error) {
+	if raw := c.String(pubkeysFlag); raw != "" {
+		parts := strings.Split(raw, ",")
+		pubkeys := make([][fieldparams.BLSPubkeyLength]byte, 0, len(parts))
+		for _, p := range parts {
+			pk, err := hexutil.Decode(strings.TrimSpace(p))
+			if err != nil {
+				return nil, errors.Wrapf(err, "could not decode public key %s", p)
+			}
+			if len(pk) != fieldparams.BLSPubkeyLength {
+				return nil, fmt.Errorf("public key %s has length %d", p, len(pk))
+			}
+			pubkeys = append(pubkeys, [fieldparams.BLSPubkeyLength]byte(pk))
+		}
+		return pubkeys, nil
+	}
+	leases, err := store.Leases(c.Context)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list key leases")
+	}
+	var pubkeys [][fieldparams.BLSPubkeyLength]byte
+	for _, l := range leases {
+		if l.Owner == owner {
+			pubkeys = append(pubkeys, [fieldparams.BLSPubkeyLength]byte(l.PublicKey))
+		}
+	}
+	return pubkeys, nil
+}
+
+// openValidatorDB opens the existing validator database in the data directory.
+func openValidatorDB(c *cli.Context) (iface.ValidatorDB, error) {
+	dataDir := c.String(cmd.DataDirFlag.Name)
+	if c.Bool(features.EnableMinimalSlashingProtection.Name) {
+		exists, err := file.HasDir(filepath.Join(dataDir, filesystem.DatabaseDirName))
+		if err != nil || !exists {
+			return nil, fmt.Errorf("no validator database found in %s", dataDir)
+		}
+		validatorDB, err := filesystem.NewStore(dataDir, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not open validator database in %s", dataDir)
+		}
+		return validatorDB, nil
+	}
+	exists, err := file.Exists(filepath.Join(dataDir, kv.ProtectionDbFileName), file.Regular)
+	if err != nil || !exists {
+		return nil, fmt.Errorf("no validator database found in %s", dataDir)
+	}
+	validatorDB, err := kv.NewKVStore(c.Context, dataDir, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not open validator database in %s, is the validator client still running?", dataDir)
+	}
+	return validatorDB, nil
+}
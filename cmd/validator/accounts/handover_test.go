@@ -0,0 +1,113 @@
+package accounts
+
+import (
+	"context"
+	"flag"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/v5/cmd"
+	"github.com/prysmaticlabs/prysm/v5/cmd/validator/flags"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	"github.com/prysmaticlabs/prysm/v5/io/file"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/validator/db/kv"
+	"github.com/prysmaticlabs/prysm/v5/validator/keymanager/lease"
+	"github.com/urfave/cli/v2"
+)
+
+type staticKeys [][fieldparams.BLSPubkeyLength]byte
+
+func (k staticKeys) FetchValidatingPublicKeys(_ context.Context) ([][fieldparams.BLSPubkeyLength]byte, error) {
+	return k, nil
+}
+
+func setupHandoverCtx(t *testing.T, dataDir, leaseDir, exportDir string) *cli.Context {
+	app := cli.App{}
+	set := flag.NewFlagSet("test", 0)
+	set.String(cmd.DataDirFlag.Name, dataDir, "")
+	set.String(flags.KeyLeaseDirFlag.Name, leaseDir, "")
+	set.String(flags.KeyLeaseOwnerFlag.Name, "alice", "")
+	set.String(flags.HandoverToFlag.Name, "bob", "")
+	set.String(flags.SlashingProtectionExportDirFlag.Name, exportDir, "")
+	set.Duration(flags.KeyLeaseDurationFlag.Name, time.Minute, "")
+	set.String(flags.ReleasePublicKeysFlag.Name, "", "")
+	return cli.NewContext(&app, set, nil)
+}
+
+func TestHandover_StoppedValidatorClient(t *testing.T) {
+	tests := []struct {
+		name string
+		// How long the validator client has been stopped when handing over.
+		stoppedFor time.Duration
+	}{
+		{name: "lease not expired", stoppedFor: 0},
+		{name: "lease expired", stoppedFor: 50 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataDir, leaseDir, exportDir := t.TempDir(), t.TempDir(), t.TempDir()
+			pubkeys := staticKeys{{1}, {2}}
+			validatorDB, err := kv.NewKVStore(context.Background(), dataDir, &kv.Config{PubKeys: pubkeys})
+			require.NoError(t, err)
+			require.NoError(t, validatorDB.SaveGenesisValidatorsRoot(context.Background(), bytesutil.PadTo([]byte{1}, fieldparams.RootLength)))
+			require.NoError(t, validatorDB.Close())
+
+			// Run and stop the lease guard of the validator client handing over.
+			store, err := lease.NewFileStore(leaseDir)
+			require.NoError(t, err)
+			ttl := time.Minute
+			if tt.stoppedFor > 0 {
+				ttl = tt.stoppedFor / 5
+			}
+			guard, err := lease.NewGuard(store, "alice", ttl)
+			require.NoError(t, err)
+			ctx, cancel := context.WithCancel(context.Background())
+			require.NoError(t, guard.Renew(ctx, pubkeys))
+			done := make(chan struct{})
+			go func() {
+				guard.Run(ctx, pubkeys)
+				close(done)
+			}()
+			cancel()
+			<-done
+			time.Sleep(tt.stoppedFor)
+
+			cliCtx := setupHandoverCtx(t, dataDir, leaseDir, exportDir)
+			require.NoError(t, accountsHandover(cliCtx))
+
+			leases, err := store.Leases(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, len(pubkeys), len(leases))
+			for _, l := range leases {
+				assert.Equal(t, "bob", l.Owner)
+			}
+			exists, err := file.Exists(filepath.Join(exportDir, handoverExportFileName), file.Regular)
+			require.NoError(t, err)
+			assert.Equal(t, true, exists)
+		})
+	}
+}
+
+func TestReleaseLeases(t *testing.T) {
+	leaseDir := t.TempDir()
+	store, err := lease.NewFileStore(leaseDir)
+	require.NoError(t, err)
+	ctx := context.Background()
+	_, err = store.Acquire(ctx, [fieldparams.BLSPubkeyLength]byte{1}, "alice", time.Minute)
+	require.NoError(t, err)
+	_, err = store.Acquire(ctx, [fieldparams.BLSPubkeyLength]byte{2}, "carol", time.Minute)
+	require.NoError(t, err)
+
+	cliCtx := setupHandoverCtx(t, t.TempDir(), leaseDir, "")
+	require.NoError(t, accountsReleaseLeases(cliCtx))
+	leases, err := store.Leases(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(leases))
+	assert.Equal(t, "carol", leases[0].Owner)
+
+	require.ErrorContains(t, "does not hold any key lease", accountsReleaseLeases(cliCtx))
+}
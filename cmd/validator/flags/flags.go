@@ -353,6 +353,21 @@ var (
 		fee recipient and gas limit. File format found in docs`,
 		Value: "",
 	}
+	// ProposerSettingsRefreshIntervalFlag defines how often proposer settings are downloaded again from the proposer settings URL.
+	ProposerSettingsRefreshIntervalFlag = &cli.DurationFlag{
+		Name: "proposer-settings-refresh-interval",
+		Usage: `Interval at which proposer settings are downloaded again from --proposer-settings-url. Changed settings
+		replace the current ones and are sent to the beacon node, overwriting changes made through the keymanager API.
+		A zero value disables the refresh.`,
+		Value: 0,
+	}
+	// ProposerSettingsSignatureKeyFlag defines the public key which must have signed the proposer settings URL content.
+	ProposerSettingsSignatureKeyFlag = &cli.StringFlag{
+		Name: "proposer-settings-signature-key",
+		Usage: `Hex encoded ed25519 public key which must have signed proposer settings downloaded from --proposer-settings-url.
+		The hex encoded detached signature is downloaded from the same URL with a .sig suffix appended to its path.`,
+		Value: "",
+	}
 	// SuggestedFeeRecipientFlag defines the address of the fee recipient.
 	SuggestedFeeRecipientFlag = &cli.StringFlag{
 		Name: "suggested-fee-recipient",
@@ -389,6 +404,51 @@ var (
 		Usage: "To enable the use of prysm validator client in Distributed Validator Cluster",
 		Value: false,
 	}
+	// KeyLeaseDirFlag defines a directory shared by validator clients to store the leases of their keys.
+	KeyLeaseDirFlag = &cli.StringFlag{
+		Name: "key-lease-dir",
+		Usage: `Directory, shared by the validator clients running the same keys, in which key leases are stored.
+		The validator client refuses to sign with keys whose lease it does not hold.`,
+	}
+	// KeyLeaseURLFlag defines the url of a validator client REST API serving key leases.
+	KeyLeaseURLFlag = &cli.StringFlag{
+		Name: "key-lease-url",
+		Usage: `URL of the REST API of the validator client holding the key lease store, as an alternative to --` +
+			KeyLeaseDirFlag.Name + `. The remote validator client must be started with --` + KeyLeaseDirFlag.Name + ".",
+	}
+	// KeyLeaseTokenFileFlag defines the path to the auth token of the validator client REST API serving key leases.
+	KeyLeaseTokenFileFlag = &cli.StringFlag{
+		Name:  "key-lease-token-file",
+		Usage: "Path to a file containing the auth token of the REST API given by --" + KeyLeaseURLFlag.Name + ".",
+	}
+	// KeyLeaseOwnerFlag defines the name under which the validator client acquires key leases.
+	KeyLeaseOwnerFlag = &cli.StringFlag{
+		Name:  "key-lease-owner",
+		Usage: "Name under which this validator client acquires key leases. Defaults to the hostname.",
+	}
+	// KeyLeaseDurationFlag defines how long a key lease lasts before it has to be renewed.
+	KeyLeaseDurationFlag = &cli.DurationFlag{
+		Name:  "key-lease-duration",
+		Usage: "Duration of a key lease. Leases are renewed every third of their duration.",
+		Value: 2 * time.Minute,
+	}
+	// HandoverToFlag defines the owner to which key leases are handed over.
+	HandoverToFlag = &cli.StringFlag{
+		Name:  "handover-to",
+		Usage: "Key lease owner to hand the selected validator keys over to.",
+	}
+	// HandoverPublicKeysFlag defines a comma-separated list of hex string public keys to hand over.
+	HandoverPublicKeysFlag = &cli.StringFlag{
+		Name:  "handover-public-keys",
+		Usage: "Comma separated list of public key hex strings to hand over. Defaults to every key leased to --" + KeyLeaseOwnerFlag.Name + ".",
+		Value: "",
+	}
+	// ReleasePublicKeysFlag defines a comma-separated list of hex string public keys whose leases to release.
+	ReleasePublicKeysFlag = &cli.StringFlag{
+		Name:  "release-public-keys",
+		Usage: "Comma separated list of public key hex strings whose leases to release. Defaults to every key leased to --" + KeyLeaseOwnerFlag.Name + ".",
+		Value: "",
+	}
 )
 
 // DefaultValidatorDir returns OS-specific default validator directory.
@@ -75,12 +75,19 @@ var appFlags = []cli.Flag{
 	flags.GraffitiFileFlag,
 	flags.EnableDistributed,
 	flags.AuthTokenPathFlag,
+	flags.KeyLeaseDirFlag,
+	flags.KeyLeaseURLFlag,
+	flags.KeyLeaseTokenFileFlag,
+	flags.KeyLeaseOwnerFlag,
+	flags.KeyLeaseDurationFlag,
 	// Consensys' Web3Signer flags
 	flags.Web3SignerURLFlag,
 	flags.Web3SignerPublicValidatorKeysFlag,
 	flags.Web3SignerKeyFileFlag,
 	flags.SuggestedFeeRecipientFlag,
 	flags.ProposerSettingsURLFlag,
+	flags.ProposerSettingsRefreshIntervalFlag,
+	flags.ProposerSettingsSignatureKeyFlag,
 	flags.ProposerSettingsFlag,
 	flags.EnableBuilderFlag,
 	flags.BuilderGasLimitFlag,
@@ -113,6 +113,8 @@ var appHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			flags.ProposerSettingsFlag,
 			flags.ProposerSettingsURLFlag,
+			flags.ProposerSettingsRefreshIntervalFlag,
+			flags.ProposerSettingsSignatureKeyFlag,
 			flags.SuggestedFeeRecipientFlag,
 			flags.EnableBuilderFlag,
 			flags.BuilderGasLimitFlag,
@@ -129,6 +131,16 @@ var appHelpFlagGroups = []flagGroup{
 			flags.Web3SignerKeyFileFlag,
 		},
 	},
+	{
+		Name: "key lease",
+		Flags: []cli.Flag{
+			flags.KeyLeaseDirFlag,
+			flags.KeyLeaseURLFlag,
+			flags.KeyLeaseTokenFileFlag,
+			flags.KeyLeaseOwnerFlag,
+			flags.KeyLeaseDurationFlag,
+		},
+	},
 	{
 		Name: "slasher",
 		Flags: []cli.Flag{
@@ -64,8 +64,9 @@ type Flags struct {
 	EnableSlashingProtectionPruning bool // Enable slashing protection pruning for the validator client.
 	EnableMinimalSlashingProtection bool // Enable minimal slashing protection database for the validator client.
 
-	SaveFullExecutionPayloads bool // Save full beacon blocks with execution payloads in the database.
-	EnableStartOptimistic     bool // EnableStartOptimistic treats every block as optimistic at startup.
+	SaveFullExecutionPayloads    bool // Save full beacon blocks with execution payloads in the database.
+	EnableValidatorIdentityIndex bool // EnableValidatorIdentityIndex maintains the historical validator identity index in the database.
+	EnableStartOptimistic        bool // EnableStartOptimistic treats every block as optimistic at startup.
 
 	DisableResourceManager     bool // Disables running the node with libp2p's resource manager.
 	DisableStakinContractCheck bool // Disables check for deposit contract when proposing blocks
@@ -226,6 +227,10 @@ func ConfigureBeaconChain(ctx *cli.Context) error {
 		logEnabled(SaveFullExecutionPayloads)
 		cfg.SaveFullExecutionPayloads = true
 	}
+	if ctx.Bool(enableValidatorIdentityIndex.Name) {
+		logEnabled(enableValidatorIdentityIndex)
+		cfg.EnableValidatorIdentityIndex = true
+	}
 	if ctx.Bool(enableStartupOptimistic.Name) {
 		logEnabled(enableStartupOptimistic)
 		cfg.EnableStartOptimistic = true
@@ -34,7 +34,7 @@ var (
 	}
 	writeSSZStateTransitionsFlag = &cli.BoolFlag{
 		Name:  "interop-write-ssz-state-transitions",
-		Usage: "Writes SSZ states to disk after attempted state transitio.",
+		Usage: "Writes SSZ states to disk after attempted state transition, with a JSON trace of the state changes of each transition.",
 	}
 	saveInvalidBlockTempFlag = &cli.BoolFlag{
 		Name:  "save-invalid-block-temp",
@@ -131,6 +131,12 @@ var (
 		Name:  "save-full-execution-payloads",
 		Usage: "Saves beacon blocks with full execution payloads instead of execution payload headers in the database.",
 	}
+	enableValidatorIdentityIndex = &cli.BoolFlag{
+		Name: "enable-validator-identity-index",
+		Usage: "Maintains an index of the public key, index, activation and exit epochs and withdrawal credentials " +
+			"history of every validator in the database, updated on finalization and served by /prysm/v1/validators/lookup. " +
+			"History from before the flag was enabled is not backfilled: credentials are recorded from the first indexed epoch.",
+	}
 	EnableBeaconRESTApi = &cli.BoolFlag{
 		Name:  "enable-beacon-rest-api",
 		Usage: "(Experimental): Enables of the beacon REST API when querying a beacon node.",
@@ -221,6 +227,7 @@ var BeaconChainFlags = append(deprecatedBeaconFlags, append(deprecatedFlags, []c
 	enableHistoricalSpaceRepresentation,
 	disableStakinContractCheck,
 	SaveFullExecutionPayloads,
+	enableValidatorIdentityIndex,
 	enableStartupOptimistic,
 	enableFullSSZDataLogging,
 	disableVerboseSigVerification,
@@ -0,0 +1,115 @@
+package loader
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/config/proposer"
+	log "github.com/sirupsen/logrus"
+)
+
+// optionSummary holds the values of a proposer option that are logged when they change.
+type optionSummary struct {
+	feeRecipient   string
+	builderEnabled bool
+	gasLimit       uint64
+	relays         string
+	minBid         uint64
+	graffiti       string
+}
+
+func summarize(o *proposer.Option) optionSummary {
+	s := optionSummary{feeRecipient: "none", relays: "none", graffiti: "none"}
+	if o == nil {
+		return s
+	}
+	if o.FeeRecipientConfig != nil {
+		s.feeRecipient = o.FeeRecipientConfig.FeeRecipient.Hex()
+	}
+	if o.BuilderConfig != nil {
+		s.builderEnabled = o.BuilderConfig.Enabled
+		s.gasLimit = uint64(o.BuilderConfig.GasLimit)
+		s.minBid = uint64(o.BuilderConfig.MinBid)
+		if len(o.BuilderConfig.Relays) != 0 {
+			s.relays = strings.Join(o.BuilderConfig.Relays, ",")
+		}
+	}
+	if o.GraffitiConfig != nil {
+		s.graffiti = o.GraffitiConfig.Graffiti
+	}
+	return s
+}
+
+// diffFields returns a log field with the old and new value of every changed value of the option.
+func diffFields(old, new *proposer.Option) log.Fields {
+	o, n := summarize(old), summarize(new)
+	fields := log.Fields{}
+	if o.feeRecipient != n.feeRecipient {
+		fields["feeRecipient"] = fmt.Sprintf("%s -> %s", o.feeRecipient, n.feeRecipient)
+	}
+	if o.builderEnabled != n.builderEnabled {
+		fields["builderEnabled"] = fmt.Sprintf("%t -> %t", o.builderEnabled, n.builderEnabled)
+	}
+	if o.gasLimit != n.gasLimit {
+		fields["gasLimit"] = fmt.Sprintf("%d -> %d", o.gasLimit, n.gasLimit)
+	}
+	if o.relays != n.relays {
+		fields["relays"] = fmt.Sprintf("%s -> %s", o.relays, n.relays)
+	}
+	if o.minBid != n.minBid {
+		fields["minBid"] = fmt.Sprintf("%d -> %d", o.minBid, n.minBid)
+	}
+	if o.graffiti != n.graffiti {
+		fields["graffiti"] = fmt.Sprintf("%s -> %s", o.graffiti, n.graffiti)
+	}
+	return fields
+}
+
+// logSettingsDiff logs the changes to the default and to every per key proposer option between two proposer settings.
+func logSettingsDiff(old, new *proposer.Settings) {
+	var oldDefault, newDefault *proposer.Option
+	oldConfig := map[[fieldparams.BLSPubkeyLength]byte]*proposer.Option{}
+	newConfig := map[[fieldparams.BLSPubkeyLength]byte]*proposer.Option{}
+	if old != nil {
+		oldDefault = old.DefaultConfig
+		if old.ProposeConfig != nil {
+			oldConfig = old.ProposeConfig
+		}
+	}
+	if new != nil {
+		newDefault = new.DefaultConfig
+		if new.ProposeConfig != nil {
+			newConfig = new.ProposeConfig
+		}
+	}
+
+	changed := 0
+	if fields := diffFields(oldDefault, newDefault); len(fields) != 0 {
+		log.WithFields(fields).Info("Default proposer settings changed")
+		changed++
+	}
+	keys := make([][fieldparams.BLSPubkeyLength]byte, 0, len(oldConfig)+len(newConfig))
+	for k := range oldConfig {
+		keys = append(keys, k)
+	}
+	for k := range newConfig {
+		if _, ok := oldConfig[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i][:], keys[j][:]) < 0
+	})
+	for _, k := range keys {
+		fields := diffFields(oldConfig[k], newConfig[k])
+		if len(fields) == 0 {
+			continue
+		}
+		log.WithFields(fields).WithField("pubkey", fmt.Sprintf("%#x", k)).Info("Proposer settings changed")
+		changed++
+	}
+	log.WithField("changedCount", changed).Info("Proposer settings refreshed from URL")
+}
@@ -1,9 +1,13 @@
 package loader
 
 import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
@@ -16,6 +20,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/validator/db/iface"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
+	"google.golang.org/protobuf/proto"
 )
 
 type settingsType int
@@ -33,6 +38,11 @@ type settingsLoader struct {
 	existsInDB  bool
 	db          iface.ValidatorDB
 	options     *flagOptions
+	fetcher     *URLFetcher
+	// baseline holds the settings that the URL settings are merged over, on load and on refresh.
+	// It is built from the flags only, so that settings previously loaded from the URL and saved
+	// in the DB never outlive their removal at the URL.
+	baseline *validatorpb.ProposerSettingsPayload
 }
 
 type flagOptions struct {
@@ -93,6 +103,17 @@ func NewProposerSettingsLoader(cliCtx *cli.Context, db iface.ValidatorDB, opts .
 		psl.loadMethods = append(psl.loadMethods, fileFlag)
 	}
 	if cliCtx.IsSet(flags.ProposerSettingsURLFlag.Name) {
+		var key ed25519.PublicKey
+		if k := cliCtx.String(flags.ProposerSettingsSignatureKeyFlag.Name); k != "" {
+			key, err = hex.DecodeString(strings.TrimPrefix(k, "0x"))
+			if err != nil {
+				return nil, errors.Wrapf(err, "could not decode --%s", flags.ProposerSettingsSignatureKeyFlag.Name)
+			}
+		}
+		psl.fetcher, err = NewURLFetcher(cliCtx.String(flags.ProposerSettingsURLFlag.Name), key)
+		if err != nil {
+			return nil, err
+		}
 		psl.loadMethods = append(psl.loadMethods, urlFlag)
 	}
 	if len(psl.loadMethods) == 0 {
@@ -138,6 +159,9 @@ func (psl *settingsLoader) Load(cliCtx *cli.Context) (*proposer.Settings, error)
 		}())
 	}
 
+	// settings set by flags, which URL settings are merged over instead of the DB settings
+	baseline := &validatorpb.ProposerSettingsPayload{}
+
 	// start to process based on load method
 	for _, method := range psl.loadMethods {
 		switch method {
@@ -160,6 +184,7 @@ func (psl *settingsLoader) Load(cliCtx *cli.Context) (*proposer.Settings, error)
 				log.Debug("Overriding previously saved proposer default settings.")
 			}
 			loadConfig.DefaultConfig = defaultConfig
+			baseline.DefaultConfig = proto.Clone(defaultConfig).(*validatorpb.ProposerOptionPayload)
 		case fileFlag:
 			var settingFromFile *validatorpb.ProposerSettingsPayload
 			if err := config.UnmarshalFromFile(cliCtx.String(flags.ProposerSettingsFlag.Name), &settingFromFile); err != nil {
@@ -171,14 +196,12 @@ func (psl *settingsLoader) Load(cliCtx *cli.Context) (*proposer.Settings, error)
 			loadConfig = psl.processProposerSettings(settingFromFile, loadConfig)
 			log.WithField(flags.ProposerSettingsFlag.Name, cliCtx.String(flags.ProposerSettingsFlag.Name)).Info("Proposer settings loaded from file")
 		case urlFlag:
-			var settingFromURL *validatorpb.ProposerSettingsPayload
-			if err := config.UnmarshalFromURL(cliCtx.Context, cliCtx.String(flags.ProposerSettingsURLFlag.Name), &settingFromURL); err != nil {
+			settingFromURL, err := psl.fetcher.Fetch(cliCtx.Context)
+			if err != nil {
 				return nil, err
 			}
-			if settingFromURL == nil {
-				return nil, errors.New("proposer settings is empty after unmarshalling from url")
-			}
-			loadConfig = psl.processProposerSettings(settingFromURL, loadConfig)
+			psl.baseline = baseline
+			loadConfig = psl.processProposerSettings(settingFromURL, proto.Clone(baseline).(*validatorpb.ProposerSettingsPayload))
 			log.WithField(flags.ProposerSettingsURLFlag.Name, cliCtx.String(flags.ProposerSettingsURLFlag.Name)).Infof("Proposer settings loaded from URL")
 		case onlyDB:
 			loadConfig = psl.processProposerSettings(nil, loadConfig)
@@ -215,6 +238,37 @@ func (psl *settingsLoader) Load(cliCtx *cli.Context) (*proposer.Settings, error)
 	return ps, nil
 }
 
+// Refresh downloads the proposer settings from the proposer settings URL again and returns them
+// merged over the settings set by flags, so that keys removed at the URL are dropped. Changes made
+// through the keymanager API are overwritten as well, as the URL is the source of truth for the
+// proposer settings. Nil settings are returned if the settings have not changed since they were last downloaded.
+func (psl *settingsLoader) Refresh(ctx context.Context, current *proposer.Settings) (*proposer.Settings, error) {
+	if psl.fetcher == nil {
+		return nil, fmt.Errorf("proposer settings can only be refreshed from --%s", flags.ProposerSettingsURLFlag.Name)
+	}
+	settingFromURL, err := psl.fetcher.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if settingFromURL == nil {
+		return nil, nil
+	}
+	var baseline *validatorpb.ProposerSettingsPayload
+	if psl.baseline != nil {
+		baseline = proto.Clone(psl.baseline).(*validatorpb.ProposerSettingsPayload)
+	}
+	loadConfig := psl.processProposerSettings(settingFromURL, baseline)
+	if loadConfig == nil {
+		return nil, errors.New("proposer settings is empty after refreshing from url")
+	}
+	ps, err := proposer.SettingFromConsensus(loadConfig)
+	if err != nil {
+		return nil, err
+	}
+	logSettingsDiff(current, ps)
+	return ps, nil
+}
+
 func (psl *settingsLoader) processProposerSettings(loadedSettings, dbSettings *validatorpb.ProposerSettingsPayload) *validatorpb.ProposerSettingsPayload {
 	if loadedSettings == nil && dbSettings == nil {
 		return nil
@@ -1003,3 +1003,97 @@ func Test_ProposerSettingsLoaderWithOnlyBuilder_DoesNotSaveInDB(t *testing.T) {
 		})
 	}
 }
+
+func TestProposerSettingsLoader_Refresh(t *testing.T) {
+	hook := logtest.NewGlobal()
+	body := `{"proposer_config":{"0xa057816155ad77931185101128655c0191bd0214c201ca48ed887f6c4c6adf334070efcd75140eada5ac83a92506dd7a":{"fee_recipient":"0x50155530FCE8a85ec7055A5F8b2bE214B3DaeFd3"}},"default_config":{"fee_recipient":"0x6e35733c5af9B61374A128e6F85f553aF09ff89A"}}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprint(w, body)
+		require.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	app := cli.App{}
+	set := flag.NewFlagSet("test", 0)
+	set.String(flags.ProposerSettingsURLFlag.Name, "", "")
+	require.NoError(t, set.Set(flags.ProposerSettingsURLFlag.Name, srv.URL))
+	cliCtx := cli.NewContext(&app, set, nil)
+	validatorDB := dbTest.SetupDB(t, [][fieldparams.BLSPubkeyLength]byte{}, false)
+	loader, err := NewProposerSettingsLoader(cliCtx, validatorDB)
+	require.NoError(t, err)
+	current, err := loader.Load(cliCtx)
+	require.NoError(t, err)
+
+	// Unchanged settings are not returned.
+	got, err := loader.Refresh(cliCtx.Context, current)
+	require.NoError(t, err)
+	assert.Equal(t, true, got == nil)
+
+	body = `{"proposer_config":{"0xa057816155ad77931185101128655c0191bd0214c201ca48ed887f6c4c6adf334070efcd75140eada5ac83a92506dd7a":{"fee_recipient":"0xb20a608c624Ca5003905aA834De7156C68b2E1d0"}},"default_config":{"fee_recipient":"0x6e35733c5af9B61374A128e6F85f553aF09ff89A"}}`
+	got, err = loader.Refresh(cliCtx.Context, current)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	key := bytesutil.ToBytes48(hexutil.MustDecode("0xa057816155ad77931185101128655c0191bd0214c201ca48ed887f6c4c6adf334070efcd75140eada5ac83a92506dd7a"))
+	assert.Equal(t, common.HexToAddress("0xb20a608c624Ca5003905aA834De7156C68b2E1d0"), got.ProposeConfig[key].FeeRecipientConfig.FeeRecipient)
+	assert.DeepEqual(t, current.DefaultConfig, got.DefaultConfig)
+	assert.LogsContain(t, hook, "feeRecipient=\"0x50155530FCE8a85ec7055A5F8b2bE214B3DaeFd3 -> 0xb20a608c624Ca5003905aA834De7156C68b2E1d0\"")
+	assert.LogsContain(t, hook, "changedCount=1")
+
+	// Keys removed at the URL are dropped rather than kept from the current settings.
+	body = `{"default_config":{"fee_recipient":"0x6e35733c5af9B61374A128e6F85f553aF09ff89A"}}`
+	got, err = loader.Refresh(cliCtx.Context, got)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, 0, len(got.ProposeConfig))
+	assert.DeepEqual(t, current.DefaultConfig, got.DefaultConfig)
+
+	// Refreshing is only possible from a URL.
+	set = flag.NewFlagSet("test", 0)
+	loader, err = NewProposerSettingsLoader(cli.NewContext(&app, set, nil), validatorDB)
+	require.NoError(t, err)
+	_, err = loader.Refresh(context.Background(), current)
+	require.ErrorContains(t, "can only be refreshed", err)
+}
+
+func TestProposerSettingsLoader_URLDropsKeysSavedInDB(t *testing.T) {
+	body := `{"default_config":{"fee_recipient":"0x6e35733c5af9B61374A128e6F85f553aF09ff89A"}}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprint(w, body)
+		require.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	// The DB holds a key loaded from the URL before the restart, which was since removed at the URL.
+	validatorDB := dbTest.SetupDB(t, [][fieldparams.BLSPubkeyLength]byte{}, false)
+	key := bytesutil.ToBytes48(hexutil.MustDecode("0xa057816155ad77931185101128655c0191bd0214c201ca48ed887f6c4c6adf334070efcd75140eada5ac83a92506dd7a"))
+	require.NoError(t, validatorDB.SaveProposerSettings(context.Background(), &proposer.Settings{
+		ProposeConfig: map[[fieldparams.BLSPubkeyLength]byte]*proposer.Option{
+			key: {FeeRecipientConfig: &proposer.FeeRecipientConfig{FeeRecipient: common.HexToAddress("0x50155530FCE8a85ec7055A5F8b2bE214B3DaeFd3")}},
+		},
+		DefaultConfig: &proposer.Option{
+			FeeRecipientConfig: &proposer.FeeRecipientConfig{FeeRecipient: common.HexToAddress("0x6e35733c5af9B61374A128e6F85f553aF09ff89A")},
+		},
+	}))
+
+	app := cli.App{}
+	set := flag.NewFlagSet("test", 0)
+	set.String(flags.ProposerSettingsURLFlag.Name, "", "")
+	require.NoError(t, set.Set(flags.ProposerSettingsURLFlag.Name, srv.URL))
+	set.String(flags.SuggestedFeeRecipientFlag.Name, "", "")
+	require.NoError(t, set.Set(flags.SuggestedFeeRecipientFlag.Name, "0xb20a608c624Ca5003905aA834De7156C68b2E1d0"))
+	cliCtx := cli.NewContext(&app, set, nil)
+	loader, err := NewProposerSettingsLoader(cliCtx, validatorDB)
+	require.NoError(t, err)
+	current, err := loader.Load(cliCtx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(current.ProposeConfig))
+	assert.Equal(t, common.HexToAddress("0x6e35733c5af9B61374A128e6F85f553aF09ff89A"), current.DefaultConfig.FeeRecipientConfig.FeeRecipient)
+
+	// Refreshed settings are merged over the flag settings, not over the DB settings.
+	body = `{"proposer_config":{}}`
+	got, err := loader.Refresh(cliCtx.Context, current)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, 0, len(got.ProposeConfig))
+	assert.Equal(t, common.HexToAddress("0xb20a608c624Ca5003905aA834De7156C68b2E1d0"), got.DefaultConfig.FeeRecipientConfig.FeeRecipient)
+}
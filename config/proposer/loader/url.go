@@ -0,0 +1,150 @@
+package loader
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	validatorpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1/validator-client"
+	log "github.com/sirupsen/logrus"
+)
+
+// signatureSuffix is appended to the path of the proposer settings URL to locate the detached signature of the settings.
+const signatureSuffix = ".sig"
+
+// fetchTimeout bounds each request for the proposer settings or their signature, so that an
+// unresponsive server cannot stall the refresh of the settings.
+const fetchTimeout = 30 * time.Second
+
+// maxSettingsSize bounds the size of proposer settings and signatures downloaded from a URL.
+const maxSettingsSize = 32 << 20
+
+// URLFetcher downloads proposer settings from a URL. It remembers the ETag and the content of the
+// last response so that unchanged settings are neither downloaded nor applied again, and verifies
+// the detached ed25519 signature of the settings when a signature key is configured.
+type URLFetcher struct {
+	url          string
+	signatureURL string
+	signatureKey ed25519.PublicKey
+	client       *http.Client
+	etag         string
+	digest       [32]byte
+}
+
+// NewURLFetcher returns a fetcher for the proposer settings at the given URL. When signatureKey is
+// not nil, the settings must be signed by it; the hex encoded signature of the settings is downloaded from the same
+// URL with a .sig suffix appended to its path.
+func NewURLFetcher(from string, signatureKey ed25519.PublicKey) (*URLFetcher, error) {
+	u, err := url.ParseRequestURI(from)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid URL: %s", from)
+	}
+	if signatureKey != nil && len(signatureKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("signature key must be %d bytes long, got %d", ed25519.PublicKeySize, len(signatureKey))
+	}
+	u.Path += signatureSuffix
+	return &URLFetcher{
+		url:          from,
+		signatureURL: u.String(),
+		signatureKey: signatureKey,
+		client:       &http.Client{Timeout: fetchTimeout},
+	}, nil
+}
+
+// Fetch downloads the proposer settings. It returns nil settings without an error if they have not
+// changed since the previous successful call.
+func (f *URLFetcher) Fetch(ctx context.Context) (*validatorpb.ProposerSettingsPayload, error) {
+	body, etag, err := f.get(ctx, f.url, f.etag)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, nil
+	}
+	digest := sha256.Sum256(body)
+	if digest == f.digest {
+		f.etag = etag
+		return nil, nil
+	}
+	if f.signatureKey != nil {
+		if err := f.verify(ctx, body); err != nil {
+			return nil, err
+		}
+	}
+	var settings *validatorpb.ProposerSettingsPayload
+	if err := json.Unmarshal(body, &settings); err != nil {
+		return nil, errors.Wrap(err, "failed to decode http response")
+	}
+	if settings == nil {
+		return nil, errors.New("proposer settings is empty after unmarshalling from url")
+	}
+	f.etag, f.digest = etag, digest
+	return settings, nil
+}
+
+func (f *URLFetcher) verify(ctx context.Context, body []byte) error {
+	enc, _, err := f.get(ctx, f.signatureURL, "")
+	if err != nil {
+		return errors.Wrap(err, "could not get proposer settings signature")
+	}
+	sig, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(enc)), "0x"))
+	if err != nil {
+		return errors.Wrap(err, "could not decode proposer settings signature")
+	}
+	if !ed25519.Verify(f.signatureKey, body, sig) {
+		return errors.Errorf("invalid signature of proposer settings from %s", f.url)
+	}
+	return nil
+}
+
+// get performs a GET request, conditional on the given ETag if any. A nil body is returned if the
+// resource has not been modified.
+func (f *URLFetcher) get(ctx context.Context, from string, etag string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, from, nil)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to create http request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to send http request")
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.WithError(err).Error("Failed to close response body")
+		}
+	}()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		if etag != "" {
+			return nil, etag, nil
+		}
+		fallthrough
+	default:
+		return nil, "", errors.Errorf("http request to %v failed with status code %d", from, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSettingsSize+1))
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to read http response")
+	}
+	if len(body) > maxSettingsSize {
+		return nil, "", errors.Errorf("http response from %v is larger than %d bytes", from, maxSettingsSize)
+	}
+	return body, resp.Header.Get("ETag"), nil
+}
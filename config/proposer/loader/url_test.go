@@ -0,0 +1,111 @@
+package loader
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+const urlSettings = `{"default_config":{"fee_recipient":"0x6e35733c5af9B61374A128e6F85f553aF09ff89A"}}`
+
+type settingsServer struct {
+	body      string
+	etag      string
+	signature string
+	requests  int
+	notMod    int
+}
+
+func (s *settingsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/settings.json"+signatureSuffix {
+		if s.signature == "" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(s.signature))
+		return
+	}
+	s.requests++
+	if s.etag != "" && r.Header.Get("If-None-Match") == s.etag {
+		s.notMod++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if s.etag != "" {
+		w.Header().Set("ETag", s.etag)
+	}
+	_, _ = w.Write([]byte(s.body))
+}
+
+func TestURLFetcher_Fetch(t *testing.T) {
+	ctx := context.Background()
+	s := &settingsServer{body: urlSettings, etag: `"1"`}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	f, err := NewURLFetcher(srv.URL+"/settings.json", nil)
+	require.NoError(t, err)
+	settings, err := f.Fetch(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, settings)
+	assert.Equal(t, "0x6e35733c5af9B61374A128e6F85f553aF09ff89A", settings.DefaultConfig.FeeRecipient)
+
+	// The ETag matches, the settings are not downloaded again.
+	settings, err = f.Fetch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, true, settings == nil)
+	assert.Equal(t, 1, s.notMod)
+
+	// A new ETag with the same content is not reported as a change.
+	s.etag = `"2"`
+	settings, err = f.Fetch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, true, settings == nil)
+	settings, err = f.Fetch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, true, settings == nil)
+	assert.Equal(t, 2, s.notMod)
+
+	s.etag = ""
+	s.body = `{"default_config":{"fee_recipient":"0xb20a608c624Ca5003905aA834De7156C68b2E1d0"}}`
+	settings, err = f.Fetch(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, settings)
+	assert.Equal(t, "0xb20a608c624Ca5003905aA834De7156C68b2E1d0", settings.DefaultConfig.FeeRecipient)
+	assert.Equal(t, 5, s.requests)
+}
+
+func TestURLFetcher_Signature(t *testing.T) {
+	ctx := context.Background()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	s := &settingsServer{body: urlSettings}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	f, err := NewURLFetcher(srv.URL+"/settings.json", pub)
+	require.NoError(t, err)
+	_, err = f.Fetch(ctx)
+	require.ErrorContains(t, "could not get proposer settings signature", err)
+
+	_, other, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	s.signature = hex.EncodeToString(ed25519.Sign(other, []byte(urlSettings)))
+	_, err = f.Fetch(ctx)
+	require.ErrorContains(t, "invalid signature", err)
+
+	s.signature = "0x" + hex.EncodeToString(ed25519.Sign(priv, []byte(urlSettings))) + "\n"
+	settings, err := f.Fetch(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, settings)
+
+	_, err = NewURLFetcher(srv.URL, pub[:16])
+	require.ErrorContains(t, "signature key must be 32 bytes long", err)
+}
@@ -78,10 +78,13 @@ func verifyOption(key string, option *validatorpb.ProposerOptionPayload) error {
 
 // BuilderConfig is the struct representation of the JSON config file set in the validator through the CLI.
 // GasLimit is a number set to help the network decide on the maximum gas in each block.
+// Relays restricts the relays the beacon node solicits bids from, and MinBid overrides the minimum
+// bid in gwei that the beacon node accepts from a builder.
 type BuilderConfig struct {
 	Enabled  bool             `json:"enabled" yaml:"enabled"`
 	GasLimit validator.Uint64 `json:"gas_limit,omitempty" yaml:"gas_limit,omitempty"`
 	Relays   []string         `json:"relays,omitempty" yaml:"relays,omitempty"`
+	MinBid   validator.Uint64 `json:"min_bid,omitempty" yaml:"min_bid,omitempty"`
 }
 
 // BuilderConfigFromConsensus converts protobuf to a builder config used in in-memory storage
@@ -92,6 +95,7 @@ func BuilderConfigFromConsensus(from *validatorpb.BuilderConfig) *BuilderConfig
 	c := &BuilderConfig{
 		Enabled:  from.Enabled,
 		GasLimit: from.GasLimit,
+		MinBid:   from.MinBid,
 	}
 	if from.Relays != nil {
 		relays := make([]string, len(from.Relays))
@@ -224,6 +228,7 @@ func (bc *BuilderConfig) Clone() *BuilderConfig {
 	c := &BuilderConfig{}
 	c.Enabled = bc.Enabled
 	c.GasLimit = bc.GasLimit
+	c.MinBid = bc.MinBid
 	var relays []string
 	if bc.Relays != nil {
 		relays = make([]string, len(bc.Relays))
@@ -255,5 +260,6 @@ func (bc *BuilderConfig) ToConsensus() *validatorpb.BuilderConfig {
 		c.Relays = relays
 	}
 	c.GasLimit = bc.GasLimit
+	c.MinBid = bc.MinBid
 	return c
 }
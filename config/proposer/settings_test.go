@@ -26,6 +26,7 @@ func Test_Proposer_Setting_Cloning(t *testing.T) {
 					Enabled:  true,
 					GasLimit: validator.Uint64(40000000),
 					Relays:   []string{"https://example-relay.com"},
+					MinBid:   validator.Uint64(100),
 				},
 			},
 		},
@@ -65,6 +66,7 @@ func Test_Proposer_Setting_Cloning(t *testing.T) {
 		require.DeepEqual(t, config.Relays, clone.Relays)
 		require.Equal(t, config.Enabled, clone.Enabled)
 		require.Equal(t, config.GasLimit, clone.GasLimit)
+		require.Equal(t, config.MinBid, clone.MinBid)
 	})
 	t.Run("To Payload and SettingFromConsensus", func(t *testing.T) {
 		payload := settings.ToConsensus()
@@ -84,6 +86,7 @@ func Test_Proposer_Setting_Cloning(t *testing.T) {
 		require.Equal(t, option.FeeRecipientConfig.FeeRecipient.Hex(), noption.FeeRecipientConfig.FeeRecipient.Hex())
 		require.Equal(t, option.BuilderConfig.GasLimit, option.BuilderConfig.GasLimit)
 		require.Equal(t, option.BuilderConfig.Enabled, option.BuilderConfig.Enabled)
+		require.Equal(t, option.BuilderConfig.MinBid, noption.BuilderConfig.MinBid)
 	})
 }
 
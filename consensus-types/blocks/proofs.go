@@ -12,6 +12,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/container/trie"
 	"github.com/prysmaticlabs/prysm/v5/crypto/hash/htr"
 	"github.com/prysmaticlabs/prysm/v5/encoding/ssz"
+	"github.com/prysmaticlabs/prysm/v5/encoding/ssz/multiproof"
 	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
 	"github.com/prysmaticlabs/prysm/v5/runtime/version"
 )
@@ -250,3 +251,34 @@ func PayloadProof(ctx context.Context, block interfaces.ReadOnlyBeaconBlock) ([]
 
 	return proof, nil
 }
+
+// BlockMultiproof crafts a Merkle multiproof of the nodes at the given generalized indices of the
+// block's SSZ tree. The proof can descend into the fields of the block body, which are otherwise
+// proven as a whole.
+func BlockMultiproof(ctx context.Context, block interfaces.ReadOnlyBeaconBlock, gindices []uint64) (*multiproof.Multiproof, error) {
+	ctx, span := trace.StartSpan(ctx, "blocks.BlockMultiproof")
+	defer span.End()
+
+	fieldRoots, err := ComputeBlockFieldRoots(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+	blockBody, ok := block.Body().(*BeaconBlockBody)
+	if !ok {
+		return nil, errors.New("failed to cast block body")
+	}
+	bodyFieldRoots, err := ComputeBlockBodyFieldRoots(ctx, blockBody)
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]multiproof.Node, len(fieldRoots))
+	for i, r := range fieldRoots {
+		fields[i] = multiproof.Leaf([32]byte(r))
+	}
+	bodyFields := make([]multiproof.Node, len(bodyFieldRoots))
+	for i, r := range bodyFieldRoots {
+		bodyFields[i] = multiproof.Leaf([32]byte(r))
+	}
+	fields[bodyFieldIndex] = multiproof.Container(bodyFields)
+	return multiproof.Prove(multiproof.Container(fields), gindices)
+}
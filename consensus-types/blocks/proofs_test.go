@@ -5,6 +5,9 @@ import (
 	"testing"
 
 	"github.com/prysmaticlabs/prysm/v5/container/trie"
+	"github.com/prysmaticlabs/prysm/v5/encoding/ssz"
+	"github.com/prysmaticlabs/prysm/v5/encoding/ssz/multiproof"
+	eth "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
 	"github.com/prysmaticlabs/prysm/v5/testing/require"
 )
 
@@ -145,3 +148,33 @@ func TestComputeBlockBodyFieldRoots_Electra(t *testing.T) {
 
 	require.DeepEqual(t, correctHash[:], hash)
 }
+
+func TestBlockMultiproof(t *testing.T) {
+	ctx := context.Background()
+	blk, err := NewBeaconBlock(&eth.BeaconBlockDeneb{
+		Slot:          7,
+		ProposerIndex: 3,
+		ParentRoot:    make([]byte, 32),
+		StateRoot:     make([]byte, 32),
+		Body:          hydrateBeaconBlockBodyDeneb(),
+	})
+	require.NoError(t, err)
+	root, err := blk.HashTreeRoot()
+	require.NoError(t, err)
+	payloadRoot, err := blk.Body().(*BeaconBlockBody).executionPayload.HashTreeRoot()
+	require.NoError(t, err)
+
+	// The slot, and the execution payload within the body.
+	payload := multiproof.Concat(8+bodyFieldIndex, 16+payloadFieldIndex)
+	p, err := BlockMultiproof(ctx, blk, []uint64{8, payload})
+	require.NoError(t, err)
+	ok, err := p.Verify(root)
+	require.NoError(t, err)
+	require.Equal(t, true, ok)
+	require.DeepEqual(t, []uint64{8, payload}, p.Indices)
+	require.Equal(t, ssz.Uint64Root(7), p.Leaves[0])
+	require.Equal(t, payloadRoot, p.Leaves[1])
+
+	_, err = BlockMultiproof(ctx, blk, []uint64{multiproof.Concat(payload, 2)})
+	require.ErrorIs(t, err, multiproof.ErrLeaf)
+}
@@ -0,0 +1,48 @@
+package multiproof
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// Depth returns the depth in the tree of the node with the given generalized index.
+func Depth(gindex uint64) uint8 {
+	if gindex == 0 {
+		return 0
+	}
+	return uint8(bits.Len64(gindex) - 1)
+}
+
+// Concat returns the generalized index of a node from the generalized indices of each subtree on
+// its path, as in concat_generalized_indices of the specification.
+func Concat(gindices ...uint64) uint64 {
+	o := uint64(1)
+	for _, g := range gindices {
+		d := Depth(g)
+		o = o<<d | (g ^ uint64(1)<<d)
+	}
+	return o
+}
+
+// HelperIndices returns the generalized indices of the sibling nodes required to prove the nodes
+// at the given generalized indices, in decreasing order.
+func HelperIndices(indices []uint64) []uint64 {
+	helpers := make(map[uint64]bool)
+	paths := make(map[uint64]bool)
+	for _, index := range indices {
+		for g := index; g > 1; g /= 2 {
+			helpers[g^1] = true
+			paths[g] = true
+		}
+	}
+	result := make([]uint64, 0, len(helpers))
+	for g := range helpers {
+		if !paths[g] {
+			result = append(result, g)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] > result[j]
+	})
+	return result
+}
@@ -0,0 +1,203 @@
+package multiproof
+
+import (
+	"encoding/binary"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/crypto/hash"
+)
+
+// MaxIndices is the maximum number of generalized indices proven by a single multiproof.
+const MaxIndices = 256
+
+// ErrInvalidIndices is returned when the set of generalized indices to prove is invalid.
+var ErrInvalidIndices = errors.New("invalid generalized indices")
+
+// Multiproof proves the leaves at the given generalized indices against the root of a tree,
+// the hashes being the roots of the nodes at the helper indices of the leaves in decreasing order.
+type Multiproof struct {
+	Indices []uint64
+	Leaves  [][32]byte
+	Hashes  [][32]byte
+}
+
+// Prove builds a multiproof of the nodes at the given generalized indices in the tree.
+func Prove(root Node, indices []uint64) (*Multiproof, error) {
+	indices, err := normalize(indices)
+	if err != nil {
+		return nil, err
+	}
+	p := &Multiproof{
+		Indices: indices,
+		Leaves:  make([][32]byte, len(indices)),
+	}
+	for i, g := range indices {
+		n, err := Get(root, g)
+		if err != nil {
+			return nil, err
+		}
+		if p.Leaves[i], err = n.Root(); err != nil {
+			return nil, errors.Wrapf(err, "could not compute root of generalized index %d", g)
+		}
+	}
+	helpers := HelperIndices(indices)
+	p.Hashes = make([][32]byte, len(helpers))
+	for i, g := range helpers {
+		n, err := Get(root, g)
+		if err != nil {
+			return nil, err
+		}
+		if p.Hashes[i], err = n.Root(); err != nil {
+			return nil, errors.Wrapf(err, "could not compute root of generalized index %d", g)
+		}
+	}
+	return p, nil
+}
+
+// normalize sorts and deduplicates the indices, rejecting empty or overlapping sets. A proof of
+// a node along with one of its descendants would not bind the descendant to the root.
+func normalize(indices []uint64) ([]uint64, error) {
+	if len(indices) == 0 {
+		return nil, errors.Wrap(ErrInvalidIndices, "no generalized index to prove")
+	}
+	if len(indices) > MaxIndices {
+		return nil, errors.Wrapf(ErrInvalidIndices, "too many generalized indices: %d > %d", len(indices), MaxIndices)
+	}
+	set := make(map[uint64]bool, len(indices))
+	for _, g := range indices {
+		if g == 0 {
+			return nil, errors.Wrap(ErrInvalidIndices, "generalized index must be positive")
+		}
+		set[g] = true
+	}
+	result := make([]uint64, 0, len(set))
+	for g := range set {
+		for a := g / 2; a > 0; a /= 2 {
+			if set[a] {
+				return nil, errors.Wrapf(ErrInvalidIndices, "generalized index %d is a descendant of %d", g, a)
+			}
+		}
+		result = append(result, g)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+	return result, nil
+}
+
+// Root computes the root of the tree from the multiproof, as in calculate_multi_merkle_root of the specification.
+func (p *Multiproof) Root() ([32]byte, error) {
+	if len(p.Indices) != len(p.Leaves) {
+		return [32]byte{}, errors.Errorf("%d leaves for %d generalized indices", len(p.Leaves), len(p.Indices))
+	}
+	indices, err := normalize(p.Indices)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	if len(indices) != len(p.Indices) {
+		return [32]byte{}, errors.New("duplicate generalized indices")
+	}
+	helpers := HelperIndices(p.Indices)
+	if len(helpers) != len(p.Hashes) {
+		return [32]byte{}, errors.Errorf("%d hashes for %d helper indices", len(p.Hashes), len(helpers))
+	}
+	objects := make(map[uint64][32]byte, len(p.Indices)+len(helpers))
+	for i, g := range p.Indices {
+		objects[g] = p.Leaves[i]
+	}
+	for i, g := range helpers {
+		objects[g] = p.Hashes[i]
+	}
+	keys := make([]uint64, 0, len(objects))
+	for g := range objects {
+		keys = append(keys, g)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] > keys[j]
+	})
+	for pos := 0; pos < len(keys); pos++ {
+		k := keys[pos]
+		_, sibling := objects[k^1]
+		_, parent := objects[k/2]
+		if k > 1 && sibling && !parent {
+			left, right := objects[(k|1)^1], objects[k|1]
+			objects[k/2] = hash.Hash(append(left[:], right[:]...))
+			keys = append(keys, k/2)
+		}
+	}
+	root, ok := objects[1]
+	if !ok {
+		return [32]byte{}, errors.New("multiproof does not reach the root")
+	}
+	return root, nil
+}
+
+// Verify checks the multiproof against the given root.
+func (p *Multiproof) Verify(root [32]byte) (bool, error) {
+	r, err := p.Root()
+	if err != nil {
+		return false, err
+	}
+	return r == root, nil
+}
+
+// SizeSSZ returns the size of the SSZ serialization of the multiproof.
+func (p *Multiproof) SizeSSZ() int {
+	return 12 + 8*len(p.Indices) + 32*len(p.Leaves) + 32*len(p.Hashes)
+}
+
+// MarshalSSZ serializes the multiproof as the SSZ container
+// {indices: List[uint64], leaves: List[Bytes32], hashes: List[Bytes32]}.
+func (p *Multiproof) MarshalSSZ() ([]byte, error) {
+	if len(p.Indices) > MaxIndices {
+		return nil, errors.Errorf("too many generalized indices: %d > %d", len(p.Indices), MaxIndices)
+	}
+	buf := make([]byte, 12, p.SizeSSZ())
+	binary.LittleEndian.PutUint32(buf[0:4], 12)
+	binary.LittleEndian.PutUint32(buf[4:8], uint32(12+8*len(p.Indices)))
+	binary.LittleEndian.PutUint32(buf[8:12], uint32(12+8*len(p.Indices)+32*len(p.Leaves)))
+	for _, g := range p.Indices {
+		buf = binary.LittleEndian.AppendUint64(buf, g)
+	}
+	for _, l := range p.Leaves {
+		buf = append(buf, l[:]...)
+	}
+	for _, h := range p.Hashes {
+		buf = append(buf, h[:]...)
+	}
+	return buf, nil
+}
+
+// UnmarshalSSZ deserializes a multiproof serialized by MarshalSSZ.
+func (p *Multiproof) UnmarshalSSZ(buf []byte) error {
+	if len(buf) < 12 {
+		return errors.Errorf("multiproof of %d bytes is too short", len(buf))
+	}
+	o0 := uint64(binary.LittleEndian.Uint32(buf[0:4]))
+	o1 := uint64(binary.LittleEndian.Uint32(buf[4:8]))
+	o2 := uint64(binary.LittleEndian.Uint32(buf[8:12]))
+	if o0 != 12 || o1 < o0 || o2 < o1 || o2 > uint64(len(buf)) {
+		return errors.New("invalid multiproof offsets")
+	}
+	if (o1-o0)%8 != 0 || (o2-o1)%32 != 0 || (uint64(len(buf))-o2)%32 != 0 {
+		return errors.New("invalid multiproof list sizes")
+	}
+	n := (o1 - o0) / 8
+	if n > MaxIndices {
+		return errors.Errorf("too many generalized indices: %d > %d", n, MaxIndices)
+	}
+	p.Indices = make([]uint64, n)
+	for i := range p.Indices {
+		p.Indices[i] = binary.LittleEndian.Uint64(buf[o0+8*uint64(i):])
+	}
+	p.Leaves = make([][32]byte, (o2-o1)/32)
+	for i := range p.Leaves {
+		copy(p.Leaves[i][:], buf[o1+32*uint64(i):])
+	}
+	p.Hashes = make([][32]byte, (uint64(len(buf))-o2)/32)
+	for i := range p.Hashes {
+		copy(p.Hashes[i][:], buf[o2+32*uint64(i):])
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package multiproof
+
+import (
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/encoding/ssz"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func testChunks(n int) [][32]byte {
+	roots := make([][32]byte, n)
+	for i := range roots {
+		roots[i][0] = byte(i + 1)
+	}
+	return roots
+}
+
+func TestChunks_Root(t *testing.T) {
+	for _, n := range []int{0, 1, 3, 4, 7} {
+		roots := testChunks(n)
+		want, err := ssz.BitwiseMerkleize(roots, uint64(n), 16)
+		require.NoError(t, err)
+		node, err := Chunks(roots, 4, nil)
+		require.NoError(t, err)
+		got, err := node.Root()
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err := Chunks(testChunks(5), 2, nil)
+	require.ErrorContains(t, "do not fit", err)
+}
+
+func TestLayers_Root(t *testing.T) {
+	roots := testChunks(5)
+	chunks, err := Chunks(roots, 4, nil)
+	require.NoError(t, err)
+	// Layers as kept by field tries, without the zero padding of odd layers.
+	layers := make([][]*[32]byte, 5)
+	for level := range layers {
+		n := (len(roots) + 1<<level - 1) >> level
+		layers[level] = make([]*[32]byte, n)
+		for i := range layers[level] {
+			node, err := Get(chunks, uint64(1)<<(4-level)+uint64(i))
+			require.NoError(t, err)
+			r, err := node.Root()
+			require.NoError(t, err)
+			layers[level][i] = &r
+		}
+	}
+	node, err := Layers(layers, nil)
+	require.NoError(t, err)
+	for _, g := range []uint64{1, 2, 3, 16, 20, 21, 31} {
+		want, err := Get(chunks, g)
+		require.NoError(t, err)
+		got, err := Get(node, g)
+		require.NoError(t, err)
+		wantRoot, err := want.Root()
+		require.NoError(t, err)
+		gotRoot, err := got.Root()
+		require.NoError(t, err)
+		assert.Equal(t, wantRoot, gotRoot)
+	}
+}
+
+func TestContainer_Root(t *testing.T) {
+	roots := testChunks(5)
+	want, err := ssz.BitwiseMerkleize(roots, 5, 5)
+	require.NoError(t, err)
+	got, err := Roots(roots).Root()
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestConcat(t *testing.T) {
+	assert.Equal(t, uint64(1), Concat())
+	assert.Equal(t, uint64(5), Concat(5))
+	// The second field of a container of 4 fields, within the third field of a container of 8 fields.
+	assert.Equal(t, uint64(10<<2|1), Concat(10, 5))
+	assert.Equal(t, uint8(3), Depth(10))
+}
+
+func TestHelperIndices(t *testing.T) {
+	assert.DeepEqual(t, []uint64{9, 5, 3}, HelperIndices([]uint64{8}))
+	assert.DeepEqual(t, []uint64{7, 2}, HelperIndices([]uint64{12, 13}))
+}
+
+func TestProve_Verify(t *testing.T) {
+	inner := testChunks(3)
+	expanded := 0
+	innerRoot, err := Roots(inner).Root()
+	require.NoError(t, err)
+	roots := testChunks(6)
+	roots[2] = innerRoot
+	tree, err := Chunks(roots, 3, func(i uint64) (Node, error) {
+		expanded++
+		return Roots(inner), nil
+	})
+	require.NoError(t, err)
+	list := List(tree, 6)
+	root, err := list.Root()
+	require.NoError(t, err)
+
+	// The first field of the third chunk of the list data, and the list length.
+	indices := []uint64{Concat(2, 8+2, 4), 3}
+	p, err := Prove(list, indices)
+	require.NoError(t, err)
+	assert.Equal(t, 1, expanded)
+	assert.DeepEqual(t, []uint64{3, Concat(2, 8+2, 4)}, p.Indices)
+	assert.Equal(t, inner[0], p.Leaves[1])
+	assert.Equal(t, Uint64Leaf(6), Leaf(p.Leaves[0]))
+	ok, err := p.Verify(root)
+	require.NoError(t, err)
+	assert.Equal(t, true, ok)
+
+	p.Leaves[1][0]++
+	ok, err = p.Verify(root)
+	require.NoError(t, err)
+	assert.Equal(t, false, ok)
+
+	_, err = Prove(list, []uint64{Concat(2, 8+6, 4)})
+	require.ErrorIs(t, err, ErrLeaf)
+	_, err = Prove(list, []uint64{2, 4})
+	require.ErrorContains(t, "is a descendant of", err)
+	_, err = Prove(list, nil)
+	require.ErrorContains(t, "no generalized index", err)
+}
+
+func TestMultiproof_SSZ(t *testing.T) {
+	p, err := Prove(Roots(testChunks(7)), []uint64{9, 14})
+	require.NoError(t, err)
+	enc, err := p.MarshalSSZ()
+	require.NoError(t, err)
+	assert.Equal(t, p.SizeSSZ(), len(enc))
+	dec := &Multiproof{}
+	require.NoError(t, dec.UnmarshalSSZ(enc))
+	assert.DeepEqual(t, p, dec)
+
+	require.ErrorContains(t, "too short", dec.UnmarshalSSZ(enc[:8]))
+	require.ErrorContains(t, "invalid multiproof list sizes", dec.UnmarshalSSZ(enc[:len(enc)-1]))
+}
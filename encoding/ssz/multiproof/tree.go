@@ -0,0 +1,263 @@
+// Package multiproof implements generalized index based SSZ Merkle multiproofs as defined in
+// https://github.com/ethereum/consensus-specs/blob/dev/ssz/merkle-proofs.md, over lazily
+// expanded Merkle trees.
+package multiproof
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/container/trie"
+	"github.com/prysmaticlabs/prysm/v5/crypto/hash"
+	"github.com/prysmaticlabs/prysm/v5/crypto/hash/htr"
+)
+
+// ErrLeaf is returned when the children of a node that can not be expanded any further are requested.
+var ErrLeaf = errors.New("node is a leaf")
+
+// Node is a node of an SSZ Merkle tree. Subtrees are only materialized when their children are
+// requested, so that proofs into large lists do not require building the whole tree.
+type Node interface {
+	// Root returns the hash tree root of the subtree rooted at the node.
+	Root() ([32]byte, error)
+	// Children returns the left and right children of the node, or ErrLeaf if the node can not be expanded.
+	Children() (Node, Node, error)
+}
+
+type leaf [32]byte
+
+// Leaf returns a node with the given root that can not be expanded.
+func Leaf(root [32]byte) Node {
+	return leaf(root)
+}
+
+// Uint64Leaf returns a leaf holding the little endian serialization of the value.
+func Uint64Leaf(v uint64) Node {
+	var root [32]byte
+	binary.LittleEndian.PutUint64(root[:8], v)
+	return leaf(root)
+}
+
+func (l leaf) Root() ([32]byte, error) {
+	return l, nil
+}
+
+func (leaf) Children() (Node, Node, error) {
+	return nil, nil, ErrLeaf
+}
+
+type branch struct {
+	left, right Node
+	once        sync.Once
+	root        [32]byte
+	err         error
+}
+
+// Branch returns the parent node of the given nodes. Its root is computed on first use.
+func Branch(left, right Node) Node {
+	return &branch{left: left, right: right}
+}
+
+func (b *branch) Root() ([32]byte, error) {
+	b.once.Do(func() {
+		l, err := b.left.Root()
+		if err != nil {
+			b.err = err
+			return
+		}
+		r, err := b.right.Root()
+		if err != nil {
+			b.err = err
+			return
+		}
+		b.root = hash.Hash(append(l[:], r[:]...))
+	})
+	return b.root, b.err
+}
+
+func (b *branch) Children() (Node, Node, error) {
+	return b.left, b.right, nil
+}
+
+type lazy struct {
+	root   [32]byte
+	expand func() (Node, error)
+	once   sync.Once
+	node   Node
+	err    error
+}
+
+// Lazy returns a node with a known root whose subtree is built by expand the first time its
+// children are requested.
+func Lazy(root [32]byte, expand func() (Node, error)) Node {
+	return &lazy{root: root, expand: expand}
+}
+
+func (l *lazy) Root() ([32]byte, error) {
+	return l.root, nil
+}
+
+func (l *lazy) Children() (Node, Node, error) {
+	l.once.Do(func() {
+		l.node, l.err = l.expand()
+	})
+	if l.err != nil {
+		return nil, nil, l.err
+	}
+	return l.node.Children()
+}
+
+// chunks is a subtree of the given depth over a list of chunk roots, right padded with zero hashes.
+// The roots of its inner nodes are read from precomputed layers, while the subtrees of the chunks
+// themselves are expanded on demand.
+type chunks struct {
+	at     func(level uint8, index uint64) ([32]byte, bool)
+	count  uint64
+	expand func(i uint64) (Node, error)
+	lock   sync.Mutex
+	nodes  map[uint64]Node
+}
+
+type chunkNode struct {
+	c     *chunks
+	level uint8
+	index uint64
+}
+
+// Chunks returns the root node of a subtree of the given depth whose leftmost leaves are the given
+// chunk roots, the remaining leaves being zero. All its layers are hashed up front. When expand is
+// not nil, it is called to build the subtree of a chunk the first time its children are requested.
+func Chunks(roots [][32]byte, depth uint8, expand func(i uint64) (Node, error)) (Node, error) {
+	if int(depth) >= len(trie.ZeroHashes) {
+		return nil, errors.Errorf("depth %d is too large", depth)
+	}
+	if uint64(len(roots)) > uint64(1)<<depth {
+		return nil, errors.Errorf("%d chunks do not fit in a tree of depth %d", len(roots), depth)
+	}
+	layers := make([][][32]byte, depth+1)
+	layers[0] = roots
+	for i := uint8(0); i < depth; i++ {
+		layer := layers[i]
+		if len(layer)%2 == 1 {
+			layer = append(layer[:len(layer):len(layer)], trie.ZeroHashes[i])
+		}
+		if len(layer) == 0 {
+			break
+		}
+		layers[i+1] = htr.VectorizedSha256(layer)
+	}
+	at := func(level uint8, index uint64) ([32]byte, bool) {
+		if index < uint64(len(layers[level])) {
+			return layers[level][index], true
+		}
+		return [32]byte{}, false
+	}
+	c := &chunks{at: at, count: uint64(len(roots)), expand: expand, nodes: make(map[uint64]Node)}
+	return &chunkNode{c: c, level: depth, index: 0}, nil
+}
+
+// Layers returns the root node of a subtree over already hashed layers, the leaves first, such as
+// the layers of a field trie of the state. Nodes missing on the right of a layer are zero. The
+// layers are read as the tree is traversed, so they must not be modified while it is in use.
+func Layers(layers [][]*[32]byte, expand func(i uint64) (Node, error)) (Node, error) {
+	if len(layers) == 0 || len(layers) > len(trie.ZeroHashes) {
+		return nil, errors.Errorf("invalid number of layers %d", len(layers))
+	}
+	depth := uint8(len(layers) - 1)
+	if uint64(len(layers[0])) > uint64(1)<<depth {
+		return nil, errors.Errorf("%d chunks do not fit in a tree of depth %d", len(layers[0]), depth)
+	}
+	at := func(level uint8, index uint64) ([32]byte, bool) {
+		if index < uint64(len(layers[level])) && layers[level][index] != nil {
+			return *layers[level][index], true
+		}
+		return [32]byte{}, false
+	}
+	c := &chunks{at: at, count: uint64(len(layers[0])), expand: expand, nodes: make(map[uint64]Node)}
+	return &chunkNode{c: c, level: depth, index: 0}, nil
+}
+
+func (n *chunkNode) Root() ([32]byte, error) {
+	if r, ok := n.c.at(n.level, n.index); ok {
+		return r, nil
+	}
+	return trie.ZeroHashes[n.level], nil
+}
+
+func (n *chunkNode) Children() (Node, Node, error) {
+	if n.level > 0 {
+		return &chunkNode{c: n.c, level: n.level - 1, index: 2 * n.index},
+			&chunkNode{c: n.c, level: n.level - 1, index: 2*n.index + 1}, nil
+	}
+	if n.c.expand == nil || n.index >= n.c.count {
+		return nil, nil, ErrLeaf
+	}
+	n.c.lock.Lock()
+	node, ok := n.c.nodes[n.index]
+	if !ok {
+		var err error
+		node, err = n.c.expand(n.index)
+		if err != nil {
+			n.c.lock.Unlock()
+			return nil, nil, errors.Wrapf(err, "could not expand chunk %d", n.index)
+		}
+		n.c.nodes[n.index] = node
+	}
+	n.c.lock.Unlock()
+	return node.Children()
+}
+
+// Container returns the root node of an SSZ container with the given field nodes.
+func Container(fields []Node) Node {
+	depth := uint8(0)
+	for uint64(1)<<depth < uint64(len(fields)) {
+		depth++
+	}
+	return subtree(fields, depth, 0)
+}
+
+func subtree(nodes []Node, depth uint8, offset uint64) Node {
+	if offset >= uint64(len(nodes)) {
+		return Leaf(trie.ZeroHashes[depth])
+	}
+	if depth == 0 {
+		return nodes[offset]
+	}
+	half := uint64(1) << (depth - 1)
+	return Branch(subtree(nodes, depth-1, offset), subtree(nodes, depth-1, offset+half))
+}
+
+// List returns the root node of an SSZ list, mixing the length in to the root of its data.
+func List(data Node, length uint64) Node {
+	return Branch(data, Uint64Leaf(length))
+}
+
+// Roots returns a container of leaves with the given roots, such as the field roots of an SSZ container.
+func Roots(roots [][32]byte) Node {
+	fields := make([]Node, len(roots))
+	for i, r := range roots {
+		fields[i] = Leaf(r)
+	}
+	return Container(fields)
+}
+
+// Get returns the node at the generalized index in the tree.
+func Get(root Node, gindex uint64) (Node, error) {
+	if gindex == 0 {
+		return nil, errors.New("generalized index must be positive")
+	}
+	node := root
+	for i := Depth(gindex); i > 0; i-- {
+		left, right, err := node.Children()
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not descend to generalized index %d", gindex)
+		}
+		if gindex&(uint64(1)<<(i-1)) == 0 {
+			node = left
+		} else {
+			node = right
+		}
+	}
+	return node, nil
+}
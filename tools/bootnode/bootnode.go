@@ -5,6 +5,9 @@
  * discovery. The purpose of this service is to provide a starting point for
  * newly connected services to find other peers outside of their network.
  *
+ * With the -crawl flag, the bootnode also walks the DHT and takes periodic
+ * census snapshots of the fork and subnets advertised by the nodes found.
+ *
  * Usage: Run bootnode --help for flag options.
  */
 package main
@@ -34,6 +37,7 @@ import (
 	"github.com/prysmaticlabs/go-bitfield"
 	"github.com/prysmaticlabs/prysm/v5/async"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/signing"
+	"github.com/prysmaticlabs/prysm/v5/cmd/prysmctl/p2p"
 	"github.com/prysmaticlabs/prysm/v5/config/params"
 	ecdsaprysm "github.com/prysmaticlabs/prysm/v5/crypto/ecdsa"
 	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
@@ -55,6 +59,11 @@ var (
 	forkVersion           = flag.String("fork-version", "", "Fork Version that the bootnode uses")
 	genesisValidatorsRoot = flag.String("genesis-root", "", "Genesis Validators Root the beacon node uses")
 	seedNode              = flag.String("seed-node", "", "External node to connect to")
+	crawl                 = flag.Bool("crawl", false, "Crawl the discv5 DHT and take periodic census snapshots of the nodes found, served on /census")
+	censusInterval        = flag.Duration("census-interval", 5*time.Minute, "Interval between census snapshots of the crawled nodes")
+	censusFile            = flag.String("census-file", "", "File to write census snapshots of the crawled nodes to as JSON")
+	dialSample            = flag.Int("dial-sample", 0, "Number of crawled nodes to dial for each census, to run the status and metadata handshakes with")
+	dialPort              = flag.Uint("dial-port", 13000, "TCP and QUIC port of the libp2p host dialing crawled nodes")
 	log                   = logrus.WithField("prefix", "bootnode")
 	discv5PeersCount      = promauto.NewGauge(prometheus.GaugeOpts{
 		Name: "bootstrap_node_discv5_peers",
@@ -114,23 +123,34 @@ func main() {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/p2p", handler.httpHandler)
 
+	if *crawl {
+		var prober *p2p.Prober
+		if *dialSample > 0 {
+			prober, err = p2p.NewProber(*dialPort, *dialPort)
+			if err != nil {
+				log.WithError(err).Fatal("Could not start libp2p host to dial crawled nodes")
+			}
+		}
+		crawler := newCrawler(listener, prober, *dialSample, *censusInterval, *censusFile)
+		crawler.start(context.Background())
+		mux.HandleFunc("/census", crawler.censusHandler)
+	}
+
 	srv := &http.Server{
 		Addr:              fmt.Sprintf(":%d", *metricsPort),
 		ReadHeaderTimeout: 3 * time.Second,
 		Handler:           mux,
 	}
 
-	if err := srv.ListenAndServe(); err != nil {
-		log.WithError(err).Fatal("Failed to start server")
-	}
-
 	// Update metrics once per slot.
 	slotDuration := time.Duration(params.BeaconConfig().SecondsPerSlot)
 	async.RunEvery(context.Background(), slotDuration*time.Second, func() {
 		updateMetrics(listener)
 	})
 
-	select {}
+	if err := srv.ListenAndServe(); err != nil {
+		log.WithError(err).Fatal("Failed to start server")
+	}
 }
 
 func createListener(ipAddr string, port int, cfg discover.Config) *discover.UDPv5 {
@@ -0,0 +1,438 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/v5/async"
+	"github.com/prysmaticlabs/prysm/v5/cmd/prysmctl/p2p"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	ecdsaprysm "github.com/prysmaticlabs/prysm/v5/crypto/ecdsa"
+	"github.com/prysmaticlabs/prysm/v5/crypto/rand"
+	"github.com/prysmaticlabs/prysm/v5/io/file"
+	pb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+)
+
+// crawledNodeTTL is the number of census intervals after which a node which was not found again
+// is dropped from the census.
+const crawledNodeTTL = 3
+
+// dialConcurrency is the number of crawled nodes dialed at the same time.
+const dialConcurrency = 16
+
+var censusNodesCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "bootstrap_node_census_nodes",
+	Help: "The number of crawled nodes in the last census, by fork digest and next scheduled fork",
+}, []string{"fork_digest", "next_fork_version", "next_fork_epoch"})
+
+var censusSubnetPeersCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "bootstrap_node_census_subnet_nodes",
+	Help: "The number of crawled nodes advertising a subnet in their ENR in the last census",
+}, []string{"kind", "subnet"})
+
+// crawledNode holds what an ENR found while crawling the DHT advertises.
+type crawledNode struct {
+	ID              string     `json:"id"`
+	ENR             string     `json:"enr"`
+	Seq             uint64     `json:"seq"`
+	IP              string     `json:"ip"`
+	TCPPort         int        `json:"tcp_port"`
+	UDPPort         int        `json:"udp_port"`
+	ForkDigest      string     `json:"fork_digest"`
+	NextForkVersion string     `json:"next_fork_version"`
+	NextForkEpoch   uint64     `json:"next_fork_epoch"`
+	Attnets         string     `json:"attnets"`
+	Syncnets        string     `json:"syncnets"`
+	LastSeen        time.Time  `json:"last_seen"`
+	Handshake       *handshake `json:"handshake,omitempty"`
+
+	node     *enode.Node
+	attnets  bitfield.Bitvector64
+	syncnets bitfield.Bitvector4
+}
+
+// handshake is the outcome of dialing a crawled node and running the status and metadata handshakes with it.
+type handshake struct {
+	Time           time.Time `json:"time"`
+	Error          string    `json:"error,omitempty"`
+	AgentVersion   string    `json:"agent_version,omitempty"`
+	ForkDigest     string    `json:"fork_digest,omitempty"`
+	HeadSlot       uint64    `json:"head_slot,omitempty"`
+	FinalizedEpoch uint64    `json:"finalized_epoch,omitempty"`
+	MetadataSeq    uint64    `json:"metadata_seq,omitempty"`
+	Attnets        string    `json:"attnets,omitempty"`
+	Syncnets       string    `json:"syncnets,omitempty"`
+}
+
+// census is a snapshot of the crawled nodes, aggregated by the fork they are on and the subnets they
+// advertise.
+type census struct {
+	Time         time.Time      `json:"time"`
+	NodeCount    int            `json:"node_count"`
+	DistinctIPs  int            `json:"distinct_ips"`
+	ForkDigests  map[string]int `json:"fork_digests"`
+	NextForks    map[string]int `json:"next_forks"`
+	AttnetNodes  []int          `json:"attnet_nodes"`
+	SyncnetNodes []int          `json:"syncnet_nodes"`
+	Dialed       int            `json:"dialed"`
+	Handshaked   int            `json:"handshaked"`
+	Nodes        []crawledNode  `json:"nodes"`
+}
+
+// crawler walks the discv5 DHT, records the ENRs it finds, and periodically takes a census of them.
+type crawler struct {
+	listener   *discover.UDPv5
+	prober     *p2p.Prober
+	dialSample int
+	interval   time.Duration
+	ttl        time.Duration
+	censusFile string
+
+	lock   sync.RWMutex
+	nodes  map[enode.ID]*crawledNode
+	latest *census
+}
+
+// newCrawler creates a crawler. Crawled nodes are dialed only if a prober is given.
+func newCrawler(listener *discover.UDPv5, prober *p2p.Prober, dialSample int, interval time.Duration, censusFile string) *crawler {
+	return &crawler{
+		listener:   listener,
+		prober:     prober,
+		dialSample: dialSample,
+		interval:   interval,
+		ttl:        crawledNodeTTL * interval,
+		censusFile: censusFile,
+		nodes:      make(map[enode.ID]*crawledNode),
+	}
+}
+
+// start crawls the DHT and takes a census every census interval until the context is done.
+func (c *crawler) start(ctx context.Context) {
+	go c.crawl(ctx)
+	async.RunEvery(ctx, c.interval, func() {
+		c.takeCensus(ctx)
+	})
+}
+
+// crawl records the nodes found by random lookups in the DHT.
+func (c *crawler) crawl(ctx context.Context) {
+	iterator := c.listener.RandomNodes()
+	go func() {
+		<-ctx.Done()
+		iterator.Close()
+	}()
+	for iterator.Next() {
+		c.record(iterator.Node(), time.Now())
+	}
+}
+
+// record stores a crawled node, unless a newer record of the node is already stored. Nodes which do not
+// advertise an Ethereum consensus fork are ignored.
+func (c *crawler) record(node *enode.Node, seen time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if existing, ok := c.nodes[node.ID()]; ok && existing.Seq >= node.Seq() {
+		existing.LastSeen = seen
+		return
+	}
+	crawled, err := parseNode(node)
+	if err != nil {
+		log.WithError(err).WithField("node", node.ID()).Trace("Ignoring crawled node")
+		return
+	}
+	crawled.LastSeen = seen
+	if existing, ok := c.nodes[node.ID()]; ok {
+		crawled.Handshake = existing.Handshake
+	}
+	c.nodes[node.ID()] = crawled
+}
+
+// takeCensus drops the nodes not found recently, dials a sample of the remaining ones, and aggregates them
+// into a census which is served over HTTP and written to the census file.
+func (c *crawler) takeCensus(ctx context.Context) {
+	now := time.Now()
+	c.lock.Lock()
+	for id, node := range c.nodes {
+		if now.Sub(node.LastSeen) > c.ttl {
+			delete(c.nodes, id)
+		}
+	}
+	c.lock.Unlock()
+
+	c.dialNodes(ctx)
+
+	c.lock.Lock()
+	snapshot := newCensus(now, c.nodes)
+	c.latest = snapshot
+	c.lock.Unlock()
+
+	updateCensusMetrics(snapshot)
+	log.WithField("nodes", snapshot.NodeCount).WithField("forkDigests", snapshot.ForkDigests).Info("Took network census")
+	if c.censusFile != "" {
+		if err := writeCensus(c.censusFile, snapshot); err != nil {
+			log.WithError(err).Error("Could not write census")
+		}
+	}
+}
+
+// dialNodes runs the status and metadata handshakes with a random sample of the crawled nodes. The nodes are
+// dialed concurrently, and the dials are bounded by the census interval for a large sample not to delay the
+// census; the nodes not dialed in time keep their previous handshake.
+func (c *crawler) dialNodes(ctx context.Context) {
+	if c.prober == nil || c.dialSample <= 0 {
+		return
+	}
+	c.lock.RLock()
+	candidates := make([]*crawledNode, 0, len(c.nodes))
+	for _, node := range c.nodes {
+		if node.node.IP() != nil && node.node.TCP() != 0 {
+			candidates = append(candidates, node)
+		}
+	}
+	c.lock.RUnlock()
+
+	randGen := rand.NewGenerator()
+	randGen.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	if len(candidates) > c.dialSample {
+		candidates = candidates[:c.dialSample]
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.interval)
+	defer cancel()
+	limit := make(chan struct{}, dialConcurrency)
+	var wg sync.WaitGroup
+	for _, node := range candidates {
+		select {
+		case limit <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(node *crawledNode) {
+			defer func() {
+				<-limit
+				wg.Done()
+			}()
+			result := c.dial(ctx, node)
+			if ctx.Err() != nil {
+				return
+			}
+			c.lock.Lock()
+			// The node may have been replaced by a newer record of it while being dialed.
+			if current, ok := c.nodes[node.node.ID()]; ok {
+				current.Handshake = result
+			}
+			c.lock.Unlock()
+		}(node)
+	}
+	wg.Wait()
+}
+
+func (c *crawler) dial(ctx context.Context, node *crawledNode) *handshake {
+	result := &handshake{Time: time.Now()}
+	info, err := addrInfo(node.node)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	forkDigest, err := hexutil.Decode(node.ForkDigest)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	// The crawler is not following the chain, it advertises the genesis of the fork the node is on.
+	status := &pb.Status{
+		ForkDigest:     forkDigest,
+		FinalizedRoot:  params.BeaconConfig().ZeroHash[:],
+		FinalizedEpoch: 0,
+		HeadRoot:       params.BeaconConfig().ZeroHash[:],
+		HeadSlot:       0,
+	}
+	res, err := c.prober.Probe(ctx, *info, status)
+	if err != nil {
+		log.WithError(err).WithField("node", node.ID).Debug("Could not run handshakes with crawled node")
+		result.Error = err.Error()
+		return result
+	}
+	result.AgentVersion = res.AgentVersion
+	result.ForkDigest = hexutil.Encode(res.Status.ForkDigest)
+	result.HeadSlot = uint64(res.Status.HeadSlot)
+	result.FinalizedEpoch = uint64(res.Status.FinalizedEpoch)
+	result.MetadataSeq = res.Metadata.SequenceNumber()
+	result.Attnets = hexutil.Encode(res.Metadata.AttnetsBitfield())
+	result.Syncnets = hexutil.Encode(res.Metadata.SyncnetsBitfield())
+	return result
+}
+
+// censusHandler serves the latest census as JSON.
+func (c *crawler) censusHandler(w http.ResponseWriter, _ *http.Request) {
+	c.lock.RLock()
+	latest := c.latest
+	c.lock.RUnlock()
+	if latest == nil {
+		http.Error(w, "No census was taken yet", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(latest); err != nil {
+		log.WithError(err).Error("Failed to write census to http response")
+	}
+}
+
+// parseNode extracts the fork and the subnets a node advertises from its ENR.
+func parseNode(node *enode.Node) (*crawledNode, error) {
+	record := node.Record()
+	netCfg := params.BeaconNetworkConfig()
+	forkEntry := make([]byte, 16)
+	if err := record.Load(enr.WithEntry(netCfg.ETH2Key, &forkEntry)); err != nil {
+		return nil, errors.Wrap(err, "could not load fork entry")
+	}
+	forkID := &pb.ENRForkID{}
+	if err := forkID.UnmarshalSSZ(forkEntry); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal fork entry")
+	}
+	// Nodes from before the introduction of the subnet entries do not advertise them.
+	attnets := bitfield.NewBitvector64()
+	if err := record.Load(enr.WithEntry(netCfg.AttSubnetKey, &attnets)); err != nil && !enr.IsNotFound(err) {
+		return nil, errors.Wrap(err, "could not load attestation subnets entry")
+	}
+	syncnets := bitfield.Bitvector4{byte(0x00)}
+	if err := record.Load(enr.WithEntry(netCfg.SyncCommsSubnetKey, &syncnets)); err != nil && !enr.IsNotFound(err) {
+		return nil, errors.Wrap(err, "could not load sync committee subnets entry")
+	}
+	crawled := &crawledNode{
+		ID:              node.ID().String(),
+		ENR:             node.String(),
+		Seq:             node.Seq(),
+		TCPPort:         node.TCP(),
+		UDPPort:         node.UDP(),
+		ForkDigest:      hexutil.Encode(forkID.CurrentForkDigest),
+		NextForkVersion: hexutil.Encode(forkID.NextForkVersion),
+		NextForkEpoch:   uint64(forkID.NextForkEpoch),
+		Attnets:         hexutil.Encode(attnets),
+		Syncnets:        hexutil.Encode(syncnets),
+		node:            node,
+		attnets:         attnets,
+		syncnets:        syncnets,
+	}
+	if ip := node.IP(); ip != nil {
+		crawled.IP = ip.String()
+	}
+	return crawled, nil
+}
+
+// newCensus aggregates the given nodes. The nodes are copied, as the crawler keeps updating them.
+func newCensus(now time.Time, nodes map[enode.ID]*crawledNode) *census {
+	cfg := params.BeaconConfig()
+	snapshot := &census{
+		Time:         now,
+		NodeCount:    len(nodes),
+		ForkDigests:  make(map[string]int),
+		NextForks:    make(map[string]int),
+		AttnetNodes:  make([]int, cfg.AttestationSubnetCount),
+		SyncnetNodes: make([]int, cfg.SyncCommitteeSubnetCount),
+		Nodes:        make([]crawledNode, 0, len(nodes)),
+	}
+	ips := make(map[string]bool)
+	for _, node := range nodes {
+		snapshot.Nodes = append(snapshot.Nodes, *node)
+		snapshot.ForkDigests[node.ForkDigest]++
+		snapshot.NextForks[nextForkKey(node)]++
+		if node.IP != "" {
+			ips[node.IP] = true
+		}
+		for _, subnet := range node.attnets.BitIndices() {
+			if subnet < len(snapshot.AttnetNodes) {
+				snapshot.AttnetNodes[subnet]++
+			}
+		}
+		for _, subnet := range node.syncnets.BitIndices() {
+			if subnet < len(snapshot.SyncnetNodes) {
+				snapshot.SyncnetNodes[subnet]++
+			}
+		}
+		if node.Handshake != nil {
+			snapshot.Dialed++
+			if node.Handshake.Error == "" {
+				snapshot.Handshaked++
+			}
+		}
+	}
+	snapshot.DistinctIPs = len(ips)
+	sort.Slice(snapshot.Nodes, func(i, j int) bool {
+		return snapshot.Nodes[i].ID < snapshot.Nodes[j].ID
+	})
+	return snapshot
+}
+
+// nextForkKey identifies the next fork a node is scheduled for, by fork version and epoch.
+func nextForkKey(node *crawledNode) string {
+	return fmt.Sprintf("%s@%d", node.NextForkVersion, node.NextForkEpoch)
+}
+
+func updateCensusMetrics(snapshot *census) {
+	censusNodesCount.Reset()
+	for _, node := range snapshot.Nodes {
+		censusNodesCount.WithLabelValues(node.ForkDigest, node.NextForkVersion, fmt.Sprintf("%d", node.NextForkEpoch)).Inc()
+	}
+	for subnet, count := range snapshot.AttnetNodes {
+		censusSubnetPeersCount.WithLabelValues("attnet", fmt.Sprintf("%d", subnet)).Set(float64(count))
+	}
+	for subnet, count := range snapshot.SyncnetNodes {
+		censusSubnetPeersCount.WithLabelValues("syncnet", fmt.Sprintf("%d", subnet)).Set(float64(count))
+	}
+}
+
+// writeCensus writes a census to a file as JSON. The census is written to a temporary file first, for
+// readers of the file to never see a partial census.
+func writeCensus(path string, snapshot *census) error {
+	enc, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not marshal census")
+	}
+	tmpPath := path + ".tmp"
+	if err := file.WriteFile(tmpPath, enc); err != nil {
+		return errors.Wrap(err, "could not write census")
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// addrInfo returns the libp2p peer ID and TCP address of a node.
+func addrInfo(node *enode.Node) (*peer.AddrInfo, error) {
+	pubkey, err := ecdsaprysm.ConvertToInterfacePubkey(node.Pubkey())
+	if err != nil {
+		return nil, errors.Wrap(err, "could not convert node public key")
+	}
+	pid, err := peer.IDFromPublicKey(pubkey)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not derive peer ID")
+	}
+	protocol := "ip4"
+	if node.IP().To4() == nil {
+		protocol = "ip6"
+	}
+	addr, err := ma.NewMultiaddr(fmt.Sprintf("/%s/%s/tcp/%d", protocol, node.IP(), node.TCP()))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not build node multiaddr")
+	}
+	return &peer.AddrInfo{ID: pid, Addrs: []ma.Multiaddr{addr}}, nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/v5/cmd/prysmctl/p2p"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	pb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func testNode(t *testing.T, ip string, digest []byte, nextForkEpoch uint64, attnets bitfield.Bitvector64, syncnets bitfield.Bitvector4) *enode.Node {
+	db, err := enode.OpenDB("")
+	require.NoError(t, err)
+	t.Cleanup(db.Close)
+	localNode := enode.NewLocalNode(db, extractPrivateKey())
+	if digest != nil {
+		forkID := &pb.ENRForkID{
+			CurrentForkDigest: digest,
+			NextForkVersion:   []byte{0x05, 0x00, 0x00, 0x00},
+			NextForkEpoch:     primitives.Epoch(nextForkEpoch),
+		}
+		forkEntry, err := forkID.MarshalSSZ()
+		require.NoError(t, err)
+		localNode.Set(enr.WithEntry(params.BeaconNetworkConfig().ETH2Key, forkEntry))
+	}
+	if attnets != nil {
+		localNode.Set(enr.WithEntry(params.BeaconNetworkConfig().AttSubnetKey, attnets))
+	}
+	if syncnets != nil {
+		localNode.Set(enr.WithEntry(params.BeaconNetworkConfig().SyncCommsSubnetKey, syncnets))
+	}
+	localNode.SetStaticIP(net.ParseIP(ip))
+	localNode.Set(enr.TCP(13000))
+	return localNode.Node()
+}
+
+func TestCrawler_Census(t *testing.T) {
+	censusPath := filepath.Join(t.TempDir(), "census.json")
+	c := newCrawler(nil, nil, 0, time.Minute, censusPath)
+
+	attnets := bitfield.NewBitvector64()
+	attnets.SetBitAt(3, true)
+	attnets.SetBitAt(60, true)
+	syncnets := bitfield.Bitvector4{0x01}
+	now := time.Now()
+	c.record(testNode(t, "8.8.8.1", []byte{0x01, 0x02, 0x03, 0x04}, 100, attnets, syncnets), now)
+	c.record(testNode(t, "8.8.8.1", []byte{0x01, 0x02, 0x03, 0x04}, 100, attnets, nil), now)
+	c.record(testNode(t, "8.8.8.2", []byte{0x0a, 0x0b, 0x0c, 0x0d}, uint64(params.BeaconConfig().FarFutureEpoch), nil, nil), now)
+	// Nodes without a fork entry are not consensus nodes.
+	c.record(testNode(t, "8.8.8.3", nil, 0, nil, nil), now)
+	// Nodes not found recently are dropped.
+	c.record(testNode(t, "8.8.8.4", []byte{0x01, 0x02, 0x03, 0x04}, 100, nil, nil), now.Add(-time.Hour))
+
+	rr := httptest.NewRecorder()
+	c.censusHandler(rr, httptest.NewRequest(http.MethodGet, "/census", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+
+	c.takeCensus(context.Background())
+	rr = httptest.NewRecorder()
+	c.censusHandler(rr, httptest.NewRequest(http.MethodGet, "/census", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	served := &census{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), served))
+
+	enc, err := os.ReadFile(censusPath)
+	require.NoError(t, err)
+	written := &census{}
+	require.NoError(t, json.Unmarshal(enc, written))
+
+	for _, snapshot := range []*census{served, written} {
+		assert.Equal(t, 3, snapshot.NodeCount)
+		assert.Equal(t, 2, snapshot.DistinctIPs)
+		assert.DeepEqual(t, map[string]int{"0x01020304": 2, "0x0a0b0c0d": 1}, snapshot.ForkDigests)
+		assert.Equal(t, 2, snapshot.NextForks["0x05000000@100"])
+		assert.Equal(t, 2, snapshot.AttnetNodes[3])
+		assert.Equal(t, 2, snapshot.AttnetNodes[60])
+		assert.Equal(t, 0, snapshot.AttnetNodes[4])
+		assert.DeepEqual(t, []int{1, 0, 0, 0}, snapshot.SyncnetNodes)
+		require.Equal(t, 3, len(snapshot.Nodes))
+	}
+}
+
+func TestCrawler_DialNodesWithinInterval(t *testing.T) {
+	// A silent peer, which accepts connections and never responds, so that every dial lasts the probe timeout.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, listener.Close())
+	}()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() {
+				_ = conn.Close()
+			})
+		}
+	}()
+	prober, err := p2p.NewProber(0, 0)
+	require.NoError(t, err)
+	defer prober.Close()
+	interval := 500 * time.Millisecond
+	c := newCrawler(nil, prober, 4*dialConcurrency, interval, "")
+	db, err := enode.OpenDB("")
+	require.NoError(t, err)
+	defer db.Close()
+	for i := 0; i < 4*dialConcurrency; i++ {
+		localNode := enode.NewLocalNode(db, extractPrivateKey())
+		forkEntry, err := (&pb.ENRForkID{CurrentForkDigest: []byte{0x01, 0x02, 0x03, 0x04}, NextForkVersion: []byte{0x05, 0x00, 0x00, 0x00}}).MarshalSSZ()
+		require.NoError(t, err)
+		localNode.Set(enr.WithEntry(params.BeaconNetworkConfig().ETH2Key, forkEntry))
+		localNode.SetStaticIP(net.IPv4(127, 0, 0, 1))
+		localNode.Set(enr.TCP(listener.Addr().(*net.TCPAddr).Port))
+		c.record(localNode.Node(), time.Now())
+	}
+
+	start := time.Now()
+	c.dialNodes(context.Background())
+	assert.Equal(t, true, time.Since(start) < interval+5*time.Second, "dials were not bounded by the census interval")
+	// The nodes whose dials were cut short by the interval keep their previous handshake.
+	for _, node := range c.nodes {
+		assert.Equal(t, (*handshake)(nil), node.Handshake)
+	}
+}
@@ -18,6 +18,10 @@ func (c *beaconApiValidatorClient) prepareBeaconProposer(ctx context.Context, re
 		jsonRecipients[index] = &structs.FeeRecipient{
 			FeeRecipient:   hexutil.Encode(recipient.FeeRecipient),
 			ValidatorIndex: strconv.FormatUint(uint64(recipient.ValidatorIndex), 10),
+			Relays:         recipient.Relays,
+		}
+		if recipient.MinBid != 0 {
+			jsonRecipients[index].MinBid = strconv.FormatUint(uint64(recipient.MinBid), 10)
 		}
 	}
 
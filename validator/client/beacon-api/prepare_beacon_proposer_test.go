@@ -40,6 +40,8 @@ func TestPrepareBeaconProposer_Valid(t *testing.T) {
 		{
 			ValidatorIndex: "3",
 			FeeRecipient:   feeRecipient3,
+			Relays:         []string{"https://relay.example.com"},
+			MinBid:         "100",
 		},
 	}
 
@@ -76,6 +78,8 @@ func TestPrepareBeaconProposer_Valid(t *testing.T) {
 		{
 			ValidatorIndex: 3,
 			FeeRecipient:   decodedFeeRecipient3,
+			Relays:         []string{"https://relay.example.com"},
+			MinBid:         100,
 		},
 	}
 
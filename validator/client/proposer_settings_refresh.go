@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/config/proposer"
+)
+
+// ProposerSettingsRefresher returns updated proposer settings given the current ones, or nil
+// settings if they have not changed.
+type ProposerSettingsRefresher interface {
+	Refresh(ctx context.Context, current *proposer.Settings) (*proposer.Settings, error)
+}
+
+// refreshProposerSettings refreshes the proposer settings at every interval until the context is
+// canceled. Failed refreshes keep the current settings.
+func (v *ValidatorService) refreshProposerSettings(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := v.refreshProposerSettingsOnce(ctx); err != nil {
+				log.WithError(err).Warn("Could not refresh proposer settings, keeping the current ones")
+			}
+		}
+	}
+}
+
+// refreshProposerSettingsOnce replaces the proposer settings with the refreshed ones, if they
+// changed, and pushes them to the beacon node without waiting for the next slot.
+func (v *ValidatorService) refreshProposerSettingsOnce(ctx context.Context) error {
+	settings, err := v.settingsRefresher.Refresh(ctx, v.ProposerSettings())
+	if err != nil {
+		return err
+	}
+	if settings == nil {
+		return nil
+	}
+	if err := v.SetProposerSettings(ctx, settings); err != nil {
+		return errors.Wrap(err, "could not set proposer settings")
+	}
+
+	km, err := v.validator.Keymanager()
+	if err != nil {
+		log.WithError(err).Debug("Could not get keymanager, refreshed proposer settings will be pushed on the next slot")
+		return nil
+	}
+	slot, err := v.validator.CanonicalHeadSlot(ctx)
+	if err != nil {
+		log.WithError(err).Warn("Could not get canonical head slot, refreshed proposer settings will be pushed on the next slot")
+		return nil
+	}
+	if err := v.validator.PushProposerSettings(ctx, km, slot, false); err != nil {
+		log.WithError(err).Warn("Failed to update proposer settings")
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/async/event"
+	"github.com/prysmaticlabs/prysm/v5/config/proposer"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/validator/client/testutil"
+	logTest "github.com/sirupsen/logrus/hooks/test"
+)
+
+type mockSettingsRefresher struct {
+	settings *proposer.Settings
+	err      error
+	current  *proposer.Settings
+}
+
+func (m *mockSettingsRefresher) Refresh(_ context.Context, current *proposer.Settings) (*proposer.Settings, error) {
+	m.current = current
+	return m.settings, m.err
+}
+
+func TestValidatorService_RefreshProposerSettingsOnce(t *testing.T) {
+	hook := logTest.NewGlobal()
+	ctx := context.Background()
+	current := &proposer.Settings{DefaultConfig: &proposer.Option{
+		FeeRecipientConfig: &proposer.FeeRecipientConfig{FeeRecipient: common.HexToAddress("0x01")},
+	}}
+	v := &testutil.FakeValidator{Km: &mockKeymanager{accountsChangedFeed: &event.Feed{}}}
+	require.NoError(t, v.SetProposerSettings(ctx, current))
+	refresher := &mockSettingsRefresher{}
+	s := &ValidatorService{validator: v, settingsRefresher: refresher}
+
+	// Unchanged settings are neither set nor pushed.
+	require.NoError(t, s.refreshProposerSettingsOnce(ctx))
+	assert.DeepEqual(t, current, refresher.current)
+	assert.Equal(t, current, v.ProposerSettings())
+	assert.LogsDoNotContain(t, hook, "Mock updated proposer settings")
+
+	refresher.err = errors.New("bad signature")
+	require.ErrorContains(t, "bad signature", s.refreshProposerSettingsOnce(ctx))
+	assert.Equal(t, current, v.ProposerSettings())
+
+	refreshed := &proposer.Settings{DefaultConfig: &proposer.Option{
+		FeeRecipientConfig: &proposer.FeeRecipientConfig{FeeRecipient: common.HexToAddress("0x02")},
+	}}
+	refresher.err, refresher.settings = nil, refreshed
+	require.NoError(t, s.refreshProposerSettingsOnce(ctx))
+	assert.Equal(t, refreshed, v.ProposerSettings())
+	assert.LogsContain(t, hook, "Mock updated proposer settings")
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/validator/graffiti"
 	validatorHelpers "github.com/prysmaticlabs/prysm/v5/validator/helpers"
 	"github.com/prysmaticlabs/prysm/v5/validator/keymanager"
+	"github.com/prysmaticlabs/prysm/v5/validator/keymanager/lease"
 	"github.com/prysmaticlabs/prysm/v5/validator/keymanager/local"
 	remoteweb3signer "github.com/prysmaticlabs/prysm/v5/validator/keymanager/remote-web3signer"
 	"go.opencensus.io/plugin/ocgrpc"
@@ -50,8 +51,12 @@ type ValidatorService struct {
 	graffiti                []byte
 	graffitiStruct          *graffiti.Graffiti
 	interopKeysConfig       *local.InteropKeymanagerConfig
+	leaseStore              lease.Store
+	leaseGuard              *lease.Guard
 	web3SignerConfig        *remoteweb3signer.SetupConfig
 	proposerSettings        *proposer.Settings
+	settingsRefresher       ProposerSettingsRefresher
+	settingsRefreshInterval time.Duration
 	validatorsRegBatchSize  int
 	useWeb                  bool
 	emitAccountMetrics      bool
@@ -76,8 +81,12 @@ type Config struct {
 	Graffiti                string
 	GraffitiStruct          *graffiti.Graffiti
 	InteropKmConfig         *local.InteropKeymanagerConfig
+	LeaseStore              lease.Store
+	LeaseGuard              *lease.Guard
 	Web3SignerConfig        *remoteweb3signer.SetupConfig
 	ProposerSettings        *proposer.Settings
+	SettingsRefresher       ProposerSettingsRefresher
+	SettingsRefreshInterval time.Duration
 	ValidatorsRegBatchSize  int
 	UseWeb                  bool
 	LogValidatorPerformance bool
@@ -99,8 +108,12 @@ func NewValidatorService(ctx context.Context, cfg *Config) (*ValidatorService, e
 		graffiti:                []byte(cfg.Graffiti),
 		graffitiStruct:          cfg.GraffitiStruct,
 		interopKeysConfig:       cfg.InteropKmConfig,
+		leaseStore:              cfg.LeaseStore,
+		leaseGuard:              cfg.LeaseGuard,
 		web3SignerConfig:        cfg.Web3SignerConfig,
 		proposerSettings:        cfg.ProposerSettings,
+		settingsRefresher:       cfg.SettingsRefresher,
+		settingsRefreshInterval: cfg.SettingsRefreshInterval,
 		validatorsRegBatchSize:  cfg.ValidatorsRegBatchSize,
 		useWeb:                  cfg.UseWeb,
 		emitAccountMetrics:      cfg.EmitAccountMetrics,
@@ -199,6 +212,7 @@ func (v *ValidatorService) Start() {
 		prysmChainClient:               beaconChainClientFactory.NewPrysmChainClient(v.conn, restHandler),
 		db:                             v.db,
 		km:                             nil,
+		leaseGuard:                     v.leaseGuard,
 		web3SignerConfig:               v.web3SignerConfig,
 		proposerSettings:               v.proposerSettings,
 		signedValidatorRegistrations:   make(map[[fieldparams.BLSPubkeyLength]byte]*ethpb.SignedValidatorRegistrationV1),
@@ -219,6 +233,9 @@ func (v *ValidatorService) Start() {
 
 	v.validator = valStruct
 	go run(v.ctx, v.validator)
+	if v.settingsRefresher != nil && v.settingsRefreshInterval > 0 {
+		go v.refreshProposerSettings(v.ctx, v.settingsRefreshInterval)
+	}
 }
 
 // Stop the validator service.
@@ -244,9 +261,19 @@ func (v *ValidatorService) InteropKeysConfig() *local.InteropKeymanagerConfig {
 	return v.interopKeysConfig
 }
 
-// Keymanager returns the underlying keymanager in the validator
+// Keymanager returns the underlying keymanager in the validator, without the key lease checks
+// so that callers may type assert on its implementation.
 func (v *ValidatorService) Keymanager() (keymanager.IKeymanager, error) {
-	return v.validator.Keymanager()
+	km, err := v.validator.Keymanager()
+	if err != nil {
+		return nil, err
+	}
+	return lease.Unwrap(km), nil
+}
+
+// LeaseStore returns the key lease store shared with other validator clients, if any.
+func (v *ValidatorService) LeaseStore() lease.Store {
+	return v.leaseStore
 }
 
 // RemoteSignerConfig returns the web3signer configuration
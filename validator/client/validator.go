@@ -44,6 +44,7 @@ import (
 	dbCommon "github.com/prysmaticlabs/prysm/v5/validator/db/common"
 	"github.com/prysmaticlabs/prysm/v5/validator/graffiti"
 	"github.com/prysmaticlabs/prysm/v5/validator/keymanager"
+	"github.com/prysmaticlabs/prysm/v5/validator/keymanager/lease"
 	"github.com/prysmaticlabs/prysm/v5/validator/keymanager/local"
 	remoteweb3signer "github.com/prysmaticlabs/prysm/v5/validator/keymanager/remote-web3signer"
 	"github.com/sirupsen/logrus"
@@ -88,6 +89,9 @@ type validator struct {
 	prysmChainClient                   iface.PrysmChainClient
 	db                                 db.Database
 	km                                 keymanager.IKeymanager
+	leaseGuard                         *lease.Guard
+	leaseKeys                          leasedKeys
+	leaseGuardOnce                     sync.Once
 	web3SignerConfig                   *remoteweb3signer.SetupConfig
 	proposerSettings                   *proposer.Settings
 	signedValidatorRegistrations       map[[fieldparams.BLSPubkeyLength]byte]*ethpb.SignedValidatorRegistrationV1
@@ -168,10 +172,61 @@ func (v *validator) WaitForKeymanagerInitialization(ctx context.Context) error {
 			v.km = keyManager
 		}
 	}
+	// The buckets of the validating keys are rechecked with the underlying keymanager, as the
+	// lease keymanager does not expose its account changes.
 	recheckKeys(ctx, v.db, v.km)
+	if v.leaseGuard != nil {
+		if err := v.initializeKeyLeases(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// initializeKeyLeases acquires the leases of the validating keys before any duty is performed,
+// wraps the keymanager so it refuses to sign with keys whose lease is not held, and keeps
+// renewing the leases in the background until the context is canceled. The keymanager is
+// initialized again when the validator client retries its initialization, so the guard is
+// started only once and renews the leases of the keys of the current keymanager.
+func (v *validator) initializeKeyLeases(ctx context.Context) error {
+	pubkeys, err := v.km.FetchValidatingPublicKeys(ctx)
+	if err != nil {
+		return errors.Wrap(err, msgCouldNotFetchKeys)
+	}
+	if err := v.leaseGuard.Renew(ctx, pubkeys); err != nil {
+		return errors.Wrap(err, "could not acquire key leases")
+	}
+	if _, ok := v.km.(*lease.Keymanager); !ok {
+		v.km = lease.NewKeymanager(v.km, v.leaseGuard)
+	}
+	v.leaseKeys.set(v.km)
+	v.leaseGuardOnce.Do(func() {
+		go v.leaseGuard.Run(ctx, &v.leaseKeys)
+	})
 	return nil
 }
 
+// leasedKeys fetches the validating keys of the current keymanager of the validator client for
+// its lease guard.
+type leasedKeys struct {
+	sync.RWMutex
+	km keymanager.IKeymanager
+}
+
+func (k *leasedKeys) set(km keymanager.IKeymanager) {
+	k.Lock()
+	defer k.Unlock()
+	k.km = km
+}
+
+// FetchValidatingPublicKeys returns the validating keys of the current keymanager.
+func (k *leasedKeys) FetchValidatingPublicKeys(ctx context.Context) ([][fieldparams.BLSPubkeyLength]byte, error) {
+	k.RLock()
+	km := k.km
+	k.RUnlock()
+	return km.FetchValidatingPublicKeys(ctx)
+}
+
 // subscribe to channel for when the wallet is initialized
 func waitForWebWalletInitialization(
 	ctx context.Context,
@@ -1271,10 +1326,14 @@ func (v *validator) buildPrepProposerReqs(activePubkeys [][fieldparams.BLSPubkey
 
 		// Default case: Define fee recipient to burn address
 		feeRecipient := common.HexToAddress(params.BeaconConfig().EthBurnAddressHex)
+		var builderConfig *proposer.BuilderConfig
 
 		// If fee recipient is defined in default configuration, use it
-		if v.ProposerSettings() != nil && v.ProposerSettings().DefaultConfig != nil && v.ProposerSettings().DefaultConfig.FeeRecipientConfig != nil {
-			feeRecipient = v.ProposerSettings().DefaultConfig.FeeRecipientConfig.FeeRecipient // Use cli config for fee recipient.
+		if v.ProposerSettings() != nil && v.ProposerSettings().DefaultConfig != nil {
+			if v.ProposerSettings().DefaultConfig.FeeRecipientConfig != nil {
+				feeRecipient = v.ProposerSettings().DefaultConfig.FeeRecipientConfig.FeeRecipient // Use cli config for fee recipient.
+			}
+			builderConfig = v.ProposerSettings().DefaultConfig.BuilderConfig
 		}
 
 		// If fee recipient is defined for this specific pubkey in proposer configuration, use it
@@ -1284,12 +1343,21 @@ func (v *validator) buildPrepProposerReqs(activePubkeys [][fieldparams.BLSPubkey
 			if ok && config != nil && config.FeeRecipientConfig != nil {
 				feeRecipient = config.FeeRecipientConfig.FeeRecipient // Use file config for fee recipient.
 			}
+			if ok && config != nil && config.BuilderConfig != nil {
+				builderConfig = config.BuilderConfig // Use file config for relays and min bid.
+			}
 		}
 
-		prepareProposerReqs = append(prepareProposerReqs, &ethpb.PrepareBeaconProposerRequest_FeeRecipientContainer{
+		req := &ethpb.PrepareBeaconProposerRequest_FeeRecipientContainer{
 			ValidatorIndex: s.index,
 			FeeRecipient:   feeRecipient[:],
-		})
+		}
+		// The relays and min bid tell the beacon node which bids to solicit and accept for the proposer.
+		if builderConfig != nil && builderConfig.Enabled {
+			req.Relays = builderConfig.Relays
+			req.MinBid = primitives.Gwei(builderConfig.MinBid)
+		}
+		prepareProposerReqs = append(prepareProposerReqs, req)
 	}
 	return prepareProposerReqs, nil
 }
@@ -40,6 +40,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/validator/client/iface"
 	dbTest "github.com/prysmaticlabs/prysm/v5/validator/db/testing"
 	"github.com/prysmaticlabs/prysm/v5/validator/keymanager"
+	"github.com/prysmaticlabs/prysm/v5/validator/keymanager/lease"
 	"github.com/prysmaticlabs/prysm/v5/validator/keymanager/local"
 	remoteweb3signer "github.com/prysmaticlabs/prysm/v5/validator/keymanager/remote-web3signer"
 	"github.com/sirupsen/logrus"
@@ -1389,6 +1390,53 @@ func TestValidator_WaitForKeymanagerInitialization_Interop(t *testing.T) {
 	}
 }
 
+func TestValidator_WaitForKeymanagerInitialization_KeyLeases(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	db := dbTest.SetupDB(t, [][fieldparams.BLSPubkeyLength]byte{}, false)
+	root := make([]byte, 32)
+	copy(root[2:], "a")
+	require.NoError(t, db.SaveGenesisValidatorsRoot(ctx, root))
+
+	store, err := lease.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	guard, err := lease.NewGuard(store, "alice", time.Minute)
+	require.NoError(t, err)
+	v := validator{
+		db:         db,
+		leaseGuard: guard,
+		interopKeysConfig: &local.InteropKeymanagerConfig{
+			NumValidatorKeys: 2,
+		},
+	}
+	interopKm, err := local.NewInteropKeymanager(ctx, 0, 2)
+	require.NoError(t, err)
+	pubkeys, err := interopKm.FetchValidatingPublicKeys(ctx)
+	require.NoError(t, err)
+	_, err = store.Acquire(ctx, pubkeys[1], "bob", time.Minute)
+	require.NoError(t, err)
+
+	require.NoError(t, v.WaitForKeymanagerInitialization(ctx))
+	km, err := v.Keymanager()
+	require.NoError(t, err)
+	_, err = km.Sign(ctx, &validatorpb.SignRequest{PublicKey: pubkeys[0][:], SigningRoot: make([]byte, 32)})
+	require.NoError(t, err)
+	_, err = km.Sign(ctx, &validatorpb.SignRequest{PublicKey: pubkeys[1][:], SigningRoot: make([]byte, 32)})
+	require.ErrorIs(t, err, lease.ErrNotLeaseHolder)
+
+	// Initializing the keymanager again, as the runner does on retries, wraps the new keymanager once.
+	require.NoError(t, v.WaitForKeymanagerInitialization(ctx))
+	km, err = v.Keymanager()
+	require.NoError(t, err)
+	leaseKm, ok := km.(*lease.Keymanager)
+	require.Equal(t, true, ok)
+	_, ok = leaseKm.Unwrap().(*lease.Keymanager)
+	assert.Equal(t, false, ok)
+	keys, err := v.leaseKeys.FetchValidatingPublicKeys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(keys))
+}
+
 type PrepareBeaconProposerRequestMatcher struct {
 	expectedRecipients []*ethpb.PrepareBeaconProposerRequest_FeeRecipientContainer
 }
@@ -2288,8 +2336,8 @@ func TestValidator_filterAndCacheActiveKeys(t *testing.T) {
 }
 
 func TestValidator_buildPrepProposerReqs_WithDefaultConfig(t *testing.T) {
-	// pubkey1 => feeRecipient1 - Status: active
-	// pubkey2 => feeRecipient2 - Status: active
+	// pubkey1 => feeRecipient1 - Status: active - Relays and min bid
+	// pubkey2 => feeRecipient2 - Status: active - Builder disabled
 	// pubkey3 => feeRecipient3 - Status: unknown
 	// pubkey4 => Nothing       - Status: active
 	// pubkey5 => Nothing       - Status: exited
@@ -2378,17 +2426,30 @@ func TestValidator_buildPrepProposerReqs_WithDefaultConfig(t *testing.T) {
 				FeeRecipientConfig: &proposer.FeeRecipientConfig{
 					FeeRecipient: defaultFeeRecipient,
 				},
+				BuilderConfig: &proposer.BuilderConfig{
+					Enabled: true,
+					Relays:  []string{"https://default.example.com"},
+				},
 			},
 			ProposeConfig: map[[fieldparams.BLSPubkeyLength]byte]*proposer.Option{
 				pubkey1: {
 					FeeRecipientConfig: &proposer.FeeRecipientConfig{
 						FeeRecipient: feeRecipient1,
 					},
+					BuilderConfig: &proposer.BuilderConfig{
+						Enabled: true,
+						Relays:  []string{"https://relay.example.com"},
+						MinBid:  100,
+					},
 				},
 				pubkey2: {
 					FeeRecipientConfig: &proposer.FeeRecipientConfig{
 						FeeRecipient: feeRecipient2,
 					},
+					BuilderConfig: &proposer.BuilderConfig{
+						Enabled: false,
+						Relays:  []string{"https://relay.example.com"},
+					},
 				},
 				pubkey3: {
 					FeeRecipientConfig: &proposer.FeeRecipientConfig{
@@ -2461,6 +2522,8 @@ func TestValidator_buildPrepProposerReqs_WithDefaultConfig(t *testing.T) {
 		{
 			ValidatorIndex: 1,
 			FeeRecipient:   feeRecipient1[:],
+			Relays:         []string{"https://relay.example.com"},
+			MinBid:         100,
 		},
 		{
 			ValidatorIndex: 2,
@@ -2469,14 +2532,17 @@ func TestValidator_buildPrepProposerReqs_WithDefaultConfig(t *testing.T) {
 		{
 			ValidatorIndex: 4,
 			FeeRecipient:   defaultFeeRecipient[:],
+			Relays:         []string{"https://default.example.com"},
 		},
 		{
 			ValidatorIndex: 7,
 			FeeRecipient:   defaultFeeRecipient[:],
+			Relays:         []string{"https://default.example.com"},
 		},
 		{
 			ValidatorIndex: 8,
 			FeeRecipient:   feeRecipient8[:],
+			Relays:         []string{"https://default.example.com"},
 		},
 	}
 	filteredKeys, err := v.filterAndCacheActiveKeys(ctx, pubkeys, 640)
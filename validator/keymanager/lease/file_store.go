@@ -0,0 +1,303 @@
+package lease
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/io/file"
+	prysmTime "github.com/prysmaticlabs/prysm/v5/time"
+)
+
+const (
+	leaseFileExt = ".json"
+	lockFileName = ".lock"
+	// Suffix of the lock file guarding the removal of a stale lock file.
+	breakerFileSuffix = ".break"
+	lockPollInterval  = 10 * time.Millisecond
+	// A lock file older than this is considered to be left behind by a crashed process.
+	staleLockAge = 30 * time.Second
+)
+
+// FileStore is a lease store backed by a directory, typically on a filesystem shared by
+// the validator clients. Each lease is kept in its own file named after the public key,
+// and every mutation is serialized through a lock file in the same directory.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates a lease store in the given directory, creating it if necessary.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := file.MkdirAll(dir); err != nil {
+		return nil, errors.Wrapf(err, "could not create lease directory %s", dir)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Acquire grants or renews the lease of a key for the owner.
+func (s *FileStore) Acquire(ctx context.Context, pubkey [fieldparams.BLSPubkeyLength]byte, owner string, ttl time.Duration) (*Lease, error) {
+	if owner == "" {
+		return nil, ErrEmptyOwner
+	}
+	var l *Lease
+	err := s.withLock(ctx, func() error {
+		current, err := s.read(pubkey)
+		if err != nil {
+			return err
+		}
+		now := prysmTime.Now()
+		if current != nil && current.Owner != owner && !current.Expired(now) {
+			return errors.Wrapf(ErrLeaseHeld, "key %#x is leased to %s until %s", pubkey, current.Owner, current.Expiry)
+		}
+		l = &Lease{PublicKey: pubkey[:], Owner: owner, Expiry: now.Add(ttl)}
+		if current != nil && current.Owner == owner && now.Before(current.NotBefore) {
+			// Renewing a handed over lease must not make it usable any sooner.
+			l.NotBefore = current.NotBefore
+			l.Expiry = current.NotBefore.Add(ttl)
+		}
+		return s.write(l)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Release gives up the lease of a key held by the owner. Releasing a key without a lease is a no-op.
+func (s *FileStore) Release(ctx context.Context, pubkey [fieldparams.BLSPubkeyLength]byte, owner string) error {
+	return s.withLock(ctx, func() error {
+		current, err := s.read(pubkey)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return nil
+		}
+		if current.Owner != owner {
+			return errors.Wrapf(ErrNotLeaseHolder, "key %#x is leased to %s", pubkey, current.Owner)
+		}
+		return os.Remove(s.leasePath(pubkey))
+	})
+}
+
+// Transfer moves the leases of all keys from one owner to another. All leases are checked
+// before any of them is rewritten, so a transfer either succeeds for every key or changes nothing.
+// Leases of the previous owner which have expired can still be transferred as long as no other
+// owner acquired them since. The new leases only become usable once the leases of the previous
+// owner would have expired, as the previous owner may still be relying on them until then.
+func (s *FileStore) Transfer(
+	ctx context.Context,
+	pubkeys [][fieldparams.BLSPubkeyLength]byte,
+	from, to string,
+	ttl time.Duration,
+) ([]*Lease, error) {
+	if from == "" || to == "" {
+		return nil, ErrEmptyOwner
+	}
+	leases := make([]*Lease, 0, len(pubkeys))
+	err := s.withLock(ctx, func() error {
+		now := prysmTime.Now()
+		current := make([]*Lease, len(pubkeys))
+		for i, pk := range pubkeys {
+			l, err := s.read(pk)
+			if err != nil {
+				return err
+			}
+			if l == nil || l.Owner != from {
+				return errors.Wrapf(ErrNotLeaseHolder, "%s does not hold the lease of key %#x", from, pk)
+			}
+			current[i] = l
+		}
+		for i, pk := range pubkeys {
+			notBefore := current[i].Expiry
+			if notBefore.Before(now) {
+				notBefore = now
+			}
+			l := &Lease{PublicKey: pk[:], Owner: to, NotBefore: notBefore, Expiry: notBefore.Add(ttl)}
+			if err := s.write(l); err != nil {
+				return err
+			}
+			leases = append(leases, l)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return leases, nil
+}
+
+// Leases lists all leases in the store, including expired ones.
+func (s *FileStore) Leases(_ context.Context) ([]*Lease, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read lease directory %s", s.dir)
+	}
+	leases := make([]*Lease, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), leaseFileExt) {
+			continue
+		}
+		l, err := readLeaseFile(filepath.Join(s.dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		leases = append(leases, l)
+	}
+	return leases, nil
+}
+
+func (s *FileStore) leasePath(pubkey [fieldparams.BLSPubkeyLength]byte) string {
+	return filepath.Join(s.dir, hexutil.Encode(pubkey[:])+leaseFileExt)
+}
+
+func (s *FileStore) read(pubkey [fieldparams.BLSPubkeyLength]byte) (*Lease, error) {
+	l, err := readLeaseFile(s.leasePath(pubkey))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return l, err
+}
+
+// write stores the lease in a temporary file which is then renamed over the lease file,
+// so that readers never observe a partially written lease.
+func (s *FileStore) write(l *Lease) error {
+	enc, err := json.Marshal(l)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal lease")
+	}
+	path := s.leasePath([fieldparams.BLSPubkeyLength]byte(l.PublicKey))
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, params.BeaconIoConfig().ReadWritePermissions)
+	if err != nil {
+		return errors.Wrap(err, "could not create lease file")
+	}
+	if _, err := f.Write(enc); err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, "could not write lease file")
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, "could not sync lease file")
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, "could not close lease file")
+	}
+	return os.Rename(tmp, path)
+}
+
+// withLock runs fn while holding the exclusive lock of the lease directory. The lock is a file
+// created with O_EXCL, which works on local and most network filesystems alike.
+func (s *FileStore) withLock(ctx context.Context, fn func() error) error {
+	lockPath := filepath.Join(s.dir, lockFileName)
+	for {
+		created, err := createLockFile(lockPath)
+		if err != nil {
+			return err
+		}
+		if created {
+			break
+		}
+		if info, statErr := os.Stat(lockPath); statErr == nil && prysmTime.Since(info.ModTime()) > staleLockAge {
+			if err := s.breakStaleLock(lockPath, info); err != nil {
+				return err
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "could not acquire lease lock")
+		case <-time.After(lockPollInterval):
+		}
+	}
+	defer func() {
+		if err := os.Remove(lockPath); err != nil {
+			log.WithError(err).Error("Could not remove lease lock file")
+		}
+	}()
+	return fn()
+}
+
+// breakStaleLock removes the lock file left behind by a crashed process. Removing it is guarded by
+// a second lock file, under which the lock is checked to still be the stale file observed by the
+// caller. Otherwise two processes observing the same stale lock could both remove it, the second
+// one removing the fresh lock of the first.
+func (s *FileStore) breakStaleLock(lockPath string, stale os.FileInfo) error {
+	breakerPath := lockPath + breakerFileSuffix
+	created, err := createLockFile(breakerPath)
+	if err != nil {
+		return err
+	}
+	if !created {
+		// Another process is breaking the lock, or crashed while doing so.
+		if info, statErr := os.Stat(breakerPath); statErr == nil && prysmTime.Since(info.ModTime()) > staleLockAge {
+			if err := os.Remove(breakerPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return errors.Wrap(err, "could not remove stale lease lock breaker file")
+			}
+		}
+		time.Sleep(lockPollInterval)
+		return nil
+	}
+	defer func() {
+		if err := os.Remove(breakerPath); err != nil {
+			log.WithError(err).Error("Could not remove lease lock breaker file")
+		}
+	}()
+	info, err := os.Stat(lockPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not stat lease lock file")
+	}
+	if !os.SameFile(info, stale) || !info.ModTime().Equal(stale.ModTime()) {
+		// The stale lock was already replaced by a live one.
+		return nil
+	}
+	log.WithField("path", lockPath).Warn("Removing stale lease lock file")
+	if err := os.Remove(lockPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return errors.Wrap(err, "could not remove stale lease lock file")
+	}
+	return nil
+}
+
+// createLockFile creates the lock file at the path, returning false if it already exists.
+func createLockFile(path string) (bool, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, params.BeaconIoConfig().ReadWritePermissions)
+	if errors.Is(err, os.ErrExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "could not create lease lock file")
+	}
+	if _, err := fmt.Fprintf(f, "%d", os.Getpid()); err != nil {
+		log.WithError(err).Debug("Could not write pid to lease lock file")
+	}
+	if err := f.Close(); err != nil {
+		log.WithError(err).Debug("Could not close lease lock file")
+	}
+	return true, nil
+}
+
+func readLeaseFile(path string) (*Lease, error) {
+	enc, err := os.ReadFile(path) // #nosec G304 -- lease files are named after public keys in the lease directory.
+	if err != nil {
+		return nil, err
+	}
+	l := &Lease{}
+	if err := json.Unmarshal(enc, l); err != nil {
+		return nil, errors.Wrapf(err, "could not unmarshal lease file %s", path)
+	}
+	if len(l.PublicKey) != fieldparams.BLSPubkeyLength {
+		return nil, fmt.Errorf("lease file %s has a public key of length %d", path, len(l.PublicKey))
+	}
+	return l, nil
+}
@@ -0,0 +1,187 @@
+package lease
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func testKey(b byte) [fieldparams.BLSPubkeyLength]byte {
+	var pk [fieldparams.BLSPubkeyLength]byte
+	pk[0] = b
+	return pk
+}
+
+func TestFileStore_Acquire(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	pk := testKey(1)
+
+	l, err := s.Acquire(ctx, pk, "alice", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", l.Owner)
+	assert.DeepEqual(t, pk[:], []byte(l.PublicKey))
+
+	// Renewing by the same owner succeeds.
+	renewed, err := s.Acquire(ctx, pk, "alice", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, false, renewed.Expiry.Before(l.Expiry))
+
+	// Another owner cannot take an unexpired lease.
+	_, err = s.Acquire(ctx, pk, "bob", time.Minute)
+	require.ErrorIs(t, err, ErrLeaseHeld)
+
+	_, err = s.Acquire(ctx, pk, "", time.Minute)
+	require.ErrorIs(t, err, ErrEmptyOwner)
+}
+
+func TestFileStore_AcquireExpired(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	pk := testKey(1)
+
+	_, err = s.Acquire(ctx, pk, "alice", time.Millisecond)
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	l, err := s.Acquire(ctx, pk, "bob", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "bob", l.Owner)
+}
+
+func TestFileStore_Release(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	pk := testKey(1)
+
+	require.NoError(t, s.Release(ctx, pk, "alice"))
+	_, err = s.Acquire(ctx, pk, "alice", time.Minute)
+	require.NoError(t, err)
+	require.ErrorIs(t, s.Release(ctx, pk, "bob"), ErrNotLeaseHolder)
+	require.NoError(t, s.Release(ctx, pk, "alice"))
+
+	leases, err := s.Leases(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(leases))
+	_, err = s.Acquire(ctx, pk, "bob", time.Minute)
+	require.NoError(t, err)
+}
+
+func TestFileStore_Transfer(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	pk1, pk2, pk3 := testKey(1), testKey(2), testKey(3)
+
+	old1, err := s.Acquire(ctx, pk1, "alice", time.Minute)
+	require.NoError(t, err)
+	_, err = s.Acquire(ctx, pk2, "alice", time.Minute)
+	require.NoError(t, err)
+	_, err = s.Acquire(ctx, pk3, "carol", time.Minute)
+	require.NoError(t, err)
+
+	t.Run("all or nothing", func(t *testing.T) {
+		_, err := s.Transfer(ctx, [][fieldparams.BLSPubkeyLength]byte{pk1, pk3}, "alice", "bob", time.Minute)
+		require.ErrorIs(t, err, ErrNotLeaseHolder)
+		leases, err := s.Leases(ctx)
+		require.NoError(t, err)
+		for _, l := range leases {
+			assert.NotEqual(t, "bob", l.Owner)
+		}
+	})
+	t.Run("ok", func(t *testing.T) {
+		leases, err := s.Transfer(ctx, [][fieldparams.BLSPubkeyLength]byte{pk1, pk2}, "alice", "bob", time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(leases))
+		assert.Equal(t, "bob", leases[0].Owner)
+		// The new lease only becomes usable once the previous one has expired.
+		assert.Equal(t, true, leases[0].NotBefore.Equal(old1.Expiry))
+		assert.Equal(t, false, leases[0].Usable(time.Now()))
+		assert.Equal(t, true, leases[0].Usable(old1.Expiry))
+
+		_, err = s.Acquire(ctx, pk1, "alice", time.Minute)
+		require.ErrorIs(t, err, ErrLeaseHeld)
+		renewed, err := s.Acquire(ctx, pk1, "bob", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, true, renewed.NotBefore.Equal(old1.Expiry))
+	})
+}
+
+func TestFileStore_TransferExpired(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	pk1, pk2 := testKey(1), testKey(2)
+
+	_, err = s.Acquire(ctx, pk1, "alice", time.Millisecond)
+	require.NoError(t, err)
+	_, err = s.Acquire(ctx, pk2, "alice", time.Millisecond)
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	_, err = s.Acquire(ctx, pk2, "carol", time.Minute)
+	require.NoError(t, err)
+
+	// An expired lease can be handed over as long as no one else acquired it since.
+	_, err = s.Transfer(ctx, [][fieldparams.BLSPubkeyLength]byte{pk2}, "alice", "bob", time.Minute)
+	require.ErrorIs(t, err, ErrNotLeaseHolder)
+	leases, err := s.Transfer(ctx, [][fieldparams.BLSPubkeyLength]byte{pk1}, "alice", "bob", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(leases))
+	assert.Equal(t, "bob", leases[0].Owner)
+	assert.Equal(t, true, leases[0].Usable(time.Now()))
+}
+
+func TestFileStore_StaleLock(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s, err := NewFileStore(dir)
+	require.NoError(t, err)
+	lockPath := filepath.Join(dir, lockFileName)
+	require.NoError(t, os.WriteFile(lockPath, []byte("1"), 0600))
+	stale := time.Now().Add(-2 * staleLockAge)
+	require.NoError(t, os.Chtimes(lockPath, stale, stale))
+
+	_, err = s.Acquire(ctx, testKey(1), "alice", time.Minute)
+	require.NoError(t, err)
+	_, err = os.Stat(lockPath)
+	require.ErrorIs(t, err, os.ErrNotExist)
+	_, err = os.Stat(lockPath + breakerFileSuffix)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestFileStore_BreakStaleLockReplaced(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewFileStore(dir)
+	require.NoError(t, err)
+	lockPath := filepath.Join(dir, lockFileName)
+	require.NoError(t, os.WriteFile(lockPath, []byte("1"), 0600))
+	stale, err := os.Stat(lockPath)
+	require.NoError(t, err)
+
+	// Another process broke the stale lock and took a fresh one in the meantime.
+	require.NoError(t, os.Remove(lockPath))
+	require.NoError(t, os.WriteFile(lockPath, []byte("2"), 0600))
+	require.NoError(t, s.breakStaleLock(lockPath, stale))
+	_, err = os.Stat(lockPath)
+	require.NoError(t, err)
+}
+
+func TestFileStore_LockContextCanceled(t *testing.T) {
+	s, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	err = s.withLock(ctx, func() error {
+		cancel()
+		_, err := s.Acquire(ctx, testKey(1), "alice", time.Minute)
+		return err
+	})
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,174 @@
+package lease
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	validatorpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1/validator-client"
+	prysmTime "github.com/prysmaticlabs/prysm/v5/time"
+	"github.com/prysmaticlabs/prysm/v5/validator/keymanager"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultTTL is the default duration of a lease. Leases are renewed every third of their duration.
+const DefaultTTL = 2 * time.Minute
+
+// Guard acquires and renews the leases of a set of keys on behalf of an owner, and
+// keeps track of which leases the owner currently holds.
+type Guard struct {
+	store  Store
+	owner  string
+	ttl    time.Duration
+	leases map[[fieldparams.BLSPubkeyLength]byte]*Lease
+	lock   sync.RWMutex
+}
+
+// NewGuard creates a guard acquiring leases from the store for the owner.
+func NewGuard(store Store, owner string, ttl time.Duration) (*Guard, error) {
+	if owner == "" {
+		return nil, ErrEmptyOwner
+	}
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Guard{
+		store:  store,
+		owner:  owner,
+		ttl:    ttl,
+		leases: make(map[[fieldparams.BLSPubkeyLength]byte]*Lease),
+	}, nil
+}
+
+// Owner returns the owner the guard acquires leases for.
+func (g *Guard) Owner() string {
+	return g.owner
+}
+
+// Holds returns true if the guard holds a lease for the key which is usable right now.
+func (g *Guard) Holds(pubkey [fieldparams.BLSPubkeyLength]byte) bool {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.leases[pubkey].Usable(prysmTime.Now())
+}
+
+// Renew acquires or renews the lease of every key. Keys leased to other owners are skipped,
+// and the first error other than ErrLeaseHeld is returned after all keys have been tried.
+func (g *Guard) Renew(ctx context.Context, pubkeys [][fieldparams.BLSPubkeyLength]byte) error {
+	var firstErr error
+	for _, pk := range pubkeys {
+		l, err := g.store.Acquire(ctx, pk, g.owner, g.ttl)
+		if err != nil {
+			g.forget(pk)
+			if errors.Is(err, ErrLeaseHeld) {
+				log.WithError(err).WithField("pubkey", shortKey(pk)).Warn("Key is leased to another validator client, not signing with it")
+				continue
+			}
+			if firstErr == nil {
+				firstErr = errors.Wrapf(err, "could not acquire lease of key %#x", pk)
+			}
+			continue
+		}
+		g.lock.Lock()
+		if _, ok := g.leases[pk]; !ok {
+			log.WithFields(logrus.Fields{
+				"pubkey":    shortKey(pk),
+				"notBefore": l.NotBefore,
+				"expiry":    l.Expiry,
+			}).Info("Acquired key lease")
+		}
+		g.leases[pk] = l
+		g.lock.Unlock()
+	}
+	return firstErr
+}
+
+// Release gives up every lease held by the guard. Other owners can acquire the released keys
+// right away, so this must only be called once the slashing protection history of the keys
+// has been made available to whoever signs with them next.
+func (g *Guard) Release(ctx context.Context) {
+	g.lock.RLock()
+	pubkeys := make([][fieldparams.BLSPubkeyLength]byte, 0, len(g.leases))
+	for pk := range g.leases {
+		pubkeys = append(pubkeys, pk)
+	}
+	g.lock.RUnlock()
+	for _, pk := range pubkeys {
+		if err := g.store.Release(ctx, pk, g.owner); err != nil {
+			log.WithError(err).WithField("pubkey", shortKey(pk)).Error("Could not release key lease")
+		}
+		g.forget(pk)
+	}
+}
+
+// Run renews the leases of the keys of the keymanager every third of the lease duration,
+// until the context is canceled. Held leases are kept on exit and expire on their own, so
+// that a stopped validator client can still hand its keys over.
+func (g *Guard) Run(ctx context.Context, km keymanager.PublicKeysFetcher) {
+	ticker := time.NewTicker(g.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			pubkeys, err := km.FetchValidatingPublicKeys(ctx)
+			if err != nil {
+				log.WithError(err).Error("Could not fetch validating keys to renew leases")
+				continue
+			}
+			if err := g.Renew(ctx, pubkeys); err != nil {
+				log.WithError(err).Error("Could not renew key leases")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (g *Guard) forget(pubkey [fieldparams.BLSPubkeyLength]byte) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	delete(g.leases, pubkey)
+}
+
+// Keymanager wraps a keymanager and refuses to sign with keys whose lease is not held by its guard.
+type Keymanager struct {
+	keymanager.IKeymanager
+	guard *Guard
+}
+
+// NewKeymanager wraps the keymanager with the lease checks of the guard.
+func NewKeymanager(km keymanager.IKeymanager, guard *Guard) *Keymanager {
+	return &Keymanager{IKeymanager: km, guard: guard}
+}
+
+// Sign signs the request only if the lease of the signing key is held.
+func (km *Keymanager) Sign(ctx context.Context, req *validatorpb.SignRequest) (bls.Signature, error) {
+	if !km.guard.Holds(bytesutil.ToBytes48(req.PublicKey)) {
+		return nil, errors.Wrapf(ErrNotLeaseHolder, "refusing to sign with key %#x", req.PublicKey)
+	}
+	return km.IKeymanager.Sign(ctx, req)
+}
+
+// Unwrap returns the wrapped keymanager.
+func (km *Keymanager) Unwrap() keymanager.IKeymanager {
+	return km.IKeymanager
+}
+
+// Unwrap returns the keymanager wrapped by a lease keymanager, or the keymanager itself
+// if it is not wrapped. Callers that type assert on the keymanager implementation should
+// use the unwrapped keymanager.
+func Unwrap(km keymanager.IKeymanager) keymanager.IKeymanager {
+	if wrapped, ok := km.(*Keymanager); ok {
+		return wrapped.Unwrap()
+	}
+	return km
+}
+
+func shortKey(pubkey [fieldparams.BLSPubkeyLength]byte) string {
+	return fmt.Sprintf("%#x", bytesutil.Trunc(pubkey[:]))
+}
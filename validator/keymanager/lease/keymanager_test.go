@@ -0,0 +1,106 @@
+package lease
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+	validatorpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1/validator-client"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/validator/keymanager/local"
+)
+
+func TestGuard_Renew(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	pk1, pk2 := testKey(1), testKey(2)
+	_, err = s.Acquire(ctx, pk2, "bob", time.Minute)
+	require.NoError(t, err)
+
+	g, err := NewGuard(s, "alice", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, g.Renew(ctx, [][fieldparams.BLSPubkeyLength]byte{pk1, pk2}))
+	assert.Equal(t, true, g.Holds(pk1))
+	assert.Equal(t, false, g.Holds(pk2))
+
+	// Once handed over, the key is no longer held after the next renewal.
+	_, err = s.Transfer(ctx, [][fieldparams.BLSPubkeyLength]byte{pk1}, "alice", "bob", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, g.Renew(ctx, [][fieldparams.BLSPubkeyLength]byte{pk1}))
+	assert.Equal(t, false, g.Holds(pk1))
+
+	_, err = NewGuard(s, "", time.Minute)
+	require.ErrorIs(t, err, ErrEmptyOwner)
+}
+
+func TestGuard_Release(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	g, err := NewGuard(s, "alice", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, g.Renew(ctx, [][fieldparams.BLSPubkeyLength]byte{testKey(1)}))
+
+	g.Release(ctx)
+	assert.Equal(t, false, g.Holds(testKey(1)))
+	leases, err := s.Leases(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(leases))
+}
+
+func TestGuard_RunKeepsLeasesOnExit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	km, err := local.NewInteropKeymanager(ctx, 0, 1)
+	require.NoError(t, err)
+	pubkeys, err := km.FetchValidatingPublicKeys(ctx)
+	require.NoError(t, err)
+	g, err := NewGuard(s, "alice", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, g.Renew(ctx, pubkeys))
+
+	done := make(chan struct{})
+	go func() {
+		g.Run(ctx, km)
+		close(done)
+	}()
+	cancel()
+	<-done
+
+	// The lease outlives the guard, so no one else can sign with the key until it expires.
+	_, err = s.Acquire(context.Background(), pubkeys[0], "bob", time.Minute)
+	require.ErrorIs(t, err, ErrLeaseHeld)
+}
+
+func TestKeymanager_Sign(t *testing.T) {
+	ctx := context.Background()
+	km, err := local.NewInteropKeymanager(ctx, 0, 2)
+	require.NoError(t, err)
+	pubkeys, err := km.FetchValidatingPublicKeys(ctx)
+	require.NoError(t, err)
+
+	s, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	_, err = s.Acquire(ctx, pubkeys[1], "bob", time.Minute)
+	require.NoError(t, err)
+	g, err := NewGuard(s, "alice", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, g.Renew(ctx, pubkeys))
+
+	leased := NewKeymanager(km, g)
+	sig, err := leased.Sign(ctx, &validatorpb.SignRequest{PublicKey: pubkeys[0][:], SigningRoot: make([]byte, 32)})
+	require.NoError(t, err)
+	_, err = bls.SignatureFromBytes(sig.Marshal())
+	require.NoError(t, err)
+
+	_, err = leased.Sign(ctx, &validatorpb.SignRequest{PublicKey: pubkeys[1][:], SigningRoot: make([]byte, 32)})
+	require.ErrorIs(t, err, ErrNotLeaseHolder)
+
+	assert.Equal(t, km, Unwrap(leased))
+	assert.Equal(t, km, Unwrap(km))
+}
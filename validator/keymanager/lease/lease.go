@@ -0,0 +1,63 @@
+// Package lease implements a cooperative key-ownership protocol for validator clients
+// sharing a set of validating keys. A validator client only signs with a key while it
+// holds an unexpired lease for it in a shared lease store, and leases can be handed over
+// atomically from one client to another.
+package lease
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+)
+
+var (
+	// ErrLeaseHeld is returned when a lease is held by a different owner.
+	ErrLeaseHeld = errors.New("lease is held by another owner")
+	// ErrNotLeaseHolder is returned when an operation requires the caller to hold the lease.
+	ErrNotLeaseHolder = errors.New("caller does not hold the lease")
+	// ErrEmptyOwner is returned when a lease operation is attempted without an owner.
+	ErrEmptyOwner = errors.New("lease owner must not be empty")
+)
+
+// Lease grants its owner the exclusive right to sign with a validating key until the expiry.
+// A lease obtained through a handover only becomes usable once the lease of the previous owner
+// has expired, which is recorded in NotBefore.
+type Lease struct {
+	PublicKey hexutil.Bytes `json:"pubkey"`
+	Owner     string        `json:"owner"`
+	NotBefore time.Time     `json:"not_before"`
+	Expiry    time.Time     `json:"expiry"`
+}
+
+// Expired returns true if the lease is no longer valid at the given time.
+func (l *Lease) Expired(now time.Time) bool {
+	return !now.Before(l.Expiry)
+}
+
+// Usable returns true if the owner of the lease may sign with the key at the given time.
+func (l *Lease) Usable(now time.Time) bool {
+	return l != nil && !now.Before(l.NotBefore) && !l.Expired(now)
+}
+
+// HeldBy returns true if the lease belongs to the owner and has not expired at the given time.
+func (l *Lease) HeldBy(owner string, now time.Time) bool {
+	return l != nil && l.Owner == owner && !l.Expired(now)
+}
+
+// Store keeps track of the leases of a set of validating keys shared by several validator clients.
+type Store interface {
+	// Acquire grants or renews the lease of a key for the owner. It fails with ErrLeaseHeld
+	// if an unexpired lease for the key is held by a different owner.
+	Acquire(ctx context.Context, pubkey [fieldparams.BLSPubkeyLength]byte, owner string, ttl time.Duration) (*Lease, error)
+	// Release gives up the lease of a key held by the owner.
+	Release(ctx context.Context, pubkey [fieldparams.BLSPubkeyLength]byte, owner string) error
+	// Transfer moves the leases of all keys from one owner to another, including leases of the
+	// previous owner which expired without being acquired by anyone else. Either every lease is
+	// transferred or none is.
+	Transfer(ctx context.Context, pubkeys [][fieldparams.BLSPubkeyLength]byte, from, to string, ttl time.Duration) ([]*Lease, error)
+	// Leases lists all leases in the store, including expired ones.
+	Leases(ctx context.Context) ([]*Lease, error)
+}
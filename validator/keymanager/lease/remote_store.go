@@ -0,0 +1,170 @@
+package lease
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
+)
+
+const (
+	leasesPath   = "leases"
+	acquirePath  = leasesPath + "/acquire"
+	releasePath  = leasesPath + "/release"
+	transferPath = leasesPath + "/transfer"
+)
+
+// Request and response bodies of the lease endpoints served by the validator client REST API.
+type (
+	AcquireRequest struct {
+		Pubkey     string `json:"pubkey"`
+		Owner      string `json:"owner"`
+		TTLSeconds uint64 `json:"ttl_seconds"`
+	}
+	ReleaseRequest struct {
+		Pubkey string `json:"pubkey"`
+		Owner  string `json:"owner"`
+	}
+	TransferRequest struct {
+		Pubkeys    []string `json:"pubkeys"`
+		From       string   `json:"from"`
+		To         string   `json:"to"`
+		TTLSeconds uint64   `json:"ttl_seconds"`
+	}
+	LeaseResponse struct {
+		Data *Lease `json:"data"`
+	}
+	LeasesResponse struct {
+		Data []*Lease `json:"data"`
+	}
+)
+
+// RemoteStore is a lease store living in another validator client, accessed through
+// the lease endpoints of its REST API.
+type RemoteStore struct {
+	baseURL   string
+	authToken string
+	client    *http.Client
+}
+
+// NewRemoteStore creates a lease store client for the validator client REST API at the given url.
+// The auth token is the one generated by the remote validator client for its REST API.
+func NewRemoteStore(endpoint, authToken string) (*RemoteStore, error) {
+	u, err := url.ParseRequestURI(endpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid lease store url")
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("lease store url must be in the format of http(s)://host:port url used: %v", endpoint)
+	}
+	return &RemoteStore{
+		baseURL:   strings.TrimSuffix(u.String(), "/") + api.WebApiUrlPrefix,
+		authToken: authToken,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Acquire grants or renews the lease of a key for the owner.
+func (s *RemoteStore) Acquire(ctx context.Context, pubkey [fieldparams.BLSPubkeyLength]byte, owner string, ttl time.Duration) (*Lease, error) {
+	req := &AcquireRequest{Pubkey: hexutil.Encode(pubkey[:]), Owner: owner, TTLSeconds: uint64(ttl.Seconds())}
+	resp := &LeaseResponse{}
+	if err := s.post(ctx, acquirePath, req, resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// Release gives up the lease of a key held by the owner.
+func (s *RemoteStore) Release(ctx context.Context, pubkey [fieldparams.BLSPubkeyLength]byte, owner string) error {
+	req := &ReleaseRequest{Pubkey: hexutil.Encode(pubkey[:]), Owner: owner}
+	return s.post(ctx, releasePath, req, nil)
+}
+
+// Transfer moves the leases of all keys from one owner to another.
+func (s *RemoteStore) Transfer(
+	ctx context.Context,
+	pubkeys [][fieldparams.BLSPubkeyLength]byte,
+	from, to string,
+	ttl time.Duration,
+) ([]*Lease, error) {
+	req := &TransferRequest{
+		Pubkeys:    make([]string, len(pubkeys)),
+		From:       from,
+		To:         to,
+		TTLSeconds: uint64(ttl.Seconds()),
+	}
+	for i, pk := range pubkeys {
+		req.Pubkeys[i] = hexutil.Encode(pk[:])
+	}
+	resp := &LeasesResponse{}
+	if err := s.post(ctx, transferPath, req, resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// Leases lists all leases in the remote store.
+func (s *RemoteStore) Leases(ctx context.Context) ([]*Lease, error) {
+	resp := &LeasesResponse{}
+	if err := s.do(ctx, http.MethodGet, leasesPath, nil, resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (s *RemoteStore) post(ctx context.Context, path string, body, out interface{}) error {
+	enc, err := json.Marshal(body)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal lease request")
+	}
+	return s.do(ctx, http.MethodPost, path, bytes.NewReader(enc), out)
+}
+
+func (s *RemoteStore) do(ctx context.Context, method, path string, body io.Reader, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, body)
+	if err != nil {
+		return errors.Wrap(err, "could not create lease request")
+	}
+	req.Header.Set("Content-Type", api.JsonMediaType)
+	if s.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+s.authToken)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "lease request failed")
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.WithError(err).Debug("Could not close lease response body")
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		errJson := &httputil.DefaultJsonError{}
+		if err := json.NewDecoder(resp.Body).Decode(errJson); err != nil {
+			return fmt.Errorf("lease request failed with status %d", resp.StatusCode)
+		}
+		switch resp.StatusCode {
+		case http.StatusConflict:
+			return errors.Wrap(ErrLeaseHeld, errJson.Message)
+		case http.StatusPreconditionFailed:
+			return errors.Wrap(ErrNotLeaseHolder, errJson.Message)
+		default:
+			return fmt.Errorf("lease request failed with status %d: %s", resp.StatusCode, errJson.Message)
+		}
+	}
+	if out == nil {
+		return nil
+	}
+	return errors.Wrap(json.NewDecoder(resp.Body).Decode(out), "could not decode lease response")
+}
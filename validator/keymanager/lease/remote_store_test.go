@@ -0,0 +1,57 @@
+package lease
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/v5/api"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func TestRemoteStore(t *testing.T) {
+	ctx := context.Background()
+	pk := testKey(1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+api.WebApiUrlPrefix+acquirePath, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		req := &AcquireRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		if req.Owner != "alice" {
+			httputil.HandleError(w, "leased to alice", http.StatusConflict)
+			return
+		}
+		assert.Equal(t, uint64(60), req.TTLSeconds)
+		httputil.WriteJson(w, &LeaseResponse{Data: &Lease{PublicKey: pk[:], Owner: req.Owner}})
+	})
+	mux.HandleFunc("POST "+api.WebApiUrlPrefix+releasePath, func(w http.ResponseWriter, r *http.Request) {
+		httputil.HandleError(w, "not the holder", http.StatusPreconditionFailed)
+	})
+	mux.HandleFunc("GET "+api.WebApiUrlPrefix+leasesPath, func(w http.ResponseWriter, r *http.Request) {
+		httputil.WriteJson(w, &LeasesResponse{Data: []*Lease{{PublicKey: pk[:], Owner: "alice"}}})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	s, err := NewRemoteStore(srv.URL, "token")
+	require.NoError(t, err)
+
+	l, err := s.Acquire(ctx, pk, "alice", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", l.Owner)
+	_, err = s.Acquire(ctx, pk, "bob", time.Minute)
+	require.ErrorIs(t, err, ErrLeaseHeld)
+	require.ErrorIs(t, s.Release(ctx, pk, "bob"), ErrNotLeaseHolder)
+	leases, err := s.Leases(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(leases))
+	assert.DeepEqual(t, pk[:], []byte(leases[0].PublicKey))
+
+	_, err = NewRemoteStore("localhost", "")
+	require.ErrorContains(t, "lease store url", err)
+}
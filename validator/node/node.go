@@ -42,6 +42,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/validator/db/iface"
 	"github.com/prysmaticlabs/prysm/v5/validator/db/kv"
 	g "github.com/prysmaticlabs/prysm/v5/validator/graffiti"
+	"github.com/prysmaticlabs/prysm/v5/validator/keymanager/lease"
 	"github.com/prysmaticlabs/prysm/v5/validator/keymanager/local"
 	remoteweb3signer "github.com/prysmaticlabs/prysm/v5/validator/keymanager/remote-web3signer"
 	"github.com/prysmaticlabs/prysm/v5/validator/rpc"
@@ -482,11 +483,28 @@ func (c *ValidatorClient) registerValidatorService(cliCtx *cli.Context) error {
 		return err
 	}
 
-	ps, err := proposerSettings(c.cliCtx, c.db)
+	ps, settingsRefresher, err := proposerSettings(c.cliCtx, c.db)
 	if err != nil {
 		return err
 	}
 
+	leaseStore, err := KeyLeaseStore(c.cliCtx)
+	if err != nil {
+		return err
+	}
+	var leaseGuard *lease.Guard
+	if leaseStore != nil {
+		owner, err := KeyLeaseOwner(c.cliCtx)
+		if err != nil {
+			return err
+		}
+		leaseGuard, err = lease.NewGuard(leaseStore, owner, c.cliCtx.Duration(flags.KeyLeaseDurationFlag.Name))
+		if err != nil {
+			return errors.Wrap(err, "could not initialize key lease guard")
+		}
+		log.WithField("owner", owner).Info("Key leases enabled, only signing with leased keys")
+	}
+
 	validatorService, err := client.NewValidatorService(c.cliCtx.Context, &client.Config{
 		DB:                      c.db,
 		Wallet:                  c.wallet,
@@ -502,8 +520,12 @@ func (c *ValidatorClient) registerValidatorService(cliCtx *cli.Context) error {
 		Graffiti:                g.ParseHexGraffiti(c.cliCtx.String(flags.GraffitiFlag.Name)),
 		GraffitiStruct:          graffitiStruct,
 		InteropKmConfig:         interopKmConfig,
+		LeaseStore:              leaseStore,
+		LeaseGuard:              leaseGuard,
 		Web3SignerConfig:        web3signerConfig,
 		ProposerSettings:        ps,
+		SettingsRefresher:       settingsRefresher,
+		SettingsRefreshInterval: c.cliCtx.Duration(flags.ProposerSettingsRefreshIntervalFlag.Name),
 		ValidatorsRegBatchSize:  c.cliCtx.Int(flags.ValidatorsRegistrationBatchSizeFlag.Name),
 		UseWeb:                  c.cliCtx.Bool(flags.EnableWebFlag.Name),
 		LogValidatorPerformance: !c.cliCtx.Bool(flags.DisablePenaltyRewardLogFlag.Name),
@@ -555,7 +577,58 @@ func Web3SignerConfig(cliCtx *cli.Context) (*remoteweb3signer.SetupConfig, error
 	return web3signerConfig, nil
 }
 
-func proposerSettings(cliCtx *cli.Context, db iface.ValidatorDB) (*proposer.Settings, error) {
+// KeyLeaseStore returns the key lease store configured by either the key lease directory or
+// the key lease url flag, or nil if key leases are not enabled.
+func KeyLeaseStore(cliCtx *cli.Context) (lease.Store, error) {
+	dir := cliCtx.String(flags.KeyLeaseDirFlag.Name)
+	endpoint := cliCtx.String(flags.KeyLeaseURLFlag.Name)
+	switch {
+	case dir != "" && endpoint != "":
+		return nil, fmt.Errorf("only one of --%s and --%s may be set", flags.KeyLeaseDirFlag.Name, flags.KeyLeaseURLFlag.Name)
+	case dir != "":
+		store, err := lease.NewFileStore(dir)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	case endpoint != "":
+		var token string
+		if tokenFile := cliCtx.String(flags.KeyLeaseTokenFileFlag.Name); tokenFile != "" {
+			enc, err := file.ReadFileAsBytes(tokenFile)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not read key lease token file")
+			}
+			token = strings.TrimSpace(string(enc))
+		}
+		store, err := lease.NewRemoteStore(endpoint, token)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	default:
+		return nil, nil
+	}
+}
+
+// KeyLeaseOwner returns the name under which key leases are acquired, which defaults to the hostname.
+func KeyLeaseOwner(cliCtx *cli.Context) (string, error) {
+	if owner := cliCtx.String(flags.KeyLeaseOwnerFlag.Name); owner != "" {
+		return owner, nil
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", errors.Wrapf(err, "could not determine hostname, please set --%s", flags.KeyLeaseOwnerFlag.Name)
+	}
+	return hostname, nil
+}
+
+// proposerSettings loads the proposer settings. The returned refresher is only set if the settings
+// are downloaded from a URL and should be refreshed.
+func proposerSettings(cliCtx *cli.Context, db iface.ValidatorDB) (*proposer.Settings, client.ProposerSettingsRefresher, error) {
+	refresh := cliCtx.Duration(flags.ProposerSettingsRefreshIntervalFlag.Name) > 0
+	if refresh && !cliCtx.IsSet(flags.ProposerSettingsURLFlag.Name) {
+		return nil, nil, fmt.Errorf("--%s requires --%s", flags.ProposerSettingsRefreshIntervalFlag.Name, flags.ProposerSettingsURLFlag.Name)
+	}
 	l, err := loader.NewProposerSettingsLoader(
 		cliCtx,
 		db,
@@ -563,9 +636,16 @@ func proposerSettings(cliCtx *cli.Context, db iface.ValidatorDB) (*proposer.Sett
 		loader.WithGasLimit(),
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	ps, err := l.Load(cliCtx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !refresh {
+		return ps, nil, nil
 	}
-	return l.Load(cliCtx)
+	return ps, l, nil
 }
 
 func (c *ValidatorClient) registerRPCService(router *http.ServeMux) error {
@@ -0,0 +1,110 @@
+package requests
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	enginev1 "github.com/prysmaticlabs/prysm/v5/proto/engine/v1"
+)
+
+// PlannedConsolidation is the predicted outcome of one request of a consolidation plan. Err
+// explains why the request would be ignored by the beacon chain, in which case Outcome is nil.
+type PlannedConsolidation struct {
+	SourcePubkey []byte
+	Outcome      *ConsolidationOutcome
+	Err          error
+}
+
+// ConsolidationPlan is the predicted outcome of consolidating a set of validators into a target.
+type ConsolidationPlan struct {
+	TargetIndex primitives.ValidatorIndex
+	// SwitchToCompounding is set if the target needs compounding credentials first. The plan then
+	// starts with a request switching the target.
+	SwitchToCompounding *PlannedConsolidation
+	Consolidations      []*PlannedConsolidation
+	// TargetBalance and TargetEffectiveBalance are the expected balances of the target once every
+	// consolidation of the plan has been processed, in CompletionEpoch.
+	TargetBalance          uint64
+	TargetEffectiveBalance uint64
+	CompletionEpoch        primitives.Epoch
+}
+
+// PlanConsolidations predicts the outcome of consolidating the source validators into the target,
+// with one consolidation request per source sent in order from the withdrawal address of the
+// source. Each request is processed against the state left by the previous ones, so later requests
+// see the consolidation churn consumed by earlier ones. The state is left untouched.
+func PlanConsolidations(ctx context.Context, st state.BeaconState, target []byte, sources [][]byte) (*ConsolidationPlan, error) {
+	cfg := params.BeaconConfig()
+	tgtIdx, ok := st.ValidatorIndexByPubkey(bytesutil.ToBytes48(target))
+	if !ok {
+		return nil, errors.Errorf("target validator %#x not found", target)
+	}
+	tgt, err := st.ValidatorAtIndexReadOnly(tgtIdx)
+	if err != nil {
+		return nil, err
+	}
+	tgtBal, err := st.BalanceAtIndex(tgtIdx)
+	if err != nil {
+		return nil, err
+	}
+	plan := &ConsolidationPlan{
+		TargetIndex:    tgtIdx,
+		Consolidations: make([]*PlannedConsolidation, len(sources)),
+		TargetBalance:  tgtBal,
+	}
+
+	post := st.Copy()
+	if !helpers.HasCompoundingWithdrawalCredential(tgt) {
+		planned, err := planConsolidation(ctx, post, target, target)
+		if err != nil {
+			return nil, err
+		}
+		if planned.Err != nil {
+			return nil, errors.Wrap(planned.Err, "target cannot switch to compounding withdrawal credentials")
+		}
+		plan.SwitchToCompounding = planned
+	}
+	for i, source := range sources {
+		planned, err := planConsolidation(ctx, post, source, target)
+		if err != nil {
+			return nil, err
+		}
+		plan.Consolidations[i] = planned
+		if planned.Err != nil {
+			continue
+		}
+		plan.TargetBalance += planned.Outcome.MovedBalance
+		// Pending consolidations are processed once the source is withdrawable.
+		plan.CompletionEpoch = max(plan.CompletionEpoch, planned.Outcome.WithdrawableEpoch)
+	}
+	plan.TargetEffectiveBalance = min(
+		plan.TargetBalance-plan.TargetBalance%cfg.EffectiveBalanceIncrement,
+		cfg.MaxEffectiveBalanceElectra,
+	)
+	return plan, nil
+}
+
+// planConsolidation processes the consolidation of the source into the target on the state. Errors
+// explaining why the request would be ignored are recorded in the returned value.
+func planConsolidation(ctx context.Context, st state.BeaconState, source, target []byte) (*PlannedConsolidation, error) {
+	planned := &PlannedConsolidation{SourcePubkey: source}
+	address, err := WithdrawalAddress(st, source)
+	if err != nil {
+		planned.Err = err
+		return planned, nil
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	planned.Outcome, planned.Err = processConsolidation(ctx, st, &enginev1.ConsolidationRequest{
+		SourceAddress: address.Bytes(),
+		SourcePubkey:  source,
+		TargetPubkey:  target,
+	})
+	return planned, nil
+}
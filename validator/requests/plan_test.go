@@ -0,0 +1,55 @@
+package requests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func TestPlanConsolidations(t *testing.T) {
+	ctx := context.Background()
+	cfg := params.BeaconConfig()
+	address := common.HexToAddress("0xb20a608c624Ca5003905aA834De7156C68b2E1d0")
+	st := testState(t, 4, cfg.ETH1AddressWithdrawalPrefixByte, address)
+	withConsolidationChurn(t, st, 3)
+	target, src1, src2 := st.PubkeyAtIndex(0), st.PubkeyAtIndex(1), st.PubkeyAtIndex(2)
+	unknown := bytesutil.PadTo([]byte{0xff}, 48)
+
+	plan, err := PlanConsolidations(ctx, st, target[:], [][]byte{src1[:], unknown, src2[:]})
+	require.NoError(t, err)
+	require.NotNil(t, plan.SwitchToCompounding)
+	require.NoError(t, plan.SwitchToCompounding.Err)
+	assert.Equal(t, true, plan.SwitchToCompounding.Outcome.SwitchToCompounding)
+
+	require.Equal(t, 3, len(plan.Consolidations))
+	first, second := plan.Consolidations[0], plan.Consolidations[2]
+	require.NoError(t, first.Err)
+	require.NoError(t, second.Err)
+	require.ErrorContains(t, "not found", plan.Consolidations[1].Err)
+	assert.Equal(t, true, second.Outcome.ExitEpoch >= first.Outcome.ExitEpoch)
+	assert.Equal(t, second.Outcome.WithdrawableEpoch, plan.CompletionEpoch)
+	assert.Equal(t, 3*cfg.MinActivationBalance, plan.TargetBalance)
+	assert.Equal(t, 3*cfg.MinActivationBalance, plan.TargetEffectiveBalance)
+
+	// The state of the beacon node is left untouched.
+	v, err := st.ValidatorAtIndexReadOnly(1)
+	require.NoError(t, err)
+	assert.Equal(t, cfg.FarFutureEpoch, v.ExitEpoch())
+}
+
+func TestPlanConsolidations_TargetCannotSwitch(t *testing.T) {
+	cfg := params.BeaconConfig()
+	st := testState(t, 2, cfg.ETH1AddressWithdrawalPrefixByte, common.Address{})
+	v, err := st.ValidatorAtIndex(0)
+	require.NoError(t, err)
+	v.ExitEpoch = 10
+	require.NoError(t, st.UpdateValidatorAtIndex(0, v))
+	target, source := st.PubkeyAtIndex(0), st.PubkeyAtIndex(1)
+	_, err = PlanConsolidations(context.Background(), st, target[:], [][]byte{source[:]})
+	require.ErrorContains(t, "target cannot switch to compounding", err)
+}
@@ -0,0 +1,295 @@
+// Package requests checks execution layer requests of validators, EIP-7002 withdrawal requests
+// and EIP-7251 consolidation requests, against a beacon state and predicts their outcome. The
+// beacon chain silently ignores invalid requests while their fee is still paid, so they should be
+// checked before being sent.
+package requests
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/electra"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/interfaces"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	enginev1 "github.com/prysmaticlabs/prysm/v5/proto/engine/v1"
+	"github.com/prysmaticlabs/prysm/v5/runtime/version"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+)
+
+// WithdrawalOutcome describes the effect of a withdrawal request processed against a state.
+type WithdrawalOutcome struct {
+	Index primitives.ValidatorIndex
+	// Amount is the amount of a partial withdrawal, capped to the balance above the minimum
+	// activation balance.
+	Amount            uint64
+	ExitEpoch         primitives.Epoch
+	WithdrawableEpoch primitives.Epoch
+}
+
+// ConsolidationOutcome describes the effect of a consolidation request processed against a state.
+type ConsolidationOutcome struct {
+	SourceIndex primitives.ValidatorIndex
+	TargetIndex primitives.ValidatorIndex
+	// SwitchToCompounding is set for requests with the same source and target, which switch the
+	// validator to compounding credentials instead of consolidating it.
+	SwitchToCompounding bool
+	// QueuedBalance is the balance above the minimum activation balance queued again as a deposit
+	// when switching to compounding credentials.
+	QueuedBalance     uint64
+	ExitEpoch         primitives.Epoch
+	WithdrawableEpoch primitives.Epoch
+	// MovedBalance is the balance moved from the source to the target once the source is
+	// withdrawable, and WithdrawnBalance the rest of the balance of the source, which is withdrawn.
+	MovedBalance     uint64
+	WithdrawnBalance uint64
+}
+
+// CheckWithdrawal verifies that the withdrawal request would be processed by the beacon chain,
+// following process_withdrawal_request, and explains why it would not be otherwise. The state is
+// left untouched.
+func CheckWithdrawal(ctx context.Context, st state.BeaconState, req *enginev1.WithdrawalRequest) (*WithdrawalOutcome, error) {
+	if st.Version() < version.Electra {
+		return nil, fmt.Errorf("withdrawal requests are only processed from Electra, state is %s", version.String(st.Version()))
+	}
+	cfg := params.BeaconConfig()
+	isFullExit := req.Amount == cfg.FullExitRequestAmount
+	n, err := st.NumPendingPartialWithdrawals()
+	if err != nil {
+		return nil, err
+	}
+	if n >= cfg.PendingPartialWithdrawalsLimit && !isFullExit {
+		return nil, fmt.Errorf("pending partial withdrawal queue is full (%d)", n)
+	}
+	idx, ok := st.ValidatorIndexByPubkey(bytesutil.ToBytes48(req.ValidatorPubkey))
+	if !ok {
+		return nil, fmt.Errorf("validator %#x not found", req.ValidatorPubkey)
+	}
+	v, err := st.ValidatorAtIndexReadOnly(idx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSource(idx, v, req.SourceAddress); err != nil {
+		return nil, err
+	}
+	currentEpoch := slots.ToEpoch(st.Slot())
+	if err := checkCanExit(idx, v.ActivationEpoch(), v.ExitEpoch(), currentEpoch); err != nil {
+		return nil, err
+	}
+	pending, err := st.PendingBalanceToWithdraw(idx)
+	if err != nil {
+		return nil, err
+	}
+	if isFullExit {
+		if pending != 0 {
+			return nil, fmt.Errorf("validator %d has %d Gwei of pending partial withdrawals, it can only exit once they are processed", idx, pending)
+		}
+	} else {
+		if !helpers.HasCompoundingWithdrawalCredential(v) {
+			return nil, fmt.Errorf("validator %d needs compounding (%#x) withdrawal credentials for partial withdrawals, "+
+				"switch with a consolidation request having it as both source and target", idx, cfg.CompoundingWithdrawalPrefixByte)
+		}
+		if v.EffectiveBalance() < cfg.MinActivationBalance {
+			return nil, fmt.Errorf("validator %d has an effective balance of %d Gwei, below the minimum activation balance", idx, v.EffectiveBalance())
+		}
+		bal, err := st.BalanceAtIndex(idx)
+		if err != nil {
+			return nil, err
+		}
+		if bal <= cfg.MinActivationBalance+pending {
+			return nil, fmt.Errorf("validator %d has no balance above the minimum activation balance to withdraw: balance %d Gwei, pending withdrawals %d Gwei", idx, bal, pending)
+		}
+	}
+
+	// Process the request on a copy of the state to learn where it lands in the exit churn.
+	post, err := electra.ProcessWithdrawalRequests(ctx, st.Copy(), []*enginev1.WithdrawalRequest{req})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not process withdrawal request")
+	}
+	outcome := &WithdrawalOutcome{Index: idx}
+	if isFullExit {
+		pv, err := post.ValidatorAtIndexReadOnly(idx)
+		if err != nil {
+			return nil, err
+		}
+		outcome.ExitEpoch = pv.ExitEpoch()
+		outcome.WithdrawableEpoch = pv.WithdrawableEpoch()
+		return outcome, nil
+	}
+	ppws, err := post.PendingPartialWithdrawals()
+	if err != nil {
+		return nil, err
+	}
+	if len(ppws) == 0 || ppws[len(ppws)-1].Index != idx {
+		return nil, errors.New("withdrawal request was not queued")
+	}
+	outcome.Amount = ppws[len(ppws)-1].Amount
+	outcome.WithdrawableEpoch = ppws[len(ppws)-1].WithdrawableEpoch
+	return outcome, nil
+}
+
+// CheckConsolidation verifies that the consolidation request would be processed by the beacon
+// chain, following process_consolidation_request, and explains why it would not be otherwise.
+// The state is left untouched.
+func CheckConsolidation(ctx context.Context, st state.BeaconState, req *enginev1.ConsolidationRequest) (*ConsolidationOutcome, error) {
+	return processConsolidation(ctx, st.Copy(), req)
+}
+
+// processConsolidation checks the consolidation request and processes it on the state.
+func processConsolidation(ctx context.Context, st state.BeaconState, req *enginev1.ConsolidationRequest) (*ConsolidationOutcome, error) {
+	if st.Version() < version.Electra {
+		return nil, fmt.Errorf("consolidation requests are only processed from Electra, state is %s", version.String(st.Version()))
+	}
+	cfg := params.BeaconConfig()
+	currentEpoch := slots.ToEpoch(st.Slot())
+	srcIdx, ok := st.ValidatorIndexByPubkey(bytesutil.ToBytes48(req.SourcePubkey))
+	if !ok {
+		return nil, fmt.Errorf("source validator %#x not found", req.SourcePubkey)
+	}
+	src, err := st.ValidatorAtIndexReadOnly(srcIdx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSource(srcIdx, src, req.SourceAddress); err != nil {
+		return nil, err
+	}
+	bal, err := st.BalanceAtIndex(srcIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.Equal(req.SourcePubkey, req.TargetPubkey) {
+		if helpers.HasCompoundingWithdrawalCredential(src) {
+			return nil, fmt.Errorf("validator %d already has compounding withdrawal credentials", srcIdx)
+		}
+		if !helpers.IsActiveValidatorUsingTrie(src, currentEpoch) {
+			return nil, fmt.Errorf("validator %d is not active", srcIdx)
+		}
+		if src.ExitEpoch() != cfg.FarFutureEpoch {
+			return nil, fmt.Errorf("validator %d is already exiting at epoch %d", srcIdx, src.ExitEpoch())
+		}
+		if !electra.IsValidSwitchToCompoundingRequest(st, req) {
+			return nil, errors.New("invalid switch to compounding request")
+		}
+		if err := electra.ProcessConsolidationRequests(ctx, st, []*enginev1.ConsolidationRequest{req}); err != nil {
+			return nil, errors.Wrap(err, "could not process consolidation request")
+		}
+		outcome := &ConsolidationOutcome{SourceIndex: srcIdx, TargetIndex: srcIdx, SwitchToCompounding: true}
+		if bal > cfg.MinActivationBalance {
+			outcome.QueuedBalance = bal - cfg.MinActivationBalance
+		}
+		return outcome, nil
+	}
+
+	n, err := st.NumPendingConsolidations()
+	if err != nil {
+		return nil, err
+	}
+	if n >= cfg.PendingConsolidationsLimit {
+		return nil, fmt.Errorf("pending consolidation queue is full (%d)", n)
+	}
+	activeBal, err := helpers.TotalActiveBalance(st)
+	if err != nil {
+		return nil, err
+	}
+	if churn := helpers.ConsolidationChurnLimit(primitives.Gwei(activeBal)); churn <= primitives.Gwei(cfg.MinActivationBalance) {
+		return nil, fmt.Errorf("consolidation churn limit of %d Gwei is too low for consolidations", churn)
+	}
+	tgtIdx, ok := st.ValidatorIndexByPubkey(bytesutil.ToBytes48(req.TargetPubkey))
+	if !ok {
+		return nil, fmt.Errorf("target validator %#x not found", req.TargetPubkey)
+	}
+	tgt, err := st.ValidatorAtIndexReadOnly(tgtIdx)
+	if err != nil {
+		return nil, err
+	}
+	if !helpers.HasCompoundingWithdrawalCredential(tgt) {
+		return nil, fmt.Errorf("target validator %d needs compounding (%#x) withdrawal credentials, "+
+			"switch it first with a request having it as both source and target", tgtIdx, cfg.CompoundingWithdrawalPrefixByte)
+	}
+	if err := checkCanExit(srcIdx, src.ActivationEpoch(), src.ExitEpoch(), currentEpoch); err != nil {
+		return nil, err
+	}
+	if !helpers.IsActiveValidatorUsingTrie(tgt, currentEpoch) {
+		return nil, fmt.Errorf("target validator %d is not active", tgtIdx)
+	}
+	if tgt.ExitEpoch() != cfg.FarFutureEpoch {
+		return nil, fmt.Errorf("target validator %d is already exiting at epoch %d", tgtIdx, tgt.ExitEpoch())
+	}
+	pending, err := st.PendingBalanceToWithdraw(srcIdx)
+	if err != nil {
+		return nil, err
+	}
+	if pending > 0 {
+		return nil, fmt.Errorf("source validator %d has %d Gwei of pending partial withdrawals, it can only be consolidated once they are processed", srcIdx, pending)
+	}
+
+	// Processing the request tells where it lands in the consolidation churn.
+	moved := min(bal, src.EffectiveBalance())
+	if err := electra.ProcessConsolidationRequests(ctx, st, []*enginev1.ConsolidationRequest{req}); err != nil {
+		return nil, errors.Wrap(err, "could not process consolidation request")
+	}
+	pv, err := st.ValidatorAtIndexReadOnly(srcIdx)
+	if err != nil {
+		return nil, err
+	}
+	if pv.ExitEpoch() == cfg.FarFutureEpoch {
+		return nil, errors.New("consolidation request was not queued")
+	}
+	return &ConsolidationOutcome{
+		SourceIndex:       srcIdx,
+		TargetIndex:       tgtIdx,
+		ExitEpoch:         pv.ExitEpoch(),
+		WithdrawableEpoch: pv.WithdrawableEpoch(),
+		MovedBalance:      moved,
+		WithdrawnBalance:  bal - moved,
+	}, nil
+}
+
+// WithdrawalAddress returns the withdrawal address of the validator with the given public key.
+func WithdrawalAddress(st state.ReadOnlyBeaconState, pubkey []byte) (common.Address, error) {
+	idx, ok := st.ValidatorIndexByPubkey(bytesutil.ToBytes48(pubkey))
+	if !ok {
+		return common.Address{}, fmt.Errorf("validator %#x not found", pubkey)
+	}
+	v, err := st.ValidatorAtIndexReadOnly(idx)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return common.BytesToAddress(v.GetWithdrawalCredentials()[12:]), nil
+}
+
+// checkSource verifies that the validator has execution withdrawal credentials and that the
+// request is sent from its withdrawal address.
+func checkSource(idx primitives.ValidatorIndex, v interfaces.WithWithdrawalCredentials, source []byte) error {
+	if !helpers.HasExecutionWithdrawalCredentials(v) {
+		return fmt.Errorf("validator %d has BLS withdrawal credentials, a withdrawal address must be set first", idx)
+	}
+	if address := common.BytesToAddress(v.GetWithdrawalCredentials()[12:]); address != common.BytesToAddress(source) {
+		return fmt.Errorf("request must be sent from the withdrawal address %s of validator %d, not from %s",
+			address, idx, common.BytesToAddress(source))
+	}
+	return nil
+}
+
+// checkCanExit verifies that the validator is active, not exiting and has been active long enough
+// to exit.
+func checkCanExit(idx primitives.ValidatorIndex, activationEpoch, exitEpoch, currentEpoch primitives.Epoch) error {
+	if activationEpoch > currentEpoch || currentEpoch >= exitEpoch {
+		return fmt.Errorf("validator %d is not active", idx)
+	}
+	if exitEpoch != params.BeaconConfig().FarFutureEpoch {
+		return fmt.Errorf("validator %d is already exiting at epoch %d", idx, exitEpoch)
+	}
+	if currentEpoch < activationEpoch+params.BeaconConfig().ShardCommitteePeriod {
+		return fmt.Errorf("validator %d has not been active long enough, it can exit from epoch %d",
+			idx, activationEpoch+params.BeaconConfig().ShardCommitteePeriod)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package requests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	enginev1 "github.com/prysmaticlabs/prysm/v5/proto/engine/v1"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+// testState returns an Electra state whose validators have been active long enough to exit, with
+// execution withdrawal credentials of the given prefix pointing to the given address.
+func testState(t *testing.T, numValidators uint64, prefix byte, address common.Address) state.BeaconState {
+	st, _ := util.DeterministicGenesisStateElectra(t, numValidators)
+	cfg := params.BeaconConfig()
+	require.NoError(t, st.SetSlot(primitives.Slot(uint64(cfg.SlotsPerEpoch)*uint64(cfg.ShardCommitteePeriod)+1)))
+	vals := st.Validators()
+	for _, v := range vals {
+		v.WithdrawalCredentials = append([]byte{prefix}, append(make([]byte, 11), address.Bytes()...)...)
+	}
+	require.NoError(t, st.SetValidators(vals))
+	return st
+}
+
+// withConsolidationChurn raises the total active balance of the state so that the consolidation
+// churn is above the minimum activation balance, which consolidations require.
+func withConsolidationChurn(t *testing.T, st state.BeaconState, idx primitives.ValidatorIndex) {
+	helpers.ClearCache()
+	v, err := st.ValidatorAtIndex(idx)
+	require.NoError(t, err)
+	v.EffectiveBalance = 20_000_000 * 1_000_000_000
+	require.NoError(t, st.UpdateValidatorAtIndex(idx, v))
+}
+
+func TestCheckWithdrawal(t *testing.T) {
+	ctx := context.Background()
+	cfg := params.BeaconConfig()
+	address := common.HexToAddress("0xb20a608c624Ca5003905aA834De7156C68b2E1d0")
+
+	t.Run("BLS credentials", func(t *testing.T) {
+		st, _ := util.DeterministicGenesisStateElectra(t, 1)
+		pk := st.PubkeyAtIndex(0)
+		req := &enginev1.WithdrawalRequest{SourceAddress: address.Bytes(), ValidatorPubkey: pk[:], Amount: 1}
+		_, err := CheckWithdrawal(ctx, st, req)
+		require.ErrorContains(t, "has BLS withdrawal credentials", err)
+	})
+	t.Run("wrong source address", func(t *testing.T) {
+		st := testState(t, 1, cfg.CompoundingWithdrawalPrefixByte, address)
+		pk := st.PubkeyAtIndex(0)
+		req := &enginev1.WithdrawalRequest{SourceAddress: common.HexToAddress("0x01").Bytes(), ValidatorPubkey: pk[:], Amount: 1}
+		_, err := CheckWithdrawal(ctx, st, req)
+		require.ErrorContains(t, "must be sent from the withdrawal address", err)
+	})
+	t.Run("partial withdrawal without compounding credentials", func(t *testing.T) {
+		st := testState(t, 1, cfg.ETH1AddressWithdrawalPrefixByte, address)
+		pk := st.PubkeyAtIndex(0)
+		req := &enginev1.WithdrawalRequest{SourceAddress: address.Bytes(), ValidatorPubkey: pk[:], Amount: 1}
+		_, err := CheckWithdrawal(ctx, st, req)
+		require.ErrorContains(t, "needs compounding", err)
+	})
+	t.Run("no excess balance", func(t *testing.T) {
+		st := testState(t, 1, cfg.CompoundingWithdrawalPrefixByte, address)
+		pk := st.PubkeyAtIndex(0)
+		req := &enginev1.WithdrawalRequest{SourceAddress: address.Bytes(), ValidatorPubkey: pk[:], Amount: 1}
+		_, err := CheckWithdrawal(ctx, st, req)
+		require.ErrorContains(t, "no balance above the minimum activation balance", err)
+	})
+	t.Run("partial withdrawal capped to excess balance", func(t *testing.T) {
+		st := testState(t, 1, cfg.CompoundingWithdrawalPrefixByte, address)
+		require.NoError(t, st.UpdateBalancesAtIndex(0, cfg.MinActivationBalance+1_000_000_000))
+		pk := st.PubkeyAtIndex(0)
+		req := &enginev1.WithdrawalRequest{SourceAddress: address.Bytes(), ValidatorPubkey: pk[:], Amount: 2_000_000_000}
+		outcome, err := CheckWithdrawal(ctx, st, req)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1_000_000_000), outcome.Amount)
+		assert.NotEqual(t, cfg.FarFutureEpoch, outcome.WithdrawableEpoch)
+		// The state of the beacon node is left untouched.
+		n, err := st.NumPendingPartialWithdrawals()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), n)
+	})
+	t.Run("full exit", func(t *testing.T) {
+		st := testState(t, 1, cfg.ETH1AddressWithdrawalPrefixByte, address)
+		pk := st.PubkeyAtIndex(0)
+		req := &enginev1.WithdrawalRequest{SourceAddress: address.Bytes(), ValidatorPubkey: pk[:], Amount: cfg.FullExitRequestAmount}
+		outcome, err := CheckWithdrawal(ctx, st, req)
+		require.NoError(t, err)
+		assert.NotEqual(t, cfg.FarFutureEpoch, outcome.ExitEpoch)
+		assert.Equal(t, outcome.ExitEpoch+cfg.MinValidatorWithdrawabilityDelay, outcome.WithdrawableEpoch)
+	})
+	t.Run("full exit with pending withdrawals", func(t *testing.T) {
+		st := testState(t, 1, cfg.CompoundingWithdrawalPrefixByte, address)
+		require.NoError(t, st.AppendPendingPartialWithdrawal(&ethpb.PendingPartialWithdrawal{Index: 0, Amount: 1}))
+		pk := st.PubkeyAtIndex(0)
+		req := &enginev1.WithdrawalRequest{SourceAddress: address.Bytes(), ValidatorPubkey: pk[:], Amount: cfg.FullExitRequestAmount}
+		_, err := CheckWithdrawal(ctx, st, req)
+		require.ErrorContains(t, "pending partial withdrawals", err)
+	})
+}
+
+func TestCheckConsolidation(t *testing.T) {
+	ctx := context.Background()
+	cfg := params.BeaconConfig()
+	address := common.HexToAddress("0xb20a608c624Ca5003905aA834De7156C68b2E1d0")
+	// request consolidates validator 0 into the target validator.
+	request := func(st state.BeaconState, target primitives.ValidatorIndex) *enginev1.ConsolidationRequest {
+		src, tgt := st.PubkeyAtIndex(0), st.PubkeyAtIndex(target)
+		return &enginev1.ConsolidationRequest{SourceAddress: address.Bytes(), SourcePubkey: src[:], TargetPubkey: tgt[:]}
+	}
+
+	t.Run("switch to compounding", func(t *testing.T) {
+		st := testState(t, 3, cfg.ETH1AddressWithdrawalPrefixByte, address)
+		require.NoError(t, st.UpdateBalancesAtIndex(0, cfg.MinActivationBalance+5))
+		outcome, err := CheckConsolidation(ctx, st, request(st, 0))
+		require.NoError(t, err)
+		assert.Equal(t, true, outcome.SwitchToCompounding)
+		assert.Equal(t, uint64(5), outcome.QueuedBalance)
+	})
+	t.Run("already compounding", func(t *testing.T) {
+		st := testState(t, 3, cfg.CompoundingWithdrawalPrefixByte, address)
+		_, err := CheckConsolidation(ctx, st, request(st, 0))
+		require.ErrorContains(t, "already has compounding withdrawal credentials", err)
+	})
+	t.Run("churn too low", func(t *testing.T) {
+		st := testState(t, 3, cfg.CompoundingWithdrawalPrefixByte, address)
+		_, err := CheckConsolidation(ctx, st, request(st, 1))
+		require.ErrorContains(t, "consolidation churn limit", err)
+	})
+	t.Run("target without compounding credentials", func(t *testing.T) {
+		st := testState(t, 3, cfg.ETH1AddressWithdrawalPrefixByte, address)
+		withConsolidationChurn(t, st, 2)
+		_, err := CheckConsolidation(ctx, st, request(st, 1))
+		require.ErrorContains(t, "target validator 1 needs compounding", err)
+	})
+	t.Run("wrong source address", func(t *testing.T) {
+		st := testState(t, 3, cfg.CompoundingWithdrawalPrefixByte, address)
+		withConsolidationChurn(t, st, 2)
+		req := request(st, 1)
+		req.SourceAddress = common.HexToAddress("0x01").Bytes()
+		_, err := CheckConsolidation(ctx, st, req)
+		require.ErrorContains(t, "must be sent from the withdrawal address", err)
+	})
+	t.Run("consolidation", func(t *testing.T) {
+		st := testState(t, 3, cfg.CompoundingWithdrawalPrefixByte, address)
+		withConsolidationChurn(t, st, 2)
+		outcome, err := CheckConsolidation(ctx, st, request(st, 1))
+		require.NoError(t, err)
+		assert.Equal(t, false, outcome.SwitchToCompounding)
+		assert.NotEqual(t, cfg.FarFutureEpoch, outcome.ExitEpoch)
+		assert.Equal(t, outcome.ExitEpoch+cfg.MinValidatorWithdrawabilityDelay, outcome.WithdrawableEpoch)
+		assert.Equal(t, cfg.MinActivationBalance, outcome.MovedBalance)
+		// The state of the beacon node is left untouched.
+		v, err := st.ValidatorAtIndexReadOnly(0)
+		require.NoError(t, err)
+		assert.Equal(t, cfg.FarFutureEpoch, v.ExitEpoch())
+	})
+}
@@ -0,0 +1,158 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api/client"
+	"github.com/prysmaticlabs/prysm/v5/api/client/beacon"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/eth/shared"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/encoding/ssz/detect"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
+	"github.com/prysmaticlabs/prysm/v5/runtime/version"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+	"github.com/prysmaticlabs/prysm/v5/validator/requests"
+)
+
+// PlanConsolidation predicts the outcome of consolidating a set of source validators into the
+// validator of the path: when each source exits and becomes withdrawable given the pending
+// consolidations and the consolidation churn, and the resulting balance of the target. The plan
+// is computed from the head state of the beacon node, which is only downloaded again once the
+// head state changed.
+func (s *Server) PlanConsolidation(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "validator.keymanagerAPI.PlanConsolidation")
+	defer span.End()
+
+	rawPubkey, pubkey, ok := shared.HexFromRoute(w, r, "pubkey", fieldparams.BLSPubkeyLength)
+	if !ok {
+		return
+	}
+	var req ConsolidationPlanRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	switch {
+	case errors.Is(err, io.EOF):
+		httputil.HandleError(w, "No data submitted", http.StatusBadRequest)
+		return
+	case err != nil:
+		httputil.HandleError(w, "Could not decode request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(req.SourcePubkeys) == 0 {
+		httputil.HandleError(w, "No source public keys submitted", http.StatusBadRequest)
+		return
+	}
+	sources := make([][]byte, len(req.SourcePubkeys))
+	for i, raw := range req.SourcePubkeys {
+		source, err := hexutil.Decode(raw)
+		if err != nil || len(source) != fieldparams.BLSPubkeyLength {
+			httputil.HandleError(w, fmt.Sprintf("Invalid source public key %s", raw), http.StatusBadRequest)
+			return
+		}
+		sources[i] = source
+	}
+
+	st, err := s.beaconHeadState(ctx)
+	if err != nil {
+		httputil.HandleError(w, errors.Wrap(err, "Could not get head state from beacon node").Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if st.Version() < version.Electra {
+		httputil.HandleError(w, "Consolidations are only processed from Electra, beacon node is on "+version.String(st.Version()), http.StatusBadRequest)
+		return
+	}
+	plan, err := requests.PlanConsolidations(ctx, st, pubkey, sources)
+	if err != nil {
+		httputil.HandleError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := &ConsolidationPlan{
+		Pubkey:                   rawPubkey,
+		ValidatorIndex:           strconv.FormatUint(uint64(plan.TargetIndex), 10),
+		CurrentEpoch:             strconv.FormatUint(uint64(slots.ToEpoch(st.Slot())), 10),
+		Consolidations:           make([]*PlannedConsolidation, len(plan.Consolidations)),
+		ExpectedBalance:          strconv.FormatUint(plan.TargetBalance, 10),
+		ExpectedEffectiveBalance: strconv.FormatUint(plan.TargetEffectiveBalance, 10),
+		CompletionEpoch:          strconv.FormatUint(uint64(plan.CompletionEpoch), 10),
+	}
+	if plan.SwitchToCompounding != nil {
+		resp.SwitchToCompounding = &PlannedSwitchToCompounding{
+			QueuedBalance: strconv.FormatUint(plan.SwitchToCompounding.Outcome.QueuedBalance, 10),
+		}
+	}
+	for i, c := range plan.Consolidations {
+		planned := &PlannedConsolidation{SourcePubkey: hexutil.Encode(c.SourcePubkey)}
+		if c.Err != nil {
+			planned.Error = c.Err.Error()
+		} else {
+			planned.SourceIndex = strconv.FormatUint(uint64(c.Outcome.SourceIndex), 10)
+			planned.ExitEpoch = strconv.FormatUint(uint64(c.Outcome.ExitEpoch), 10)
+			planned.WithdrawableEpoch = strconv.FormatUint(uint64(c.Outcome.WithdrawableEpoch), 10)
+			planned.MovedBalance = strconv.FormatUint(c.Outcome.MovedBalance, 10)
+			planned.WithdrawnBalance = strconv.FormatUint(c.Outcome.WithdrawnBalance, 10)
+		}
+		resp.Consolidations[i] = planned
+	}
+	httputil.WriteJson(w, &ConsolidationPlanResponse{Data: resp})
+}
+
+// headStateCache holds the last head state downloaded from the beacon node.
+type headStateCache struct {
+	sync.Mutex
+	root  [32]byte
+	state state.BeaconState
+}
+
+// beaconHeadState returns the head state of the beacon node. The state is only downloaded from the
+// beacon node REST API when its root changed since the last call. The plan is computed with the
+// beacon config of the validator client, so states of another network are rejected.
+func (s *Server) beaconHeadState(ctx context.Context) (state.BeaconState, error) {
+	c, err := beacon.NewClient(s.beaconApiEndpoint, client.WithTimeout(s.beaconApiTimeout), client.WithMaxBodySize(client.MaxBodySizeState))
+	if err != nil {
+		return nil, err
+	}
+	root, err := c.GetStateRoot(ctx, beacon.IdHead)
+	if err != nil {
+		return nil, err
+	}
+	cache := &s.consolidationHeadState
+	cache.Lock()
+	if cache.state != nil && cache.root == root {
+		st := cache.state
+		cache.Unlock()
+		return st, nil
+	}
+	cache.Unlock()
+
+	b, err := c.GetState(ctx, beacon.IdHead)
+	if err != nil {
+		return nil, err
+	}
+	vu, err := detect.FromState(b)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not detect version of head state")
+	}
+	if name := params.BeaconConfig().ConfigName; vu.Config.ConfigName != name {
+		return nil, fmt.Errorf("beacon node is on network %s, validator client is on network %s", vu.Config.ConfigName, name)
+	}
+	st, err := vu.UnmarshalBeaconState(b)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal head state")
+	}
+	// If the head moved on while the state was downloaded, the state is downloaded again on the next call.
+	cache.Lock()
+	cache.root, cache.state = root, st
+	cache.Unlock()
+	return st, nil
+}
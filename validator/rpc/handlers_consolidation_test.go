@@ -0,0 +1,113 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+func TestServer_PlanConsolidation(t *testing.T) {
+	cfg := params.BeaconConfig()
+	st, _ := util.DeterministicGenesisStateElectra(t, 4)
+	require.NoError(t, st.SetSlot(primitives.Slot(uint64(cfg.SlotsPerEpoch)*uint64(cfg.ShardCommitteePeriod)+1)))
+	vals := st.Validators()
+	for _, v := range vals {
+		v.WithdrawalCredentials = append([]byte{cfg.CompoundingWithdrawalPrefixByte}, make([]byte, 31)...)
+	}
+	// Consolidations need a consolidation churn above the minimum activation balance.
+	vals[3].EffectiveBalance = 20_000_000 * 1_000_000_000
+	require.NoError(t, st.SetValidators(vals))
+	require.NoError(t, st.SetFork(&ethpb.Fork{
+		PreviousVersion: cfg.DenebForkVersion,
+		CurrentVersion:  cfg.ElectraForkVersion,
+		Epoch:           cfg.ElectraForkEpoch,
+	}))
+	helpers.ClearCache()
+	enc, err := st.MarshalSSZ()
+	require.NoError(t, err)
+
+	stateRoot := "0x" + strings.Repeat("01", 32)
+	downloads := 0
+	beaconNode := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/eth/v1/beacon/states/head/root":
+			_, err := w.Write([]byte(`{"data":{"root":"` + stateRoot + `"}}`))
+			require.NoError(t, err)
+		case "/eth/v2/debug/beacon/states/head":
+			downloads++
+			_, err := w.Write(enc)
+			require.NoError(t, err)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer beaconNode.Close()
+	s := &Server{beaconApiEndpoint: beaconNode.URL}
+
+	target, source := st.PubkeyAtIndex(0), st.PubkeyAtIndex(1)
+	plan := func(pubkey string, body interface{}) *httptest.ResponseRecorder {
+		b, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/eth/v1/validator/{pubkey}/consolidation_plan", bytes.NewReader(b))
+		req.SetPathValue("pubkey", pubkey)
+		w := httptest.NewRecorder()
+		w.Body = &bytes.Buffer{}
+		s.PlanConsolidation(w, req)
+		return w
+	}
+
+	w := plan(hexutil.Encode(target[:]), &ConsolidationPlanRequest{SourcePubkeys: []string{hexutil.Encode(source[:]), hexutil.Encode(target[:])}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	resp := &ConsolidationPlanResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), resp))
+	assert.Equal(t, "0", resp.Data.ValidatorIndex)
+	assert.Equal(t, (*PlannedSwitchToCompounding)(nil), resp.Data.SwitchToCompounding)
+	require.Equal(t, 2, len(resp.Data.Consolidations))
+	assert.Equal(t, "1", resp.Data.Consolidations[0].SourceIndex)
+	assert.Equal(t, "", resp.Data.Consolidations[0].Error)
+	assert.Equal(t, resp.Data.Consolidations[0].WithdrawableEpoch, resp.Data.CompletionEpoch)
+	assert.StringContains(t, "already has compounding withdrawal credentials", resp.Data.Consolidations[1].Error)
+	assert.Equal(t, "64000000000", resp.Data.ExpectedBalance)
+	assert.Equal(t, "64000000000", resp.Data.ExpectedEffectiveBalance)
+
+	// The head state is only downloaded again once it changed.
+	assert.Equal(t, 1, downloads)
+	w = plan(hexutil.Encode(target[:]), &ConsolidationPlanRequest{SourcePubkeys: []string{hexutil.Encode(source[:])}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 1, downloads)
+	stateRoot = "0x" + strings.Repeat("02", 32)
+	w = plan(hexutil.Encode(target[:]), &ConsolidationPlanRequest{SourcePubkeys: []string{hexutil.Encode(source[:])}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 2, downloads)
+
+	// States of another network than the one of the validator client are rejected.
+	params.SetupTestConfigCleanup(t)
+	otherCfg := params.BeaconConfig().Copy()
+	otherCfg.ConfigName = "other"
+	params.OverrideBeaconConfig(otherCfg)
+	stateRoot = "0x" + strings.Repeat("03", 32)
+	w = plan(hexutil.Encode(target[:]), &ConsolidationPlanRequest{SourcePubkeys: []string{hexutil.Encode(source[:])}})
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.StringContains(t, "validator client is on network other", w.Body.String())
+	params.OverrideBeaconConfig(cfg)
+
+	w = plan(hexutil.Encode(target[:]), &ConsolidationPlanRequest{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = plan(hexutil.Encode(target[:]), &ConsolidationPlanRequest{SourcePubkeys: []string{"0x01"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = plan(hexutil.Encode(make([]byte, 48)), &ConsolidationPlanRequest{SourcePubkeys: []string{hexutil.Encode(source[:])}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	require.StringContains(t, "target validator", w.Body.String())
+}
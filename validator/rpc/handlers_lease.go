@@ -0,0 +1,156 @@
+package rpc
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
+	"github.com/prysmaticlabs/prysm/v5/validator/keymanager/lease"
+)
+
+// ListKeyLeases returns every key lease in the lease store served by this validator client.
+func (s *Server) ListKeyLeases(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "validator.web.ListKeyLeases")
+	defer span.End()
+
+	store, ok := s.leaseStore(w)
+	if !ok {
+		return
+	}
+	leases, err := store.Leases(ctx)
+	if err != nil {
+		httputil.HandleError(w, errors.Wrap(err, "could not list key leases").Error(), http.StatusInternalServerError)
+		return
+	}
+	httputil.WriteJson(w, &lease.LeasesResponse{Data: leases})
+}
+
+// AcquireKeyLease grants or renews the lease of a key for the requesting owner.
+func (s *Server) AcquireKeyLease(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "validator.web.AcquireKeyLease")
+	defer span.End()
+
+	store, ok := s.leaseStore(w)
+	if !ok {
+		return
+	}
+	var req lease.AcquireRequest
+	if !decodeLeaseRequest(w, r, &req) {
+		return
+	}
+	pubkey, ok := decodeLeasePubkey(w, req.Pubkey)
+	if !ok {
+		return
+	}
+	l, err := store.Acquire(ctx, pubkey, req.Owner, time.Duration(req.TTLSeconds)*time.Second)
+	if err != nil {
+		handleLeaseError(w, err)
+		return
+	}
+	httputil.WriteJson(w, &lease.LeaseResponse{Data: l})
+}
+
+// ReleaseKeyLease gives up the lease of a key held by the requesting owner.
+func (s *Server) ReleaseKeyLease(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "validator.web.ReleaseKeyLease")
+	defer span.End()
+
+	store, ok := s.leaseStore(w)
+	if !ok {
+		return
+	}
+	var req lease.ReleaseRequest
+	if !decodeLeaseRequest(w, r, &req) {
+		return
+	}
+	pubkey, ok := decodeLeasePubkey(w, req.Pubkey)
+	if !ok {
+		return
+	}
+	if err := store.Release(ctx, pubkey, req.Owner); err != nil {
+		handleLeaseError(w, err)
+		return
+	}
+}
+
+// TransferKeyLeases hands the leases of a set of keys over from one owner to another.
+func (s *Server) TransferKeyLeases(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "validator.web.TransferKeyLeases")
+	defer span.End()
+
+	store, ok := s.leaseStore(w)
+	if !ok {
+		return
+	}
+	var req lease.TransferRequest
+	if !decodeLeaseRequest(w, r, &req) {
+		return
+	}
+	pubkeys := make([][fieldparams.BLSPubkeyLength]byte, len(req.Pubkeys))
+	for i, pk := range req.Pubkeys {
+		if pubkeys[i], ok = decodeLeasePubkey(w, pk); !ok {
+			return
+		}
+	}
+	leases, err := store.Transfer(ctx, pubkeys, req.From, req.To, time.Duration(req.TTLSeconds)*time.Second)
+	if err != nil {
+		handleLeaseError(w, err)
+		return
+	}
+	log.WithField("from", req.From).WithField("to", req.To).Infof("Handed over %d key leases", len(leases))
+	httputil.WriteJson(w, &lease.LeasesResponse{Data: leases})
+}
+
+func (s *Server) leaseStore(w http.ResponseWriter) (lease.Store, bool) {
+	if s.validatorService == nil {
+		httputil.HandleError(w, "Validator service not ready.", http.StatusServiceUnavailable)
+		return nil, false
+	}
+	store := s.validatorService.LeaseStore()
+	if store == nil {
+		httputil.HandleError(w, "Key leases are not enabled on this validator client.", http.StatusNotFound)
+		return nil, false
+	}
+	return store, true
+}
+
+func decodeLeaseRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(req)
+	switch {
+	case errors.Is(err, io.EOF):
+		httputil.HandleError(w, "No data submitted", http.StatusBadRequest)
+		return false
+	case err != nil:
+		httputil.HandleError(w, "Could not decode request body: "+err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func decodeLeasePubkey(w http.ResponseWriter, s string) ([fieldparams.BLSPubkeyLength]byte, bool) {
+	pubkey, err := hexutil.Decode(s)
+	if err != nil || len(pubkey) != fieldparams.BLSPubkeyLength {
+		httputil.HandleError(w, "Invalid public key: "+s, http.StatusBadRequest)
+		return [fieldparams.BLSPubkeyLength]byte{}, false
+	}
+	return [fieldparams.BLSPubkeyLength]byte(pubkey), true
+}
+
+func handleLeaseError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, lease.ErrLeaseHeld):
+		httputil.HandleError(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, lease.ErrNotLeaseHolder):
+		httputil.HandleError(w, err.Error(), http.StatusPreconditionFailed)
+	case errors.Is(err, lease.ErrEmptyOwner):
+		httputil.HandleError(w, err.Error(), http.StatusBadRequest)
+	default:
+		httputil.HandleError(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,76 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	mock "github.com/prysmaticlabs/prysm/v5/validator/accounts/testing"
+	"github.com/prysmaticlabs/prysm/v5/validator/client"
+	"github.com/prysmaticlabs/prysm/v5/validator/keymanager/lease"
+)
+
+func TestServer_KeyLeases(t *testing.T) {
+	ctx := context.Background()
+	var pk [fieldparams.BLSPubkeyLength]byte
+	pk[0] = 1
+
+	t.Run("leases not enabled", func(t *testing.T) {
+		vs, err := client.NewValidatorService(ctx, &client.Config{Validator: &mock.Validator{}})
+		require.NoError(t, err)
+		s := &Server{validatorService: vs}
+		w := httptest.NewRecorder()
+		s.ListKeyLeases(w, httptest.NewRequest(http.MethodGet, "/v2/validator/leases", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	store, err := lease.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	vs, err := client.NewValidatorService(ctx, &client.Config{Validator: &mock.Validator{}, LeaseStore: store})
+	require.NoError(t, err)
+	s := &Server{validatorService: vs}
+
+	post := func(handler http.HandlerFunc, body interface{}) *httptest.ResponseRecorder {
+		enc, err := json.Marshal(body)
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodPost, "/v2/validator/leases", bytes.NewReader(enc)))
+		return w
+	}
+
+	w := post(s.AcquireKeyLease, &lease.AcquireRequest{Pubkey: hexutil.Encode(pk[:]), Owner: "alice", TTLSeconds: 60})
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := &lease.LeaseResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), resp))
+	assert.Equal(t, "alice", resp.Data.Owner)
+
+	w = post(s.AcquireKeyLease, &lease.AcquireRequest{Pubkey: hexutil.Encode(pk[:]), Owner: "bob", TTLSeconds: 60})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = post(s.AcquireKeyLease, &lease.AcquireRequest{Pubkey: "0x01", Owner: "bob", TTLSeconds: 60})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = post(s.ReleaseKeyLease, &lease.ReleaseRequest{Pubkey: hexutil.Encode(pk[:]), Owner: "bob"})
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	w = post(s.TransferKeyLeases, &lease.TransferRequest{Pubkeys: []string{hexutil.Encode(pk[:])}, From: "alice", To: "bob", TTLSeconds: 60})
+	require.Equal(t, http.StatusOK, w.Code)
+	leasesResp := &lease.LeasesResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), leasesResp))
+	require.Equal(t, 1, len(leasesResp.Data))
+	assert.Equal(t, "bob", leasesResp.Data[0].Owner)
+	assert.Equal(t, true, leasesResp.Data[0].NotBefore.After(time.Now()))
+
+	w = httptest.NewRecorder()
+	s.ListKeyLeases(w, httptest.NewRequest(http.MethodGet, "/v2/validator/leases", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), leasesResp))
+	require.Equal(t, 1, len(leasesResp.Data))
+	assert.Equal(t, "bob", leasesResp.Data[0].Owner)
+}
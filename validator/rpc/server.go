@@ -77,6 +77,7 @@ type Server struct {
 	logStreamer               logs.Streamer
 	logStreamerBufferSize     int
 	startFailure              error
+	consolidationHeadState    headStateCache
 }
 
 // NewServer instantiates a new HTTP server.
@@ -191,6 +192,7 @@ func (s *Server) InitializeRoutes() error {
 	s.router.HandleFunc("GET /eth/v1/validator/{pubkey}/graffiti", s.GetGraffiti)
 	s.router.HandleFunc("POST /eth/v1/validator/{pubkey}/graffiti", s.SetGraffiti)
 	s.router.HandleFunc("DELETE /eth/v1/validator/{pubkey}/graffiti", s.DeleteGraffiti)
+	s.router.HandleFunc("POST /eth/v1/validator/{pubkey}/consolidation_plan", s.PlanConsolidation)
 
 	// auth endpoint
 	s.router.HandleFunc("GET "+api.WebUrlPrefix+"initialize", s.Initialize)
@@ -216,6 +218,11 @@ func (s *Server) InitializeRoutes() error {
 	// slashing protection endpoints
 	s.router.HandleFunc("GET "+api.WebUrlPrefix+"slashing-protection/export", s.ExportSlashingProtection)
 	s.router.HandleFunc("POST "+api.WebUrlPrefix+"slashing-protection/import", s.ImportSlashingProtection)
+	// key lease endpoints
+	s.router.HandleFunc("GET "+api.WebUrlPrefix+"leases", s.ListKeyLeases)
+	s.router.HandleFunc("POST "+api.WebUrlPrefix+"leases/acquire", s.AcquireKeyLease)
+	s.router.HandleFunc("POST "+api.WebUrlPrefix+"leases/release", s.ReleaseKeyLease)
+	s.router.HandleFunc("POST "+api.WebUrlPrefix+"leases/transfer", s.TransferKeyLeases)
 
 	log.Info("Initialized REST API routes")
 	return nil
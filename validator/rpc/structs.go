@@ -62,6 +62,40 @@ type SetGasLimitRequest struct {
 	GasLimit string `json:"gas_limit"`
 }
 
+// consolidation plan keymanager api
+type ConsolidationPlanRequest struct {
+	SourcePubkeys []string `json:"source_pubkeys"`
+}
+
+type ConsolidationPlanResponse struct {
+	Data *ConsolidationPlan `json:"data"`
+}
+
+type ConsolidationPlan struct {
+	Pubkey                   string                      `json:"pubkey"`
+	ValidatorIndex           string                      `json:"validator_index"`
+	CurrentEpoch             string                      `json:"current_epoch"`
+	SwitchToCompounding      *PlannedSwitchToCompounding `json:"switch_to_compounding,omitempty"`
+	Consolidations           []*PlannedConsolidation     `json:"consolidations"`
+	ExpectedBalance          string                      `json:"expected_balance"`
+	ExpectedEffectiveBalance string                      `json:"expected_effective_balance"`
+	CompletionEpoch          string                      `json:"completion_epoch"`
+}
+
+type PlannedSwitchToCompounding struct {
+	QueuedBalance string `json:"queued_balance"`
+}
+
+type PlannedConsolidation struct {
+	SourcePubkey      string `json:"source_pubkey"`
+	SourceIndex       string `json:"source_index,omitempty"`
+	ExitEpoch         string `json:"exit_epoch,omitempty"`
+	WithdrawableEpoch string `json:"withdrawable_epoch,omitempty"`
+	MovedBalance      string `json:"moved_balance,omitempty"`
+	WithdrawnBalance  string `json:"withdrawn_balance,omitempty"`
+	Error             string `json:"error,omitempty"`
+}
+
 // remote keymanager api
 type ListRemoteKeysResponse struct {
 	Data []*RemoteKey `json:"data"`